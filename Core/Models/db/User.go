@@ -28,6 +28,9 @@ type User struct {
 	// Status represents the current state of the user's account
 	Status types.AccountStatus `json:"status"`
 
+	// Role decides what the user may do with data of other users; empty means types.RoleUser
+	Role types.Role `json:"role,omitempty"`
+
 	// CreatedAt is the timestamp when the user account was created
 	CreatedAt time.Time `json:"created_at,omitempty"`
 
@@ -71,6 +74,14 @@ func (u *User) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// EffectiveRole is the role of the user, treating rows without one as plain users
+func (u *User) EffectiveRole() types.Role {
+	if u.Role == "" {
+		return types.RoleUser
+	}
+	return u.Role
+}
+
 // UpdateAccountRequest represents the data that can be updated for a user account.
 // This struct is used as a DTO (Data Transfer Object) for account updates.
 type UpdateAccountRequest struct {
@@ -91,4 +102,7 @@ type UpdateAccountRequest struct {
 
 	// Password is the new password (will be hashed before storage, optional)
 	Password string
+
+	// CurrentPassword is the password the user has now, required to change the password or email
+	CurrentPassword string
 }
@@ -0,0 +1,44 @@
+// Package models contains the data structures used throughout the application.
+// This file defines the APIKey structure used for personal API keys.
+package db
+
+import "time"
+
+// APIKey is a personal key a user creates for scripts and integrations. Only the hash of
+// the key is stored; the key itself is shown once, when it is created.
+type APIKey struct {
+	// ID is the unique identifier for the key
+	ID int `json:"id"`
+
+	// UserID is the foreign key that references the owner of the key
+	UserID int `json:"user_id"`
+
+	// Name is the label the user gave the key, e.g. "backup script"
+	Name string `json:"name"`
+
+	// Prefix is the start of the key, so users can tell their keys apart
+	Prefix string `json:"prefix"`
+
+	// KeyHash is the SHA-256 of the key, used to look it up
+	KeyHash string `json:"key_hash"`
+
+	// Scopes are the types.APIKeyScope values the key grants
+	Scopes []string `json:"scopes"`
+
+	// CreatedAt is when the key was created
+	CreatedAt time.Time `json:"created_at"`
+
+	// ExpiresAt is when the key stops working; nil keys don't expire
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+
+	// LastUsedAt is when the key last authenticated a request
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+
+	// RevokedAt is set when the user revokes the key
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// Active reports whether the key still authenticates requests at now
+func (k *APIKey) Active(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
@@ -0,0 +1,29 @@
+// Package models contains the data structures used throughout the application.
+// This file defines the AuditEntry structure used for the security audit log.
+package db
+
+import (
+	"Financial/Core/types"
+	"time"
+)
+
+// AuditEntry records a security event, such as an account locked out after failed logins
+type AuditEntry struct {
+	// ID is the unique identifier for the entry
+	ID int `json:"id"`
+
+	// UserID is the account the event concerns; nil when it isn't a known account
+	UserID *int `json:"user_id"`
+
+	// Action is what happened
+	Action types.AuditAction `json:"action"`
+
+	// Detail describes the event for whoever reads the log
+	Detail string `json:"detail"`
+
+	// IP is the client address that caused the event
+	IP string `json:"ip"`
+
+	// CreatedAt is when the event happened
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,27 @@
+// Package models contains the data structures used throughout the application.
+// This file defines the Budget structure used to plan spending per category.
+package db
+
+import "Financial/Core/types"
+
+// Budget is the spending limit a user sets on an expense category for one month.
+// The limit covers the category and all of its subcategories.
+type Budget struct {
+	// ID is the unique identifier for the budget
+	ID int `json:"id"`
+
+	// UserID is the foreign key that references the user who owns this budget
+	UserID int `json:"user_id"`
+
+	// CategoryID references the expense category the limit applies to
+	CategoryID int `json:"category_id"`
+
+	// Period is the month covered by the budget, formatted as YYYY-MM
+	Period string `json:"period"`
+
+	// Limit is the maximum amount planned for the period
+	Limit types.Money `json:"limit_amount"`
+
+	// Currency is the ISO 4217 code of Limit; only wallets in this currency count towards it
+	Currency string `json:"currency"`
+}
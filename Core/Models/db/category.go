@@ -0,0 +1,29 @@
+// Package models contains the data structures used throughout the application.
+// This file defines the Category structure used to classify wallet transactions.
+package db
+
+import "Financial/Core/types"
+
+// Category is a user-defined label for transactions (e.g. Food > Restaurants).
+// Categories form a tree per user: a category without ParentID is a root and
+// any category may have subcategories.
+type Category struct {
+	// ID is the unique identifier for the category
+	ID int `json:"id"`
+
+	// UserID is the foreign key that references the user who owns this category
+	UserID int `json:"user_id"`
+
+	// ParentID references the parent category; nil for root categories
+	ParentID *int `json:"parent_id"`
+
+	// Name is the label shown to the user; it must be unique among its siblings
+	Name string `json:"name"`
+
+	// Type tells whether the category groups incomes or expenses
+	Type types.TransactionType `json:"type"`
+
+	// Children is the navigation property to the subcategories.
+	// It is only populated when the categories are returned as a tree.
+	Children []Category `json:"children,omitempty"`
+}
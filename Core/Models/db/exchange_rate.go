@@ -0,0 +1,28 @@
+// Package models contains the data structures used throughout the application.
+// This file defines the ExchangeRate structure used to convert between wallet currencies.
+package db
+
+import (
+	"Financial/Core/types"
+	"time"
+)
+
+// ExchangeRate is the value of one unit of BaseCurrency in QuoteCurrency from
+// EffectiveDate on. Rates are never overwritten: a new rate gets a new effective
+// date, so a conversion done for a past date always finds the same rate.
+type ExchangeRate struct {
+	// ID is the unique identifier for the rate
+	ID int `json:"id,omitempty"`
+
+	// BaseCurrency is the ISO 4217 code of the currency being converted (e.g. "USD")
+	BaseCurrency string `json:"base_currency"`
+
+	// QuoteCurrency is the ISO 4217 code of the resulting currency (e.g. "DOP")
+	QuoteCurrency string `json:"quote_currency"`
+
+	// Rate is how many units of QuoteCurrency one unit of BaseCurrency is worth
+	Rate types.Rate `json:"rate"`
+
+	// EffectiveDate is the moment from which the rate applies
+	EffectiveDate time.Time `json:"effective_date"`
+}
@@ -0,0 +1,27 @@
+// Package models contains the data structures used throughout the application.
+// This file defines the LoginAttempt structure used to throttle failed logins.
+package db
+
+import (
+	"Financial/Core/types"
+	"time"
+)
+
+// LoginAttempt counts the failed logins of an account or of a client address.
+// Keys look like "user:42", "login:someone@example.com" (unknown accounts) or "ip:203.0.113.7".
+type LoginAttempt struct {
+	// Key identifies what failed to log in
+	Key string `json:"key"`
+
+	// Failures is the number of failed logins since the count was last reset
+	Failures int `json:"failures"`
+
+	// LastFailureAt is when the last failed login happened
+	LastFailureAt time.Time `json:"last_failure_at"`
+
+	// LockedUntil is set while the key is locked out after too many failures
+	LockedUntil *time.Time `json:"locked_until,omitempty"`
+
+	// PreviousStatus is the status the account had before the lockout suspended it
+	PreviousStatus types.AccountStatus `json:"previous_status,omitempty"`
+}
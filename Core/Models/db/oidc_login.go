@@ -0,0 +1,27 @@
+// Package models contains the data structures used throughout the application.
+// This file defines the OIDCLogin structure that carries a login through the identity provider.
+package db
+
+import "time"
+
+// OIDCLogin is a login started against an OpenID Connect provider and not completed yet.
+// It keeps the PKCE verifier and nonce until the provider redirects back with the state.
+type OIDCLogin struct {
+	// ID is the unique identifier for the login
+	ID int `json:"id"`
+
+	// StateHash is the SHA-256 of the state sent to the provider
+	StateHash string `json:"state_hash"`
+
+	// CodeVerifier is the PKCE verifier whose challenge was sent to the provider
+	CodeVerifier string `json:"code_verifier"`
+
+	// Nonce is the value the ID token must carry
+	Nonce string `json:"nonce"`
+
+	// CreatedAt is when the login was started
+	CreatedAt time.Time `json:"created_at"`
+
+	// ExpiresAt is when the callback stops being accepted
+	ExpiresAt time.Time `json:"expires_at"`
+}
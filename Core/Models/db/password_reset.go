@@ -0,0 +1,27 @@
+// Package models contains the data structures used throughout the application.
+// This file defines the PasswordReset structure used to recover accounts.
+package db
+
+import "time"
+
+// PasswordReset is a one-time token emailed to a user who forgot their password.
+// Only the hash of the token is stored.
+type PasswordReset struct {
+	// ID is the unique identifier for the reset
+	ID int `json:"id"`
+
+	// UserID is the foreign key that references the account being recovered
+	UserID int `json:"user_id"`
+
+	// TokenHash is the SHA-256 of the emailed token
+	TokenHash string `json:"token_hash"`
+
+	// CreatedAt is when the reset was requested
+	CreatedAt time.Time `json:"created_at"`
+
+	// ExpiresAt is when the token stops being accepted
+	ExpiresAt time.Time `json:"expires_at"`
+
+	// UsedAt is set once the token has been used, or replaced by a successful reset
+	UsedAt *time.Time `json:"used_at,omitempty"`
+}
@@ -0,0 +1,159 @@
+// Package models contains the data structures used throughout the application.
+// This file defines the RecurringTransaction structure and how its schedule expands.
+package db
+
+import (
+	"Financial/Core/types"
+	"sort"
+	"time"
+)
+
+// maxRecurrenceScan bounds the number of periods inspected when looking for the next occurrence
+const maxRecurrenceScan = 1000
+
+// RecurringTransaction represents an income or expense that repeats on a schedule
+// (e.g. salary on the 15th and 30th, monthly rent, yearly subscriptions).
+// The schedule follows the RRULE subset FREQ, INTERVAL, BYMONTHDAY and UNTIL.
+type RecurringTransaction struct {
+	// ID is the unique identifier for the series
+	ID int `json:"id"`
+
+	// UserID is the foreign key that references the user who owns this series.
+	UserID int `json:"user_id"`
+
+	// WalletID is the wallet that receives the postings.
+	WalletID int `json:"wallet_id"`
+
+	// Type indicates whether each posting is an Income or an Expense.
+	Type types.TransactionType `json:"type"`
+
+	// Amount is the absolute value of each posting.
+	Amount types.Money `json:"amount"`
+
+	// Description is copied to every posted transaction.
+	Description string `json:"description"`
+
+	// CategoryID is the category every posted transaction is tagged with, if any.
+	CategoryID *int `json:"category_id,omitempty"`
+
+	// Frequency is the unit of the schedule (DAILY, WEEKLY, MONTHLY or YEARLY).
+	Frequency types.Frequency `json:"frequency"`
+
+	// Interval is the number of Frequency units between periods (1 = every period).
+	Interval int `json:"interval"`
+
+	// ByMonthDay lists the days of the month to post on for MONTHLY and YEARLY series.
+	// Negative values count from the end of the month (-1 = last day) and days past the
+	// end of a short month fall on its last day. Empty means the day of StartDate.
+	ByMonthDay []int `json:"by_month_day,omitempty"`
+
+	// StartDate is the first possible occurrence; it also sets the time of day of every posting.
+	StartDate time.Time `json:"start_date"`
+
+	// EndDate is the last instant an occurrence may fall on, if the series ends.
+	EndDate *time.Time `json:"end_date,omitempty"`
+
+	// Status tells whether the scheduler posts the series.
+	Status types.RecurringStatus `json:"status"`
+
+	// NextRunAt is the next occurrence still to be posted; nil once the series has ended.
+	// It is always sent so ending a series clears the column.
+	NextRunAt *time.Time `json:"next_run_at"`
+}
+
+// NextOccurrence returns the first occurrence of the schedule strictly after the given instant.
+// The second value is false when the series has no more occurrences.
+func (r RecurringTransaction) NextOccurrence(after time.Time) (time.Time, bool) {
+	interval := r.Interval
+	if interval < 1 {
+		interval = 1
+	}
+
+	// Jump close to the period that contains "after" instead of walking from the start
+	period := 0
+	if after.After(r.StartDate) {
+		switch r.Frequency {
+		case types.Daily:
+			period = int(after.Sub(r.StartDate).Hours()/24) / interval
+		case types.Weekly:
+			period = int(after.Sub(r.StartDate).Hours()/(24*7)) / interval
+		case types.Monthly:
+			period = monthsBetween(r.StartDate, after) / interval
+		case types.Yearly:
+			period = (after.Year() - r.StartDate.Year()) / interval
+		}
+		if period > 0 {
+			period--
+		}
+	}
+
+	for i := 0; i < maxRecurrenceScan; i, period = i+1, period+1 {
+		for _, candidate := range r.periodOccurrences(period * interval) {
+			if candidate.Before(r.StartDate) || !candidate.After(after) {
+				continue
+			}
+			if r.EndDate != nil && candidate.After(*r.EndDate) {
+				return time.Time{}, false
+			}
+			return candidate, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// periodOccurrences lists, in order, the occurrences of the period that is offset units after StartDate
+func (r RecurringTransaction) periodOccurrences(offset int) []time.Time {
+	start := r.StartDate
+	switch r.Frequency {
+	case types.Daily:
+		return []time.Time{start.AddDate(0, 0, offset)}
+	case types.Weekly:
+		return []time.Time{start.AddDate(0, 0, 7*offset)}
+	case types.Monthly:
+		return r.monthOccurrences(start.Year(), start.Month()+time.Month(offset))
+	case types.Yearly:
+		return r.monthOccurrences(start.Year()+offset, start.Month())
+	}
+	return nil
+}
+
+// monthOccurrences resolves ByMonthDay within a month, at the time of day of StartDate
+func (r RecurringTransaction) monthOccurrences(year int, month time.Month) []time.Time {
+	start := r.StartDate
+	// Normalizes months past December into the following years
+	first := time.Date(year, month, 1, start.Hour(), start.Minute(), start.Second(), 0, start.Location())
+	daysInMonth := first.AddDate(0, 1, -1).Day()
+
+	days := r.ByMonthDay
+	if len(days) == 0 {
+		days = []int{start.Day()}
+	}
+
+	seen := map[int]bool{}
+	resolved := []int{}
+	for _, day := range days {
+		if day < 0 {
+			day = daysInMonth + day + 1
+		}
+		if day > daysInMonth {
+			day = daysInMonth
+		}
+		if day < 1 || seen[day] {
+			continue
+		}
+		seen[day] = true
+		resolved = append(resolved, day)
+	}
+	sort.Ints(resolved)
+
+	occurrences := make([]time.Time, 0, len(resolved))
+	for _, day := range resolved {
+		occurrences = append(occurrences, first.AddDate(0, 0, day-1))
+	}
+	return occurrences
+}
+
+// monthsBetween counts the calendar months from a to b
+func monthsBetween(a time.Time, b time.Time) int {
+	return (b.Year()-a.Year())*12 + int(b.Month()) - int(a.Month())
+}
@@ -0,0 +1,49 @@
+// Package models contains the data structures used throughout the application.
+// This file defines the Session structure that backs refresh tokens and logout.
+package db
+
+import "time"
+
+// Session is a login of a user on one device. It holds the current refresh token
+// (hashed) and the ID of the current access token, so both can be rotated and revoked.
+type Session struct {
+	// ID is the unique identifier for the session
+	ID int `json:"id"`
+
+	// UserID is the foreign key that references the user who logged in
+	UserID int `json:"user_id"`
+
+	// AccessTokenID is the jti of the last access token issued for the session.
+	// Access tokens with any other jti are rejected.
+	AccessTokenID string `json:"access_token_id"`
+
+	// RefreshTokenHash is the SHA-256 of the current refresh token; the token itself is never stored
+	RefreshTokenHash string `json:"refresh_token_hash"`
+
+	// PreviousRefreshHash is the hash of the refresh token replaced by the last rotation.
+	// Presenting it again means the token was stolen, and the session is revoked.
+	PreviousRefreshHash *string `json:"previous_refresh_hash,omitempty"`
+
+	// Device is the user agent that opened or last refreshed the session
+	Device string `json:"device"`
+
+	// IP is the client address that opened or last refreshed the session
+	IP string `json:"ip"`
+
+	// CreatedAt is the timestamp of the login
+	CreatedAt time.Time `json:"created_at"`
+
+	// LastSeenAt is the last time the session was refreshed or used
+	LastSeenAt time.Time `json:"last_seen_at"`
+
+	// ExpiresAt is when the refresh token stops being accepted
+	ExpiresAt time.Time `json:"expires_at"`
+
+	// RevokedAt is set when the session is logged out; revoked sessions are kept for the session list
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// Active reports whether the session can still be used at the given instant.
+func (s Session) Active(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
@@ -0,0 +1,50 @@
+// Package models contains the data structures used throughout the application.
+// This file defines the Transaction structure that backs the wallet ledger.
+package db
+
+import (
+	"Financial/Core/types"
+	"time"
+)
+
+// Transaction represents a single movement of money in or out of a wallet.
+// Every change to a wallet balance is recorded as a transaction so the
+// balance can always be explained by its ledger.
+type Transaction struct {
+	// ID is the unique identifier for the transaction
+	ID int `json:"id"`
+
+	// WalletID is the foreign key that references the wallet this movement belongs to.
+	WalletID int `json:"wallet_id"`
+
+	// Type indicates whether the movement adds (Income) or removes (Expense) money.
+	Type types.TransactionType `json:"type"`
+
+	// Amount is the absolute value of the movement; its direction is given by Type.
+	Amount types.Money `json:"amount"`
+
+	// Description is an optional free text note about the movement.
+	Description string `json:"description"`
+
+	// CategoryID references the category the movement is tagged with, if any.
+	CategoryID *int `json:"category_id,omitempty"`
+
+	// RecurringID references the recurring series that posted this movement, if any.
+	RecurringID *int `json:"recurring_id,omitempty"`
+
+	// OccurrenceDate is the scheduled occurrence of the recurring series this movement posts.
+	// Together with RecurringID it is unique, so an occurrence is never posted twice.
+	OccurrenceDate *time.Time `json:"occurrence_date,omitempty"`
+
+	// CreatedAt is the timestamp when the movement was recorded
+	CreatedAt time.Time `json:"created_at,omitempty"`
+}
+
+// SignedAmount returns the amount with the sign it has on the wallet balance:
+// positive for income and negative for expenses.
+func (t Transaction) SignedAmount() types.Money {
+	if t.Type == types.Expense {
+		return t.Amount.Negate()
+	}
+	return t.Amount
+}
@@ -0,0 +1,28 @@
+// Package models contains the data structures used throughout the application.
+// This file defines the Transfer structure used to move money between wallets.
+package db
+
+import "Financial/Core/types"
+
+// Transfer represents money moved from one wallet to another of the same user.
+// A transfer is always stored as a pair of ledger entries: an expense on the
+// source wallet and an income on the destination wallet.
+type Transfer struct {
+	// FromWalletID is the wallet the money leaves
+	FromWalletID int `json:"from_wallet_id"`
+
+	// ToWalletID is the wallet the money arrives to
+	ToWalletID int `json:"to_wallet_id"`
+
+	// Amount is the positive amount moved between the wallets
+	Amount types.Money `json:"amount"`
+
+	// Description is an optional note copied to both ledger entries
+	Description string `json:"description"`
+
+	// Debit is the expense recorded on the source wallet
+	Debit *Transaction `json:"debit,omitempty"`
+
+	// Credit is the income recorded on the destination wallet
+	Credit *Transaction `json:"credit,omitempty"`
+}
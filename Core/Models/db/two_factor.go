@@ -0,0 +1,30 @@
+// Package models contains the data structures used throughout the application.
+// This file defines the TwoFactor structure used for TOTP two-factor authentication.
+package db
+
+import "time"
+
+// TwoFactor holds the TOTP enrollment of a user. It exists from setup on,
+// but only protects the account once ConfirmedAt is set.
+type TwoFactor struct {
+	// ID is the unique identifier for the enrollment
+	ID int `json:"id"`
+
+	// UserID is the foreign key that references the enrolled user; a user has at most one enrollment
+	UserID int `json:"user_id"`
+
+	// Secret is the base32 TOTP secret shared with the authenticator app
+	Secret string `json:"secret"`
+
+	// ConfirmedAt is set when the user proves the app works by entering a first code
+	ConfirmedAt *time.Time `json:"confirmed_at,omitempty"`
+
+	// LastUsedStep is the time step of the last accepted code; a code is never accepted twice
+	LastUsedStep int64 `json:"last_used_step"`
+
+	// RecoveryCodes are the SHA-256 hashes of the unused recovery codes
+	RecoveryCodes []string `json:"recovery_codes"`
+
+	// CreatedAt is when the setup started
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,30 @@
+// Package models contains the data structures used throughout the application.
+// This file defines the UserIdentity structure that links accounts to identity providers.
+package db
+
+import "time"
+
+// UserIdentity links an account to a user of an external OpenID Connect provider,
+// so later logins through that provider reach the same account.
+type UserIdentity struct {
+	// ID is the unique identifier for the link
+	ID int `json:"id"`
+
+	// UserID is the foreign key that references the linked account
+	UserID int `json:"user_id"`
+
+	// Issuer identifies the provider (the iss claim of its ID tokens)
+	Issuer string `json:"issuer"`
+
+	// Subject is the stable ID of the user at the provider (the sub claim)
+	Subject string `json:"subject"`
+
+	// Email is the address the provider reported when the link was made
+	Email string `json:"email"`
+
+	// CreatedAt is when the link was made
+	CreatedAt time.Time `json:"created_at"`
+
+	// LastLoginAt is when the identity was last used to log in
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+}
@@ -21,8 +21,12 @@ type Wallet struct {
 	Type types.WalletType `json:"type"`
 
 	// Balance is the current monetary amount available in the wallet.
-	// It's represented as a float64 to support decimal values.
-	Balance float64 `json:"balance"`
+	// It's represented as exact minor units to match the DECIMAL(15,2) column.
+	Balance types.Money `json:"balance"`
+
+	// Currency is the ISO 4217 code of the balance (e.g. "USD", "DOP").
+	// It is set when the wallet is created and cannot be changed afterwards.
+	Currency string `json:"currency"`
 
 	// UserID is the foreign key that references the user who owns this wallet.
 	// This field is required and must reference a valid user ID.
@@ -31,4 +35,8 @@ type Wallet struct {
 	// User is the navigation property to access the user who owns this wallet.
 	// This field should be populated manually when needed.
 	User *User `json:"user,omitempty"`
+
+	// Transactions is the navigation property to access the ledger of this wallet.
+	// This field is only populated when the query embeds the transactions relation.
+	Transactions []Transaction `json:"transactions,omitempty"`
 }
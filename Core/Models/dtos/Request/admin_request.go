@@ -0,0 +1,15 @@
+package dtos
+
+// UpdateAccountStatusRequest sets the status of an account from the admin API
+// swagger:model
+// @name UpdateAccountStatusRequest
+type UpdateAccountStatusRequest struct {
+	Status string `json:"status" binding:"required"`
+}
+
+// UpdateRoleRequest sets the role of an account from the admin API
+// swagger:model
+// @name UpdateRoleRequest
+type UpdateRoleRequest struct {
+	Role string `json:"role" binding:"required"`
+}
@@ -0,0 +1,17 @@
+package dtos
+
+import "time"
+
+// CreateAPIKeyRequest describes a new personal API key
+// swagger:model
+// @name CreateAPIKeyRequest
+type CreateAPIKeyRequest struct {
+	// Name is a label to recognise the key later
+	Name string `json:"name" binding:"required"`
+
+	// Scopes are the access the key grants: wallets:read, wallets:write, account:read, account:write
+	Scopes []string `json:"scopes" binding:"required"`
+
+	// ExpiresAt is when the key stops working; omit it for a key that doesn't expire
+	ExpiresAt *time.Time `json:"expires_at"`
+}
@@ -0,0 +1,14 @@
+package dtos
+
+import "Financial/Core/types"
+
+// CreateBudgetRequest representa la estructura de la solicitud para fijar un presupuesto mensual
+// swagger:model
+// @name CreateBudgetRequest
+type CreateBudgetRequest struct {
+	UserID     int         `json:"-"`
+	CategoryID int         `json:"category_id"`
+	Period     string      `json:"period"`
+	Limit      types.Money `json:"limit"`
+	Currency   string      `json:"currency"`
+}
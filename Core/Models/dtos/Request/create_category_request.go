@@ -0,0 +1,13 @@
+package dtos
+
+import "Financial/Core/types"
+
+// CreateCategoryRequest representa la estructura de la solicitud para crear una categoría
+// swagger:model
+// @name CreateCategoryRequest
+type CreateCategoryRequest struct {
+	UserID   int                   `json:"-"`
+	Name     string                `json:"name"`
+	Type     types.TransactionType `json:"type"`
+	ParentID *int                  `json:"parent_id"`
+}
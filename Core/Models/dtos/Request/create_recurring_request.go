@@ -0,0 +1,24 @@
+package dtos
+
+import (
+	"Financial/Core/types"
+	"time"
+)
+
+// CreateRecurringRequest representa la estructura de la solicitud para programar un movimiento recurrente.
+// Frequency, Interval, ByMonthDay and EndDate mirror the FREQ, INTERVAL, BYMONTHDAY and UNTIL parts of an RRULE.
+// swagger:model
+// @name CreateRecurringRequest
+type CreateRecurringRequest struct {
+	UserID      int                   `json:"-"`
+	WalletID    int                   `json:"wallet_id"`
+	Type        types.TransactionType `json:"type" binding:"required"`
+	Amount      types.Money           `json:"amount"`
+	Description string                `json:"description"`
+	CategoryID  *int                  `json:"category_id"`
+	Frequency   types.Frequency       `json:"frequency" binding:"required"`
+	Interval    int                   `json:"interval"`
+	ByMonthDay  []int                 `json:"by_month_day"`
+	StartDate   time.Time             `json:"start_date" binding:"required"`
+	EndDate     *time.Time            `json:"end_date"`
+}
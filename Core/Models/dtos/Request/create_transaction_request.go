@@ -0,0 +1,22 @@
+package dtos
+
+import (
+	"Financial/Core/types"
+	"time"
+)
+
+// CreateTransactionRequest representa la estructura de la solicitud para registrar un movimiento
+// swagger:model
+// @name CreateTransactionRequest
+type CreateTransactionRequest struct {
+	WalletID    int                   `json:"wallet_id"`
+	Type        types.TransactionType `json:"type" binding:"required"`
+	Amount      types.Money           `json:"amount"`
+	Description string                `json:"description"`
+	CategoryID  *int                  `json:"category_id"`
+	// Set only by the recurring scheduler, never bound from the request body
+	RecurringID    *int       `json:"-"`
+	OccurrenceDate *time.Time `json:"-"`
+	// Set only by statement imports to keep the date of the movement in the bank
+	PostedAt *time.Time `json:"-"`
+}
@@ -0,0 +1,13 @@
+package dtos
+
+import "Financial/Core/types"
+
+// CreateTransferRequest representa la estructura de la solicitud para transferir entre billeteras
+// swagger:model
+// @name CreateTransferRequest
+type CreateTransferRequest struct {
+	FromWalletID int         `json:"from_wallet_id" binding:"required"`
+	ToWalletID   int         `json:"to_wallet_id" binding:"required"`
+	Amount       types.Money `json:"amount"`
+	Description  string      `json:"description"`
+}
@@ -5,6 +5,7 @@ import "Financial/Core/types"
 type CreateWalletRequest struct {
 	Name       string           `json:"name"`
 	WalletType types.WalletType `json:"type"`
-	Balance    float64          `json:"balance"`
+	Balance    types.Money      `json:"balance"`
+	Currency   string           `json:"currency"`
 	UserID     int              `json:"accoundId"`
 }
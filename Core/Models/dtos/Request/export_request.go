@@ -0,0 +1,12 @@
+package dtos
+
+import "time"
+
+// ExportRequest representa la estructura de la solicitud para exportar las carteras y sus movimientos.
+// From and To limit the movements to a range of days (both inclusive); nil means unbounded.
+type ExportRequest struct {
+	UserID int
+	Format string
+	From   *time.Time
+	To     *time.Time
+}
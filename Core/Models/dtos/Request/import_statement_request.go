@@ -0,0 +1,30 @@
+package dtos
+
+// ImportStatementRequest representa la estructura de la solicitud para importar un extracto bancario.
+// Content is the uploaded file; Mapping is only used by the CSV format.
+// swagger:model
+// @name ImportStatementRequest
+type ImportStatementRequest struct {
+	UserID   int           `json:"-"`
+	WalletID int           `json:"-"`
+	Format   string        `json:"format" form:"format"`
+	Content  []byte        `json:"-"`
+	Mapping  ColumnMapping `json:"mapping"`
+}
+
+// ColumnMapping tells the CSV importer where each field is. Columns are header names,
+// or zero-based indexes when the file has no header row. Either Amount (signed) or
+// Debit/Credit must be set.
+// swagger:model
+// @name ColumnMapping
+type ColumnMapping struct {
+	Date         string `json:"date" form:"date_column"`
+	Amount       string `json:"amount" form:"amount_column"`
+	Debit        string `json:"debit" form:"debit_column"`
+	Credit       string `json:"credit" form:"credit_column"`
+	Description  string `json:"description" form:"description_column"`
+	DateFormat   string `json:"date_format" form:"date_format"`
+	Delimiter    string `json:"delimiter" form:"delimiter"`
+	DecimalComma bool   `json:"decimal_comma" form:"decimal_comma"`
+	NoHeader     bool   `json:"no_header" form:"no_header"`
+}
@@ -0,0 +1,16 @@
+package dtos
+
+// ForgotPasswordRequest starts a password reset for the account registered with Email
+// swagger:model
+// @name ForgotPasswordRequest
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ResetPasswordRequest sets a new password with the token emailed by a forgot-password request
+// swagger:model
+// @name ResetPasswordRequest
+type ResetPasswordRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
@@ -0,0 +1,6 @@
+package dtos
+
+// RefreshTokenRequest carries the refresh token returned by login or by a previous refresh
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
@@ -0,0 +1,8 @@
+package dtos
+
+// ResendVerificationRequest representa la solicitud para reenviar el enlace de verificación
+// swagger:model
+// @name ResendVerificationRequest
+type ResendVerificationRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
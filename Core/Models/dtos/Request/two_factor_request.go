@@ -0,0 +1,16 @@
+package dtos
+
+// TwoFactorCodeRequest carries a code from the authenticator app, or a recovery code
+// swagger:model
+// @name TwoFactorCodeRequest
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// TwoFactorLoginRequest completes a login that answered with a two-factor challenge
+// swagger:model
+// @name TwoFactorLoginRequest
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+}
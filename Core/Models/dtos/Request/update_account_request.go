@@ -10,4 +10,6 @@ type UpdateAccountRequest struct {
 	Email     string `json:"email" binding:"omitempty,email"`
 	Status    string `json:"status"`
 	Password  string `json:"password"`
+	// CurrentPassword is required to change the password or email
+	CurrentPassword string `json:"current_password"`
 }
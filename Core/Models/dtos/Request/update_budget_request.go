@@ -0,0 +1,12 @@
+package dtos
+
+import "Financial/Core/types"
+
+// UpdateBudgetRequest representa la estructura de la solicitud para cambiar el límite de un presupuesto
+// swagger:model
+// @name UpdateBudgetRequest
+type UpdateBudgetRequest struct {
+	BudgetID int         `json:"-"`
+	UserID   int         `json:"-"`
+	Limit    types.Money `json:"limit"`
+}
@@ -0,0 +1,13 @@
+package dtos
+
+// UpdateCategoryRequest representa la estructura de la solicitud para renombrar o mover una categoría.
+// ParentID moves the category under another one; MoveToRoot turns it into a root category.
+// swagger:model
+// @name UpdateCategoryRequest
+type UpdateCategoryRequest struct {
+	CategoryID int    `json:"-"`
+	UserID     int    `json:"-"`
+	Name       string `json:"name"`
+	ParentID   *int   `json:"parent_id"`
+	MoveToRoot bool   `json:"move_to_root"`
+}
@@ -0,0 +1,19 @@
+package dtos
+
+import (
+	"Financial/Core/types"
+	"time"
+)
+
+// UpdateRecurringRequest representa la estructura de la solicitud para modificar un movimiento recurrente.
+// Nil fields are left unchanged; Status pauses ("paused"), resumes ("active") or ends ("ended") the series.
+// swagger:model
+// @name UpdateRecurringRequest
+type UpdateRecurringRequest struct {
+	RecurringID int                   `json:"-"`
+	UserID      int                   `json:"-"`
+	Amount      *types.Money          `json:"amount"`
+	Description *string               `json:"description"`
+	EndDate     *time.Time            `json:"end_date"`
+	Status      types.RecurringStatus `json:"status"`
+}
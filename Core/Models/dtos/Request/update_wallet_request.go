@@ -6,5 +6,5 @@ type UpdateWalletRequest struct {
 	WalletID   int               `json:"id"`
 	Name       string            `json:"name"`
 	WalletType *types.WalletType `json:"type"`
-	Balance    *float64          `json:"balance"`
+	Balance    *types.Money      `json:"balance"`
 }
@@ -0,0 +1,34 @@
+package response
+
+import "time"
+
+// APIKeyResponse describes a personal API key without its secret
+// swagger:model APIKeyResponse
+// @name APIKeyResponse
+type APIKeyResponse struct {
+	ID         int        `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// CreatedAPIKeyResponse is a new API key; Key is only returned this once
+// swagger:model CreatedAPIKeyResponse
+// @name CreatedAPIKeyResponse
+type CreatedAPIKeyResponse struct {
+	APIKeyResponse
+
+	// Key is sent in the X-API-Key header
+	Key string `json:"key"`
+}
+
+// APIKeyPrincipal is who an API key authenticates and what it may reach
+type APIKeyPrincipal struct {
+	UserID int
+	KeyID  int
+	Scopes []string
+}
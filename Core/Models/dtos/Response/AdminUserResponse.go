@@ -0,0 +1,20 @@
+package response
+
+import (
+	"Financial/Core/types"
+	"time"
+)
+
+// AdminUserResponse describes an account for the admin API; the password hash is never included
+// swagger:model AdminUserResponse
+// @name AdminUserResponse
+type AdminUserResponse struct {
+	ID        int                 `json:"id"`
+	Nickname  string              `json:"nick_name"`
+	FirstName string              `json:"first_name"`
+	LastName  string              `json:"last_name"`
+	Email     string              `json:"email"`
+	Status    types.AccountStatus `json:"status"`
+	Role      types.Role          `json:"role"`
+	CreatedAt time.Time           `json:"created_at"`
+}
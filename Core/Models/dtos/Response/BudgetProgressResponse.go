@@ -0,0 +1,24 @@
+package response
+
+import "Financial/Core/types"
+
+// BudgetProgressResponse lists how much of each budget of a period has been used
+type BudgetProgressResponse struct {
+	// Period is the month of the budgets, formatted as YYYY-MM
+	Period string `json:"period"`
+
+	Budgets []BudgetProgress `json:"budgets"`
+}
+
+// BudgetProgress is the state of one budget: what was planned and what was spent so far
+type BudgetProgress struct {
+	BudgetID     int         `json:"budget_id"`
+	CategoryID   int         `json:"category_id"`
+	CategoryName string      `json:"category_name"`
+	Limit        types.Money `json:"limit"`
+	Spent        types.Money `json:"spent"`
+	// Remaining is Limit minus Spent; it is negative once the budget is exceeded
+	Remaining types.Money `json:"remaining"`
+	Currency  string      `json:"currency"`
+	Exceeded  bool        `json:"exceeded"`
+}
@@ -0,0 +1,47 @@
+package response
+
+import (
+	"Financial/Core/Models/db"
+	"Financial/Core/types"
+	"time"
+)
+
+// ImportPreviewResponse shows what importing a statement would record, without recording anything
+type ImportPreviewResponse struct {
+	WalletID int    `json:"wallet_id"`
+	Format   string `json:"format"`
+
+	Lines []ImportLine `json:"lines"`
+
+	// New is the number of lines that would be recorded; Duplicates the ones that would be skipped
+	New        int `json:"new"`
+	Duplicates int `json:"duplicates"`
+}
+
+// ImportLine is one movement of the statement
+type ImportLine struct {
+	// Line is the position of the movement in the file
+	Line        int                   `json:"line"`
+	Date        time.Time             `json:"date"`
+	Type        types.TransactionType `json:"type"`
+	Amount      types.Money           `json:"amount"`
+	Description string                `json:"description"`
+	// Fingerprint identifies the movement by date, amount and description
+	Fingerprint string `json:"fingerprint"`
+	// Duplicate is true when the wallet already has the same movement
+	Duplicate bool `json:"duplicate"`
+}
+
+// ImportResultResponse reports what a committed import recorded
+type ImportResultResponse struct {
+	Imported     int                 `json:"imported"`
+	Duplicates   int                 `json:"duplicates"`
+	Transactions []db.Transaction    `json:"transactions"`
+	Failed       []ImportLineFailure `json:"failed,omitempty"`
+}
+
+// ImportLineFailure is a line that could not be recorded (e.g. it would overdraw a debit wallet)
+type ImportLineFailure struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
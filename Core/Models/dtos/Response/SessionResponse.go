@@ -0,0 +1,56 @@
+package response
+
+import "time"
+
+// SessionGrant is what a session use case hands to the transport layer after a login
+// or refresh: the values to sign into a new access token plus the new refresh token.
+type SessionGrant struct {
+	// SessionID identifies the session the tokens belong to
+	SessionID int
+
+	// Subject is the value to put in the sub claim of the access token
+	Subject string
+
+	// TokenID is the jti of the access token to issue
+	TokenID string
+
+	// RefreshToken is the opaque refresh token; it is only returned once
+	RefreshToken string
+
+	// RefreshExpiresAt is when RefreshToken stops being accepted
+	RefreshExpiresAt time.Time
+}
+
+// TokenResponse is returned by login and refresh
+// swagger:model TokenResponse
+// @name TokenResponse
+type TokenResponse struct {
+	// AccessToken is the short-lived JWT to send as "Authorization: Bearer ..."
+	AccessToken string `json:"access_token"`
+
+	// TokenType is always "Bearer"
+	TokenType string `json:"token_type"`
+
+	// ExpiresIn is the lifetime of AccessToken in seconds
+	ExpiresIn int `json:"expires_in"`
+
+	// RefreshToken is exchanged at /api/auth/refresh for a new pair of tokens; each one works once
+	RefreshToken string `json:"refresh_token"`
+
+	// RefreshExpiresAt is when RefreshToken stops being accepted
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
+
+// SessionResponse describes one login of the user, so unknown devices can be spotted and revoked
+// swagger:model SessionResponse
+// @name SessionResponse
+type SessionResponse struct {
+	ID         int        `json:"id"`
+	Device     string     `json:"device"`
+	IP         string     `json:"ip"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastSeenAt time.Time  `json:"last_seen_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	Current    bool       `json:"current"`
+}
@@ -0,0 +1,40 @@
+package response
+
+// TwoFactorSetupResponse is the secret to load in an authenticator app
+// swagger:model TwoFactorSetupResponse
+// @name TwoFactorSetupResponse
+type TwoFactorSetupResponse struct {
+	// Secret is the base32 secret, for apps where it is typed by hand
+	Secret string `json:"secret"`
+
+	// URI is the otpauth:// URI, usually shown as a QR code
+	URI string `json:"otpauth_uri"`
+}
+
+// RecoveryCodesResponse lists the single-use codes that replace the app if it is lost.
+// They are only shown once.
+// swagger:model RecoveryCodesResponse
+// @name RecoveryCodesResponse
+type RecoveryCodesResponse struct {
+	Codes []string `json:"recovery_codes"`
+}
+
+// TwoFactorChallengeResponse is the answer of a login with a right password on an account
+// with two-factor authentication; the challenge token and a code complete the login
+// swagger:model TwoFactorChallengeResponse
+// @name TwoFactorChallengeResponse
+type TwoFactorChallengeResponse struct {
+	TwoFactorRequired bool   `json:"two_factor_required"`
+	ChallengeToken    string `json:"challenge_token"`
+	ExpiresIn         int    `json:"expires_in"`
+}
+
+// AccessTokenResponse is a new access token for the current session, returned by a step-up
+// verification; the refresh token doesn't change
+// swagger:model AccessTokenResponse
+// @name AccessTokenResponse
+type AccessTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+}
@@ -1,7 +1,9 @@
 package response
 
 import (
+	"Financial/Core/Models/db"
 	"Financial/Core/types"
+	"time"
 )
 
 type UserWalletResponse struct {
@@ -14,9 +16,30 @@ type UserWalletResponse struct {
 	// Email is the user's email address (required, unique)
 	Email string `json:"email"`
 
-	Wallets []struct {
-		Name    string           `json:"name"`
-		Type    types.WalletType `json:"type"`
-		Balance float64          `json:"balance"`
-	}
+	Wallets []WalletSummary
+
+	// Totals is the sum of the wallet balances for each currency held by the user
+	Totals map[string]types.Money `json:"totals"`
+
+	// ReportingCurrency is the currency Total is expressed in, only set when a conversion was requested
+	ReportingCurrency string `json:"reporting_currency,omitempty"`
+
+	// Total is the sum of every wallet balance converted to ReportingCurrency
+	Total *types.Money `json:"total,omitempty"`
+
+	// RatesAsOf is the moment whose exchange rates were used for the conversion
+	RatesAsOf *time.Time `json:"rates_as_of,omitempty"`
+}
+
+// WalletSummary is the public view of a wallet returned inside UserWalletResponse,
+// including the movements recorded on its ledger.
+type WalletSummary struct {
+	ID       int              `json:"id"`
+	Name     string           `json:"name"`
+	Type     types.WalletType `json:"type"`
+	Balance  types.Money      `json:"balance"`
+	Currency string           `json:"currency"`
+	// ConvertedBalance is Balance in the reporting currency, only set when a conversion was requested
+	ConvertedBalance *types.Money     `json:"converted_balance,omitempty"`
+	Transactions     []db.Transaction `json:"transactions"`
 }
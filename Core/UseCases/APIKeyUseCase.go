@@ -0,0 +1,218 @@
+package usecases
+
+import (
+	"Financial/Core/Models/db"
+	dtos "Financial/Core/Models/dtos/Request"
+	response "Financial/Core/Models/dtos/Response"
+	"Financial/Core/ports"
+	"Financial/Core/types"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// apiKeyPrefix starts every key, so leaked keys are easy to spot in logs and code
+const apiKeyPrefix = "mfk_"
+
+// apiKeyDisplayLength is how much of the key is kept in clear to tell keys apart
+const apiKeyDisplayLength = len(apiKeyPrefix) + 6
+
+// apiKeyTouchInterval limits how often Authenticate writes LastUsedAt
+const apiKeyTouchInterval = time.Minute
+
+// APIKeyUseCase implements the APIKeyUseCase interface
+type APIKeyUseCase struct {
+	repository     ports.Repository[db.APIKey, int]
+	userRepository ports.Repository[db.User, int]
+	clock          ports.Clock
+}
+
+// NewAPIKeyUseCase creates a new instance of APIKeyUseCase.
+// userRepo is used to refuse keys of suspended accounts.
+func NewAPIKeyUseCase(repo ports.Repository[db.APIKey, int], userRepo ports.Repository[db.User, int], clock ports.Clock) ports.APIKeyUseCase {
+	return &APIKeyUseCase{
+		repository:     repo,
+		userRepository: userRepo,
+		clock:          clock,
+	}
+}
+
+// CreateKey implements APIKeyUseCase.CreateKey
+func (uc *APIKeyUseCase) CreateKey(userID int, request dtos.CreateAPIKeyRequest) (*response.CreatedAPIKeyResponse, *response.ErrorResponse) {
+	now := uc.clock.Now().UTC()
+
+	name := strings.TrimSpace(request.Name)
+	if name == "" {
+		return nil, &response.ErrorResponse{
+			Error: errors.New("name is required").Error(),
+		}
+	}
+	if len(request.Scopes) == 0 {
+		return nil, &response.ErrorResponse{
+			Error: errors.New("at least one scope is required").Error(),
+		}
+	}
+	scopes := make([]string, 0, len(request.Scopes))
+	seen := map[string]bool{}
+	for _, scope := range request.Scopes {
+		if !types.APIKeyScope(scope).IsValid() {
+			return nil, &response.ErrorResponse{
+				Error: fmt.Errorf("unknown scope %q", scope).Error(),
+			}
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+	if request.ExpiresAt != nil && !request.ExpiresAt.After(now) {
+		return nil, &response.ErrorResponse{
+			Error: errors.New("expires_at must be in the future").Error(),
+		}
+	}
+
+	token, err := randomToken()
+	if err != nil {
+		return nil, &response.ErrorResponse{
+			Error: fmt.Errorf("error generating key: %w", err).Error(),
+		}
+	}
+	key := apiKeyPrefix + token
+
+	created, err := uc.repository.Create(&db.APIKey{
+		UserID:    userID,
+		Name:      name,
+		Prefix:    key[:apiKeyDisplayLength],
+		KeyHash:   hashToken(key),
+		Scopes:    scopes,
+		CreatedAt: now,
+		ExpiresAt: request.ExpiresAt,
+	})
+	if err != nil {
+		return nil, &response.ErrorResponse{
+			Error: fmt.Errorf("error creating API key: %w", err).Error(),
+		}
+	}
+
+	return &response.CreatedAPIKeyResponse{
+		APIKeyResponse: newAPIKeyResponse(created),
+		Key:            key,
+	}, nil
+}
+
+// ListKeys implements APIKeyUseCase.ListKeys
+func (uc *APIKeyUseCase) ListKeys(userID int) ([]response.APIKeyResponse, *response.ErrorResponse) {
+	page, err := uc.repository.Query("*", ports.QueryOptions{
+		Filters: []ports.Filter{
+			{Field: "user_id", Operator: "eq", Value: userID},
+		},
+	})
+	if err != nil {
+		return nil, &response.ErrorResponse{
+			Error: fmt.Errorf("error fetching API keys: %w", err).Error(),
+		}
+	}
+
+	keys := page.Items
+
+	sort.SliceStable(keys, func(i, j int) bool {
+		return keys[i].CreatedAt.After(keys[j].CreatedAt)
+	})
+
+	result := make([]response.APIKeyResponse, 0, len(keys))
+	for i := range keys {
+		result = append(result, newAPIKeyResponse(&keys[i]))
+	}
+	return result, nil
+}
+
+// RevokeKey implements APIKeyUseCase.RevokeKey
+func (uc *APIKeyUseCase) RevokeKey(userID int, keyID int) *response.ErrorResponse {
+	key, err := uc.repository.GetByID(keyID)
+	// Keys of other users are reported as missing
+	if err != nil || key.UserID != userID {
+		if err == nil || err == types.ErrNotFound {
+			return &response.ErrorResponse{
+				Error: errors.New("API key not found").Error(),
+			}
+		}
+		return &response.ErrorResponse{
+			Error: fmt.Errorf("error fetching API key: %w", err).Error(),
+		}
+	}
+
+	if key.RevokedAt != nil {
+		return nil
+	}
+	now := uc.clock.Now().UTC()
+	key.RevokedAt = &now
+	if _, err := uc.repository.Update(key); err != nil {
+		return &response.ErrorResponse{
+			Error: fmt.Errorf("error revoking API key: %w", err).Error(),
+		}
+	}
+	return nil
+}
+
+// Authenticate implements APIKeyUseCase.Authenticate
+func (uc *APIKeyUseCase) Authenticate(key string) (*response.APIKeyPrincipal, error) {
+	if !strings.HasPrefix(key, apiKeyPrefix) {
+		return nil, types.ErrInvalidAPIKey
+	}
+
+	stored, err := uc.repository.FindByField("key_hash", hashToken(key))
+	if err == types.ErrNotFound {
+		return nil, types.ErrInvalidAPIKey
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching API key: %w", err)
+	}
+
+	now := uc.clock.Now().UTC()
+	if !stored.Active(now) {
+		return nil, types.ErrInvalidAPIKey
+	}
+
+	user, err := uc.userRepository.GetByID(stored.UserID)
+	if err == types.ErrNotFound {
+		return nil, types.ErrInvalidAPIKey
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching account: %w", err)
+	}
+	if user.Status == types.Suspend {
+		return nil, types.ErrInvalidAPIKey
+	}
+
+	if stored.LastUsedAt == nil || now.Sub(*stored.LastUsedAt) >= apiKeyTouchInterval {
+		stored.LastUsedAt = &now
+		// Losing a last-used update is not a reason to reject the request
+		_, _ = uc.repository.Update(stored)
+	}
+
+	return &response.APIKeyPrincipal{
+		UserID: stored.UserID,
+		KeyID:  stored.ID,
+		Scopes: stored.Scopes,
+	}, nil
+}
+
+// newAPIKeyResponse maps a key to its public view, leaving out the hash
+func newAPIKeyResponse(key *db.APIKey) response.APIKeyResponse {
+	scopes := key.Scopes
+	if scopes == nil {
+		scopes = []string{}
+	}
+	return response.APIKeyResponse{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     scopes,
+		CreatedAt:  key.CreatedAt,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		RevokedAt:  key.RevokedAt,
+	}
+}
@@ -8,18 +8,28 @@ import (
 	"Financial/Core/types"
 	"Financial/Core/validators"
 
+	"crypto/subtle"
 	"errors"
 	"fmt"
 	"time"
 )
 
 type AccountUseCase struct {
-	repository ports.Repository[db.User, int]
+	repository      ports.Repository[db.User, int]
+	unitOfWork      ports.UnitOfWork
+	hasher          ports.PasswordHasher
+	requireVerified bool
 }
 
-func NewAccountUseCase(repo ports.Repository[db.User, int]) ports.UserUseCase {
+// NewAccountUseCase creates a new instance of AccountUseCase.
+// Creating an account runs inside unitOfWork, since it writes the user and its default categories.
+// When requireVerified is set, accounts whose email is not verified yet can't log in.
+func NewAccountUseCase(repo ports.Repository[db.User, int], unitOfWork ports.UnitOfWork, hasher ports.PasswordHasher, requireVerified bool) ports.UserUseCase {
 	return &AccountUseCase{
-		repository: repo,
+		repository:      repo,
+		unitOfWork:      unitOfWork,
+		hasher:          hasher,
+		requireVerified: requireVerified,
 	}
 }
 
@@ -78,20 +88,45 @@ func (uc *AccountUseCase) CreateAccount(nick string, email string, password stri
 		return nil, &validationsError
 	}
 
+	hash, errHash := uc.hasher.Hash(password)
+	if errHash != nil {
+		validationsError = append(validationsError, response.ErrorResponse{
+			Error: fmt.Errorf("error hashing password: %w", errHash).Error(),
+		})
+		return nil, &validationsError
+	}
+
 	account := &db.User{
 		Nickname:  nick,
 		FirstName: "",
 		Lastname:  "",
 		Email:     email,
 		Status:    types.Inactive,
+		Role:      types.RoleUser,
 		CreatedAt: time.Now(),
-		Password:  password,
+		Password:  hash,
 	}
-	result, error := uc.repository.Create(account)
 
-	if error != nil {
+	// La cuenta y su árbol de categorías por defecto se guardan juntos o no se guarda nada
+	var result *db.User
+	errCreate := uc.unitOfWork.Do(func(repos ports.UnitOfWorkRepositories) error {
+		created, err := repos.Users.Create(account)
+		if err != nil {
+			return fmt.Errorf("error checking nick existence: %w", err)
+		}
+
+		// Las cuentas nuevas empiezan con el árbol de categorías por defecto
+		categories := NewCategoryUseCase(repos.Categories, repos.Transactions)
+		if errSeed := categories.SeedDefaultCategories(created.ID); errSeed != nil {
+			return errors.New(errSeed.Error)
+		}
+		result = created
+		return nil
+	})
+
+	if errCreate != nil {
 		validationsError = append(validationsError, response.ErrorResponse{
-			Error: fmt.Sprintf("error checking nick existence: %w", error),
+			Error: errCreate.Error(),
 		})
 		return nil, &validationsError
 	}
@@ -108,7 +143,7 @@ func (uc *AccountUseCase) CreateAccount(nick string, email string, password stri
 	}, nil
 }
 
-func (uc *AccountUseCase) DestroyAccount(email string) *[]response.ErrorResponse {
+func (uc *AccountUseCase) DestroyAccount(userID int, email string) *[]response.ErrorResponse {
 	validationsError := []response.ErrorResponse{}
 	validator := validators.DestroidAccountValidator(email, uc.repository)
 
@@ -126,9 +161,16 @@ func (uc *AccountUseCase) DestroyAccount(email string) *[]response.ErrorResponse
 		return &validationsError
 	}
 
-	err := uc.repository.Delete(user.ID)
+	// Accounts of other users are reported as missing
+	if user.ID != userID {
+		validationsError = append(validationsError, response.ErrorResponse{
+			Error: "User not found",
+		})
+		return &validationsError
+	}
 
-	if err != nil {
+	// A single delete: the schema cascades it to the wallets, transactions and categories of the user
+	if err := uc.repository.Delete(user.ID); err != nil {
 		validationsError = append(validationsError, response.ErrorResponse{
 			Error: err.Error(),
 		})
@@ -138,6 +180,32 @@ func (uc *AccountUseCase) DestroyAccount(email string) *[]response.ErrorResponse
 	return nil
 }
 
+// GetAccount implements UserUseCase.GetAccount
+func (uc *AccountUseCase) GetAccount(userID int) (*response.AccountResponse, *response.ErrorResponse) {
+	user, err := uc.repository.GetByID(userID)
+	if err != nil {
+		if err == types.ErrNotFound {
+			return nil, &response.ErrorResponse{
+				Error: errors.New("account not found").Error(),
+			}
+		}
+		return nil, &response.ErrorResponse{
+			Error: fmt.Errorf("error fetching account: %w", err).Error(),
+		}
+	}
+
+	return &response.AccountResponse{
+		ID:        user.ID,
+		Nickname:  user.Nickname,
+		FirstName: user.FirstName,
+		LastName:  user.Lastname,
+		Email:     user.Email,
+		Status:    user.Status,
+		Role:      user.EffectiveRole(),
+		CreatedAt: user.CreatedAt,
+	}, nil
+}
+
 func (uc *AccountUseCase) UpdateAccount(req db.UpdateAccountRequest) (*response.SuccessResponse[*response.UpdateAccountResponse], *[]response.ErrorResponse) {
 
 	validationsError := []response.ErrorResponse{}
@@ -152,8 +220,30 @@ func (uc *AccountUseCase) UpdateAccount(req db.UpdateAccountRequest) (*response.
 		return nil, &validationsError
 	}
 
-	user := uc.validateAndGetUser(req.Email, &validationsError)
-	if len(validationsError) > 0 {
+	user, err := uc.repository.GetByID(req.ID)
+	if err != nil {
+		validationsError = append(validationsError, response.ErrorResponse{
+			Error: "User not found",
+		})
+		return nil, &validationsError
+	}
+
+	// Whoever holds a session can't take the account over without knowing its password
+	emailChanged := req.Email != "" && req.Email != user.Email
+	if req.Password != "" || emailChanged {
+		if req.CurrentPassword == "" || !uc.verifyPassword(user, req.CurrentPassword) {
+			validationsError = append(validationsError, response.ErrorResponse{
+				Error: types.ErrCurrentPasswordRequired.Error(),
+			})
+			return nil, &validationsError
+		}
+	}
+
+	// Activation goes through email verification and suspension through the admin API
+	if req.Status != "" && req.Status != user.Status {
+		validationsError = append(validationsError, response.ErrorResponse{
+			Error: "Account status can only be changed by an administrator",
+		})
 		return nil, &validationsError
 	}
 
@@ -167,19 +257,21 @@ func (uc *AccountUseCase) UpdateAccount(req db.UpdateAccountRequest) (*response.
 		user.Lastname = req.Lastname
 		updated = true
 	}
-	if req.Password != "" {
-		user.Password = req.Password // En un caso real, hashear la contraseña
+	if emailChanged {
+		user.Email = req.Email
 		updated = true
 	}
-	if req.Status != "" {
-		user.Status = req.Status
+	if req.Password != "" {
+		hash, errHash := uc.hasher.Hash(req.Password)
+		if errHash != nil {
+			validationsError = append(validationsError, response.ErrorResponse{
+				Error: fmt.Errorf("error hashing password: %w", errHash).Error(),
+			})
+			return nil, &validationsError
+		}
+		user.Password = hash
 		updated = true
 	}
-
-	if user.ID != req.ID {
-		//return nil, errors.New("user id and Mail not match")
-	}
-
 	if !updated {
 		return &response.SuccessResponse[*response.UpdateAccountResponse]{
 			Message: "NoChanges",
@@ -209,7 +301,9 @@ func (uc *AccountUseCase) UpdateAccount(req db.UpdateAccountRequest) (*response.
 
 }
 
-func (uc *AccountUseCase) Login(auth dtos.AuthRequest) (*string, error) {
+// Login implements UserUseCase.Login. The account is looked up by email, or by nickname
+// when no email is given; unknown accounts and wrong passwords fail the same way.
+func (uc *AccountUseCase) Login(auth dtos.AuthRequest) (*int, error) {
 	v := validators.NewValidator()
 	if auth.Email == "" && auth.Nickname == "" {
 		v.AddError("nick or email can't be empty")
@@ -223,30 +317,55 @@ func (uc *AccountUseCase) Login(auth dtos.AuthRequest) (*string, error) {
 		return nil, errors.New(v.Error())
 	}
 
-	data, err := uc.repository.Query("email, password", ports.QueryOptions{
-		Filters: []ports.Filter{
-			{
-				Field:    "email",
-				Operator: "eq",
-				Value:    auth.Email,
-			},
-			{
-				Field:    "password",
-				Operator: "eq",
-				Value:    auth.Passwd,
-			},
-		},
-	})
+	field, identifier := "email", auth.Email
+	if identifier == "" {
+		field, identifier = "nick_name", auth.Nickname
+	}
 
+	user, err := uc.repository.FindByField(field, identifier)
 	if err != nil {
-		return nil, errors.New("account not found")
+		// Hashing anyway keeps unknown accounts as slow as wrong passwords
+		_, _ = uc.hasher.Hash(auth.Passwd)
+		if err != types.ErrNotFound {
+			return nil, fmt.Errorf("error fetching account: %w", err)
+		}
+		return nil, types.ErrInvalidCredentials
+	}
+
+	if !uc.verifyPassword(user, auth.Passwd) {
+		return nil, types.ErrInvalidCredentials
+	}
+
+	// Checked after the password, so it doesn't reveal which accounts exist
+	if user.Status == types.Suspend {
+		return nil, types.ErrAccountSuspended
+	}
+	if uc.requireVerified && awaitingVerification(user.Status) {
+		return nil, types.ErrAccountNotVerified
 	}
 
-	user, ok := data.([]db.User)
+	return &user.ID, nil
+}
 
-	if !ok {
-		return nil, errors.New("account not found")
+// verifyPassword checks the password of the user, upgrading the stored value when it is
+// plain text (accounts created before passwords were hashed) or an outdated hash.
+func (uc *AccountUseCase) verifyPassword(user *db.User, password string) bool {
+	matches, err := uc.hasher.Verify(password, user.Password)
+	if errors.Is(err, types.ErrUnknownHash) {
+		matches = subtle.ConstantTimeCompare([]byte(user.Password), []byte(password)) == 1
+	} else if err != nil {
+		return false
+	}
+	if !matches {
+		return false
 	}
 
-	return &user[0].Email, nil
+	if uc.hasher.NeedsRehash(user.Password) {
+		// A failed upgrade is retried on the next login; the password was still right
+		if hash, errHash := uc.hasher.Hash(password); errHash == nil {
+			user.Password = hash
+			_, _ = uc.repository.Update(user)
+		}
+	}
+	return true
 }
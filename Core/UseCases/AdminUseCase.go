@@ -0,0 +1,186 @@
+package usecases
+
+import (
+	"Financial/Core/Models/db"
+	response "Financial/Core/Models/dtos/Response"
+	"Financial/Core/ports"
+	"Financial/Core/types"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// AdminUseCase implements the AdminUseCase interface
+type AdminUseCase struct {
+	repository ports.Repository[db.User, int]
+	wallets    ports.WalletUseCase
+	sessions   ports.SessionUseCase
+	lockouts   ports.LoginThrottle
+}
+
+// NewAdminUseCase creates a new instance of AdminUseCase.
+// Suspended accounts are logged out through sessions, and a status set by hand ends any
+// lockout of lockouts, so it isn't overwritten when the lockout expires.
+func NewAdminUseCase(repo ports.Repository[db.User, int], wallets ports.WalletUseCase, sessions ports.SessionUseCase, lockouts ports.LoginThrottle) ports.AdminUseCase {
+	return &AdminUseCase{
+		repository: repo,
+		wallets:    wallets,
+		sessions:   sessions,
+		lockouts:   lockouts,
+	}
+}
+
+// Authorize implements Authorizer.Authorize
+func (uc *AdminUseCase) Authorize(userID int, permission types.Permission) error {
+	user, err := uc.repository.GetByID(userID)
+	if err != nil {
+		if err == types.ErrNotFound {
+			return types.ErrForbidden
+		}
+		return fmt.Errorf("error fetching account: %w", err)
+	}
+	// The role is read on every check, so a demotion or suspension applies at once
+	if user.Status == types.Suspend || !user.EffectiveRole().Can(permission) {
+		return types.ErrForbidden
+	}
+	return nil
+}
+
+// ListUsers implements AdminUseCase.ListUsers
+func (uc *AdminUseCase) ListUsers() ([]response.AdminUserResponse, *response.ErrorResponse) {
+	users, err := uc.repository.GetAll()
+	if err != nil {
+		return nil, &response.ErrorResponse{
+			Error: fmt.Errorf("error fetching accounts: %w", err).Error(),
+		}
+	}
+
+	sort.SliceStable(users, func(i, j int) bool {
+		return users[i].ID < users[j].ID
+	})
+
+	result := make([]response.AdminUserResponse, 0, len(users))
+	for i := range users {
+		result = append(result, newAdminUserResponse(&users[i]))
+	}
+	return result, nil
+}
+
+// SetAccountStatus implements AdminUseCase.SetAccountStatus
+func (uc *AdminUseCase) SetAccountStatus(actorID int, userID int, status types.AccountStatus) (*response.AdminUserResponse, *response.ErrorResponse) {
+	switch status {
+	case types.Active, types.Inactive, types.Pending, types.Suspend:
+	default:
+		return nil, &response.ErrorResponse{
+			Error: fmt.Errorf("unknown account status %q", status).Error(),
+		}
+	}
+
+	user, errRes := uc.managedUser(actorID, userID)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	user.Status = status
+	updated, err := uc.repository.Update(user)
+	if err != nil {
+		return nil, &response.ErrorResponse{
+			Error: fmt.Errorf("error updating account: %w", err).Error(),
+		}
+	}
+
+	// The status set by an administrator wins over the one a lockout would restore
+	if err := uc.lockouts.Release(userID); err != nil {
+		return nil, &response.ErrorResponse{
+			Error: fmt.Errorf("account updated but its lockout was not released: %w", err).Error(),
+		}
+	}
+
+	// A suspended user must not keep using the tokens they already hold
+	if status == types.Suspend {
+		if errRes := uc.sessions.LogoutAll(userID); errRes != nil {
+			return nil, &response.ErrorResponse{
+				Error: fmt.Errorf("account suspended but sessions were not revoked: %s", errRes.Error).Error(),
+			}
+		}
+	}
+
+	result := newAdminUserResponse(updated)
+	return &result, nil
+}
+
+// SetRole implements AdminUseCase.SetRole
+func (uc *AdminUseCase) SetRole(actorID int, userID int, role types.Role) (*response.AdminUserResponse, *response.ErrorResponse) {
+	if !role.IsValid() {
+		return nil, &response.ErrorResponse{
+			Error: fmt.Errorf("unknown role %q", role).Error(),
+		}
+	}
+
+	user, errRes := uc.managedUser(actorID, userID)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	user.Role = role
+	updated, err := uc.repository.Update(user)
+	if err != nil {
+		return nil, &response.ErrorResponse{
+			Error: fmt.Errorf("error updating account: %w", err).Error(),
+		}
+	}
+
+	result := newAdminUserResponse(updated)
+	return &result, nil
+}
+
+// GetUserWallets implements AdminUseCase.GetUserWallets
+func (uc *AdminUseCase) GetUserWallets(userID int, reportingCurrency string, asOf time.Time) (*response.UserWalletResponse, *response.ErrorResponse) {
+	user, errRes := uc.getUser(userID)
+	if errRes != nil {
+		return nil, errRes
+	}
+	return uc.wallets.GetUserWallet(user.ID, user.Email, reportingCurrency, asOf)
+}
+
+// managedUser fetches the account an administrator is changing; changing your own account
+// could lock the last administrator out, so it is refused
+func (uc *AdminUseCase) managedUser(actorID int, userID int) (*db.User, *response.ErrorResponse) {
+	if actorID == userID {
+		return nil, &response.ErrorResponse{
+			Error: errors.New("you can't change your own account from the admin API").Error(),
+		}
+	}
+	return uc.getUser(userID)
+}
+
+// getUser fetches an account by ID
+func (uc *AdminUseCase) getUser(userID int) (*db.User, *response.ErrorResponse) {
+	user, err := uc.repository.GetByID(userID)
+	if err != nil {
+		if err == types.ErrNotFound {
+			return nil, &response.ErrorResponse{
+				Error: errors.New("account not found").Error(),
+			}
+		}
+		return nil, &response.ErrorResponse{
+			Error: fmt.Errorf("error fetching account: %w", err).Error(),
+		}
+	}
+	return user, nil
+}
+
+// newAdminUserResponse maps an account to its admin view
+func newAdminUserResponse(user *db.User) response.AdminUserResponse {
+	return response.AdminUserResponse{
+		ID:        user.ID,
+		Nickname:  user.Nickname,
+		FirstName: user.FirstName,
+		LastName:  user.Lastname,
+		Email:     user.Email,
+		Status:    user.Status,
+		Role:      user.EffectiveRole(),
+		CreatedAt: user.CreatedAt,
+	}
+}
@@ -0,0 +1,315 @@
+package usecases
+
+import (
+	"Financial/Core/Models/db"
+	dtos "Financial/Core/Models/dtos/Request"
+	response "Financial/Core/Models/dtos/Response"
+	"Financial/Core/ports"
+	"Financial/Core/types"
+	"Financial/Core/validators"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+var periodPattern = regexp.MustCompile(validators.PeriodPattern)
+
+// BudgetUseCase implements the BudgetUseCase interface
+type BudgetUseCase struct {
+	repository            ports.Repository[db.Budget, int]
+	categoryRepository    ports.Repository[db.Category, int]
+	walletRepository      ports.Repository[db.Wallet, int]
+	transactionRepository ports.Repository[db.Transaction, int]
+}
+
+// NewBudgetUseCase creates a new instance of BudgetUseCase
+func NewBudgetUseCase(repo ports.Repository[db.Budget, int], categoryRepo ports.Repository[db.Category, int], walletRepo ports.Repository[db.Wallet, int], transactionRepo ports.Repository[db.Transaction, int]) ports.BudgetUseCase {
+	return &BudgetUseCase{
+		repository:            repo,
+		categoryRepository:    categoryRepo,
+		walletRepository:      walletRepo,
+		transactionRepository: transactionRepo,
+	}
+}
+
+// periodRange returns the first instant of the month and the first instant of the next one (UTC)
+func periodRange(period string) (time.Time, time.Time, error) {
+	if !periodPattern.MatchString(period) {
+		return time.Time{}, time.Time{}, errors.New("period must have the format YYYY-MM")
+	}
+	start, err := time.Parse("2006-01", period)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid period: %w", err)
+	}
+	return start, start.AddDate(0, 1, 0), nil
+}
+
+// userBudgets lists the budgets of the user, optionally only those of one period
+func (uc *BudgetUseCase) userBudgets(userID int, period string) ([]db.Budget, *response.ErrorResponse) {
+	filters := []ports.Filter{
+		{
+			Field:    "user_id",
+			Operator: "eq",
+			Value:    userID,
+		},
+	}
+	if period != "" {
+		filters = append(filters, ports.Filter{
+			Field:    "period",
+			Operator: "eq",
+			Value:    period,
+		})
+	}
+
+	page, err := uc.repository.Query("*", ports.QueryOptions{Filters: filters})
+	if err != nil {
+		return nil, &response.ErrorResponse{
+			Error: fmt.Errorf("error fetching budgets: %w", err).Error(),
+		}
+	}
+
+	return page.Items, nil
+}
+
+// getUserBudget fetches a budget, reporting budgets of other users as missing
+func (uc *BudgetUseCase) getUserBudget(userID int, budgetID int) (*db.Budget, *response.ErrorResponse) {
+	if budgetID <= 0 {
+		return nil, &response.ErrorResponse{
+			Error: errors.New("invalid budget ID").Error(),
+		}
+	}
+
+	budget, err := uc.repository.GetByID(budgetID)
+	if err != nil || budget.UserID != userID {
+		if err == nil || err == types.ErrNotFound {
+			return nil, &response.ErrorResponse{
+				Error: errors.New("budget not found").Error(),
+			}
+		}
+		return nil, &response.ErrorResponse{
+			Error: fmt.Errorf("error fetching budget: %w", err).Error(),
+		}
+	}
+	return budget, nil
+}
+
+// CreateBudget implements BudgetUseCase.CreateBudget
+func (uc *BudgetUseCase) CreateBudget(request dtos.CreateBudgetRequest) (*db.Budget, *response.ErrorResponse) {
+	request.Currency = strings.ToUpper(strings.TrimSpace(request.Currency))
+	if request.Currency == "" {
+		request.Currency = types.DefaultCurrency
+	}
+
+	success, errorsVal := validators.ValidateBudget(request)
+	if !success {
+		return nil, &response.ErrorResponse{
+			Error: strings.Join(*errorsVal, " \n"),
+		}
+	}
+
+	categories, errCategories := categoriesByID(uc.categoryRepository, request.UserID)
+	if errCategories != nil {
+		return nil, errCategories
+	}
+	category, ok := categories[request.CategoryID]
+	if !ok {
+		return nil, &response.ErrorResponse{
+			Error: errors.New("category not found").Error(),
+		}
+	}
+	if category.Type != types.Expense {
+		return nil, &response.ErrorResponse{
+			Error: errors.New("budgets can only be set on expense categories").Error(),
+		}
+	}
+
+	existing, errBudgets := uc.userBudgets(request.UserID, request.Period)
+	if errBudgets != nil {
+		return nil, errBudgets
+	}
+	for _, budget := range existing {
+		if budget.CategoryID == request.CategoryID {
+			return nil, &response.ErrorResponse{
+				Error: errors.New("the category already has a budget for this period").Error(),
+			}
+		}
+	}
+
+	result, err := uc.repository.Create(&db.Budget{
+		UserID:     request.UserID,
+		CategoryID: request.CategoryID,
+		Period:     request.Period,
+		Limit:      request.Limit.WithCurrency(request.Currency),
+		Currency:   request.Currency,
+	})
+	if err != nil {
+		return nil, &response.ErrorResponse{
+			Error: fmt.Errorf("error creating budget: %w", err).Error(),
+		}
+	}
+
+	return result, nil
+}
+
+// UpdateBudget implements BudgetUseCase.UpdateBudget
+func (uc *BudgetUseCase) UpdateBudget(request dtos.UpdateBudgetRequest) (*db.Budget, *response.ErrorResponse) {
+	if !request.Limit.IsPositive() {
+		return nil, &response.ErrorResponse{
+			Error: errors.New("Limit must be greater than zero").Error(),
+		}
+	}
+
+	budget, errBudget := uc.getUserBudget(request.UserID, request.BudgetID)
+	if errBudget != nil {
+		return nil, errBudget
+	}
+
+	budget.Limit = request.Limit.WithCurrency(budget.Currency)
+	result, err := uc.repository.Update(budget)
+	if err != nil {
+		return nil, &response.ErrorResponse{
+			Error: fmt.Errorf("error updating budget: %w", err).Error(),
+		}
+	}
+
+	return result, nil
+}
+
+// DeleteBudget implements BudgetUseCase.DeleteBudget
+func (uc *BudgetUseCase) DeleteBudget(userID int, budgetID int) *response.ErrorResponse {
+	if _, errBudget := uc.getUserBudget(userID, budgetID); errBudget != nil {
+		return errBudget
+	}
+
+	if err := uc.repository.Delete(budgetID); err != nil {
+		return &response.ErrorResponse{
+			Error: fmt.Errorf("error deleting budget: %w", err).Error(),
+		}
+	}
+	return nil
+}
+
+// spentByCategory adds up the expenses of the user's wallets in the period,
+// grouped by currency and category. Untagged expenses are not counted.
+func (uc *BudgetUseCase) spentByCategory(userID int, start time.Time, end time.Time) (map[string]map[int]types.Money, *response.ErrorResponse) {
+	page, err := uc.walletRepository.Query("id,currency", ports.QueryOptions{
+		Filters: []ports.Filter{
+			{
+				Field:    "user_id",
+				Operator: "eq",
+				Value:    userID,
+			},
+		},
+	})
+	if err != nil {
+		return nil, &response.ErrorResponse{
+			Error: fmt.Errorf("error fetching wallets: %w", err).Error(),
+		}
+	}
+	wallets := page.Items
+
+	spent := map[string]map[int]types.Money{}
+	for _, wallet := range wallets {
+		currency := wallet.Currency
+		if currency == "" {
+			currency = types.DefaultCurrency
+		}
+
+		page, err := uc.transactionRepository.Query("*", ports.QueryOptions{
+			Filters: []ports.Filter{
+				{Field: "wallet_id", Operator: "eq", Value: wallet.ID},
+				{Field: "type", Operator: "eq", Value: string(types.Expense)},
+				{Field: "created_at", Operator: "gte", Value: start.Format(time.RFC3339)},
+				{Field: "created_at", Operator: "lt", Value: end.Format(time.RFC3339)},
+			},
+		})
+		if err != nil {
+			return nil, &response.ErrorResponse{
+				Error: fmt.Errorf("error fetching wallet transactions: %w", err).Error(),
+			}
+		}
+		transactions := page.Items
+
+		for _, transaction := range transactions {
+			if transaction.CategoryID == nil || transaction.Type != types.Expense {
+				continue
+			}
+			if spent[currency] == nil {
+				spent[currency] = map[int]types.Money{}
+			}
+			total := spent[currency][*transaction.CategoryID]
+			spent[currency][*transaction.CategoryID] = types.NewMoney(total.Minor+transaction.Amount.Minor, currency)
+		}
+	}
+	return spent, nil
+}
+
+// GetBudgetProgress implements BudgetUseCase.GetBudgetProgress
+func (uc *BudgetUseCase) GetBudgetProgress(userID int, period string) (*response.BudgetProgressResponse, *response.ErrorResponse) {
+	start, end, errPeriod := periodRange(period)
+	if errPeriod != nil {
+		return nil, &response.ErrorResponse{
+			Error: errPeriod.Error(),
+		}
+	}
+
+	budgets, errBudgets := uc.userBudgets(userID, period)
+	if errBudgets != nil {
+		return nil, errBudgets
+	}
+
+	result := &response.BudgetProgressResponse{
+		Period:  period,
+		Budgets: []response.BudgetProgress{},
+	}
+	if len(budgets) == 0 {
+		return result, nil
+	}
+
+	categories, errCategories := categoriesByID(uc.categoryRepository, userID)
+	if errCategories != nil {
+		return nil, errCategories
+	}
+
+	spent, errSpent := uc.spentByCategory(userID, start, end)
+	if errSpent != nil {
+		return nil, errSpent
+	}
+
+	for _, budget := range budgets {
+		currency := budget.Currency
+		if currency == "" {
+			currency = types.DefaultCurrency
+		}
+
+		// A budget covers its category and every subcategory below it
+		total := types.NewMoney(0, currency)
+		for categoryID, amount := range spent[currency] {
+			if isDescendant(categories, categoryID, budget.CategoryID) {
+				total.Minor += amount.Minor
+			}
+		}
+
+		limit := budget.Limit.WithCurrency(currency)
+		remaining := types.NewMoney(limit.Minor-total.Minor, currency)
+		result.Budgets = append(result.Budgets, response.BudgetProgress{
+			BudgetID:     budget.ID,
+			CategoryID:   budget.CategoryID,
+			CategoryName: categories[budget.CategoryID].Name,
+			Limit:        limit,
+			Spent:        total,
+			Remaining:    remaining,
+			Currency:     currency,
+			Exceeded:     remaining.IsNegative(),
+		})
+	}
+
+	sort.Slice(result.Budgets, func(i, j int) bool {
+		return strings.ToLower(result.Budgets[i].CategoryName) < strings.ToLower(result.Budgets[j].CategoryName)
+	})
+
+	return result, nil
+}
@@ -0,0 +1,421 @@
+package usecases
+
+import (
+	"Financial/Core/Models/db"
+	dtos "Financial/Core/Models/dtos/Request"
+	response "Financial/Core/Models/dtos/Response"
+	"Financial/Core/ports"
+	"Financial/Core/types"
+	"Financial/Core/validators"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// defaultCategory describes an entry of the tree seeded for every new account
+type defaultCategory struct {
+	name     string
+	kind     types.TransactionType
+	children []string
+}
+
+// defaultCategories is the starting set of categories of a new user
+var defaultCategories = []defaultCategory{
+	{name: "Food", kind: types.Expense, children: []string{"Groceries", "Restaurants"}},
+	{name: "Housing", kind: types.Expense, children: []string{"Rent", "Utilities"}},
+	{name: "Transportation", kind: types.Expense, children: []string{"Fuel", "Public transport"}},
+	{name: "Health", kind: types.Expense},
+	{name: "Entertainment", kind: types.Expense, children: []string{"Subscriptions"}},
+	{name: "Shopping", kind: types.Expense},
+	{name: "Salary", kind: types.Income},
+	{name: "Investments", kind: types.Income},
+	{name: "Other income", kind: types.Income},
+}
+
+// CategoryUseCase implements the CategoryUseCase interface
+type CategoryUseCase struct {
+	repository            ports.Repository[db.Category, int]
+	transactionRepository ports.Repository[db.Transaction, int]
+}
+
+// NewCategoryUseCase creates a new instance of CategoryUseCase
+func NewCategoryUseCase(repo ports.Repository[db.Category, int], transactionRepo ports.Repository[db.Transaction, int]) ports.CategoryUseCase {
+	return &CategoryUseCase{
+		repository:            repo,
+		transactionRepository: transactionRepo,
+	}
+}
+
+// userCategories loads every category of the user indexed by ID
+func (uc *CategoryUseCase) userCategories(userID int) (map[int]db.Category, *response.ErrorResponse) {
+	return categoriesByID(uc.repository, userID)
+}
+
+// categoriesByID loads every category of the user from repo indexed by ID
+func categoriesByID(repo ports.Repository[db.Category, int], userID int) (map[int]db.Category, *response.ErrorResponse) {
+	page, err := repo.Query("*", ports.QueryOptions{
+		Filters: []ports.Filter{
+			{
+				Field:    "user_id",
+				Operator: "eq",
+				Value:    userID,
+			},
+		},
+	})
+	if err != nil {
+		return nil, &response.ErrorResponse{
+			Error: fmt.Errorf("error fetching categories: %w", err).Error(),
+		}
+	}
+
+	categories := page.Items
+
+	byID := make(map[int]db.Category, len(categories))
+	for _, category := range categories {
+		byID[category.ID] = category
+	}
+	return byID, nil
+}
+
+// isDescendant reports whether candidate is ancestor itself or lies below it in the tree
+func isDescendant(categories map[int]db.Category, candidate int, ancestor int) bool {
+	// The walk is bounded by the number of categories in case the stored tree already has a cycle
+	for steps := 0; steps <= len(categories); steps++ {
+		if candidate == ancestor {
+			return true
+		}
+		category, ok := categories[candidate]
+		if !ok || category.ParentID == nil {
+			return false
+		}
+		candidate = *category.ParentID
+	}
+	return true
+}
+
+// nameTaken reports whether a sibling under parentID, other than exceptID, already uses name
+func nameTaken(categories map[int]db.Category, parentID *int, name string, exceptID int) bool {
+	for _, category := range categories {
+		if category.ID == exceptID || !sameParent(category.ParentID, parentID) {
+			continue
+		}
+		if strings.EqualFold(category.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func sameParent(a *int, b *int) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// CreateCategory implements CategoryUseCase.CreateCategory
+func (uc *CategoryUseCase) CreateCategory(request dtos.CreateCategoryRequest) (*db.Category, *response.ErrorResponse) {
+	success, errorsVal := validators.ValidateCategory(request)
+	if !success {
+		return nil, &response.ErrorResponse{
+			Error: strings.Join(*errorsVal, " \n"),
+		}
+	}
+
+	categories, errCategories := uc.userCategories(request.UserID)
+	if errCategories != nil {
+		return nil, errCategories
+	}
+
+	name := strings.TrimSpace(request.Name)
+	if request.ParentID != nil {
+		parent, ok := categories[*request.ParentID]
+		if !ok {
+			return nil, &response.ErrorResponse{
+				Error: errors.New("parent category not found").Error(),
+			}
+		}
+		if parent.Type != request.Type {
+			return nil, &response.ErrorResponse{
+				Error: errors.New("subcategory type must match its parent").Error(),
+			}
+		}
+	}
+
+	if nameTaken(categories, request.ParentID, name, 0) {
+		return nil, &response.ErrorResponse{
+			Error: errors.New("a category with this name already exists at this level").Error(),
+		}
+	}
+
+	result, err := uc.repository.Create(&db.Category{
+		UserID:   request.UserID,
+		ParentID: request.ParentID,
+		Name:     name,
+		Type:     request.Type,
+	})
+	if err != nil {
+		return nil, &response.ErrorResponse{
+			Error: fmt.Errorf("error creating category: %w", err).Error(),
+		}
+	}
+
+	return result, nil
+}
+
+// UpdateCategory implements CategoryUseCase.UpdateCategory
+func (uc *CategoryUseCase) UpdateCategory(request dtos.UpdateCategoryRequest) (*db.Category, *response.ErrorResponse) {
+	success, errorsVal := validators.ValidateUpdateCategory(request)
+	if !success {
+		return nil, &response.ErrorResponse{
+			Error: strings.Join(*errorsVal, " \n"),
+		}
+	}
+
+	categories, errCategories := uc.userCategories(request.UserID)
+	if errCategories != nil {
+		return nil, errCategories
+	}
+
+	category, ok := categories[request.CategoryID]
+	if !ok {
+		return nil, &response.ErrorResponse{
+			Error: errors.New("category not found").Error(),
+		}
+	}
+
+	updated := false
+
+	parentID := category.ParentID
+	if request.MoveToRoot {
+		parentID = nil
+	} else if request.ParentID != nil {
+		parent, ok := categories[*request.ParentID]
+		if !ok {
+			return nil, &response.ErrorResponse{
+				Error: errors.New("parent category not found").Error(),
+			}
+		}
+		if isDescendant(categories, parent.ID, category.ID) {
+			return nil, &response.ErrorResponse{
+				Error: errors.New("a category can't be moved under itself or one of its subcategories").Error(),
+			}
+		}
+		if parent.Type != category.Type {
+			return nil, &response.ErrorResponse{
+				Error: errors.New("subcategory type must match its parent").Error(),
+			}
+		}
+		parentID = &parent.ID
+	}
+	if !sameParent(parentID, category.ParentID) {
+		category.ParentID = parentID
+		updated = true
+	}
+
+	name := strings.TrimSpace(request.Name)
+	if name != "" && name != category.Name {
+		category.Name = name
+		updated = true
+	}
+
+	if !updated {
+		return &category, nil // No changes made
+	}
+
+	if nameTaken(categories, category.ParentID, category.Name, category.ID) {
+		return nil, &response.ErrorResponse{
+			Error: errors.New("a category with this name already exists at this level").Error(),
+		}
+	}
+
+	category.Children = nil
+	result, err := uc.repository.Update(&category)
+	if err != nil {
+		return nil, &response.ErrorResponse{
+			Error: fmt.Errorf("error updating category: %w", err).Error(),
+		}
+	}
+
+	return result, nil
+}
+
+// categoryTransactions lists the transactions tagged with the category
+func (uc *CategoryUseCase) categoryTransactions(categoryID int) ([]db.Transaction, *response.ErrorResponse) {
+	page, err := uc.transactionRepository.Query("*", ports.QueryOptions{
+		Filters: []ports.Filter{
+			{
+				Field:    "category_id",
+				Operator: "eq",
+				Value:    categoryID,
+			},
+		},
+	})
+	if err != nil {
+		return nil, &response.ErrorResponse{
+			Error: fmt.Errorf("error fetching category transactions: %w", err).Error(),
+		}
+	}
+
+	return page.Items, nil
+}
+
+// DeleteCategory implements CategoryUseCase.DeleteCategory
+func (uc *CategoryUseCase) DeleteCategory(userID int, categoryID int, reassignTo *int) *response.ErrorResponse {
+	if categoryID <= 0 {
+		return &response.ErrorResponse{
+			Error: errors.New("invalid category ID").Error(),
+		}
+	}
+
+	categories, errCategories := uc.userCategories(userID)
+	if errCategories != nil {
+		return errCategories
+	}
+
+	category, ok := categories[categoryID]
+	if !ok {
+		return &response.ErrorResponse{
+			Error: errors.New("category not found").Error(),
+		}
+	}
+
+	var children []db.Category
+	for _, c := range categories {
+		if c.ParentID != nil && *c.ParentID == categoryID {
+			children = append(children, c)
+		}
+	}
+
+	transactions, errTransactions := uc.categoryTransactions(categoryID)
+	if errTransactions != nil {
+		return errTransactions
+	}
+
+	if len(children) > 0 || len(transactions) > 0 {
+		if reassignTo == nil {
+			return &response.ErrorResponse{
+				Error: fmt.Errorf("category has %d subcategories and %d transactions; choose a category to reassign them to", len(children), len(transactions)).Error(),
+			}
+		}
+
+		target, ok := categories[*reassignTo]
+		if !ok {
+			return &response.ErrorResponse{
+				Error: errors.New("reassignment category not found").Error(),
+			}
+		}
+		if isDescendant(categories, target.ID, category.ID) {
+			return &response.ErrorResponse{
+				Error: errors.New("reassignment category can't be the deleted category or one of its subcategories").Error(),
+			}
+		}
+		if target.Type != category.Type {
+			return &response.ErrorResponse{
+				Error: errors.New("reassignment category must have the same type").Error(),
+			}
+		}
+
+		for _, child := range children {
+			if nameTaken(categories, &target.ID, child.Name, child.ID) {
+				return &response.ErrorResponse{
+					Error: fmt.Errorf("reassignment category already has a subcategory named %q", child.Name).Error(),
+				}
+			}
+		}
+
+		for i := range children {
+			children[i].ParentID = &target.ID
+			if _, err := uc.repository.Update(&children[i]); err != nil {
+				return &response.ErrorResponse{
+					Error: fmt.Errorf("error reassigning subcategory: %w", err).Error(),
+				}
+			}
+		}
+		for i := range transactions {
+			transactions[i].CategoryID = &target.ID
+			if _, err := uc.transactionRepository.Update(&transactions[i]); err != nil {
+				return &response.ErrorResponse{
+					Error: fmt.Errorf("error reassigning transaction: %w", err).Error(),
+				}
+			}
+		}
+	}
+
+	if err := uc.repository.Delete(categoryID); err != nil {
+		return &response.ErrorResponse{
+			Error: fmt.Errorf("error deleting category: %w", err).Error(),
+		}
+	}
+
+	return nil
+}
+
+// GetUserCategories implements CategoryUseCase.GetUserCategories
+func (uc *CategoryUseCase) GetUserCategories(userID int) ([]db.Category, *response.ErrorResponse) {
+	if userID <= 0 {
+		return nil, &response.ErrorResponse{
+			Error: errors.New("invalid user ID").Error(),
+		}
+	}
+
+	categories, errCategories := uc.userCategories(userID)
+	if errCategories != nil {
+		return nil, errCategories
+	}
+
+	childrenOf := map[int][]db.Category{}
+	roots := []db.Category{}
+	for _, category := range categories {
+		// Orphans (parent of another user or missing) are shown as roots rather than hidden
+		if category.ParentID == nil || categories[*category.ParentID].ID == 0 {
+			roots = append(roots, category)
+			continue
+		}
+		childrenOf[*category.ParentID] = append(childrenOf[*category.ParentID], category)
+	}
+
+	var build func(nodes []db.Category, depth int) []db.Category
+	build = func(nodes []db.Category, depth int) []db.Category {
+		sort.Slice(nodes, func(i, j int) bool {
+			return strings.ToLower(nodes[i].Name) < strings.ToLower(nodes[j].Name)
+		})
+		for i := range nodes {
+			if depth < len(categories) {
+				nodes[i].Children = build(childrenOf[nodes[i].ID], depth+1)
+			}
+		}
+		return nodes
+	}
+
+	return build(roots, 0), nil
+}
+
+// SeedDefaultCategories implements CategoryUseCase.SeedDefaultCategories
+func (uc *CategoryUseCase) SeedDefaultCategories(userID int) *response.ErrorResponse {
+	if userID <= 0 {
+		return &response.ErrorResponse{
+			Error: errors.New("invalid user ID").Error(),
+		}
+	}
+
+	for _, def := range defaultCategories {
+		parent, err := uc.repository.Create(&db.Category{UserID: userID, Name: def.name, Type: def.kind})
+		if err != nil {
+			return &response.ErrorResponse{
+				Error: fmt.Errorf("error creating default category %q: %w", def.name, err).Error(),
+			}
+		}
+		for _, child := range def.children {
+			parentID := parent.ID
+			if _, err := uc.repository.Create(&db.Category{UserID: userID, ParentID: &parentID, Name: child, Type: def.kind}); err != nil {
+				return &response.ErrorResponse{
+					Error: fmt.Errorf("error creating default category %q: %w", child, err).Error(),
+				}
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,132 @@
+package usecases
+
+import (
+	"Financial/Core/Models/db"
+	dtos "Financial/Core/Models/dtos/Request"
+	response "Financial/Core/Models/dtos/Response"
+	"Financial/Core/exporters"
+	"Financial/Core/ports"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ExportUseCase implements the ExportUseCase interface
+type ExportUseCase struct {
+	walletRepository      ports.Repository[db.Wallet, int]
+	transactionRepository ports.Repository[db.Transaction, int]
+}
+
+// NewExportUseCase creates a new instance of ExportUseCase
+func NewExportUseCase(walletRepo ports.Repository[db.Wallet, int], transactionRepo ports.Repository[db.Transaction, int]) ports.ExportUseCase {
+	return &ExportUseCase{
+		walletRepository:      walletRepo,
+		transactionRepository: transactionRepo,
+	}
+}
+
+// Export implements ExportUseCase.Export
+func (uc *ExportUseCase) Export(request dtos.ExportRequest, w io.Writer) *response.ErrorResponse {
+	format, err := exporters.LookupFormat(request.Format)
+	if err != nil {
+		return &response.ErrorResponse{
+			Error: err.Error(),
+		}
+	}
+	if request.From != nil && request.To != nil && request.To.Before(*request.From) {
+		return &response.ErrorResponse{
+			Error: errors.New("the end of the range can't be before its start").Error(),
+		}
+	}
+
+	page, err := uc.walletRepository.Query("*", ports.QueryOptions{
+		Filters: []ports.Filter{
+			{
+				Field:    "user_id",
+				Operator: "eq",
+				Value:    request.UserID,
+			},
+		},
+		OrderBy: []ports.OrderBy{
+			{
+				Field:     "id",
+				Ascending: true,
+			},
+		},
+	})
+	if err != nil {
+		return &response.ErrorResponse{
+			Error: fmt.Errorf("error fetching wallets: %w", err).Error(),
+		}
+	}
+	wallets := page.Items
+
+	writer := exporters.NewWriter(format, w)
+	if err := writer.Begin(); err != nil {
+		return exportError(err)
+	}
+
+	// Movements are fetched one wallet at a time so the export never holds the whole ledger
+	for _, wallet := range wallets {
+		if err := writer.Wallet(wallet); err != nil {
+			return exportError(err)
+		}
+
+		transactions, errTransactions := uc.walletTransactions(wallet.ID, request.From, request.To)
+		if errTransactions != nil {
+			return errTransactions
+		}
+		for _, transaction := range transactions {
+			if err := writer.Transaction(wallet, transaction); err != nil {
+				return exportError(err)
+			}
+		}
+	}
+
+	if err := writer.End(); err != nil {
+		return exportError(err)
+	}
+	return nil
+}
+
+// walletTransactions lists the movements of a wallet in the range of days, oldest first
+func (uc *ExportUseCase) walletTransactions(walletID int, from *time.Time, to *time.Time) ([]db.Transaction, *response.ErrorResponse) {
+	filters := []ports.Filter{
+		{
+			Field:    "wallet_id",
+			Operator: "eq",
+			Value:    walletID,
+		},
+	}
+	if from != nil {
+		start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+		filters = append(filters, ports.Filter{Field: "created_at", Operator: "gte", Value: start.Format(time.RFC3339)})
+	}
+	if to != nil {
+		end := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1)
+		filters = append(filters, ports.Filter{Field: "created_at", Operator: "lt", Value: end.Format(time.RFC3339)})
+	}
+
+	page, err := uc.transactionRepository.Query("*", ports.QueryOptions{
+		Filters: filters,
+		OrderBy: []ports.OrderBy{
+			{
+				Field:     "created_at",
+				Ascending: true,
+			},
+		},
+	})
+	if err != nil {
+		return nil, &response.ErrorResponse{
+			Error: fmt.Errorf("error fetching wallet transactions: %w", err).Error(),
+		}
+	}
+	return page.Items, nil
+}
+
+func exportError(err error) *response.ErrorResponse {
+	return &response.ErrorResponse{
+		Error: fmt.Errorf("error writing export: %w", err).Error(),
+	}
+}
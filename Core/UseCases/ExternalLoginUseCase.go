@@ -0,0 +1,286 @@
+package usecases
+
+import (
+	"Financial/Core/Models/db"
+	"Financial/Core/oidc"
+	"Financial/Core/ports"
+	"Financial/Core/types"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// externalLoginTTL is how long the user has to come back from the provider
+const externalLoginTTL = 10 * time.Minute
+
+// nickUnsafeChars are removed from the email local part to build a nickname
+var nickUnsafeChars = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
+
+// ExternalLoginUseCase implements the ExternalLoginUseCase interface
+type ExternalLoginUseCase struct {
+	provider       ports.IdentityProvider
+	identities     ports.Repository[db.UserIdentity, int]
+	logins         ports.Repository[db.OIDCLogin, int]
+	userRepository ports.Repository[db.User, int]
+	categories     ports.CategoryUseCase
+	hasher         ports.PasswordHasher
+	clock          ports.Clock
+	autoProvision  bool
+}
+
+// NewExternalLoginUseCase creates a new instance of ExternalLoginUseCase.
+// When autoProvision is set, users with a verified email and no account get one.
+func NewExternalLoginUseCase(provider ports.IdentityProvider, identities ports.Repository[db.UserIdentity, int], logins ports.Repository[db.OIDCLogin, int], userRepo ports.Repository[db.User, int], categories ports.CategoryUseCase, hasher ports.PasswordHasher, clock ports.Clock, autoProvision bool) ports.ExternalLoginUseCase {
+	return &ExternalLoginUseCase{
+		provider:       provider,
+		identities:     identities,
+		logins:         logins,
+		userRepository: userRepo,
+		categories:     categories,
+		hasher:         hasher,
+		clock:          clock,
+		autoProvision:  autoProvision,
+	}
+}
+
+// StartLogin implements ExternalLoginUseCase.StartLogin
+func (uc *ExternalLoginUseCase) StartLogin() (string, error) {
+	state, err := oidc.NewState()
+	if err != nil {
+		return "", fmt.Errorf("error generating state: %w", err)
+	}
+	nonce, err := oidc.NewState()
+	if err != nil {
+		return "", fmt.Errorf("error generating nonce: %w", err)
+	}
+	verifier, err := oidc.NewCodeVerifier()
+	if err != nil {
+		return "", fmt.Errorf("error generating code verifier: %w", err)
+	}
+
+	now := uc.clock.Now().UTC()
+	if _, err := uc.logins.Create(&db.OIDCLogin{
+		StateHash:    hashToken(state),
+		CodeVerifier: verifier,
+		Nonce:        nonce,
+		CreatedAt:    now,
+		ExpiresAt:    now.Add(externalLoginTTL),
+	}); err != nil {
+		return "", fmt.Errorf("error storing login: %w", err)
+	}
+
+	return uc.provider.AuthCodeURL(state, nonce, oidc.CodeChallenge(verifier)), nil
+}
+
+// CompleteLogin implements ExternalLoginUseCase.CompleteLogin
+func (uc *ExternalLoginUseCase) CompleteLogin(state string, code string) (*int, error) {
+	if state == "" || code == "" {
+		return nil, types.ErrExternalLoginFailed
+	}
+
+	login, err := uc.logins.FindByField("state_hash", hashToken(state))
+	if err == types.ErrNotFound {
+		return nil, types.ErrExternalLoginFailed
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching login: %w", err)
+	}
+	// The state is spent whatever happens next, so a callback can't be replayed
+	if err := uc.logins.Delete(login.ID); err != nil {
+		return nil, fmt.Errorf("error consuming login: %w", err)
+	}
+	if !uc.clock.Now().UTC().Before(login.ExpiresAt) {
+		return nil, types.ErrExternalLoginFailed
+	}
+
+	identity, err := uc.provider.Identify(code, login.CodeVerifier, login.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", types.ErrExternalLoginFailed, err)
+	}
+
+	user, err := uc.resolveAccount(identity)
+	if err != nil {
+		return nil, err
+	}
+	if user.Status == types.Suspend {
+		return nil, types.ErrAccountSuspended
+	}
+	return &user.ID, nil
+}
+
+// resolveAccount finds the account of an identity: through an existing link, by verified
+// email, or by creating one when provisioning is allowed
+func (uc *ExternalLoginUseCase) resolveAccount(identity *ports.ExternalIdentity) (*db.User, error) {
+	now := uc.clock.Now().UTC()
+
+	link, err := uc.findLink(identity)
+	if err != nil {
+		return nil, err
+	}
+	if link != nil {
+		user, err := uc.userRepository.GetByID(link.UserID)
+		if err == types.ErrNotFound {
+			return nil, types.ErrAccountNotLinked
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error fetching account: %w", err)
+		}
+		link.LastLoginAt = &now
+		if _, err := uc.identities.Update(link); err != nil {
+			return nil, fmt.Errorf("error updating identity: %w", err)
+		}
+		return user, nil
+	}
+
+	// An unverified email could belong to anyone; it never links or creates an account
+	email := strings.ToLower(strings.TrimSpace(identity.Email))
+	if email == "" || !identity.EmailVerified {
+		return nil, types.ErrAccountNotLinked
+	}
+
+	user, err := uc.userRepository.FindByField("email", email)
+	switch {
+	case err == nil:
+		if user, err = uc.claimAccount(user); err != nil {
+			return nil, err
+		}
+	case err == types.ErrNotFound && uc.autoProvision:
+		if user, err = uc.provision(identity, email); err != nil {
+			return nil, err
+		}
+	case err == types.ErrNotFound:
+		return nil, types.ErrAccountNotLinked
+	default:
+		return nil, fmt.Errorf("error fetching account: %w", err)
+	}
+
+	if _, err := uc.identities.Create(&db.UserIdentity{
+		UserID:      user.ID,
+		Issuer:      identity.Issuer,
+		Subject:     identity.Subject,
+		Email:       email,
+		CreatedAt:   now,
+		LastLoginAt: &now,
+	}); err != nil {
+		return nil, fmt.Errorf("error linking identity: %w", err)
+	}
+	return user, nil
+}
+
+// findLink returns the link of the identity, or nil when it was never used here
+func (uc *ExternalLoginUseCase) findLink(identity *ports.ExternalIdentity) (*db.UserIdentity, error) {
+	page, err := uc.identities.Query("*", ports.QueryOptions{
+		Filters: []ports.Filter{
+			{Field: "issuer", Operator: "eq", Value: identity.Issuer},
+			{Field: "subject", Operator: "eq", Value: identity.Subject},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error fetching identity: %w", err)
+	}
+
+	links := page.Items
+	for i := range links {
+		if links[i].Issuer == identity.Issuer && links[i].Subject == identity.Subject {
+			return &links[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// claimAccount prepares an existing account for its first login through the provider.
+// An account still awaiting verification may have been registered by someone else with
+// this email, so its password is replaced before the provider's verification activates it.
+func (uc *ExternalLoginUseCase) claimAccount(user *db.User) (*db.User, error) {
+	if !awaitingVerification(user.Status) {
+		return user, nil
+	}
+
+	password, err := uc.unusablePassword()
+	if err != nil {
+		return nil, err
+	}
+	user.Password = password
+	user.Status = types.Active
+	updated, err := uc.userRepository.Update(user)
+	if err != nil {
+		return nil, fmt.Errorf("error activating account: %w", err)
+	}
+	return updated, nil
+}
+
+// provision creates an active account for a user seen for the first time. It gets a random
+// password nobody knows; the user can set one through password recovery.
+func (uc *ExternalLoginUseCase) provision(identity *ports.ExternalIdentity, email string) (*db.User, error) {
+	nick, err := uc.availableNick(email)
+	if err != nil {
+		return nil, err
+	}
+	password, err := uc.unusablePassword()
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := uc.userRepository.Create(&db.User{
+		Nickname:  nick,
+		FirstName: identity.GivenName,
+		Lastname:  identity.FamilyName,
+		Email:     email,
+		Status:    types.Active,
+		Role:      types.RoleUser,
+		CreatedAt: uc.clock.Now().UTC(),
+		Password:  password,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating account: %w", err)
+	}
+
+	// Las cuentas nuevas empiezan con el árbol de categorías por defecto
+	if errSeed := uc.categories.SeedDefaultCategories(created.ID); errSeed != nil {
+		_ = uc.userRepository.Delete(created.ID)
+		return nil, fmt.Errorf("error creating account: %s", errSeed.Error)
+	}
+	return created, nil
+}
+
+// availableNick derives a free nickname from the local part of the email
+func (uc *ExternalLoginUseCase) availableNick(email string) (string, error) {
+	base := nickUnsafeChars.ReplaceAllString(strings.SplitN(email, "@", 2)[0], "")
+	if base == "" {
+		base = "user"
+	}
+
+	for attempt := 1; attempt <= 10; attempt++ {
+		nick := base
+		if attempt > 1 {
+			nick = fmt.Sprintf("%s%d", base, attempt)
+		}
+		_, err := uc.userRepository.FindByField("nick_name", nick)
+		if err == types.ErrNotFound {
+			return nick, nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("error checking nickname: %w", err)
+		}
+	}
+
+	suffix, err := randomToken()
+	if err != nil {
+		return "", fmt.Errorf("error generating nickname: %w", err)
+	}
+	return base + "-" + strings.ToLower(suffix[:8]), nil
+}
+
+// unusablePassword hashes a random password that is never shown to anyone
+func (uc *ExternalLoginUseCase) unusablePassword() (string, error) {
+	secret, err := randomToken()
+	if err != nil {
+		return "", fmt.Errorf("error generating password: %w", err)
+	}
+	hash, err := uc.hasher.Hash(secret)
+	if err != nil {
+		return "", fmt.Errorf("error hashing password: %w", err)
+	}
+	return hash, nil
+}
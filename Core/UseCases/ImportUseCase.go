@@ -0,0 +1,210 @@
+package usecases
+
+import (
+	"Financial/Core/Models/db"
+	dtos "Financial/Core/Models/dtos/Request"
+	response "Financial/Core/Models/dtos/Response"
+	"Financial/Core/importers"
+	"Financial/Core/ports"
+	"Financial/Core/types"
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ImportUseCase implements the ImportUseCase interface
+type ImportUseCase struct {
+	transactionRepository ports.Repository[db.Transaction, int]
+	walletRepository      ports.Repository[db.Wallet, int]
+	transactions          ports.TransactionUseCase
+}
+
+// NewImportUseCase creates a new instance of ImportUseCase.
+// Movements are recorded through the TransactionUseCase so they update the wallet balance.
+func NewImportUseCase(transactionRepo ports.Repository[db.Transaction, int], walletRepo ports.Repository[db.Wallet, int], transactions ports.TransactionUseCase) ports.ImportUseCase {
+	return &ImportUseCase{
+		transactionRepository: transactionRepo,
+		walletRepository:      walletRepo,
+		transactions:          transactions,
+	}
+}
+
+// PreviewImport implements ImportUseCase.PreviewImport
+func (uc *ImportUseCase) PreviewImport(request dtos.ImportStatementRequest) (*response.ImportPreviewResponse, *response.ErrorResponse) {
+	return uc.preview(request)
+}
+
+// CommitImport implements ImportUseCase.CommitImport
+func (uc *ImportUseCase) CommitImport(request dtos.ImportStatementRequest) (*response.ImportResultResponse, *response.ErrorResponse) {
+	// The preview is computed again so the duplicates are checked against the current ledger
+	preview, errPreview := uc.preview(request)
+	if errPreview != nil {
+		return nil, errPreview
+	}
+
+	result := &response.ImportResultResponse{
+		Duplicates:   preview.Duplicates,
+		Transactions: []db.Transaction{},
+	}
+	for _, line := range preview.Lines {
+		if line.Duplicate {
+			continue
+		}
+
+		postedAt := line.Date
+		transaction, errTransaction := uc.transactions.RecordTransaction(request.UserID, dtos.CreateTransactionRequest{
+			WalletID:    request.WalletID,
+			Type:        line.Type,
+			Amount:      line.Amount,
+			Description: line.Description,
+			PostedAt:    &postedAt,
+		})
+		if errTransaction != nil {
+			result.Failed = append(result.Failed, response.ImportLineFailure{
+				Line:  line.Line,
+				Error: errTransaction.Error,
+			})
+			continue
+		}
+		result.Transactions = append(result.Transactions, *transaction)
+	}
+	result.Imported = len(result.Transactions)
+
+	return result, nil
+}
+
+// preview parses the statement and flags the movements the wallet already has
+func (uc *ImportUseCase) preview(request dtos.ImportStatementRequest) (*response.ImportPreviewResponse, *response.ErrorResponse) {
+	if request.WalletID <= 0 {
+		return nil, &response.ErrorResponse{
+			Error: errors.New("invalid wallet ID").Error(),
+		}
+	}
+
+	wallet, err := uc.walletRepository.GetByID(request.WalletID)
+	// Wallets of other users are reported as missing
+	if err != nil || wallet.UserID != request.UserID {
+		if err == nil || err == types.ErrNotFound {
+			return nil, &response.ErrorResponse{
+				Error: errors.New("wallet not found").Error(),
+			}
+		}
+		return nil, &response.ErrorResponse{
+			Error: fmt.Errorf("error fetching wallet: %w", err).Error(),
+		}
+	}
+
+	if len(request.Content) == 0 {
+		return nil, &response.ErrorResponse{
+			Error: errors.New("the statement file is empty").Error(),
+		}
+	}
+
+	parser, err := importers.NewParser(request.Format, request.Mapping)
+	if err != nil {
+		return nil, &response.ErrorResponse{
+			Error: err.Error(),
+		}
+	}
+	lines, err := parser.Parse(bytes.NewReader(request.Content))
+	if err != nil {
+		return nil, &response.ErrorResponse{
+			Error: fmt.Errorf("error reading statement: %w", err).Error(),
+		}
+	}
+
+	currency := wallet.Currency
+	if currency == "" {
+		currency = types.DefaultCurrency
+	}
+
+	result := &response.ImportPreviewResponse{
+		WalletID: request.WalletID,
+		Format:   strings.ToLower(request.Format),
+		Lines:    []response.ImportLine{},
+	}
+
+	// Lines without amount can't be recorded
+	movements := []importers.Line{}
+	for _, line := range lines {
+		if !line.Amount.IsZero() {
+			movements = append(movements, line)
+		}
+	}
+	if len(movements) == 0 {
+		return result, nil
+	}
+
+	existing, errExisting := uc.existingFingerprints(request.WalletID, movements)
+	if errExisting != nil {
+		return nil, errExisting
+	}
+
+	for _, line := range movements {
+		amount := line.Amount.WithCurrency(currency)
+		fingerprint := importers.Fingerprint(line.Date, amount, line.Description)
+
+		// Every movement on the ledger matches a single line, so repeated movements
+		// on the same day (two identical coffees) are only skipped as often as recorded
+		duplicate := existing[fingerprint] > 0
+		if duplicate {
+			existing[fingerprint]--
+			result.Duplicates++
+		} else {
+			result.New++
+		}
+
+		transactionType := types.Income
+		if amount.IsNegative() {
+			transactionType = types.Expense
+		}
+		result.Lines = append(result.Lines, response.ImportLine{
+			Line:        line.Number,
+			Date:        line.Date,
+			Type:        transactionType,
+			Amount:      amount.Abs(),
+			Description: line.Description,
+			Fingerprint: fingerprint,
+			Duplicate:   duplicate,
+		})
+	}
+
+	return result, nil
+}
+
+// existingFingerprints counts the fingerprints of the wallet movements on the days covered by the lines
+func (uc *ImportUseCase) existingFingerprints(walletID int, lines []importers.Line) (map[string]int, *response.ErrorResponse) {
+	from, to := lines[0].Date, lines[0].Date
+	for _, line := range lines {
+		if line.Date.Before(from) {
+			from = line.Date
+		}
+		if line.Date.After(to) {
+			to = line.Date
+		}
+	}
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	to = time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1)
+
+	page, err := uc.transactionRepository.Query("*", ports.QueryOptions{
+		Filters: []ports.Filter{
+			{Field: "wallet_id", Operator: "eq", Value: walletID},
+			{Field: "created_at", Operator: "gte", Value: from.Format(time.RFC3339)},
+			{Field: "created_at", Operator: "lt", Value: to.Format(time.RFC3339)},
+		},
+	})
+	if err != nil {
+		return nil, &response.ErrorResponse{
+			Error: fmt.Errorf("error fetching wallet transactions: %w", err).Error(),
+		}
+	}
+	transactions := page.Items
+
+	fingerprints := map[string]int{}
+	for _, transaction := range transactions {
+		fingerprints[importers.Fingerprint(transaction.CreatedAt.UTC(), transaction.SignedAmount(), transaction.Description)]++
+	}
+	return fingerprints, nil
+}
@@ -0,0 +1,353 @@
+package usecases
+
+import (
+	"Financial/Core/Models/db"
+	dtos "Financial/Core/Models/dtos/Request"
+	"Financial/Core/ports"
+	"Financial/Core/types"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LoginThrottleConfig sets how the login throttle reacts to failed logins
+type LoginThrottleConfig struct {
+	// FreeAttempts is how many failures of an account go by before its logins have to wait
+	FreeAttempts int
+
+	// IPFreeAttempts is the same for a client address; higher, since an address can be shared
+	IPFreeAttempts int
+
+	// BaseDelay is the wait after the first failure beyond the free ones; it doubles with each failure
+	BaseDelay time.Duration
+
+	// MaxDelay caps the wait between attempts
+	MaxDelay time.Duration
+
+	// LockoutThreshold is the number of failures that suspends an account
+	LockoutThreshold int
+
+	// LockoutDuration is how long the account stays suspended
+	LockoutDuration time.Duration
+
+	// Window is how long failures are remembered after the last one
+	Window time.Duration
+
+	// ResetRequests is how many password resets an email address may request within a Window
+	ResetRequests int
+
+	// IPResetRequests is how many password resets a client address may request within a Window
+	IPResetRequests int
+}
+
+// DefaultLoginThrottleConfig waits from the fourth failure on, starting at one second, and locks
+// an account out for half an hour at its tenth failure. An email address gets three reset
+// emails an hour, and a client address can ask for ten
+var DefaultLoginThrottleConfig = LoginThrottleConfig{
+	FreeAttempts:     3,
+	IPFreeAttempts:   20,
+	BaseDelay:        time.Second,
+	MaxDelay:         15 * time.Minute,
+	LockoutThreshold: 10,
+	LockoutDuration:  30 * time.Minute,
+	Window:           time.Hour,
+	ResetRequests:    3,
+	IPResetRequests:  10,
+}
+
+// LoginThrottleUseCase implements the LoginThrottle interface
+type LoginThrottleUseCase struct {
+	attempts ports.LoginAttemptStore
+	users    ports.Repository[db.User, int]
+	audit    ports.Repository[db.AuditEntry, int]
+	clock    ports.Clock
+	config   LoginThrottleConfig
+}
+
+// NewLoginThrottleUseCase creates a new instance of LoginThrottleUseCase.
+// Lockouts are written to audit.
+func NewLoginThrottleUseCase(attempts ports.LoginAttemptStore, users ports.Repository[db.User, int], audit ports.Repository[db.AuditEntry, int], clock ports.Clock, config LoginThrottleConfig) ports.LoginThrottle {
+	return &LoginThrottleUseCase{
+		attempts: attempts,
+		users:    users,
+		audit:    audit,
+		clock:    clock,
+		config:   config,
+	}
+}
+
+// Allow implements LoginThrottle.Allow
+func (uc *LoginThrottleUseCase) Allow(auth dtos.AuthRequest, ip string) (time.Duration, error) {
+	now := uc.clock.Now()
+
+	user, key, err := uc.account(auth)
+	if err != nil {
+		return 0, err
+	}
+	return uc.allow(user, key, ip, now)
+}
+
+// LoginFailed implements LoginThrottle.LoginFailed
+func (uc *LoginThrottleUseCase) LoginFailed(auth dtos.AuthRequest, ip string) error {
+	now := uc.clock.Now()
+
+	user, key, err := uc.account(auth)
+	if err != nil {
+		return err
+	}
+	return uc.failed(user, key, ip, now)
+}
+
+// AllowSecondFactor implements LoginThrottle.AllowSecondFactor
+func (uc *LoginThrottleUseCase) AllowSecondFactor(userID int, ip string) (time.Duration, error) {
+	now := uc.clock.Now()
+
+	user, err := uc.user(userID)
+	if err != nil {
+		return 0, err
+	}
+	return uc.allow(user, accountKey(userID), ip, now)
+}
+
+// SecondFactorFailed implements LoginThrottle.SecondFactorFailed
+func (uc *LoginThrottleUseCase) SecondFactorFailed(userID int, ip string) error {
+	user, err := uc.user(userID)
+	if err != nil {
+		return err
+	}
+	return uc.failed(user, accountKey(userID), ip, uc.clock.Now())
+}
+
+// ClaimChallenge implements LoginThrottle.ClaimChallenge. The claim is counted in the attempt
+// store, whose atomic count lets only one of several instances see it as the first.
+func (uc *LoginThrottleUseCase) ClaimChallenge(challengeID string, ttl time.Duration) (bool, error) {
+	claim, err := uc.attempts.RecordFailure(challengeKey(challengeID), uc.clock.Now(), ttl)
+	if err != nil {
+		return false, fmt.Errorf("error claiming two-factor challenge: %w", err)
+	}
+	return claim.Failures == 1, nil
+}
+
+// AllowPasswordReset implements LoginThrottle.AllowPasswordReset. Every request is counted,
+// refused ones included, so a client that keeps asking keeps waiting.
+func (uc *LoginThrottleUseCase) AllowPasswordReset(email string, ip string) (time.Duration, error) {
+	now := uc.clock.Now()
+
+	emailRequests, err := uc.attempts.RecordFailure(resetKey(email), now, uc.config.Window)
+	if err != nil {
+		return 0, fmt.Errorf("error recording password reset request: %w", err)
+	}
+	ipRequests, err := uc.attempts.RecordFailure(resetIPKey(ip), now, uc.config.Window)
+	if err != nil {
+		return 0, fmt.Errorf("error recording password reset request: %w", err)
+	}
+
+	if emailRequests.Failures > uc.config.ResetRequests || ipRequests.Failures > uc.config.IPResetRequests {
+		return uc.config.Window, types.ErrTooManyAttempts
+	}
+	return 0, nil
+}
+
+// allow is how long the account and the address still have to wait, the longer of the two
+func (uc *LoginThrottleUseCase) allow(user *db.User, key string, ip string, now time.Time) (time.Duration, error) {
+	accountWait, err := uc.accountWait(user, key, ip, now)
+	if err != nil {
+		return 0, err
+	}
+	ipWait, err := uc.wait(ipKey(ip), uc.config.IPFreeAttempts, now)
+	if err != nil {
+		return 0, err
+	}
+
+	if wait := max(accountWait, ipWait); wait > 0 {
+		return wait, types.ErrTooManyAttempts
+	}
+	return 0, nil
+}
+
+// failed counts a failure of the account and the address, locking the account at the threshold
+func (uc *LoginThrottleUseCase) failed(user *db.User, key string, ip string, now time.Time) error {
+	attempt, err := uc.attempts.RecordFailure(key, now, uc.config.Window)
+	if err != nil {
+		return fmt.Errorf("error recording failed login: %w", err)
+	}
+	if _, err := uc.attempts.RecordFailure(ipKey(ip), now, uc.config.Window); err != nil {
+		return fmt.Errorf("error recording failed login: %w", err)
+	}
+
+	// Only the failure that reaches the threshold locks, so instances sharing the store lock once
+	if attempt.Failures == uc.config.LockoutThreshold {
+		return uc.lock(user, key, attempt.Failures, ip, now)
+	}
+	return nil
+}
+
+// LoginSucceeded implements LoginThrottle.LoginSucceeded
+func (uc *LoginThrottleUseCase) LoginSucceeded(userID int) error {
+	return uc.attempts.Reset(accountKey(userID))
+}
+
+// Release implements LoginThrottle.Release
+func (uc *LoginThrottleUseCase) Release(userID int) error {
+	return uc.attempts.Reset(accountKey(userID))
+}
+
+// account finds the account a login is for and the key its failures are counted under.
+// Unknown accounts are counted by the identifier used, so they are throttled the same way.
+func (uc *LoginThrottleUseCase) account(auth dtos.AuthRequest) (*db.User, string, error) {
+	field, identifier := "email", auth.Email
+	if identifier == "" {
+		field, identifier = "nick_name", auth.Nickname
+	}
+
+	user, err := uc.users.FindByField(field, identifier)
+	if err == types.ErrNotFound {
+		return nil, "login:" + strings.ToLower(identifier), nil
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("error fetching account: %w", err)
+	}
+	return user, accountKey(user.ID), nil
+}
+
+// user fetches the account a two-factor code is for; a deleted account is counted all the same
+func (uc *LoginThrottleUseCase) user(userID int) (*db.User, error) {
+	user, err := uc.users.GetByID(userID)
+	if err == types.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching account: %w", err)
+	}
+	return user, nil
+}
+
+// accountWait is how long the account still has to wait; an expired lockout is ended here
+func (uc *LoginThrottleUseCase) accountWait(user *db.User, key string, ip string, now time.Time) (time.Duration, error) {
+	attempt, err := uc.attempts.Get(key)
+	if err == types.ErrNotFound {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("error reading failed logins: %w", err)
+	}
+
+	if attempt.LockedUntil != nil {
+		if now.Before(*attempt.LockedUntil) {
+			return attempt.LockedUntil.Sub(now), nil
+		}
+		return 0, uc.unlock(user, key, attempt, ip, now)
+	}
+	return uc.delay(attempt, uc.config.FreeAttempts, now), nil
+}
+
+// wait is how long a key still has to wait after its last failure
+func (uc *LoginThrottleUseCase) wait(key string, freeAttempts int, now time.Time) (time.Duration, error) {
+	attempt, err := uc.attempts.Get(key)
+	if err == types.ErrNotFound {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("error reading failed logins: %w", err)
+	}
+	return uc.delay(attempt, freeAttempts, now), nil
+}
+
+// delay applies the exponential backoff: BaseDelay after the first failure beyond the free
+// ones, twice as much after each further failure, and never more than MaxDelay
+func (uc *LoginThrottleUseCase) delay(attempt *db.LoginAttempt, freeAttempts int, now time.Time) time.Duration {
+	if attempt.Failures <= freeAttempts || now.Sub(attempt.LastFailureAt) >= uc.config.Window {
+		return 0
+	}
+
+	backoff := uc.config.BaseDelay
+	for i := freeAttempts + 1; i < attempt.Failures && backoff < uc.config.MaxDelay; i++ {
+		backoff *= 2
+	}
+	backoff = min(backoff, uc.config.MaxDelay)
+
+	return max(attempt.LastFailureAt.Add(backoff).Sub(now), 0)
+}
+
+// lock suspends the account until the lockout ends and writes it to the audit log.
+// Accounts an administrator already suspended stay suspended when the lockout ends.
+func (uc *LoginThrottleUseCase) lock(user *db.User, key string, failures int, ip string, now time.Time) error {
+	until := now.Add(uc.config.LockoutDuration)
+
+	var previous types.AccountStatus
+	if user != nil && user.Status != types.Suspend {
+		previous = user.Status
+	}
+	if err := uc.attempts.Lock(key, until, previous); err != nil {
+		return fmt.Errorf("error locking account: %w", err)
+	}
+
+	entry := &db.AuditEntry{
+		Action:    types.AuditAccountLocked,
+		Detail:    fmt.Sprintf("%d failed logins; locked until %s", failures, until.UTC().Format(time.RFC3339)),
+		IP:        ip,
+		CreatedAt: now,
+	}
+	if user != nil {
+		entry.UserID = &user.ID
+		if previous != "" {
+			user.Status = types.Suspend
+			if _, err := uc.users.Update(user); err != nil {
+				return fmt.Errorf("error suspending account: %w", err)
+			}
+		}
+	} else {
+		entry.Detail = fmt.Sprintf("%s for unknown account %q", entry.Detail, strings.TrimPrefix(key, "login:"))
+	}
+
+	if _, err := uc.audit.Create(entry); err != nil {
+		return fmt.Errorf("error writing audit log: %w", err)
+	}
+	return nil
+}
+
+// unlock gives the account back the status it had before the lockout and forgets its failures
+func (uc *LoginThrottleUseCase) unlock(user *db.User, key string, attempt *db.LoginAttempt, ip string, now time.Time) error {
+	if user != nil && user.Status == types.Suspend && attempt.PreviousStatus != "" {
+		user.Status = attempt.PreviousStatus
+		if _, err := uc.users.Update(user); err != nil {
+			return fmt.Errorf("error restoring account: %w", err)
+		}
+		if _, err := uc.audit.Create(&db.AuditEntry{
+			UserID:    &user.ID,
+			Action:    types.AuditAccountUnlocked,
+			Detail:    fmt.Sprintf("lockout over; status restored to %s", attempt.PreviousStatus),
+			IP:        ip,
+			CreatedAt: now,
+		}); err != nil {
+			return fmt.Errorf("error writing audit log: %w", err)
+		}
+	}
+	return uc.attempts.Reset(key)
+}
+
+// accountKey is the key the failures of an account are counted under
+func accountKey(userID int) string {
+	return "user:" + strconv.Itoa(userID)
+}
+
+// ipKey is the key the failures of a client address are counted under
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
+
+// resetKey is the key the password reset requests for an email address are counted under
+func resetKey(email string) string {
+	return "reset:" + strings.ToLower(email)
+}
+
+// resetIPKey is the key the password reset requests of a client address are counted under
+func resetIPKey(ip string) string {
+	return "reset-ip:" + ip
+}
+
+// challengeKey is the key the uses of a two-factor challenge are counted under
+func challengeKey(challengeID string) string {
+	return "challenge:" + challengeID
+}
@@ -0,0 +1,63 @@
+package usecases
+
+import (
+	dtos "Financial/Core/Models/dtos/Request"
+	response "Financial/Core/Models/dtos/Response"
+	"Financial/Core/ports"
+	"context"
+	"log"
+	"sync"
+)
+
+// PasswordResetQueue is a PasswordResetUseCase that sends the reset emails in the background.
+// Requests wait in a queue of fixed size for a fixed number of workers, so a burst of
+// forgot-password requests can't start an unbounded number of lookups and emails.
+type PasswordResetQueue struct {
+	resets   ports.PasswordResetUseCase
+	requests chan string
+}
+
+// NewPasswordResetQueue creates a queue that holds up to size requests for resets.
+// Nothing is sent until Start runs.
+func NewPasswordResetQueue(resets ports.PasswordResetUseCase, size int) *PasswordResetQueue {
+	return &PasswordResetQueue{
+		resets:   resets,
+		requests: make(chan string, size),
+	}
+}
+
+// RequestReset implements PasswordResetUseCase.RequestReset. It doesn't wait for the lookup
+// and the email, so its timing reveals nothing; a request that finds the queue full is dropped.
+func (q *PasswordResetQueue) RequestReset(email string) {
+	select {
+	case q.requests <- email:
+	default:
+		log.Printf("password reset not started: the queue is full")
+	}
+}
+
+// ResetPassword implements PasswordResetUseCase.ResetPassword
+func (q *PasswordResetQueue) ResetPassword(request dtos.ResetPasswordRequest) *response.ErrorResponse {
+	return q.resets.ResetPassword(request)
+}
+
+// Start sends the queued requests with the given number of workers until the context is
+// cancelled; requests still queued then are dropped
+func (q *PasswordResetQueue) Start(ctx context.Context, workers int) {
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case email := <-q.requests:
+					q.resets.RequestReset(email)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
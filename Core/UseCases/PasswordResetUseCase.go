@@ -0,0 +1,177 @@
+package usecases
+
+import (
+	"Financial/Core/Models/db"
+	dtos "Financial/Core/Models/dtos/Request"
+	response "Financial/Core/Models/dtos/Response"
+	"Financial/Core/ports"
+	"Financial/Core/types"
+	"Financial/Core/validators"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// PasswordResetUseCase implements the PasswordResetUseCase interface
+type PasswordResetUseCase struct {
+	repository     ports.Repository[db.PasswordReset, int]
+	userRepository ports.Repository[db.User, int]
+	hasher         ports.PasswordHasher
+	sessions       ports.SessionUseCase
+	mailer         ports.Mailer
+	clock          ports.Clock
+	resetURL       string
+	ttl            time.Duration
+}
+
+// NewPasswordResetUseCase creates a new instance of PasswordResetUseCase.
+// Tokens are valid for ttl; the emailed link is resetURL with the token appended
+// as the "token" query parameter.
+func NewPasswordResetUseCase(repo ports.Repository[db.PasswordReset, int], userRepo ports.Repository[db.User, int], hasher ports.PasswordHasher, sessions ports.SessionUseCase, mailer ports.Mailer, clock ports.Clock, resetURL string, ttl time.Duration) ports.PasswordResetUseCase {
+	return &PasswordResetUseCase{
+		repository:     repo,
+		userRepository: userRepo,
+		hasher:         hasher,
+		sessions:       sessions,
+		mailer:         mailer,
+		clock:          clock,
+		resetURL:       resetURL,
+		ttl:            ttl,
+	}
+}
+
+// RequestReset implements PasswordResetUseCase.RequestReset
+func (uc *PasswordResetUseCase) RequestReset(email string) {
+	// Failures are only logged: the answer must be the same whether or not the account exists
+	user, err := uc.userRepository.FindByField("email", email)
+	if err != nil {
+		if err != types.ErrNotFound {
+			log.Printf("password reset not started: error fetching account: %v", err)
+		}
+		return
+	}
+
+	token, err := randomToken()
+	if err != nil {
+		log.Printf("password reset for user %d not started: error generating token: %v", user.ID, err)
+		return
+	}
+
+	now := uc.clock.Now().UTC()
+	_, err = uc.repository.Create(&db.PasswordReset{
+		UserID:    user.ID,
+		TokenHash: hashToken(token),
+		CreatedAt: now,
+		ExpiresAt: now.Add(uc.ttl),
+	})
+	if err != nil {
+		log.Printf("password reset for user %d not started: error saving token: %v", user.ID, err)
+		return
+	}
+
+	err = uc.mailer.Send(ports.MailMessage{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\nOpen this link to choose a new password:\n\n%s?token=%s\n\nOr send this token to /api/auth/reset-password:\n\n%s\n\nIt expires in %s and works once. If you didn't ask for it, ignore this email; your password has not changed.\n",
+			user.Nickname, uc.resetURL, url.QueryEscape(token), token, uc.ttl),
+	})
+	if err != nil {
+		log.Printf("password reset email for user %d not sent: %v", user.ID, err)
+	}
+}
+
+// ResetPassword implements PasswordResetUseCase.ResetPassword
+func (uc *PasswordResetUseCase) ResetPassword(request dtos.ResetPasswordRequest) *response.ErrorResponse {
+	success, errorsVal := validators.ValidateResetPassword(request)
+	if !success {
+		return &response.ErrorResponse{
+			Error: strings.Join(*errorsVal, " \n"),
+		}
+	}
+
+	invalid := &response.ErrorResponse{
+		Error: errors.New("invalid or expired reset token").Error(),
+	}
+
+	now := uc.clock.Now().UTC()
+	reset, err := uc.repository.FindByField("token_hash", hashToken(request.Token))
+	if err != nil {
+		if err == types.ErrNotFound {
+			return invalid
+		}
+		return &response.ErrorResponse{
+			Error: fmt.Errorf("error fetching reset token: %w", err).Error(),
+		}
+	}
+	if reset.UsedAt != nil || !now.Before(reset.ExpiresAt) {
+		return invalid
+	}
+
+	user, err := uc.userRepository.GetByID(reset.UserID)
+	if err != nil {
+		if err == types.ErrNotFound {
+			return invalid
+		}
+		return &response.ErrorResponse{
+			Error: fmt.Errorf("error fetching account: %w", err).Error(),
+		}
+	}
+
+	// Burn the tokens before touching the password, so a failure can't leave one reusable
+	if errRes := uc.useOutstandingTokens(user.ID, now); errRes != nil {
+		return errRes
+	}
+
+	hash, err := uc.hasher.Hash(request.Password)
+	if err != nil {
+		return &response.ErrorResponse{
+			Error: fmt.Errorf("error hashing password: %w", err).Error(),
+		}
+	}
+	user.Password = hash
+	if _, err := uc.userRepository.Update(user); err != nil {
+		return &response.ErrorResponse{
+			Error: fmt.Errorf("error updating password: %w", err).Error(),
+		}
+	}
+
+	// Whoever knew the old password may still hold a session
+	if errRes := uc.sessions.LogoutAll(user.ID); errRes != nil {
+		return &response.ErrorResponse{
+			Error: fmt.Errorf("password changed but sessions were not revoked: %s", errRes.Error).Error(),
+		}
+	}
+	return nil
+}
+
+// useOutstandingTokens marks every unused reset token of the user as used
+func (uc *PasswordResetUseCase) useOutstandingTokens(userID int, now time.Time) *response.ErrorResponse {
+	page, err := uc.repository.Query("*", ports.QueryOptions{
+		Filters: []ports.Filter{
+			{Field: "user_id", Operator: "eq", Value: userID},
+		},
+	})
+	if err != nil {
+		return &response.ErrorResponse{
+			Error: fmt.Errorf("error fetching reset tokens: %w", err).Error(),
+		}
+	}
+
+	resets := page.Items
+
+	for i := range resets {
+		if resets[i].UsedAt != nil {
+			continue
+		}
+		resets[i].UsedAt = &now
+		if _, err := uc.repository.Update(&resets[i]); err != nil {
+			return &response.ErrorResponse{
+				Error: fmt.Errorf("error updating reset token: %w", err).Error(),
+			}
+		}
+	}
+	return nil
+}
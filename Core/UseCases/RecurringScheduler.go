@@ -0,0 +1,171 @@
+package usecases
+
+import (
+	"Financial/Core/Models/db"
+	dtos "Financial/Core/Models/dtos/Request"
+	response "Financial/Core/Models/dtos/Response"
+	"Financial/Core/ports"
+	"Financial/Core/types"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// SystemClock is the Clock backed by the system time
+type SystemClock struct{}
+
+// Now implements Clock.Now
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// RecurringScheduler implements the RecurringScheduler interface
+type RecurringScheduler struct {
+	repository            ports.Repository[db.RecurringTransaction, int]
+	transactionRepository ports.Repository[db.Transaction, int]
+	transactions          ports.TransactionUseCase
+	clock                 ports.Clock
+}
+
+// NewRecurringScheduler creates a new instance of RecurringScheduler.
+// Postings go through the TransactionUseCase so they update the wallet balance like any other movement.
+func NewRecurringScheduler(repo ports.Repository[db.RecurringTransaction, int], transactionRepo ports.Repository[db.Transaction, int], transactions ports.TransactionUseCase, clock ports.Clock) ports.RecurringScheduler {
+	return &RecurringScheduler{
+		repository:            repo,
+		transactionRepository: transactionRepo,
+		transactions:          transactions,
+		clock:                 clock,
+	}
+}
+
+// Start implements RecurringScheduler.Start
+func (s *RecurringScheduler) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		posted, err := s.RunDue()
+		if err != nil {
+			log.Printf("recurring scheduler: %v", err)
+		}
+		if posted > 0 {
+			log.Printf("recurring scheduler: posted %d transactions", posted)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunDue implements RecurringScheduler.RunDue
+func (s *RecurringScheduler) RunDue() (int, error) {
+	now := s.clock.Now().UTC()
+
+	page, err := s.repository.Query("*", ports.QueryOptions{
+		Filters: []ports.Filter{
+			{Field: "status", Operator: "eq", Value: string(types.RecurringActive)},
+			{Field: "next_run_at", Operator: "lte", Value: now.Format(time.RFC3339)},
+		},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error fetching due recurring transactions: %w", err)
+	}
+	due := page.Items
+
+	total := 0
+	var failures []error
+	for _, series := range due {
+		posted, err := s.postDue(series, now)
+		total += posted
+		if err != nil {
+			failures = append(failures, fmt.Errorf("recurring transaction %d: %w", series.ID, err))
+		}
+	}
+	return total, errors.Join(failures...)
+}
+
+// postDue posts every occurrence of the series up to now, saving the progress after each one.
+// An occurrence the ledger rejects (e.g. the category was deleted) is skipped and reported, so
+// it doesn't hold back the later ones. Any other failure stops the series where it is: the
+// next run retries the same occurrence, and alreadyPosted keeps it from being posted twice.
+func (s *RecurringScheduler) postDue(series db.RecurringTransaction, now time.Time) (int, error) {
+	posted := 0
+	var failures []error
+	for series.Status == types.RecurringActive && series.NextRunAt != nil && !series.NextRunAt.After(now) {
+		occurrence := series.NextRunAt.UTC()
+
+		// A previous run may have posted the occurrence without saving the progress
+		exists, err := s.alreadyPosted(series.ID, occurrence)
+		if err != nil {
+			return posted, errors.Join(append(failures, err)...)
+		}
+		if !exists {
+			recurringID := series.ID
+			_, errTransaction := s.transactions.RecordTransaction(series.UserID, dtos.CreateTransactionRequest{
+				WalletID:       series.WalletID,
+				Type:           series.Type,
+				Amount:         series.Amount,
+				Description:    series.Description,
+				CategoryID:     series.CategoryID,
+				RecurringID:    &recurringID,
+				OccurrenceDate: &occurrence,
+			})
+			if errTransaction != nil && !isRejection(errTransaction) {
+				return posted, errors.Join(append(failures, fmt.Errorf("occurrence %s not posted, retrying on the next run: %s", occurrence.Format(time.RFC3339), errTransaction.Error))...)
+			}
+			if errTransaction != nil {
+				failures = append(failures, fmt.Errorf("occurrence %s skipped: %s", occurrence.Format(time.RFC3339), errTransaction.Error))
+			} else {
+				posted++
+			}
+		}
+
+		next, ok := series.NextOccurrence(occurrence)
+		if ok {
+			series.NextRunAt = &next
+		} else {
+			series.NextRunAt = nil
+			series.Status = types.RecurringEnded
+		}
+		if _, err := s.repository.Update(&series); err != nil {
+			return posted, errors.Join(append(failures, fmt.Errorf("error saving progress: %w", err))...)
+		}
+	}
+	return posted, errors.Join(failures...)
+}
+
+// isRejection reports whether RecordTransaction refused a posting because of the series or the
+// rows it refers to (validation, wallet or category gone, not enough funds, another currency),
+// which a retry can't fix, rather than because the storage failed
+func isRejection(err *response.ErrorResponse) bool {
+	if strings.HasPrefix(err.Error, "Field: ") || strings.HasPrefix(err.Error, types.ErrCurrencyMismatch.Error()) {
+		return true
+	}
+	for _, rejection := range []error{errWalletNotFound, errCategoryNotFound, errCategoryTypeMismatch, errInsufficientFunds} {
+		if err.Error == rejection.Error() {
+			return true
+		}
+	}
+	return false
+}
+
+// alreadyPosted reports whether the ledger has a transaction for the occurrence of the series
+func (s *RecurringScheduler) alreadyPosted(recurringID int, occurrence time.Time) (bool, error) {
+	page, err := s.transactionRepository.Query("id", ports.QueryOptions{
+		Filters: []ports.Filter{
+			{Field: "recurring_id", Operator: "eq", Value: recurringID},
+			{Field: "occurrence_date", Operator: "eq", Value: occurrence.Format(time.RFC3339)},
+		},
+	})
+	if err != nil {
+		return false, fmt.Errorf("error checking posted occurrences: %w", err)
+	}
+	transactions := page.Items
+	return len(transactions) > 0, nil
+}
@@ -0,0 +1,268 @@
+package usecases
+
+import (
+	"Financial/Core/Models/db"
+	dtos "Financial/Core/Models/dtos/Request"
+	response "Financial/Core/Models/dtos/Response"
+	"Financial/Core/ports"
+	"Financial/Core/types"
+	"Financial/Core/validators"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RecurringTransactionUseCase implements the RecurringTransactionUseCase interface
+type RecurringTransactionUseCase struct {
+	repository         ports.Repository[db.RecurringTransaction, int]
+	walletRepository   ports.Repository[db.Wallet, int]
+	categoryRepository ports.Repository[db.Category, int]
+	clock              ports.Clock
+}
+
+// NewRecurringTransactionUseCase creates a new instance of RecurringTransactionUseCase
+func NewRecurringTransactionUseCase(repo ports.Repository[db.RecurringTransaction, int], walletRepo ports.Repository[db.Wallet, int], categoryRepo ports.Repository[db.Category, int], clock ports.Clock) ports.RecurringTransactionUseCase {
+	return &RecurringTransactionUseCase{
+		repository:         repo,
+		walletRepository:   walletRepo,
+		categoryRepository: categoryRepo,
+		clock:              clock,
+	}
+}
+
+// CreateRecurring implements RecurringTransactionUseCase.CreateRecurring
+func (uc *RecurringTransactionUseCase) CreateRecurring(request dtos.CreateRecurringRequest) (*db.RecurringTransaction, *response.ErrorResponse) {
+	request.Frequency = types.Frequency(strings.ToUpper(strings.TrimSpace(string(request.Frequency))))
+	if request.Interval == 0 {
+		request.Interval = 1
+	}
+
+	success, errorsVal := validators.ValidateRecurring(request)
+	if !success {
+		return nil, &response.ErrorResponse{
+			Error: strings.Join(*errorsVal, " \n"),
+		}
+	}
+
+	wallet, err := uc.walletRepository.GetByID(request.WalletID)
+	// Wallets of other users are reported as missing
+	if err != nil || wallet.UserID != request.UserID {
+		if err == nil || err == types.ErrNotFound {
+			return nil, &response.ErrorResponse{
+				Error: errors.New("wallet not found").Error(),
+			}
+		}
+		return nil, &response.ErrorResponse{
+			Error: fmt.Errorf("error fetching wallet: %w", err).Error(),
+		}
+	}
+
+	if request.CategoryID != nil {
+		category, err := uc.categoryRepository.GetByID(*request.CategoryID)
+		if err != nil || category.UserID != request.UserID {
+			if err == nil || err == types.ErrNotFound {
+				return nil, &response.ErrorResponse{
+					Error: errors.New("category not found").Error(),
+				}
+			}
+			return nil, &response.ErrorResponse{
+				Error: fmt.Errorf("error fetching category: %w", err).Error(),
+			}
+		}
+		if category.Type != request.Type {
+			return nil, &response.ErrorResponse{
+				Error: errors.New("category type must match the transaction type").Error(),
+			}
+		}
+	}
+
+	currency := wallet.Currency
+	if currency == "" {
+		currency = types.DefaultCurrency
+	}
+
+	series := db.RecurringTransaction{
+		UserID:      request.UserID,
+		WalletID:    request.WalletID,
+		Type:        request.Type,
+		Amount:      request.Amount.WithCurrency(currency),
+		Description: request.Description,
+		CategoryID:  request.CategoryID,
+		Frequency:   request.Frequency,
+		Interval:    request.Interval,
+		ByMonthDay:  request.ByMonthDay,
+		StartDate:   request.StartDate.UTC(),
+		Status:      types.RecurringActive,
+	}
+	if request.EndDate != nil {
+		endDate := request.EndDate.UTC()
+		series.EndDate = &endDate
+	}
+
+	// The start date itself is the first candidate
+	first, ok := series.NextOccurrence(series.StartDate.Add(-time.Nanosecond))
+	if !ok {
+		return nil, &response.ErrorResponse{
+			Error: errors.New("the schedule has no occurrences").Error(),
+		}
+	}
+	series.NextRunAt = &first
+
+	result, err := uc.repository.Create(&series)
+	if err != nil {
+		return nil, &response.ErrorResponse{
+			Error: fmt.Errorf("error creating recurring transaction: %w", err).Error(),
+		}
+	}
+
+	return result, nil
+}
+
+// UpdateRecurring implements RecurringTransactionUseCase.UpdateRecurring
+func (uc *RecurringTransactionUseCase) UpdateRecurring(request dtos.UpdateRecurringRequest) (*db.RecurringTransaction, *response.ErrorResponse) {
+	series, errSeries := uc.GetRecurring(request.UserID, request.RecurringID)
+	if errSeries != nil {
+		return nil, errSeries
+	}
+
+	if series.Status == types.RecurringEnded {
+		return nil, &response.ErrorResponse{
+			Error: errors.New("the recurring transaction has already ended").Error(),
+		}
+	}
+
+	if request.Amount != nil {
+		if !request.Amount.IsPositive() {
+			return nil, &response.ErrorResponse{
+				Error: errors.New("Amount must be greater than zero").Error(),
+			}
+		}
+		series.Amount = request.Amount.WithCurrency(series.Amount.Currency)
+	}
+	if request.Description != nil {
+		series.Description = *request.Description
+	}
+
+	now := uc.clock.Now().UTC()
+	switch request.Status {
+	case "":
+	case types.RecurringPaused:
+		series.Status = types.RecurringPaused
+	case types.RecurringActive:
+		if series.Status == types.RecurringPaused {
+			// Occurrences missed while paused are skipped
+			series.Status = types.RecurringActive
+			after := now
+			if series.StartDate.After(after) {
+				after = series.StartDate.Add(-time.Nanosecond)
+			}
+			next, ok := series.NextOccurrence(after)
+			series.NextRunAt = nil
+			if ok {
+				series.NextRunAt = &next
+			}
+		}
+	case types.RecurringEnded:
+		if series.EndDate == nil || series.EndDate.After(now) {
+			series.EndDate = &now
+		}
+	default:
+		return nil, &response.ErrorResponse{
+			Error: errors.New("status must be active, paused or ended").Error(),
+		}
+	}
+
+	if request.EndDate != nil && request.Status != types.RecurringEnded {
+		endDate := request.EndDate.UTC()
+		if endDate.Before(series.StartDate) {
+			return nil, &response.ErrorResponse{
+				Error: errors.New("end date can't be before the start date").Error(),
+			}
+		}
+		series.EndDate = &endDate
+	}
+
+	// A series whose next occurrence falls after its end has nothing left to post
+	if request.Status == types.RecurringEnded || series.NextRunAt == nil ||
+		(series.EndDate != nil && series.NextRunAt.After(*series.EndDate)) {
+		series.Status = types.RecurringEnded
+		series.NextRunAt = nil
+	}
+
+	result, err := uc.repository.Update(series)
+	if err != nil {
+		return nil, &response.ErrorResponse{
+			Error: fmt.Errorf("error updating recurring transaction: %w", err).Error(),
+		}
+	}
+
+	return result, nil
+}
+
+// DeleteRecurring implements RecurringTransactionUseCase.DeleteRecurring
+func (uc *RecurringTransactionUseCase) DeleteRecurring(userID int, recurringID int) *response.ErrorResponse {
+	if _, errSeries := uc.GetRecurring(userID, recurringID); errSeries != nil {
+		return errSeries
+	}
+
+	if err := uc.repository.Delete(recurringID); err != nil {
+		return &response.ErrorResponse{
+			Error: fmt.Errorf("error deleting recurring transaction: %w", err).Error(),
+		}
+	}
+	return nil
+}
+
+// GetRecurring implements RecurringTransactionUseCase.GetRecurring
+func (uc *RecurringTransactionUseCase) GetRecurring(userID int, recurringID int) (*db.RecurringTransaction, *response.ErrorResponse) {
+	if recurringID <= 0 {
+		return nil, &response.ErrorResponse{
+			Error: errors.New("invalid recurring transaction ID").Error(),
+		}
+	}
+
+	series, err := uc.repository.GetByID(recurringID)
+	// Series of other users are reported as missing
+	if err != nil || series.UserID != userID {
+		if err == nil || err == types.ErrNotFound {
+			return nil, &response.ErrorResponse{
+				Error: errors.New("recurring transaction not found").Error(),
+			}
+		}
+		return nil, &response.ErrorResponse{
+			Error: fmt.Errorf("error fetching recurring transaction: %w", err).Error(),
+		}
+	}
+	return series, nil
+}
+
+// GetUserRecurring implements RecurringTransactionUseCase.GetUserRecurring
+func (uc *RecurringTransactionUseCase) GetUserRecurring(userID int) ([]db.RecurringTransaction, *response.ErrorResponse) {
+	page, err := uc.repository.Query("*", ports.QueryOptions{
+		Filters: []ports.Filter{
+			{
+				Field:    "user_id",
+				Operator: "eq",
+				Value:    userID,
+			},
+		},
+		OrderBy: []ports.OrderBy{
+			{
+				Field:     "id",
+				Ascending: true,
+			},
+		},
+	})
+	if err != nil {
+		return nil, &response.ErrorResponse{
+			Error: fmt.Errorf("error fetching recurring transactions: %w", err).Error(),
+		}
+	}
+
+	series := page.Items
+	if series == nil {
+		series = []db.RecurringTransaction{}
+	}
+	return series, nil
+}
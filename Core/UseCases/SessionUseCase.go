@@ -0,0 +1,331 @@
+package usecases
+
+import (
+	"Financial/Core/Models/db"
+	response "Financial/Core/Models/dtos/Response"
+	"Financial/Core/ports"
+	"Financial/Core/types"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// sessionTouchInterval limits how often ValidateAccess writes LastSeenAt,
+// so authenticated requests don't each cost a database update
+const sessionTouchInterval = time.Minute
+
+// SessionUseCase implements the SessionUseCase interface
+type SessionUseCase struct {
+	repository ports.SessionRepository
+	users      ports.Repository[db.User, int]
+	clock      ports.Clock
+	refreshTTL time.Duration
+}
+
+// NewSessionUseCase creates a new instance of SessionUseCase.
+// users is read on every refresh, so a suspended account can't keep its sessions alive.
+// refreshTTL is how long a refresh token stays valid after it is issued.
+func NewSessionUseCase(repo ports.SessionRepository, users ports.Repository[db.User, int], clock ports.Clock, refreshTTL time.Duration) ports.SessionUseCase {
+	return &SessionUseCase{
+		repository: repo,
+		users:      users,
+		clock:      clock,
+		refreshTTL: refreshTTL,
+	}
+}
+
+// StartSession implements SessionUseCase.StartSession
+func (uc *SessionUseCase) StartSession(userID int, device string, ip string) (*response.SessionGrant, *response.ErrorResponse) {
+	tokenID, refreshToken, err := newSessionTokens()
+	if err != nil {
+		return nil, &response.ErrorResponse{
+			Error: fmt.Errorf("error generating tokens: %w", err).Error(),
+		}
+	}
+
+	now := uc.clock.Now().UTC()
+	session, err := uc.repository.Create(&db.Session{
+		UserID:           userID,
+		AccessTokenID:    tokenID,
+		RefreshTokenHash: hashToken(refreshToken),
+		Device:           device,
+		IP:               ip,
+		CreatedAt:        now,
+		LastSeenAt:       now,
+		ExpiresAt:        now.Add(uc.refreshTTL),
+	})
+	if err != nil {
+		return nil, &response.ErrorResponse{
+			Error: fmt.Errorf("error creating session: %w", err).Error(),
+		}
+	}
+
+	return &response.SessionGrant{
+		SessionID:        session.ID,
+		Subject:          strconv.Itoa(session.UserID),
+		TokenID:          tokenID,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: session.ExpiresAt,
+	}, nil
+}
+
+// RefreshSession implements SessionUseCase.RefreshSession
+func (uc *SessionUseCase) RefreshSession(refreshToken string, device string, ip string) (*response.SessionGrant, *response.ErrorResponse) {
+	invalid := &response.ErrorResponse{
+		Error: errors.New("invalid refresh token").Error(),
+	}
+	if refreshToken == "" {
+		return nil, invalid
+	}
+
+	now := uc.clock.Now().UTC()
+	hash := hashToken(refreshToken)
+
+	session, err := uc.repository.FindByField("refresh_token_hash", hash)
+	if err == types.ErrNotFound {
+		// A token that was already rotated away is being replayed: whoever holds
+		// the current one may be an attacker, so the whole session goes
+		reused, errReused := uc.repository.FindByField("previous_refresh_hash", hash)
+		if errReused == nil && reused.RevokedAt == nil {
+			reused.RevokedAt = &now
+			if _, err := uc.repository.Update(reused); err != nil {
+				return nil, &response.ErrorResponse{
+					Error: fmt.Errorf("error revoking session: %w", err).Error(),
+				}
+			}
+		}
+		return nil, invalid
+	}
+	if err != nil {
+		return nil, &response.ErrorResponse{
+			Error: fmt.Errorf("error fetching session: %w", err).Error(),
+		}
+	}
+	if !session.Active(now) {
+		return nil, invalid
+	}
+
+	user, err := uc.users.GetByID(session.UserID)
+	if err == types.ErrNotFound {
+		return nil, invalid
+	}
+	if err != nil {
+		return nil, &response.ErrorResponse{
+			Error: fmt.Errorf("error fetching account: %w", err).Error(),
+		}
+	}
+	if user.Status == types.Suspend {
+		if errRes := uc.revoke(session); errRes != nil {
+			return nil, errRes
+		}
+		return nil, &response.ErrorResponse{
+			Error: types.ErrAccountSuspended.Error(),
+		}
+	}
+
+	tokenID, newRefreshToken, err := newSessionTokens()
+	if err != nil {
+		return nil, &response.ErrorResponse{
+			Error: fmt.Errorf("error generating tokens: %w", err).Error(),
+		}
+	}
+
+	session.PreviousRefreshHash = &hash
+	session.RefreshTokenHash = hashToken(newRefreshToken)
+	session.AccessTokenID = tokenID
+	session.Device = device
+	session.IP = ip
+	session.LastSeenAt = now
+	session.ExpiresAt = now.Add(uc.refreshTTL)
+
+	// Only one of two refreshes racing with the same token gets to rotate it
+	updated, err := uc.repository.RotateRefresh(session, hash)
+	if err == types.ErrNotFound {
+		return nil, invalid
+	}
+	if err != nil {
+		return nil, &response.ErrorResponse{
+			Error: fmt.Errorf("error updating session: %w", err).Error(),
+		}
+	}
+
+	return &response.SessionGrant{
+		SessionID:        updated.ID,
+		Subject:          strconv.Itoa(updated.UserID),
+		TokenID:          tokenID,
+		RefreshToken:     newRefreshToken,
+		RefreshExpiresAt: updated.ExpiresAt,
+	}, nil
+}
+
+// ValidateAccess implements SessionUseCase.ValidateAccess
+func (uc *SessionUseCase) ValidateAccess(tokenID string) error {
+	if tokenID == "" {
+		return types.ErrSessionRevoked
+	}
+
+	session, err := uc.repository.FindByField("access_token_id", tokenID)
+	if err == types.ErrNotFound {
+		return types.ErrSessionRevoked
+	}
+	if err != nil {
+		return err
+	}
+
+	now := uc.clock.Now().UTC()
+	if !session.Active(now) {
+		return types.ErrSessionRevoked
+	}
+
+	if now.Sub(session.LastSeenAt) >= sessionTouchInterval {
+		session.LastSeenAt = now
+		// Losing a last-seen update is not a reason to reject the request
+		_, _ = uc.repository.Update(session)
+	}
+	return nil
+}
+
+// Logout implements SessionUseCase.Logout
+func (uc *SessionUseCase) Logout(tokenID string) *response.ErrorResponse {
+	session, err := uc.repository.FindByField("access_token_id", tokenID)
+	if err != nil {
+		if err == types.ErrNotFound {
+			return &response.ErrorResponse{
+				Error: errors.New("session not found").Error(),
+			}
+		}
+		return &response.ErrorResponse{
+			Error: fmt.Errorf("error fetching session: %w", err).Error(),
+		}
+	}
+	return uc.revoke(session)
+}
+
+// LogoutAll implements SessionUseCase.LogoutAll
+func (uc *SessionUseCase) LogoutAll(userID int) *response.ErrorResponse {
+	sessions, errRes := uc.userSessions(userID)
+	if errRes != nil {
+		return errRes
+	}
+
+	for i := range sessions {
+		if sessions[i].RevokedAt != nil {
+			continue
+		}
+		if errRes := uc.revoke(&sessions[i]); errRes != nil {
+			return errRes
+		}
+	}
+	return nil
+}
+
+// RevokeSession implements SessionUseCase.RevokeSession
+func (uc *SessionUseCase) RevokeSession(userID int, sessionID int) *response.ErrorResponse {
+	session, err := uc.repository.GetByID(sessionID)
+	// Sessions of other users are reported as missing
+	if err != nil || session.UserID != userID {
+		if err == nil || err == types.ErrNotFound {
+			return &response.ErrorResponse{
+				Error: errors.New("session not found").Error(),
+			}
+		}
+		return &response.ErrorResponse{
+			Error: fmt.Errorf("error fetching session: %w", err).Error(),
+		}
+	}
+
+	if session.RevokedAt != nil {
+		return nil
+	}
+	return uc.revoke(session)
+}
+
+// GetSessions implements SessionUseCase.GetSessions
+func (uc *SessionUseCase) GetSessions(userID int, currentTokenID string) ([]response.SessionResponse, *response.ErrorResponse) {
+	sessions, errRes := uc.userSessions(userID)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return sessions[i].LastSeenAt.After(sessions[j].LastSeenAt)
+	})
+
+	result := make([]response.SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		result = append(result, response.SessionResponse{
+			ID:         session.ID,
+			Device:     session.Device,
+			IP:         session.IP,
+			CreatedAt:  session.CreatedAt,
+			LastSeenAt: session.LastSeenAt,
+			ExpiresAt:  session.ExpiresAt,
+			RevokedAt:  session.RevokedAt,
+			Current:    session.RevokedAt == nil && currentTokenID != "" && session.AccessTokenID == currentTokenID,
+		})
+	}
+	return result, nil
+}
+
+// userSessions fetches every session of the user
+func (uc *SessionUseCase) userSessions(userID int) ([]db.Session, *response.ErrorResponse) {
+	page, err := uc.repository.Query("*", ports.QueryOptions{
+		Filters: []ports.Filter{
+			{Field: "user_id", Operator: "eq", Value: userID},
+		},
+	})
+	if err != nil {
+		return nil, &response.ErrorResponse{
+			Error: fmt.Errorf("error fetching sessions: %w", err).Error(),
+		}
+	}
+
+	return page.Items, nil
+}
+
+// revoke marks a session as logged out; its access and refresh tokens stop working at once
+func (uc *SessionUseCase) revoke(session *db.Session) *response.ErrorResponse {
+	now := uc.clock.Now().UTC()
+	session.RevokedAt = &now
+	if _, err := uc.repository.Update(session); err != nil {
+		return &response.ErrorResponse{
+			Error: fmt.Errorf("error revoking session: %w", err).Error(),
+		}
+	}
+	return nil
+}
+
+// newSessionTokens generates a random access token ID and refresh token
+func newSessionTokens() (string, string, error) {
+	tokenID := make([]byte, 16)
+	if _, err := rand.Read(tokenID); err != nil {
+		return "", "", err
+	}
+	refreshToken, err := randomToken()
+	if err != nil {
+		return "", "", err
+	}
+	return hex.EncodeToString(tokenID), refreshToken, nil
+}
+
+// randomToken generates an opaque, URL-safe token of 256 random bits
+func randomToken() (string, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(token), nil
+}
+
+// hashToken is the stored form of refresh and reset tokens. The tokens are 256 random bits,
+// so a plain SHA-256 is enough to make a leaked table useless
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,292 @@
+package usecases
+
+import (
+	"Financial/Core/Models/db"
+	dtos "Financial/Core/Models/dtos/Request"
+	response "Financial/Core/Models/dtos/Response"
+	"Financial/Core/ports"
+	"Financial/Core/types"
+	"Financial/Core/validators"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TransactionUseCase implements the TransactionUseCase interface
+type TransactionUseCase struct {
+	repository         ports.Repository[db.Transaction, int]
+	walletRepository   ports.Repository[db.Wallet, int]
+	categoryRepository ports.Repository[db.Category, int]
+	unitOfWork         ports.UnitOfWork
+}
+
+// NewTransactionUseCase creates a new instance of TransactionUseCase.
+// A transaction and the balance it changes are written inside unitOfWork.
+func NewTransactionUseCase(repo ports.Repository[db.Transaction, int], walletRepo ports.Repository[db.Wallet, int], categoryRepo ports.Repository[db.Category, int], unitOfWork ports.UnitOfWork) ports.TransactionUseCase {
+	return &TransactionUseCase{
+		repository:         repo,
+		walletRepository:   walletRepo,
+		categoryRepository: categoryRepo,
+		unitOfWork:         unitOfWork,
+	}
+}
+
+// newLedgerEntry builds the transaction that explains a change of delta on a wallet balance.
+func newLedgerEntry(walletID int, delta types.Money, description string) db.Transaction {
+	transactionType := types.Income
+	if delta.IsNegative() {
+		transactionType = types.Expense
+	}
+	return db.Transaction{
+		WalletID:    walletID,
+		Type:        transactionType,
+		Amount:      delta.Abs(),
+		Description: description,
+		CreatedAt:   time.Now(),
+	}
+}
+
+// Reasons RecordTransaction refuses a transaction because of the request or the rows it refers
+// to, not because the storage failed: the same request gets the same answer until they change
+var (
+	errWalletNotFound       = errors.New("wallet not found")
+	errCategoryNotFound     = errors.New("category not found")
+	errCategoryTypeMismatch = errors.New("category type must match the transaction type")
+	errInsufficientFunds    = errors.New("insufficient funds in debit wallet")
+)
+
+// lockWallet reads a wallet for an update inside a unit of work
+func lockWallet(repos ports.UnitOfWorkRepositories, walletID int) (*db.Wallet, error) {
+	wallet, err := repos.Wallets.GetForUpdate(walletID)
+	if err != nil {
+		if errors.Is(err, types.ErrNotFound) {
+			return nil, errWalletNotFound
+		}
+		return nil, fmt.Errorf("error fetching wallet: %w", err)
+	}
+	return wallet, nil
+}
+
+// wouldOverdraw reports whether setting balance on the wallet leaves a debit wallet below zero.
+func wouldOverdraw(wallet *db.Wallet, balance types.Money) bool {
+	return wallet.Type == types.Debit && balance.IsNegative()
+}
+
+// getWallet fetches a wallet of the user; wallets of other users are reported as missing
+func (uc *TransactionUseCase) getWallet(userID int, walletID int) (*db.Wallet, *response.ErrorResponse) {
+	wallet, errWallet := uc.findWallet(walletID)
+	if errWallet != nil {
+		return nil, errWallet
+	}
+	if wallet.UserID != userID {
+		return nil, &response.ErrorResponse{
+			Error: errWalletNotFound.Error(),
+		}
+	}
+	return wallet, nil
+}
+
+// findWallet fetches a wallet whoever owns it
+func (uc *TransactionUseCase) findWallet(walletID int) (*db.Wallet, *response.ErrorResponse) {
+	wallet, err := uc.walletRepository.GetByID(walletID)
+	if err != nil {
+		if err == types.ErrNotFound {
+			return nil, &response.ErrorResponse{
+				Error: errWalletNotFound.Error(),
+			}
+		}
+		return nil, &response.ErrorResponse{
+			Error: fmt.Errorf("error fetching wallet: %w", err).Error(),
+		}
+	}
+	return wallet, nil
+}
+
+// RecordTransaction implements TransactionUseCase.RecordTransaction
+func (uc *TransactionUseCase) RecordTransaction(userID int, request dtos.CreateTransactionRequest) (*db.Transaction, *response.ErrorResponse) {
+	success, errorsVal := validators.ValidateTransaction(request)
+	if !success {
+		return nil, &response.ErrorResponse{
+			Error: strings.Join(*errorsVal, " \n"),
+		}
+	}
+
+	wallet, errWallet := uc.getWallet(userID, request.WalletID)
+	if errWallet != nil {
+		return nil, errWallet
+	}
+
+	if request.CategoryID != nil {
+		category, err := uc.categoryRepository.GetByID(*request.CategoryID)
+		// Categories of other users are reported as missing
+		if err != nil || category.UserID != wallet.UserID {
+			if err == nil || err == types.ErrNotFound {
+				return nil, &response.ErrorResponse{
+					Error: errCategoryNotFound.Error(),
+				}
+			}
+			return nil, &response.ErrorResponse{
+				Error: fmt.Errorf("error fetching category: %w", err).Error(),
+			}
+		}
+		if category.Type != request.Type {
+			return nil, &response.ErrorResponse{
+				Error: errCategoryTypeMismatch.Error(),
+			}
+		}
+	}
+
+	transaction := db.Transaction{
+		WalletID:    request.WalletID,
+		Type:        request.Type,
+		Amount:      request.Amount,
+		Description: request.Description,
+		CategoryID:  request.CategoryID,
+		CreatedAt:   time.Now(),
+
+		RecurringID:    request.RecurringID,
+		OccurrenceDate: request.OccurrenceDate,
+	}
+	if request.PostedAt != nil {
+		transaction.CreatedAt = *request.PostedAt
+	}
+
+	// The transaction and the new balance are stored together or not at all; the balance is
+	// computed from the wallet locked inside the unit of work, so concurrent postings add up
+	var result *db.Transaction
+	err := uc.unitOfWork.Do(func(repos ports.UnitOfWorkRepositories) error {
+		current, err := lockWallet(repos, wallet.ID)
+		if err != nil {
+			return err
+		}
+		balance, err := current.Balance.Add(transaction.SignedAmount())
+		if err != nil {
+			return err
+		}
+		if wouldOverdraw(current, balance) {
+			return errInsufficientFunds
+		}
+
+		created, err := repos.Transactions.Create(&transaction)
+		if err != nil {
+			return fmt.Errorf("error recording transaction: %w", err)
+		}
+
+		current.Balance = balance
+		if _, err := repos.Wallets.Update(current); err != nil {
+			return fmt.Errorf("error updating wallet balance: %w", err)
+		}
+		result = created
+		return nil
+	})
+	if err != nil {
+		return nil, &response.ErrorResponse{
+			Error: err.Error(),
+		}
+	}
+
+	return result, nil
+}
+
+// GetWalletTransactions implements TransactionUseCase.GetWalletTransactions
+func (uc *TransactionUseCase) GetWalletTransactions(userID int, walletID int) ([]db.Transaction, *response.ErrorResponse) {
+	if walletID <= 0 {
+		return nil, &response.ErrorResponse{
+			Error: errors.New("invalid wallet ID").Error(),
+		}
+	}
+
+	// Support staff pass 0 to read the ledger of any wallet
+	var errWallet *response.ErrorResponse
+	if userID == 0 {
+		_, errWallet = uc.findWallet(walletID)
+	} else {
+		_, errWallet = uc.getWallet(userID, walletID)
+	}
+	if errWallet != nil {
+		return nil, errWallet
+	}
+
+	page, err := uc.repository.Query("*", ports.QueryOptions{
+		Filters: []ports.Filter{
+			{
+				Field:    "wallet_id",
+				Operator: "eq",
+				Value:    walletID,
+			},
+		},
+		OrderBy: []ports.OrderBy{
+			{
+				Field:     "created_at",
+				Ascending: false,
+			},
+		},
+	})
+	if err != nil {
+		return nil, &response.ErrorResponse{
+			Error: err.Error(),
+		}
+	}
+
+	return page.Items, nil
+}
+
+// DeleteTransaction implements TransactionUseCase.DeleteTransaction
+func (uc *TransactionUseCase) DeleteTransaction(userID int, walletID int, transactionID int) *response.ErrorResponse {
+	if transactionID <= 0 {
+		return &response.ErrorResponse{
+			Error: errors.New("invalid transaction ID").Error(),
+		}
+	}
+
+	// The wallet is checked first so transactions of other users stay hidden
+	wallet, errWallet := uc.getWallet(userID, walletID)
+	if errWallet != nil {
+		return errWallet
+	}
+
+	transaction, err := uc.repository.GetByID(transactionID)
+	if err != nil || transaction.WalletID != walletID {
+		if err == nil || err == types.ErrNotFound {
+			return &response.ErrorResponse{
+				Error: errors.New("transaction not found").Error(),
+			}
+		}
+		return &response.ErrorResponse{
+			Error: fmt.Errorf("error fetching transaction: %w", err).Error(),
+		}
+	}
+
+	// The balance is reverted only if the transaction is removed with it, starting from the
+	// wallet locked inside the unit of work
+	errDelete := uc.unitOfWork.Do(func(repos ports.UnitOfWorkRepositories) error {
+		current, err := lockWallet(repos, wallet.ID)
+		if err != nil {
+			return err
+		}
+		balance, err := current.Balance.Subtract(transaction.SignedAmount())
+		if err != nil {
+			return err
+		}
+		if wouldOverdraw(current, balance) {
+			return errInsufficientFunds
+		}
+
+		current.Balance = balance
+		if _, err := repos.Wallets.Update(current); err != nil {
+			return fmt.Errorf("error updating wallet balance: %w", err)
+		}
+		if err := repos.Transactions.Delete(transactionID); err != nil {
+			return fmt.Errorf("error deleting transaction: %w", err)
+		}
+		return nil
+	})
+	if errDelete != nil {
+		return &response.ErrorResponse{
+			Error: errDelete.Error(),
+		}
+	}
+
+	return nil
+}
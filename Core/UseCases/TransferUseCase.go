@@ -0,0 +1,97 @@
+package usecases
+
+import (
+	"Financial/Core/Models/db"
+	dtos "Financial/Core/Models/dtos/Request"
+	response "Financial/Core/Models/dtos/Response"
+	"Financial/Core/ports"
+	"Financial/Core/types"
+	"Financial/Core/validators"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// TransferUseCase implements the TransferUseCase interface
+type TransferUseCase struct {
+	repository       ports.TransferRepository
+	walletRepository ports.Repository[db.Wallet, int]
+}
+
+// NewTransferUseCase creates a new instance of TransferUseCase
+func NewTransferUseCase(repo ports.TransferRepository, walletRepo ports.Repository[db.Wallet, int]) ports.TransferUseCase {
+	return &TransferUseCase{
+		repository:       repo,
+		walletRepository: walletRepo,
+	}
+}
+
+// getWallet fetches a wallet of the user; wallets of other users are reported as missing
+func (uc *TransferUseCase) getWallet(userID int, walletID int, role string) (*db.Wallet, *response.ErrorResponse) {
+	wallet, err := uc.walletRepository.GetByID(walletID)
+	if err != nil || wallet.UserID != userID {
+		if err == nil || err == types.ErrNotFound {
+			return nil, &response.ErrorResponse{
+				Error: fmt.Sprintf("%s wallet not found", role),
+			}
+		}
+		return nil, &response.ErrorResponse{
+			Error: fmt.Errorf("error fetching %s wallet: %w", role, err).Error(),
+		}
+	}
+	return wallet, nil
+}
+
+// Transfer implements TransferUseCase.Transfer
+func (uc *TransferUseCase) Transfer(userID int, request dtos.CreateTransferRequest) (*db.Transfer, *response.ErrorResponse) {
+	success, errorsVal := validators.ValidateTransfer(request)
+	if !success {
+		return nil, &response.ErrorResponse{
+			Error: strings.Join(*errorsVal, " \n"),
+		}
+	}
+
+	from, errFrom := uc.getWallet(userID, request.FromWalletID, "source")
+	if errFrom != nil {
+		return nil, errFrom
+	}
+
+	to, errTo := uc.getWallet(userID, request.ToWalletID, "destination")
+	if errTo != nil {
+		return nil, errTo
+	}
+
+	// Transfers move the same amount on both sides, so they can't cross currencies
+	if from.Currency != to.Currency {
+		return nil, &response.ErrorResponse{
+			Error: errors.New("wallets have different currencies").Error(),
+		}
+	}
+
+	// Fail fast here; the repository checks it again while both wallets are locked
+	remaining, err := from.Balance.Subtract(request.Amount)
+	if err != nil {
+		return nil, &response.ErrorResponse{
+			Error: err.Error(),
+		}
+	}
+	if wouldOverdraw(from, remaining) {
+		return nil, &response.ErrorResponse{
+			Error: errors.New("insufficient funds in debit wallet").Error(),
+		}
+	}
+
+	result, errTransfer := uc.repository.Transfer(&db.Transfer{
+		FromWalletID: from.ID,
+		ToWalletID:   to.ID,
+		Amount:       request.Amount,
+		Description:  request.Description,
+	})
+	if errTransfer != nil {
+		return nil, &response.ErrorResponse{
+			Error: fmt.Errorf("error applying transfer: %w", errTransfer).Error(),
+		}
+	}
+
+	return result, nil
+}
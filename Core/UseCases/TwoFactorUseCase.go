@@ -0,0 +1,260 @@
+package usecases
+
+import (
+	"Financial/Core/Models/db"
+	response "Financial/Core/Models/dtos/Response"
+	"Financial/Core/ports"
+	"Financial/Core/totp"
+	"Financial/Core/types"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// recoveryCodeCount is how many recovery codes are handed out on confirmation
+const recoveryCodeCount = 10
+
+// TwoFactorUseCase implements the TwoFactorUseCase interface
+type TwoFactorUseCase struct {
+	repository     ports.Repository[db.TwoFactor, int]
+	userRepository ports.Repository[db.User, int]
+	clock          ports.Clock
+	issuer         string
+}
+
+// NewTwoFactorUseCase creates a new instance of TwoFactorUseCase.
+// issuer is the name authenticator apps show next to the account email.
+func NewTwoFactorUseCase(repo ports.Repository[db.TwoFactor, int], userRepo ports.Repository[db.User, int], clock ports.Clock, issuer string) ports.TwoFactorUseCase {
+	return &TwoFactorUseCase{
+		repository:     repo,
+		userRepository: userRepo,
+		clock:          clock,
+		issuer:         issuer,
+	}
+}
+
+// Setup implements TwoFactorUseCase.Setup
+func (uc *TwoFactorUseCase) Setup(userID int) (*response.TwoFactorSetupResponse, *response.ErrorResponse) {
+	user, err := uc.userRepository.GetByID(userID)
+	if err != nil {
+		if err == types.ErrNotFound {
+			return nil, &response.ErrorResponse{
+				Error: errors.New("account not found").Error(),
+			}
+		}
+		return nil, &response.ErrorResponse{
+			Error: fmt.Errorf("error fetching account: %w", err).Error(),
+		}
+	}
+
+	enrollment, errRes := uc.enrollment(userID)
+	if errRes != nil {
+		return nil, errRes
+	}
+	if enrollment != nil && enrollment.ConfirmedAt != nil {
+		return nil, &response.ErrorResponse{
+			Error: errors.New("two-factor authentication is already enabled").Error(),
+		}
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, &response.ErrorResponse{
+			Error: fmt.Errorf("error generating secret: %w", err).Error(),
+		}
+	}
+
+	// An unconfirmed setup is restarted with the new secret
+	if enrollment != nil {
+		enrollment.Secret = secret
+		enrollment.CreatedAt = uc.clock.Now().UTC()
+		_, err = uc.repository.Update(enrollment)
+	} else {
+		_, err = uc.repository.Create(&db.TwoFactor{
+			UserID:        userID,
+			Secret:        secret,
+			RecoveryCodes: []string{},
+			CreatedAt:     uc.clock.Now().UTC(),
+		})
+	}
+	if err != nil {
+		return nil, &response.ErrorResponse{
+			Error: fmt.Errorf("error saving two-factor setup: %w", err).Error(),
+		}
+	}
+
+	return &response.TwoFactorSetupResponse{
+		Secret: secret,
+		URI:    totp.URI(uc.issuer, user.Email, secret),
+	}, nil
+}
+
+// Confirm implements TwoFactorUseCase.Confirm
+func (uc *TwoFactorUseCase) Confirm(userID int, code string) (*response.RecoveryCodesResponse, *response.ErrorResponse) {
+	enrollment, errRes := uc.enrollment(userID)
+	if errRes != nil {
+		return nil, errRes
+	}
+	if enrollment == nil || enrollment.ConfirmedAt != nil {
+		return nil, &response.ErrorResponse{
+			Error: errors.New("no two-factor setup in progress").Error(),
+		}
+	}
+
+	now := uc.clock.Now().UTC()
+	step, ok := totp.Validate(enrollment.Secret, code, now)
+	if !ok {
+		return nil, &response.ErrorResponse{
+			Error: types.ErrInvalidTwoFactorCode.Error(),
+		}
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, &response.ErrorResponse{
+			Error: fmt.Errorf("error generating recovery codes: %w", err).Error(),
+		}
+	}
+
+	enrollment.ConfirmedAt = &now
+	enrollment.LastUsedStep = step
+	enrollment.RecoveryCodes = hashes
+	if _, err := uc.repository.Update(enrollment); err != nil {
+		return nil, &response.ErrorResponse{
+			Error: fmt.Errorf("error enabling two-factor authentication: %w", err).Error(),
+		}
+	}
+
+	return &response.RecoveryCodesResponse{Codes: codes}, nil
+}
+
+// Disable implements TwoFactorUseCase.Disable
+func (uc *TwoFactorUseCase) Disable(userID int, code string) *response.ErrorResponse {
+	enrollment, errRes := uc.confirmedEnrollment(userID)
+	if errRes != nil {
+		return errRes
+	}
+	if errRes := uc.check(enrollment, code); errRes != nil {
+		return errRes
+	}
+
+	if err := uc.repository.Delete(enrollment.ID); err != nil {
+		return &response.ErrorResponse{
+			Error: fmt.Errorf("error disabling two-factor authentication: %w", err).Error(),
+		}
+	}
+	return nil
+}
+
+// Enabled implements TwoFactorUseCase.Enabled
+func (uc *TwoFactorUseCase) Enabled(userID int) (bool, error) {
+	enrollment, err := uc.repository.FindByField("user_id", userID)
+	if err != nil {
+		if err == types.ErrNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	return enrollment.ConfirmedAt != nil, nil
+}
+
+// Verify implements TwoFactorUseCase.Verify
+func (uc *TwoFactorUseCase) Verify(userID int, code string) *response.ErrorResponse {
+	enrollment, errRes := uc.confirmedEnrollment(userID)
+	if errRes != nil {
+		return errRes
+	}
+	return uc.check(enrollment, code)
+}
+
+// check accepts an app code newer than the last one used, or consumes a recovery code
+func (uc *TwoFactorUseCase) check(enrollment *db.TwoFactor, code string) *response.ErrorResponse {
+	invalid := &response.ErrorResponse{
+		Error: types.ErrInvalidTwoFactorCode.Error(),
+	}
+
+	if step, ok := totp.Validate(enrollment.Secret, code, uc.clock.Now().UTC()); ok {
+		// A code seen once may have been observed; it never works a second time
+		if step <= enrollment.LastUsedStep {
+			return invalid
+		}
+		enrollment.LastUsedStep = step
+	} else {
+		hash := hashToken(normalizeRecoveryCode(code))
+		index := -1
+		for i, stored := range enrollment.RecoveryCodes {
+			if subtle.ConstantTimeCompare([]byte(stored), []byte(hash)) == 1 {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			return invalid
+		}
+		remaining := make([]string, 0, len(enrollment.RecoveryCodes)-1)
+		remaining = append(remaining, enrollment.RecoveryCodes[:index]...)
+		enrollment.RecoveryCodes = append(remaining, enrollment.RecoveryCodes[index+1:]...)
+	}
+
+	if _, err := uc.repository.Update(enrollment); err != nil {
+		return &response.ErrorResponse{
+			Error: fmt.Errorf("error updating two-factor authentication: %w", err).Error(),
+		}
+	}
+	return nil
+}
+
+// enrollment fetches the enrollment of the user, or nil when there is none
+func (uc *TwoFactorUseCase) enrollment(userID int) (*db.TwoFactor, *response.ErrorResponse) {
+	enrollment, err := uc.repository.FindByField("user_id", userID)
+	if err != nil {
+		if err == types.ErrNotFound {
+			return nil, nil
+		}
+		return nil, &response.ErrorResponse{
+			Error: fmt.Errorf("error fetching two-factor authentication: %w", err).Error(),
+		}
+	}
+	return enrollment, nil
+}
+
+// confirmedEnrollment fetches the enrollment of the user, failing unless it is enabled
+func (uc *TwoFactorUseCase) confirmedEnrollment(userID int) (*db.TwoFactor, *response.ErrorResponse) {
+	enrollment, errRes := uc.enrollment(userID)
+	if errRes != nil {
+		return nil, errRes
+	}
+	if enrollment == nil || enrollment.ConfirmedAt == nil {
+		return nil, &response.ErrorResponse{
+			Error: errors.New("two-factor authentication is not enabled").Error(),
+		}
+	}
+	return enrollment, nil
+}
+
+// newRecoveryCodes generates the recovery codes, formatted "xxxxx-xxxxx", and their stored hashes
+func newRecoveryCodes() ([]string, []string, error) {
+	encoding := base32.StdEncoding.WithPadding(base32.NoPadding)
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		raw := make([]byte, 7)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, nil, err
+		}
+		value := strings.ToLower(encoding.EncodeToString(raw))[:10]
+		codes = append(codes, value[:5]+"-"+value[5:])
+		hashes = append(hashes, hashToken(value))
+	}
+	return codes, hashes, nil
+}
+
+// normalizeRecoveryCode drops the separators and case users tend to change when typing a code
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	code = strings.ReplaceAll(code, "-", "")
+	return strings.ReplaceAll(code, " ", "")
+}
@@ -0,0 +1,166 @@
+package usecases
+
+import (
+	"Financial/Core/Models/db"
+	response "Financial/Core/Models/dtos/Response"
+	"Financial/Core/ports"
+	"Financial/Core/types"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// errInvalidVerificationToken is the single answer for every bad token, so callers
+// can't tell a forged token from an expired or used one
+var errInvalidVerificationToken = errors.New("invalid or expired verification token")
+
+// VerificationUseCase implements the VerificationUseCase interface
+type VerificationUseCase struct {
+	repository ports.Repository[db.User, int]
+	mailer     ports.Mailer
+	clock      ports.Clock
+	secret     []byte
+	verifyURL  string
+	ttl        time.Duration
+}
+
+// NewVerificationUseCase creates a new instance of VerificationUseCase.
+// Tokens are signed with secret and valid for ttl; the emailed link is verifyURL with
+// the token appended as the "token" query parameter.
+func NewVerificationUseCase(repo ports.Repository[db.User, int], mailer ports.Mailer, clock ports.Clock, secret []byte, verifyURL string, ttl time.Duration) ports.VerificationUseCase {
+	return &VerificationUseCase{
+		repository: repo,
+		mailer:     mailer,
+		clock:      clock,
+		secret:     secret,
+		verifyURL:  verifyURL,
+		ttl:        ttl,
+	}
+}
+
+// SendVerification implements VerificationUseCase.SendVerification
+func (uc *VerificationUseCase) SendVerification(userID int) *response.ErrorResponse {
+	user, err := uc.repository.GetByID(userID)
+	if err != nil {
+		if err == types.ErrNotFound {
+			return &response.ErrorResponse{
+				Error: errors.New("account not found").Error(),
+			}
+		}
+		return &response.ErrorResponse{
+			Error: fmt.Errorf("error fetching account: %w", err).Error(),
+		}
+	}
+	return uc.send(user)
+}
+
+// ResendVerification implements VerificationUseCase.ResendVerification
+func (uc *VerificationUseCase) ResendVerification(email string) {
+	user, err := uc.repository.FindByField("email", email)
+	if err != nil {
+		return
+	}
+	// Failures are only logged: the answer must be the same whether or not the account exists
+	if errRes := uc.send(user); errRes != nil {
+		log.Printf("verification email for user %d not sent: %s", user.ID, errRes.Error)
+	}
+}
+
+// VerifyEmail implements VerificationUseCase.VerifyEmail
+func (uc *VerificationUseCase) VerifyEmail(token string) *response.ErrorResponse {
+	invalid := &response.ErrorResponse{
+		Error: errInvalidVerificationToken.Error(),
+	}
+
+	userID, expiresAt, ok := parseVerificationToken(token)
+	if !ok || !uc.clock.Now().Before(expiresAt) {
+		return invalid
+	}
+
+	user, err := uc.repository.GetByID(userID)
+	if err != nil {
+		if err == types.ErrNotFound {
+			return invalid
+		}
+		return &response.ErrorResponse{
+			Error: fmt.Errorf("error fetching account: %w", err).Error(),
+		}
+	}
+
+	// The signature covers the email and status, so the token dies once the account
+	// is activated or its email changes
+	if !awaitingVerification(user.Status) || !hmac.Equal([]byte(token), []byte(uc.sign(user, expiresAt))) {
+		return invalid
+	}
+
+	user.Status = types.Active
+	if _, err := uc.repository.Update(user); err != nil {
+		return &response.ErrorResponse{
+			Error: fmt.Errorf("error activating account: %w", err).Error(),
+		}
+	}
+	return nil
+}
+
+// send emails a fresh verification link to the user
+func (uc *VerificationUseCase) send(user *db.User) *response.ErrorResponse {
+	if !awaitingVerification(user.Status) {
+		return &response.ErrorResponse{
+			Error: errors.New("account already verified").Error(),
+		}
+	}
+
+	token := uc.sign(user, uc.clock.Now().Add(uc.ttl))
+	link := uc.verifyURL + "?token=" + url.QueryEscape(token)
+
+	err := uc.mailer.Send(ports.MailMessage{
+		To:      user.Email,
+		Subject: "Confirm your email address",
+		Body: fmt.Sprintf("Hi %s,\n\nOpen this link to activate your account:\n\n%s\n\nThe link expires in %s. If you didn't sign up, ignore this email.\n",
+			user.Nickname, link, uc.ttl),
+	})
+	if err != nil {
+		return &response.ErrorResponse{
+			Error: fmt.Errorf("error sending verification email: %w", err).Error(),
+		}
+	}
+	return nil
+}
+
+// sign builds the token "<user id>.<expiry>.<signature>" for the current email and status of the user
+func (uc *VerificationUseCase) sign(user *db.User, expiresAt time.Time) string {
+	payload := strconv.Itoa(user.ID) + "." + strconv.FormatInt(expiresAt.Unix(), 10)
+
+	mac := hmac.New(sha256.New, uc.secret)
+	mac.Write([]byte("verify-email|" + payload + "|" + user.Email + "|" + string(user.Status)))
+	return payload + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// parseVerificationToken reads the user ID and expiry of a token without checking its signature
+func parseVerificationToken(token string) (int, time.Time, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return 0, time.Time{}, false
+	}
+	userID, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, time.Time{}, false
+	}
+	expires, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, time.Time{}, false
+	}
+	return userID, time.Unix(expires, 0), true
+}
+
+// awaitingVerification reports whether an account status still needs its email verified
+func awaitingVerification(status types.AccountStatus) bool {
+	return status == types.Inactive || status == types.Pending
+}
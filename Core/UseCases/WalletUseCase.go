@@ -10,17 +10,24 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 )
 
 // WalletUseCase implements the WalletUseCase interface
 type WalletUseCase struct {
 	repository ports.Repository[db.Wallet, int]
+	unitOfWork ports.UnitOfWork
+	rates      ports.ExchangeRateProvider
 }
 
-// NewWalletUseCase creates a new instance of WalletUseCase
-func NewWalletUseCase(repo ports.Repository[db.Wallet, int]) ports.WalletUseCase {
+// NewWalletUseCase creates a new instance of WalletUseCase.
+// Writes that touch the wallet and its ledger run inside unitOfWork.
+// rates may be nil, in which case balances can't be converted to a reporting currency.
+func NewWalletUseCase(repo ports.Repository[db.Wallet, int], unitOfWork ports.UnitOfWork, rates ports.ExchangeRateProvider) ports.WalletUseCase {
 	return &WalletUseCase{
 		repository: repo,
+		unitOfWork: unitOfWork,
+		rates:      rates,
 	}
 }
 
@@ -39,10 +46,15 @@ func (uc *WalletUseCase) CreateWallet(request dtos.CreateWalletRequest) (*db.Wal
 	// 	return nil, errors.New("invalid user ID")
 	// }
 
-	success, error := validators.ValidateWallet(request)
+	request.Currency = strings.ToUpper(strings.TrimSpace(request.Currency))
+	if request.Currency == "" {
+		request.Currency = types.DefaultCurrency
+	}
+
+	success, validationErrors := validators.ValidateWallet(request)
 	if !success {
 		return nil, &response.ErrorResponse{
-			Error: strings.Join(*error, " \n"),
+			Error: strings.Join(*validationErrors, " \n"),
 		}
 	}
 
@@ -63,16 +75,34 @@ func (uc *WalletUseCase) CreateWallet(request dtos.CreateWalletRequest) (*db.Wal
 	}
 
 	wallet := db.Wallet{
-		Name:    request.Name,
-		Type:    request.WalletType,
-		Balance: request.Balance,
-		UserID:  request.UserID,
+		Name:     request.Name,
+		Type:     request.WalletType,
+		Balance:  request.Balance.WithCurrency(request.Currency),
+		Currency: request.Currency,
+		UserID:   request.UserID,
 	}
-	result, err := uc.repository.Create(&wallet)
 
+	// The wallet and its opening entry are stored together or not at all
+	var result *db.Wallet
+	err = uc.unitOfWork.Do(func(repos ports.UnitOfWorkRepositories) error {
+		created, err := repos.Wallets.Create(&wallet)
+		if err != nil {
+			return errors.New("a wallet with this name already exists for this user")
+		}
+
+		// The opening balance is the first entry of the wallet ledger
+		if !created.Balance.IsZero() {
+			opening := newLedgerEntry(created.ID, created.Balance, "Opening balance")
+			if _, err := repos.Transactions.Create(&opening); err != nil {
+				return fmt.Errorf("error recording opening balance: %w", err)
+			}
+		}
+		result = created
+		return nil
+	})
 	if err != nil {
 		return nil, &response.ErrorResponse{
-			Error: errors.New("a wallet with this name already exists for this user").Error(),
+			Error: err.Error(),
 		}
 	}
 
@@ -80,7 +110,7 @@ func (uc *WalletUseCase) CreateWallet(request dtos.CreateWalletRequest) (*db.Wal
 }
 
 // UpdateWallet implements WalletUseCase.UpdateWallet
-func (uc *WalletUseCase) UpdateWallet(request dtos.UpdateWalletRequest) (*db.Wallet, *response.ErrorResponse) {
+func (uc *WalletUseCase) UpdateWallet(userID int, request dtos.UpdateWalletRequest) (*db.Wallet, *response.ErrorResponse) {
 	// Input validation
 	// if request.WalletID <= 0 {
 	// 	return nil, errors.New("invalid wallet ID")
@@ -94,6 +124,13 @@ func (uc *WalletUseCase) UpdateWallet(request dtos.UpdateWalletRequest) (*db.Wal
 		}
 	}
 
+	// Wallets of other users are reported as missing
+	if existingWallet.UserID != userID {
+		return nil, &response.ErrorResponse{
+			Error: errors.New("wallet not found").Error(),
+		}
+	}
+
 	// Get existing wallet
 	//existingWallet, err := uc.repository.FindByField("id", request.WalletID)
 	// if err != nil {
@@ -107,9 +144,7 @@ func (uc *WalletUseCase) UpdateWallet(request dtos.UpdateWalletRequest) (*db.Wal
 	// 	}
 	// }
 
-	// Update fields if provided
-	updated := false
-
+	// Check the new name before locking anything
 	if request.Name != "" && request.Name != existingWallet.Name {
 		// Check if new name is already taken by another wallet of the same user
 		existingWallets, err := uc.repository.GetAll()
@@ -126,31 +161,67 @@ func (uc *WalletUseCase) UpdateWallet(request dtos.UpdateWalletRequest) (*db.Wal
 				}
 			}
 		}
-
-		existingWallet.Name = request.Name
-		updated = true
 	}
 
-	if request.WalletType != nil && request.WalletType != &existingWallet.Type {
-		existingWallet.Type = *request.WalletType
-		updated = true
+	if request.Balance != nil && request.Balance.IsNegative() {
+		return nil, &response.ErrorResponse{
+			Error: errors.New("balance cannot be negative").Error(),
+		}
 	}
 
-	if request.Balance != nil && *request.Balance != existingWallet.Balance {
-		if *request.Balance < 0 {
-			return nil, &response.ErrorResponse{
-				Error: errors.New("balance cannot be negative").Error(),
+	// The wallet is read again locked, so the adjustment is computed from the balance it is
+	// written over; the adjustment entry and the new balance are stored together or not at all
+	var result *db.Wallet
+	errorUpdate := uc.unitOfWork.Do(func(repos ports.UnitOfWorkRepositories) error {
+		wallet, err := lockWallet(repos, existingWallet.ID)
+		if err != nil {
+			return err
+		}
+
+		// Update fields if provided
+		updated := false
+
+		if request.Name != "" && request.Name != wallet.Name {
+			wallet.Name = request.Name
+			updated = true
+		}
+
+		if request.WalletType != nil && *request.WalletType != wallet.Type {
+			wallet.Type = *request.WalletType
+			updated = true
+		}
+
+		if request.Balance != nil {
+			// A balance without a currency is in the wallet's; another currency is not converted
+			if request.Balance.Currency != "" && request.Balance.Currency != wallet.Currency {
+				return fmt.Errorf("%w: %s and %s", types.ErrCurrencyMismatch, request.Balance.Currency, wallet.Currency)
+			}
+			balance := request.Balance.WithCurrency(wallet.Currency)
+
+			if balance.Minor != wallet.Balance.Minor {
+				// Setting the balance directly is recorded as an adjustment so the ledger still explains it
+				delta := types.NewMoney(balance.Minor-wallet.Balance.Minor, wallet.Currency)
+				entry := newLedgerEntry(wallet.ID, delta, "Balance adjustment")
+				if _, err := repos.Transactions.Create(&entry); err != nil {
+					return fmt.Errorf("error recording balance adjustment: %w", err)
+				}
+				wallet.Balance = balance
+				updated = true
 			}
 		}
-		existingWallet.Balance = *request.Balance
-		updated = true
-	}
 
-	if !updated {
-		return existingWallet, nil // No changes made
-	}
+		if !updated {
+			result = wallet // No changes made
+			return nil
+		}
 
-	result, errorUpdate := uc.repository.Update(existingWallet)
+		updatedWallet, err := repos.Wallets.Update(wallet)
+		if err != nil {
+			return err
+		}
+		result = updatedWallet
+		return nil
+	})
 
 	if errorUpdate != nil {
 		return nil, &response.ErrorResponse{
@@ -162,19 +233,22 @@ func (uc *WalletUseCase) UpdateWallet(request dtos.UpdateWalletRequest) (*db.Wal
 }
 
 // DeleteWallet implements WalletUseCase.DeleteWallet
-func (uc *WalletUseCase) DeleteWallet(walletID int) error {
+func (uc *WalletUseCase) DeleteWallet(userID int, walletID int) error {
 	if walletID <= 0 {
 		return errors.New("invalid wallet ID")
 	}
 
-	// Check if wallet exists
-	_, err := uc.repository.GetByID(walletID)
+	// Check if wallet exists; wallets of other users are reported as missing
+	wallet, err := uc.repository.GetByID(walletID)
 	if err != nil {
 		if err == types.ErrNotFound {
 			return errors.New("wallet not found")
 		}
 		return fmt.Errorf("error fetching wallet: %w", err)
 	}
+	if wallet.UserID != userID {
+		return errors.New("wallet not found")
+	}
 
 	// In a real application, you might want to check if the wallet has any transactions
 	// before allowing deletion
@@ -182,56 +256,112 @@ func (uc *WalletUseCase) DeleteWallet(walletID int) error {
 	return uc.repository.Delete(walletID)
 }
 
-func (uc *WalletUseCase) GetUserWallet(id int, email string) (*response.UserWalletResponse, *response.ErrorResponse) {
-	data, err := uc.repository.Query("id,name,type,balance,user:users!inner(email)", ports.QueryOptions{
-		Filters: []ports.Filter{
-			ports.Filter{
-				Field:    "users.email",
-				Operator: "eq",
-				Value:    email,
-			},
-		},
-	})
-	if err != nil {
+// GetUserWallet implements WalletUseCase.GetUserWallet
+func (uc *WalletUseCase) GetUserWallet(id int, email string, reportingCurrency string, asOf time.Time) (*response.UserWalletResponse, *response.ErrorResponse) {
+	reportingCurrency = strings.ToUpper(strings.TrimSpace(reportingCurrency))
+	if reportingCurrency != "" && uc.rates == nil {
 		return nil, &response.ErrorResponse{
-			Error: err.Error(),
+			Error: errors.New("currency conversion is not available").Error(),
 		}
 	}
+	if asOf.IsZero() {
+		asOf = time.Now()
+	}
 
-	var result response.UserWalletResponse
+	filters := []ports.Filter{}
+	if email != "" {
+		filters = append(filters, ports.Filter{
+			Field:    "users.email",
+			Operator: "eq",
+			Value:    email,
+		})
+	}
+	// Restricting to the owner keeps callers from reading someone else's wallets by email
+	if id > 0 {
+		filters = append(filters, ports.Filter{
+			Field:    "user_id",
+			Operator: "eq",
+			Value:    id,
+		})
+	}
+	if len(filters) == 0 {
+		return nil, &response.ErrorResponse{
+			Error: errors.New("a user ID or email is required").Error(),
+		}
+	}
 
-	// Type assert the result to *ports.UserWallet
-	wallet, ok := data.([]db.Wallet)
-	if !ok {
+	page, err := uc.repository.Query("id,name,type,balance,currency,user:users!inner(email),transactions(*)", ports.QueryOptions{
+		Filters: filters,
+	})
+	if err != nil {
 		return nil, &response.ErrorResponse{
-			Error: errors.New("unexpected type returned from repository").Error(),
+			Error: err.Error(),
 		}
 	}
 
-	result = response.UserWalletResponse{
-		Email: email,
+	wallet := page.Items
+
+	result := response.UserWalletResponse{
+		Email:  email,
+		Totals: map[string]types.Money{},
+	}
+	if reportingCurrency != "" {
+		total := types.NewMoney(0, reportingCurrency)
+		result.ReportingCurrency = reportingCurrency
+		result.Total = &total
+		result.RatesAsOf = &asOf
 	}
 
 	if len(wallet) == 0 {
-		result.Wallets = []struct {
-			Name    string           `json:"name"`
-			Type    types.WalletType `json:"type"`
-			Balance float64          `json:"balance"`
-		}{}
+		result.Wallets = []response.WalletSummary{}
 		return &result, nil
 	}
 
+	// Each currency is looked up once, every wallet in it uses the same rate
+	rates := map[string]types.Rate{}
+
 	result.Email = wallet[0].User.Email
 	for _, w := range wallet {
-		result.Wallets = append(result.Wallets, struct {
-			Name    string           "json:\"name\""
-			Type    types.WalletType "json:\"type\""
-			Balance float64          "json:\"balance\""
-		}{
-			Name:    w.Name,
-			Type:    w.Type,
-			Balance: w.Balance,
-		})
+		transactions := w.Transactions
+		if transactions == nil {
+			transactions = []db.Transaction{}
+		}
+		currency := w.Currency
+		if currency == "" {
+			currency = types.DefaultCurrency
+		}
+		balance := w.Balance.WithCurrency(currency)
+
+		// Balances of different currencies never mix, they are summed separately
+		result.Totals[currency] = types.NewMoney(result.Totals[currency].Minor+balance.Minor, currency)
+
+		summary := response.WalletSummary{
+			ID:           w.ID,
+			Name:         w.Name,
+			Type:         w.Type,
+			Balance:      balance,
+			Currency:     currency,
+			Transactions: transactions,
+		}
+
+		if reportingCurrency != "" {
+			rate, found := rates[currency]
+			if !found {
+				exchangeRate, errRate := uc.rates.GetRate(currency, reportingCurrency, asOf)
+				if errRate != nil {
+					return nil, &response.ErrorResponse{
+						Error: fmt.Errorf("error converting %s to %s: %w", currency, reportingCurrency, errRate).Error(),
+					}
+				}
+				rate = exchangeRate.Rate
+				rates[currency] = rate
+			}
+			converted := balance.Convert(rate, reportingCurrency)
+			summary.ConvertedBalance = &converted
+			result.Total.Minor += converted.Minor
+		}
+
+		result.Wallets = append(result.Wallets, summary)
 	}
 
 	return &result, nil
@@ -0,0 +1,65 @@
+package exporters
+
+import (
+	"Financial/Core/Models/db"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+)
+
+// csvWriter writes one row per movement, repeating the wallet columns on each row
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+// Begin implements Writer.Begin
+func (cw *csvWriter) Begin() error {
+	return cw.w.Write([]string{
+		"wallet_id", "wallet_name", "wallet_type", "currency",
+		"transaction_id", "date", "type", "amount", "description", "category_id",
+	})
+}
+
+// Wallet implements Writer.Wallet
+func (cw *csvWriter) Wallet(wallet db.Wallet) error {
+	return nil
+}
+
+// Transaction implements Writer.Transaction
+func (cw *csvWriter) Transaction(wallet db.Wallet, transaction db.Transaction) error {
+	categoryID := ""
+	if transaction.CategoryID != nil {
+		categoryID = strconv.Itoa(*transaction.CategoryID)
+	}
+
+	err := cw.w.Write([]string{
+		strconv.Itoa(wallet.ID),
+		wallet.Name,
+		string(wallet.Type),
+		wallet.Currency,
+		strconv.Itoa(transaction.ID),
+		transaction.CreatedAt.UTC().Format(time.RFC3339),
+		string(transaction.Type),
+		// Signed, so the column adds up to the change of the balance
+		transaction.SignedAmount().Decimal(),
+		transaction.Description,
+		categoryID,
+	})
+	if err != nil {
+		return err
+	}
+	// Flushing each row keeps the response streaming
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+// End implements Writer.End
+func (cw *csvWriter) End() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
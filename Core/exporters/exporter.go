@@ -0,0 +1,73 @@
+// Package exporters writes wallets and their movements as CSV, JSON Lines or OFX.
+// Writers stream: each movement is written as soon as it is received.
+package exporters
+
+import (
+	"Financial/Core/Models/db"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	FormatCSV   = "csv"
+	FormatJSONL = "jsonl"
+	FormatOFX   = "ofx"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported export format")
+
+// Writer receives the wallets in order, each one followed by its movements
+type Writer interface {
+	// Begin writes the header of the document
+	Begin() error
+
+	// Wallet starts the section of a wallet
+	Wallet(wallet db.Wallet) error
+
+	// Transaction writes a movement of the last wallet started
+	Transaction(wallet db.Wallet, transaction db.Transaction) error
+
+	// End closes the last wallet and the document
+	End() error
+}
+
+// Format describes how an export is served
+type Format struct {
+	Name        string
+	ContentType string
+	Extension   string
+}
+
+// formats lists the supported formats; "json" is accepted as an alias of JSON Lines
+var formats = map[string]Format{
+	FormatCSV:   {Name: FormatCSV, ContentType: "text/csv; charset=utf-8", Extension: "csv"},
+	FormatJSONL: {Name: FormatJSONL, ContentType: "application/x-ndjson", Extension: "jsonl"},
+	"json":      {Name: FormatJSONL, ContentType: "application/x-ndjson", Extension: "jsonl"},
+	FormatOFX:   {Name: FormatOFX, ContentType: "application/x-ofx", Extension: "ofx"},
+}
+
+// LookupFormat resolves a format name, defaulting to CSV
+func LookupFormat(name string) (Format, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		name = FormatCSV
+	}
+	format, ok := formats[name]
+	if !ok {
+		return Format{}, fmt.Errorf("%w: %q (use csv, jsonl or ofx)", ErrUnsupportedFormat, name)
+	}
+	return format, nil
+}
+
+// NewWriter returns the writer of a format on w
+func NewWriter(format Format, w io.Writer) Writer {
+	switch format.Name {
+	case FormatJSONL:
+		return &jsonlWriter{w: w}
+	case FormatOFX:
+		return &ofxWriter{w: w}
+	}
+	return newCSVWriter(w)
+}
@@ -0,0 +1,49 @@
+package exporters
+
+import (
+	"Financial/Core/Models/db"
+	"encoding/json"
+	"io"
+)
+
+// jsonlWriter writes one JSON object per line: a "wallet" record followed by its "transaction" records
+type jsonlWriter struct {
+	w io.Writer
+}
+
+type jsonlRecord struct {
+	Record      string          `json:"record"`
+	Wallet      *db.Wallet      `json:"wallet,omitempty"`
+	Transaction *db.Transaction `json:"transaction,omitempty"`
+}
+
+func (jw *jsonlWriter) write(record jsonlRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	_, err = jw.w.Write(append(line, '\n'))
+	return err
+}
+
+// Begin implements Writer.Begin
+func (jw *jsonlWriter) Begin() error {
+	return nil
+}
+
+// Wallet implements Writer.Wallet
+func (jw *jsonlWriter) Wallet(wallet db.Wallet) error {
+	wallet.User = nil
+	wallet.Transactions = nil
+	return jw.write(jsonlRecord{Record: "wallet", Wallet: &wallet})
+}
+
+// Transaction implements Writer.Transaction
+func (jw *jsonlWriter) Transaction(wallet db.Wallet, transaction db.Transaction) error {
+	return jw.write(jsonlRecord{Record: "transaction", Transaction: &transaction})
+}
+
+// End implements Writer.End
+func (jw *jsonlWriter) End() error {
+	return nil
+}
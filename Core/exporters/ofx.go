@@ -0,0 +1,106 @@
+package exporters
+
+import (
+	"Financial/Core/Models/db"
+	"Financial/Core/types"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const ofxHeader = `<?xml version="1.0" encoding="UTF-8" standalone="no"?>
+<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>
+<OFX>
+<BANKMSGSRSV1>
+`
+
+// ofxWriter writes an OFX 2.2 document with one bank statement per wallet
+type ofxWriter struct {
+	w    io.Writer
+	open *db.Wallet
+}
+
+func ofxDate(t time.Time) string {
+	return t.UTC().Format("20060102150405")
+}
+
+func ofxText(value string) string {
+	var escaped strings.Builder
+	_ = xml.EscapeText(&escaped, []byte(value))
+	return escaped.String()
+}
+
+// Begin implements Writer.Begin
+func (ow *ofxWriter) Begin() error {
+	_, err := io.WriteString(ow.w, ofxHeader)
+	return err
+}
+
+// Wallet implements Writer.Wallet
+func (ow *ofxWriter) Wallet(wallet db.Wallet) error {
+	if err := ow.closeWallet(); err != nil {
+		return err
+	}
+
+	currency := wallet.Currency
+	if currency == "" {
+		currency = types.DefaultCurrency
+	}
+	_, err := fmt.Fprintf(ow.w, `<STMTTRNRS>
+<TRNUID>%d</TRNUID>
+<STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>
+<STMTRS>
+<CURDEF>%s</CURDEF>
+<BANKACCTFROM><BANKID>FINANCIAL</BANKID><ACCTID>%d</ACCTID><ACCTTYPE>CHECKING</ACCTTYPE></BANKACCTFROM>
+<BANKTRANLIST>
+`, wallet.ID, currency, wallet.ID)
+	if err != nil {
+		return err
+	}
+	ow.open = &wallet
+	return nil
+}
+
+// Transaction implements Writer.Transaction
+func (ow *ofxWriter) Transaction(wallet db.Wallet, transaction db.Transaction) error {
+	transactionType := "CREDIT"
+	if transaction.Type == types.Expense {
+		transactionType = "DEBIT"
+	}
+	_, err := fmt.Fprintf(ow.w, `<STMTTRN>
+<TRNTYPE>%s</TRNTYPE>
+<DTPOSTED>%s</DTPOSTED>
+<TRNAMT>%s</TRNAMT>
+<FITID>%s</FITID>
+<NAME>%s</NAME>
+</STMTTRN>
+`, transactionType, ofxDate(transaction.CreatedAt), transaction.SignedAmount().Decimal(),
+		strconv.Itoa(transaction.ID), ofxText(transaction.Description))
+	return err
+}
+
+// closeWallet ends the statement of the open wallet with its current balance
+func (ow *ofxWriter) closeWallet() error {
+	if ow.open == nil {
+		return nil
+	}
+	_, err := fmt.Fprintf(ow.w, `</BANKTRANLIST>
+<LEDGERBAL><BALAMT>%s</BALAMT><DTASOF>%s</DTASOF></LEDGERBAL>
+</STMTRS>
+</STMTTRNRS>
+`, ow.open.Balance.Decimal(), ofxDate(time.Now()))
+	ow.open = nil
+	return err
+}
+
+// End implements Writer.End
+func (ow *ofxWriter) End() error {
+	if err := ow.closeWallet(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(ow.w, "</BANKMSGSRSV1>\n</OFX>\n")
+	return err
+}
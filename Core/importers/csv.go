@@ -0,0 +1,151 @@
+package importers
+
+import (
+	dtos "Financial/Core/Models/dtos/Request"
+	"Financial/Core/types"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// defaultDateLayouts are tried when the mapping has no date format
+var defaultDateLayouts = []string{"2006-01-02", "2006/01/02", "01/02/2006", "1/2/2006", "2006-01-02T15:04:05Z07:00"}
+
+type csvParser struct {
+	mapping dtos.ColumnMapping
+}
+
+// Parse implements Parser.Parse
+func (p *csvParser) Parse(r io.Reader) ([]Line, error) {
+	mapping := p.mapping
+	if mapping.Date == "" || mapping.Description == "" {
+		return nil, errors.New("the column mapping needs the date and description columns")
+	}
+	if mapping.Amount == "" && mapping.Debit == "" && mapping.Credit == "" {
+		return nil, errors.New("the column mapping needs an amount column or debit/credit columns")
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if mapping.Delimiter != "" {
+		delimiter, size := utf8.DecodeRuneInString(mapping.Delimiter)
+		if mapping.Delimiter == `\t` {
+			delimiter, size = '\t', 2
+		}
+		if size != len(mapping.Delimiter) {
+			return nil, fmt.Errorf("invalid delimiter %q", mapping.Delimiter)
+		}
+		reader.Comma = delimiter
+	}
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+
+	var header []string
+	first := 0
+	if !mapping.NoHeader && len(records) > 0 {
+		header = records[0]
+		first = 1
+	}
+
+	column := func(name string) (int, error) {
+		if name == "" {
+			return -1, nil
+		}
+		for i, title := range header {
+			if strings.EqualFold(strings.TrimSpace(title), strings.TrimSpace(name)) {
+				return i, nil
+			}
+		}
+		if index, err := strconv.Atoi(name); err == nil && index >= 0 {
+			return index, nil
+		}
+		return -1, fmt.Errorf("column %q not found", name)
+	}
+
+	dateCol, err := column(mapping.Date)
+	if err != nil {
+		return nil, err
+	}
+	descriptionCol, err := column(mapping.Description)
+	if err != nil {
+		return nil, err
+	}
+	amountCol, err := column(mapping.Amount)
+	if err != nil {
+		return nil, err
+	}
+	debitCol, err := column(mapping.Debit)
+	if err != nil {
+		return nil, err
+	}
+	creditCol, err := column(mapping.Credit)
+	if err != nil {
+		return nil, err
+	}
+
+	layouts := defaultDateLayouts
+	if mapping.DateFormat != "" {
+		layouts = []string{mapping.DateFormat}
+	}
+
+	lines := []Line{}
+	for i := first; i < len(records); i++ {
+		record := records[i]
+		number := i + 1
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+
+		field := func(index int) string {
+			if index < 0 || index >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[index])
+		}
+
+		date, err := parseDate(field(dateCol), layouts...)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", number, err)
+		}
+
+		var amount types.Money
+		if amountCol >= 0 {
+			amount, err = parseAmount(field(amountCol), mapping.DecimalComma)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", number, err)
+			}
+		} else {
+			// Debit and credit are usually both positive, with one of them empty
+			for _, side := range []struct {
+				index int
+				sign  int64
+			}{{creditCol, 1}, {debitCol, -1}} {
+				value := field(side.index)
+				if value == "" {
+					continue
+				}
+				part, err := parseAmount(value, mapping.DecimalComma)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: %w", number, err)
+				}
+				amount.Minor += side.sign * part.Abs().Minor
+			}
+		}
+
+		lines = append(lines, Line{
+			Number:      number,
+			Date:        date,
+			Amount:      amount,
+			Description: field(descriptionCol),
+		})
+	}
+	return lines, nil
+}
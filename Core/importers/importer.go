@@ -0,0 +1,108 @@
+// Package importers turns bank statements (CSV, OFX and QIF) into statement lines
+// that can be recorded as wallet transactions.
+package importers
+
+import (
+	dtos "Financial/Core/Models/dtos/Request"
+	"Financial/Core/types"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	FormatCSV = "csv"
+	FormatOFX = "ofx"
+	FormatQIF = "qif"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported statement format")
+
+// Line is one movement read from a statement
+type Line struct {
+	// Number is the position of the movement in the file, for error reporting
+	Number int
+
+	Date time.Time
+
+	// Amount is signed: positive for money in, negative for money out. Its currency is not set.
+	Amount types.Money
+
+	Description string
+}
+
+// Parser reads the movements of a statement
+type Parser interface {
+	Parse(r io.Reader) ([]Line, error)
+}
+
+// NewParser returns the parser of a format; the mapping is only used by CSV
+func NewParser(format string, mapping dtos.ColumnMapping) (Parser, error) {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case FormatCSV:
+		return &csvParser{mapping: mapping}, nil
+	case FormatOFX:
+		return &ofxParser{}, nil
+	case FormatQIF:
+		return &qifParser{}, nil
+	}
+	return nil, fmt.Errorf("%w: %q (use csv, ofx or qif)", ErrUnsupportedFormat, format)
+}
+
+// Fingerprint identifies a movement by its day, signed amount and description, so the same
+// movement imported twice (or typed by hand before) is recognized as a duplicate.
+func Fingerprint(date time.Time, amount types.Money, description string) string {
+	normalized := strings.Join(strings.Fields(strings.ToLower(description)), " ")
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%d|%s", date.Format("2006-01-02"), amount.Minor, normalized)))
+	return hex.EncodeToString(sum[:])
+}
+
+// parseAmount reads amounts as printed on statements: "1,234.56", "$ -12.00", "(12.00)"
+// or, with decimalComma, "1.234,56".
+func parseAmount(value string, decimalComma bool) (types.Money, error) {
+	s := strings.TrimSpace(value)
+	negative := false
+	if strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		negative = true
+		s = s[1 : len(s)-1]
+	}
+
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case r >= '0' && r <= '9', r == '-', r == '+', r == '.', r == ',':
+			return r
+		}
+		// Currency symbols, codes and spaces
+		return -1
+	}, s)
+	if decimalComma {
+		s = strings.ReplaceAll(s, ".", "")
+		s = strings.ReplaceAll(s, ",", ".")
+	} else {
+		s = strings.ReplaceAll(s, ",", "")
+	}
+
+	amount, err := types.ParseMoney(s, "")
+	if err != nil {
+		return types.Money{}, err
+	}
+	if negative {
+		amount = amount.Negate()
+	}
+	return amount, nil
+}
+
+// parseDate tries the layouts in order
+func parseDate(value string, layouts ...string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range layouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", value)
+}
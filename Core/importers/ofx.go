@@ -0,0 +1,81 @@
+package importers
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ofxTag matches an opening tag and the value that follows it. It covers both the
+// SGML flavour of OFX 1.x (unclosed value tags) and the XML of OFX 2.x.
+var ofxTag = regexp.MustCompile(`<(/?)([A-Za-z0-9.]+)>([^<]*)`)
+
+type ofxParser struct{}
+
+// Parse implements Parser.Parse
+func (p *ofxParser) Parse(r io.Reader) ([]Line, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := []Line{}
+	var current map[string]string
+	for _, match := range ofxTag.FindAllStringSubmatch(string(content), -1) {
+		closing, tag := match[1] == "/", strings.ToUpper(match[2])
+		value := html.UnescapeString(strings.TrimSpace(match[3]))
+
+		switch {
+		case tag == "STMTTRN" && !closing:
+			current = map[string]string{}
+		case tag == "STMTTRN" && closing:
+			if current == nil {
+				continue
+			}
+			line, err := ofxLine(current, len(lines)+1)
+			if err != nil {
+				return nil, err
+			}
+			lines = append(lines, line)
+			current = nil
+		case current != nil && !closing:
+			current[tag] = value
+		}
+	}
+	return lines, nil
+}
+
+func ofxLine(fields map[string]string, number int) (Line, error) {
+	date, err := parseOFXDate(fields["DTPOSTED"])
+	if err != nil {
+		return Line{}, fmt.Errorf("transaction %d: %w", number, err)
+	}
+	amount, err := parseAmount(fields["TRNAMT"], false)
+	if err != nil {
+		return Line{}, fmt.Errorf("transaction %d: %w", number, err)
+	}
+
+	description := fields["NAME"]
+	if memo := fields["MEMO"]; memo != "" && !strings.EqualFold(memo, description) {
+		description = strings.TrimSpace(description + " " + memo)
+	}
+
+	return Line{
+		Number:      number,
+		Date:        date,
+		Amount:      amount,
+		Description: description,
+	}, nil
+}
+
+// parseOFXDate reads YYYYMMDD[HHMMSS[.XXX]][[offset:TZ]], keeping only the day
+func parseOFXDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if len(value) < 8 {
+		return time.Time{}, fmt.Errorf("invalid date %q", value)
+	}
+	return parseDate(value[:8], "20060102")
+}
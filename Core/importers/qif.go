@@ -0,0 +1,83 @@
+package importers
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// qifDateLayouts covers the usual QIF dates: 12/31/2024, 12/31/24, 12/31'24 and ISO
+var qifDateLayouts = []string{"1/2/2006", "1/2/06", "2006-01-02", "1-2-2006", "1-2-06"}
+
+type qifParser struct{}
+
+// Parse implements Parser.Parse
+func (p *qifParser) Parse(r io.Reader) ([]Line, error) {
+	scanner := bufio.NewScanner(r)
+
+	lines := []Line{}
+	fields := map[byte]string{}
+	number := 0
+	start := 0
+	for scanner.Scan() {
+		number++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "!") {
+			continue
+		}
+		if len(fields) == 0 {
+			start = number
+		}
+
+		if text[0] != '^' {
+			// Split lines keep their first value; memos of the splits are not needed
+			if _, ok := fields[text[0]]; !ok {
+				fields[text[0]] = strings.TrimSpace(text[1:])
+			}
+			continue
+		}
+
+		line, err := qifLine(fields, start)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, line)
+		fields = map[byte]string{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(fields) > 0 {
+		return nil, fmt.Errorf("line %d: record is not terminated with ^", start)
+	}
+	return lines, nil
+}
+
+func qifLine(fields map[byte]string, number int) (Line, error) {
+	date, err := parseDate(strings.ReplaceAll(fields['D'], "'", "/"), qifDateLayouts...)
+	if err != nil {
+		return Line{}, fmt.Errorf("line %d: %w", number, err)
+	}
+
+	value := fields['T']
+	if value == "" {
+		value = fields['U']
+	}
+	amount, err := parseAmount(value, false)
+	if err != nil {
+		return Line{}, fmt.Errorf("line %d: %w", number, err)
+	}
+
+	description := fields['P']
+	if memo := fields['M']; memo != "" && !strings.EqualFold(memo, description) {
+		description = strings.TrimSpace(description + " " + memo)
+	}
+
+	return Line{
+		Number:      number,
+		Date:        date,
+		Amount:      amount,
+		Description: description,
+	}, nil
+}
@@ -0,0 +1,229 @@
+// Package oidc implements the client side of OpenID Connect logins: provider discovery,
+// the authorization code flow with PKCE (RFC 7636) and ID token verification against
+// the provider's published keys.
+package oidc
+
+import (
+	"Financial/Core/ports"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// discoveryPath is where providers publish their metadata, relative to the issuer
+const discoveryPath = "/.well-known/openid-configuration"
+
+// clockSkew is the drift tolerated between our clock and the provider's when checking exp and iat
+const clockSkew = time.Minute
+
+// ErrInvalidIDToken is returned when the ID token fails any check
+var ErrInvalidIDToken = errors.New("invalid ID token")
+
+// Config identifies the provider and this application as one of its clients
+type Config struct {
+	// Issuer is the provider URL, exactly as it appears in its ID tokens
+	Issuer string
+
+	// ClientID and ClientSecret are the credentials registered at the provider
+	ClientID     string
+	ClientSecret string
+
+	// RedirectURL is the callback registered at the provider
+	RedirectURL string
+
+	// Scopes are requested besides "openid"; "email" and "profile" when empty
+	Scopes []string
+}
+
+// metadata holds the fields of the discovery document this package uses
+type metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Provider is a discovered OpenID Connect provider. It implements ports.IdentityProvider
+type Provider struct {
+	config   Config
+	metadata metadata
+	client   *http.Client
+	now      func() time.Time
+
+	mu   sync.Mutex
+	keys map[string]publicKey
+}
+
+// Discover fetches the metadata of the provider and checks that it belongs to the configured issuer.
+// A nil client uses one with a 10 second timeout.
+func Discover(config Config, client *http.Client) (*Provider, error) {
+	if config.Issuer == "" || config.ClientID == "" || config.RedirectURL == "" {
+		return nil, errors.New("issuer, client ID and redirect URL are required")
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	provider := &Provider{
+		config: config,
+		client: client,
+		now:    time.Now,
+		keys:   map[string]publicKey{},
+	}
+	if err := provider.getJSON(strings.TrimSuffix(config.Issuer, "/")+discoveryPath, &provider.metadata); err != nil {
+		return nil, fmt.Errorf("error discovering provider: %w", err)
+	}
+	// A document for another issuer would let that issuer's tokens in
+	if provider.metadata.Issuer != config.Issuer {
+		return nil, fmt.Errorf("provider reports issuer %q, expected %q", provider.metadata.Issuer, config.Issuer)
+	}
+	if provider.metadata.AuthorizationEndpoint == "" || provider.metadata.TokenEndpoint == "" || provider.metadata.JWKSURI == "" {
+		return nil, errors.New("provider metadata is missing an endpoint")
+	}
+	return provider, nil
+}
+
+// UseClock replaces the clock used to check the expiry of ID tokens
+func (p *Provider) UseClock(clock ports.Clock) {
+	p.now = clock.Now
+}
+
+// AuthCodeURL implements ports.IdentityProvider.AuthCodeURL
+func (p *Provider) AuthCodeURL(state string, nonce string, codeChallenge string) string {
+	scopes := p.config.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{"email", "profile"}
+	}
+
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.config.ClientID},
+		"redirect_uri":          {p.config.RedirectURL},
+		"scope":                 {strings.Join(append([]string{"openid"}, scopes...), " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {codeChallenge},
+		"code_challenge_method": {"S256"},
+	}
+
+	separator := "?"
+	if strings.Contains(p.metadata.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return p.metadata.AuthorizationEndpoint + separator + query.Encode()
+}
+
+// Identify implements ports.IdentityProvider.Identify
+func (p *Provider) Identify(code string, codeVerifier string, nonce string) (*ports.ExternalIdentity, error) {
+	rawIDToken, err := p.exchange(code, codeVerifier)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := p.verify(rawIDToken, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ports.ExternalIdentity{
+		Issuer:        claims.Issuer,
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: bool(claims.EmailVerified),
+		Name:          claims.Name,
+		GivenName:     claims.GivenName,
+		FamilyName:    claims.FamilyName,
+	}, nil
+}
+
+// exchange redeems the authorization code at the token endpoint and returns the ID token
+func (p *Provider) exchange(code string, codeVerifier string) (string, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.config.RedirectURL},
+		"code_verifier": {codeVerifier},
+		"client_id":     {p.config.ClientID},
+	}
+
+	req, err := http.NewRequest(http.MethodPost, p.metadata.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	// client_secret_basic, the default authentication method of the token endpoint
+	if p.config.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+	}
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error exchanging code: %w", err)
+	}
+	defer res.Body.Close()
+
+	var body struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(io.LimitReader(res.Body, 1<<20)).Decode(&body); err != nil {
+		return "", fmt.Errorf("error reading token response: %w", err)
+	}
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token endpoint answered %d: %s %s", res.StatusCode, body.Error, body.ErrorDescription)
+	}
+	if body.IDToken == "" {
+		return "", errors.New("token response has no ID token")
+	}
+	return body.IDToken, nil
+}
+
+// getJSON fetches a JSON document
+func (p *Provider) getJSON(address string, target any) error {
+	res, err := p.client.Get(address)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s answered %d", address, res.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(res.Body, 1<<20)).Decode(target)
+}
+
+// NewCodeVerifier creates a random PKCE code verifier (43 URL-safe characters)
+func NewCodeVerifier() (string, error) {
+	return randomString(32)
+}
+
+// NewState creates a random value for the state or nonce of a login
+func NewState() (string, error) {
+	return randomString(32)
+}
+
+// CodeChallenge is the S256 challenge of a code verifier
+func CodeChallenge(codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// randomString encodes size random bytes as unpadded base64url
+func randomString(size int) (string, error) {
+	raw := make([]byte, size)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
@@ -0,0 +1,274 @@
+package oidc
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// publicKey is a key of the provider's JWKS; exactly one field is set
+type publicKey struct {
+	rsa     *rsa.PublicKey
+	ecdsa   *ecdsa.PublicKey
+	ed25519 ed25519.PublicKey
+}
+
+// jwk is a JSON Web Key (RFC 7517) as published at the jwks_uri
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// audience accepts the aud claim as a single string or a list
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*a = list
+	return nil
+}
+
+// flexibleBool accepts true/false as booleans or strings; some providers send "true"
+type flexibleBool bool
+
+func (b *flexibleBool) UnmarshalJSON(data []byte) error {
+	switch strings.Trim(string(data), `"`) {
+	case "true":
+		*b = true
+	default:
+		*b = false
+	}
+	return nil
+}
+
+// claims are the ID token claims this package checks or returns
+type claims struct {
+	Issuer          string       `json:"iss"`
+	Subject         string       `json:"sub"`
+	Audience        audience     `json:"aud"`
+	AuthorizedParty string       `json:"azp"`
+	Expiry          int64        `json:"exp"`
+	IssuedAt        int64        `json:"iat"`
+	Nonce           string       `json:"nonce"`
+	Email           string       `json:"email"`
+	EmailVerified   flexibleBool `json:"email_verified"`
+	Name            string       `json:"name"`
+	GivenName       string       `json:"given_name"`
+	FamilyName      string       `json:"family_name"`
+}
+
+// verify checks the signature and claims of an ID token and returns its claims
+func (p *Provider) verify(rawIDToken string, nonce string) (*claims, error) {
+	parts := strings.Split(rawIDToken, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed token", ErrInvalidIDToken)
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("%w: bad header", ErrInvalidIDToken)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: bad signature encoding", ErrInvalidIDToken)
+	}
+
+	key, err := p.key(header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSignature(header.Alg, key, []byte(parts[0]+"."+parts[1]), signature); err != nil {
+		return nil, err
+	}
+
+	var c claims
+	if err := decodeSegment(parts[1], &c); err != nil {
+		return nil, fmt.Errorf("%w: bad payload", ErrInvalidIDToken)
+	}
+
+	now := p.now()
+	switch {
+	case c.Issuer != p.config.Issuer:
+		return nil, fmt.Errorf("%w: unexpected issuer %q", ErrInvalidIDToken, c.Issuer)
+	case !c.Audience.contains(p.config.ClientID):
+		return nil, fmt.Errorf("%w: not issued for this client", ErrInvalidIDToken)
+	case len(c.Audience) > 1 && c.AuthorizedParty != "" && c.AuthorizedParty != p.config.ClientID:
+		return nil, fmt.Errorf("%w: issued for another party", ErrInvalidIDToken)
+	case c.Expiry == 0 || now.After(time.Unix(c.Expiry, 0).Add(clockSkew)):
+		return nil, fmt.Errorf("%w: expired", ErrInvalidIDToken)
+	case c.IssuedAt != 0 && time.Unix(c.IssuedAt, 0).After(now.Add(clockSkew)):
+		return nil, fmt.Errorf("%w: issued in the future", ErrInvalidIDToken)
+	case subtle.ConstantTimeCompare([]byte(c.Nonce), []byte(nonce)) != 1:
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrInvalidIDToken)
+	case c.Subject == "":
+		return nil, fmt.Errorf("%w: no subject", ErrInvalidIDToken)
+	}
+	return &c, nil
+}
+
+// contains reports whether the audience includes the client
+func (a audience) contains(clientID string) bool {
+	for _, value := range a {
+		if value == clientID {
+			return true
+		}
+	}
+	return false
+}
+
+// key returns the signing key with the given ID. Unknown IDs reload the JWKS once,
+// which picks up keys the provider rotated in since the last fetch.
+func (p *Provider) key(kid string) (publicKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.lookup(kid); ok {
+		return key, nil
+	}
+	if err := p.loadKeys(); err != nil {
+		return publicKey{}, fmt.Errorf("error fetching provider keys: %w", err)
+	}
+	if key, ok := p.lookup(kid); ok {
+		return key, nil
+	}
+	return publicKey{}, fmt.Errorf("%w: unknown key %q", ErrInvalidIDToken, kid)
+}
+
+// lookup finds a key by ID; a token without kid is accepted only when the JWKS has a single key
+func (p *Provider) lookup(kid string) (publicKey, bool) {
+	if kid == "" {
+		if len(p.keys) == 1 {
+			for _, key := range p.keys {
+				return key, true
+			}
+		}
+		return publicKey{}, false
+	}
+	key, ok := p.keys[kid]
+	return key, ok
+}
+
+// loadKeys replaces the cached keys with the current JWKS of the provider
+func (p *Provider) loadKeys() error {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := p.getJSON(p.metadata.JWKSURI, &set); err != nil {
+		return err
+	}
+
+	keys := map[string]publicKey{}
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			// Keys of types we don't support are skipped rather than failing every login
+			continue
+		}
+		keys[k.Kid] = key
+	}
+	p.keys = keys
+	return nil
+}
+
+// publicKey decodes the key material of a JWK
+func (k jwk) publicKey() (publicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, errN := decodeBigInt(k.N)
+		e, errE := decodeBigInt(k.E)
+		if errN != nil || errE != nil || !e.IsInt64() {
+			return publicKey{}, fmt.Errorf("invalid RSA key %q", k.Kid)
+		}
+		return publicKey{rsa: &rsa.PublicKey{N: n, E: int(e.Int64())}}, nil
+	case "EC":
+		if k.Crv != "P-256" {
+			return publicKey{}, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, errX := decodeBigInt(k.X)
+		y, errY := decodeBigInt(k.Y)
+		if errX != nil || errY != nil || !elliptic.P256().IsOnCurve(x, y) {
+			return publicKey{}, fmt.Errorf("invalid EC key %q", k.Kid)
+		}
+		return publicKey{ecdsa: &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}}, nil
+	case "OKP":
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if k.Crv != "Ed25519" || err != nil || len(x) != ed25519.PublicKeySize {
+			return publicKey{}, fmt.Errorf("invalid OKP key %q", k.Kid)
+		}
+		return publicKey{ed25519: ed25519.PublicKey(x)}, nil
+	}
+	return publicKey{}, fmt.Errorf("unsupported key type %q", k.Kty)
+}
+
+// checkSignature verifies the signature for the algorithm of the header; the algorithm must
+// match the type of the key, so a token can't pick a weaker check than the provider intended
+func checkSignature(alg string, key publicKey, signed []byte, signature []byte) error {
+	digest := sha256.Sum256(signed)
+	valid := false
+	switch {
+	case alg == "RS256" && key.rsa != nil:
+		valid = rsa.VerifyPKCS1v15(key.rsa, crypto.SHA256, digest[:], signature) == nil
+	case alg == "ES256" && key.ecdsa != nil:
+		if len(signature) == 64 {
+			r := new(big.Int).SetBytes(signature[:32])
+			s := new(big.Int).SetBytes(signature[32:])
+			valid = ecdsa.Verify(key.ecdsa, digest[:], r, s)
+		}
+	case alg == "EdDSA" && key.ed25519 != nil:
+		valid = ed25519.Verify(key.ed25519, signed, signature)
+	default:
+		return fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidIDToken, alg)
+	}
+	if !valid {
+		return fmt.Errorf("%w: bad signature", ErrInvalidIDToken)
+	}
+	return nil
+}
+
+// decodeSegment decodes a base64url JSON segment of a token
+func decodeSegment(segment string, target any) error {
+	raw, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, target)
+}
+
+// decodeBigInt decodes a base64url big-endian integer
+func decodeBigInt(value string) (*big.Int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(raw) == 0 {
+		return nil, fmt.Errorf("invalid integer")
+	}
+	return new(big.Int).SetBytes(raw), nil
+}
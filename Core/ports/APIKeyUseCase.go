@@ -0,0 +1,49 @@
+package ports
+
+import (
+	dtos "Financial/Core/Models/dtos/Request"
+	response "Financial/Core/Models/dtos/Response"
+)
+
+// APIKeyUseCase manages the personal API keys users create for scripts and integrations.
+// Keys are stored hashed and only shown when created.
+type APIKeyUseCase interface {
+	// CreateKey creates a key for the user.
+	//
+	// Parameters:
+	//   - userID:  The authenticated user
+	//   - request: Name, scopes and optional expiry of the key
+	//
+	// Returns:
+	//   - *response.CreatedAPIKeyResponse: The key, including the secret shown only this once
+	//   - *response.ErrorResponse:         Error if the name, scopes or expiry are invalid
+	CreateKey(userID int, request dtos.CreateAPIKeyRequest) (*response.CreatedAPIKeyResponse, *response.ErrorResponse)
+
+	// ListKeys lists the keys of the user, newest first, without their secrets.
+	//
+	// Returns:
+	//   - []response.APIKeyResponse: The keys, revoked and expired ones included
+	//   - *response.ErrorResponse:   Error if the keys can't be fetched
+	ListKeys(userID int) ([]response.APIKeyResponse, *response.ErrorResponse)
+
+	// RevokeKey stops a key of the user from working.
+	//
+	// Parameters:
+	//   - userID: The authenticated user; keys of other users are reported as not found
+	//   - keyID:  The key to revoke
+	//
+	// Returns:
+	//   - *response.ErrorResponse: Error if the key is not found
+	RevokeKey(userID int, keyID int) *response.ErrorResponse
+
+	// Authenticate resolves the key sent with a request and records its use.
+	//
+	// Parameters:
+	//   - key: The value of the X-API-Key header
+	//
+	// Returns:
+	//   - *response.APIKeyPrincipal: The owner of the key and its scopes
+	//   - error: ErrInvalidAPIKey (types) for unknown, revoked or expired keys and
+	//            suspended owners, or another error if the key can't be fetched
+	Authenticate(key string) (*response.APIKeyPrincipal, error)
+}
@@ -0,0 +1,71 @@
+package ports
+
+import (
+	response "Financial/Core/Models/dtos/Response"
+	"Financial/Core/types"
+	"time"
+)
+
+// Authorizer decides whether a user may perform an operation on data of other users.
+type Authorizer interface {
+	// Authorize checks the role of a user against a permission.
+	//
+	// Parameters:
+	//   - userID:     The authenticated user
+	//   - permission: The operation being attempted
+	//
+	// Returns:
+	//   - error: nil if allowed, ErrForbidden (types) if the role doesn't grant it or the
+	//            account is suspended, or another error if the account can't be fetched
+	Authorize(userID int, permission types.Permission) error
+}
+
+// AdminUseCase defines the operations of support staff and administrators on any account.
+// Callers are expected to check the permission of each operation with Authorize first.
+type AdminUseCase interface {
+	Authorizer
+
+	// ListUsers returns every account, oldest first.
+	//
+	// Returns:
+	//   - []response.AdminUserResponse: The accounts, without their password hashes
+	//   - *response.ErrorResponse:      Error if the accounts can't be fetched
+	ListUsers() ([]response.AdminUserResponse, *response.ErrorResponse)
+
+	// SetAccountStatus changes the status of an account. Suspending it also logs out every session,
+	// and any lockout after failed logins ends, so the status set here is the one that stays.
+	//
+	// Parameters:
+	//   - actorID: The administrator making the change; they can't change their own account
+	//   - userID:  The account to change
+	//   - status:  The new status
+	//
+	// Returns:
+	//   - *response.AdminUserResponse: The updated account
+	//   - *response.ErrorResponse:     Error if the status is unknown or the account is not found
+	SetAccountStatus(actorID int, userID int, status types.AccountStatus) (*response.AdminUserResponse, *response.ErrorResponse)
+
+	// SetRole changes the role of an account.
+	//
+	// Parameters:
+	//   - actorID: The administrator making the change; they can't change their own role
+	//   - userID:  The account to change
+	//   - role:    The new role
+	//
+	// Returns:
+	//   - *response.AdminUserResponse: The updated account
+	//   - *response.ErrorResponse:     Error if the role is unknown or the account is not found
+	SetRole(actorID int, userID int, role types.Role) (*response.AdminUserResponse, *response.ErrorResponse)
+
+	// GetUserWallets returns the wallets of any account.
+	//
+	// Parameters:
+	//   - userID:            The account to inspect
+	//   - reportingCurrency: Optional ISO 4217 code to convert the balances to
+	//   - asOf:              Moment whose exchange rates are used (zero means now)
+	//
+	// Returns:
+	//   - *response.UserWalletResponse: The wallets with their transactions and totals
+	//   - *response.ErrorResponse:      Error if the account is not found or the wallets can't be fetched
+	GetUserWallets(userID int, reportingCurrency string, asOf time.Time) (*response.UserWalletResponse, *response.ErrorResponse)
+}
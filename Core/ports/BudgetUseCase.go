@@ -0,0 +1,54 @@
+package ports
+
+import (
+	"Financial/Core/Models/db"
+	dtos "Financial/Core/Models/dtos/Request"
+	response "Financial/Core/Models/dtos/Response"
+)
+
+// BudgetUseCase defines the business logic operations for monthly budgets.
+// A budget limits the spending of an expense category (and its subcategories) in one month;
+// the spent amount is always computed from the expenses recorded on the user's wallets.
+type BudgetUseCase interface {
+	// CreateBudget sets the limit of a category for a month.
+	//
+	// Parameters:
+	//   - request: A CreateBudgetRequest with the owner, category, period (YYYY-MM), limit and currency
+	//
+	// Returns:
+	//   - *db.Budget: The created budget
+	//   - *response.ErrorResponse: Error response if the data is invalid, the category is not an
+	//     expense category of the user or the category already has a budget for the period
+	CreateBudget(request dtos.CreateBudgetRequest) (*db.Budget, *response.ErrorResponse)
+
+	// UpdateBudget changes the limit of an existing budget.
+	//
+	// Parameters:
+	//   - request: An UpdateBudgetRequest with the budget, the owner and the new limit
+	//
+	// Returns:
+	//   - *db.Budget: The updated budget
+	//   - *response.ErrorResponse: Error response if the budget is not found or the limit is invalid
+	UpdateBudget(request dtos.UpdateBudgetRequest) (*db.Budget, *response.ErrorResponse)
+
+	// DeleteBudget removes a budget of the user.
+	//
+	// Parameters:
+	//   - userID:   ID of the user who owns the budget
+	//   - budgetID: ID of the budget to remove
+	//
+	// Returns:
+	//   - *response.ErrorResponse: Error response if the budget is not found or the deletion fails
+	DeleteBudget(userID int, budgetID int) *response.ErrorResponse
+
+	// GetBudgetProgress computes spent and remaining amounts for every budget of a month.
+	//
+	// Parameters:
+	//   - userID: ID of the user whose budgets are requested
+	//   - period: Month to report, formatted as YYYY-MM
+	//
+	// Returns:
+	//   - *response.BudgetProgressResponse: The progress of each budget, flagging the exceeded ones
+	//   - *response.ErrorResponse: Error response if the period is invalid or a query fails
+	GetBudgetProgress(userID int, period string) (*response.BudgetProgressResponse, *response.ErrorResponse)
+}
@@ -0,0 +1,65 @@
+package ports
+
+import (
+	"Financial/Core/Models/db"
+	dtos "Financial/Core/Models/dtos/Request"
+	response "Financial/Core/Models/dtos/Response"
+)
+
+// CategoryUseCase defines the business logic operations for the spending categories of a user.
+// Categories form a tree (e.g. Food > Restaurants) and transactions can be tagged with any of them.
+type CategoryUseCase interface {
+	// CreateCategory adds a category, optionally under an existing parent of the same user.
+	//
+	// Parameters:
+	//   - request: A CreateCategoryRequest with the owner, name, type and optional parent
+	//
+	// Returns:
+	//   - *db.Category: The created category
+	//   - *response.ErrorResponse: Error response if the data is invalid, the parent does not belong
+	//     to the user or a sibling already has the same name
+	CreateCategory(request dtos.CreateCategoryRequest) (*db.Category, *response.ErrorResponse)
+
+	// UpdateCategory renames a category or moves it under another parent.
+	//
+	// Parameters:
+	//   - request: An UpdateCategoryRequest with the category, the owner and the changes
+	//
+	// Returns:
+	//   - *db.Category: The updated category
+	//   - *response.ErrorResponse: Error response if the category is not found, the name is taken
+	//     or the move would create a cycle
+	UpdateCategory(request dtos.UpdateCategoryRequest) (*db.Category, *response.ErrorResponse)
+
+	// DeleteCategory removes a category of the user.
+	//
+	// Parameters:
+	//   - userID:     ID of the user who owns the category
+	//   - categoryID: ID of the category to remove
+	//   - reassignTo: Optional category that receives the subcategories and transactions of the
+	//     deleted one; without it the deletion is rejected when any of them exist
+	//
+	// Returns:
+	//   - *response.ErrorResponse: Error response if the category is not found, still in use
+	//     or the reassignment target is not valid
+	DeleteCategory(userID int, categoryID int, reassignTo *int) *response.ErrorResponse
+
+	// GetUserCategories lists the categories of a user as a tree.
+	//
+	// Parameters:
+	//   - userID: ID of the user whose categories are requested
+	//
+	// Returns:
+	//   - []db.Category: The root categories, each with its Children populated
+	//   - *response.ErrorResponse: Error response if the query fails
+	GetUserCategories(userID int) ([]db.Category, *response.ErrorResponse)
+
+	// SeedDefaultCategories creates the default category tree for a new user.
+	//
+	// Parameters:
+	//   - userID: ID of the user that receives the categories
+	//
+	// Returns:
+	//   - *response.ErrorResponse: Error response if any category can't be created
+	SeedDefaultCategories(userID int) *response.ErrorResponse
+}
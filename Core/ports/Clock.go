@@ -0,0 +1,10 @@
+package ports
+
+import "time"
+
+// Clock abstracts the current time so time-dependent logic (like the recurring scheduler)
+// can be tested with a fixed or simulated clock.
+type Clock interface {
+	// Now returns the current instant.
+	Now() time.Time
+}
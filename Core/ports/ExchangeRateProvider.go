@@ -0,0 +1,24 @@
+package ports
+
+import (
+	"Financial/Core/Models/db"
+	"time"
+)
+
+// ExchangeRateProvider defines the source of the exchange rates used to convert
+// amounts between currencies. Implementations keep every rate with its effective
+// date so historical conversions can be reproduced.
+type ExchangeRateProvider interface {
+	// GetRate returns the rate to convert from one currency to another as it was at a given moment
+	//
+	// Parameters:
+	//   - from: ISO 4217 code of the currency of the amount (e.g. "USD")
+	//   - to:   ISO 4217 code of the requested currency (e.g. "DOP")
+	//   - at:   Moment of the conversion; the latest rate effective on or before it is used
+	//
+	// Returns:
+	//   - *db.ExchangeRate: The rate with BaseCurrency = from and QuoteCurrency = to
+	//     (an inverse rate is derived when only the opposite pair is stored)
+	//   - error: types.ErrExchangeRateNotFound if no rate applies, or a storage error
+	GetRate(from string, to string, at time.Time) (*db.ExchangeRate, error)
+}
@@ -0,0 +1,24 @@
+package ports
+
+import (
+	dtos "Financial/Core/Models/dtos/Request"
+	response "Financial/Core/Models/dtos/Response"
+	"io"
+)
+
+// ExportUseCase defines the business logic operations for exporting the wallets of a user and their
+// movements as CSV, JSON Lines or OFX.
+type ExportUseCase interface {
+	// Export streams the wallets of the user and their movements, oldest first, to w.
+	// The request is validated before anything is written, so an error response with nothing
+	// written means the export did not start.
+	//
+	// Parameters:
+	//   - request: An ExportRequest with the user, format and optional date range
+	//   - w:       Destination of the export
+	//
+	// Returns:
+	//   - *response.ErrorResponse: Error response if the format or range is invalid, or a query or
+	//     write fails
+	Export(request dtos.ExportRequest, w io.Writer) *response.ErrorResponse
+}
@@ -0,0 +1,27 @@
+package ports
+
+// ExternalLoginUseCase signs users in through an OpenID Connect provider.
+// Identities are linked to existing accounts by verified email and, when allowed,
+// accounts are created for users seen for the first time.
+type ExternalLoginUseCase interface {
+	// StartLogin prepares a login: it stores a fresh state, nonce and PKCE verifier
+	// and returns where to send the browser.
+	//
+	// Returns:
+	//   - string: The URL of the provider's login page
+	//   - error:  Error if the login can't be stored
+	StartLogin() (string, error)
+
+	// CompleteLogin finishes the login the provider redirected back. Each state works once.
+	//
+	// Parameters:
+	//   - state: The state parameter of the callback
+	//   - code:  The authorization code of the callback
+	//
+	// Returns:
+	//   - *int:  The ID of the account the user logged in to
+	//   - error: ErrExternalLoginFailed (types) for an unknown or expired state or an identity the
+	//            provider can't confirm, ErrAccountNotLinked (types) when no account matches and none
+	//            can be created, ErrAccountSuspended (types), or another error if storage fails
+	CompleteLogin(state string, code string) (*int, error)
+}
@@ -0,0 +1,51 @@
+package ports
+
+// ExternalIdentity is the user an external identity provider vouched for after a login.
+type ExternalIdentity struct {
+	// Issuer identifies the provider (the iss claim of its ID token)
+	Issuer string
+
+	// Subject is the stable ID of the user at the provider (the sub claim)
+	Subject string
+
+	// Email is the address the provider holds for the user; it may be empty
+	Email string
+
+	// EmailVerified tells whether the provider checked that the user owns Email
+	EmailVerified bool
+
+	// Name is the display name, when the provider shares it
+	Name string
+
+	// GivenName and FamilyName are the parts of the name, when the provider shares them
+	GivenName  string
+	FamilyName string
+}
+
+// IdentityProvider is an OpenID Connect provider users can sign in with, using the
+// authorization code flow with PKCE.
+type IdentityProvider interface {
+	// AuthCodeURL builds the address of the provider's login page.
+	//
+	// Parameters:
+	//   - state:         Opaque value the provider sends back to the callback, tying it to this login
+	//   - nonce:         Value the provider must copy into the ID token, to detect replayed tokens
+	//   - codeChallenge: The S256 PKCE challenge of the code verifier kept for the callback
+	//
+	// Returns:
+	//   - string: The URL to redirect the browser to
+	AuthCodeURL(state string, nonce string, codeChallenge string) string
+
+	// Identify exchanges the authorization code of the callback and verifies the ID token
+	// it returns: signature, issuer, audience, expiry and nonce.
+	//
+	// Parameters:
+	//   - code:         The authorization code received on the callback
+	//   - codeVerifier: The PKCE verifier whose challenge was sent to AuthCodeURL
+	//   - nonce:        The nonce sent to AuthCodeURL
+	//
+	// Returns:
+	//   - *ExternalIdentity: The user the provider authenticated
+	//   - error:            Error if the exchange fails or the ID token is not valid
+	Identify(code string, codeVerifier string, nonce string) (*ExternalIdentity, error)
+}
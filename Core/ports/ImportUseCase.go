@@ -0,0 +1,33 @@
+package ports
+
+import (
+	dtos "Financial/Core/Models/dtos/Request"
+	response "Financial/Core/Models/dtos/Response"
+)
+
+// ImportUseCase defines the business logic operations for importing bank statements (CSV, OFX and QIF)
+// into a wallet. Movements already on the wallet, recognized by their date/amount/description
+// fingerprint, are never recorded twice.
+type ImportUseCase interface {
+	// PreviewImport parses a statement and flags the duplicates without recording anything.
+	//
+	// Parameters:
+	//   - request: An ImportStatementRequest with the owner, wallet, format, file and CSV column mapping
+	//
+	// Returns:
+	//   - *response.ImportPreviewResponse: The movements of the statement and which of them are duplicates
+	//   - *response.ErrorResponse: Error response if the wallet does not belong to the user or the file
+	//     can't be parsed
+	PreviewImport(request dtos.ImportStatementRequest) (*response.ImportPreviewResponse, *response.ErrorResponse)
+
+	// CommitImport records the movements of a statement that are not duplicates, updating the wallet balance.
+	//
+	// Parameters:
+	//   - request: The same ImportStatementRequest used for the preview
+	//
+	// Returns:
+	//   - *response.ImportResultResponse: The recorded transactions and the lines that failed
+	//   - *response.ErrorResponse: Error response if the wallet does not belong to the user or the file
+	//     can't be parsed
+	CommitImport(request dtos.ImportStatementRequest) (*response.ImportResultResponse, *response.ErrorResponse)
+}
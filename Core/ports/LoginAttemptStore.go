@@ -0,0 +1,42 @@
+package ports
+
+import (
+	"Financial/Core/Models/db"
+	"Financial/Core/types"
+	"time"
+)
+
+// LoginAttemptStore keeps the failed login counters the login throttle works with.
+// Several instances of the server may share it, so counting a failure must be atomic.
+type LoginAttemptStore interface {
+	// Get returns the counter of a key.
+	//
+	// Returns:
+	//   - *db.LoginAttempt: The counter
+	//   - error: ErrNotFound (types) if the key has no failures, or another error if the storage fails
+	Get(key string) (*db.LoginAttempt, error)
+
+	// RecordFailure adds a failure to a key in a single atomic step. A count whose last
+	// failure is older than window starts again from one.
+	//
+	// Parameters:
+	//   - key:    What failed to log in
+	//   - at:     When it failed
+	//   - window: How long failures are remembered
+	//
+	// Returns:
+	//   - *db.LoginAttempt: The counter after adding the failure
+	//   - error: Error if the storage fails
+	RecordFailure(key string, at time.Time, window time.Duration) (*db.LoginAttempt, error)
+
+	// Lock locks a key out until the given time.
+	//
+	// Parameters:
+	//   - key:            What is locked out
+	//   - until:          When the lockout ends
+	//   - previousStatus: Status of the account before the lockout suspended it; empty for addresses and unknown accounts
+	Lock(key string, until time.Time, previousStatus types.AccountStatus) error
+
+	// Reset forgets the failures and the lockout of a key; resetting an unknown key is not an error
+	Reset(key string) error
+}
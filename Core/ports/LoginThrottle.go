@@ -0,0 +1,92 @@
+package ports
+
+import (
+	dtos "Financial/Core/Models/dtos/Request"
+	"time"
+)
+
+// LoginThrottle slows down password guessing. Failed logins are counted per account and per
+// client address; after a few of them each new attempt has to wait twice as long as the last,
+// and an account with too many failures is suspended for a while. Wrong two-factor codes are
+// counted with the wrong passwords of the account, so codes can't be guessed any faster.
+// Forgot-password requests are limited too, so they can't be used to flood a mailbox.
+type LoginThrottle interface {
+	// Allow tells whether a login may be attempted now. It also ends lockouts that are over,
+	// giving the account back the status it had.
+	//
+	// Parameters:
+	//   - auth: The credentials of the login, to find the account
+	//   - ip:   The client address
+	//
+	// Returns:
+	//   - time.Duration: How long to wait before trying again, when the login is refused
+	//   - error: ErrTooManyAttempts (types) if the account or the address has to wait,
+	//            or another error if the counters can't be read
+	Allow(auth dtos.AuthRequest, ip string) (time.Duration, error)
+
+	// LoginFailed counts a login refused for an unknown account or a wrong password,
+	// locking the account out when it reaches the threshold.
+	//
+	// Returns:
+	//   - error: Error if the failure can't be recorded
+	LoginFailed(auth dtos.AuthRequest, ip string) error
+
+	// AllowSecondFactor tells whether a two-factor code of the account may be checked now, like Allow.
+	//
+	// Parameters:
+	//   - userID: The account the code is for
+	//   - ip:     The client address
+	//
+	// Returns:
+	//   - time.Duration: How long to wait before trying again, when the code is refused
+	//   - error: ErrTooManyAttempts (types) if the account or the address has to wait,
+	//            or another error if the counters can't be read
+	AllowSecondFactor(userID int, ip string) (time.Duration, error)
+
+	// SecondFactorFailed counts a wrong two-factor code (from the app or a recovery code) like
+	// a wrong password, locking the account out when it reaches the threshold.
+	//
+	// Returns:
+	//   - error: Error if the failure can't be recorded
+	SecondFactorFailed(userID int, ip string) error
+
+	// ClaimChallenge marks the challenge of a two-factor login as used, so its token can't
+	// complete a second login.
+	//
+	// Parameters:
+	//   - challengeID: The jti of the challenge token
+	//   - ttl:         How long the token stays valid, and so how long the claim is kept
+	//
+	// Returns:
+	//   - bool:  True for the first claim of the challenge, false if it was already used
+	//   - error: Error if the claim can't be recorded
+	ClaimChallenge(challengeID string, ttl time.Duration) (bool, error)
+
+	// AllowPasswordReset counts a forgot-password request and tells whether its email may be
+	// sent. Requests are counted per email address, whether or not it has an account, and per client address.
+	//
+	// Parameters:
+	//   - email: The address the reset is asked for
+	//   - ip:    The client address
+	//
+	// Returns:
+	//   - time.Duration: How long to wait before asking again, when the request is refused
+	//   - error: ErrTooManyAttempts (types) if the email or the client address asked too often,
+	//            or another error if the request can't be counted
+	AllowPasswordReset(email string, ip string) (time.Duration, error)
+
+	// LoginSucceeded forgets the failures of the account after a successful login, which for
+	// accounts with two-factor authentication is once the code was accepted. The failures
+	// of the address are kept, so one valid account doesn't reset the count of a guessing client.
+	//
+	// Returns:
+	//   - error: Error if the counter can't be reset
+	LoginSucceeded(userID int) error
+
+	// Release ends the lockout of an account without touching its status, e.g. when an
+	// administrator sets the status by hand.
+	//
+	// Returns:
+	//   - error: Error if the lockout can't be removed
+	Release(userID int) error
+}
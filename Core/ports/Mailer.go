@@ -0,0 +1,21 @@
+package ports
+
+// MailMessage is a plain text email
+type MailMessage struct {
+	To      string // Recipient address
+	Subject string // Subject line
+	Body    string // Plain text body
+}
+
+// Mailer delivers emails to users, e.g. account verification links.
+// Implementations range from a real SMTP relay to writing messages to disk for local development.
+type Mailer interface {
+	// Send delivers a message.
+	//
+	// Parameters:
+	//   - message: The message to deliver
+	//
+	// Returns:
+	//   - error: Error if the message could not be handed over for delivery
+	Send(message MailMessage) error
+}
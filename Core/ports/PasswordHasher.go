@@ -0,0 +1,32 @@
+package ports
+
+// PasswordHasher turns passwords into one-way hashes and checks passwords against them.
+// Hashes are self-describing strings (algorithm, parameters, salt and key) so the
+// parameters can be raised later without breaking the stored ones.
+type PasswordHasher interface {
+	// Hash derives a new salted hash of the password.
+	//
+	// Parameters:
+	//   - password: The plain text password
+	//
+	// Returns:
+	//   - string: The encoded hash, safe to store
+	//   - error:  Error if the random salt can't be generated
+	Hash(password string) (string, error)
+
+	// Verify checks a password against a stored hash in constant time.
+	//
+	// Parameters:
+	//   - password: The plain text password to check
+	//   - encoded:  A hash produced by Hash (or by a previous algorithm the hasher still reads)
+	//
+	// Returns:
+	//   - bool:  True if the password matches
+	//   - error: ErrUnknownHash (types) if encoded is not a hash the implementation recognizes
+	Verify(password string, encoded string) (bool, error)
+
+	// NeedsRehash reports whether a stored value should be replaced by a fresh Hash,
+	// because it is not a hash at all (legacy plain text), uses another algorithm or
+	// weaker parameters than the current ones.
+	NeedsRehash(encoded string) bool
+}
@@ -0,0 +1,28 @@
+package ports
+
+import (
+	dtos "Financial/Core/Models/dtos/Request"
+	response "Financial/Core/Models/dtos/Response"
+)
+
+// PasswordResetUseCase lets users who forgot their password set a new one through
+// a single-use, expiring token sent to their email address.
+type PasswordResetUseCase interface {
+	// RequestReset emails a reset token to the account registered with an email address.
+	// It never reports whether the address belongs to an account, so it can't be used to probe for users.
+	//
+	// Parameters:
+	//   - email: The address the user signed up with
+	RequestReset(email string)
+
+	// ResetPassword sets a new password with a reset token. On success the token and any
+	// other outstanding token of the user stop working, and every session of the user is logged out.
+	//
+	// Parameters:
+	//   - request: The emailed token and the new password
+	//
+	// Returns:
+	//   - *response.ErrorResponse: Error if the password breaks the password rules,
+	//     or the token is unknown, expired or already used
+	ResetPassword(request dtos.ResetPasswordRequest) *response.ErrorResponse
+}
@@ -0,0 +1,88 @@
+package ports
+
+import (
+	"Financial/Core/Models/db"
+	dtos "Financial/Core/Models/dtos/Request"
+	response "Financial/Core/Models/dtos/Response"
+	"context"
+	"time"
+)
+
+// RecurringTransactionUseCase defines the business logic operations for recurring incomes and expenses.
+// A series only describes the schedule; the postings are made by the RecurringScheduler.
+type RecurringTransactionUseCase interface {
+	// CreateRecurring schedules a new recurring series on a wallet of the user.
+	//
+	// Parameters:
+	//   - request: A CreateRecurringRequest with the wallet, movement and schedule
+	//
+	// Returns:
+	//   - *db.RecurringTransaction: The created series with its first occurrence in NextRunAt
+	//   - *response.ErrorResponse: Error response if the data is invalid, the wallet or category
+	//     does not belong to the user or the schedule has no occurrences
+	CreateRecurring(request dtos.CreateRecurringRequest) (*db.RecurringTransaction, *response.ErrorResponse)
+
+	// UpdateRecurring changes the amount, description or end of a series, or pauses, resumes or ends it.
+	// Resuming a paused series skips the occurrences missed while it was paused.
+	//
+	// Parameters:
+	//   - request: An UpdateRecurringRequest with the series, the owner and the changes
+	//
+	// Returns:
+	//   - *db.RecurringTransaction: The updated series
+	//   - *response.ErrorResponse: Error response if the series is not found, the changes are
+	//     invalid or the series has already ended
+	UpdateRecurring(request dtos.UpdateRecurringRequest) (*db.RecurringTransaction, *response.ErrorResponse)
+
+	// DeleteRecurring removes a series of the user. Transactions already posted are kept.
+	//
+	// Parameters:
+	//   - userID:      ID of the user who owns the series
+	//   - recurringID: ID of the series to remove
+	//
+	// Returns:
+	//   - *response.ErrorResponse: Error response if the series is not found or the deletion fails
+	DeleteRecurring(userID int, recurringID int) *response.ErrorResponse
+
+	// GetRecurring fetches a series of the user.
+	//
+	// Parameters:
+	//   - userID:      ID of the user who owns the series
+	//   - recurringID: ID of the series
+	//
+	// Returns:
+	//   - *db.RecurringTransaction: The series
+	//   - *response.ErrorResponse: Error response if the series is not found
+	GetRecurring(userID int, recurringID int) (*db.RecurringTransaction, *response.ErrorResponse)
+
+	// GetUserRecurring lists the series of a user.
+	//
+	// Parameters:
+	//   - userID: ID of the user whose series are requested
+	//
+	// Returns:
+	//   - []db.RecurringTransaction: The series of the user (empty if none)
+	//   - *response.ErrorResponse: Error response if the query fails
+	GetUserRecurring(userID int) ([]db.RecurringTransaction, *response.ErrorResponse)
+}
+
+// RecurringScheduler posts the due occurrences of the active recurring series.
+// Posting is idempotent: an occurrence already recorded on the ledger is never posted again,
+// so the scheduler can be restarted (or crash) at any point.
+type RecurringScheduler interface {
+	// RunDue posts every occurrence that is due at the current time of the clock.
+	//
+	// Returns:
+	//   - int:   Number of transactions posted
+	//   - error: Error joining the failures of the series that could not be posted; the rest
+	//     are still processed. An occurrence the ledger rejects is skipped and reported here,
+	//     and the later occurrences of its series are still posted
+	RunDue() (int, error)
+
+	// Start runs RunDue immediately and then every interval until the context is cancelled.
+	//
+	// Parameters:
+	//   - ctx:      Context whose cancellation stops the scheduler
+	//   - interval: Time between runs
+	Start(ctx context.Context, interval time.Duration)
+}
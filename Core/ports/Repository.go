@@ -1,18 +1,52 @@
 package ports
 
 type QueryOptions struct {
-	Filters []Filter  // Filtros a aplicar
+	Filters []Filter  // Filtros a aplicar; deben cumplirse todos
 	OrderBy []OrderBy // Ordenamiento
 	Limit   *int      // Límite de resultados
 	Offset  *int      // Offset para paginación
-	Count   *string   // Tipo de conteo ("exact", "planned", "estimated")
+	Count   *string   // Tipo de conteo ("exact", "planned", "estimated"); llena Page.Total
 }
 
-// Filter representa un filtro individual
+// Operadores de Filter
+const (
+	OpEq    = "eq"    // igual
+	OpNeq   = "neq"   // distinto
+	OpGt    = "gt"    // mayor que
+	OpGte   = "gte"   // mayor o igual que
+	OpLt    = "lt"    // menor que
+	OpLte   = "lte"   // menor o igual que
+	OpLike  = "like"  // patrón con % y _, distingue mayúsculas
+	OpIlike = "ilike" // patrón con % y _, sin distinguir mayúsculas
+	OpIn    = "in"    // el valor es una lista (slice) y basta con que coincida uno
+	OpIs    = "is"    // el valor es nil, true o false
+)
+
+// Conteos de QueryOptions.Count
+const (
+	CountExact     = "exact"
+	CountPlanned   = "planned"
+	CountEstimated = "estimated"
+)
+
+// Filter representa un filtro individual, o un grupo de filtros cuando Or o And tienen elementos
 type Filter struct {
 	Field    string      // Campo a filtrar
-	Operator string      // Operador (eq, neq, gt, gte, lt, lte, like, ilike, in, etc.)
-	Value    interface{} // Valor del filtro
+	Operator string      // Operador (eq, neq, gt, gte, lt, lte, like, ilike, in, is)
+	Value    interface{} // Valor del filtro: string, número, bool, time.Time, types.Money o un puntero a ellos
+
+	Or  []Filter // Grupo que se cumple si se cumple alguno de sus filtros
+	And []Filter // Grupo que se cumple si se cumplen todos sus filtros (útil dentro de Or)
+}
+
+// Or agrupa filtros de los que basta con que se cumpla uno
+func Or(filters ...Filter) Filter {
+	return Filter{Or: filters}
+}
+
+// And agrupa filtros que deben cumplirse todos
+func And(filters ...Filter) Filter {
+	return Filter{And: filters}
 }
 
 // OrderBy representa una cláusula de ordenamiento
@@ -22,6 +56,18 @@ type OrderBy struct {
 	NullsFirst *bool  // nil para default, true/false para NULLS FIRST/LAST
 }
 
+// Page es el resultado de Query: las filas dentro de Limit y Offset
+type Page[T any] struct {
+	Items  []T  // Filas de la página
+	Offset int  // Posición de la primera fila entre todas las que cumplen los filtros
+	Total  *int // Filas que cumplen los filtros sin Limit ni Offset; solo cuando se pidió Count
+}
+
+// HasMore indica si quedan filas después de esta página; sin Total no se sabe y devuelve false
+func (p Page[T]) HasMore() bool {
+	return p.Total != nil && p.Offset+len(p.Items) < *p.Total
+}
+
 // Repository is a generic interface that defines the standard CRUD operations for domain entities.
 // It uses Go generics to work with any entity type (T) and any comparable ID type (ID).
 //
@@ -91,22 +137,18 @@ type Repository[T any, ID comparable] interface {
 	// Note: Some implementations might choose to implement soft delete instead of physical deletion
 	Delete(id ID) error
 
-	// Query executes a custom query and returns the result as type R.
-	// This method provides a flexible way to execute custom queries that don't fit the standard CRUD operations.
-	//
-	// Type parameters:
-	//   - R: The type of the result
+	// Query retrieves the entities that match the filters of args, one page at a time.
 	//
 	// Parameters:
-	//   - query: The query to execute (format depends on the implementation)
-	//   - args: Optional arguments for the query
+	//   - fields: The columns to return as a PostgREST select list, e.g. "*" or
+	//     "id,name,user:users!inner(email)" to embed related rows
+	//   - args: Filters (including Or/And groups), ordering, Limit/Offset and Count
 	//
 	// Returns:
-	//   - R: The query result
-	//   - error: Error if the operation fails
-	//
-	// Note: The query format and arguments are implementation-specific
-	Query(fields string, args QueryOptions) (any, error)
+	//   - Page[T]: The matching entities; Total is only set when args.Count is given
+	//   - error: Error if the options are invalid (unknown operator or field, a value
+	//     of an unsupported type) or the operation fails
+	Query(fields string, args QueryOptions) (Page[T], error)
 
 	// FindByField retrieves the first entity that matches the given field-value pair.
 	// This method is useful for looking up entities by non-primary key fields.
@@ -0,0 +1,22 @@
+package ports
+
+import "Financial/Core/Models/db"
+
+// SessionRepository stores the server-side sessions. Besides the CRUD operations of Repository,
+// it rotates refresh tokens with a conditional write, so two refreshes racing with the same
+// token can't both succeed.
+type SessionRepository interface {
+	Repository[db.Session, int]
+
+	// RotateRefresh writes the session only if its stored refresh token hash is still previousHash.
+	//
+	// Parameters:
+	//   - session: The session with the rotated tokens
+	//   - previousHash: The refresh token hash the session must still have
+	//
+	// Returns:
+	//   - *db.Session: The updated session
+	//   - error: ErrNotFound (types) if no session has that id and hash, e.g. because another
+	//     refresh rotated it first, or another error if the storage fails
+	RotateRefresh(session *db.Session, previousHash string) (*db.Session, error)
+}
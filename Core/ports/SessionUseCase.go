@@ -0,0 +1,87 @@
+package ports
+
+import (
+	response "Financial/Core/Models/dtos/Response"
+)
+
+// SessionUseCase manages the server-side sessions behind access and refresh tokens.
+// Access tokens are short-lived JWTs identified by a jti; refresh tokens are opaque,
+// stored hashed and replaced on every use.
+type SessionUseCase interface {
+	// StartSession opens a session after a successful login.
+	//
+	// Parameters:
+	//   - userID: The authenticated user, as returned by UserUseCase.Login
+	//   - device: The user agent of the client
+	//   - ip:     The client address
+	//
+	// Returns:
+	//   - *response.SessionGrant:  The access token ID to sign and the new refresh token
+	//   - *response.ErrorResponse: Error if the session can't be stored
+	StartSession(userID int, device string, ip string) (*response.SessionGrant, *response.ErrorResponse)
+
+	// RefreshSession exchanges a refresh token for a new access token ID and refresh token.
+	// The presented token stops working; presenting a replaced token again revokes the session.
+	// Of two refreshes with the same token only one succeeds, and the session of a suspended
+	// account is revoked instead of refreshed.
+	//
+	// Parameters:
+	//   - refreshToken: The refresh token returned by the last login or refresh
+	//   - device:       The user agent of the client
+	//   - ip:           The client address
+	//
+	// Returns:
+	//   - *response.SessionGrant:  The rotated tokens
+	//   - *response.ErrorResponse: Error if the token is unknown, expired, revoked or reused,
+	//                              or the account is suspended
+	RefreshSession(refreshToken string, device string, ip string) (*response.SessionGrant, *response.ErrorResponse)
+
+	// ValidateAccess checks that an access token ID belongs to a live session,
+	// and records the activity on the session.
+	//
+	// Parameters:
+	//   - tokenID: The jti claim of the access token
+	//
+	// Returns:
+	//   - error: ErrSessionRevoked (types) if the token was rotated, logged out or expired
+	ValidateAccess(tokenID string) error
+
+	// Logout revokes the session of an access token.
+	//
+	// Parameters:
+	//   - tokenID: The jti claim of the access token used for the request
+	//
+	// Returns:
+	//   - *response.ErrorResponse: Error if the session can't be revoked
+	Logout(tokenID string) *response.ErrorResponse
+
+	// LogoutAll revokes every session of the user, on all devices.
+	//
+	// Parameters:
+	//   - userID: The authenticated user
+	//
+	// Returns:
+	//   - *response.ErrorResponse: Error if the sessions can't be revoked
+	LogoutAll(userID int) *response.ErrorResponse
+
+	// RevokeSession revokes one session of the user, e.g. an unknown device from the session list.
+	//
+	// Parameters:
+	//   - userID:    The authenticated user
+	//   - sessionID: The session to revoke
+	//
+	// Returns:
+	//   - *response.ErrorResponse: Error if the session doesn't exist or belongs to another user
+	RevokeSession(userID int, sessionID int) *response.ErrorResponse
+
+	// GetSessions lists the sessions of the user, most recently used first.
+	//
+	// Parameters:
+	//   - userID:         The authenticated user
+	//   - currentTokenID: The jti of that token, to flag the session making the request
+	//
+	// Returns:
+	//   - []response.SessionResponse: The sessions, including revoked ones
+	//   - *response.ErrorResponse:    Error if the sessions can't be fetched
+	GetSessions(userID int, currentTokenID string) ([]response.SessionResponse, *response.ErrorResponse)
+}
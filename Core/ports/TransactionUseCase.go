@@ -0,0 +1,48 @@
+package ports
+
+import (
+	"Financial/Core/Models/db"
+	dtos "Financial/Core/Models/dtos/Request"
+	response "Financial/Core/Models/dtos/Response"
+)
+
+// TransactionUseCase defines the business logic operations for the wallet ledger.
+// Every income or expense goes through this contract so the wallet balance always
+// matches the sum of its recorded transactions.
+type TransactionUseCase interface {
+	// RecordTransaction registers a new income or expense on a wallet and applies it to the wallet balance.
+	//
+	// Parameters:
+	//   - userID:  ID of the user that owns the wallet
+	//   - request: A CreateTransactionRequest with the wallet, type, amount, description and optional category of the movement
+	//
+	// Returns:
+	//   - *db.Transaction: The recorded transaction
+	//   - *response.ErrorResponse: Error response if the movement is invalid, the wallet does not exist
+	//     or belongs to another user, or it would overdraw a debit wallet
+	RecordTransaction(userID int, request dtos.CreateTransactionRequest) (*db.Transaction, *response.ErrorResponse)
+
+	// GetWalletTransactions lists the ledger of a wallet, newest first.
+	//
+	// Parameters:
+	//   - userID:   ID of the user that owns the wallet; 0 lifts the restriction (support staff)
+	//   - walletID: ID of the wallet whose transactions are requested
+	//
+	// Returns:
+	//   - []db.Transaction: The transactions recorded on the wallet (empty if none)
+	//   - *response.ErrorResponse: Error response if the wallet does not exist, belongs to another user
+	//     or the query fails
+	GetWalletTransactions(userID int, walletID int) ([]db.Transaction, *response.ErrorResponse)
+
+	// DeleteTransaction removes a transaction from a wallet ledger and reverts its effect on the balance.
+	//
+	// Parameters:
+	//   - userID:        ID of the user that owns the wallet
+	//   - walletID:      ID of the wallet the transaction belongs to
+	//   - transactionID: ID of the transaction to remove
+	//
+	// Returns:
+	//   - *response.ErrorResponse: Error response if the transaction is not found, the wallet belongs to
+	//     another user or the reversal fails
+	DeleteTransaction(userID int, walletID int, transactionID int) *response.ErrorResponse
+}
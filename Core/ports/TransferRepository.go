@@ -0,0 +1,20 @@
+package ports
+
+import "Financial/Core/Models/db"
+
+// TransferRepository defines the persistence contract for wallet-to-wallet transfers.
+// Unlike Repository, a transfer touches two wallets and two ledger entries, so the
+// implementation must apply all of it in a single atomic operation or nothing at all.
+type TransferRepository interface {
+	// Transfer debits the source wallet, credits the destination wallet and records
+	// both ledger entries atomically.
+	//
+	// Parameters:
+	//   - transfer: The transfer to apply (wallets, amount and description)
+	//
+	// Returns:
+	//   - *db.Transfer: The applied transfer with the Debit and Credit entries populated
+	//   - error: Error if the transfer is rejected (e.g., insufficient funds) or the storage fails;
+	//     in both cases no balance has been modified
+	Transfer(transfer *db.Transfer) (*db.Transfer, error)
+}
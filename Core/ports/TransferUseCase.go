@@ -0,0 +1,22 @@
+package ports
+
+import (
+	"Financial/Core/Models/db"
+	dtos "Financial/Core/Models/dtos/Request"
+	response "Financial/Core/Models/dtos/Response"
+)
+
+// TransferUseCase defines the business logic for moving money between wallets.
+type TransferUseCase interface {
+	// Transfer moves an amount between two wallets of the user.
+	//
+	// Parameters:
+	//   - userID:  ID of the user that owns both wallets
+	//   - request: A CreateTransferRequest with the source and destination wallets and the amount
+	//
+	// Returns:
+	//   - *db.Transfer: The applied transfer including the debit and credit ledger entries
+	//   - *response.ErrorResponse: Error response if the request is invalid, a wallet does not exist or
+	//     belongs to another user, or the transfer would overdraw a debit wallet
+	Transfer(userID int, request dtos.CreateTransferRequest) (*db.Transfer, *response.ErrorResponse)
+}
@@ -0,0 +1,59 @@
+package ports
+
+import (
+	response "Financial/Core/Models/dtos/Response"
+)
+
+// TwoFactorUseCase manages optional TOTP two-factor authentication.
+// Enrollment happens in two steps: Setup hands out a secret, Confirm enables it
+// once the user enters a valid code, returning recovery codes.
+type TwoFactorUseCase interface {
+	// Setup starts (or restarts) the enrollment of a user with a new secret.
+	//
+	// Parameters:
+	//   - userID: The authenticated user
+	//
+	// Returns:
+	//   - *response.TwoFactorSetupResponse: The secret and its otpauth:// URI
+	//   - *response.ErrorResponse:          Error if two-factor authentication is already enabled
+	Setup(userID int) (*response.TwoFactorSetupResponse, *response.ErrorResponse)
+
+	// Confirm enables two-factor authentication with a first code from the app.
+	//
+	// Parameters:
+	//   - userID: The authenticated user
+	//   - code:   The current code shown by the app
+	//
+	// Returns:
+	//   - *response.RecoveryCodesResponse: The recovery codes, shown only this once
+	//   - *response.ErrorResponse:         Error if there is no pending setup or the code is wrong
+	Confirm(userID int, code string) (*response.RecoveryCodesResponse, *response.ErrorResponse)
+
+	// Disable turns two-factor authentication off after checking a code.
+	//
+	// Parameters:
+	//   - userID: The authenticated user
+	//   - code:   A code from the app or a recovery code
+	//
+	// Returns:
+	//   - *response.ErrorResponse: Error if it is not enabled or the code is wrong
+	Disable(userID int, code string) *response.ErrorResponse
+
+	// Enabled reports whether the user has confirmed two-factor authentication.
+	//
+	// Returns:
+	//   - bool:  True if logins and sensitive operations need a code
+	//   - error: Error if the enrollment can't be fetched
+	Enabled(userID int) (bool, error)
+
+	// Verify checks a code of a user with two-factor authentication enabled.
+	// App codes are accepted once; recovery codes are consumed.
+	//
+	// Parameters:
+	//   - userID: The user
+	//   - code:   A code from the app or a recovery code
+	//
+	// Returns:
+	//   - *response.ErrorResponse: Error if the code is wrong or already used
+	Verify(userID int, code string) *response.ErrorResponse
+}
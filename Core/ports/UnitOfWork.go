@@ -0,0 +1,49 @@
+package ports
+
+import "Financial/Core/Models/db"
+
+// UnitOfWork runs several writes across repositories as a single atomic operation.
+// Use cases use it when one request touches more than one row or table, e.g. creating an
+// account with its default categories, so a failure halfway leaves nothing behind.
+type UnitOfWork interface {
+	// Do runs fn with repositories bound to a new transaction.
+	//
+	// Parameters:
+	//   - fn: The writes to apply; it must only use the repositories it receives
+	//
+	// Returns:
+	//   - error: The error returned by fn, or the storage error of committing the transaction;
+	//     in both cases none of the writes made by fn are kept
+	//
+	// Note: Models returned by Create and Update inside fn hold their final values once Do
+	// returns nil. Some implementations (the Supabase one) apply the writes when fn returns,
+	// so reads inside fn only see what was committed before Do started.
+	Do(fn func(repos UnitOfWorkRepositories) error) error
+}
+
+// LockingRepository is a Repository whose rows can be read for an update inside a unit of work
+type LockingRepository[T any, ID comparable] interface {
+	Repository[T, ID]
+
+	// GetForUpdate retrieves an entity and keeps it from changing until the unit of work ends.
+	// Use it to compute a new value from the current one, e.g. a balance, so two units of work
+	// racing on the same row can't both write a value computed from the same read.
+	//
+	// Parameters:
+	//   - id: The unique identifier of the entity to retrieve
+	//
+	// Returns:
+	//   - *T: The entity as it is stored
+	//   - error: ErrNotFound (types) if no entity has that id, or another error if the storage fails;
+	//     implementations that can't lock (the Supabase one) fail the whole unit of work instead
+	//     if the row changed before it is applied
+	GetForUpdate(id ID) (*T, error)
+}
+
+// UnitOfWorkRepositories are the repositories available inside a unit of work
+type UnitOfWorkRepositories struct {
+	Users        Repository[db.User, int]
+	Wallets      LockingRepository[db.Wallet, int]
+	Transactions Repository[db.Transaction, int]
+	Categories   Repository[db.Category, int]
+}
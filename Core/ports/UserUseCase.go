@@ -21,19 +21,32 @@ type UserUseCase interface {
 	//   - *response.ErrorResponse: Error response if account creation fails (e.g., duplicate email, invalid input)
 	CreateAccount(nick string, email string, password string) (*response.SuccessResponse[*response.CreateAccountResponse], *[]response.ErrorResponse)
 
+	// GetAccount returns the account of the authenticated user.
+	//
+	// Parameters:
+	//   - userID: The ID of the authenticated user
+	//
+	// Returns:
+	//   - *response.AccountResponse: The account, without its password hash
+	//   - *response.ErrorResponse: Error response if the account can't be fetched (e.g., account not found)
+	GetAccount(userID int) (*response.AccountResponse, *response.ErrorResponse)
+
 	// DestroyAccount permanently deletes a user account identified by email.
 	//
 	// Parameters:
-	//   - email: The email of the account to be deleted
+	//   - userID: The ID of the authenticated user; other accounts are reported as not found
+	//   - email:  The email of the account to be deleted
 	//
 	// Returns:
 	//   - *response.ErrorResponse: Error response if account deletion fails (e.g., account not found, permission denied)
-	DestroyAccount(email string) *[]response.ErrorResponse
+	DestroyAccount(userID int, email string) *[]response.ErrorResponse
 
 	// UpdateAccount modifies an existing user's account information.
+	// The status can't be changed here; it belongs to email verification and the admin API.
 	//
 	// Parameters:
-	//   - user: A db.UpdateAccountRequest containing the fields to be updated
+	//   - user: A db.UpdateAccountRequest containing the fields to be updated; its ID must be
+	//           the authenticated user and match the account of its email
 	//
 	// Returns:
 	//   - *response.SuccessResponse[*response.UpdateAccountResponse]: Wrapped success response containing the updated account details
@@ -41,12 +54,16 @@ type UserUseCase interface {
 	UpdateAccount(user db.UpdateAccountRequest) (*response.SuccessResponse[*response.UpdateAccountResponse], *[]response.ErrorResponse)
 
 	// Login authenticates a user with the provided credentials.
+	// The account is identified by its email or, when no email is given, by its nickname.
 	//
 	// Parameters:
 	//   - auth: An AuthRequest containing the user's login credentials
 	//
 	// Returns:
-	//   - *string: A JWT token if authentication is successful
-	//   - error: Error if authentication fails (e.g., invalid credentials)
-	Login(auth dtos.AuthRequest) (*string, error)
+	//   - *int:  The ID of the authenticated user, used as the subject of its tokens
+	//   - error: ErrInvalidCredentials (types) for an unknown account or a wrong password,
+	//            ErrAccountSuspended (types) if an administrator suspended the account,
+	//            ErrAccountNotVerified (types) if the email must be verified first,
+	//            or another error if the account can't be fetched
+	Login(auth dtos.AuthRequest) (*int, error)
 }
@@ -0,0 +1,36 @@
+package ports
+
+import (
+	response "Financial/Core/Models/dtos/Response"
+)
+
+// VerificationUseCase proves that users own the email address of their account.
+// New accounts get a signed, expiring link by email; opening it activates the account.
+type VerificationUseCase interface {
+	// SendVerification emails a verification link to the user.
+	//
+	// Parameters:
+	//   - userID: The account to verify
+	//
+	// Returns:
+	//   - *response.ErrorResponse: Error if the account doesn't exist, is already verified,
+	//     or the email can't be sent
+	SendVerification(userID int) *response.ErrorResponse
+
+	// ResendVerification emails a new link to the account registered with an email address.
+	// It never reports whether the address belongs to an account, so it can't be used to probe for users.
+	//
+	// Parameters:
+	//   - email: The address the user signed up with
+	ResendVerification(email string)
+
+	// VerifyEmail activates the account a verification token was issued for.
+	// A token works once: after the account is active it is no longer accepted.
+	//
+	// Parameters:
+	//   - token: The token from the verification link
+	//
+	// Returns:
+	//   - *response.ErrorResponse: Error if the token is malformed, forged, expired or already used
+	VerifyEmail(token string) *response.ErrorResponse
+}
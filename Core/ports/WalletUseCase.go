@@ -4,6 +4,7 @@ import (
 	"Financial/Core/Models/db"
 	dtos "Financial/Core/Models/dtos/Request"
 	response "Financial/Core/Models/dtos/Response"
+	"time"
 )
 
 // WalletUseCase defines the interface for wallet-related business logic operations.
@@ -14,6 +15,7 @@ type WalletUseCase interface {
 	//   - name:      The name of the wallet (must be unique per user)
 	//   - walletType: The type of wallet (e.g., checking, savings, credit)
 	//   - balance:    Initial balance of the wallet (must be >= 0)
+	//   - currency:   ISO 4217 code of the wallet (defaults to types.DefaultCurrency)
 	//   - userID:     ID of the user who owns the wallet
 	//
 	// Returns:
@@ -24,6 +26,7 @@ type WalletUseCase interface {
 	// UpdateWallet updates an existing wallet with new information
 	//
 	// Parameters:
+	//   - userID:    ID of the authenticated user; wallets of other users are reported as not found
 	//   - walletID:  ID of the wallet to update
 	//   - name:      New name for the wallet (optional)
 	//   - walletType: New type for the wallet (optional)
@@ -32,25 +35,28 @@ type WalletUseCase interface {
 	// Returns:
 	//   - *models.Wallet: The updated wallet
 	//   - error:         Error if update fails (e.g., invalid data, wallet not found)
-	UpdateWallet(request dtos.UpdateWalletRequest) (*db.Wallet, *response.ErrorResponse)
+	UpdateWallet(userID int, request dtos.UpdateWalletRequest) (*db.Wallet, *response.ErrorResponse)
 
 	// DeleteWallet removes a wallet by its ID
 	//
 	// Parameters:
+	//   - userID:   ID of the authenticated user; wallets of other users are reported as not found
 	//   - walletID: ID of the wallet to delete
 	//
 	// Returns:
 	//   - error: Error if deletion fails (e.g., wallet not found)
-	DeleteWallet(walletID int) error
+	DeleteWallet(userID int, walletID int) error
 
 	// GetUserWallet retrieves wallet information for a specific user
 	//
 	// Parameters:
-	//   - id:    The ID of the wallet to retrieve
-	//   - email: Email of the user requesting the wallet (for authorization)
+	//   - id:                ID of the user the wallets must belong to; 0 lifts the restriction (support staff)
+	//   - email:             Email of the owner of the wallets; empty lists the wallets of id
+	//   - reportingCurrency: Optional ISO 4217 code; when set every balance is also converted to it
+	//   - asOf:              Moment whose exchange rates are used (zero means now)
 	//
 	// Returns:
-	//   - *response.UserWalletResponse: The wallet information including balance and transactions
-	//   - error: Error if retrieval fails (e.g., wallet not found, unauthorized access)
-	GetUserWallet(id int, email string) (*response.UserWalletResponse, *response.ErrorResponse)
+	//   - *response.UserWalletResponse: The wallet information including balance, transactions and totals
+	//   - error: Error if retrieval fails (e.g., wallet not found, unauthorized access, missing exchange rate)
+	GetUserWallet(id int, email string, reportingCurrency string, asOf time.Time) (*response.UserWalletResponse, *response.ErrorResponse)
 }
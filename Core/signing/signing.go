@@ -0,0 +1,347 @@
+// Package signing keeps the asymmetric keys access tokens are signed with. One key signs;
+// the others only verify, so tokens signed before a rotation stay valid until they expire.
+// Keys are identified by their RFC 7638 thumbprint, used as the kid of tokens and of the JWKS.
+package signing
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// RS256 is RSASSA-PKCS1-v1_5 with SHA-256, for RSA keys
+	RS256 = "RS256"
+
+	// EdDSA is Ed25519, for OKP keys
+	EdDSA = "EdDSA"
+)
+
+// minRSABits is the smallest RSA modulus accepted
+const minRSABits = 2048
+
+// Key is a private signing key
+type Key struct {
+	// ID is the thumbprint of the public key, sent as the kid header
+	ID string
+
+	// Algorithm is the JWS algorithm of the key: RS256 or EdDSA
+	Algorithm string
+
+	// Private signs the tokens
+	Private crypto.Signer
+}
+
+// Public is the key tokens are verified with
+func (k *Key) Public() crypto.PublicKey {
+	return k.Private.Public()
+}
+
+// NewKey wraps an RSA (2048 bits or more) or Ed25519 private key
+func NewKey(private crypto.Signer) (*Key, error) {
+	var algorithm string
+	switch key := private.(type) {
+	case *rsa.PrivateKey:
+		if key.N.BitLen() < minRSABits {
+			return nil, fmt.Errorf("RSA keys need at least %d bits", minRSABits)
+		}
+		algorithm = RS256
+	case ed25519.PrivateKey:
+		algorithm = EdDSA
+	default:
+		return nil, fmt.Errorf("unsupported key type %T; use RSA or Ed25519", private)
+	}
+
+	key := &Key{Algorithm: algorithm, Private: private}
+	key.ID = thumbprint(key.jwk())
+	return key, nil
+}
+
+// GenerateKey creates a new Ed25519 key
+func GenerateKey() (*Key, error) {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return NewKey(private)
+}
+
+// ParsePrivateKey reads a PEM private key: PKCS#8 (RSA or Ed25519) or PKCS#1 (RSA)
+func ParsePrivateKey(data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	var private any
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		private, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		private, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	signer, ok := private.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported key type %T", private)
+	}
+	return NewKey(signer)
+}
+
+// entry is a key of the ring; retiresAt is zero while the key doesn't expire
+type entry struct {
+	key       *Key
+	retiresAt time.Time
+}
+
+// KeyRing holds the current signing key and the keys still accepted for verification.
+// It is safe for concurrent use.
+type KeyRing struct {
+	mu        sync.RWMutex
+	keys      map[string]*entry
+	current   string
+	retention time.Duration
+	now       func() time.Time
+}
+
+// NewKeyRing creates a ring whose last key signs; the others only verify
+func NewKeyRing(keys ...*Key) (*KeyRing, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("no signing keys")
+	}
+	ring := &KeyRing{keys: map[string]*entry{}, now: time.Now}
+	for _, key := range keys {
+		ring.keys[key.ID] = &entry{key: key}
+	}
+	ring.current = keys[len(keys)-1].ID
+	return ring, nil
+}
+
+// LoadDir creates a ring from the *.pem files of a directory. Files are sorted by name and the
+// last one signs, so naming them by date (e.g. 2025-07-01.pem) makes the newest the signer.
+func LoadDir(dir string) (*KeyRing, error) {
+	keys, err := readDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	return NewKeyRing(keys...)
+}
+
+// SetRetention sets how long a key stays valid for verification after it is retired.
+// It must be at least the lifetime of the tokens it signed.
+func (r *KeyRing) SetRetention(retention time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.retention = retention
+}
+
+// Current is the key new tokens are signed with
+func (r *KeyRing) Current() *Key {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.keys[r.current].key
+}
+
+// Lookup finds a key that may still verify tokens
+func (r *KeyRing) Lookup(id string) (*Key, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entry, ok := r.keys[id]
+	if !ok || r.retired(entry) {
+		return nil, false
+	}
+	return entry.key, true
+}
+
+// Rotate makes key the signing key. The previous one keeps verifying for the retention period.
+func (r *KeyRing) Rotate(key *Key) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if key.ID == r.current {
+		return
+	}
+	if previous, ok := r.keys[r.current]; ok && previous.retiresAt.IsZero() {
+		previous.retiresAt = r.now().Add(r.retention)
+	}
+	r.keys[key.ID] = &entry{key: key}
+	r.current = key.ID
+	r.prune()
+}
+
+// ReloadDir brings the ring in line with a key directory: new files are added, the last file
+// signs, and keys whose file was removed keep verifying for the retention period.
+func (r *KeyRing) ReloadDir(dir string) error {
+	keys, err := readDir(dir)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	present := map[string]bool{}
+	for _, key := range keys {
+		present[key.ID] = true
+		if existing, ok := r.keys[key.ID]; ok {
+			existing.retiresAt = time.Time{}
+		} else {
+			r.keys[key.ID] = &entry{key: key}
+		}
+	}
+	for id, entry := range r.keys {
+		if !present[id] && entry.retiresAt.IsZero() {
+			entry.retiresAt = r.now().Add(r.retention)
+		}
+	}
+	r.current = keys[len(keys)-1].ID
+	r.prune()
+	return nil
+}
+
+// WatchDir reloads the directory every interval until the context is cancelled, so a new key
+// dropped in the directory starts signing on every instance without a restart
+func (r *KeyRing) WatchDir(ctx context.Context, dir string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.ReloadDir(dir); err != nil {
+				// The keys already loaded keep working until the directory is fixed
+				log.Printf("signing keys: %v", err)
+			}
+		}
+	}
+}
+
+// JWKS is the public JSON Web Key Set of the keys that may still verify tokens
+func (r *KeyRing) JWKS() JSONWebKeySet {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ids := make([]string, 0, len(r.keys))
+	for id, entry := range r.keys {
+		if !r.retired(entry) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	set := JSONWebKeySet{Keys: make([]JSONWebKey, 0, len(ids))}
+	for _, id := range ids {
+		set.Keys = append(set.Keys, r.keys[id].key.jwk())
+	}
+	return set
+}
+
+// retired reports whether the retention of a key is over; callers hold the lock
+func (r *KeyRing) retired(entry *entry) bool {
+	return !entry.retiresAt.IsZero() && !r.now().Before(entry.retiresAt)
+}
+
+// prune forgets the keys whose retention is over; callers hold the write lock
+func (r *KeyRing) prune() {
+	for id, entry := range r.keys {
+		if id != r.current && r.retired(entry) {
+			delete(r.keys, id)
+		}
+	}
+}
+
+// readDir parses the *.pem files of a directory, sorted by name
+func readDir(dir string) ([]*Key, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no *.pem keys in %s", dir)
+	}
+	sort.Strings(paths)
+
+	keys := make([]*Key, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		key, err := ParsePrivateKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// JSONWebKey is the public part of a key (RFC 7517)
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JSONWebKeySet is the document served at /.well-known/jwks.json
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// jwk describes the public key; the kid is the thumbprint once NewKey has set it
+func (k *Key) jwk() JSONWebKey {
+	jwk := JSONWebKey{Use: "sig", Alg: k.Algorithm, Kid: k.ID}
+	switch public := k.Public().(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(public)
+	}
+	return jwk
+}
+
+// thumbprint is the RFC 7638 thumbprint: SHA-256 of the required members in lexicographic order
+func thumbprint(jwk JSONWebKey) string {
+	var members string
+	switch jwk.Kty {
+	case "RSA":
+		members = fmt.Sprintf(`{"e":%q,"kty":"RSA","n":%q}`, jwk.E, jwk.N)
+	case "OKP":
+		members = fmt.Sprintf(`{"crv":%q,"kty":"OKP","x":%q}`, jwk.Crv, jwk.X)
+	}
+	sum := sha256.Sum256([]byte(members))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
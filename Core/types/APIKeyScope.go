@@ -0,0 +1,33 @@
+package types
+
+import "strings"
+
+// APIKeyScope limits what a personal API key can reach; keys never reach routes without a scope
+type APIKeyScope string
+
+const (
+	ScopeWalletsRead  APIKeyScope = "wallets:read"
+	ScopeWalletsWrite APIKeyScope = "wallets:write"
+	ScopeAccountRead  APIKeyScope = "account:read"
+	ScopeAccountWrite APIKeyScope = "account:write"
+)
+
+// IsValid reports whether the scope is one of the known scopes
+func (s APIKeyScope) IsValid() bool {
+	switch s {
+	case ScopeWalletsRead, ScopeWalletsWrite, ScopeAccountRead, ScopeAccountWrite:
+		return true
+	}
+	return false
+}
+
+// Grants reports whether holding the scope allows a route that needs required.
+// Write access to a resource includes read access to it.
+func (s APIKeyScope) Grants(required APIKeyScope) bool {
+	if s == required {
+		return true
+	}
+	resource, access, _ := strings.Cut(string(s), ":")
+	requiredResource, requiredAccess, _ := strings.Cut(string(required), ":")
+	return resource == requiredResource && access == "write" && requiredAccess == "read"
+}
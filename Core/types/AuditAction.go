@@ -0,0 +1,12 @@
+package types
+
+// AuditAction names the events written to the audit log
+type AuditAction string
+
+const (
+	// AuditAccountLocked is written when too many failed logins lock an account out
+	AuditAccountLocked AuditAction = "account_locked"
+
+	// AuditAccountUnlocked is written when a lockout ends and the account gets its status back
+	AuditAccountUnlocked AuditAction = "account_unlocked"
+)
@@ -0,0 +1,11 @@
+package types
+
+// Frequency is the base unit of a recurring schedule, as in the FREQ part of an RRULE
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
@@ -0,0 +1,229 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MoneyScale is the number of decimal places kept by Money. It matches the
+// DECIMAL(15,2) columns used for balances and amounts in the database.
+const MoneyScale = 2
+
+const minorPerUnit = 100
+
+var (
+	ErrInvalidMoney     = errors.New("invalid money amount")
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+)
+
+// Money is an exact monetary amount stored as integer minor units (cents)
+// together with its ISO 4217 currency code.
+//
+// An empty Currency means the currency is not known yet (e.g. a value decoded
+// from JSON); it is compatible with any other currency in arithmetic and
+// comparisons and adopts the currency of the other operand.
+//
+// In JSON a Money is written as a plain decimal number (e.g. 1000.50) so it maps
+// directly to the numeric database columns; the currency travels separately.
+type Money struct {
+	// Minor is the amount in minor units (1050 is 10.50)
+	Minor int64
+
+	// Currency is the ISO 4217 code of the amount (e.g. "USD", "DOP")
+	Currency string
+}
+
+// NewMoney builds a Money from an amount in minor units.
+func NewMoney(minor int64, currency string) Money {
+	return Money{Minor: minor, Currency: strings.ToUpper(currency)}
+}
+
+// ParseMoney parses a decimal string such as "1250", "-3.5" or "0.99" into Money.
+// More than MoneyScale significant decimal places are rejected instead of rounded.
+func ParseMoney(value string, currency string) (Money, error) {
+	s := strings.TrimSpace(value)
+	if s == "" {
+		return Money{}, fmt.Errorf("%w: empty value", ErrInvalidMoney)
+	}
+
+	negative := false
+	switch s[0] {
+	case '-':
+		negative = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+
+	whole, fraction, hasFraction := strings.Cut(s, ".")
+	if whole == "" && (!hasFraction || fraction == "") {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidMoney, value)
+	}
+	if whole == "" {
+		whole = "0"
+	}
+	if !isDigits(whole) || (hasFraction && !isDigits(fraction)) {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidMoney, value)
+	}
+
+	// Extra decimal places are only allowed when they are zeros (e.g. "10.500")
+	if len(fraction) > MoneyScale {
+		if strings.Trim(fraction[MoneyScale:], "0") != "" {
+			return Money{}, fmt.Errorf("%w: %q has more than %d decimal places", ErrInvalidMoney, value, MoneyScale)
+		}
+		fraction = fraction[:MoneyScale]
+	}
+	fraction += strings.Repeat("0", MoneyScale-len(fraction))
+
+	minor, err := strconv.ParseInt(whole+fraction, 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("%w: %q is out of range", ErrInvalidMoney, value)
+	}
+	if negative {
+		minor = -minor
+	}
+
+	return NewMoney(minor, currency), nil
+}
+
+// MoneyFromUnits builds a Money from a whole amount of major units (100 is 100.00).
+func MoneyFromUnits(units int64, currency string) Money {
+	return NewMoney(units*minorPerUnit, currency)
+}
+
+// MustParseMoney is like ParseMoney but panics on invalid input.
+// It is meant for constants and tests.
+func MustParseMoney(value string, currency string) Money {
+	m, err := ParseMoney(value, currency)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// currencyWith returns the currency shared by m and other, or ErrCurrencyMismatch.
+func (m Money) currencyWith(other Money) (string, error) {
+	switch {
+	case m.Currency == "":
+		return other.Currency, nil
+	case other.Currency == "" || m.Currency == other.Currency:
+		return m.Currency, nil
+	default:
+		return "", fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+}
+
+// Add returns m + other.
+func (m Money) Add(other Money) (Money, error) {
+	currency, err := m.currencyWith(other)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{Minor: m.Minor + other.Minor, Currency: currency}, nil
+}
+
+// Subtract returns m - other.
+func (m Money) Subtract(other Money) (Money, error) {
+	return m.Add(other.Negate())
+}
+
+// Compare returns -1, 0 or 1 when m is less than, equal to or greater than other.
+func (m Money) Compare(other Money) (int, error) {
+	if _, err := m.currencyWith(other); err != nil {
+		return 0, err
+	}
+	switch {
+	case m.Minor < other.Minor:
+		return -1, nil
+	case m.Minor > other.Minor:
+		return 1, nil
+	default:
+		return 0, nil
+	}
+}
+
+// Negate returns the amount with the opposite sign.
+func (m Money) Negate() Money {
+	return Money{Minor: -m.Minor, Currency: m.Currency}
+}
+
+// Abs returns the absolute value of the amount.
+func (m Money) Abs() Money {
+	if m.Minor < 0 {
+		return m.Negate()
+	}
+	return m
+}
+
+// WithCurrency returns the same amount tagged with the given currency.
+func (m Money) WithCurrency(currency string) Money {
+	return NewMoney(m.Minor, currency)
+}
+
+func (m Money) IsZero() bool     { return m.Minor == 0 }
+func (m Money) IsPositive() bool { return m.Minor > 0 }
+func (m Money) IsNegative() bool { return m.Minor < 0 }
+
+// Decimal returns the amount as a decimal string with MoneyScale places (e.g. "-12.05").
+func (m Money) Decimal() string {
+	minor := m.Minor
+	sign := ""
+	if minor < 0 {
+		sign = "-"
+		minor = -minor
+	}
+	return fmt.Sprintf("%s%d.%0*d", sign, minor/minorPerUnit, MoneyScale, minor%minorPerUnit)
+}
+
+// String returns the decimal amount followed by the currency code, when known.
+func (m Money) String() string {
+	if m.Currency == "" {
+		return m.Decimal()
+	}
+	return m.Decimal() + " " + m.Currency
+}
+
+// MarshalJSON writes the amount as a JSON number with MoneyScale decimal places.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.Decimal()), nil
+}
+
+// UnmarshalJSON reads a JSON number or numeric string without going through float64.
+// The currency of the receiver is kept.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	raw := strings.TrimSpace(string(data))
+	if raw == "null" {
+		return nil
+	}
+	if strings.HasPrefix(raw, `"`) {
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return err
+		}
+	}
+	parsed, err := ParseMoney(raw, m.Currency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// MoneyPtr returns a pointer to the given Money value.
+func MoneyPtr(m Money) *Money {
+	return &m
+}
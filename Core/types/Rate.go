@@ -0,0 +1,121 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// DefaultCurrency is the currency assigned to wallets created without one.
+const DefaultCurrency = "USD"
+
+var (
+	ErrInvalidRate          = errors.New("invalid exchange rate")
+	ErrExchangeRateNotFound = errors.New("exchange rate not found")
+)
+
+// Rate is an exact exchange rate: how many units of the quote currency one unit
+// of the base currency is worth. It is kept as a rational number so conversions
+// don't pick up float64 rounding before the final rounding to minor units.
+//
+// In JSON a Rate is written as a plain decimal number (e.g. 58.75), matching the
+// NUMERIC column used to store it.
+type Rate struct {
+	value *big.Rat
+}
+
+// ParseRate parses a positive decimal string such as "58.75" or "0.017".
+func ParseRate(value string) (Rate, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(value))
+	if !ok || strings.ContainsAny(value, "/eE") {
+		return Rate{}, fmt.Errorf("%w: %q", ErrInvalidRate, value)
+	}
+	if r.Sign() <= 0 {
+		return Rate{}, fmt.Errorf("%w: %q must be greater than zero", ErrInvalidRate, value)
+	}
+	return Rate{value: r}, nil
+}
+
+// MustParseRate is like ParseRate but panics on invalid input.
+// It is meant for constants and tests.
+func MustParseRate(value string) Rate {
+	r, err := ParseRate(value)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// IdentityRate is the rate between a currency and itself.
+func IdentityRate() Rate {
+	return Rate{value: big.NewRat(1, 1)}
+}
+
+// IsZero reports whether the rate was never set.
+func (r Rate) IsZero() bool {
+	return r.value == nil || r.value.Sign() == 0
+}
+
+// Inverse returns the rate in the opposite direction (quote to base).
+func (r Rate) Inverse() Rate {
+	if r.IsZero() {
+		return r
+	}
+	return Rate{value: new(big.Rat).Inv(r.value)}
+}
+
+// String returns the rate as a decimal with up to 8 places, the precision of the rates table.
+func (r Rate) String() string {
+	if r.value == nil {
+		return "0"
+	}
+	s := r.value.FloatString(8)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// MarshalJSON writes the rate as a JSON number.
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalJSON reads a JSON number or numeric string without going through float64.
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	raw := strings.TrimSpace(string(data))
+	if raw == "null" {
+		return nil
+	}
+	if strings.HasPrefix(raw, `"`) {
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return err
+		}
+	}
+	parsed, err := ParseRate(raw)
+	if err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}
+
+// Convert returns the amount expressed in currency using rate, rounded half away
+// from zero to minor units. The rate must go from m.Currency to currency.
+func (m Money) Convert(rate Rate, currency string) Money {
+	if rate.IsZero() {
+		return NewMoney(0, currency)
+	}
+	product := new(big.Rat).Mul(new(big.Rat).SetInt64(m.Minor), rate.value)
+
+	num := new(big.Int).Abs(product.Num())
+	quotient, remainder := new(big.Int).QuoRem(num, product.Denom(), new(big.Int))
+	if remainder.Lsh(remainder, 1).Cmp(product.Denom()) >= 0 {
+		quotient.Add(quotient, big.NewInt(1))
+	}
+	minor := quotient.Int64()
+	if product.Sign() < 0 {
+		minor = -minor
+	}
+	return NewMoney(minor, currency)
+}
@@ -0,0 +1,10 @@
+package types
+
+// RecurringStatus tells whether the scheduler keeps posting a recurring series
+type RecurringStatus string
+
+const (
+	RecurringActive RecurringStatus = "active"
+	RecurringPaused RecurringStatus = "paused"
+	RecurringEnded  RecurringStatus = "ended"
+)
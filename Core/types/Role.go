@@ -0,0 +1,47 @@
+package types
+
+// Role decides what a user may do beyond managing their own data
+type Role string
+
+const (
+	RoleUser    Role = "user"
+	RoleSupport Role = "support"
+	RoleAdmin   Role = "admin"
+)
+
+// Permission is an operation on data that doesn't belong to the caller
+type Permission string
+
+const (
+	// PermUsersRead lists and inspects any account
+	PermUsersRead Permission = "users:read"
+	// PermUsersManage changes the status of any account (e.g. suspends it)
+	PermUsersManage Permission = "users:manage"
+	// PermRolesManage changes the role of any account
+	PermRolesManage Permission = "roles:manage"
+	// PermWalletsRead inspects the wallets of any account
+	PermWalletsRead Permission = "wallets:read"
+)
+
+// rolePermissions lists what each role may do; plain users only act on their own data
+var rolePermissions = map[Role][]Permission{
+	RoleUser:    {},
+	RoleSupport: {PermUsersRead, PermWalletsRead},
+	RoleAdmin:   {PermUsersRead, PermUsersManage, PermRolesManage, PermWalletsRead},
+}
+
+// IsValid reports whether the role is one of the known roles
+func (r Role) IsValid() bool {
+	_, ok := rolePermissions[r]
+	return ok
+}
+
+// Can reports whether the role grants the permission
+func (r Role) Can(permission Permission) bool {
+	for _, granted := range rolePermissions[r] {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,8 @@
+package types
+
+type TransactionType string
+
+const (
+	Income  TransactionType = "Income"
+	Expense TransactionType = "Expense"
+)
@@ -3,3 +3,45 @@ package types
 import "errors"
 
 var ErrNotFound = errors.New("record not found")
+
+// ErrUnknownHash is returned when a stored password is not in a hash format the hasher reads
+var ErrUnknownHash = errors.New("unknown password hash format")
+
+// ErrInvalidCredentials is returned by login for both unknown accounts and wrong passwords,
+// so callers can't tell which accounts exist
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// ErrAccountNotVerified is returned by login when the password is right but the email address
+// of the account has not been verified yet
+var ErrAccountNotVerified = errors.New("account email not verified")
+
+// ErrSessionRevoked is returned when an access token belongs to a session that was rotated, logged out or expired
+var ErrSessionRevoked = errors.New("session revoked or expired")
+
+// ErrAccountSuspended is returned by login when the password is right but the account was suspended
+var ErrAccountSuspended = errors.New("account suspended")
+
+// ErrForbidden is returned when the role of a user doesn't grant the requested permission
+var ErrForbidden = errors.New("permission denied")
+
+// ErrInvalidAPIKey is returned for unknown, revoked and expired API keys alike
+var ErrInvalidAPIKey = errors.New("invalid or expired API key")
+
+// ErrExternalLoginFailed is returned when a login through an identity provider can't be completed:
+// unknown or expired state, a code the provider rejects, or an ID token that fails verification
+var ErrExternalLoginFailed = errors.New("external login failed")
+
+// ErrAccountNotLinked is returned when an identity provider vouches for a user that has no account
+// here and accounts can't be created automatically (or the provider didn't verify the email)
+var ErrAccountNotLinked = errors.New("no account is linked to this identity")
+
+// ErrTooManyAttempts is returned when an account or a client address has to wait after failed logins.
+// It is returned for unknown accounts as well, so it doesn't reveal which accounts exist
+var ErrTooManyAttempts = errors.New("too many failed login attempts")
+
+// ErrInvalidTwoFactorCode is the single answer for a wrong, reused or expired two-factor code
+var ErrInvalidTwoFactorCode = errors.New("invalid two-factor code")
+
+// ErrCurrentPasswordRequired is returned when the password or email of an account is changed
+// without confirming the current password, or with a wrong one
+var ErrCurrentPasswordRequired = errors.New("the current password is required to change the password or email")
@@ -0,0 +1,76 @@
+package engine
+
+import (
+	"Financial/Core/types"
+	"reflect"
+)
+
+// compareValues compara value con expected para las reglas ShouldGreatThah,
+// ShouldGreaterOrEqualThan, ShouldLessThat y ShouldLessOrEqualThat.
+//
+// Devuelve el resultado de la comparación (-1, 0, 1), si los tipos eran comparables
+// y si la regla debe omitirse porque value es un puntero nil (campo opcional no enviado).
+//
+// Tipos soportados:
+//   - enteros contra enteros
+//   - types.Money contra types.Money o contra un entero (unidades enteras, ej. 0 o 100)
+func compareValues(value interface{}, expected interface{}) (cmp int, ok bool, skip bool) {
+	v := reflect.ValueOf(value)
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return 0, false, true
+		}
+		value = v.Elem().Interface()
+		v = v.Elem()
+	}
+
+	if money, isMoney := value.(types.Money); isMoney {
+		target, valid := toMoney(expected)
+		if !valid {
+			return 0, false, false
+		}
+		result, err := money.Compare(target)
+		if err != nil {
+			return 0, false, false
+		}
+		return result, true, false
+	}
+
+	e := reflect.ValueOf(expected)
+	if isInt(v) && isInt(e) {
+		switch {
+		case v.Int() < e.Int():
+			return -1, true, false
+		case v.Int() > e.Int():
+			return 1, true, false
+		default:
+			return 0, true, false
+		}
+	}
+
+	return 0, false, false
+}
+
+func toMoney(expected interface{}) (types.Money, bool) {
+	switch e := expected.(type) {
+	case types.Money:
+		return e, true
+	case *types.Money:
+		if e == nil {
+			return types.Money{}, false
+		}
+		return *e, true
+	}
+	if v := reflect.ValueOf(expected); isInt(v) {
+		return types.MoneyFromUnits(v.Int(), ""), true
+	}
+	return types.Money{}, false
+}
+
+func isInt(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+	return false
+}
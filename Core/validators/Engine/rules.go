@@ -69,8 +69,10 @@ func (r ValidationRule) Validate(value interface{}) *ValidationError {
 			}
 		}
 	case ShouldGreatThah:
-		if v.Kind() == reflect.Int && expected.Kind() == reflect.Int {
-			if v.Int() <= expected.Int() {
+		if cmp, ok, skip := compareValues(value, r.Expected); skip {
+			return nil
+		} else if ok {
+			if cmp <= 0 {
 				return &ValidationError{
 					Field:     r.FieldName,
 					Rule:      r.Rule,
@@ -87,8 +89,10 @@ func (r ValidationRule) Validate(value interface{}) *ValidationError {
 			}
 		}
 	case ShouldGreaterOrEqualThan:
-		if v.Kind() == reflect.Int && expected.Kind() == reflect.Int {
-			if v.Int() < expected.Int() {
+		if cmp, ok, skip := compareValues(value, r.Expected); skip {
+			return nil
+		} else if ok {
+			if cmp < 0 {
 				return &ValidationError{
 					Field:     r.FieldName,
 					Rule:      r.Rule,
@@ -105,8 +109,10 @@ func (r ValidationRule) Validate(value interface{}) *ValidationError {
 			}
 		}
 	case ShouldLessThat:
-		if v.Kind() == reflect.Int && expected.Kind() == reflect.Int {
-			if v.Int() >= expected.Int() {
+		if cmp, ok, skip := compareValues(value, r.Expected); skip {
+			return nil
+		} else if ok {
+			if cmp >= 0 {
 				return &ValidationError{
 					Field:     r.FieldName,
 					Rule:      r.Rule,
@@ -123,8 +129,10 @@ func (r ValidationRule) Validate(value interface{}) *ValidationError {
 			}
 		}
 	case ShouldLessOrEqualThat:
-		if v.Kind() == reflect.Int && expected.Kind() == reflect.Int {
-			if v.Int() > expected.Int() {
+		if cmp, ok, skip := compareValues(value, r.Expected); skip {
+			return nil
+		} else if ok {
+			if cmp > 0 {
 				return &ValidationError{
 					Field:     r.FieldName,
 					Rule:      r.Rule,
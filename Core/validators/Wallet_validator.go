@@ -7,6 +7,7 @@ import (
 	"Financial/Core/types"
 	engine "Financial/Core/validators/Engine"
 	"fmt"
+	"strings"
 )
 
 // ValidateWallet validates the CreateWalletRequest and returns validation results.
@@ -17,13 +18,13 @@ func ValidateWallet(data dtos.CreateWalletRequest) (bool, *[]string) {
 	validator := engine.NewValidator()
 
 	nameRule := []engine.PatialValidationRule{
-		{Rule: engine.ShouldNotEmpty, Expected: nil, Message: ""},
-		{Rule: engine.ShouldLength, Expected: 5, Message: ""},
+		{Rule: engine.Must, Expected: engine.CustomValidatorFunc(isNotBlank), Message: "wallet name cannot be empty"},
 	}
 
 	validator.AddRules("Name", nameRule)
-	validator.AddRule("Balance", engine.ShouldGreaterOrEqualThan, 0, "") // Fixed typo
-	validator.AddRule("UserID", engine.ShouldGreaterOrEqualThan, 0, "")  // Fixed typo
+	validator.AddRule("Balance", engine.ShouldGreaterOrEqualThan, 0, "initial balance cannot be negative")
+	validator.AddRule("UserID", engine.ShouldGreatThah, 0, "invalid user ID")
+	validator.AddRule("Currency", engine.ShouldMatch, `^[A-Z]{3}$`, "currency must be a 3-letter ISO 4217 code")
 
 	result := validator.Validate(data)
 
@@ -46,8 +47,9 @@ func UpdateWalletValidator(data dtos.UpdateWalletRequest, repository ports.Repos
 	var errors []string
 
 	validator := engine.NewValidator()
-	validator.AddRule("Name", engine.ShouldNotEmpty, nil, "")
-	validator.AddRule("Balance", engine.ShouldGreaterOrEqualThan, 0, "") // Fixed typo
+	validator.AddRule("WalletID", engine.ShouldGreatThah, 0, "invalid wallet ID")
+	// Name is optional on update; an empty name keeps the current one
+	validator.AddRule("Balance", engine.ShouldGreaterOrEqualThan, 0, "balance cannot be negative")
 
 	result := validator.Validate(data)
 
@@ -70,5 +72,14 @@ func UpdateWalletValidator(data dtos.UpdateWalletRequest, repository ports.Repos
 		return &errors, nil
 	}
 
-	return &errors, wallet
+	return nil, wallet
+}
+
+// isNotBlank rejects names made only of whitespace
+func isNotBlank(value interface{}) (bool, string) {
+	name, ok := value.(string)
+	if !ok || strings.TrimSpace(name) == "" {
+		return false, "value cannot be blank"
+	}
+	return true, ""
 }
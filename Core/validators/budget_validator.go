@@ -0,0 +1,36 @@
+package validators
+
+import (
+	dtos "Financial/Core/Models/dtos/Request"
+	engine "Financial/Core/validators/Engine"
+	"fmt"
+)
+
+// PeriodPattern is the format of a budget period (YYYY-MM)
+const PeriodPattern = `^[0-9]{4}-(0[1-9]|1[0-2])$`
+
+// ValidateBudget validates the CreateBudgetRequest and returns validation results.
+// Returns true with nil errors if valid, or false with a slice of error messages.
+func ValidateBudget(data dtos.CreateBudgetRequest) (bool, *[]string) {
+	var errors []string
+
+	validator := engine.NewValidator()
+	validator.AddRule("UserID", engine.ShouldGreatThah, 0, "invalid user ID")
+	validator.AddRule("CategoryID", engine.ShouldGreatThah, 0, "Category is required")
+	validator.AddRule("Period", engine.ShouldMatch, PeriodPattern, "period must have the format YYYY-MM")
+	validator.AddRule("Limit", engine.ShouldGreatThah, 0, "Limit must be greater than zero")
+	validator.AddRule("Currency", engine.ShouldMatch, `^[A-Z]{3}$`, "currency must be a 3-letter ISO 4217 code")
+
+	result := validator.Validate(data)
+
+	if result.IsValid() {
+		return true, nil
+	}
+
+	for _, err := range result.Errors {
+		errorMsg := fmt.Sprintf("Field: %s, Rule: %s, Message: %s", err.Field, err.Rule, err.Message)
+		errors = append(errors, errorMsg)
+	}
+
+	return false, &errors
+}
@@ -0,0 +1,93 @@
+package validators
+
+import (
+	dtos "Financial/Core/Models/dtos/Request"
+	"Financial/Core/types"
+	engine "Financial/Core/validators/Engine"
+	"fmt"
+)
+
+// isCategoryName rejects blank names and names longer than the column allows
+func isCategoryName(value interface{}) (bool, string) {
+	name, ok := value.(string)
+	if !ok {
+		return false, "Value is not a string"
+	}
+	if ok, message := isNotBlank(name); !ok {
+		return false, message
+	}
+	if len(name) > 100 {
+		return false, "value is too long"
+	}
+	return true, ""
+}
+
+// ValidateCategory validates the CreateCategoryRequest and returns validation results.
+// Returns true with nil errors if valid, or false with a slice of error messages.
+func ValidateCategory(data dtos.CreateCategoryRequest) (bool, *[]string) {
+	var errors []string
+
+	isKnownType := func(value interface{}) (bool, string) {
+		categoryType, ok := value.(types.TransactionType)
+		if !ok {
+			return false, "Value is not of type TransactionType"
+		}
+		switch categoryType {
+		case types.Income, types.Expense:
+			return true, ""
+		default:
+			return false, "Invalid TransactionType value"
+		}
+	}
+
+	validator := engine.NewValidator()
+	validator.AddRule("UserID", engine.ShouldGreatThah, 0, "invalid user ID")
+	validator.AddRule("Name", engine.Must, engine.CustomValidatorFunc(isCategoryName), "category name must have between 1 and 100 characters")
+	validator.AddRule("Type", engine.Must, engine.CustomValidatorFunc(isKnownType), "Type is not valid")
+	validator.AddRule("ParentID", engine.ShouldGreatThah, 0, "invalid parent category")
+
+	result := validator.Validate(data)
+
+	if result.IsValid() {
+		return true, nil
+	}
+
+	for _, err := range result.Errors {
+		errorMsg := fmt.Sprintf("Field: %s, Rule: %s, Message: %s", err.Field, err.Rule, err.Message)
+		errors = append(errors, errorMsg)
+	}
+
+	return false, &errors
+}
+
+// ValidateUpdateCategory validates the UpdateCategoryRequest and returns validation results.
+// Returns true with nil errors if valid, or false with a slice of error messages.
+func ValidateUpdateCategory(data dtos.UpdateCategoryRequest) (bool, *[]string) {
+	var errors []string
+
+	isOptionalName := func(value interface{}) (bool, string) {
+		if name, ok := value.(string); ok && name == "" {
+			return true, ""
+		}
+		return isCategoryName(value)
+	}
+
+	validator := engine.NewValidator()
+	validator.AddRule("CategoryID", engine.ShouldGreatThah, 0, "invalid category ID")
+	validator.AddRule("UserID", engine.ShouldGreatThah, 0, "invalid user ID")
+	validator.AddRule("Name", engine.Must, engine.CustomValidatorFunc(isOptionalName), "category name must have between 1 and 100 characters")
+	validator.AddRule("ParentID", engine.ShouldGreatThah, 0, "invalid parent category")
+
+	result := validator.Validate(data)
+
+	if result.IsValid() {
+		return true, nil
+	}
+
+	for _, err := range result.Errors {
+		errorMsg := fmt.Sprintf("Field: %s, Rule: %s, Message: %s", err.Field, err.Rule, err.Message)
+		errors = append(errors, errorMsg)
+	}
+
+	return false, &errors
+}
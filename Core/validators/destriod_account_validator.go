@@ -2,6 +2,7 @@ package validators
 
 import (
 	"Financial/Core/Models/db"
+	dtos "Financial/Core/Models/dtos/Request"
 	"Financial/Core/ports"
 	engine "Financial/Core/validators/Engine"
 )
@@ -27,6 +28,7 @@ func DestroidAccountValidator(email string, repo ports.Repository[db.User, int])
 		{Rule: engine.Must, Expected: engine.CustomValidatorFunc(detectValidMail), Message: "Email not exists"},
 	}
 	validator.AddRules("Email", emailrules)
-	errors := validator.Validate(email)
+	// The engine validates struct fields, a bare string was always rejected as an invalid type
+	errors := validator.Validate(dtos.DeleteAccountRequest{Email: email})
 	return &errors
 }
@@ -0,0 +1,90 @@
+package validators
+
+import (
+	dtos "Financial/Core/Models/dtos/Request"
+	"Financial/Core/types"
+	engine "Financial/Core/validators/Engine"
+	"fmt"
+	"time"
+)
+
+// ValidateRecurring validates the CreateRecurringRequest and returns validation results.
+// Returns true with nil errors if valid, or false with a slice of error messages.
+func ValidateRecurring(data dtos.CreateRecurringRequest) (bool, *[]string) {
+	var errors []string
+
+	isKnownType := func(value interface{}) (bool, string) {
+		transactionType, ok := value.(types.TransactionType)
+		if !ok {
+			return false, "Value is not of type TransactionType"
+		}
+		switch transactionType {
+		case types.Income, types.Expense:
+			return true, ""
+		default:
+			return false, "Invalid TransactionType value"
+		}
+	}
+
+	isKnownFrequency := func(value interface{}) (bool, string) {
+		frequency, ok := value.(types.Frequency)
+		if !ok {
+			return false, "Value is not of type Frequency"
+		}
+		switch frequency {
+		case types.Daily, types.Weekly, types.Monthly, types.Yearly:
+			return true, ""
+		default:
+			return false, "Invalid Frequency value"
+		}
+	}
+
+	// Days of the month only make sense when the period is at least a month
+	areMonthDays := func(value interface{}) (bool, string) {
+		days, ok := value.([]int)
+		if !ok {
+			return false, "Value is not a list of days"
+		}
+		if len(days) > 0 && data.Frequency != types.Monthly && data.Frequency != types.Yearly {
+			return false, "days of the month require a MONTHLY or YEARLY frequency"
+		}
+		for _, day := range days {
+			if day == 0 || day < -31 || day > 31 {
+				return false, "days must be between 1 and 31 or between -31 and -1"
+			}
+		}
+		return true, ""
+	}
+
+	isAfterStart := func(value interface{}) (bool, string) {
+		endDate, ok := value.(*time.Time)
+		if !ok || endDate == nil {
+			return true, ""
+		}
+		return !endDate.Before(data.StartDate), ""
+	}
+
+	validator := engine.NewValidator()
+	validator.AddRule("UserID", engine.ShouldGreatThah, 0, "invalid user ID")
+	validator.AddRule("WalletID", engine.ShouldGreatThah, 0, "invalid wallet ID")
+	validator.AddRule("Type", engine.Must, engine.CustomValidatorFunc(isKnownType), "Type is not valid")
+	validator.AddRule("Amount", engine.ShouldGreatThah, 0, "Amount must be greater than zero")
+	validator.AddRule("CategoryID", engine.ShouldGreatThah, 0, "invalid category")
+	validator.AddRule("Frequency", engine.Must, engine.CustomValidatorFunc(isKnownFrequency), "frequency must be DAILY, WEEKLY, MONTHLY or YEARLY")
+	validator.AddRule("Interval", engine.ShouldGreatThah, 0, "interval must be greater than zero")
+	validator.AddRule("ByMonthDay", engine.Must, engine.CustomValidatorFunc(areMonthDays), "invalid days of the month")
+	validator.AddRule("EndDate", engine.Must, engine.CustomValidatorFunc(isAfterStart), "end date can't be before the start date")
+
+	result := validator.Validate(data)
+
+	if result.IsValid() {
+		return true, nil
+	}
+
+	for _, err := range result.Errors {
+		errorMsg := fmt.Sprintf("Field: %s, Rule: %s, Message: %s", err.Field, err.Rule, err.Message)
+		errors = append(errors, errorMsg)
+	}
+
+	return false, &errors
+}
@@ -0,0 +1,30 @@
+package validators
+
+import (
+	dtos "Financial/Core/Models/dtos/Request"
+	engine "Financial/Core/validators/Engine"
+	"fmt"
+)
+
+// ValidateResetPassword validates the ResetPasswordRequest with the same password rules as account updates.
+// Returns true with nil errors if valid, or false with a slice of error messages.
+func ValidateResetPassword(data dtos.ResetPasswordRequest) (bool, *[]string) {
+	var errors []string
+
+	validator := engine.NewValidator()
+	validator.AddRule("Token", engine.ShouldNotEmpty, nil, "Token is required")
+	validator.AddRules("Password", PasswordRules)
+
+	result := validator.Validate(data)
+
+	if result.IsValid() {
+		return true, nil
+	}
+
+	for _, err := range result.Errors {
+		errorMsg := fmt.Sprintf("Field: %s, Rule: %s, Message: %s", err.Field, err.Rule, err.Message)
+		errors = append(errors, errorMsg)
+	}
+
+	return false, &errors
+}
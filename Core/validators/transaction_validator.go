@@ -0,0 +1,48 @@
+package validators
+
+import (
+	dtos "Financial/Core/Models/dtos/Request"
+	"Financial/Core/types"
+	engine "Financial/Core/validators/Engine"
+	"fmt"
+)
+
+// ValidateTransaction validates the CreateTransactionRequest and returns validation results.
+// Returns true with nil errors if valid, or false with a slice of error messages.
+func ValidateTransaction(data dtos.CreateTransactionRequest) (bool, *[]string) {
+	var errors []string
+
+	isKnownType := func(value interface{}) (bool, string) {
+		transactionType, ok := value.(types.TransactionType)
+		if !ok {
+			return false, "Value is not of type TransactionType"
+		}
+		switch transactionType {
+		case types.Income, types.Expense:
+			return true, ""
+		default:
+			return false, "Invalid TransactionType value"
+		}
+	}
+
+	validator := engine.NewValidator()
+	validator.AddRule("WalletID", engine.ShouldGreatThah, 0, "Wallet is required")
+	validator.AddRules("Type", []engine.PatialValidationRule{
+		{Rule: engine.ShouldNotEmpty, Expected: nil, Message: "Type Is Empty"},
+		{Rule: engine.Must, Expected: engine.CustomValidatorFunc(isKnownType), Message: "Type is not valid"},
+	})
+	validator.AddRule("Amount", engine.ShouldGreatThah, 0, "Amount must be greater than zero")
+
+	result := validator.Validate(data)
+
+	if result.IsValid() {
+		return true, nil
+	}
+
+	for _, err := range result.Errors {
+		errorMsg := fmt.Sprintf("Field: %s, Rule: %s, Message: %s", err.Field, err.Rule, err.Message)
+		errors = append(errors, errorMsg)
+	}
+
+	return false, &errors
+}
@@ -0,0 +1,34 @@
+package validators
+
+import (
+	dtos "Financial/Core/Models/dtos/Request"
+	engine "Financial/Core/validators/Engine"
+	"fmt"
+)
+
+// ValidateTransfer validates the CreateTransferRequest and returns validation results.
+// Returns true with nil errors if valid, or false with a slice of error messages.
+func ValidateTransfer(data dtos.CreateTransferRequest) (bool, *[]string) {
+	var errors []string
+
+	validator := engine.NewValidator()
+	validator.AddRule("FromWalletID", engine.ShouldGreatThah, 0, "Source wallet is required")
+	validator.AddRules("ToWalletID", []engine.PatialValidationRule{
+		{Rule: engine.ShouldGreatThah, Expected: 0, Message: "Destination wallet is required"},
+		{Rule: engine.ShouldNotEqual, Expected: data.FromWalletID, Message: "Source and destination wallets must be different"},
+	})
+	validator.AddRule("Amount", engine.ShouldGreatThah, 0, "Amount must be greater than zero")
+
+	result := validator.Validate(data)
+
+	if result.IsValid() {
+		return true, nil
+	}
+
+	for _, err := range result.Errors {
+		errorMsg := fmt.Sprintf("Field: %s, Rule: %s, Message: %s", err.Field, err.Rule, err.Message)
+		errors = append(errors, errorMsg)
+	}
+
+	return false, &errors
+}
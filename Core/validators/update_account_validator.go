@@ -1,20 +1,35 @@
 package validators
 
 import (
+	"errors"
+
 	"Financial/Core/Models/db"
 	"Financial/Core/ports"
 	"Financial/Core/types"
 	engine "Financial/Core/validators/Engine"
 )
 
+// PasswordRules are the rules a new password must follow, when updating the account or resetting it
+var PasswordRules = []engine.PatialValidationRule{
+	{Rule: engine.ShouldNotEmpty, Expected: nil, Message: "Password Is Empty"},
+	{Rule: engine.ShouldMatch, Expected: `^\S*$`, Message: "Password contains space"},
+	{Rule: engine.ShouldMinLength, Expected: 8, Message: "Password not have length"},
+}
+
+// UpdateAccountValidator validates the fields of an account update; fields left empty are kept
+// as they are and not validated
 func UpdateAccountValidator(data db.UpdateAccountRequest, repo ports.Repository[db.User, int]) *engine.ValidationResult {
 
+	// The account's own email is not a duplicate
 	detectDuplicatedMail := func(value interface{}) (bool, string) {
 		result, error := repo.FindByField("email", value)
+		if errors.Is(error, types.ErrNotFound) {
+			return true, ""
+		}
 		if error != nil {
 			return false, "Error fectching data"
 		}
-		if result != nil {
+		if result.ID != data.ID {
 			return false, "Duplicated Mail"
 		}
 		return true, ""
@@ -43,20 +58,21 @@ func UpdateAccountValidator(data db.UpdateAccountRequest, repo ports.Repository[
 		{Rule: engine.ShouldMatch, Expected: `^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`, Message: "Email not match"},
 		{Rule: engine.Must, Expected: engine.CustomValidatorFunc(detectDuplicatedMail), Message: "Email already exists"},
 	}
-	passwordRule := []engine.PatialValidationRule{
-		{Rule: engine.ShouldNotEmpty, Expected: nil, Message: "Password Is Empty"},
-		{Rule: engine.ShouldMatch, Expected: `^\S*$`, Message: "Password contains space"},
-		{Rule: engine.ShouldMinLength, Expected: 8, Message: "Password not have length"},
-	}
 	statuRules := []engine.PatialValidationRule{
 		{Rule: engine.ShouldNotEmpty, Expected: nil, Message: "Value is not define"},
 		{Rule: engine.Must, Expected: engine.CustomValidatorFunc(detectIsStatusInEnum), Message: "Value is not valid"},
 	}
 
-	validator.AddRules("Email", emailrules)
-	validator.AddRules("Password", passwordRule)
-	validator.AddRules("Status", statuRules)
+	if data.Email != "" {
+		validator.AddRules("Email", emailrules)
+	}
+	if data.Password != "" {
+		validator.AddRules("Password", PasswordRules)
+	}
+	if data.Status != "" {
+		validator.AddRules("Status", statuRules)
+	}
 
-	errors := validator.Validate(data)
-	return &errors
+	result := validator.Validate(data)
+	return &result
 }
@@ -21,6 +21,8 @@ import (
 	contracts "Financial/Core/ports"
 	types "Financial/Core/types"
 	"Financial/intefaces/middleware"
+	"log"
+	"net/http"
 
 	"github.com/gin-gonic/gin"
 )
@@ -35,30 +37,42 @@ import (
 type AccountController struct {
 	*BaseController
 	userUseCase    contracts.UserUseCase
+	verification   contracts.VerificationUseCase
 	authMiddleware *middleware.AuthMiddleware
 }
 
-func NewAccountController(userUseCase contracts.UserUseCase, authMiddlerware *middleware.AuthMiddleware) *AccountController {
+func NewAccountController(userUseCase contracts.UserUseCase, verification contracts.VerificationUseCase, authMiddlerware *middleware.AuthMiddleware) *AccountController {
 	return &AccountController{
 		BaseController: NewBaseController("/account"),
 		userUseCase:    userUseCase,
+		verification:   verification,
 		authMiddleware: authMiddlerware,
 	}
 }
 
 func (ac *AccountController) RegisterRoutes(router *gin.RouterGroup) {
 	ac.authMiddleware.Config.AddPublicRoute("POST", "/api/account")
+	ac.authMiddleware.Config.AddPublicRoute("GET", "/api/account/verify")
+	ac.authMiddleware.Config.AddPublicRoute("POST", "/api/account/verify/resend")
 
 	public := router.Group("/account")
 	{
 		public.POST("", ac.CreateUserAccount)
+		public.GET("/verify", ac.VerifyEmail)
+		public.POST("/verify/resend", ac.ResendVerification)
 	}
 
+	// Closing the account is left out on purpose: API keys can't reach it
+	ac.authMiddleware.Config.AddScopedRoute("GET", "/api/account", types.ScopeAccountRead)
+	ac.authMiddleware.Config.AddScopedRoute("PUT", "/api/account", types.ScopeAccountWrite)
+
 	protected := router.Group("/account")
 	protected.Use(ac.authMiddleware.AuthMiddleware())
 	{
+		protected.GET("", ac.GetUserAccount)
 		protected.PUT("", ac.UpdateUserAccount)
-		protected.DELETE("", ac.DeleteUserAccount)
+		// Closing the account needs a recent two-factor verification when it is enabled
+		protected.DELETE("", ac.authMiddleware.RequireRecent2FA(), ac.DeleteUserAccount)
 	}
 }
 
@@ -86,22 +100,101 @@ func (ac *AccountController) CreateUserAccount(c *gin.Context) {
 	account, err := ac.userUseCase.CreateAccount(request.Nick, request.Email, request.Password)
 	if err != nil {
 		c.JSON(500, err)
+		return
+	}
+
+	// La cuenta ya existe aunque el correo falle; el usuario puede pedir otro enlace
+	if errMail := ac.verification.SendVerification(account.Data.ID); errMail != nil {
+		log.Printf("verification email for user %d not sent: %s", account.Data.ID, errMail.Error)
 	}
 	c.JSON(200, account)
 }
 
+// VerifyEmail activa la cuenta del enlace de verificación
+// @Summary Verificar email
+// @Description Activa la cuenta con el token enviado por correo. Cada token sirve una sola vez
+// @Tags Account
+// @Produce json
+// @Param token query string true "Token de verificación"
+// @Success 200 {object} map[string]string "Cuenta verificada"
+// @Failure 400 {object} dtos.ErrorResponse "Token inválido, expirado o ya usado"
+// @Router /account/verify [get]
+func (ac *AccountController) VerifyEmail(c *gin.Context) {
+	if err := ac.verification.VerifyEmail(c.Query("token")); err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Account verified"})
+}
+
+// ResendVerification envía un nuevo enlace de verificación
+// @Summary Reenviar verificación
+// @Description Envía un nuevo enlace de verificación si el email pertenece a una cuenta sin verificar. La respuesta es la misma exista o no la cuenta
+// @Tags Account
+// @Accept json
+// @Produce json
+// @Param request body dtos.ResendVerificationRequest true "Email de la cuenta"
+// @Success 202 {object} map[string]string "Solicitud aceptada"
+// @Failure 400 {object} dtos.ErrorResponse "Error en la solicitud"
+// @Router /account/verify/resend [post]
+func (ac *AccountController) ResendVerification(c *gin.Context) {
+	var request request.ResendVerificationRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{Error: "Solicitud inválida"})
+		return
+	}
+
+	ac.verification.ResendVerification(request.Email)
+	c.JSON(http.StatusAccepted, gin.H{"message": "If the account exists and is not verified, a new link was sent"})
+}
+
+// GetUserAccount devuelve la cuenta del usuario autenticado
+// @Summary Obtener la cuenta
+// @Description Devuelve la cuenta del usuario autenticado, sin la contraseña
+// @Tags Account
+// @Produce json
+// @Success 200 {object} response.AccountResponse "Cuenta del usuario"
+// @Failure 401 {object} dtos.ErrorResponse "No autenticado"
+// @Failure 404 {object} dtos.ErrorResponse "Cuenta no encontrada"
+// @Router /account [get]
+func (ac *AccountController) GetUserAccount(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	account, err := ac.userUseCase.GetAccount(userID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error == "account not found" {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, err)
+		return
+	}
+	c.JSON(http.StatusOK, account)
+}
+
 // UpdateUserAccount actualiza la información de un usuario existente
 // @Summary Actualizar usuario
-// @Description Actualiza la información de un usuario existente
+// @Description Actualiza la información del usuario autenticado. El estado de la cuenta solo lo cambia un administrador.
+// @Description Cambiar la contraseña o el email pide current_password y no se permite con una API key
 // @Tags Account
 // @Accept json
 // @Produce json
 // @Param request body dtos.UpdateAccountRequest true "Datos actualizados del usuario"
 // @Success 200 {object} dtos.UpdateAccountResponse "Usuario actualizado exitosamente"
 // @Failure 400 {object} dtos.ErrorResponse "Error en la solicitud"
+// @Failure 403 {object} dtos.ErrorResponse "Contraseña actual ausente o incorrecta, o cambio con una API key"
 // @Failure 500 {object} dtos.ErrorResponse "Error interno del servidor"
 // @Router /account [put]
 func (ac *AccountController) UpdateUserAccount(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
 	var request request.UpdateAccountRequest
 
 	if err := c.ShouldBindJSON(&request); err != nil {
@@ -112,17 +205,28 @@ func (ac *AccountController) UpdateUserAccount(c *gin.Context) {
 		return
 	}
 
+	// A leaked key must not be enough to take the account over
+	if _, viaAPIKey := c.Get("apiKeyID"); viaAPIKey && (request.Password != "" || request.Email != "") {
+		c.JSON(http.StatusForbidden, response.ErrorResponse{Error: "The password and email can't be changed with an API key"})
+		return
+	}
+
+	// The account is always the one of the token, whatever ID the body carries
 	account, err := ac.userUseCase.UpdateAccount(db.UpdateAccountRequest{
-		ID:        request.ID,
-		FirstName: request.FirstName,
-		Lastname:  request.LastName,
-		Email:     request.Email,
-		Status:    types.AccountStatus(request.Status),
-		Password:  request.Password,
+		ID:              userID,
+		FirstName:       request.FirstName,
+		Lastname:        request.LastName,
+		Email:           request.Email,
+		Status:          types.AccountStatus(request.Status),
+		Password:        request.Password,
+		CurrentPassword: request.CurrentPassword,
 	})
 
 	if err != nil {
-
+		if len(*err) > 0 && (*err)[0].Error == types.ErrCurrentPasswordRequired.Error() {
+			c.JSON(http.StatusForbidden, err)
+			return
+		}
 		c.JSON(500, err)
 		return
 	}
@@ -131,7 +235,7 @@ func (ac *AccountController) UpdateUserAccount(c *gin.Context) {
 
 // DeleteUserAccount elimina una cuenta de usuario
 // @Summary Eliminar usuario
-// @Description Elimina un usuario existente por su email
+// @Description Elimina la cuenta del usuario autenticado; el email debe ser el suyo
 // @Tags Account
 // @Accept json
 // @Produce json
@@ -141,6 +245,11 @@ func (ac *AccountController) UpdateUserAccount(c *gin.Context) {
 // @Failure 500 {object} dtos.ErrorResponse "Error interno del servidor"
 // @Router /account [delete]
 func (ac *AccountController) DeleteUserAccount(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
 	var request request.DeleteAccountRequest
 
 	if err := c.ShouldBindJSON(&request); err != nil {
@@ -151,7 +260,7 @@ func (ac *AccountController) DeleteUserAccount(c *gin.Context) {
 		return
 	}
 
-	err := ac.userUseCase.DestroyAccount(request.Email)
+	err := ac.userUseCase.DestroyAccount(userID, request.Email)
 	if err != nil {
 		c.JSON(500, err)
 		return
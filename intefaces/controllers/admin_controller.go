@@ -0,0 +1,180 @@
+package controllers
+
+import (
+	request "Financial/Core/Models/dtos/Request"
+	response "Financial/Core/Models/dtos/Response"
+	contracts "Financial/Core/ports"
+	"Financial/Core/types"
+	"Financial/intefaces/middleware"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminController handles the operations of support staff and administrators on any account
+// @Summary Administration
+// @Description Provides endpoints for listing accounts, changing their status or role and inspecting their wallets
+type AdminController struct {
+	*BaseController
+	admin          contracts.AdminUseCase
+	authMiddleware *middleware.AuthMiddleware
+}
+
+func NewAdminController(adminUseCase contracts.AdminUseCase, auth *middleware.AuthMiddleware) *AdminController {
+	return &AdminController{
+		BaseController: NewBaseController("/admin"),
+		admin:          adminUseCase,
+		authMiddleware: auth,
+	}
+}
+
+func (ac *AdminController) RegisterRoutes(router *gin.RouterGroup) {
+	// Each route checks the permission it needs against the role of the caller
+	protected := router.Group("/admin")
+	protected.Use(ac.authMiddleware.AuthMiddleware())
+	{
+		protected.GET("/users", ac.authMiddleware.RequirePermission(types.PermUsersRead), ac.listUsers)
+		protected.PUT("/users/:id/status", ac.authMiddleware.RequirePermission(types.PermUsersManage), ac.setAccountStatus)
+		protected.PUT("/users/:id/role", ac.authMiddleware.RequirePermission(types.PermRolesManage), ac.setRole)
+		protected.GET("/users/:id/wallets", ac.authMiddleware.RequirePermission(types.PermWalletsRead), ac.getUserWallets)
+	}
+}
+
+// userIDParam reads the account ID from the route, answering 400 when it is not a number.
+func userIDParam(c *gin.Context) (int, bool) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{Error: "Invalid user ID"})
+		return 0, false
+	}
+	return userID, true
+}
+
+// listUsers godoc
+// @Summary List accounts
+// @Description Lists every account with its status and role. Needs the users:read permission (support, admin)
+// @Tags admin
+// @Produce  json
+// @Security Bearer
+// @Success 200 {array} response.AdminUserResponse
+// @Failure 401 {object} dtos.ErrorResponse
+// @Failure 403 {object} dtos.ErrorResponse
+// @Failure 500 {object} dtos.ErrorResponse
+// @Router /admin/users [get]
+func (ac *AdminController) listUsers(c *gin.Context) {
+	users, err := ac.admin.ListUsers()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, users)
+}
+
+// setAccountStatus godoc
+// @Summary Change the status of an account
+// @Description Sets the status of an account (active, inactive, pending, suspended). Suspending it logs out every session. Needs the users:manage permission (admin)
+// @Tags admin
+// @Accept  json
+// @Produce  json
+// @Security Bearer
+// @Param id path int true "User ID"
+// @Param request body dtos.UpdateAccountStatusRequest true "New status"
+// @Success 200 {object} response.AdminUserResponse
+// @Failure 400 {object} dtos.ErrorResponse
+// @Failure 401 {object} dtos.ErrorResponse
+// @Failure 403 {object} dtos.ErrorResponse
+// @Router /admin/users/{id}/status [put]
+func (ac *AdminController) setAccountStatus(c *gin.Context) {
+	actorID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	userID, ok := userIDParam(c)
+	if !ok {
+		return
+	}
+
+	var request request.UpdateAccountStatusRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{Error: "Invalid request format: " + err.Error()})
+		return
+	}
+
+	user, err := ac.admin.SetAccountStatus(actorID, userID, types.AccountStatus(request.Status))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+	c.JSON(http.StatusOK, user)
+}
+
+// setRole godoc
+// @Summary Change the role of an account
+// @Description Sets the role of an account (user, support, admin). Needs the roles:manage permission (admin)
+// @Tags admin
+// @Accept  json
+// @Produce  json
+// @Security Bearer
+// @Param id path int true "User ID"
+// @Param request body dtos.UpdateRoleRequest true "New role"
+// @Success 200 {object} response.AdminUserResponse
+// @Failure 400 {object} dtos.ErrorResponse
+// @Failure 401 {object} dtos.ErrorResponse
+// @Failure 403 {object} dtos.ErrorResponse
+// @Router /admin/users/{id}/role [put]
+func (ac *AdminController) setRole(c *gin.Context) {
+	actorID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	userID, ok := userIDParam(c)
+	if !ok {
+		return
+	}
+
+	var request request.UpdateRoleRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{Error: "Invalid request format: " + err.Error()})
+		return
+	}
+
+	user, err := ac.admin.SetRole(actorID, userID, types.Role(request.Role))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+	c.JSON(http.StatusOK, user)
+}
+
+// getUserWallets godoc
+// @Summary Inspect the wallets of an account
+// @Description Returns the wallets of any account with their transactions and totals. Needs the wallets:read permission (support, admin)
+// @Tags admin
+// @Produce  json
+// @Security Bearer
+// @Param id path int true "User ID"
+// @Param currency query string false "Reporting currency (ISO 4217) to convert the balances to"
+// @Param asOf query string false "Date of the exchange rates, YYYY-MM-DD or RFC3339 (default now)"
+// @Success 200 {object} response.UserWalletResponse
+// @Failure 400 {object} dtos.ErrorResponse
+// @Failure 401 {object} dtos.ErrorResponse
+// @Failure 403 {object} dtos.ErrorResponse
+// @Router /admin/users/{id}/wallets [get]
+func (ac *AdminController) getUserWallets(c *gin.Context) {
+	userID, ok := userIDParam(c)
+	if !ok {
+		return
+	}
+	asOf, ok := asOfParam(c)
+	if !ok {
+		return
+	}
+
+	wallets, err := ac.admin.GetUserWallets(userID, c.Query("currency"), asOf)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+	c.JSON(http.StatusOK, wallets)
+}
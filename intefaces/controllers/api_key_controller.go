@@ -0,0 +1,130 @@
+package controllers
+
+import (
+	request "Financial/Core/Models/dtos/Request"
+	response "Financial/Core/Models/dtos/Response"
+	contracts "Financial/Core/ports"
+	"Financial/intefaces/middleware"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyController handles the personal API keys of the authenticated user
+// @Summary API keys
+// @Description Provides endpoints for creating, listing and revoking personal API keys
+type APIKeyController struct {
+	*BaseController
+	apiKeys        contracts.APIKeyUseCase
+	authMiddleware *middleware.AuthMiddleware
+}
+
+func NewAPIKeyController(apiKeyUseCase contracts.APIKeyUseCase, auth *middleware.AuthMiddleware) *APIKeyController {
+	return &APIKeyController{
+		BaseController: NewBaseController("/api-keys"),
+		apiKeys:        apiKeyUseCase,
+		authMiddleware: auth,
+	}
+}
+
+func (kc *APIKeyController) RegisterRoutes(router *gin.RouterGroup) {
+	// These routes have no scope, so an API key can never create or revoke keys
+	protected := router.Group("/api-keys")
+	protected.Use(kc.authMiddleware.AuthMiddleware())
+	{
+		protected.GET("", kc.listKeys)
+		protected.POST("", kc.createKey)
+		protected.DELETE(":id", kc.revokeKey)
+	}
+}
+
+// listKeys godoc
+// @Summary List API keys
+// @Description Lists the API keys of the authenticated user, newest first. Keys are only shown in full when created
+// @Tags api-keys
+// @Produce  json
+// @Security Bearer
+// @Success 200 {array} response.APIKeyResponse
+// @Failure 401 {object} dtos.ErrorResponse
+// @Failure 500 {object} dtos.ErrorResponse
+// @Router /api-keys [get]
+func (kc *APIKeyController) listKeys(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	keys, err := kc.apiKeys.ListKeys(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, keys)
+}
+
+// createKey godoc
+// @Summary Create an API key
+// @Description Creates an API key with the given scopes (wallets:read, wallets:write, account:read, account:write) and optional expiry. The key is returned only once; send it in the X-API-Key header
+// @Tags api-keys
+// @Accept  json
+// @Produce  json
+// @Security Bearer
+// @Param request body dtos.CreateAPIKeyRequest true "Name, scopes and expiry of the key"
+// @Success 201 {object} response.CreatedAPIKeyResponse
+// @Failure 400 {object} dtos.ErrorResponse
+// @Failure 401 {object} dtos.ErrorResponse
+// @Router /api-keys [post]
+func (kc *APIKeyController) createKey(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var request request.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{Error: "Invalid request format: " + err.Error()})
+		return
+	}
+
+	key, err := kc.apiKeys.CreateKey(userID, request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+	c.JSON(http.StatusCreated, key)
+}
+
+// revokeKey godoc
+// @Summary Revoke an API key
+// @Description Revokes an API key of the authenticated user; it stops working at once
+// @Tags api-keys
+// @Produce  json
+// @Security Bearer
+// @Param id path int true "API key ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} dtos.ErrorResponse
+// @Failure 401 {object} dtos.ErrorResponse
+// @Failure 404 {object} dtos.ErrorResponse
+// @Router /api-keys/{id} [delete]
+func (kc *APIKeyController) revokeKey(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	keyID, errID := strconv.Atoi(c.Param("id"))
+	if errID != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{Error: "Invalid API key ID"})
+		return
+	}
+
+	if err := kc.apiKeys.RevokeKey(userID, keyID); err != nil {
+		status := http.StatusInternalServerError
+		if err.Error == "API key not found" {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "API key revoked"})
+}
@@ -6,7 +6,12 @@ import (
 	request "Financial/Core/Models/dtos/Request"
 	response "Financial/Core/Models/dtos/Response"
 	contract "Financial/Core/ports"
+	"Financial/Core/types"
 	"Financial/intefaces/middleware"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -16,6 +21,11 @@ import (
 type AuthController struct {
 	*BaseController
 	userUseCase    contract.UserUseCase
+	sessionUseCase contract.SessionUseCase
+	passwordReset  contract.PasswordResetUseCase
+	twoFactor      contract.TwoFactorUseCase
+	externalLogin  contract.ExternalLoginUseCase
+	loginThrottle  contract.LoginThrottle
 	authMiddleware *middleware.AuthMiddleware
 }
 
@@ -27,10 +37,15 @@ type AuthController struct {
 // @license.name Apache 2.0
 // @host localhost:8080
 // @BasePath /api
-func NewAuthController(userUseCase contract.UserUseCase, authMiddlerware *middleware.AuthMiddleware) *AuthController {
+func NewAuthController(userUseCase contract.UserUseCase, sessionUseCase contract.SessionUseCase, passwordReset contract.PasswordResetUseCase, twoFactor contract.TwoFactorUseCase, externalLogin contract.ExternalLoginUseCase, loginThrottle contract.LoginThrottle, authMiddlerware *middleware.AuthMiddleware) *AuthController {
 	return &AuthController{
 		BaseController: NewBaseController("/auth"),
 		userUseCase:    userUseCase,
+		sessionUseCase: sessionUseCase,
+		passwordReset:  passwordReset,
+		twoFactor:      twoFactor,
+		externalLogin:  externalLogin,
+		loginThrottle:  loginThrottle,
 		authMiddleware: authMiddlerware,
 	}
 }
@@ -38,23 +53,53 @@ func NewAuthController(userUseCase contract.UserUseCase, authMiddlerware *middle
 // RegisterRoutes sets up the routes for authentication endpoints
 func (ac *AuthController) RegisterRoutes(router *gin.RouterGroup) {
 	ac.authMiddleware.Config.AddPublicRoute("POST", "/api/auth")
+	ac.authMiddleware.Config.AddPublicRoute("POST", "/api/auth/refresh")
+	ac.authMiddleware.Config.AddPublicRoute("POST", "/api/auth/forgot-password")
+	ac.authMiddleware.Config.AddPublicRoute("POST", "/api/auth/reset-password")
+	ac.authMiddleware.Config.AddPublicRoute("POST", "/api/auth/2fa/verify")
 
-	public := router.Group("/auth")
+	// Login, refresh, password recovery and the second login step are public;
+	// the session and two-factor management routes need a valid access token
+	auth := router.Group("/auth")
 	{
-		public.POST("", ac.Login)
+		auth.POST("", ac.Login)
+		auth.POST("/refresh", ac.Refresh)
+		auth.POST("/forgot-password", ac.ForgotPassword)
+		auth.POST("/reset-password", ac.ResetPassword)
+		auth.POST("/logout", ac.Logout)
+		auth.POST("/logout-all", ac.LogoutAll)
+		auth.GET("/sessions", ac.GetSessions)
+		auth.DELETE("/sessions/:id", ac.RevokeSession)
+		auth.POST("/2fa/verify", ac.VerifyTwoFactor)
+		auth.POST("/2fa/setup", ac.SetupTwoFactor)
+		auth.POST("/2fa/confirm", ac.ConfirmTwoFactor)
+		auth.POST("/2fa/disable", ac.DisableTwoFactor)
+		auth.POST("/2fa/step-up", ac.StepUpTwoFactor)
+	}
+
+	// The OpenID Connect login only exists when a provider is configured
+	if ac.externalLogin != nil {
+		ac.authMiddleware.Config.AddPublicRoute("GET", "/api/auth/oidc/login")
+		ac.authMiddleware.Config.AddPublicRoute("GET", "/api/auth/oidc/callback")
+		auth.GET("/oidc/login", ac.StartExternalLogin)
+		auth.GET("/oidc/callback", ac.CompleteExternalLogin)
 	}
 }
 
 // Login authenticates a user and returns a token
 // @Summary Authenticate user
-// @Description Authenticates a user with email/username and password
+// @Description Authenticates a user with email or nickname and password, returning an access token whose subject is the numeric user ID.
+// @Description Accounts with two-factor authentication get a challenge token instead, to send with a code to /auth/2fa/verify
 // @Tags auth
 // @Accept  json
 // @Produce  json
 // @Param   auth  body      dtos.AuthRequest  true  "Login credentials"
-// @Success 200 {string} string "Authentication successful"
+// @Success 200 {object} response.TokenResponse "Authentication successful"
+// @Success 202 {object} response.TwoFactorChallengeResponse "Password accepted, two-factor code required"
 // @Failure 400 {object} response.ErrorResponse "Invalid request format"
 // @Failure 401 {object} response.ErrorResponse "Invalid credentials"
+// @Failure 403 {object} response.ErrorResponse "Email not verified or account suspended"
+// @Failure 429 {object} response.ErrorResponse "Too many failed attempts; retry after the Retry-After header"
 // @Failure 500 {object} response.ErrorResponse "Internal server error"
 // @Router /auth [post]
 func (ac *AuthController) Login(c *gin.Context) {
@@ -77,23 +122,559 @@ func (ac *AuthController) Login(c *gin.Context) {
 		return
 	}
 
+	// Accounts and addresses with recent failures have to wait before trying again
+	ip := c.ClientIP()
+	if wait, err := ac.loginThrottle.Allow(request, ip); err != nil {
+		throttled(c, wait, err)
+		return
+	}
+
 	// Authenticate user
-	email, err := ac.userUseCase.Login(request)
+	userID, err := ac.userUseCase.Login(request)
 	if err != nil {
 		errorRes := response.ErrorResponse{
 			Error: "Authentication failed: " + err.Error(),
 		}
-		c.JSON(401, errorRes)
+		if errors.Is(err, types.ErrInvalidCredentials) {
+			if errThrottle := ac.loginThrottle.LoginFailed(request, ip); errThrottle != nil {
+				c.JSON(500, response.ErrorResponse{Error: "Authentication failed: " + errThrottle.Error()})
+				return
+			}
+			c.JSON(401, errorRes)
+			return
+		}
+		if errors.Is(err, types.ErrAccountNotVerified) || errors.Is(err, types.ErrAccountSuspended) {
+			c.JSON(403, errorRes)
+			return
+		}
+		c.JSON(500, errorRes)
 		return
 	}
 
-	token, err := ac.authMiddleware.GenerateToken(*email)
+	ac.completeLogin(c, *userID)
+}
+
+// StartExternalLogin sends the browser to the OpenID Connect provider
+// @Summary Log in with the identity provider
+// @Description Redirects to the login page of the configured OpenID Connect provider (authorization code flow with PKCE). The provider sends the user back to /auth/oidc/callback
+// @Tags auth
+// @Success 302 "Redirect to the provider"
+// @Failure 500 {object} response.ErrorResponse "The login can't be started"
+// @Router /auth/oidc/login [get]
+func (ac *AuthController) StartExternalLogin(c *gin.Context) {
+	authURL, err := ac.externalLogin.StartLogin()
 	if err != nil {
-		var errorRes = response.ErrorResponse{
-			Error: err.Error(),
+		c.JSON(http.StatusInternalServerError, response.ErrorResponse{Error: "Authentication failed: " + err.Error()})
+		return
+	}
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// CompleteExternalLogin finishes a login through the OpenID Connect provider
+// @Summary Identity provider callback
+// @Description Exchanges the authorization code for the user's identity and logs in to the account linked to it, linking by verified email or creating the account when allowed.
+// @Description Accounts with two-factor authentication get a challenge token instead, to send with a code to /auth/2fa/verify
+// @Tags auth
+// @Produce  json
+// @Param state query string true "State of the login"
+// @Param code query string true "Authorization code"
+// @Success 200 {object} response.TokenResponse "Authentication successful"
+// @Success 202 {object} response.TwoFactorChallengeResponse "Identity accepted, two-factor code required"
+// @Failure 401 {object} response.ErrorResponse "The provider denied the login, or the state or code is not valid"
+// @Failure 403 {object} response.ErrorResponse "No account linked to the identity, or account suspended"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /auth/oidc/callback [get]
+func (ac *AuthController) CompleteExternalLogin(c *gin.Context) {
+	// The provider reports a denied or failed login through the error parameter
+	if providerError := c.Query("error"); providerError != "" {
+		c.JSON(http.StatusUnauthorized, response.ErrorResponse{Error: "Authentication failed: " + providerError})
+		return
+	}
+
+	userID, err := ac.externalLogin.CompleteLogin(c.Query("state"), c.Query("code"))
+	if err != nil {
+		errorRes := response.ErrorResponse{
+			Error: "Authentication failed: " + err.Error(),
+		}
+		switch {
+		case errors.Is(err, types.ErrExternalLoginFailed):
+			c.JSON(http.StatusUnauthorized, errorRes)
+		case errors.Is(err, types.ErrAccountNotLinked), errors.Is(err, types.ErrAccountSuspended):
+			c.JSON(http.StatusForbidden, errorRes)
+		default:
+			c.JSON(http.StatusInternalServerError, errorRes)
 		}
-		c.JSON(500, errorRes)
 		return
 	}
-	c.JSON(200, token)
+
+	ac.completeLogin(c, *userID)
+}
+
+// throttled answers a login or a two-factor code refused by the login throttle
+func throttled(c *gin.Context, wait time.Duration, err error) {
+	errorRes := response.ErrorResponse{
+		Error: "Authentication failed: " + err.Error(),
+	}
+	if errors.Is(err, types.ErrTooManyAttempts) {
+		c.Header("Retry-After", strconv.Itoa(int((wait+time.Second-1)/time.Second)))
+		c.JSON(http.StatusTooManyRequests, errorRes)
+		return
+	}
+	c.JSON(500, errorRes)
+}
+
+// completeLogin opens the session of an authenticated user, or answers with a
+// two-factor challenge when the account has it enabled. The failed logins of the account
+// are forgotten only once the session is opened, after the two-factor code if there is one.
+func (ac *AuthController) completeLogin(c *gin.Context, userID int) {
+	// With two-factor enabled the first factor alone doesn't open a session
+	enabled, err := ac.twoFactor.Enabled(userID)
+	if err != nil {
+		c.JSON(500, response.ErrorResponse{Error: "Authentication failed: " + err.Error()})
+		return
+	}
+	if enabled {
+		challenge, err := ac.authMiddleware.GenerateChallengeToken(strconv.Itoa(userID))
+		if err != nil {
+			c.JSON(500, response.ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusAccepted, response.TwoFactorChallengeResponse{
+			TwoFactorRequired: true,
+			ChallengeToken:    challenge,
+			ExpiresIn:         int(ac.authMiddleware.ChallengeTokenTTL().Seconds()),
+		})
+		return
+	}
+
+	grant, errRes := ac.sessionUseCase.StartSession(userID, c.Request.UserAgent(), c.ClientIP())
+	if errRes != nil {
+		c.JSON(500, errRes)
+		return
+	}
+	if errThrottle := ac.loginThrottle.LoginSucceeded(userID); errThrottle != nil {
+		c.JSON(500, response.ErrorResponse{Error: "Authentication failed: " + errThrottle.Error()})
+		return
+	}
+
+	ac.issueTokens(c, grant, nil)
+}
+
+// VerifyTwoFactor completes a login that answered with a two-factor challenge
+// @Summary Complete a two-factor login
+// @Description Exchanges the challenge token of a login plus a code from the authenticator app (or a recovery code) for the session tokens
+// @Tags auth
+// @Accept  json
+// @Produce  json
+// @Param   request  body      dtos.TwoFactorLoginRequest  true  "Challenge token and code"
+// @Success 200 {object} response.TokenResponse
+// @Failure 400 {object} response.ErrorResponse "Invalid request format"
+// @Failure 401 {object} response.ErrorResponse "Invalid or expired challenge, or wrong code"
+// @Failure 429 {object} response.ErrorResponse "Too many wrong codes; retry after the Retry-After header"
+// @Router /auth/2fa/verify [post]
+func (ac *AuthController) VerifyTwoFactor(c *gin.Context) {
+	var request request.TwoFactorLoginRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{Error: "Invalid request format: " + err.Error()})
+		return
+	}
+
+	subject, challengeID, err := ac.authMiddleware.ParseChallengeToken(request.ChallengeToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, response.ErrorResponse{Error: "Invalid or expired challenge token"})
+		return
+	}
+	userID, err := strconv.Atoi(subject)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, response.ErrorResponse{Error: "Invalid or expired challenge token"})
+		return
+	}
+
+	if !ac.checkSecondFactor(c, userID, http.StatusUnauthorized, func() *response.ErrorResponse {
+		return ac.twoFactor.Verify(userID, request.Code)
+	}) {
+		return
+	}
+
+	// A challenge completes one login; replaying its token needs a new password
+	first, err := ac.loginThrottle.ClaimChallenge(challengeID, ac.authMiddleware.ChallengeTokenTTL())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.ErrorResponse{Error: "Authentication failed: " + err.Error()})
+		return
+	}
+	if !first {
+		c.JSON(http.StatusUnauthorized, response.ErrorResponse{Error: "Invalid or expired challenge token"})
+		return
+	}
+
+	grant, errRes := ac.sessionUseCase.StartSession(userID, c.Request.UserAgent(), c.ClientIP())
+	if errRes != nil {
+		c.JSON(http.StatusInternalServerError, errRes)
+		return
+	}
+	if errThrottle := ac.loginThrottle.LoginSucceeded(userID); errThrottle != nil {
+		c.JSON(http.StatusInternalServerError, response.ErrorResponse{Error: "Authentication failed: " + errThrottle.Error()})
+		return
+	}
+
+	verifiedAt := time.Now()
+	ac.issueTokens(c, grant, &verifiedAt)
+}
+
+// checkSecondFactor runs check, a use case call that checks a two-factor code of the user,
+// through the login throttle: wrong codes count like wrong passwords, with the same backoff
+// and lockout. When the code is refused it answers with status (429 while the account has
+// to wait) and returns false.
+func (ac *AuthController) checkSecondFactor(c *gin.Context, userID int, status int, check func() *response.ErrorResponse) bool {
+	ip := c.ClientIP()
+	if wait, err := ac.loginThrottle.AllowSecondFactor(userID, ip); err != nil {
+		throttled(c, wait, err)
+		return false
+	}
+
+	errRes := check()
+	if errRes == nil {
+		return true
+	}
+	if errRes.Error == types.ErrInvalidTwoFactorCode.Error() {
+		if errThrottle := ac.loginThrottle.SecondFactorFailed(userID, ip); errThrottle != nil {
+			c.JSON(http.StatusInternalServerError, response.ErrorResponse{Error: "Authentication failed: " + errThrottle.Error()})
+			return false
+		}
+	}
+	c.JSON(status, errRes)
+	return false
+}
+
+// SetupTwoFactor starts the two-factor enrollment of the user
+// @Summary Set up two-factor authentication
+// @Description Creates a new TOTP secret for the authenticated user. It is not enforced until confirmed with a first code
+// @Tags auth
+// @Produce  json
+// @Security Bearer
+// @Success 200 {object} response.TwoFactorSetupResponse
+// @Failure 400 {object} response.ErrorResponse "Already enabled"
+// @Failure 401 {object} response.ErrorResponse
+// @Router /auth/2fa/setup [post]
+func (ac *AuthController) SetupTwoFactor(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	setup, err := ac.twoFactor.Setup(userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+	c.JSON(http.StatusOK, setup)
+}
+
+// ConfirmTwoFactor enables two-factor authentication with a first code
+// @Summary Confirm two-factor authentication
+// @Description Enables two-factor authentication with a code from the app and returns the recovery codes. They are shown only once
+// @Tags auth
+// @Accept  json
+// @Produce  json
+// @Security Bearer
+// @Param   request  body      dtos.TwoFactorCodeRequest  true  "Code from the authenticator app"
+// @Success 200 {object} response.RecoveryCodesResponse
+// @Failure 400 {object} response.ErrorResponse "No setup in progress or wrong code"
+// @Failure 401 {object} response.ErrorResponse
+// @Router /auth/2fa/confirm [post]
+func (ac *AuthController) ConfirmTwoFactor(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var request request.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{Error: "Invalid request format: " + err.Error()})
+		return
+	}
+
+	codes, err := ac.twoFactor.Confirm(userID, request.Code)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+	c.JSON(http.StatusOK, codes)
+}
+
+// DisableTwoFactor turns two-factor authentication off
+// @Summary Disable two-factor authentication
+// @Description Turns two-factor authentication off after checking a code from the app or a recovery code
+// @Tags auth
+// @Accept  json
+// @Produce  json
+// @Security Bearer
+// @Param   request  body      dtos.TwoFactorCodeRequest  true  "Code"
+// @Success 204 "No Content"
+// @Failure 400 {object} response.ErrorResponse "Not enabled or wrong code"
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 429 {object} response.ErrorResponse "Too many wrong codes; retry after the Retry-After header"
+// @Router /auth/2fa/disable [post]
+func (ac *AuthController) DisableTwoFactor(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var request request.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{Error: "Invalid request format: " + err.Error()})
+		return
+	}
+
+	if !ac.checkSecondFactor(c, userID, http.StatusBadRequest, func() *response.ErrorResponse {
+		return ac.twoFactor.Disable(userID, request.Code)
+	}) {
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// StepUpTwoFactor re-verifies the user before a sensitive operation
+// @Summary Step up with a two-factor code
+// @Description Checks a code and returns a new access token for the current session, accepted by the routes that need a recent two-factor verification
+// @Tags auth
+// @Accept  json
+// @Produce  json
+// @Security Bearer
+// @Param   request  body      dtos.TwoFactorCodeRequest  true  "Code"
+// @Success 200 {object} response.AccessTokenResponse
+// @Failure 400 {object} response.ErrorResponse "Invalid request format"
+// @Failure 401 {object} response.ErrorResponse "Wrong code"
+// @Failure 429 {object} response.ErrorResponse "Too many wrong codes; retry after the Retry-After header"
+// @Router /auth/2fa/step-up [post]
+func (ac *AuthController) StepUpTwoFactor(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var request request.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{Error: "Invalid request format: " + err.Error()})
+		return
+	}
+
+	if !ac.checkSecondFactor(c, userID, http.StatusUnauthorized, func() *response.ErrorResponse {
+		return ac.twoFactor.Verify(userID, request.Code)
+	}) {
+		return
+	}
+
+	// Same session (jti), so logout and revocation keep working on the new token
+	token, err := ac.authMiddleware.GenerateTokenWith2FA(strconv.Itoa(userID), c.GetString("tokenID"), time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.AccessTokenResponse{
+		AccessToken: token,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(ac.authMiddleware.AccessTokenTTL().Seconds()),
+	})
+}
+
+// Refresh exchanges a refresh token for a new access and refresh token
+// @Summary Refresh tokens
+// @Description Exchanges a refresh token for a new access token and a new refresh token. Each refresh token works once; reusing one revokes its session
+// @Tags auth
+// @Accept  json
+// @Produce  json
+// @Param   refresh  body      dtos.RefreshTokenRequest  true  "Refresh token"
+// @Success 200 {object} response.TokenResponse
+// @Failure 400 {object} response.ErrorResponse "Invalid request format"
+// @Failure 401 {object} response.ErrorResponse "Invalid, expired or revoked refresh token"
+// @Router /auth/refresh [post]
+func (ac *AuthController) Refresh(c *gin.Context) {
+	var request request.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{Error: "Invalid request format: " + err.Error()})
+		return
+	}
+
+	grant, errRes := ac.sessionUseCase.RefreshSession(request.RefreshToken, c.Request.UserAgent(), c.ClientIP())
+	if errRes != nil {
+		c.JSON(http.StatusUnauthorized, errRes)
+		return
+	}
+
+	ac.issueTokens(c, grant, nil)
+}
+
+// Logout closes the session of the token used for the request
+// @Summary Log out
+// @Description Revokes the current session; its access and refresh tokens stop working
+// @Tags auth
+// @Produce  json
+// @Security Bearer
+// @Success 204 "No Content"
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Router /auth/logout [post]
+func (ac *AuthController) Logout(c *gin.Context) {
+	if err := ac.sessionUseCase.Logout(c.GetString("tokenID")); err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// LogoutAll closes every session of the user
+// @Summary Log out all devices
+// @Description Revokes every session of the authenticated user, including the current one
+// @Tags auth
+// @Produce  json
+// @Security Bearer
+// @Success 204 "No Content"
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Router /auth/logout-all [post]
+func (ac *AuthController) LogoutAll(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := ac.sessionUseCase.LogoutAll(userID); err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetSessions lists the sessions of the user
+// @Summary List sessions
+// @Description Lists the logins of the authenticated user with device, IP and last activity, most recent first
+// @Tags auth
+// @Produce  json
+// @Security Bearer
+// @Success 200 {array} response.SessionResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Router /auth/sessions [get]
+func (ac *AuthController) GetSessions(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	sessions, err := ac.sessionUseCase.GetSessions(userID, c.GetString("tokenID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+	c.JSON(http.StatusOK, sessions)
+}
+
+// RevokeSession closes one session of the user
+// @Summary Revoke a session
+// @Description Revokes one session of the authenticated user, e.g. an unknown device
+// @Tags auth
+// @Produce  json
+// @Security Bearer
+// @Param id path int true "Session ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Router /auth/sessions/{id} [delete]
+func (ac *AuthController) RevokeSession(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	sessionID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{Error: "Invalid session ID"})
+		return
+	}
+
+	if err := ac.sessionUseCase.RevokeSession(userID, sessionID); err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ForgotPassword emails a password reset token
+// @Summary Forgot password
+// @Description Emails a single-use password reset token if the address belongs to an account. The answer is the same whether or not it does
+// @Tags auth
+// @Accept  json
+// @Produce  json
+// @Param   request  body      dtos.ForgotPasswordRequest  true  "Account email"
+// @Success 202 {object} map[string]string "Request accepted"
+// @Failure 400 {object} response.ErrorResponse "Invalid request format"
+// @Failure 429 {object} response.ErrorResponse "Too many requests for the email or from the client; retry after the Retry-After header"
+// @Router /auth/forgot-password [post]
+func (ac *AuthController) ForgotPassword(c *gin.Context) {
+	var request request.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{Error: "Invalid request format: " + err.Error()})
+		return
+	}
+
+	// The count doesn't depend on the account existing, so being refused reveals nothing
+	if wait, err := ac.loginThrottle.AllowPasswordReset(request.Email, c.ClientIP()); err != nil {
+		throttled(c, wait, err)
+		return
+	}
+
+	// The email goes out in the background, so the timing of the answer reveals nothing either
+	ac.passwordReset.RequestReset(request.Email)
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "If the account exists, a reset token was sent to its email"})
+}
+
+// ResetPassword sets a new password with an emailed reset token
+// @Summary Reset password
+// @Description Sets a new password with a reset token and logs out every session of the account
+// @Tags auth
+// @Accept  json
+// @Produce  json
+// @Param   request  body      dtos.ResetPasswordRequest  true  "Reset token and new password"
+// @Success 204 "No Content"
+// @Failure 400 {object} response.ErrorResponse "Invalid password, or invalid, expired or used token"
+// @Router /auth/reset-password [post]
+func (ac *AuthController) ResetPassword(c *gin.Context) {
+	var request request.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{Error: "Invalid request format: " + err.Error()})
+		return
+	}
+
+	if err := ac.passwordReset.ResetPassword(request); err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// issueTokens signs the access token of a session grant and answers with both tokens.
+// twoFactorAt, when set, is recorded in the access token for the routes that need a recent verification
+func (ac *AuthController) issueTokens(c *gin.Context, grant *response.SessionGrant, twoFactorAt *time.Time) {
+	var token string
+	var err error
+	if twoFactorAt != nil {
+		token, err = ac.authMiddleware.GenerateTokenWith2FA(grant.Subject, grant.TokenID, *twoFactorAt)
+	} else {
+		token, err = ac.authMiddleware.GenerateToken(grant.Subject, grant.TokenID)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.TokenResponse{
+		AccessToken:      token,
+		TokenType:        "Bearer",
+		ExpiresIn:        int(ac.authMiddleware.AccessTokenTTL().Seconds()),
+		RefreshToken:     grant.RefreshToken,
+		RefreshExpiresAt: grant.RefreshExpiresAt,
+	})
 }
@@ -1,6 +1,11 @@
 package controllers
 
-import "github.com/gin-gonic/gin"
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
 
 type Controller interface {
 	RegisterRoutes(router *gin.RouterGroup)
@@ -15,3 +20,23 @@ func NewBaseController(path string) *BaseController {
 		Path: path,
 	}
 }
+
+// currentUserID reads the authenticated user ID set by the auth middleware,
+// answering 401 when the token subject is not a numeric user ID.
+func currentUserID(c *gin.Context) (int, bool) {
+	value, exists := c.Get("userID")
+	if exists {
+		switch v := value.(type) {
+		case int:
+			return v, true
+		case float64:
+			return int(v), true
+		case string:
+			if id, err := strconv.Atoi(v); err == nil {
+				return id, true
+			}
+		}
+	}
+	c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+	return 0, false
+}
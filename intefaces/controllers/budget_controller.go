@@ -0,0 +1,183 @@
+package controllers
+
+import (
+	request "Financial/Core/Models/dtos/Request"
+	response "Financial/Core/Models/dtos/Response"
+	contracts "Financial/Core/ports"
+	"Financial/intefaces/middleware"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BudgetController handles the monthly budgets of the authenticated user
+// @Summary Budgets
+// @Description Provides endpoints for planning spending per category and tracking it
+type BudgetController struct {
+	*BaseController
+	budget         contracts.BudgetUseCase
+	authMiddleware *middleware.AuthMiddleware
+}
+
+func NewBudgetController(budgetUseCase contracts.BudgetUseCase, auth *middleware.AuthMiddleware) *BudgetController {
+	return &BudgetController{
+		BaseController: NewBaseController("/budgets"),
+		budget:         budgetUseCase,
+		authMiddleware: auth,
+	}
+}
+
+func (bc *BudgetController) RegisterRoutes(router *gin.RouterGroup) {
+	// ":id" carries the period (YYYY-MM) on GET and the budget ID on PUT/DELETE,
+	// gin requires the same wildcard name for the segment.
+	protected := router.Group("/budgets")
+	protected.Use(bc.authMiddleware.AuthMiddleware())
+	{
+		protected.GET(":id", bc.getBudgetProgress)
+		protected.POST("", bc.createBudget)
+		protected.PUT(":id", bc.updateBudget)
+		protected.DELETE(":id", bc.deleteBudget)
+	}
+}
+
+// budgetIDParam reads the budget ID from the route, answering 400 when it is not a number.
+func budgetIDParam(c *gin.Context) (int, bool) {
+	budgetID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{Error: "Invalid budget ID"})
+		return 0, false
+	}
+	return budgetID, true
+}
+
+// getBudgetProgress godoc
+// @Summary Budget progress
+// @Description Get spent and remaining amounts of every budget of a month, flagging the exceeded ones
+// @Tags budgets
+// @Accept  json
+// @Produce  json
+// @Security Bearer
+// @Param period path string true "Month, formatted as YYYY-MM"
+// @Success 200 {object} response.BudgetProgressResponse
+// @Failure 400 {object} dtos.ErrorResponse
+// @Failure 401 {object} dtos.ErrorResponse
+// @Router /budgets/{period} [get]
+func (bc *BudgetController) getBudgetProgress(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	progress, err := bc.budget.GetBudgetProgress(userID, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, progress)
+}
+
+// createBudget godoc
+// @Summary Create a budget
+// @Description Set the spending limit of an expense category for a month
+// @Tags budgets
+// @Accept  json
+// @Produce  json
+// @Security Bearer
+// @Param budget body dtos.CreateBudgetRequest true "Budget data"
+// @Success 201 {object} db.Budget
+// @Failure 400 {object} dtos.ErrorResponse
+// @Failure 401 {object} dtos.ErrorResponse
+// @Router /budgets [post]
+func (bc *BudgetController) createBudget(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var request request.CreateBudgetRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	request.UserID = userID
+
+	budget, err := bc.budget.CreateBudget(request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, budget)
+}
+
+// updateBudget godoc
+// @Summary Update a budget
+// @Description Change the limit of a budget
+// @Tags budgets
+// @Accept  json
+// @Produce  json
+// @Security Bearer
+// @Param id path int true "Budget ID"
+// @Param budget body dtos.UpdateBudgetRequest true "New limit"
+// @Success 200 {object} db.Budget
+// @Failure 400 {object} dtos.ErrorResponse
+// @Failure 401 {object} dtos.ErrorResponse
+// @Router /budgets/{id} [put]
+func (bc *BudgetController) updateBudget(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	budgetID, ok := budgetIDParam(c)
+	if !ok {
+		return
+	}
+
+	var request request.UpdateBudgetRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	request.BudgetID = budgetID
+	request.UserID = userID
+
+	budget, err := bc.budget.UpdateBudget(request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, budget)
+}
+
+// deleteBudget godoc
+// @Summary Delete a budget
+// @Description Remove a budget
+// @Tags budgets
+// @Accept  json
+// @Produce  json
+// @Security Bearer
+// @Param id path int true "Budget ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} dtos.ErrorResponse
+// @Failure 401 {object} dtos.ErrorResponse
+// @Router /budgets/{id} [delete]
+func (bc *BudgetController) deleteBudget(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	budgetID, ok := budgetIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := bc.budget.DeleteBudget(userID, budgetID); err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,193 @@
+package controllers
+
+import (
+	request "Financial/Core/Models/dtos/Request"
+	response "Financial/Core/Models/dtos/Response"
+	contracts "Financial/Core/ports"
+	"Financial/intefaces/middleware"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CategoryController handles the spending categories of the authenticated user
+// @Summary Categories
+// @Description Provides endpoints for managing the category tree used to tag transactions
+type CategoryController struct {
+	*BaseController
+	category       contracts.CategoryUseCase
+	authMiddleware *middleware.AuthMiddleware
+}
+
+func NewCategoryController(categoryUseCase contracts.CategoryUseCase, auth *middleware.AuthMiddleware) *CategoryController {
+	return &CategoryController{
+		BaseController: NewBaseController("/categories"),
+		category:       categoryUseCase,
+		authMiddleware: auth,
+	}
+}
+
+func (cc *CategoryController) RegisterRoutes(router *gin.RouterGroup) {
+	protected := router.Group("/categories")
+	protected.Use(cc.authMiddleware.AuthMiddleware())
+	{
+		protected.GET("", cc.getCategories)
+		protected.POST("", cc.createCategory)
+		protected.PUT(":id", cc.updateCategory)
+		protected.DELETE(":id", cc.deleteCategory)
+	}
+}
+
+// categoryIDParam reads the category ID from the route, answering 400 when it is not a number.
+func categoryIDParam(c *gin.Context) (int, bool) {
+	categoryID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{Error: "Invalid category ID"})
+		return 0, false
+	}
+	return categoryID, true
+}
+
+// getCategories godoc
+// @Summary List categories
+// @Description Get the category tree of the authenticated user
+// @Tags categories
+// @Accept  json
+// @Produce  json
+// @Security Bearer
+// @Success 200 {array} db.Category
+// @Failure 400 {object} dtos.ErrorResponse
+// @Failure 401 {object} dtos.ErrorResponse
+// @Router /categories [get]
+func (cc *CategoryController) getCategories(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	categories, err := cc.category.GetUserCategories(userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, categories)
+}
+
+// createCategory godoc
+// @Summary Create a category
+// @Description Create a category, optionally as a subcategory of an existing one
+// @Tags categories
+// @Accept  json
+// @Produce  json
+// @Security Bearer
+// @Param category body dtos.CreateCategoryRequest true "Category data"
+// @Success 201 {object} db.Category
+// @Failure 400 {object} dtos.ErrorResponse
+// @Failure 401 {object} dtos.ErrorResponse
+// @Router /categories [post]
+func (cc *CategoryController) createCategory(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var request request.CreateCategoryRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	// The owner always comes from the token
+	request.UserID = userID
+
+	category, err := cc.category.CreateCategory(request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, category)
+}
+
+// updateCategory godoc
+// @Summary Update a category
+// @Description Rename a category or move it under another parent
+// @Tags categories
+// @Accept  json
+// @Produce  json
+// @Security Bearer
+// @Param id path int true "Category ID"
+// @Param category body dtos.UpdateCategoryRequest true "Category changes"
+// @Success 200 {object} db.Category
+// @Failure 400 {object} dtos.ErrorResponse
+// @Failure 401 {object} dtos.ErrorResponse
+// @Router /categories/{id} [put]
+func (cc *CategoryController) updateCategory(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	categoryID, ok := categoryIDParam(c)
+	if !ok {
+		return
+	}
+
+	var request request.UpdateCategoryRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	request.CategoryID = categoryID
+	request.UserID = userID
+
+	category, err := cc.category.UpdateCategory(request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, category)
+}
+
+// deleteCategory godoc
+// @Summary Delete a category
+// @Description Delete a category; subcategories and transactions must be moved to reassignTo first
+// @Tags categories
+// @Accept  json
+// @Produce  json
+// @Security Bearer
+// @Param id path int true "Category ID"
+// @Param reassignTo query int false "Category that receives the subcategories and transactions"
+// @Success 204 "No Content"
+// @Failure 400 {object} dtos.ErrorResponse
+// @Failure 401 {object} dtos.ErrorResponse
+// @Router /categories/{id} [delete]
+func (cc *CategoryController) deleteCategory(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	categoryID, ok := categoryIDParam(c)
+	if !ok {
+		return
+	}
+
+	var reassignTo *int
+	if value := c.Query("reassignTo"); value != "" {
+		target, errParam := strconv.Atoi(value)
+		if errParam != nil {
+			c.JSON(http.StatusBadRequest, response.ErrorResponse{Error: "Invalid reassignTo category ID"})
+			return
+		}
+		reassignTo = &target
+	}
+
+	if err := cc.category.DeleteCategory(userID, categoryID, reassignTo); err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
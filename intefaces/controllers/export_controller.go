@@ -0,0 +1,113 @@
+package controllers
+
+import (
+	request "Financial/Core/Models/dtos/Request"
+	response "Financial/Core/Models/dtos/Response"
+	"Financial/Core/exporters"
+	contracts "Financial/Core/ports"
+	"Financial/Core/types"
+	"Financial/intefaces/middleware"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExportController handles the export of the data of the authenticated user
+// @Summary Data export
+// @Description Provides an endpoint for downloading wallets and movements as CSV, JSON Lines or OFX
+type ExportController struct {
+	*BaseController
+	export         contracts.ExportUseCase
+	authMiddleware *middleware.AuthMiddleware
+}
+
+func NewExportController(exportUseCase contracts.ExportUseCase, auth *middleware.AuthMiddleware) *ExportController {
+	return &ExportController{
+		BaseController: NewBaseController("/export"),
+		export:         exportUseCase,
+		authMiddleware: auth,
+	}
+}
+
+func (ec *ExportController) RegisterRoutes(router *gin.RouterGroup) {
+	ec.authMiddleware.Config.AddScopedRoute("GET", "/api/export", types.ScopeWalletsRead)
+
+	protected := router.Group("/export")
+	protected.Use(ec.authMiddleware.AuthMiddleware())
+	{
+		protected.GET("", ec.exportData)
+	}
+}
+
+// dateQuery reads an optional YYYY-MM-DD query parameter, answering 400 when it is malformed.
+func dateQuery(c *gin.Context, name string) (*time.Time, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, true
+	}
+	date, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{Error: fmt.Sprintf("Invalid %s date, use YYYY-MM-DD", name)})
+		return nil, false
+	}
+	return &date, true
+}
+
+// exportData godoc
+// @Summary Export wallets and movements
+// @Description Stream every wallet of the authenticated user and its movements, oldest first
+// @Tags export
+// @Produce  text/csv
+// @Produce  application/x-ndjson
+// @Produce  application/x-ofx
+// @Security Bearer
+// @Param format query string false "csv (default), jsonl or ofx"
+// @Param from query string false "First day of the movements, YYYY-MM-DD"
+// @Param to query string false "Last day of the movements, YYYY-MM-DD"
+// @Success 200 {file} file
+// @Failure 400 {object} dtos.ErrorResponse
+// @Failure 401 {object} dtos.ErrorResponse
+// @Router /export [get]
+func (ec *ExportController) exportData(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	from, ok := dateQuery(c, "from")
+	if !ok {
+		return
+	}
+	to, ok := dateQuery(c, "to")
+	if !ok {
+		return
+	}
+
+	format, err := exporters.LookupFormat(c.Query("format"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.Header("Content-Type", format.ContentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="export-%s.%s"`, time.Now().Format("20060102"), format.Extension))
+
+	errExport := ec.export.Export(request.ExportRequest{
+		UserID: userID,
+		Format: format.Name,
+		From:   from,
+		To:     to,
+	}, c.Writer)
+	if errExport != nil {
+		if !c.Writer.Written() {
+			c.Writer.Header().Del("Content-Disposition")
+			c.JSON(http.StatusBadRequest, errExport)
+			return
+		}
+		// The response has already started, the client gets a truncated file
+		log.Printf("export of user %d interrupted: %s", userID, errExport.Error)
+		c.Abort()
+	}
+}
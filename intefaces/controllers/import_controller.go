@@ -0,0 +1,165 @@
+package controllers
+
+import (
+	request "Financial/Core/Models/dtos/Request"
+	response "Financial/Core/Models/dtos/Response"
+	contracts "Financial/Core/ports"
+	"Financial/Core/types"
+	"Financial/intefaces/middleware"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxStatementSize limits the size of uploaded statements (5 MB)
+const maxStatementSize = 5 << 20
+
+// ImportController handles the import of bank statements into a wallet
+// @Summary Statement import
+// @Description Provides endpoints for previewing and importing CSV, OFX and QIF bank statements
+type ImportController struct {
+	*BaseController
+	importer       contracts.ImportUseCase
+	authMiddleware *middleware.AuthMiddleware
+}
+
+func NewImportController(importUseCase contracts.ImportUseCase, auth *middleware.AuthMiddleware) *ImportController {
+	return &ImportController{
+		BaseController: NewBaseController("/wallets/:walletId/import"),
+		importer:       importUseCase,
+		authMiddleware: auth,
+	}
+}
+
+func (ic *ImportController) RegisterRoutes(router *gin.RouterGroup) {
+	ic.authMiddleware.Config.AddScopedRoute("POST", "/api/wallets/:walletId/import/preview", types.ScopeWalletsWrite)
+	ic.authMiddleware.Config.AddScopedRoute("POST", "/api/wallets/:walletId/import", types.ScopeWalletsWrite)
+
+	protected := router.Group("/wallets/:walletId/import")
+	protected.Use(ic.authMiddleware.AuthMiddleware())
+	{
+		protected.POST("/preview", ic.previewImport)
+		protected.POST("", ic.commitImport)
+	}
+}
+
+// bindStatement reads the uploaded statement and its options, answering 400 when they are missing.
+// The format defaults to the extension of the file.
+func bindStatement(c *gin.Context) (request.ImportStatementRequest, bool) {
+	var statement request.ImportStatementRequest
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return statement, false
+	}
+	walletID, ok := walletIDParam(c)
+	if !ok {
+		return statement, false
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{Error: "The statement file is required"})
+		return statement, false
+	}
+	if header.Size > maxStatementSize {
+		c.JSON(http.StatusRequestEntityTooLarge, response.ErrorResponse{Error: "The statement file is too large"})
+		return statement, false
+	}
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{Error: "The statement file can't be read"})
+		return statement, false
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(io.LimitReader(file, maxStatementSize))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{Error: "The statement file can't be read"})
+		return statement, false
+	}
+
+	if err := c.ShouldBind(&statement.Mapping); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid column mapping"})
+		return statement, false
+	}
+
+	statement.UserID = userID
+	statement.WalletID = walletID
+	statement.Content = content
+	statement.Format = c.PostForm("format")
+	if statement.Format == "" {
+		statement.Format = strings.TrimPrefix(strings.ToLower(filepath.Ext(header.Filename)), ".")
+	}
+	return statement, true
+}
+
+// previewImport godoc
+// @Summary Preview a statement import
+// @Description Parse a CSV, OFX or QIF statement and flag the movements the wallet already has, without recording anything
+// @Tags import
+// @Accept  multipart/form-data
+// @Produce  json
+// @Security Bearer
+// @Param walletId path int true "Wallet ID"
+// @Param file formData file true "Statement file"
+// @Param format formData string false "csv, ofx or qif (defaults to the file extension)"
+// @Param date_column formData string false "CSV date column (header name or zero-based index)"
+// @Param amount_column formData string false "CSV signed amount column"
+// @Param debit_column formData string false "CSV debit column, used when there is no amount column"
+// @Param credit_column formData string false "CSV credit column, used when there is no amount column"
+// @Param description_column formData string false "CSV description column"
+// @Param date_format formData string false "CSV date layout in Go notation (e.g. 02/01/2006)"
+// @Param delimiter formData string false "CSV delimiter (defaults to a comma)"
+// @Param decimal_comma formData bool false "CSV amounts use a decimal comma"
+// @Param no_header formData bool false "CSV has no header row"
+// @Success 200 {object} response.ImportPreviewResponse
+// @Failure 400 {object} dtos.ErrorResponse
+// @Failure 401 {object} dtos.ErrorResponse
+// @Router /wallets/{walletId}/import/preview [post]
+func (ic *ImportController) previewImport(c *gin.Context) {
+	statement, ok := bindStatement(c)
+	if !ok {
+		return
+	}
+
+	preview, err := ic.importer.PreviewImport(statement)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, preview)
+}
+
+// commitImport godoc
+// @Summary Import a statement
+// @Description Record the movements of a CSV, OFX or QIF statement that the wallet does not have yet. Takes the same form as the preview.
+// @Tags import
+// @Accept  multipart/form-data
+// @Produce  json
+// @Security Bearer
+// @Param walletId path int true "Wallet ID"
+// @Param file formData file true "Statement file"
+// @Param format formData string false "csv, ofx or qif (defaults to the file extension)"
+// @Success 201 {object} response.ImportResultResponse
+// @Failure 400 {object} dtos.ErrorResponse
+// @Failure 401 {object} dtos.ErrorResponse
+// @Router /wallets/{walletId}/import [post]
+func (ic *ImportController) commitImport(c *gin.Context) {
+	statement, ok := bindStatement(c)
+	if !ok {
+		return
+	}
+
+	result, err := ic.importer.CommitImport(statement)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, result)
+}
@@ -0,0 +1,213 @@
+package controllers
+
+import (
+	request "Financial/Core/Models/dtos/Request"
+	response "Financial/Core/Models/dtos/Response"
+	contracts "Financial/Core/ports"
+	"Financial/intefaces/middleware"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RecurringController handles the recurring incomes and expenses of the authenticated user
+// @Summary Recurring transactions
+// @Description Provides endpoints for scheduling, pausing and ending recurring transactions
+type RecurringController struct {
+	*BaseController
+	recurring      contracts.RecurringTransactionUseCase
+	authMiddleware *middleware.AuthMiddleware
+}
+
+func NewRecurringController(recurringUseCase contracts.RecurringTransactionUseCase, auth *middleware.AuthMiddleware) *RecurringController {
+	return &RecurringController{
+		BaseController: NewBaseController("/recurring"),
+		recurring:      recurringUseCase,
+		authMiddleware: auth,
+	}
+}
+
+func (rc *RecurringController) RegisterRoutes(router *gin.RouterGroup) {
+	protected := router.Group("/recurring")
+	protected.Use(rc.authMiddleware.AuthMiddleware())
+	{
+		protected.GET("", rc.getUserRecurring)
+		protected.GET(":id", rc.getRecurring)
+		protected.POST("", rc.createRecurring)
+		protected.PUT(":id", rc.updateRecurring)
+		protected.DELETE(":id", rc.deleteRecurring)
+	}
+}
+
+// recurringIDParam reads the recurring transaction ID from the route, answering 400 when it is not a number.
+func recurringIDParam(c *gin.Context) (int, bool) {
+	recurringID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{Error: "Invalid recurring transaction ID"})
+		return 0, false
+	}
+	return recurringID, true
+}
+
+// getUserRecurring godoc
+// @Summary List recurring transactions
+// @Description Get the recurring transactions of the authenticated user
+// @Tags recurring
+// @Accept  json
+// @Produce  json
+// @Security Bearer
+// @Success 200 {array} db.RecurringTransaction
+// @Failure 400 {object} dtos.ErrorResponse
+// @Failure 401 {object} dtos.ErrorResponse
+// @Router /recurring [get]
+func (rc *RecurringController) getUserRecurring(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	series, err := rc.recurring.GetUserRecurring(userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, series)
+}
+
+// getRecurring godoc
+// @Summary Get a recurring transaction
+// @Description Get a recurring transaction of the authenticated user, including its next occurrence
+// @Tags recurring
+// @Accept  json
+// @Produce  json
+// @Security Bearer
+// @Param id path int true "Recurring transaction ID"
+// @Success 200 {object} db.RecurringTransaction
+// @Failure 400 {object} dtos.ErrorResponse
+// @Failure 401 {object} dtos.ErrorResponse
+// @Failure 404 {object} dtos.ErrorResponse
+// @Router /recurring/{id} [get]
+func (rc *RecurringController) getRecurring(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	recurringID, ok := recurringIDParam(c)
+	if !ok {
+		return
+	}
+
+	series, err := rc.recurring.GetRecurring(userID, recurringID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, series)
+}
+
+// createRecurring godoc
+// @Summary Create a recurring transaction
+// @Description Schedule an income or expense that is posted to a wallet on every occurrence
+// @Tags recurring
+// @Accept  json
+// @Produce  json
+// @Security Bearer
+// @Param recurring body dtos.CreateRecurringRequest true "Movement and schedule"
+// @Success 201 {object} db.RecurringTransaction
+// @Failure 400 {object} dtos.ErrorResponse
+// @Failure 401 {object} dtos.ErrorResponse
+// @Router /recurring [post]
+func (rc *RecurringController) createRecurring(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var request request.CreateRecurringRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	request.UserID = userID
+
+	series, err := rc.recurring.CreateRecurring(request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, series)
+}
+
+// updateRecurring godoc
+// @Summary Update a recurring transaction
+// @Description Change the amount, description or end date, or pause, resume or end the series
+// @Tags recurring
+// @Accept  json
+// @Produce  json
+// @Security Bearer
+// @Param id path int true "Recurring transaction ID"
+// @Param recurring body dtos.UpdateRecurringRequest true "Changes"
+// @Success 200 {object} db.RecurringTransaction
+// @Failure 400 {object} dtos.ErrorResponse
+// @Failure 401 {object} dtos.ErrorResponse
+// @Router /recurring/{id} [put]
+func (rc *RecurringController) updateRecurring(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	recurringID, ok := recurringIDParam(c)
+	if !ok {
+		return
+	}
+
+	var request request.UpdateRecurringRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	request.RecurringID = recurringID
+	request.UserID = userID
+
+	series, err := rc.recurring.UpdateRecurring(request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, series)
+}
+
+// deleteRecurring godoc
+// @Summary Delete a recurring transaction
+// @Description Remove a series; transactions already posted are kept
+// @Tags recurring
+// @Accept  json
+// @Produce  json
+// @Security Bearer
+// @Param id path int true "Recurring transaction ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} dtos.ErrorResponse
+// @Failure 401 {object} dtos.ErrorResponse
+// @Router /recurring/{id} [delete]
+func (rc *RecurringController) deleteRecurring(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	recurringID, ok := recurringIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := rc.recurring.DeleteRecurring(userID, recurringID); err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,172 @@
+package controllers
+
+import (
+	request "Financial/Core/Models/dtos/Request"
+	response "Financial/Core/Models/dtos/Response"
+	contracts "Financial/Core/ports"
+	"Financial/Core/types"
+	"Financial/intefaces/middleware"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TransactionController handles the ledger of a wallet
+// @Summary Wallet transactions
+// @Description Provides endpoints for recording and listing the income and expenses of a wallet
+type TransactionController struct {
+	*BaseController
+	transaction    contracts.TransactionUseCase
+	authMiddleware *middleware.AuthMiddleware
+}
+
+func NewTransactionController(transactionUseCase contracts.TransactionUseCase, auth *middleware.AuthMiddleware) *TransactionController {
+	return &TransactionController{
+		BaseController: NewBaseController("/wallets/:walletId/transactions"),
+		transaction:    transactionUseCase,
+		authMiddleware: auth,
+	}
+}
+
+func (tc *TransactionController) RegisterRoutes(router *gin.RouterGroup) {
+	tc.authMiddleware.Config.AddScopedRoute("GET", "/api/wallets/:walletId/transactions", types.ScopeWalletsRead)
+	tc.authMiddleware.Config.AddScopedRoute("POST", "/api/wallets/:walletId/transactions", types.ScopeWalletsWrite)
+	tc.authMiddleware.Config.AddScopedRoute("DELETE", "/api/wallets/:walletId/transactions/:transactionId", types.ScopeWalletsWrite)
+
+	protected := router.Group("/wallets/:walletId/transactions")
+	protected.Use(tc.authMiddleware.AuthMiddleware())
+	{
+		protected.GET("", tc.getTransactions)
+		protected.POST("", tc.createTransaction)
+		protected.DELETE(":transactionId", tc.deleteTransaction)
+	}
+}
+
+// walletIDParam reads the wallet ID from the route, answering 400 when it is not a number.
+func walletIDParam(c *gin.Context) (int, bool) {
+	walletID, err := strconv.Atoi(c.Param("walletId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{Error: "Invalid wallet ID"})
+		return 0, false
+	}
+	return walletID, true
+}
+
+// getTransactions godoc
+// @Summary List wallet transactions
+// @Description Get the ledger of a wallet of the authenticated user, newest first. Support staff and admins can read any wallet
+// @Tags transactions
+// @Accept  json
+// @Produce  json
+// @Security Bearer
+// @Param walletId path int true "Wallet ID"
+// @Success 200 {array} db.Transaction
+// @Failure 400 {object} dtos.ErrorResponse
+// @Failure 401 {object} dtos.ErrorResponse
+// @Router /wallets/{walletId}/transactions [get]
+func (tc *TransactionController) getTransactions(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	walletID, ok := walletIDParam(c)
+	if !ok {
+		return
+	}
+
+	// Staff allowed to inspect wallets may read the ledger of any of them
+	owner := userID
+	if tc.authMiddleware.HasPermission(c, types.PermWalletsRead) {
+		owner = 0
+	}
+
+	transactions, err := tc.transaction.GetWalletTransactions(owner, walletID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, transactions)
+}
+
+// createTransaction godoc
+// @Summary Record a transaction
+// @Description Record an income or expense on a wallet and update its balance
+// @Tags transactions
+// @Accept  json
+// @Produce  json
+// @Security Bearer
+// @Param walletId path int true "Wallet ID"
+// @Param transaction body dtos.CreateTransactionRequest true "Transaction data"
+// @Success 201 {object} db.Transaction
+// @Failure 400 {object} dtos.ErrorResponse
+// @Failure 401 {object} dtos.ErrorResponse
+// @Router /wallets/{walletId}/transactions [post]
+func (tc *TransactionController) createTransaction(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	walletID, ok := walletIDParam(c)
+	if !ok {
+		return
+	}
+
+	var request request.CreateTransactionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	// The wallet always comes from the route
+	request.WalletID = walletID
+
+	transaction, err := tc.transaction.RecordTransaction(userID, request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, transaction)
+}
+
+// deleteTransaction godoc
+// @Summary Delete a transaction
+// @Description Remove a transaction from the ledger and revert its effect on the wallet balance
+// @Tags transactions
+// @Accept  json
+// @Produce  json
+// @Security Bearer
+// @Param walletId path int true "Wallet ID"
+// @Param transactionId path int true "Transaction ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} dtos.ErrorResponse
+// @Failure 401 {object} dtos.ErrorResponse
+// @Router /wallets/{walletId}/transactions/{transactionId} [delete]
+func (tc *TransactionController) deleteTransaction(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	walletID, ok := walletIDParam(c)
+	if !ok {
+		return
+	}
+
+	transactionID, errParam := strconv.Atoi(c.Param("transactionId"))
+	if errParam != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{Error: "Invalid transaction ID"})
+		return
+	}
+
+	if err := tc.transaction.DeleteTransaction(userID, walletID, transactionID); err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	request "Financial/Core/Models/dtos/Request"
+	contracts "Financial/Core/ports"
+	"Financial/Core/types"
+	"Financial/intefaces/middleware"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TransferController handles money movements between wallets
+// @Summary Wallet transfers
+// @Description Provides endpoints for moving money between wallets of the same user
+type TransferController struct {
+	*BaseController
+	transfer       contracts.TransferUseCase
+	authMiddleware *middleware.AuthMiddleware
+}
+
+func NewTransferController(transferUseCase contracts.TransferUseCase, auth *middleware.AuthMiddleware) *TransferController {
+	return &TransferController{
+		BaseController: NewBaseController("/transfers"),
+		transfer:       transferUseCase,
+		authMiddleware: auth,
+	}
+}
+
+func (tc *TransferController) RegisterRoutes(router *gin.RouterGroup) {
+	tc.authMiddleware.Config.AddScopedRoute("POST", "/api/transfers", types.ScopeWalletsWrite)
+
+	protected := router.Group("/transfers")
+	protected.Use(tc.authMiddleware.AuthMiddleware())
+	{
+		protected.POST("", tc.createTransfer)
+	}
+}
+
+// createTransfer godoc
+// @Summary Transfer between wallets
+// @Description Atomically move an amount from one wallet to another owned by the same user
+// @Tags transfers
+// @Accept  json
+// @Produce  json
+// @Security Bearer
+// @Param transfer body dtos.CreateTransferRequest true "Transfer data"
+// @Success 201 {object} db.Transfer
+// @Failure 400 {object} dtos.ErrorResponse
+// @Failure 401 {object} dtos.ErrorResponse
+// @Router /transfers [post]
+func (tc *TransferController) createTransfer(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var request request.CreateTransferRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	transfer, err := tc.transfer.Transfer(userID, request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, transfer)
+}
@@ -19,8 +19,10 @@ import (
 	request "Financial/Core/Models/dtos/Request"
 	response "Financial/Core/Models/dtos/Response"
 	contracts "Financial/Core/ports"
+	"Financial/Core/types"
 	"Financial/intefaces/middleware"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -43,41 +45,79 @@ func NewWalletController(walletUseCase contracts.WalletUseCase, auth *middleware
 }
 
 func (wc *WalletController) RegisterRoutes(router *gin.RouterGroup) {
-	wc.authMiddlerware.Config.AddPublicRoute("GET", "/api/wallet/:email")
-	public := router.Group("/wallet")
-	{
-		public.GET(":email", wc.getUserWallets)
-	}
+	wc.authMiddlerware.Config.AddScopedRoute("GET", "/api/wallet", types.ScopeWalletsRead)
+	wc.authMiddlerware.Config.AddScopedRoute("GET", "/api/wallet/:email", types.ScopeWalletsRead)
+	wc.authMiddlerware.Config.AddScopedRoute("POST", "/api/wallet", types.ScopeWalletsWrite)
+	wc.authMiddlerware.Config.AddScopedRoute("PUT", "/api/wallet/:walletId", types.ScopeWalletsWrite)
+	wc.authMiddlerware.Config.AddScopedRoute("DELETE", "/api/wallet/:walletId", types.ScopeWalletsWrite)
+
 	protected := router.Group("/wallet")
 	protected.Use(wc.authMiddlerware.AuthMiddleware())
 	{
 		protected.GET("", wc.getUserWallets)
+		protected.GET(":email", wc.getUserWallets)
 		protected.POST("", wc.createWallet)
-		protected.PUT(":id", wc.updateWallet)
-		protected.DELETE(":id", wc.deleteWallet)
+		protected.PUT(":walletId", wc.updateWallet)
+		// Deleting a wallet needs a recent two-factor verification when it is enabled
+		protected.DELETE(":walletId", wc.authMiddlerware.RequireRecent2FA(), wc.deleteWallet)
+	}
+}
+
+// asOfParam reads the optional asOf query (YYYY-MM-DD or RFC3339), answering 400 when it is not a date.
+// A plain date means the rates in force at the end of that day; no value is the zero time.
+func asOfParam(c *gin.Context) (time.Time, bool) {
+	value := c.Query("asOf")
+	if value == "" {
+		return time.Time{}, true
+	}
+	parsed, errDate := time.Parse(time.DateOnly, value)
+	if errDate != nil {
+		parsed, errDate = time.Parse(time.RFC3339, value)
 	}
+	if errDate != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{Error: "Invalid asOf date"})
+		return time.Time{}, false
+	}
+	if len(value) == len(time.DateOnly) {
+		parsed = parsed.Add(24*time.Hour - time.Nanosecond)
+	}
+	return parsed, true
 }
 
 // getUserWallets godoc
 // @Summary Get user wallets
-// @Description Get all wallets for the authenticated user
+// @Description Get all wallets for the authenticated user. Looking up another email only works for support staff and admins
 // @Tags wallets
 // @Accept  json
 // @Produce  json
-// @Param email path string true "User email"
+// @Param email path string false "User email (defaults to the authenticated user)"
+// @Param currency query string false "Reporting currency (ISO 4217) to convert the balances to"
+// @Param asOf query string false "Date of the exchange rates, YYYY-MM-DD or RFC3339 (default now)"
 // @Security Bearer
-// @Success 200 {object} ports.UserWallet
+// @Success 200 {object} response.UserWalletResponse
 // @Failure 400 {object} dtos.ErrorResponse
 // @Failure 401 {object} dtos.ErrorResponse
 // @Router /wallet/{email} [get]
 // @Router /wallet [get]
 func (wc *WalletController) getUserWallets(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
 	email := c.Param("email")
-	if email == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Email is empty"})
+
+	// Only staff allowed to inspect wallets may read an email that is not theirs
+	owner := userID
+	if email != "" && wc.authMiddlerware.HasPermission(c, types.PermWalletsRead) {
+		owner = 0
+	}
+
+	asOf, ok := asOfParam(c)
+	if !ok {
 		return
 	}
-	wallet, err := wc.wallet.GetUserWallet(0, email)
+
+	wallet, err := wc.wallet.GetUserWallet(owner, email, c.Query("currency"), asOf)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, err)
 		return
@@ -98,9 +138,8 @@ func (wc *WalletController) getUserWallets(c *gin.Context) {
 // @Failure 401 {object} dtos.ErrorResponse
 // @Router /wallet [post]
 func (wc *WalletController) createWallet(c *gin.Context) {
-	userID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+	userID, ok := currentUserID(c)
+	if !ok {
 		return
 	}
 
@@ -111,7 +150,7 @@ func (wc *WalletController) createWallet(c *gin.Context) {
 	}
 
 	// Set the user ID from the authenticated user
-	request.UserID = userID.(int)
+	request.UserID = userID
 
 	wallet, err := wc.wallet.CreateWallet(request)
 	if err != nil {
@@ -129,19 +168,33 @@ func (wc *WalletController) createWallet(c *gin.Context) {
 // @Accept  json
 // @Produce  json
 // @Security Bearer
-// @Param id path int true "Wallet ID"
+// @Param walletId path int true "Wallet ID"
 // @Param wallet body dtos.UpdateWalletRequest true "Wallet update data"
 // @Success 200 {object} db.Wallet
 // @Failure 400 {object} dtos.ErrorResponse
 // @Failure 401 {object} dtos.ErrorResponse
-// @Router /wallet/{id} [put]
+// @Router /wallet/{walletId} [put]
 func (wc *WalletController) updateWallet(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	walletID, ok := walletIDParam(c)
+	if !ok {
+		return
+	}
+
 	var request request.UpdateWalletRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
 		return
 	}
-	updatedWallet, err := wc.wallet.UpdateWallet(request)
+
+	// The wallet always comes from the route
+	request.WalletID = walletID
+
+	updatedWallet, err := wc.wallet.UpdateWallet(userID, request)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, err)
 		return
@@ -157,24 +210,29 @@ func (wc *WalletController) updateWallet(c *gin.Context) {
 // @Accept  json
 // @Produce  json
 // @Security Bearer
-// @Param id path int true "Wallet ID"
+// @Param walletId path int true "Wallet ID"
 // @Success 204 "No Content"
 // @Failure 400 {object} dtos.ErrorResponse
 // @Failure 401 {object} dtos.ErrorResponse
+// @Failure 404 {object} dtos.ErrorResponse
 // @Failure 500 {object} dtos.ErrorResponse
-// @Router /wallet/{id} [delete]
+// @Router /wallet/{walletId} [delete]
 func (wc *WalletController) deleteWallet(c *gin.Context) {
-	var request request.DeleteWalletRequest
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
 
-	if err := c.ShouldBindJSON(&request); err != nil {
-		var errorRes = response.ErrorResponse{
-			Error: "Solicitud inválida",
-		}
-		c.JSON(400, errorRes)
+	walletID, ok := walletIDParam(c)
+	if !ok {
 		return
 	}
 
-	if err := wc.wallet.DeleteWallet(request.ID); err != nil {
+	if err := wc.wallet.DeleteWallet(userID, walletID); err != nil {
+		if err.Error() == "wallet not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete wallet"})
 		return
 	}
@@ -1,13 +1,16 @@
 package middleware
 
 import (
+	"Financial/Core/types"
 	"strings"
 	"sync"
 )
 
 type AuthConfig struct {
 	PublicRoutes []string
-	mu           sync.Mutex
+	// RouteScopes indica qué scope necesita una API key para cada ruta ("MÉTODO_ruta")
+	RouteScopes map[string]types.APIKeyScope
+	mu          sync.Mutex
 }
 
 func NewAuthConfig() *AuthConfig {
@@ -17,6 +20,7 @@ func NewAuthConfig() *AuthConfig {
 			"POST_/api/auth/login",
 			"POST_/api/auth/register",
 		},
+		RouteScopes: map[string]types.APIKeyScope{},
 	}
 }
 
@@ -54,11 +58,30 @@ func (ac *AuthConfig) IsPublicRoute(method, path string) bool {
 			return true
 		}
 
-		// Si el método coincide y la ruta coincide con el prefijo
+		// Si el método coincide y la ruta coincide exactamente, o con el prefijo
+		// cuando la ruta registrada termina en "/" (ej. "/swagger/")
 		if (routeMethod == method || routeMethod == "ANY") &&
-			(path == routePath || strings.HasPrefix(path, routePath+"/")) {
+			(path == routePath || (strings.HasSuffix(routePath, "/") && strings.HasPrefix(path, routePath))) {
 			return true
 		}
 	}
 	return false
 }
+
+// AddScopedRoute permite usar una ruta con una API key que tenga el scope indicado.
+// Las rutas sin scope solo aceptan tokens JWT
+func (ac *AuthConfig) AddScopedRoute(method, path string, scope types.APIKeyScope) {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+
+	ac.RouteScopes[strings.ToUpper(method)+"_"+path] = scope
+}
+
+// RouteScope devuelve el scope que necesita una API key para la ruta, si lo tiene
+func (ac *AuthConfig) RouteScope(method, path string) (types.APIKeyScope, bool) {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+
+	scope, ok := ac.RouteScopes[strings.ToUpper(method)+"_"+path]
+	return scope, ok
+}
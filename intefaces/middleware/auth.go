@@ -2,9 +2,16 @@ package middleware
 
 import (
 	models "Financial/Core/Models"
+	contracts "Financial/Core/ports"
+	"Financial/Core/signing"
+	"Financial/Core/types"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -12,23 +19,91 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// defaultAccessTokenTTL is the lifetime of access tokens when ACCESS_TOKEN_TTL is not set.
+// Access tokens are short-lived; clients renew them with their refresh token.
+const defaultAccessTokenTTL = 15 * time.Minute
+
+// defaultTwoFactorMaxAge is how long a two-factor verification unlocks sensitive routes
+// when TWO_FACTOR_MAX_AGE is not set
+const defaultTwoFactorMaxAge = 10 * time.Minute
+
+// challengeTokenTTL is how long a login has to send its two-factor code
+const challengeTokenTTL = 5 * time.Minute
+
+// challengePurpose marks the tokens that only serve to complete a two-factor login
+const challengePurpose = "2fa"
+
 type AuthMiddleware struct {
-	secretKey []byte
-	Config    *AuthConfig
+	keys            *signing.KeyRing
+	accessTTL       time.Duration
+	twoFactorMaxAge time.Duration
+	sessions        contracts.SessionUseCase
+	twoFactor       contracts.TwoFactorUseCase
+	authorizer      contracts.Authorizer
+	apiKeys         contracts.APIKeyUseCase
+	Config          *AuthConfig
 }
 
-func NewAuthMiddleware() *AuthMiddleware {
-	secretKey := os.Getenv("JWT_SECRET_KEY")
-	if secretKey == "" {
-		// Clave secreta por defecto (deberías establecer esto en tus variables de entorno)
-		secretKey = "tu_clave_secreta_muy_segura"
+// NewAuthMiddleware crea el middleware que firma y valida los tokens con las claves del anillo
+func NewAuthMiddleware(keys *signing.KeyRing) *AuthMiddleware {
+	accessTTL, err := time.ParseDuration(os.Getenv("ACCESS_TOKEN_TTL"))
+	if err != nil || accessTTL <= 0 {
+		accessTTL = defaultAccessTokenTTL
 	}
+	twoFactorMaxAge, err := time.ParseDuration(os.Getenv("TWO_FACTOR_MAX_AGE"))
+	if err != nil || twoFactorMaxAge <= 0 {
+		twoFactorMaxAge = defaultTwoFactorMaxAge
+	}
+	// Una clave retirada sigue validando mientras pueda quedar algún token firmado con ella
+	keys.SetRetention(max(accessTTL, challengeTokenTTL))
 	return &AuthMiddleware{
-		secretKey: []byte(secretKey),
-		Config:    NewAuthConfig(),
+		keys:            keys,
+		accessTTL:       accessTTL,
+		twoFactorMaxAge: twoFactorMaxAge,
+		Config:          NewAuthConfig(),
 	}
 }
 
+// UseSessions hace que el middleware rechace los tokens cuya sesión (jti) fue revocada o rotada
+func (m *AuthMiddleware) UseSessions(sessions contracts.SessionUseCase) {
+	m.sessions = sessions
+}
+
+// UseTwoFactor permite a RequireRecent2FA saber qué usuarios tienen la verificación en dos pasos activada
+func (m *AuthMiddleware) UseTwoFactor(twoFactor contracts.TwoFactorUseCase) {
+	m.twoFactor = twoFactor
+}
+
+// UseAuthorizer define quién decide los permisos de RequirePermission y HasPermission
+func (m *AuthMiddleware) UseAuthorizer(authorizer contracts.Authorizer) {
+	m.authorizer = authorizer
+}
+
+// UseAPIKeys permite autenticar con la cabecera X-API-Key en las rutas registradas con AddScopedRoute
+func (m *AuthMiddleware) UseAPIKeys(apiKeys contracts.APIKeyUseCase) {
+	m.apiKeys = apiKeys
+}
+
+// JWKS publica las claves públicas con las que se validan los tokens, para que otros
+// servicios puedan verificarlos sin compartir ningún secreto
+func (m *AuthMiddleware) JWKS() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Tras una rotación los clientes deben ver la clave nueva en pocos minutos
+		c.Header("Cache-Control", "public, max-age=300")
+		c.JSON(http.StatusOK, m.keys.JWKS())
+	}
+}
+
+// ChallengeTokenTTL devuelve la duración de los tokens emitidos por GenerateChallengeToken
+func (m *AuthMiddleware) ChallengeTokenTTL() time.Duration {
+	return challengeTokenTTL
+}
+
+// AccessTokenTTL devuelve la duración de los tokens de acceso emitidos por GenerateToken
+func (m *AuthMiddleware) AccessTokenTTL() time.Duration {
+	return m.accessTTL
+}
+
 // SkipAuth verifica si la ruta actual está en la lista de rutas que no requieren autenticación
 func (m *AuthMiddleware) SkipAuth(c *gin.Context, skipRoutes []string) bool {
 	path := c.FullPath()
@@ -62,6 +137,12 @@ func (m *AuthMiddleware) AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		// Una API key sustituye al token JWT
+		if key := c.GetHeader("X-API-Key"); key != "" {
+			m.authenticateAPIKey(c, key)
+			return
+		}
+
 		tokenString := c.GetHeader("Authorization")
 		if tokenString == "" {
 			c.JSON(http.StatusUnauthorized, authError)
@@ -73,14 +154,7 @@ func (m *AuthMiddleware) AuthMiddleware() gin.HandlerFunc {
 		tokenString = strings.TrimPrefix(tokenString, "Bearer ")
 
 		// Validar el token
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			// Validar el algoritmo de firma
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("método de firma inesperado: %v", token.Header["alg"])
-			}
-			return m.secretKey, nil
-		})
-
+		token, err := m.parse(tokenString)
 		if err != nil || !token.Valid {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido o expirado"})
 			c.Abort()
@@ -89,27 +163,252 @@ func (m *AuthMiddleware) AuthMiddleware() gin.HandlerFunc {
 
 		// Extraer claims del token
 		if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
+			// Un token de desafío solo sirve para completar el login en dos pasos
+			if _, ok := claims["purpose"]; ok {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido o expirado"})
+				c.Abort()
+				return
+			}
+
+			// Rechazar tokens de sesiones cerradas o ya renovadas
+			tokenID, _ := claims["jti"].(string)
+			if m.sessions != nil {
+				if err := m.sessions.ValidateAccess(tokenID); err != nil {
+					c.JSON(http.StatusUnauthorized, gin.H{"error": "Token revocado"})
+					c.Abort()
+					return
+				}
+			}
+
 			// Agregar el ID de usuario al contexto para que esté disponible en los controladores
 			c.Set("userID", claims["sub"])
+			c.Set("tokenID", tokenID)
+			if verifiedAt, ok := claims["tfa"].(float64); ok {
+				c.Set("twoFactorAt", time.Unix(int64(verifiedAt), 0))
+			}
 		}
 
 		c.Next()
 	}
 }
 
-// GenerateToken genera un nuevo token JWT para un usuario
-func (m *AuthMiddleware) GenerateToken(userID string) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"sub": userID,
-		// Puedes agregar más claims según sea necesario
-		"exp": jwt.NewNumericDate(time.Now().Add(24 * time.Hour)), // Token expira en 24 horas
-	})
+// authenticateAPIKey valida una API key y comprueba que alguno de sus scopes cubra la ruta
+func (m *AuthMiddleware) authenticateAPIKey(c *gin.Context, key string) {
+	if m.apiKeys == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "API key inválida o expirada"})
+		c.Abort()
+		return
+	}
 
-	// Firmar el token con la clave secreta
-	tokenString, err := token.SignedString(m.secretKey)
+	principal, err := m.apiKeys.Authenticate(key)
 	if err != nil {
+		if errors.Is(err, types.ErrInvalidAPIKey) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "API key inválida o expirada"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudo comprobar la API key"})
+		}
+		c.Abort()
+		return
+	}
+
+	// Las rutas sin scope (gestión de sesiones, de API keys, administración...) solo aceptan JWT
+	required, ok := m.Config.RouteScope(c.Request.Method, c.FullPath())
+	if !ok || !grants(principal.Scopes, required) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "La API key no tiene permiso para esta ruta"})
+		c.Abort()
+		return
+	}
+
+	c.Set("userID", strconv.Itoa(principal.UserID))
+	c.Set("apiKeyID", principal.KeyID)
+	c.Next()
+}
+
+// grants indica si alguno de los scopes de una API key cubre el scope requerido
+func grants(scopes []string, required types.APIKeyScope) bool {
+	for _, scope := range scopes {
+		if types.APIKeyScope(scope).Grants(required) {
+			return true
+		}
+	}
+	return false
+}
+
+// RequireRecent2FA protege las rutas sensibles: si el usuario tiene la verificación en dos pasos
+// activada, su token debe venir de una verificación de hace menos de TWO_FACTOR_MAX_AGE.
+// Debe ir después de AuthMiddleware
+func (m *AuthMiddleware) RequireRecent2FA() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if m.twoFactor == nil {
+			c.Next()
+			return
+		}
+
+		userID, ok := subjectID(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido o expirado"})
+			c.Abort()
+			return
+		}
+
+		enabled, err := m.twoFactor.Enabled(userID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudo comprobar la verificación en dos pasos"})
+			c.Abort()
+			return
+		}
+		if !enabled {
+			c.Next()
+			return
+		}
+
+		verifiedAt, ok := c.Get("twoFactorAt")
+		if at, isTime := verifiedAt.(time.Time); !ok || !isTime || time.Since(at) > m.twoFactorMaxAge {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":               "Se requiere una verificación en dos pasos reciente",
+				"two_factor_required": true,
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequirePermission protege una ruta con un permiso del rol del usuario (ver types.Role).
+// Debe ir después de AuthMiddleware
+func (m *AuthMiddleware) RequirePermission(permission types.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := subjectID(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido o expirado"})
+			c.Abort()
+			return
+		}
+
+		if err := m.authorize(userID, permission); err != nil {
+			if errors.Is(err, types.ErrForbidden) {
+				c.JSON(http.StatusForbidden, gin.H{"error": "Permiso denegado"})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudieron comprobar los permisos"})
+			}
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// HasPermission indica si el usuario autenticado tiene un permiso, para las rutas que
+// amplían lo que devuelven según el rol. Ante cualquier error responde que no
+func (m *AuthMiddleware) HasPermission(c *gin.Context, permission types.Permission) bool {
+	userID, ok := subjectID(c)
+	return ok && m.authorize(userID, permission) == nil
+}
+
+// authorize consulta el permiso; sin autorizador configurado se deniega todo
+func (m *AuthMiddleware) authorize(userID int, permission types.Permission) error {
+	if m.authorizer == nil {
+		return types.ErrForbidden
+	}
+	return m.authorizer.Authorize(userID, permission)
+}
+
+// subjectID lee el ID numérico del usuario que AuthMiddleware guardó en el contexto
+func subjectID(c *gin.Context) (int, bool) {
+	subject, exists := c.Get("userID")
+	if !exists {
+		return 0, false
+	}
+	userID, err := strconv.Atoi(fmt.Sprint(subject))
+	return userID, err == nil
+}
+
+// GenerateToken genera un nuevo token de acceso JWT para un usuario.
+// tokenID es el jti que identifica la sesión, para poder revocarlo
+func (m *AuthMiddleware) GenerateToken(userID string, tokenID string) (string, error) {
+	return m.generate(userID, tokenID, nil)
+}
+
+// GenerateTokenWith2FA genera un token de acceso que además registra cuándo el usuario pasó
+// la verificación en dos pasos, para las rutas protegidas por RequireRecent2FA
+func (m *AuthMiddleware) GenerateTokenWith2FA(userID string, tokenID string, verifiedAt time.Time) (string, error) {
+	return m.generate(userID, tokenID, &verifiedAt)
+}
+
+// GenerateChallengeToken genera el token que recibe un login con la contraseña correcta
+// cuando la cuenta tiene la verificación en dos pasos activada. No da acceso a la API.
+// Su jti identifica el desafío, para que un token ya usado no complete otro login
+func (m *AuthMiddleware) GenerateChallengeToken(userID string) (string, error) {
+	challengeID := make([]byte, 16)
+	if _, err := rand.Read(challengeID); err != nil {
 		return "", err
 	}
+	now := time.Now()
+	return m.sign(jwt.MapClaims{
+		"sub":     userID,
+		"jti":     hex.EncodeToString(challengeID),
+		"purpose": challengePurpose,
+		"iat":     jwt.NewNumericDate(now),
+		"exp":     jwt.NewNumericDate(now.Add(challengeTokenTTL)),
+	})
+}
+
+// ParseChallengeToken valida un token de GenerateChallengeToken y devuelve su usuario y su jti
+func (m *AuthMiddleware) ParseChallengeToken(tokenString string) (string, string, error) {
+	token, err := m.parse(tokenString)
+	if err != nil || !token.Valid {
+		return "", "", fmt.Errorf("token de desafío inválido o expirado")
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || claims["purpose"] != challengePurpose {
+		return "", "", fmt.Errorf("token de desafío inválido o expirado")
+	}
+	subject, _ := claims["sub"].(string)
+	challengeID, _ := claims["jti"].(string)
+	if subject == "" || challengeID == "" {
+		return "", "", fmt.Errorf("token de desafío inválido o expirado")
+	}
+	return subject, challengeID, nil
+}
+
+// parse valida la firma y la expiración de un token con la clave que indica su kid
+func (m *AuthMiddleware) parse(tokenString string) (*jwt.Token, error) {
+	return jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := m.keys.Lookup(kid)
+		if !ok {
+			return nil, fmt.Errorf("clave de firma desconocida o retirada: %q", kid)
+		}
+		// El algoritmo lo fija la clave, no la cabecera del token
+		if token.Method.Alg() != key.Algorithm {
+			return nil, fmt.Errorf("método de firma inesperado: %v", token.Header["alg"])
+		}
+		return key.Public(), nil
+	}, jwt.WithValidMethods([]string{signing.RS256, signing.EdDSA}))
+}
+
+// sign firma los claims con la clave actual e indica en el kid cuál es
+func (m *AuthMiddleware) sign(claims jwt.MapClaims) (string, error) {
+	key := m.keys.Current()
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(key.Algorithm), claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.Private)
+}
 
-	return tokenString, nil
+// generate firma un token de acceso; verifiedAt, si no es nil, se guarda en el claim tfa
+func (m *AuthMiddleware) generate(userID string, tokenID string, verifiedAt *time.Time) (string, error) {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"sub": userID,
+		"jti": tokenID,
+		"iat": jwt.NewNumericDate(now),
+		"exp": jwt.NewNumericDate(now.Add(m.accessTTL)), // Token de corta duración; se renueva con el refresh token
+	}
+	if verifiedAt != nil {
+		claims["tfa"] = verifiedAt.Unix()
+	}
+	return m.sign(claims)
 }
@@ -16,6 +16,7 @@ package intefaces
 
 import (
 	contracts "Financial/Core/ports"
+	"Financial/Core/signing"
 	// "Financial/Domains/ports"
 	// "Financial/intefaces/controllers"
 	// "Financial/intefaces/controllers"
@@ -30,19 +31,57 @@ import (
 )
 
 type Server struct {
-	router         *gin.Engine
-	userUseCase    contracts.UserUseCase
-	walletUseCase  contracts.WalletUseCase
-	apiControllers []controllers.Controller
-	authMiddleware *middleware.AuthMiddleware
+	router               *gin.Engine
+	userUseCase          contracts.UserUseCase
+	walletUseCase        contracts.WalletUseCase
+	transactionUseCase   contracts.TransactionUseCase
+	transferUseCase      contracts.TransferUseCase
+	categoryUseCase      contracts.CategoryUseCase
+	budgetUseCase        contracts.BudgetUseCase
+	recurringUseCase     contracts.RecurringTransactionUseCase
+	importUseCase        contracts.ImportUseCase
+	exportUseCase        contracts.ExportUseCase
+	sessionUseCase       contracts.SessionUseCase
+	verificationUseCase  contracts.VerificationUseCase
+	passwordResetUseCase contracts.PasswordResetUseCase
+	twoFactorUseCase     contracts.TwoFactorUseCase
+	adminUseCase         contracts.AdminUseCase
+	apiKeyUseCase        contracts.APIKeyUseCase
+	externalLoginUseCase contracts.ExternalLoginUseCase
+	loginThrottle        contracts.LoginThrottle
+	apiControllers       []controllers.Controller
+	authMiddleware       *middleware.AuthMiddleware
 }
 
-func NewServer(userUseCase contracts.UserUseCase, walletUseCase contracts.WalletUseCase) *Server {
+func NewServer(userUseCase contracts.UserUseCase, walletUseCase contracts.WalletUseCase, transactionUseCase contracts.TransactionUseCase, transferUseCase contracts.TransferUseCase, categoryUseCase contracts.CategoryUseCase, budgetUseCase contracts.BudgetUseCase, recurringUseCase contracts.RecurringTransactionUseCase, importUseCase contracts.ImportUseCase, exportUseCase contracts.ExportUseCase, sessionUseCase contracts.SessionUseCase, verificationUseCase contracts.VerificationUseCase, passwordResetUseCase contracts.PasswordResetUseCase, twoFactorUseCase contracts.TwoFactorUseCase, adminUseCase contracts.AdminUseCase, apiKeyUseCase contracts.APIKeyUseCase, externalLoginUseCase contracts.ExternalLoginUseCase, loginThrottle contracts.LoginThrottle, signingKeys *signing.KeyRing) *Server {
 	server := &Server{
-		userUseCase:    userUseCase,
-		walletUseCase:  walletUseCase,
-		authMiddleware: middleware.NewAuthMiddleware(),
+		userUseCase:          userUseCase,
+		walletUseCase:        walletUseCase,
+		transactionUseCase:   transactionUseCase,
+		transferUseCase:      transferUseCase,
+		categoryUseCase:      categoryUseCase,
+		budgetUseCase:        budgetUseCase,
+		recurringUseCase:     recurringUseCase,
+		importUseCase:        importUseCase,
+		exportUseCase:        exportUseCase,
+		sessionUseCase:       sessionUseCase,
+		verificationUseCase:  verificationUseCase,
+		passwordResetUseCase: passwordResetUseCase,
+		twoFactorUseCase:     twoFactorUseCase,
+		adminUseCase:         adminUseCase,
+		apiKeyUseCase:        apiKeyUseCase,
+		externalLoginUseCase: externalLoginUseCase,
+		loginThrottle:        loginThrottle,
+		authMiddleware:       middleware.NewAuthMiddleware(signingKeys),
 	}
+	// Los tokens de sesiones cerradas o renovadas dejan de ser válidos
+	server.authMiddleware.UseSessions(sessionUseCase)
+	// Las rutas sensibles piden una verificación en dos pasos reciente a quien la tenga activada
+	server.authMiddleware.UseTwoFactor(twoFactorUseCase)
+	// Los permisos de cada ruta se comprueban contra el rol actual del usuario
+	server.authMiddleware.UseAuthorizer(adminUseCase)
+	// Las API keys (cabecera X-API-Key) solo llegan a las rutas con un scope que tengan
+	server.authMiddleware.UseAPIKeys(apiKeyUseCase)
 	server.setupControllers()
 	server.setupRouter()
 	return server
@@ -51,9 +90,18 @@ func NewServer(userUseCase contracts.UserUseCase, walletUseCase contracts.Wallet
 func (s *Server) setupControllers() {
 	// Register all controllers here
 	s.apiControllers = []controllers.Controller{
-		controllers.NewAccountController(s.userUseCase, s.authMiddleware),
+		controllers.NewAccountController(s.userUseCase, s.verificationUseCase, s.authMiddleware),
 		controllers.NewWalletController(s.walletUseCase, s.authMiddleware),
-		controllers.NewAuthController(s.userUseCase, s.authMiddleware),
+		controllers.NewAuthController(s.userUseCase, s.sessionUseCase, s.passwordResetUseCase, s.twoFactorUseCase, s.externalLoginUseCase, s.loginThrottle, s.authMiddleware),
+		controllers.NewTransactionController(s.transactionUseCase, s.authMiddleware),
+		controllers.NewTransferController(s.transferUseCase, s.authMiddleware),
+		controllers.NewCategoryController(s.categoryUseCase, s.authMiddleware),
+		controllers.NewBudgetController(s.budgetUseCase, s.authMiddleware),
+		controllers.NewRecurringController(s.recurringUseCase, s.authMiddleware),
+		controllers.NewImportController(s.importUseCase, s.authMiddleware),
+		controllers.NewExportController(s.exportUseCase, s.authMiddleware),
+		controllers.NewAdminController(s.adminUseCase, s.authMiddleware),
+		controllers.NewAPIKeyController(s.apiKeyUseCase, s.authMiddleware),
 		// Add more controllers here as needed
 	}
 }
@@ -66,11 +114,14 @@ func (s *Server) setupRouter() {
 	url := ginSwagger.URL("/swagger/doc.json") // La URL para el archivo JSON generado
 	s.router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler, url))
 
+	// Claves públicas de los tokens; fuera de /api porque no requiere autenticación
+	s.router.GET("/.well-known/jwks.json", s.authMiddleware.JWKS())
+
 	// Configuración CORS
 	s.router.Use(func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
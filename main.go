@@ -15,12 +15,20 @@
 package main
 
 import (
+	"Financial/Core/oidc"
 	"Financial/Core/ports"
+	"Financial/Core/signing"
 	"Financial/intefaces"
 	"Financial/persistence"
+	"context"
+	"crypto/rand"
+	"errors"
 	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	UserCases "Financial/Core/UseCases"
 
@@ -42,6 +50,11 @@ func PassPrerequirements() bool {
 		return false
 	}
 
+	// Solo los motores que pasan por Supabase necesitan sus credenciales; DB_DRIVER=sqlite y memory trabajan sin ellas
+	if !persistence.NeedsSupabase(persistence.Driver()) {
+		return true
+	}
+
 	supabaseURL = os.Getenv("SUPABASE_URL")
 	supabaseKey = os.Getenv("SUPABASE_KEY")
 
@@ -52,24 +65,242 @@ func PassPrerequirements() bool {
 	return true
 }
 
+// schedulerInterval lee RECURRING_INTERVAL (p. ej. "30s", "5m"); por defecto un minuto
+func schedulerInterval() time.Duration {
+	interval, err := time.ParseDuration(os.Getenv("RECURRING_INTERVAL"))
+	if err != nil || interval <= 0 {
+		return time.Minute
+	}
+	return interval
+}
+
+// refreshTokenTTL lee REFRESH_TOKEN_TTL (p. ej. "168h"); por defecto 30 días
+func refreshTokenTTL() time.Duration {
+	ttl, err := time.ParseDuration(os.Getenv("REFRESH_TOKEN_TTL"))
+	if err != nil || ttl <= 0 {
+		return 30 * 24 * time.Hour
+	}
+	return ttl
+}
+
+// requireEmailVerification lee REQUIRE_EMAIL_VERIFICATION; por defecto las cuentas
+// sin verificar no pueden iniciar sesión ("false" lo desactiva, p. ej. en desarrollo)
+func requireEmailVerification() bool {
+	required, err := strconv.ParseBool(os.Getenv("REQUIRE_EMAIL_VERIFICATION"))
+	if err != nil {
+		return true
+	}
+	return required
+}
+
+// verificationTTL lee VERIFICATION_TTL (p. ej. "24h"); por defecto 48 horas
+func verificationTTL() time.Duration {
+	ttl, err := time.ParseDuration(os.Getenv("VERIFICATION_TTL"))
+	if err != nil || ttl <= 0 {
+		return 48 * time.Hour
+	}
+	return ttl
+}
+
+// verificationSecret lee VERIFICATION_SECRET, con la que se firman los enlaces de verificación.
+// Sin ella, en producción es un error; en desarrollo se genera una clave aleatoria y los enlaces
+// enviados dejan de valer al reiniciar
+func verificationSecret() ([]byte, error) {
+	if secret := os.Getenv("VERIFICATION_SECRET"); secret != "" {
+		return []byte(secret), nil
+	}
+	if production() {
+		return nil, errors.New("VERIFICATION_SECRET no está definida")
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	fmt.Println("Aviso: VERIFICATION_SECRET no está definida; los enlaces de verificación no sobreviven a un reinicio")
+	return secret, nil
+}
+
+// passwordResetTTL lee PASSWORD_RESET_TTL (p. ej. "30m"); por defecto una hora
+func passwordResetTTL() time.Duration {
+	ttl, err := time.ParseDuration(os.Getenv("PASSWORD_RESET_TTL"))
+	if err != nil || ttl <= 0 {
+		return time.Hour
+	}
+	return ttl
+}
+
+// Los correos de recuperación pendientes se limitan a passwordResetQueueSize, enviados por
+// passwordResetWorkers workers; si la cola está llena la solicitud se descarta
+const (
+	passwordResetQueueSize = 100
+	passwordResetWorkers   = 2
+)
+
+// totpIssuer lee TOTP_ISSUER, el nombre que muestran las apps de autenticación; por defecto "MyFinance"
+func totpIssuer() string {
+	if issuer := os.Getenv("TOTP_ISSUER"); issuer != "" {
+		return issuer
+	}
+	return "MyFinance"
+}
+
+// externalLogin prepara el login con un proveedor OpenID Connect cuando OIDC_ISSUER_URL está
+// definida (con OIDC_CLIENT_ID y OIDC_CLIENT_SECRET). OIDC_REDIRECT_URL es por defecto
+// APP_BASE_URL + /api/auth/oidc/callback; OIDC_AUTO_PROVISION=true crea las cuentas que no existan.
+// Sin OIDC_ISSUER_URL devuelve nil y solo queda el login con contraseña
+func externalLogin(dbBoostrap *persistence.DbBoostrap, categories ports.CategoryUseCase, appURL string) (ports.ExternalLoginUseCase, error) {
+	issuer := os.Getenv("OIDC_ISSUER_URL")
+	if issuer == "" {
+		return nil, nil
+	}
+
+	redirectURL := os.Getenv("OIDC_REDIRECT_URL")
+	if redirectURL == "" {
+		redirectURL = strings.TrimSuffix(appURL, "/") + "/api/auth/oidc/callback"
+	}
+	var scopes []string
+	if value := os.Getenv("OIDC_SCOPES"); value != "" {
+		scopes = strings.Fields(value)
+	}
+
+	provider, err := oidc.Discover(oidc.Config{
+		Issuer:       issuer,
+		ClientID:     os.Getenv("OIDC_CLIENT_ID"),
+		ClientSecret: os.Getenv("OIDC_CLIENT_SECRET"),
+		RedirectURL:  redirectURL,
+		Scopes:       scopes,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	autoProvision := strings.EqualFold(os.Getenv("OIDC_AUTO_PROVISION"), "true")
+	return UserCases.NewExternalLoginUseCase(provider, dbBoostrap.UserIdentityRepository, dbBoostrap.OIDCLoginRepository, dbBoostrap.AccountRepository, categories, dbBoostrap.PasswordHasher, UserCases.SystemClock{}, autoProvision), nil
+}
+
+// loginThrottleConfig parte de DefaultLoginThrottleConfig; LOGIN_LOCKOUT_THRESHOLD (fallos que
+// suspenden la cuenta, por defecto 10) y LOGIN_LOCKOUT_DURATION (por defecto "30m") la ajustan
+func loginThrottleConfig() UserCases.LoginThrottleConfig {
+	config := UserCases.DefaultLoginThrottleConfig
+	if threshold, err := strconv.Atoi(os.Getenv("LOGIN_LOCKOUT_THRESHOLD")); err == nil && threshold > 0 {
+		config.LockoutThreshold = threshold
+	}
+	if duration, err := time.ParseDuration(os.Getenv("LOGIN_LOCKOUT_DURATION")); err == nil && duration > 0 {
+		config.LockoutDuration = duration
+	}
+	return config
+}
+
+// production indica si APP_ENV=production; en producción no se admiten claves generadas al vuelo
+func production() bool {
+	return strings.EqualFold(os.Getenv("APP_ENV"), "production")
+}
+
+// signingKeys carga las claves privadas (RSA o Ed25519, PEM) con las que se firman los tokens
+// desde JWT_SIGNING_KEYS_DIR; firma la del último fichero por nombre y las demás solo validan.
+// Sin directorio, en producción es un error; en desarrollo se genera una clave efímera
+func signingKeys() (*signing.KeyRing, error) {
+	if dir := os.Getenv("JWT_SIGNING_KEYS_DIR"); dir != "" {
+		return signing.LoadDir(dir)
+	}
+	if production() {
+		return nil, errors.New("JWT_SIGNING_KEYS_DIR no está definida")
+	}
+
+	key, err := signing.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+	fmt.Println("Aviso: JWT_SIGNING_KEYS_DIR no está definida; los tokens emitidos no sobreviven a un reinicio")
+	return signing.NewKeyRing(key)
+}
+
+// signingKeysReload lee JWT_SIGNING_KEYS_RELOAD (p. ej. "30s"); por defecto un minuto
+func signingKeysReload() time.Duration {
+	interval, err := time.ParseDuration(os.Getenv("JWT_SIGNING_KEYS_RELOAD"))
+	if err != nil || interval <= 0 {
+		return time.Minute
+	}
+	return interval
+}
+
 func main() {
 	passRequirements := PassPrerequirements()
 	if !passRequirements {
 		os.Exit(1)
 	}
 
+	// Sin claves de firma no se arranca: nunca se firma con un secreto por defecto
+	keys, err := signingKeys()
+	if err != nil {
+		fmt.Printf("Error al cargar las claves de firma de los tokens: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Los enlaces de verificación tampoco se firman con una clave por defecto
+	verificationKey, err := verificationSecret()
+	if err != nil {
+		fmt.Printf("Error al cargar la clave de verificación: %v\n", err)
+		os.Exit(1)
+	}
+
 	dbBoostrap, err := persistence.Init()
 
 	if err != nil {
 		fmt.Printf("Error al configurar la aplicación: %v\n", err)
 		os.Exit(1)
 	}
+	defer dbBoostrap.Close()
+
+	categoryUseCase := UserCases.NewCategoryUseCase(dbBoostrap.CategoryRepository, dbBoostrap.TransactionRepository)
+	accountUseCase := UserCases.NewAccountUseCase(dbBoostrap.AccountRepository, dbBoostrap.UnitOfWork, dbBoostrap.PasswordHasher, requireEmailVerification())
+	walletUseCase := UserCases.NewWalletUseCase(dbBoostrap.WalletRepository, dbBoostrap.UnitOfWork, dbBoostrap.ExchangeRateProvider)
+	transactionUseCase := UserCases.NewTransactionUseCase(dbBoostrap.TransactionRepository, dbBoostrap.WalletRepository, dbBoostrap.CategoryRepository, dbBoostrap.UnitOfWork)
+	transferUseCase := UserCases.NewTransferUseCase(dbBoostrap.TransferRepository, dbBoostrap.WalletRepository)
+	budgetUseCase := UserCases.NewBudgetUseCase(dbBoostrap.BudgetRepository, dbBoostrap.CategoryRepository, dbBoostrap.WalletRepository, dbBoostrap.TransactionRepository)
+
+	recurringUseCase := UserCases.NewRecurringTransactionUseCase(dbBoostrap.RecurringRepository, dbBoostrap.WalletRepository, dbBoostrap.CategoryRepository, UserCases.SystemClock{})
+	importUseCase := UserCases.NewImportUseCase(dbBoostrap.TransactionRepository, dbBoostrap.WalletRepository, transactionUseCase)
+	exportUseCase := UserCases.NewExportUseCase(dbBoostrap.WalletRepository, dbBoostrap.TransactionRepository)
+	appURL := os.Getenv("APP_BASE_URL")
+	if appURL == "" {
+		appURL = "http://localhost:8080"
+	}
+	verificationUseCase := UserCases.NewVerificationUseCase(dbBoostrap.AccountRepository, dbBoostrap.Mailer, UserCases.SystemClock{}, verificationKey, strings.TrimSuffix(appURL, "/")+"/api/account/verify", verificationTTL())
+	sessionUseCase := UserCases.NewSessionUseCase(dbBoostrap.SessionRepository, dbBoostrap.AccountRepository, UserCases.SystemClock{}, refreshTokenTTL())
+	resetURL := os.Getenv("PASSWORD_RESET_URL")
+	if resetURL == "" {
+		resetURL = strings.TrimSuffix(appURL, "/") + "/reset-password"
+	}
+	passwordResetUseCase := UserCases.NewPasswordResetQueue(UserCases.NewPasswordResetUseCase(dbBoostrap.PasswordResetRepository, dbBoostrap.AccountRepository, dbBoostrap.PasswordHasher, sessionUseCase, dbBoostrap.Mailer, UserCases.SystemClock{}, resetURL, passwordResetTTL()), passwordResetQueueSize)
+	twoFactorUseCase := UserCases.NewTwoFactorUseCase(dbBoostrap.TwoFactorRepository, dbBoostrap.AccountRepository, UserCases.SystemClock{}, totpIssuer())
+	loginThrottle := UserCases.NewLoginThrottleUseCase(dbBoostrap.LoginAttemptStore, dbBoostrap.AccountRepository, dbBoostrap.AuditRepository, UserCases.SystemClock{}, loginThrottleConfig())
+	adminUseCase := UserCases.NewAdminUseCase(dbBoostrap.AccountRepository, walletUseCase, sessionUseCase, loginThrottle)
+	apiKeyUseCase := UserCases.NewAPIKeyUseCase(dbBoostrap.APIKeyRepository, dbBoostrap.AccountRepository, UserCases.SystemClock{})
+	externalLoginUseCase, err := externalLogin(dbBoostrap, categoryUseCase, appURL)
+	if err != nil {
+		fmt.Printf("Error al configurar el proveedor OpenID Connect: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Los movimientos recurrentes se registran en segundo plano mientras el servidor esté activo
+	scheduler := UserCases.NewRecurringScheduler(dbBoostrap.RecurringRepository, dbBoostrap.TransactionRepository, transactionUseCase, UserCases.SystemClock{})
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	defer stopScheduler()
+	go scheduler.Start(schedulerCtx, schedulerInterval())
 
-	accountUseCase := UserCases.NewAccountUseCase(dbBoostrap.AccountRepository)
-	walletUseCase := UserCases.NewWalletUseCase(dbBoostrap.WalletRepository)
+	// Los correos de recuperación de contraseña los envían unos pocos workers en segundo plano
+	go passwordResetUseCase.Start(schedulerCtx, passwordResetWorkers)
+
+	// Una clave nueva en JWT_SIGNING_KEYS_DIR empieza a firmar sin reiniciar; las retiradas
+	// siguen validando hasta que caducan los tokens que firmaron
+	if dir := os.Getenv("JWT_SIGNING_KEYS_DIR"); dir != "" {
+		go keys.WatchDir(schedulerCtx, dir, signingKeysReload())
+	}
 
 	// Crear e iniciar el servidor web
-	server := intefaces.NewServer(accountUseCase, walletUseCase)
+	server := intefaces.NewServer(accountUseCase, walletUseCase, transactionUseCase, transferUseCase, categoryUseCase, budgetUseCase, recurringUseCase, importUseCase, exportUseCase, sessionUseCase, verificationUseCase, passwordResetUseCase, twoFactorUseCase, adminUseCase, apiKeyUseCase, externalLoginUseCase, loginThrottle, keys)
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080"
@@ -1,22 +1,116 @@
 package persistence
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"Financial/Core/Models/db"
 	port "Financial/Core/ports"
 	"Financial/persistence/infrastructure"
 
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/supabase-community/supabase-go"
 )
 
 type DbBoostrap struct {
-	AccountRepository port.Repository[db.User, int]
-	WalletRepository  port.Repository[db.Wallet, int]
+	AccountRepository       port.Repository[db.User, int]
+	WalletRepository        port.Repository[db.Wallet, int]
+	TransactionRepository   port.Repository[db.Transaction, int]
+	TransferRepository      port.TransferRepository
+	ExchangeRateProvider    port.ExchangeRateProvider
+	CategoryRepository      port.Repository[db.Category, int]
+	BudgetRepository        port.Repository[db.Budget, int]
+	RecurringRepository     port.Repository[db.RecurringTransaction, int]
+	PasswordHasher          port.PasswordHasher
+	SessionRepository       port.SessionRepository
+	Mailer                  port.Mailer
+	PasswordResetRepository port.Repository[db.PasswordReset, int]
+	TwoFactorRepository     port.Repository[db.TwoFactor, int]
+	APIKeyRepository        port.Repository[db.APIKey, int]
+	UserIdentityRepository  port.Repository[db.UserIdentity, int]
+	OIDCLoginRepository     port.Repository[db.OIDCLogin, int]
+	LoginAttemptStore       port.LoginAttemptStore
+	AuditRepository         port.Repository[db.AuditEntry, int]
+	UnitOfWork              port.UnitOfWork
+
+	pool   *pgxpool.Pool
+	sqlite *sql.DB
+}
+
+// Valores de DB_DRIVER
+const (
+	DriverSupabase = "supabase"
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+	DriverMemory   = "memory"
+)
+
+// Driver devuelve el motor elegido en DB_DRIVER; sin valor se usa Supabase. DB_DRIVER=postgres es
+// un modo mixto: las tablas que escribe la unidad de trabajo (usuarios, billeteras, movimientos y
+// categorías) van por pgx a DATABASE_URL y las demás siguen yendo por Supabase, así que también
+// necesita SUPABASE_URL y SUPABASE_KEY, y ambos deben apuntar a la misma base de datos
+func Driver() string {
+	if driver := os.Getenv("DB_DRIVER"); driver != "" {
+		return driver
+	}
+	return DriverSupabase
+}
+
+// NeedsSupabase indica si el motor usa el cliente de Supabase (SUPABASE_URL y SUPABASE_KEY);
+// DB_DRIVER=postgres también lo usa para las tablas que no van por pgx
+func NeedsSupabase(driver string) bool {
+	return driver == DriverSupabase || driver == DriverPostgres
 }
 
 func Init() (*DbBoostrap, error) {
+	var boostrap *DbBoostrap
+	var err error
+	switch driver := Driver(); driver {
+	case DriverSupabase, DriverPostgres:
+		boostrap, err = initSupabase(driver)
+	case DriverSQLite:
+		boostrap, err = initSQLite()
+	case DriverMemory:
+		boostrap = initMemory()
+	default:
+		return nil, fmt.Errorf("DB_DRIVER desconocido: %q", driver)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Las tasas de cambio se leen de un archivo cuando EXCHANGE_RATES_FILE está definido
+	if ratesFile := os.Getenv("EXCHANGE_RATES_FILE"); ratesFile != "" {
+		boostrap.ExchangeRateProvider, err = infrastructure.NewFileExchangeRateProvider(ratesFile)
+		if err != nil {
+			boostrap.Close()
+			return nil, err
+		}
+	}
+
+	boostrap.Mailer, err = newMailer()
+	if err != nil {
+		boostrap.Close()
+		return nil, err
+	}
+
+	// Los intentos fallidos se cuentan en la base de datos para que todas las instancias los compartan;
+	// LOGIN_ATTEMPTS_STORE=memory los guarda en memoria (una sola instancia)
+	if os.Getenv("LOGIN_ATTEMPTS_STORE") == "memory" {
+		boostrap.LoginAttemptStore = infrastructure.NewMemoryLoginAttemptStore()
+	}
+
+	boostrap.PasswordHasher = infrastructure.NewArgon2PasswordHasher(infrastructure.DefaultArgon2Params)
+	return boostrap, nil
+}
+
+// initSupabase guarda todo a través de Supabase. DB_DRIVER=postgres guarda usuarios, billeteras,
+// movimientos y categorías con una conexión directa a DATABASE_URL, lo que permite consultas que
+// PostgREST no expresa; son las tablas de la unidad de trabajo, que así lee y escribe un solo backend
+func initSupabase(driver string) (*DbBoostrap, error) {
 	supabaseURL := os.Getenv("SUPABASE_URL")
 	supabaseKey := os.Getenv("SUPABASE_KEY")
 
@@ -25,8 +119,145 @@ func Init() (*DbBoostrap, error) {
 		return nil, fmt.Errorf("error inicializando cliente de Supabase: %w", err)
 	}
 
+	users := infrastructure.NewSupaBaseUserRepository(client)
+	wallets := infrastructure.NewSupaBaseWalletRepository(client)
+	transactions := infrastructure.NewSupaBaseTransactionRepository(client)
+	categories := infrastructure.NewSupaBaseCategoryRepository(client)
+	// Con Supabase las escrituras de varios pasos se aplican juntas con la función apply_unit_of_work;
+	// con una conexión directa se hacen dentro de una transacción de la base de datos
+	unitOfWork := infrastructure.NewSupaBaseUnitOfWork(client)
+	var pool *pgxpool.Pool
+	if driver == DriverPostgres {
+		databaseURL := os.Getenv("DATABASE_URL")
+		if databaseURL == "" {
+			return nil, fmt.Errorf("DATABASE_URL es obligatorio cuando DB_DRIVER=postgres")
+		}
+		pool, err = infrastructure.NewPostgresPool(context.Background(), databaseURL)
+		if err != nil {
+			return nil, err
+		}
+		users = infrastructure.NewPostgresUserRepository(pool)
+		wallets = infrastructure.NewPostgresWalletRepository(pool)
+		transactions = infrastructure.NewPostgresTransactionRepository(pool)
+		categories = infrastructure.NewPostgresCategoryRepository(pool)
+		unitOfWork = infrastructure.NewPostgresUnitOfWork(pool)
+	}
+
 	return &DbBoostrap{
-		AccountRepository: infrastructure.NewSupaBaseUserRepository(client),
-		WalletRepository:  infrastructure.NewSupaBaseWalletRepository(client),
+		AccountRepository:       users,
+		WalletRepository:        wallets,
+		TransactionRepository:   transactions,
+		TransferRepository:      infrastructure.NewSupaBaseTransferRepository(client),
+		ExchangeRateProvider:    infrastructure.NewSupaBaseExchangeRateProvider(client),
+		CategoryRepository:      categories,
+		BudgetRepository:        infrastructure.NewSupaBaseBudgetRepository(client),
+		RecurringRepository:     infrastructure.NewSupaBaseRecurringTransactionRepository(client),
+		SessionRepository:       infrastructure.NewSupaBaseSessionRepository(client),
+		PasswordResetRepository: infrastructure.NewSupaBasePasswordResetRepository(client),
+		TwoFactorRepository:     infrastructure.NewSupaBaseTwoFactorRepository(client),
+		APIKeyRepository:        infrastructure.NewSupaBaseAPIKeyRepository(client),
+		UserIdentityRepository:  infrastructure.NewSupaBaseUserIdentityRepository(client),
+		OIDCLoginRepository:     infrastructure.NewSupaBaseOIDCLoginRepository(client),
+		LoginAttemptStore:       infrastructure.NewSupaBaseLoginAttemptStore(client),
+		AuditRepository:         infrastructure.NewSupaBaseAuditRepository(client),
+		UnitOfWork:              unitOfWork,
+		pool:                    pool,
 	}, nil
 }
+
+// initSQLite guarda todo en un archivo SQLite local (SQLITE_PATH, por defecto financial.db) para
+// trabajar sin conexión o con un solo usuario; el esquema se crea al abrirlo
+func initSQLite() (*DbBoostrap, error) {
+	path := os.Getenv("SQLITE_PATH")
+	if path == "" {
+		path = "financial.db"
+	}
+	database, err := infrastructure.OpenSQLite(path)
+	if err != nil {
+		return nil, fmt.Errorf("error abriendo la base de datos SQLite %s: %w", path, err)
+	}
+
+	return &DbBoostrap{
+		AccountRepository:       infrastructure.NewSQLiteUserRepository(database),
+		WalletRepository:        infrastructure.NewSQLiteWalletRepository(database),
+		TransactionRepository:   infrastructure.NewSQLiteTransactionRepository(database),
+		TransferRepository:      infrastructure.NewSQLiteTransferRepository(database),
+		ExchangeRateProvider:    infrastructure.NewSQLiteExchangeRateProvider(database),
+		CategoryRepository:      infrastructure.NewSQLiteCategoryRepository(database),
+		BudgetRepository:        infrastructure.NewSQLiteBudgetRepository(database),
+		RecurringRepository:     infrastructure.NewSQLiteRecurringTransactionRepository(database),
+		SessionRepository:       infrastructure.NewSQLiteSessionRepository(database),
+		PasswordResetRepository: infrastructure.NewSQLitePasswordResetRepository(database),
+		TwoFactorRepository:     infrastructure.NewSQLiteTwoFactorRepository(database),
+		APIKeyRepository:        infrastructure.NewSQLiteAPIKeyRepository(database),
+		UserIdentityRepository:  infrastructure.NewSQLiteUserIdentityRepository(database),
+		OIDCLoginRepository:     infrastructure.NewSQLiteOIDCLoginRepository(database),
+		LoginAttemptStore:       infrastructure.NewSQLiteLoginAttemptStore(database),
+		AuditRepository:         infrastructure.NewSQLiteAuditRepository(database),
+		UnitOfWork:              infrastructure.NewSQLiteUnitOfWork(database),
+		sqlite:                  database,
+	}, nil
+}
+
+// initMemory guarda todo en la memoria del proceso, con las mismas restricciones que el esquema SQL;
+// sirve para demos y pruebas de punta a punta sin base de datos, y los datos se pierden al detenerlo
+func initMemory() *DbBoostrap {
+	store := infrastructure.NewMemoryStore()
+	return &DbBoostrap{
+		AccountRepository:       infrastructure.NewMemoryUserRepository(store),
+		WalletRepository:        infrastructure.NewMemoryWalletRepository(store),
+		TransactionRepository:   infrastructure.NewMemoryTransactionRepository(store),
+		TransferRepository:      infrastructure.NewMemoryTransferRepository(store),
+		ExchangeRateProvider:    infrastructure.NewMemoryExchangeRateProvider(store),
+		CategoryRepository:      infrastructure.NewMemoryCategoryRepository(store),
+		BudgetRepository:        infrastructure.NewMemoryBudgetRepository(store),
+		RecurringRepository:     infrastructure.NewMemoryRecurringTransactionRepository(store),
+		SessionRepository:       infrastructure.NewMemorySessionRepository(store),
+		PasswordResetRepository: infrastructure.NewMemoryPasswordResetRepository(store),
+		TwoFactorRepository:     infrastructure.NewMemoryTwoFactorRepository(store),
+		APIKeyRepository:        infrastructure.NewMemoryAPIKeyRepository(store),
+		UserIdentityRepository:  infrastructure.NewMemoryUserIdentityRepository(store),
+		OIDCLoginRepository:     infrastructure.NewMemoryOIDCLoginRepository(store),
+		LoginAttemptStore:       infrastructure.NewMemoryLoginAttemptStore(),
+		AuditRepository:         infrastructure.NewMemoryAuditRepository(store),
+		UnitOfWork:              infrastructure.NewMemoryUnitOfWork(store),
+	}
+}
+
+// Close libera las conexiones abiertas por Init
+func (b *DbBoostrap) Close() {
+	if b.pool != nil {
+		b.pool.Close()
+	}
+	if b.sqlite != nil {
+		b.sqlite.Close()
+	}
+}
+
+// newMailer usa SMTP cuando SMTP_HOST está definido; si no, guarda los correos como
+// archivos .eml en MAIL_DIR (por defecto en el directorio temporal) para desarrollo local
+func newMailer() (port.Mailer, error) {
+	if host := os.Getenv("SMTP_HOST"); host != "" {
+		smtpPort := os.Getenv("SMTP_PORT")
+		if smtpPort == "" {
+			smtpPort = "587"
+		}
+		from := os.Getenv("MAIL_FROM")
+		if from == "" {
+			return nil, fmt.Errorf("MAIL_FROM es obligatorio cuando SMTP_HOST está definido")
+		}
+		return infrastructure.NewSMTPMailer(infrastructure.SMTPConfig{
+			Host:     host,
+			Port:     smtpPort,
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     from,
+		}), nil
+	}
+
+	dir := os.Getenv("MAIL_DIR")
+	if dir == "" {
+		dir = filepath.Join(os.TempDir(), "financial-mail")
+	}
+	return infrastructure.NewFileMailer(dir)
+}
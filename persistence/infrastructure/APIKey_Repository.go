@@ -0,0 +1,142 @@
+package infrastructure
+
+import (
+	"Financial/Core/Models/db"
+	"Financial/Core/ports"
+	"Financial/Core/types"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/supabase-community/supabase-go"
+)
+
+const apiKeyTable = "api_keys"
+
+type SupaBaseAPIKeyRepository struct {
+	client *supabase.Client
+}
+
+func NewSupaBaseAPIKeyRepository(client *supabase.Client) ports.Repository[db.APIKey, int] {
+	return &SupaBaseAPIKeyRepository{client: client}
+}
+
+// CreateAPIKey is a helper struct that matches the database schema
+type CreateAPIKey struct {
+	UserID     int        `json:"user_id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	KeyHash    string     `json:"key_hash"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+}
+
+// newCreateAPIKey maps the model to the columns, leaving out the ID
+func newCreateAPIKey(model *db.APIKey) CreateAPIKey {
+	scopes := model.Scopes
+	if scopes == nil {
+		// NULL would break the NOT NULL column
+		scopes = []string{}
+	}
+	return CreateAPIKey{
+		UserID:     model.UserID,
+		Name:       model.Name,
+		Prefix:     model.Prefix,
+		KeyHash:    model.KeyHash,
+		Scopes:     scopes,
+		CreatedAt:  model.CreatedAt,
+		ExpiresAt:  model.ExpiresAt,
+		LastUsedAt: model.LastUsedAt,
+		RevokedAt:  model.RevokedAt,
+	}
+}
+
+func (repo *SupaBaseAPIKeyRepository) Create(model *db.APIKey) (*db.APIKey, error) {
+	newKey := newCreateAPIKey(model)
+
+	var result db.APIKey
+	_, err := repo.client.From(apiKeyTable).
+		Insert(newKey, false, "", "representation", "").
+		Single().
+		ExecuteTo(&result)
+
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (repo *SupaBaseAPIKeyRepository) Delete(id int) error {
+	_, _, err := repo.client.From(apiKeyTable).Delete("", "").
+		Eq("id", strconv.Itoa(id)).Execute()
+	return err
+}
+
+func (repo *SupaBaseAPIKeyRepository) FindByField(field string, value any) (*db.APIKey, error) {
+	var results []db.APIKey
+
+	var filterValue string
+	switch v := value.(type) {
+	case string:
+		filterValue = v
+	case int, int32, int64, uint, uint32, uint64:
+		filterValue = fmt.Sprintf("%d", v)
+	case float32, float64:
+		filterValue = fmt.Sprintf("%f", v)
+	case bool:
+		filterValue = strconv.FormatBool(v)
+	default:
+		return nil, fmt.Errorf("unsupported type for field filtering: %T", value)
+	}
+
+	_, err := repo.client.From(apiKeyTable).
+		Select("*", "exact", false).
+		Filter(field, "eq", filterValue).
+		ExecuteTo(&results)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(results) == 0 {
+		return nil, types.ErrNotFound
+	}
+
+	return &results[0], nil
+}
+
+func (repo *SupaBaseAPIKeyRepository) GetAll() ([]db.APIKey, error) {
+	var keys []db.APIKey
+	_, err := repo.client.From(apiKeyTable).Select("*", "exact", false).
+		ExecuteTo(&keys)
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (repo *SupaBaseAPIKeyRepository) GetByID(id int) (*db.APIKey, error) {
+	return repo.FindByField("id", id)
+}
+
+func (repo *SupaBaseAPIKeyRepository) Update(model *db.APIKey) (*db.APIKey, error) {
+	var result []db.APIKey
+	_, err := repo.client.From(apiKeyTable).Update(newCreateAPIKey(model), "representation", "").Eq("id", strconv.Itoa(model.ID)).
+		ExecuteTo(&result)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, types.ErrNotFound
+	}
+	return &result[0], nil
+}
+
+// Query returns a page of the rows matching args; see ports.QueryOptions for the filters,
+// paging and counts it supports.
+func (repo *SupaBaseAPIKeyRepository) Query(fields string, args ports.QueryOptions) (ports.Page[db.APIKey], error) {
+	return supabaseQuery[db.APIKey](repo.client, apiKeyTable, fields, args)
+}
@@ -0,0 +1,127 @@
+package infrastructure
+
+import (
+	"Financial/Core/ports"
+	"Financial/Core/types"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Argon2Params are the cost parameters of argon2id
+type Argon2Params struct {
+	// Memory in KiB
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultArgon2Params follows the OWASP recommendation for argon2id (19 MiB, 2 iterations, 1 lane)
+var DefaultArgon2Params = Argon2Params{
+	Memory:      19 * 1024,
+	Iterations:  2,
+	Parallelism: 1,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+// Argon2PasswordHasher hashes with argon2id in the PHC string format
+// ($argon2id$v=19$m=...,t=...,p=...$salt$key). It also verifies bcrypt hashes
+// so they can be upgraded on the next login.
+type Argon2PasswordHasher struct {
+	params Argon2Params
+}
+
+func NewArgon2PasswordHasher(params Argon2Params) ports.PasswordHasher {
+	return &Argon2PasswordHasher{params: params}
+}
+
+// Hash implements PasswordHasher.Hash
+func (h *Argon2PasswordHasher) Hash(password string) (string, error) {
+	salt := make([]byte, h.params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("error generating salt: %w", err)
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.params.Iterations, h.params.Memory, h.params.Parallelism, h.params.KeyLength)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, h.params.Memory, h.params.Iterations, h.params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Verify implements PasswordHasher.Verify
+func (h *Argon2PasswordHasher) Verify(password string, encoded string) (bool, error) {
+	if isBcrypt(encoded) {
+		err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+		if err == bcrypt.ErrMismatchedHashAndPassword {
+			return false, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("%w: %v", types.ErrUnknownHash, err)
+		}
+		return true, nil
+	}
+
+	params, salt, key, err := decodeArgon2(encoded)
+	if err != nil {
+		return false, err
+	}
+	candidate := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, params.KeyLength)
+	return subtle.ConstantTimeCompare(key, candidate) == 1, nil
+}
+
+// NeedsRehash implements PasswordHasher.NeedsRehash
+func (h *Argon2PasswordHasher) NeedsRehash(encoded string) bool {
+	params, salt, _, err := decodeArgon2(encoded)
+	if err != nil {
+		return true
+	}
+	return params.Memory < h.params.Memory ||
+		params.Iterations < h.params.Iterations ||
+		params.Parallelism < h.params.Parallelism ||
+		params.KeyLength < h.params.KeyLength ||
+		uint32(len(salt)) < h.params.SaltLength
+}
+
+func isBcrypt(encoded string) bool {
+	return strings.HasPrefix(encoded, "$2a$") || strings.HasPrefix(encoded, "$2b$") || strings.HasPrefix(encoded, "$2y$")
+}
+
+// decodeArgon2 reads a PHC argon2id string
+func decodeArgon2(encoded string) (Argon2Params, []byte, []byte, error) {
+	var params Argon2Params
+
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[0] != "" || parts[1] != "argon2id" {
+		return params, nil, nil, types.ErrUnknownHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, fmt.Errorf("%w: unsupported argon2 version", types.ErrUnknownHash)
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return params, nil, nil, fmt.Errorf("%w: invalid argon2 parameters", types.ErrUnknownHash)
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, fmt.Errorf("%w: invalid salt", types.ErrUnknownHash)
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return params, nil, nil, fmt.Errorf("%w: invalid key", types.ErrUnknownHash)
+	}
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+	return params, salt, key, nil
+}
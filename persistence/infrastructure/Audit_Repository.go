@@ -0,0 +1,129 @@
+package infrastructure
+
+import (
+	"Financial/Core/Models/db"
+	"Financial/Core/ports"
+	"Financial/Core/types"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/supabase-community/supabase-go"
+)
+
+const auditTable = "audit_log"
+
+type SupaBaseAuditRepository struct {
+	client *supabase.Client
+}
+
+func NewSupaBaseAuditRepository(client *supabase.Client) ports.Repository[db.AuditEntry, int] {
+	return &SupaBaseAuditRepository{client: client}
+}
+
+// CreateAuditEntry is a helper struct that matches the database schema
+type CreateAuditEntry struct {
+	UserID    *int              `json:"user_id"`
+	Action    types.AuditAction `json:"action"`
+	Detail    string            `json:"detail"`
+	IP        string            `json:"ip"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// newCreateAuditEntry maps the model to the columns, leaving out the ID
+func newCreateAuditEntry(model *db.AuditEntry) CreateAuditEntry {
+	return CreateAuditEntry{
+		UserID:    model.UserID,
+		Action:    model.Action,
+		Detail:    model.Detail,
+		IP:        model.IP,
+		CreatedAt: model.CreatedAt,
+	}
+}
+
+func (repo *SupaBaseAuditRepository) Create(model *db.AuditEntry) (*db.AuditEntry, error) {
+	newEntry := newCreateAuditEntry(model)
+
+	var result db.AuditEntry
+	_, err := repo.client.From(auditTable).
+		Insert(newEntry, false, "", "representation", "").
+		Single().
+		ExecuteTo(&result)
+
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (repo *SupaBaseAuditRepository) Delete(id int) error {
+	_, _, err := repo.client.From(auditTable).Delete("", "").
+		Eq("id", strconv.Itoa(id)).Execute()
+	return err
+}
+
+func (repo *SupaBaseAuditRepository) FindByField(field string, value any) (*db.AuditEntry, error) {
+	var results []db.AuditEntry
+
+	var filterValue string
+	switch v := value.(type) {
+	case string:
+		filterValue = v
+	case int, int32, int64, uint, uint32, uint64:
+		filterValue = fmt.Sprintf("%d", v)
+	case float32, float64:
+		filterValue = fmt.Sprintf("%f", v)
+	case bool:
+		filterValue = strconv.FormatBool(v)
+	default:
+		return nil, fmt.Errorf("unsupported type for field filtering: %T", value)
+	}
+
+	_, err := repo.client.From(auditTable).
+		Select("*", "exact", false).
+		Filter(field, "eq", filterValue).
+		ExecuteTo(&results)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(results) == 0 {
+		return nil, types.ErrNotFound
+	}
+
+	return &results[0], nil
+}
+
+func (repo *SupaBaseAuditRepository) GetAll() ([]db.AuditEntry, error) {
+	var entries []db.AuditEntry
+	_, err := repo.client.From(auditTable).Select("*", "exact", false).
+		ExecuteTo(&entries)
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (repo *SupaBaseAuditRepository) GetByID(id int) (*db.AuditEntry, error) {
+	return repo.FindByField("id", id)
+}
+
+func (repo *SupaBaseAuditRepository) Update(model *db.AuditEntry) (*db.AuditEntry, error) {
+	var result []db.AuditEntry
+	_, err := repo.client.From(auditTable).Update(newCreateAuditEntry(model), "representation", "").Eq("id", strconv.Itoa(model.ID)).
+		ExecuteTo(&result)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, types.ErrNotFound
+	}
+	return &result[0], nil
+}
+
+// Query returns a page of the rows matching args; see ports.QueryOptions for the filters,
+// paging and counts it supports.
+func (repo *SupaBaseAuditRepository) Query(fields string, args ports.QueryOptions) (ports.Page[db.AuditEntry], error) {
+	return supabaseQuery[db.AuditEntry](repo.client, auditTable, fields, args)
+}
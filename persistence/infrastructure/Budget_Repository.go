@@ -0,0 +1,129 @@
+package infrastructure
+
+import (
+	"Financial/Core/Models/db"
+	"Financial/Core/ports"
+	"Financial/Core/types"
+	"fmt"
+	"strconv"
+
+	"github.com/supabase-community/supabase-go"
+)
+
+const budgetTable = "budgets"
+
+type SupaBaseBudgetRepository struct {
+	client *supabase.Client
+}
+
+func NewSupaBaseBudgetRepository(client *supabase.Client) ports.Repository[db.Budget, int] {
+	return &SupaBaseBudgetRepository{client: client}
+}
+
+// CreateBudget is a helper struct that matches the database schema
+type CreateBudget struct {
+	UserID     int         `json:"user_id"`
+	CategoryID int         `json:"category_id"`
+	Period     string      `json:"period"`
+	Limit      types.Money `json:"limit_amount"`
+	Currency   string      `json:"currency"`
+}
+
+func (repo *SupaBaseBudgetRepository) Create(model *db.Budget) (*db.Budget, error) {
+	newBudget := CreateBudget{
+		UserID:     model.UserID,
+		CategoryID: model.CategoryID,
+		Period:     model.Period,
+		Limit:      model.Limit,
+		Currency:   model.Currency,
+	}
+
+	var result db.Budget
+	_, err := repo.client.From(budgetTable).
+		Insert(newBudget, false, "", "representation", "").
+		Single().
+		ExecuteTo(&result)
+
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (repo *SupaBaseBudgetRepository) Delete(id int) error {
+	_, _, err := repo.client.From(budgetTable).Delete("", "").
+		Eq("id", strconv.Itoa(id)).Execute()
+	return err
+}
+
+func (repo *SupaBaseBudgetRepository) FindByField(field string, value any) (*db.Budget, error) {
+	var results []db.Budget
+
+	var filterValue string
+	switch v := value.(type) {
+	case string:
+		filterValue = v
+	case int, int32, int64, uint, uint32, uint64:
+		filterValue = fmt.Sprintf("%d", v)
+	case float32, float64:
+		filterValue = fmt.Sprintf("%f", v)
+	case bool:
+		filterValue = strconv.FormatBool(v)
+	default:
+		return nil, fmt.Errorf("unsupported type for field filtering: %T", value)
+	}
+
+	_, err := repo.client.From(budgetTable).
+		Select("*", "exact", false).
+		Filter(field, "eq", filterValue).
+		ExecuteTo(&results)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(results) == 0 {
+		return nil, types.ErrNotFound
+	}
+
+	return &results[0], nil
+}
+
+func (repo *SupaBaseBudgetRepository) GetAll() ([]db.Budget, error) {
+	var budgets []db.Budget
+	_, err := repo.client.From(budgetTable).Select("*", "exact", false).
+		ExecuteTo(&budgets)
+	if err != nil {
+		return nil, err
+	}
+	return budgets, nil
+}
+
+func (repo *SupaBaseBudgetRepository) GetByID(id int) (*db.Budget, error) {
+	return repo.FindByField("id", id)
+}
+
+func (repo *SupaBaseBudgetRepository) Update(model *db.Budget) (*db.Budget, error) {
+	var result []db.Budget
+	_, err := repo.client.From(budgetTable).Update(CreateBudget{
+		UserID:     model.UserID,
+		CategoryID: model.CategoryID,
+		Period:     model.Period,
+		Limit:      model.Limit,
+		Currency:   model.Currency,
+	}, "representation", "").Eq("id", strconv.Itoa(model.ID)).
+		ExecuteTo(&result)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, types.ErrNotFound
+	}
+	return &result[0], nil
+}
+
+// Query returns a page of the rows matching args; see ports.QueryOptions for the filters,
+// paging and counts it supports.
+func (repo *SupaBaseBudgetRepository) Query(fields string, args ports.QueryOptions) (ports.Page[db.Budget], error) {
+	return supabaseQuery[db.Budget](repo.client, budgetTable, fields, args)
+}
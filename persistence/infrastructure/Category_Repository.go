@@ -0,0 +1,126 @@
+package infrastructure
+
+import (
+	"Financial/Core/Models/db"
+	"Financial/Core/ports"
+	"Financial/Core/types"
+	"fmt"
+	"strconv"
+
+	"github.com/supabase-community/supabase-go"
+)
+
+const categoryTable = "categories"
+
+type SupaBaseCategoryRepository struct {
+	client *supabase.Client
+}
+
+func NewSupaBaseCategoryRepository(client *supabase.Client) ports.Repository[db.Category, int] {
+	return &SupaBaseCategoryRepository{client: client}
+}
+
+// CreateCategory is a helper struct that matches the database schema
+type CreateCategory struct {
+	UserID   int                   `json:"user_id"`
+	ParentID *int                  `json:"parent_id"`
+	Name     string                `json:"name"`
+	Type     types.TransactionType `json:"type"`
+}
+
+func (repo *SupaBaseCategoryRepository) Create(model *db.Category) (*db.Category, error) {
+	newCategory := CreateCategory{
+		UserID:   model.UserID,
+		ParentID: model.ParentID,
+		Name:     model.Name,
+		Type:     model.Type,
+	}
+
+	var result db.Category
+	_, err := repo.client.From(categoryTable).
+		Insert(newCategory, false, "", "representation", "").
+		Single().
+		ExecuteTo(&result)
+
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (repo *SupaBaseCategoryRepository) Delete(id int) error {
+	_, _, err := repo.client.From(categoryTable).Delete("", "").
+		Eq("id", strconv.Itoa(id)).Execute()
+	return err
+}
+
+func (repo *SupaBaseCategoryRepository) FindByField(field string, value any) (*db.Category, error) {
+	var results []db.Category
+
+	var filterValue string
+	switch v := value.(type) {
+	case string:
+		filterValue = v
+	case int, int32, int64, uint, uint32, uint64:
+		filterValue = fmt.Sprintf("%d", v)
+	case float32, float64:
+		filterValue = fmt.Sprintf("%f", v)
+	case bool:
+		filterValue = strconv.FormatBool(v)
+	default:
+		return nil, fmt.Errorf("unsupported type for field filtering: %T", value)
+	}
+
+	_, err := repo.client.From(categoryTable).
+		Select("*", "exact", false).
+		Filter(field, "eq", filterValue).
+		ExecuteTo(&results)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(results) == 0 {
+		return nil, types.ErrNotFound
+	}
+
+	return &results[0], nil
+}
+
+func (repo *SupaBaseCategoryRepository) GetAll() ([]db.Category, error) {
+	var categories []db.Category
+	_, err := repo.client.From(categoryTable).Select("*", "exact", false).
+		ExecuteTo(&categories)
+	if err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+func (repo *SupaBaseCategoryRepository) GetByID(id int) (*db.Category, error) {
+	return repo.FindByField("id", id)
+}
+
+func (repo *SupaBaseCategoryRepository) Update(model *db.Category) (*db.Category, error) {
+	var result []db.Category
+	_, err := repo.client.From(categoryTable).Update(CreateCategory{
+		UserID:   model.UserID,
+		ParentID: model.ParentID,
+		Name:     model.Name,
+		Type:     model.Type,
+	}, "representation", "").Eq("id", strconv.Itoa(model.ID)).
+		ExecuteTo(&result)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, types.ErrNotFound
+	}
+	return &result[0], nil
+}
+
+// Query returns a page of the rows matching args; see ports.QueryOptions for the filters,
+// paging and counts it supports.
+func (repo *SupaBaseCategoryRepository) Query(fields string, args ports.QueryOptions) (ports.Page[db.Category], error) {
+	return supabaseQuery[db.Category](repo.client, categoryTable, fields, args)
+}
@@ -0,0 +1,174 @@
+package infrastructure
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"Financial/Core/Models/db"
+	"Financial/Core/ports"
+	"Financial/Core/types"
+
+	"github.com/supabase-community/postgrest-go"
+	"github.com/supabase-community/supabase-go"
+)
+
+const exchangeRateTable = "exchange_rates"
+
+// resolveRate picks, among candidates, the latest rate between from and to that is
+// effective at the given moment. A stored rate for the opposite pair is inverted;
+// when both directions exist the most recent one wins, preferring the direct pair on ties.
+func resolveRate(candidates []db.ExchangeRate, from string, to string, at time.Time) (*db.ExchangeRate, error) {
+	from = strings.ToUpper(from)
+	to = strings.ToUpper(to)
+	if from == to {
+		return &db.ExchangeRate{BaseCurrency: from, QuoteCurrency: to, Rate: types.IdentityRate(), EffectiveDate: at}, nil
+	}
+
+	var best *db.ExchangeRate
+	inverse := false
+	for i := range candidates {
+		rate := &candidates[i]
+		if rate.EffectiveDate.After(at) || rate.Rate.IsZero() {
+			continue
+		}
+		direct := rate.BaseCurrency == from && rate.QuoteCurrency == to
+		opposite := rate.BaseCurrency == to && rate.QuoteCurrency == from
+		if !direct && !opposite {
+			continue
+		}
+		if best == nil || rate.EffectiveDate.After(best.EffectiveDate) ||
+			(rate.EffectiveDate.Equal(best.EffectiveDate) && direct && inverse) {
+			best = rate
+			inverse = opposite
+		}
+	}
+
+	if best == nil {
+		return nil, fmt.Errorf("%w: %s to %s on %s", types.ErrExchangeRateNotFound, from, to, at.Format(time.DateOnly))
+	}
+	if inverse {
+		return &db.ExchangeRate{
+			BaseCurrency:  from,
+			QuoteCurrency: to,
+			Rate:          best.Rate.Inverse(),
+			EffectiveDate: best.EffectiveDate,
+		}, nil
+	}
+	result := *best
+	return &result, nil
+}
+
+// FileExchangeRateProvider serves exchange rates from a JSON file holding an array
+// of rates, e.g. [{"base_currency":"USD","quote_currency":"DOP","rate":58.75,"effective_date":"2025-01-01T00:00:00Z"}].
+// The file is read once when the provider is created.
+type FileExchangeRateProvider struct {
+	rates []db.ExchangeRate
+}
+
+func NewFileExchangeRateProvider(path string) (ports.ExchangeRateProvider, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading exchange rates file: %w", err)
+	}
+
+	var rates []db.ExchangeRate
+	if err := json.Unmarshal(content, &rates); err != nil {
+		return nil, fmt.Errorf("error parsing exchange rates file: %w", err)
+	}
+	for i := range rates {
+		rates[i].BaseCurrency = strings.ToUpper(rates[i].BaseCurrency)
+		rates[i].QuoteCurrency = strings.ToUpper(rates[i].QuoteCurrency)
+	}
+	sort.SliceStable(rates, func(i, j int) bool {
+		return rates[i].EffectiveDate.Before(rates[j].EffectiveDate)
+	})
+
+	return &FileExchangeRateProvider{rates: rates}, nil
+}
+
+func (p *FileExchangeRateProvider) GetRate(from string, to string, at time.Time) (*db.ExchangeRate, error) {
+	return resolveRate(p.rates, from, to, at)
+}
+
+// SupaBaseExchangeRateProvider serves exchange rates from the exchange_rates table.
+type SupaBaseExchangeRateProvider struct {
+	client *supabase.Client
+}
+
+func NewSupaBaseExchangeRateProvider(client *supabase.Client) ports.ExchangeRateProvider {
+	return &SupaBaseExchangeRateProvider{client: client}
+}
+
+func (p *SupaBaseExchangeRateProvider) GetRate(from string, to string, at time.Time) (*db.ExchangeRate, error) {
+	from = strings.ToUpper(from)
+	to = strings.ToUpper(to)
+	if from == to {
+		return resolveRate(nil, from, to, at)
+	}
+
+	// Both directions are fetched so an inverse rate can be derived when needed
+	var candidates []db.ExchangeRate
+	pair := []string{from, to}
+	_, err := p.client.From(exchangeRateTable).
+		Select("*", "", false).
+		In("base_currency", pair).
+		In("quote_currency", pair).
+		Lte("effective_date", at.UTC().Format(time.RFC3339)).
+		Order("effective_date", &postgrest.OrderOpts{Ascending: false}).
+		Limit(2, "").
+		ExecuteTo(&candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	return resolveRate(candidates, from, to, at)
+}
+
+// RepositoryExchangeRateProvider serves exchange rates from the exchange_rates table of the
+// stores that implement it as a Repository (SQLite and memory).
+type RepositoryExchangeRateProvider struct {
+	rates ports.Repository[db.ExchangeRate, int]
+}
+
+func NewSQLiteExchangeRateProvider(database *sql.DB) ports.ExchangeRateProvider {
+	return &RepositoryExchangeRateProvider{rates: newSQLiteRepository[db.ExchangeRate](database, exchangeRatesSchema)}
+}
+
+func NewMemoryExchangeRateProvider(store *MemoryStore) ports.ExchangeRateProvider {
+	return &RepositoryExchangeRateProvider{rates: NewMemoryExchangeRateRepository(store)}
+}
+
+func (p *RepositoryExchangeRateProvider) GetRate(from string, to string, at time.Time) (*db.ExchangeRate, error) {
+	from = strings.ToUpper(from)
+	to = strings.ToUpper(to)
+	if from == to {
+		return resolveRate(nil, from, to, at)
+	}
+
+	// The latest rate of each direction is enough for resolveRate
+	pair := []string{from, to}
+	limit := 1
+	var candidates []db.ExchangeRate
+	for _, base := range pair {
+		rates, err := p.rates.Query("*", ports.QueryOptions{
+			Filters: []ports.Filter{
+				{Field: "base_currency", Operator: "eq", Value: base},
+				{Field: "quote_currency", Operator: "in", Value: pair},
+				{Field: "effective_date", Operator: "lte", Value: at.UTC().Format(time.RFC3339Nano)},
+			},
+			OrderBy: []ports.OrderBy{{Field: "effective_date", Ascending: false}},
+			Limit:   &limit,
+		})
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, rates.Items...)
+	}
+
+	return resolveRate(candidates, from, to, at)
+}
@@ -0,0 +1,38 @@
+package infrastructure
+
+import (
+	"Financial/Core/ports"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
+// unsafeFileChars matches what can't be used in the file name of a saved message
+var unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9@._-]`)
+
+// FileMailer is the mailer for local development: instead of sending messages it saves
+// each one as an .eml file and logs where it went, so links can be opened by hand.
+type FileMailer struct {
+	dir string
+}
+
+func NewFileMailer(dir string) (ports.Mailer, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("error creating mail directory %s: %w", dir, err)
+	}
+	return &FileMailer{dir: dir}, nil
+}
+
+func (m *FileMailer) Send(message ports.MailMessage) error {
+	name := fmt.Sprintf("%d-%s.eml", time.Now().UnixNano(), unsafeFileChars.ReplaceAllString(message.To, "_"))
+	path := filepath.Join(m.dir, name)
+
+	if err := os.WriteFile(path, formatMail("no-reply@localhost", message), 0o600); err != nil {
+		return fmt.Errorf("error saving mail to %s: %w", path, err)
+	}
+	log.Printf("mail %q to %s saved in %s", message.Subject, message.To, path)
+	return nil
+}
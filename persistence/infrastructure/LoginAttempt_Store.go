@@ -0,0 +1,87 @@
+package infrastructure
+
+import (
+	"Financial/Core/Models/db"
+	"Financial/Core/ports"
+	"Financial/Core/types"
+	"time"
+
+	"github.com/supabase-community/supabase-go"
+)
+
+const loginAttemptTable = "login_attempts"
+
+// recordLoginFailureFunction is the Postgres function that counts a failure in a single statement,
+// so instances sharing the table never lose a failure
+const recordLoginFailureFunction = "record_login_failure"
+
+// SupaBaseLoginAttemptStore keeps the failed login counters in the database, shared by every instance
+type SupaBaseLoginAttemptStore struct {
+	client *supabase.Client
+}
+
+func NewSupaBaseLoginAttemptStore(client *supabase.Client) ports.LoginAttemptStore {
+	return &SupaBaseLoginAttemptStore{client: client}
+}
+
+// recordLoginFailureParams is a helper struct that matches the arguments of record_login_failure
+type recordLoginFailureParams struct {
+	Key           string    `json:"p_key"`
+	At            time.Time `json:"p_at"`
+	WindowSeconds int64     `json:"p_window_seconds"`
+}
+
+// lockLoginAttempt is a helper struct that matches the columns Lock changes
+type lockLoginAttempt struct {
+	LockedUntil    time.Time           `json:"locked_until"`
+	PreviousStatus types.AccountStatus `json:"previous_status"`
+}
+
+func (store *SupaBaseLoginAttemptStore) Get(key string) (*db.LoginAttempt, error) {
+	var results []db.LoginAttempt
+	_, err := store.client.From(loginAttemptTable).
+		Select("*", "exact", false).
+		Eq("key", key).
+		ExecuteTo(&results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, types.ErrNotFound
+	}
+	return &results[0], nil
+}
+
+func (store *SupaBaseLoginAttemptStore) RecordFailure(key string, at time.Time, window time.Duration) (*db.LoginAttempt, error) {
+	var result db.LoginAttempt
+	err := executeRpc(store.client, recordLoginFailureFunction, recordLoginFailureParams{
+		Key:           key,
+		At:            at,
+		WindowSeconds: int64(window / time.Second),
+	}, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (store *SupaBaseLoginAttemptStore) Lock(key string, until time.Time, previousStatus types.AccountStatus) error {
+	var results []db.LoginAttempt
+	_, err := store.client.From(loginAttemptTable).
+		Update(lockLoginAttempt{LockedUntil: until, PreviousStatus: previousStatus}, "representation", "").
+		Eq("key", key).
+		ExecuteTo(&results)
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		return types.ErrNotFound
+	}
+	return nil
+}
+
+func (store *SupaBaseLoginAttemptStore) Reset(key string) error {
+	_, _, err := store.client.From(loginAttemptTable).Delete("", "").
+		Eq("key", key).Execute()
+	return err
+}
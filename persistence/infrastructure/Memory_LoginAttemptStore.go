@@ -0,0 +1,65 @@
+package infrastructure
+
+import (
+	"Financial/Core/Models/db"
+	"Financial/Core/ports"
+	"Financial/Core/types"
+	"sync"
+	"time"
+)
+
+// MemoryLoginAttemptStore keeps the failed login counters in the memory of the process.
+// It suits a single instance; several instances need the database store to share counters.
+type MemoryLoginAttemptStore struct {
+	mu       sync.Mutex
+	attempts map[string]db.LoginAttempt
+}
+
+func NewMemoryLoginAttemptStore() ports.LoginAttemptStore {
+	return &MemoryLoginAttemptStore{attempts: map[string]db.LoginAttempt{}}
+}
+
+func (store *MemoryLoginAttemptStore) Get(key string) (*db.LoginAttempt, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	attempt, ok := store.attempts[key]
+	if !ok {
+		return nil, types.ErrNotFound
+	}
+	return &attempt, nil
+}
+
+func (store *MemoryLoginAttemptStore) RecordFailure(key string, at time.Time, window time.Duration) (*db.LoginAttempt, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	attempt, ok := store.attempts[key]
+	if !ok || at.Sub(attempt.LastFailureAt) >= window {
+		attempt = db.LoginAttempt{Key: key}
+	}
+	attempt.Failures++
+	attempt.LastFailureAt = at
+	store.attempts[key] = attempt
+	return &attempt, nil
+}
+
+func (store *MemoryLoginAttemptStore) Lock(key string, until time.Time, previousStatus types.AccountStatus) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	attempt := store.attempts[key]
+	attempt.Key = key
+	attempt.LockedUntil = &until
+	attempt.PreviousStatus = previousStatus
+	store.attempts[key] = attempt
+	return nil
+}
+
+func (store *MemoryLoginAttemptStore) Reset(key string) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	delete(store.attempts, key)
+	return nil
+}
@@ -0,0 +1,274 @@
+package infrastructure
+
+import (
+	"fmt"
+
+	"Financial/Core/Models/db"
+	"Financial/Core/ports"
+	"Financial/Core/types"
+)
+
+// MemoryRepository stores the models of one table in a MemoryStore. Like SQLiteRepository, it
+// works for any model whose JSON names are the column names.
+type MemoryRepository[T any] struct {
+	data  memoryAccess
+	table *tableSchema
+}
+
+func newMemoryRepository[T any](data memoryAccess, table *tableSchema) *MemoryRepository[T] {
+	return &MemoryRepository[T]{data: data, table: table}
+}
+
+func NewMemoryUserRepository(store *MemoryStore) ports.Repository[db.User, int] {
+	return newMemoryRepository[db.User](store, usersSchema)
+}
+
+func NewMemoryWalletRepository(store *MemoryStore) ports.Repository[db.Wallet, int] {
+	return newMemoryRepository[db.Wallet](store, walletsSchema)
+}
+
+func NewMemoryTransactionRepository(store *MemoryStore) ports.Repository[db.Transaction, int] {
+	return newMemoryRepository[db.Transaction](store, transactionsSchema)
+}
+
+func NewMemoryExchangeRateRepository(store *MemoryStore) ports.Repository[db.ExchangeRate, int] {
+	return newMemoryRepository[db.ExchangeRate](store, exchangeRatesSchema)
+}
+
+func NewMemoryCategoryRepository(store *MemoryStore) ports.Repository[db.Category, int] {
+	return newMemoryRepository[db.Category](store, categoriesSchema)
+}
+
+func NewMemoryBudgetRepository(store *MemoryStore) ports.Repository[db.Budget, int] {
+	return newMemoryRepository[db.Budget](store, budgetsSchema)
+}
+
+func NewMemoryRecurringTransactionRepository(store *MemoryStore) ports.Repository[db.RecurringTransaction, int] {
+	return newMemoryRepository[db.RecurringTransaction](store, recurringTransactionsSchema)
+}
+
+// MemorySessionRepository adds the conditional rotation of refresh tokens to the sessions table
+type MemorySessionRepository struct {
+	*MemoryRepository[db.Session]
+}
+
+func NewMemorySessionRepository(store *MemoryStore) ports.SessionRepository {
+	return &MemorySessionRepository{newMemoryRepository[db.Session](store, sessionsSchema)}
+}
+
+func (repo *MemorySessionRepository) RotateRefresh(session *db.Session, previousHash string) (*db.Session, error) {
+	return repo.updateWhere(session, "refresh_token_hash", previousHash)
+}
+
+func NewMemoryPasswordResetRepository(store *MemoryStore) ports.Repository[db.PasswordReset, int] {
+	return newMemoryRepository[db.PasswordReset](store, passwordResetsSchema)
+}
+
+func NewMemoryTwoFactorRepository(store *MemoryStore) ports.Repository[db.TwoFactor, int] {
+	return newMemoryRepository[db.TwoFactor](store, twoFactorsSchema)
+}
+
+func NewMemoryAPIKeyRepository(store *MemoryStore) ports.Repository[db.APIKey, int] {
+	return newMemoryRepository[db.APIKey](store, apiKeysSchema)
+}
+
+func NewMemoryUserIdentityRepository(store *MemoryStore) ports.Repository[db.UserIdentity, int] {
+	return newMemoryRepository[db.UserIdentity](store, userIdentitiesSchema)
+}
+
+func NewMemoryOIDCLoginRepository(store *MemoryStore) ports.Repository[db.OIDCLogin, int] {
+	return newMemoryRepository[db.OIDCLogin](store, oidcLoginsSchema)
+}
+
+func NewMemoryAuditRepository(store *MemoryStore) ports.Repository[db.AuditEntry, int] {
+	return newMemoryRepository[db.AuditEntry](store, auditLogSchema)
+}
+
+func (repo *MemoryRepository[T]) Create(model *T) (*T, error) {
+	values, err := columnValues(repo.table, model)
+	if err != nil {
+		return nil, err
+	}
+	var row memoryRow
+	err = repo.data.write(func(state *memoryState) error {
+		row, err = state.insert(repo.table, values)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return repo.model(row)
+}
+
+func (repo *MemoryRepository[T]) Delete(id int) error {
+	return repo.data.write(func(state *memoryState) error {
+		return state.delete(repo.table, int64(id))
+	})
+}
+
+func (repo *MemoryRepository[T]) FindByField(field string, value any) (*T, error) {
+	limit := 1
+	page, err := repo.Query("*", ports.QueryOptions{
+		Filters: []ports.Filter{{Field: field, Operator: ports.OpEq, Value: value}},
+		OrderBy: []ports.OrderBy{{Field: "id", Ascending: true}},
+		Limit:   &limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(page.Items) == 0 {
+		return nil, types.ErrNotFound
+	}
+	return &page.Items[0], nil
+}
+
+func (repo *MemoryRepository[T]) GetAll() ([]T, error) {
+	page, err := repo.Query("*", ports.QueryOptions{})
+	return page.Items, err
+}
+
+// GetForUpdate reads like GetByID: a MemoryUnitOfWork holds the store until it ends, so
+// nothing else writes the row in between
+func (repo *MemoryRepository[T]) GetForUpdate(id int) (*T, error) {
+	return repo.GetByID(id)
+}
+
+func (repo *MemoryRepository[T]) GetByID(id int) (*T, error) {
+	var row memoryRow
+	repo.data.read(func(state *memoryState) error {
+		row = state.table(repo.table).rows[int64(id)]
+		return nil
+	})
+	if row == nil {
+		return nil, types.ErrNotFound
+	}
+	return repo.model(row)
+}
+
+// Update writes the writable columns of the model that its JSON carries
+func (repo *MemoryRepository[T]) Update(model *T) (*T, error) {
+	return repo.updateWhere(model, "", nil)
+}
+
+// updateWhere is Update, but when column is set the row is only written while column still
+// holds expected; otherwise it returns types.ErrNotFound
+func (repo *MemoryRepository[T]) updateWhere(model *T, column string, expected any) (*T, error) {
+	values, err := columnValues(repo.table, model)
+	if err != nil {
+		return nil, err
+	}
+	id, ok := values["id"].(int64)
+	if !ok {
+		return nil, fmt.Errorf("%s model without id", repo.table.name)
+	}
+
+	writable := repo.table.writable
+	if writable == nil {
+		for _, column := range repo.table.columns {
+			writable = append(writable, column.name)
+		}
+	}
+	changes := map[string]any{}
+	for _, name := range writable {
+		value, ok := values[name]
+		if name == "id" || !ok || value == storedZeroTime {
+			continue
+		}
+		changes[name] = value
+	}
+
+	var row memoryRow
+	err = repo.data.write(func(state *memoryState) error {
+		if column != "" {
+			current, ok := state.table(repo.table).rows[id]
+			if !ok || current[column] != expected {
+				return types.ErrNotFound
+			}
+		}
+		row, err = state.update(repo.table, id, changes)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return repo.model(row)
+}
+
+// Query returns a page of the models matching args. fields is a PostgREST select list, as in
+// SQLiteRepository.Query.
+func (repo *MemoryRepository[T]) Query(fields string, args ports.QueryOptions) (ports.Page[T], error) {
+	plan, err := newQueryPlan(args)
+	if err != nil {
+		return ports.Page[T]{}, err
+	}
+
+	var objects []map[string]any
+	var total int
+	err = repo.data.read(func(state *memoryState) error {
+		var err error
+		objects, total, err = state.query(repo.table, fields, plan)
+		return err
+	})
+	if err != nil {
+		return ports.Page[T]{}, err
+	}
+	models, err := decodeModels[T](objects)
+	if err != nil {
+		return ports.Page[T]{}, err
+	}
+	return queryPage(plan, models, total), nil
+}
+
+func (repo *MemoryRepository[T]) model(row memoryRow) (*T, error) {
+	models, err := decodeModels[T]([]map[string]any{memoryObject(repo.table, row)})
+	if err != nil {
+		return nil, err
+	}
+	return &models[0], nil
+}
+
+// MemoryTransferRepository applies transfers on a MemoryStore; a rejected transfer changes nothing
+type MemoryTransferRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryTransferRepository(store *MemoryStore) ports.TransferRepository {
+	return &MemoryTransferRepository{store: store}
+}
+
+func (repo *MemoryTransferRepository) Transfer(transfer *db.Transfer) (*db.Transfer, error) {
+	var result *db.Transfer
+	err := repo.store.write(func(state *memoryState) error {
+		var err error
+		result, err = transferBetweenWallets(
+			newMemoryRepository[db.Wallet](state, walletsSchema),
+			newMemoryRepository[db.Transaction](state, transactionsSchema),
+			transfer)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// MemoryUnitOfWork runs a unit of work on a copy of the MemoryStore data that replaces it only
+// when every write succeeded
+type MemoryUnitOfWork struct {
+	store *MemoryStore
+}
+
+func NewMemoryUnitOfWork(store *MemoryStore) ports.UnitOfWork {
+	return &MemoryUnitOfWork{store: store}
+}
+
+func (uow *MemoryUnitOfWork) Do(fn func(repos ports.UnitOfWorkRepositories) error) error {
+	return uow.store.write(func(state *memoryState) error {
+		return fn(ports.UnitOfWorkRepositories{
+			Users:        newMemoryRepository[db.User](state, usersSchema),
+			Wallets:      newMemoryRepository[db.Wallet](state, walletsSchema),
+			Transactions: newMemoryRepository[db.Transaction](state, transactionsSchema),
+			Categories:   newMemoryRepository[db.Category](state, categoriesSchema),
+		})
+	})
+}
@@ -0,0 +1,561 @@
+package infrastructure
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"Financial/Core/ports"
+	"Financial/Core/types"
+)
+
+// MemoryStore keeps every table in the memory of the process, enforcing the constraints of
+// the SQL schema: not null columns, checks, unique keys and foreign keys with their ON DELETE
+// actions. It is meant for demos and end-to-end tests; the data is gone when the process ends.
+type MemoryStore struct {
+	mu    sync.RWMutex
+	state *memoryState
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{state: newMemoryState()}
+}
+
+func (store *MemoryStore) read(fn func(state *memoryState) error) error {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+	return fn(store.state)
+}
+
+func (store *MemoryStore) write(fn func(state *memoryState) error) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	return store.state.change(fn)
+}
+
+// memoryAccess is what the memory repositories work with: the store, or the state
+// of a change in progress so several writes succeed or fail together
+type memoryAccess interface {
+	read(fn func(state *memoryState) error) error
+	write(fn func(state *memoryState) error) error
+}
+
+// memoryRow is a stored row: the values of its columns as columnValues gives them. Rows are
+// never modified once stored; an update stores a new row.
+type memoryRow = map[string]any
+
+type memoryTable struct {
+	lastID int64
+	rows   map[int64]memoryRow
+}
+
+// memoryState is one version of the data. A change is made on a copy that replaces the state
+// only when the whole change succeeds; the copy shares the tables it doesn't modify.
+type memoryState struct {
+	tables map[string]*memoryTable
+
+	// owned are the tables this copy has already copied, which it may modify in place
+	owned map[string]bool
+}
+
+func newMemoryState() *memoryState {
+	return &memoryState{tables: map[string]*memoryTable{}, owned: map[string]bool{}}
+}
+
+func (state *memoryState) read(fn func(state *memoryState) error) error {
+	return fn(state)
+}
+
+func (state *memoryState) write(fn func(state *memoryState) error) error {
+	return state.change(fn)
+}
+
+// change runs fn on a copy of the state and keeps its changes if it succeeds
+func (state *memoryState) change(fn func(state *memoryState) error) error {
+	copied := &memoryState{tables: make(map[string]*memoryTable, len(state.tables)), owned: map[string]bool{}}
+	for name, table := range state.tables {
+		copied.tables[name] = table
+	}
+	if err := fn(copied); err != nil {
+		return err
+	}
+	state.tables = copied.tables
+	for name := range copied.owned {
+		state.owned[name] = true
+	}
+	return nil
+}
+
+func (state *memoryState) table(schema *tableSchema) *memoryTable {
+	if table, ok := state.tables[schema.name]; ok {
+		return table
+	}
+	return &memoryTable{rows: map[int64]memoryRow{}}
+}
+
+// writable returns the table of schema, copying it first if it is shared with another state
+func (state *memoryState) writable(schema *tableSchema) *memoryTable {
+	if state.owned[schema.name] {
+		return state.tables[schema.name]
+	}
+	current := state.table(schema)
+	table := &memoryTable{lastID: current.lastID, rows: make(map[int64]memoryRow, len(current.rows)+1)}
+	for id, row := range current.rows {
+		table.rows[id] = row
+	}
+	state.tables[schema.name] = table
+	state.owned[schema.name] = true
+	return table
+}
+
+// insert adds a row with the given values. The id is always generated, and null values and
+// zero times are left out so the defaults apply, as the SQL repositories do.
+func (state *memoryState) insert(schema *tableSchema, values map[string]any) (memoryRow, error) {
+	row := memoryRow{}
+	for _, column := range schema.columns {
+		value, ok := values[column.name]
+		if column.name == "id" || !ok || value == nil || value == storedZeroTime {
+			continue
+		}
+		row[column.name] = value
+	}
+	for name, value := range schema.defaults {
+		if _, ok := row[name]; ok {
+			continue
+		}
+		if value == defaultNow {
+			value = time.Now().UTC().Format(storedTimeLayout)
+		}
+		row[name] = value
+	}
+
+	table := state.writable(schema)
+	table.lastID++
+	row["id"] = table.lastID
+	if err := state.validate(schema, row); err != nil {
+		return nil, err
+	}
+	table.rows[table.lastID] = row
+	return row, nil
+}
+
+// update changes the columns of a row; values holds only the columns to change
+func (state *memoryState) update(schema *tableSchema, id int64, values map[string]any) (memoryRow, error) {
+	current, ok := state.table(schema).rows[id]
+	if !ok {
+		return nil, types.ErrNotFound
+	}
+	row := make(memoryRow, len(current))
+	for name, value := range current {
+		row[name] = value
+	}
+	for name, value := range values {
+		row[name] = value
+	}
+	if err := state.validate(schema, row); err != nil {
+		return nil, err
+	}
+	state.writable(schema).rows[id] = row
+	return row, nil
+}
+
+// delete removes a row and applies the ON DELETE action of every foreign key that references it.
+// References that block the delete are checked once the cascades are done, like at the end of
+// a SQL statement.
+func (state *memoryState) delete(schema *tableSchema, id int64) error {
+	if _, ok := state.table(schema).rows[id]; !ok {
+		return nil
+	}
+	deleted := map[*tableSchema]bool{}
+	state.remove(schema, id, deleted)
+
+	for _, child := range schemas {
+		for _, key := range child.foreignKeys {
+			if key.onDelete != onDeleteNoAction || !deleted[key.table] {
+				continue
+			}
+			for _, row := range state.table(child).rows {
+				if !state.references(key, row) {
+					return fmt.Errorf("update or delete on table %q violates foreign key constraint %q on table %q",
+						key.table.name, key.name, child.name)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (state *memoryState) remove(schema *tableSchema, id int64, deleted map[*tableSchema]bool) {
+	delete(state.writable(schema).rows, id)
+	deleted[schema] = true
+
+	for _, child := range schemas {
+		for _, key := range child.foreignKeys {
+			if key.table != schema || key.onDelete == onDeleteNoAction {
+				continue
+			}
+			var referencing []int64
+			for childID, row := range state.table(child).rows {
+				if row[key.column] == id {
+					referencing = append(referencing, childID)
+				}
+			}
+			for _, childID := range referencing {
+				row, ok := state.table(child).rows[childID]
+				if !ok {
+					continue
+				}
+				if key.onDelete == onDeleteCascade {
+					state.remove(child, childID, deleted)
+					continue
+				}
+				cleared := make(memoryRow, len(row))
+				for name, value := range row {
+					cleared[name] = value
+				}
+				cleared[key.column] = nil
+				state.writable(child).rows[childID] = cleared
+			}
+		}
+	}
+}
+
+// references reports whether the row referenced through key exists; a null reference always does
+func (state *memoryState) references(key tableForeignKey, row memoryRow) bool {
+	id, ok := row[key.column].(int64)
+	if !ok {
+		return true
+	}
+	_, exists := state.table(key.table).rows[id]
+	return exists
+}
+
+// validate checks a row against the constraints of its table
+func (state *memoryState) validate(schema *tableSchema, row memoryRow) error {
+	for _, column := range schema.notNull {
+		if row[column] == nil {
+			return fmt.Errorf("null value in column %q of relation %q violates not-null constraint", column, schema.name)
+		}
+	}
+	for _, check := range schema.checks {
+		if !check.valid(row) {
+			return fmt.Errorf("new row for relation %q violates check constraint %q", schema.name, check.name)
+		}
+	}
+	for _, constraint := range schema.uniques {
+		key, ok := constraint.key(row)
+		if !ok {
+			continue
+		}
+		for id, other := range state.table(schema).rows {
+			if id == row["id"] {
+				continue
+			}
+			if otherKey, ok := constraint.key(other); ok && otherKey == key {
+				return fmt.Errorf("duplicate key value violates unique constraint %q", constraint.name)
+			}
+		}
+	}
+	for _, key := range schema.foreignKeys {
+		if !state.references(key, row) {
+			return fmt.Errorf("insert or update on table %q violates foreign key constraint %q", schema.name, key.name)
+		}
+	}
+	return nil
+}
+
+// query returns the rows of schema matching args as the JSON objects of their models. fields is
+// a PostgREST select list, as in the SQL repositories.
+func (state *memoryState) query(schema *tableSchema, fields string, plan *queryPlan) ([]map[string]any, int, error) {
+	selection, err := parseSelectList(schema, fields)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var rows []memoryRow
+	for _, row := range state.table(schema).rows {
+		matches, err := state.matchesAll(schema, row, plan.filters)
+		if err != nil {
+			return nil, 0, err
+		}
+		if matches {
+			rows = append(rows, row)
+		}
+	}
+	if err := sortMemoryRows(schema, rows, plan.orders); err != nil {
+		return nil, 0, err
+	}
+	total := len(rows)
+	rows = pageMemoryRows(rows, plan)
+
+	objects := make([]map[string]any, len(rows))
+	for i, row := range rows {
+		objects[i] = memoryObject(schema, row)
+	}
+	for _, embed := range selection.embeds {
+		state.embedRows(schema, embed, objects)
+	}
+	narrowObjects(schema, selection, objects)
+	return objects, total, nil
+}
+
+// embedRows adds to every object its related rows under the embed key
+func (state *memoryState) embedRows(schema *tableSchema, embed selectEmbed, objects []map[string]any) {
+	relation := embed.relation
+	localName := schema.jsonName(relation.localKey)
+	foreignName := relation.table.jsonName(relation.foreignKey)
+
+	related := map[any][]map[string]any{}
+	var children []map[string]any
+	for _, row := range sortedByID(state.table(relation.table).rows) {
+		child := memoryObject(relation.table, row)
+		related[child[foreignName]] = append(related[child[foreignName]], child)
+		children = append(children, child)
+	}
+	for _, nested := range embed.fields.embeds {
+		state.embedRows(relation.table, nested, children)
+	}
+	narrowObjects(relation.table, embed.fields, children)
+
+	for _, object := range objects {
+		var matches []map[string]any
+		if key := object[localName]; key != nil {
+			matches = related[key]
+		}
+		switch {
+		case relation.many && matches == nil:
+			object[embed.key] = []map[string]any{}
+		case relation.many:
+			object[embed.key] = matches
+		case len(matches) > 0:
+			object[embed.key] = matches[0]
+		default:
+			object[embed.key] = nil
+		}
+	}
+}
+
+// memoryObject converts a row into the JSON of its model
+func memoryObject(schema *tableSchema, row memoryRow) map[string]any {
+	object := make(map[string]any, len(schema.columns))
+	for _, column := range schema.columns {
+		object[schema.jsonName(column.name)] = storedJSON(column, row[column.name])
+	}
+	return object
+}
+
+func (state *memoryState) matchesAll(schema *tableSchema, row memoryRow, filters []queryFilter) (bool, error) {
+	return matchFilters(filters, false, func(filter queryFilter) (bool, error) {
+		return state.matches(schema, row, filter)
+	})
+}
+
+// matches evaluates a filter on a row. A field "users.email" filters on a column of a related
+// table and keeps the rows that have a matching related row, like an !inner embed in PostgREST.
+func (state *memoryState) matches(schema *tableSchema, row memoryRow, filter queryFilter) (bool, error) {
+	if relationName, field, ok := strings.Cut(filter.field, "."); ok {
+		relation, ok := schema.relations[relationName]
+		if !ok {
+			return false, fmt.Errorf("unknown filter field %q", filter.field)
+		}
+		inner := filter
+		inner.field = field
+		for _, related := range state.table(relation.table).rows {
+			if related[relation.foreignKey] == nil || related[relation.foreignKey] != row[relation.localKey] {
+				continue
+			}
+			matches, err := state.matches(relation.table, related, inner)
+			if err != nil || matches {
+				return matches, err
+			}
+		}
+		return false, nil
+	}
+
+	column, ok := schema.column(filter.field)
+	if !ok || column.kind == columnArray {
+		return false, fmt.Errorf("unknown filter field %q", filter.field)
+	}
+	value := row[column.name]
+
+	switch filter.operator {
+	case ports.OpIs:
+		if filter.values[0] == "null" {
+			return value == nil, nil
+		}
+		return value != nil && fmt.Sprint(value) == filter.values[0], nil
+	case ports.OpIn:
+		for _, text := range filter.values {
+			arg, err := columnArg(column, text)
+			if err != nil {
+				return false, err
+			}
+			if value != nil && compareStored(column, value, arg) == 0 {
+				return true, nil
+			}
+		}
+		return false, nil
+	case ports.OpLike, ports.OpIlike:
+		if value == nil {
+			return false, nil
+		}
+		return likeToRegexp(filter.values[0], filter.operator == ports.OpIlike).MatchString(fmt.Sprint(value)), nil
+	}
+
+	arg, err := columnArg(column, filter.values[0])
+	if err != nil {
+		return false, err
+	}
+	if value == nil {
+		return false, nil
+	}
+
+	comparison := compareStored(column, value, arg)
+	switch filter.operator {
+	case ports.OpEq:
+		return comparison == 0, nil
+	case ports.OpNeq:
+		return comparison != 0, nil
+	case ports.OpGt:
+		return comparison > 0, nil
+	case ports.OpGte:
+		return comparison >= 0, nil
+	case ports.OpLt:
+		return comparison < 0, nil
+	}
+	return comparison <= 0, nil
+}
+
+// compareStored compares two non-null values of column
+func compareStored(column tableColumn, a, b any) int {
+	switch column.kind {
+	case columnInteger:
+		x, okX := a.(int64)
+		y, okY := b.(int64)
+		if okX && okY {
+			return cmpInt64(x, y)
+		}
+	case columnDecimal:
+		x, okX := new(big.Rat).SetString(fmt.Sprint(a))
+		y, okY := new(big.Rat).SetString(fmt.Sprint(b))
+		if okX && okY {
+			return x.Cmp(y)
+		}
+	}
+	// Text, and timestamps in storedTimeLayout, compare as strings
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+func cmpInt64(x, y int64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
+
+// likeToRegexp translates a LIKE pattern into a regular expression matching the whole value
+func likeToRegexp(pattern string, ignoreCase bool) *regexp.Regexp {
+	var expr strings.Builder
+	expr.WriteString("(?s)")
+	if ignoreCase {
+		expr.WriteString("(?i)")
+	}
+	expr.WriteString("^")
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			escaped = false
+			expr.WriteString(regexp.QuoteMeta(string(r)))
+		case r == '\\':
+			escaped = true
+		case r == '%':
+			expr.WriteString(".*")
+		case r == '_':
+			expr.WriteString(".")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	expr.WriteString("$")
+	return regexp.MustCompile(expr.String())
+}
+
+// sortMemoryRows orders rows by id and then by orders. Without NullsFirst, nulls go where
+// Postgres puts them: last when ascending and first when descending.
+func sortMemoryRows(schema *tableSchema, rows []memoryRow, orders []ports.OrderBy) error {
+	columns := make([]tableColumn, len(orders))
+	for i, order := range orders {
+		column, ok := schema.column(order.Field)
+		if !ok {
+			return fmt.Errorf("unknown order field %q", order.Field)
+		}
+		columns[i] = column
+	}
+
+	sort.Slice(rows, func(i, j int) bool { return rows[i]["id"].(int64) < rows[j]["id"].(int64) })
+	sort.SliceStable(rows, func(i, j int) bool {
+		for k, order := range orders {
+			a, b := rows[i][columns[k].name], rows[j][columns[k].name]
+			nullsFirst := !order.Ascending
+			if order.NullsFirst != nil {
+				nullsFirst = *order.NullsFirst
+			}
+
+			var comparison int
+			switch {
+			case a == nil && b == nil:
+				continue
+			case a == nil:
+				comparison = 1
+				if nullsFirst {
+					comparison = -1
+				}
+			case b == nil:
+				comparison = -1
+				if nullsFirst {
+					comparison = 1
+				}
+			default:
+				comparison = compareStored(columns[k], a, b)
+				if !order.Ascending {
+					comparison = -comparison
+				}
+			}
+			if comparison != 0 {
+				return comparison < 0
+			}
+		}
+		return false
+	})
+	return nil
+}
+
+func pageMemoryRows(rows []memoryRow, plan *queryPlan) []memoryRow {
+	rows = rows[min(plan.offset, len(rows)):]
+	if plan.limit != nil {
+		rows = rows[:min(*plan.limit, len(rows))]
+	}
+	return rows
+}
+
+func sortedByID(rows map[int64]memoryRow) []memoryRow {
+	ids := make([]int64, 0, len(rows))
+	for id := range rows {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	sorted := make([]memoryRow, len(ids))
+	for i, id := range ids {
+		sorted[i] = rows[id]
+	}
+	return sorted
+}
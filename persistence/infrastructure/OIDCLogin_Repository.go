@@ -0,0 +1,129 @@
+package infrastructure
+
+import (
+	"Financial/Core/Models/db"
+	"Financial/Core/ports"
+	"Financial/Core/types"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/supabase-community/supabase-go"
+)
+
+const oidcLoginTable = "oidc_logins"
+
+type SupaBaseOIDCLoginRepository struct {
+	client *supabase.Client
+}
+
+func NewSupaBaseOIDCLoginRepository(client *supabase.Client) ports.Repository[db.OIDCLogin, int] {
+	return &SupaBaseOIDCLoginRepository{client: client}
+}
+
+// CreateOIDCLogin is a helper struct that matches the database schema
+type CreateOIDCLogin struct {
+	StateHash    string    `json:"state_hash"`
+	CodeVerifier string    `json:"code_verifier"`
+	Nonce        string    `json:"nonce"`
+	CreatedAt    time.Time `json:"created_at"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+// newCreateOIDCLogin maps the model to the columns, leaving out the ID
+func newCreateOIDCLogin(model *db.OIDCLogin) CreateOIDCLogin {
+	return CreateOIDCLogin{
+		StateHash:    model.StateHash,
+		CodeVerifier: model.CodeVerifier,
+		Nonce:        model.Nonce,
+		CreatedAt:    model.CreatedAt,
+		ExpiresAt:    model.ExpiresAt,
+	}
+}
+
+func (repo *SupaBaseOIDCLoginRepository) Create(model *db.OIDCLogin) (*db.OIDCLogin, error) {
+	newLogin := newCreateOIDCLogin(model)
+
+	var result db.OIDCLogin
+	_, err := repo.client.From(oidcLoginTable).
+		Insert(newLogin, false, "", "representation", "").
+		Single().
+		ExecuteTo(&result)
+
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (repo *SupaBaseOIDCLoginRepository) Delete(id int) error {
+	_, _, err := repo.client.From(oidcLoginTable).Delete("", "").
+		Eq("id", strconv.Itoa(id)).Execute()
+	return err
+}
+
+func (repo *SupaBaseOIDCLoginRepository) FindByField(field string, value any) (*db.OIDCLogin, error) {
+	var results []db.OIDCLogin
+
+	var filterValue string
+	switch v := value.(type) {
+	case string:
+		filterValue = v
+	case int, int32, int64, uint, uint32, uint64:
+		filterValue = fmt.Sprintf("%d", v)
+	case float32, float64:
+		filterValue = fmt.Sprintf("%f", v)
+	case bool:
+		filterValue = strconv.FormatBool(v)
+	default:
+		return nil, fmt.Errorf("unsupported type for field filtering: %T", value)
+	}
+
+	_, err := repo.client.From(oidcLoginTable).
+		Select("*", "exact", false).
+		Filter(field, "eq", filterValue).
+		ExecuteTo(&results)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(results) == 0 {
+		return nil, types.ErrNotFound
+	}
+
+	return &results[0], nil
+}
+
+func (repo *SupaBaseOIDCLoginRepository) GetAll() ([]db.OIDCLogin, error) {
+	var logins []db.OIDCLogin
+	_, err := repo.client.From(oidcLoginTable).Select("*", "exact", false).
+		ExecuteTo(&logins)
+	if err != nil {
+		return nil, err
+	}
+	return logins, nil
+}
+
+func (repo *SupaBaseOIDCLoginRepository) GetByID(id int) (*db.OIDCLogin, error) {
+	return repo.FindByField("id", id)
+}
+
+func (repo *SupaBaseOIDCLoginRepository) Update(model *db.OIDCLogin) (*db.OIDCLogin, error) {
+	var result []db.OIDCLogin
+	_, err := repo.client.From(oidcLoginTable).Update(newCreateOIDCLogin(model), "representation", "").Eq("id", strconv.Itoa(model.ID)).
+		ExecuteTo(&result)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, types.ErrNotFound
+	}
+	return &result[0], nil
+}
+
+// Query returns a page of the rows matching args; see ports.QueryOptions for the filters,
+// paging and counts it supports.
+func (repo *SupaBaseOIDCLoginRepository) Query(fields string, args ports.QueryOptions) (ports.Page[db.OIDCLogin], error) {
+	return supabaseQuery[db.OIDCLogin](repo.client, oidcLoginTable, fields, args)
+}
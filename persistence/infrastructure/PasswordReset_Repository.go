@@ -0,0 +1,129 @@
+package infrastructure
+
+import (
+	"Financial/Core/Models/db"
+	"Financial/Core/ports"
+	"Financial/Core/types"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/supabase-community/supabase-go"
+)
+
+const passwordResetTable = "password_resets"
+
+type SupaBasePasswordResetRepository struct {
+	client *supabase.Client
+}
+
+func NewSupaBasePasswordResetRepository(client *supabase.Client) ports.Repository[db.PasswordReset, int] {
+	return &SupaBasePasswordResetRepository{client: client}
+}
+
+// CreatePasswordReset is a helper struct that matches the database schema
+type CreatePasswordReset struct {
+	UserID    int        `json:"user_id"`
+	TokenHash string     `json:"token_hash"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+}
+
+// newCreatePasswordReset maps the model to the columns, leaving out the ID
+func newCreatePasswordReset(model *db.PasswordReset) CreatePasswordReset {
+	return CreatePasswordReset{
+		UserID:    model.UserID,
+		TokenHash: model.TokenHash,
+		CreatedAt: model.CreatedAt,
+		ExpiresAt: model.ExpiresAt,
+		UsedAt:    model.UsedAt,
+	}
+}
+
+func (repo *SupaBasePasswordResetRepository) Create(model *db.PasswordReset) (*db.PasswordReset, error) {
+	newReset := newCreatePasswordReset(model)
+
+	var result db.PasswordReset
+	_, err := repo.client.From(passwordResetTable).
+		Insert(newReset, false, "", "representation", "").
+		Single().
+		ExecuteTo(&result)
+
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (repo *SupaBasePasswordResetRepository) Delete(id int) error {
+	_, _, err := repo.client.From(passwordResetTable).Delete("", "").
+		Eq("id", strconv.Itoa(id)).Execute()
+	return err
+}
+
+func (repo *SupaBasePasswordResetRepository) FindByField(field string, value any) (*db.PasswordReset, error) {
+	var results []db.PasswordReset
+
+	var filterValue string
+	switch v := value.(type) {
+	case string:
+		filterValue = v
+	case int, int32, int64, uint, uint32, uint64:
+		filterValue = fmt.Sprintf("%d", v)
+	case float32, float64:
+		filterValue = fmt.Sprintf("%f", v)
+	case bool:
+		filterValue = strconv.FormatBool(v)
+	default:
+		return nil, fmt.Errorf("unsupported type for field filtering: %T", value)
+	}
+
+	_, err := repo.client.From(passwordResetTable).
+		Select("*", "exact", false).
+		Filter(field, "eq", filterValue).
+		ExecuteTo(&results)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(results) == 0 {
+		return nil, types.ErrNotFound
+	}
+
+	return &results[0], nil
+}
+
+func (repo *SupaBasePasswordResetRepository) GetAll() ([]db.PasswordReset, error) {
+	var resets []db.PasswordReset
+	_, err := repo.client.From(passwordResetTable).Select("*", "exact", false).
+		ExecuteTo(&resets)
+	if err != nil {
+		return nil, err
+	}
+	return resets, nil
+}
+
+func (repo *SupaBasePasswordResetRepository) GetByID(id int) (*db.PasswordReset, error) {
+	return repo.FindByField("id", id)
+}
+
+func (repo *SupaBasePasswordResetRepository) Update(model *db.PasswordReset) (*db.PasswordReset, error) {
+	var result []db.PasswordReset
+	_, err := repo.client.From(passwordResetTable).Update(newCreatePasswordReset(model), "representation", "").Eq("id", strconv.Itoa(model.ID)).
+		ExecuteTo(&result)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, types.ErrNotFound
+	}
+	return &result[0], nil
+}
+
+// Query returns a page of the rows matching args; see ports.QueryOptions for the filters,
+// paging and counts it supports.
+func (repo *SupaBasePasswordResetRepository) Query(fields string, args ports.QueryOptions) (ports.Page[db.PasswordReset], error) {
+	return supabaseQuery[db.PasswordReset](repo.client, passwordResetTable, fields, args)
+}
@@ -0,0 +1,223 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"Financial/Core/Models/db"
+	"Financial/Core/ports"
+	"Financial/Core/types"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresTableRepository stores the models of one table through a direct Postgres connection.
+// Like SQLiteRepository it works for any model whose JSON names are the column names: rows are
+// written from JSON with jsonb_populate_record and read back with to_jsonb, so Postgres converts
+// every value to the type of its column.
+type PostgresTableRepository[T any] struct {
+	db      pgExecutor
+	table   *tableSchema
+	columns map[string]pgColumn
+}
+
+func newPostgresTableRepository[T any](conn pgExecutor, table *tableSchema) *PostgresTableRepository[T] {
+	return &PostgresTableRepository[T]{db: conn, table: table, columns: pgTableColumns(table)}
+}
+
+func NewPostgresTransactionRepository(pool *pgxpool.Pool) ports.Repository[db.Transaction, int] {
+	return newPostgresTableRepository[db.Transaction](pool, transactionsSchema)
+}
+
+func NewPostgresCategoryRepository(pool *pgxpool.Pool) ports.Repository[db.Category, int] {
+	return newPostgresTableRepository[db.Category](pool, categoriesSchema)
+}
+
+// pgTableColumns are the fields a table can be filtered and ordered by: all of its columns.
+// Text columns are compared as text, which also covers the enum types.
+func pgTableColumns(table *tableSchema) map[string]pgColumn {
+	columns := make(map[string]pgColumn, len(table.columns))
+	for _, column := range table.columns {
+		name := pgx.Identifier{column.name}.Sanitize()
+		switch column.kind {
+		case columnInteger:
+			columns[column.name] = pgColumn{name, "bigint"}
+		case columnDecimal:
+			columns[column.name] = pgColumn{name, "numeric"}
+		case columnTime:
+			columns[column.name] = pgColumn{name, "timestamptz"}
+		default:
+			columns[column.name] = pgColumn{name + "::text", "text"}
+		}
+	}
+	return columns
+}
+
+// Create inserts the model. The id is always generated, and null values and zero
+// times are left out so the column defaults apply.
+func (repo *PostgresTableRepository[T]) Create(model *T) (*T, error) {
+	values, err := columnValues(repo.table, model)
+	if err != nil {
+		return nil, err
+	}
+
+	record := map[string]any{}
+	var columns []string
+	for _, column := range repo.table.columns {
+		value, ok := values[column.name]
+		if column.name == "id" || !ok || value == nil || value == storedZeroTime {
+			continue
+		}
+		record[column.name] = storedJSON(column, value)
+		columns = append(columns, pgx.Identifier{column.name}.Sanitize())
+	}
+
+	table := repo.tableName()
+	if len(columns) == 0 {
+		return repo.one(fmt.Sprintf("INSERT INTO %s DEFAULT VALUES RETURNING to_jsonb(%s.*)", table, table))
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+	list := strings.Join(columns, ", ")
+	return repo.one(fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM jsonb_populate_record(NULL::%s, $1::jsonb) RETURNING to_jsonb(%s.*)",
+		table, list, list, table, table), string(data))
+}
+
+func (repo *PostgresTableRepository[T]) Delete(id int) error {
+	_, err := repo.db.Exec(context.Background(), "DELETE FROM "+repo.tableName()+" WHERE id = $1", id)
+	return err
+}
+
+func (repo *PostgresTableRepository[T]) FindByField(field string, value any) (*T, error) {
+	limit := 1
+	page, err := repo.Query("*", ports.QueryOptions{
+		Filters: []ports.Filter{{Field: field, Operator: ports.OpEq, Value: value}},
+		OrderBy: []ports.OrderBy{{Field: "id", Ascending: true}},
+		Limit:   &limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(page.Items) == 0 {
+		return nil, types.ErrNotFound
+	}
+	return &page.Items[0], nil
+}
+
+func (repo *PostgresTableRepository[T]) GetAll() ([]T, error) {
+	page, err := repo.Query("*", ports.QueryOptions{OrderBy: []ports.OrderBy{{Field: "id", Ascending: true}}})
+	return page.Items, err
+}
+
+func (repo *PostgresTableRepository[T]) GetByID(id int) (*T, error) {
+	table := repo.tableName()
+	return repo.one(fmt.Sprintf("SELECT to_jsonb(%s.*) FROM %s WHERE id = $1", table, table), id)
+}
+
+// Update writes the writable columns of the model that its JSON carries
+func (repo *PostgresTableRepository[T]) Update(model *T) (*T, error) {
+	values, err := columnValues(repo.table, model)
+	if err != nil {
+		return nil, err
+	}
+	id, ok := values["id"]
+	if !ok {
+		return nil, fmt.Errorf("%s model without id", repo.table.name)
+	}
+
+	writable := repo.table.writable
+	if writable == nil {
+		for _, column := range repo.table.columns {
+			writable = append(writable, column.name)
+		}
+	}
+
+	record := map[string]any{}
+	var assignments []string
+	for _, name := range writable {
+		value, ok := values[name]
+		if name == "id" || !ok || value == storedZeroTime {
+			continue
+		}
+		column, _ := repo.table.column(name)
+		record[name] = storedJSON(column, value)
+		quoted := pgx.Identifier{name}.Sanitize()
+		assignments = append(assignments, quoted+" = r."+quoted)
+	}
+	if len(assignments) == 0 {
+		return repo.GetByID(int(id.(int64)))
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+
+	table := repo.tableName()
+	return repo.one(fmt.Sprintf("UPDATE %s AS t SET %s FROM jsonb_populate_record(NULL::%s, $1::jsonb) AS r WHERE t.id = $2 RETURNING to_jsonb(t.*)",
+		table, strings.Join(assignments, ", "), table), string(data), id)
+}
+
+// Query returns a page of the models matching args. Rows always come with every column and
+// no embedded relations, whatever fields selects.
+func (repo *PostgresTableRepository[T]) Query(fields string, args ports.QueryOptions) (ports.Page[T], error) {
+	plan, err := newQueryPlan(args)
+	if err != nil {
+		return ports.Page[T]{}, err
+	}
+	where, params, err := pgWhere(repo.columns, plan.filters, nil)
+	if err != nil {
+		return ports.Page[T]{}, err
+	}
+	order, err := pgOrderBy(repo.columns, plan.orders)
+	if err != nil {
+		return ports.Page[T]{}, err
+	}
+
+	table := repo.tableName()
+	rows, err := repo.db.Query(context.Background(),
+		fmt.Sprintf("SELECT to_jsonb(%s.*) FROM %s", table, table)+where+order+pgPage(plan), params...)
+	if err != nil {
+		return ports.Page[T]{}, err
+	}
+	defer rows.Close()
+
+	models := []T{}
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return ports.Page[T]{}, err
+		}
+		model, err := rowModel[T](repo.table, data)
+		if err != nil {
+			return ports.Page[T]{}, fmt.Errorf("error reading %s row: %w", repo.table.name, err)
+		}
+		models = append(models, *model)
+	}
+	if err := rows.Err(); err != nil {
+		return ports.Page[T]{}, err
+	}
+
+	total, err := pgCount(repo.db, plan, " FROM "+table, where, params)
+	if err != nil {
+		return ports.Page[T]{}, err
+	}
+	return queryPage(plan, models, total), nil
+}
+
+// one runs a query that returns a single row of the table as JSON
+func (repo *PostgresTableRepository[T]) one(query string, args ...any) (*T, error) {
+	var data []byte
+	if err := repo.db.QueryRow(context.Background(), query, args...).Scan(&data); err != nil {
+		return nil, pgNotFound(err)
+	}
+	return rowModel[T](repo.table, data)
+}
+
+func (repo *PostgresTableRepository[T]) tableName() string {
+	return pgx.Identifier{repo.table.name}.Sanitize()
+}
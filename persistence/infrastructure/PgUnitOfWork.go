@@ -0,0 +1,41 @@
+package infrastructure
+
+import (
+	"context"
+
+	"Financial/Core/Models/db"
+	"Financial/Core/ports"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresUnitOfWork runs a unit of work inside a transaction on a connection of the pool.
+// Reads inside fn see the writes made before them.
+type PostgresUnitOfWork struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresUnitOfWork(pool *pgxpool.Pool) ports.UnitOfWork {
+	return &PostgresUnitOfWork{pool: pool}
+}
+
+func (uow *PostgresUnitOfWork) Do(fn func(repos ports.UnitOfWorkRepositories) error) error {
+	ctx := context.Background()
+	tx, err := uow.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	err = fn(ports.UnitOfWorkRepositories{
+		Users:        &PostgresUserRepository{db: tx},
+		Wallets:      &PostgresWalletRepository{db: tx},
+		Transactions: newPostgresTableRepository[db.Transaction](tx, transactionsSchema),
+		Categories:   newPostgresTableRepository[db.Category](tx, categoriesSchema),
+	})
+	if err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,162 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"Financial/Core/Models/db"
+	contracts "Financial/Core/ports"
+	"Financial/Core/types"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// pgExecutor is what the Postgres repositories need from a connection, so they work
+// the same on a pool and inside a transaction
+type pgExecutor interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+const pgUserReturning = "id, nick_name, first_name, last_name, email, status::text, role, created_at, password"
+
+// pgUserColumns are the fields users can be filtered and ordered by
+var pgUserColumns = map[string]pgColumn{
+	"id":         {"id", "integer"},
+	"nick_name":  {"nick_name", "text"},
+	"first_name": {"first_name", "text"},
+	"last_name":  {"last_name", "text"},
+	"email":      {"email", "text"},
+	"status":     {"status", "account_status"},
+	"role":       {"role", "text"},
+	"created_at": {"created_at", "timestamp"},
+	"updated_at": {"updated_at", "timestamp"},
+}
+
+// PostgresUserRepository stores users through a direct Postgres connection
+type PostgresUserRepository struct {
+	db pgExecutor
+}
+
+func NewPostgresUserRepository(pool *pgxpool.Pool) contracts.Repository[db.User, int] {
+	return &PostgresUserRepository{db: pool}
+}
+
+// scanPgUser reads a row selected with pgUserReturning
+func scanPgUser(row pgx.Row) (*db.User, error) {
+	var user db.User
+	var status, role string
+	err := row.Scan(&user.ID, &user.Nickname, &user.FirstName, &user.Lastname, &user.Email,
+		&status, &role, &user.CreatedAt, &user.Password)
+	if err != nil {
+		return nil, pgNotFound(err)
+	}
+	user.Status = types.AccountStatus(status)
+	user.Role = types.Role(role)
+	return &user, nil
+}
+
+// pgTimestamp leaves a zero time to the column default; the TIMESTAMP columns hold UTC
+func pgTimestamp(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	t = t.UTC()
+	return &t
+}
+
+func (repo *PostgresUserRepository) Create(model *db.User) (*db.User, error) {
+	row := repo.db.QueryRow(context.Background(),
+		`INSERT INTO users (nick_name, first_name, last_name, email, status, role, created_at, password)
+		VALUES ($1, $2, $3, $4, $5::text::account_status, $6, COALESCE($7::timestamp, LOCALTIMESTAMP(3)), $8)
+		RETURNING `+pgUserReturning,
+		model.Nickname, model.FirstName, model.Lastname, model.Email, string(model.Status),
+		string(model.EffectiveRole()), pgTimestamp(model.CreatedAt), model.Password)
+	return scanPgUser(row)
+}
+
+func (repo *PostgresUserRepository) Delete(id int) error {
+	_, err := repo.db.Exec(context.Background(), "DELETE FROM users WHERE id = $1", id)
+	return err
+}
+
+func (repo *PostgresUserRepository) FindByField(field string, value any) (*db.User, error) {
+	where, args, err := pgFieldWhere(pgUserColumns, field, value)
+	if err != nil {
+		return nil, err
+	}
+	row := repo.db.QueryRow(context.Background(),
+		"SELECT "+pgUserReturning+" FROM users"+where+" ORDER BY id LIMIT 1", args...)
+	return scanPgUser(row)
+}
+
+func (repo *PostgresUserRepository) GetAll() ([]db.User, error) {
+	return repo.list("SELECT " + pgUserReturning + " FROM users ORDER BY id")
+}
+
+func (repo *PostgresUserRepository) GetByID(id int) (*db.User, error) {
+	row := repo.db.QueryRow(context.Background(), "SELECT "+pgUserReturning+" FROM users WHERE id = $1", id)
+	return scanPgUser(row)
+}
+
+// Update writes every column but the creation date; an empty role keeps the stored one
+func (repo *PostgresUserRepository) Update(model *db.User) (*db.User, error) {
+	row := repo.db.QueryRow(context.Background(),
+		`UPDATE users SET nick_name = $2, first_name = $3, last_name = $4, email = $5,
+			status = $6::text::account_status, role = COALESCE(NULLIF($7, ''), role), password = $8,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING `+pgUserReturning,
+		model.ID, model.Nickname, model.FirstName, model.Lastname, model.Email, string(model.Status),
+		string(model.Role), model.Password)
+	return scanPgUser(row)
+}
+
+// Query returns a page of the users matching args. Rows always come with every column,
+// whatever fields selects.
+func (repo *PostgresUserRepository) Query(fields string, args contracts.QueryOptions) (contracts.Page[db.User], error) {
+	plan, err := newQueryPlan(args)
+	if err != nil {
+		return contracts.Page[db.User]{}, err
+	}
+	where, params, err := pgWhere(pgUserColumns, plan.filters, nil)
+	if err != nil {
+		return contracts.Page[db.User]{}, err
+	}
+	order, err := pgOrderBy(pgUserColumns, plan.orders)
+	if err != nil {
+		return contracts.Page[db.User]{}, err
+	}
+
+	users, err := repo.list("SELECT "+pgUserReturning+" FROM users"+where+order+pgPage(plan), params...)
+	if err != nil {
+		return contracts.Page[db.User]{}, err
+	}
+	total, err := pgCount(repo.db, plan, " FROM users", where, params)
+	if err != nil {
+		return contracts.Page[db.User]{}, err
+	}
+	return queryPage(plan, users, total), nil
+}
+
+func (repo *PostgresUserRepository) list(sql string, args ...any) ([]db.User, error) {
+	rows, err := repo.db.Query(context.Background(), sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []db.User{}
+	for rows.Next() {
+		user, err := scanPgUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error reading user: %w", err)
+		}
+		users = append(users, *user)
+	}
+	return users, rows.Err()
+}
@@ -0,0 +1,253 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"Financial/Core/Models/db"
+	"Financial/Core/ports"
+	"Financial/Core/types"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	pgWalletReturning = "id, name, type::text, balance::text, currency, user_id"
+	pgWalletFields    = "w.id, w.name, w.type::text, w.balance::text, w.currency, w.user_id"
+	pgOwnerFields     = "u.id, u.nick_name, u.first_name, u.last_name, u.email, u.status::text, u.role, u.created_at"
+
+	// pgWalletFrom joins the owner so queries can filter on it and embed it
+	pgWalletFrom = " FROM wallets w JOIN users u ON u.id = w.user_id"
+)
+
+// pgWalletColumns are the fields wallets can be filtered and ordered by, including the
+// columns of the owner that PostgREST exposes through the users embed
+var pgWalletColumns = map[string]pgColumn{
+	"id":              {"w.id", "integer"},
+	"name":            {"w.name", "text"},
+	"type":            {"w.type", `"WalletType"`},
+	"balance":         {"w.balance", "numeric"},
+	"currency":        {"w.currency", "text"},
+	"user_id":         {"w.user_id", "integer"},
+	"users.id":        {"u.id", "integer"},
+	"users.email":     {"u.email", "text"},
+	"users.nick_name": {"u.nick_name", "text"},
+	"user.id":         {"u.id", "integer"},
+	"user.email":      {"u.email", "text"},
+	"user.nick_name":  {"u.nick_name", "text"},
+}
+
+// PostgresWalletRepository stores wallets through a direct Postgres connection
+type PostgresWalletRepository struct {
+	db pgExecutor
+}
+
+func NewPostgresWalletRepository(pool *pgxpool.Pool) ports.Repository[db.Wallet, int] {
+	return &PostgresWalletRepository{db: pool}
+}
+
+// scanPgWallet reads a row selected with pgWalletReturning, plus any extra destinations
+func scanPgWallet(row pgx.Row, extra ...any) (*db.Wallet, error) {
+	var wallet db.Wallet
+	var walletType, balance string
+	dest := append([]any{&wallet.ID, &wallet.Name, &walletType, &balance, &wallet.Currency, &wallet.UserID}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return nil, pgNotFound(err)
+	}
+
+	money, err := types.ParseMoney(balance, wallet.Currency)
+	if err != nil {
+		return nil, err
+	}
+	wallet.Type = types.WalletType(walletType)
+	wallet.Balance = money
+	return &wallet, nil
+}
+
+// Create inserts the wallet; an empty currency takes the column default
+func (repo *PostgresWalletRepository) Create(model *db.Wallet) (*db.Wallet, error) {
+	row := repo.db.QueryRow(context.Background(),
+		`INSERT INTO wallets (name, type, balance, currency, user_id)
+		VALUES ($1, $2::text::"WalletType", $3::text::numeric, COALESCE(NULLIF($4, ''), 'USD'), $5)
+		RETURNING `+pgWalletReturning,
+		model.Name, string(model.Type), model.Balance.Decimal(), model.Currency, model.UserID)
+	return scanPgWallet(row)
+}
+
+func (repo *PostgresWalletRepository) Delete(id int) error {
+	_, err := repo.db.Exec(context.Background(), "DELETE FROM wallets WHERE id = $1", id)
+	return err
+}
+
+func (repo *PostgresWalletRepository) FindByField(field string, value any) (*db.Wallet, error) {
+	where, args, err := pgFieldWhere(pgWalletColumns, field, value)
+	if err != nil {
+		return nil, err
+	}
+	row := repo.db.QueryRow(context.Background(),
+		"SELECT "+pgWalletFields+pgWalletFrom+where+" ORDER BY w.id LIMIT 1", args...)
+	return scanPgWallet(row)
+}
+
+func (repo *PostgresWalletRepository) GetAll() ([]db.Wallet, error) {
+	return repo.list("SELECT " + pgWalletReturning + " FROM wallets ORDER BY id")
+}
+
+func (repo *PostgresWalletRepository) GetByID(id int) (*db.Wallet, error) {
+	row := repo.db.QueryRow(context.Background(), "SELECT "+pgWalletReturning+" FROM wallets WHERE id = $1", id)
+	return scanPgWallet(row)
+}
+
+// GetForUpdate reads the wallet with FOR UPDATE, so inside a PostgresUnitOfWork other
+// transactions wait to write it until this one ends
+func (repo *PostgresWalletRepository) GetForUpdate(id int) (*db.Wallet, error) {
+	row := repo.db.QueryRow(context.Background(), "SELECT "+pgWalletReturning+" FROM wallets WHERE id = $1 FOR UPDATE", id)
+	return scanPgWallet(row)
+}
+
+// Update writes the columns of a wallet that can change: name, type and balance
+func (repo *PostgresWalletRepository) Update(model *db.Wallet) (*db.Wallet, error) {
+	row := repo.db.QueryRow(context.Background(),
+		`UPDATE wallets SET name = $2, type = $3::text::"WalletType", balance = $4::text::numeric
+		WHERE id = $1
+		RETURNING `+pgWalletReturning,
+		model.ID, model.Name, string(model.Type), model.Balance.Decimal())
+	return scanPgWallet(row)
+}
+
+// Query returns a page of the wallets matching args. Wallet rows always come with every
+// column; the PostgREST embeds "user:users(...)" and "transactions(...)" in fields fill
+// Wallet.User (without the password) and Wallet.Transactions.
+func (repo *PostgresWalletRepository) Query(fields string, args ports.QueryOptions) (ports.Page[db.Wallet], error) {
+	plan, err := newQueryPlan(args)
+	if err != nil {
+		return ports.Page[db.Wallet]{}, err
+	}
+	where, params, err := pgWhere(pgWalletColumns, plan.filters, nil)
+	if err != nil {
+		return ports.Page[db.Wallet]{}, err
+	}
+	order, err := pgOrderBy(pgWalletColumns, plan.orders)
+	if err != nil {
+		return ports.Page[db.Wallet]{}, err
+	}
+
+	wallets, err := repo.queryWallets(fields, "SELECT "+pgWalletFields+", "+pgOwnerFields+pgWalletFrom+where+order+pgPage(plan), params)
+	if err != nil {
+		return ports.Page[db.Wallet]{}, err
+	}
+	total, err := pgCount(repo.db, plan, pgWalletFrom, where, params)
+	if err != nil {
+		return ports.Page[db.Wallet]{}, err
+	}
+	return queryPage(plan, wallets, total), nil
+}
+
+// queryWallets reads wallets selected with their owner and fills the embeds fields asks for
+func (repo *PostgresWalletRepository) queryWallets(fields string, sql string, params []any) ([]db.Wallet, error) {
+	rows, err := repo.db.Query(context.Background(), sql, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	embeds := pgEmbeds(fields)
+	wallets := []db.Wallet{}
+	for rows.Next() {
+		var owner db.User
+		var status, role string
+		wallet, err := scanPgWallet(rows, &owner.ID, &owner.Nickname, &owner.FirstName, &owner.Lastname,
+			&owner.Email, &status, &role, &owner.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("error reading wallet: %w", err)
+		}
+		if embeds["user"] || embeds["users"] {
+			owner.Status = types.AccountStatus(status)
+			owner.Role = types.Role(role)
+			wallet.User = &owner
+		}
+		wallets = append(wallets, *wallet)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if embeds["transactions"] && len(wallets) > 0 {
+		if err := repo.loadTransactions(wallets); err != nil {
+			return nil, err
+		}
+	}
+	return wallets, nil
+}
+
+// loadTransactions fills the ledger of every wallet, oldest movement first
+func (repo *PostgresWalletRepository) loadTransactions(wallets []db.Wallet) error {
+	index := make(map[int]int, len(wallets))
+	ids := make([]int32, len(wallets))
+	for i, wallet := range wallets {
+		index[wallet.ID] = i
+		ids[i] = int32(wallet.ID)
+		wallets[i].Transactions = []db.Transaction{}
+	}
+
+	rows, err := repo.db.Query(context.Background(),
+		`SELECT id, wallet_id, type::text, amount::text, description, category_id, recurring_id, occurrence_date, created_at
+		FROM transactions WHERE wallet_id = ANY($1) ORDER BY id`, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var transaction db.Transaction
+		var transactionType, amount string
+		var categoryID, recurringID *int32
+		var occurrence *time.Time
+		if err := rows.Scan(&transaction.ID, &transaction.WalletID, &transactionType, &amount, &transaction.Description,
+			&categoryID, &recurringID, &occurrence, &transaction.CreatedAt); err != nil {
+			return fmt.Errorf("error reading transaction: %w", err)
+		}
+
+		wallet := &wallets[index[transaction.WalletID]]
+		money, err := types.ParseMoney(amount, wallet.Currency)
+		if err != nil {
+			return err
+		}
+		transaction.Type = types.TransactionType(transactionType)
+		transaction.Amount = money
+		transaction.CategoryID = pgInt(categoryID)
+		transaction.RecurringID = pgInt(recurringID)
+		transaction.OccurrenceDate = occurrence
+		wallet.Transactions = append(wallet.Transactions, transaction)
+	}
+	return rows.Err()
+}
+
+// pgInt converts a nullable integer column
+func pgInt(value *int32) *int {
+	if value == nil {
+		return nil
+	}
+	v := int(*value)
+	return &v
+}
+
+func (repo *PostgresWalletRepository) list(sql string, args ...any) ([]db.Wallet, error) {
+	rows, err := repo.db.Query(context.Background(), sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	wallets := []db.Wallet{}
+	for rows.Next() {
+		wallet, err := scanPgWallet(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error reading wallet: %w", err)
+		}
+		wallets = append(wallets, *wallet)
+	}
+	return wallets, rows.Err()
+}
@@ -0,0 +1,145 @@
+package infrastructure
+
+import (
+	"Financial/Core/Models/db"
+	"Financial/Core/ports"
+	"Financial/Core/types"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/supabase-community/supabase-go"
+)
+
+const recurringTable = "recurring_transactions"
+
+type SupaBaseRecurringTransactionRepository struct {
+	client *supabase.Client
+}
+
+func NewSupaBaseRecurringTransactionRepository(client *supabase.Client) ports.Repository[db.RecurringTransaction, int] {
+	return &SupaBaseRecurringTransactionRepository{client: client}
+}
+
+// CreateRecurringTransaction is a helper struct that matches the database schema
+type CreateRecurringTransaction struct {
+	UserID      int                   `json:"user_id"`
+	WalletID    int                   `json:"wallet_id"`
+	Type        types.TransactionType `json:"type"`
+	Amount      types.Money           `json:"amount"`
+	Description string                `json:"description"`
+	CategoryID  *int                  `json:"category_id"`
+	Frequency   types.Frequency       `json:"frequency"`
+	Interval    int                   `json:"interval"`
+	ByMonthDay  []int                 `json:"by_month_day"`
+	StartDate   time.Time             `json:"start_date"`
+	EndDate     *time.Time            `json:"end_date"`
+	Status      types.RecurringStatus `json:"status"`
+	NextRunAt   *time.Time            `json:"next_run_at"`
+}
+
+// newCreateRecurringTransaction maps the model to the columns, leaving out the ID
+func newCreateRecurringTransaction(model *db.RecurringTransaction) CreateRecurringTransaction {
+	return CreateRecurringTransaction{
+		UserID:      model.UserID,
+		WalletID:    model.WalletID,
+		Type:        model.Type,
+		Amount:      model.Amount,
+		Description: model.Description,
+		CategoryID:  model.CategoryID,
+		Frequency:   model.Frequency,
+		Interval:    model.Interval,
+		ByMonthDay:  model.ByMonthDay,
+		StartDate:   model.StartDate,
+		EndDate:     model.EndDate,
+		Status:      model.Status,
+		NextRunAt:   model.NextRunAt,
+	}
+}
+
+func (repo *SupaBaseRecurringTransactionRepository) Create(model *db.RecurringTransaction) (*db.RecurringTransaction, error) {
+	newSeries := newCreateRecurringTransaction(model)
+
+	var result db.RecurringTransaction
+	_, err := repo.client.From(recurringTable).
+		Insert(newSeries, false, "", "representation", "").
+		Single().
+		ExecuteTo(&result)
+
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (repo *SupaBaseRecurringTransactionRepository) Delete(id int) error {
+	_, _, err := repo.client.From(recurringTable).Delete("", "").
+		Eq("id", strconv.Itoa(id)).Execute()
+	return err
+}
+
+func (repo *SupaBaseRecurringTransactionRepository) FindByField(field string, value any) (*db.RecurringTransaction, error) {
+	var results []db.RecurringTransaction
+
+	var filterValue string
+	switch v := value.(type) {
+	case string:
+		filterValue = v
+	case int, int32, int64, uint, uint32, uint64:
+		filterValue = fmt.Sprintf("%d", v)
+	case float32, float64:
+		filterValue = fmt.Sprintf("%f", v)
+	case bool:
+		filterValue = strconv.FormatBool(v)
+	default:
+		return nil, fmt.Errorf("unsupported type for field filtering: %T", value)
+	}
+
+	_, err := repo.client.From(recurringTable).
+		Select("*", "exact", false).
+		Filter(field, "eq", filterValue).
+		ExecuteTo(&results)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(results) == 0 {
+		return nil, types.ErrNotFound
+	}
+
+	return &results[0], nil
+}
+
+func (repo *SupaBaseRecurringTransactionRepository) GetAll() ([]db.RecurringTransaction, error) {
+	var series []db.RecurringTransaction
+	_, err := repo.client.From(recurringTable).Select("*", "exact", false).
+		ExecuteTo(&series)
+	if err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+func (repo *SupaBaseRecurringTransactionRepository) GetByID(id int) (*db.RecurringTransaction, error) {
+	return repo.FindByField("id", id)
+}
+
+func (repo *SupaBaseRecurringTransactionRepository) Update(model *db.RecurringTransaction) (*db.RecurringTransaction, error) {
+	var result []db.RecurringTransaction
+	_, err := repo.client.From(recurringTable).Update(newCreateRecurringTransaction(model), "representation", "").Eq("id", strconv.Itoa(model.ID)).
+		ExecuteTo(&result)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, types.ErrNotFound
+	}
+	return &result[0], nil
+}
+
+// Query returns a page of the rows matching args; see ports.QueryOptions for the filters,
+// paging and counts it supports.
+func (repo *SupaBaseRecurringTransactionRepository) Query(fields string, args ports.QueryOptions) (ports.Page[db.RecurringTransaction], error) {
+	return supabaseQuery[db.RecurringTransaction](repo.client, recurringTable, fields, args)
+}
@@ -0,0 +1,62 @@
+package infrastructure
+
+import (
+	"Financial/Core/ports"
+	"bytes"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// SMTPConfig holds the relay used to deliver emails
+type SMTPConfig struct {
+	Host     string // Relay host name
+	Port     string // Relay port, usually 587 (STARTTLS) or 25
+	Username string // Login for PLAIN auth; empty for relays that don't require auth
+	Password string
+	From     string // Sender address of every message
+}
+
+type SMTPMailer struct {
+	config SMTPConfig
+}
+
+func NewSMTPMailer(config SMTPConfig) ports.Mailer {
+	return &SMTPMailer{config: config}
+}
+
+// Send delivers the message through the relay; net/smtp upgrades to TLS when the relay offers STARTTLS
+func (m *SMTPMailer) Send(message ports.MailMessage) error {
+	var auth smtp.Auth
+	if m.config.Username != "" {
+		auth = smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)
+	}
+
+	addr := net.JoinHostPort(m.config.Host, m.config.Port)
+	if err := smtp.SendMail(addr, auth, m.config.From, []string{headerValue(message.To)}, formatMail(m.config.From, message)); err != nil {
+		return fmt.Errorf("error sending mail to %s: %w", message.To, err)
+	}
+	return nil
+}
+
+// formatMail renders the message as RFC 5322 text
+func formatMail(from string, message ports.MailMessage) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", headerValue(from))
+	fmt.Fprintf(&buf, "To: %s\r\n", headerValue(message.To))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", headerValue(message.Subject)))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(strings.ReplaceAll(strings.ReplaceAll(message.Body, "\r\n", "\n"), "\n", "\r\n"))
+	return buf.Bytes()
+}
+
+// headerValue drops line breaks so a value can't inject extra headers
+func headerValue(value string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(value)
+}
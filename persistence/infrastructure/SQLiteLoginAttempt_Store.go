@@ -0,0 +1,79 @@
+package infrastructure
+
+import (
+	"database/sql"
+	"time"
+
+	"Financial/Core/Models/db"
+	"Financial/Core/ports"
+	"Financial/Core/types"
+)
+
+// SQLiteLoginAttemptStore keeps the failed login counters in the login_attempts table of a SQLite database
+type SQLiteLoginAttemptStore struct {
+	db *sql.DB
+}
+
+func NewSQLiteLoginAttemptStore(database *sql.DB) ports.LoginAttemptStore {
+	return &SQLiteLoginAttemptStore{db: database}
+}
+
+const sqliteLoginAttemptColumns = "key, failures, last_failure_at, locked_until, previous_status"
+
+func (store *SQLiteLoginAttemptStore) Get(key string) (*db.LoginAttempt, error) {
+	return scanSQLiteLoginAttempt(store.db.QueryRow(
+		"SELECT "+sqliteLoginAttemptColumns+" FROM "+loginAttemptTable+" WHERE key = ?", key))
+}
+
+// RecordFailure counts the failure in a single statement, like the record_login_failure Postgres function
+func (store *SQLiteLoginAttemptStore) RecordFailure(key string, at time.Time, window time.Duration) (*db.LoginAttempt, error) {
+	return scanSQLiteLoginAttempt(store.db.QueryRow(`INSERT INTO `+loginAttemptTable+` (key, failures, last_failure_at)
+		VALUES (?, 1, ?)
+		ON CONFLICT (key) DO UPDATE SET
+			failures = CASE WHEN last_failure_at <= ? THEN 1 ELSE failures + 1 END,
+			last_failure_at = excluded.last_failure_at
+		RETURNING `+sqliteLoginAttemptColumns,
+		key, at.UTC().Format(storedTimeLayout), at.Add(-window).UTC().Format(storedTimeLayout)))
+}
+
+func (store *SQLiteLoginAttemptStore) Lock(key string, until time.Time, previousStatus types.AccountStatus) error {
+	result, err := store.db.Exec("UPDATE "+loginAttemptTable+" SET locked_until = ?, previous_status = ? WHERE key = ?",
+		until.UTC().Format(storedTimeLayout), string(previousStatus), key)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return types.ErrNotFound
+	}
+	return nil
+}
+
+func (store *SQLiteLoginAttemptStore) Reset(key string) error {
+	_, err := store.db.Exec("DELETE FROM "+loginAttemptTable+" WHERE key = ?", key)
+	return err
+}
+
+func scanSQLiteLoginAttempt(row *sql.Row) (*db.LoginAttempt, error) {
+	var attempt db.LoginAttempt
+	var lastFailureAt string
+	var lockedUntil, previousStatus sql.NullString
+	if err := row.Scan(&attempt.Key, &attempt.Failures, &lastFailureAt, &lockedUntil, &previousStatus); err != nil {
+		return nil, sqliteNotFound(err)
+	}
+
+	var err error
+	if attempt.LastFailureAt, err = time.Parse(time.RFC3339Nano, lastFailureAt); err != nil {
+		return nil, err
+	}
+	if lockedUntil.Valid {
+		until, err := time.Parse(time.RFC3339Nano, lockedUntil.String)
+		if err != nil {
+			return nil, err
+		}
+		attempt.LockedUntil = &until
+	}
+	attempt.PreviousStatus = types.AccountStatus(previousStatus.String)
+	return &attempt, nil
+}
@@ -0,0 +1,38 @@
+package infrastructure
+
+import (
+	"database/sql"
+
+	"Financial/Core/Models/db"
+	"Financial/Core/ports"
+)
+
+// SQLiteTransferRepository applies transfers inside a SQLite transaction, following the
+// same rules as the transfer_between_wallets Postgres function
+type SQLiteTransferRepository struct {
+	db *sql.DB
+}
+
+func NewSQLiteTransferRepository(database *sql.DB) ports.TransferRepository {
+	return &SQLiteTransferRepository{db: database}
+}
+
+func (repo *SQLiteTransferRepository) Transfer(transfer *db.Transfer) (*db.Transfer, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	result, err := transferBetweenWallets(
+		newSQLiteRepository[db.Wallet](tx, walletsSchema),
+		newSQLiteRepository[db.Transaction](tx, transactionsSchema),
+		transfer)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,37 @@
+package infrastructure
+
+import (
+	"database/sql"
+
+	"Financial/Core/Models/db"
+	"Financial/Core/ports"
+)
+
+// SQLiteUnitOfWork runs a unit of work inside a SQLite transaction. The database has a single
+// connection, so fn must not use repositories other than the ones it receives.
+type SQLiteUnitOfWork struct {
+	db *sql.DB
+}
+
+func NewSQLiteUnitOfWork(database *sql.DB) ports.UnitOfWork {
+	return &SQLiteUnitOfWork{db: database}
+}
+
+func (uow *SQLiteUnitOfWork) Do(fn func(repos ports.UnitOfWorkRepositories) error) error {
+	tx, err := uow.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = fn(ports.UnitOfWorkRepositories{
+		Users:        newSQLiteRepository[db.User](tx, usersSchema),
+		Wallets:      newSQLiteRepository[db.Wallet](tx, walletsSchema),
+		Transactions: newSQLiteRepository[db.Transaction](tx, transactionsSchema),
+		Categories:   newSQLiteRepository[db.Category](tx, categoriesSchema),
+	})
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,320 @@
+package infrastructure
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"Financial/Core/Models/db"
+	"Financial/Core/ports"
+	"Financial/Core/types"
+)
+
+// SQLiteRepository stores the models of one table in SQLite. It works for any model whose
+// JSON names are the column names, which is also what the Supabase repositories rely on.
+type SQLiteRepository[T any] struct {
+	db    sqliteExecutor
+	table *tableSchema
+}
+
+func newSQLiteRepository[T any](database sqliteExecutor, table *tableSchema) *SQLiteRepository[T] {
+	return &SQLiteRepository[T]{db: database, table: table}
+}
+
+func NewSQLiteUserRepository(database *sql.DB) ports.Repository[db.User, int] {
+	return newSQLiteRepository[db.User](database, usersSchema)
+}
+
+func NewSQLiteWalletRepository(database *sql.DB) ports.Repository[db.Wallet, int] {
+	return newSQLiteRepository[db.Wallet](database, walletsSchema)
+}
+
+func NewSQLiteTransactionRepository(database *sql.DB) ports.Repository[db.Transaction, int] {
+	return newSQLiteRepository[db.Transaction](database, transactionsSchema)
+}
+
+func NewSQLiteCategoryRepository(database *sql.DB) ports.Repository[db.Category, int] {
+	return newSQLiteRepository[db.Category](database, categoriesSchema)
+}
+
+func NewSQLiteBudgetRepository(database *sql.DB) ports.Repository[db.Budget, int] {
+	return newSQLiteRepository[db.Budget](database, budgetsSchema)
+}
+
+func NewSQLiteRecurringTransactionRepository(database *sql.DB) ports.Repository[db.RecurringTransaction, int] {
+	return newSQLiteRepository[db.RecurringTransaction](database, recurringTransactionsSchema)
+}
+
+// SQLiteSessionRepository adds the conditional rotation of refresh tokens to the sessions table
+type SQLiteSessionRepository struct {
+	*SQLiteRepository[db.Session]
+}
+
+func NewSQLiteSessionRepository(database *sql.DB) ports.SessionRepository {
+	return &SQLiteSessionRepository{newSQLiteRepository[db.Session](database, sessionsSchema)}
+}
+
+func (repo *SQLiteSessionRepository) RotateRefresh(session *db.Session, previousHash string) (*db.Session, error) {
+	return repo.updateWhere(session, "refresh_token_hash", previousHash)
+}
+
+func NewSQLitePasswordResetRepository(database *sql.DB) ports.Repository[db.PasswordReset, int] {
+	return newSQLiteRepository[db.PasswordReset](database, passwordResetsSchema)
+}
+
+func NewSQLiteTwoFactorRepository(database *sql.DB) ports.Repository[db.TwoFactor, int] {
+	return newSQLiteRepository[db.TwoFactor](database, twoFactorsSchema)
+}
+
+func NewSQLiteAPIKeyRepository(database *sql.DB) ports.Repository[db.APIKey, int] {
+	return newSQLiteRepository[db.APIKey](database, apiKeysSchema)
+}
+
+func NewSQLiteUserIdentityRepository(database *sql.DB) ports.Repository[db.UserIdentity, int] {
+	return newSQLiteRepository[db.UserIdentity](database, userIdentitiesSchema)
+}
+
+func NewSQLiteOIDCLoginRepository(database *sql.DB) ports.Repository[db.OIDCLogin, int] {
+	return newSQLiteRepository[db.OIDCLogin](database, oidcLoginsSchema)
+}
+
+func NewSQLiteAuditRepository(database *sql.DB) ports.Repository[db.AuditEntry, int] {
+	return newSQLiteRepository[db.AuditEntry](database, auditLogSchema)
+}
+
+// Create inserts the model. The id is always generated, and null values and zero
+// times are left out so the column defaults apply.
+func (repo *SQLiteRepository[T]) Create(model *T) (*T, error) {
+	values, err := columnValues(repo.table, model)
+	if err != nil {
+		return nil, err
+	}
+
+	var columns, placeholders []string
+	var args []any
+	for _, column := range repo.table.columns {
+		value, ok := values[column.name]
+		if column.name == "id" || !ok || value == nil || value == storedZeroTime {
+			continue
+		}
+		columns = append(columns, column.name)
+		placeholders = append(placeholders, "?")
+		args = append(args, value)
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) RETURNING %s", repo.table.name,
+		strings.Join(columns, ", "), strings.Join(placeholders, ", "), repo.table.columnList())
+	if len(columns) == 0 {
+		query = fmt.Sprintf("INSERT INTO %s DEFAULT VALUES RETURNING %s", repo.table.name, repo.table.columnList())
+	}
+	return repo.one(query, args...)
+}
+
+func (repo *SQLiteRepository[T]) Delete(id int) error {
+	_, err := repo.db.Exec("DELETE FROM "+repo.table.name+" WHERE id = ?", id)
+	return err
+}
+
+func (repo *SQLiteRepository[T]) FindByField(field string, value any) (*T, error) {
+	limit := 1
+	page, err := repo.Query("*", ports.QueryOptions{
+		Filters: []ports.Filter{{Field: field, Operator: ports.OpEq, Value: value}},
+		OrderBy: []ports.OrderBy{{Field: "id", Ascending: true}},
+		Limit:   &limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(page.Items) == 0 {
+		return nil, types.ErrNotFound
+	}
+	return &page.Items[0], nil
+}
+
+func (repo *SQLiteRepository[T]) GetAll() ([]T, error) {
+	page, err := repo.Query("*", ports.QueryOptions{OrderBy: []ports.OrderBy{{Field: "id", Ascending: true}}})
+	return page.Items, err
+}
+
+func (repo *SQLiteRepository[T]) GetByID(id int) (*T, error) {
+	return repo.one("SELECT "+repo.table.columnList()+" FROM "+repo.table.name+" WHERE id = ?", id)
+}
+
+// GetForUpdate reads like GetByID: the database has a single connection, so a SQLiteUnitOfWork
+// holds it until it ends and nothing else writes the row in between
+func (repo *SQLiteRepository[T]) GetForUpdate(id int) (*T, error) {
+	return repo.GetByID(id)
+}
+
+// Update writes the writable columns of the model that its JSON carries
+func (repo *SQLiteRepository[T]) Update(model *T) (*T, error) {
+	return repo.updateWhere(model, "", nil)
+}
+
+// updateWhere is Update, but when column is set the row is only written while column still
+// holds expected; otherwise it returns types.ErrNotFound
+func (repo *SQLiteRepository[T]) updateWhere(model *T, column string, expected any) (*T, error) {
+	values, err := columnValues(repo.table, model)
+	if err != nil {
+		return nil, err
+	}
+	id, ok := values["id"]
+	if !ok {
+		return nil, fmt.Errorf("%s model without id", repo.table.name)
+	}
+
+	writable := repo.table.writable
+	if writable == nil {
+		for _, column := range repo.table.columns {
+			writable = append(writable, column.name)
+		}
+	}
+
+	var assignments []string
+	var args []any
+	for _, name := range writable {
+		value, ok := values[name]
+		if name == "id" || !ok || value == storedZeroTime {
+			continue
+		}
+		assignments = append(assignments, name+" = ?")
+		args = append(args, value)
+	}
+	if repo.table == usersSchema {
+		assignments = append(assignments, "updated_at = strftime('%Y-%m-%dT%H:%M:%f000Z', 'now')")
+	}
+
+	where := "id = ?"
+	args = append(args, id)
+	if column != "" {
+		where += " AND " + column + " = ?"
+		args = append(args, expected)
+	}
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE %s RETURNING %s", repo.table.name,
+		strings.Join(assignments, ", "), where, repo.table.columnList())
+	return repo.one(query, args...)
+}
+
+// Query returns a page of the models matching args. fields is a PostgREST select list: it
+// narrows the columns and embeds the relations of the table, e.g.
+// "id,name,user:users!inner(email),transactions(*)".
+func (repo *SQLiteRepository[T]) Query(fields string, args ports.QueryOptions) (ports.Page[T], error) {
+	plan, err := newQueryPlan(args)
+	if err != nil {
+		return ports.Page[T]{}, err
+	}
+	selection, err := parseSelectList(repo.table, fields)
+	if err != nil {
+		return ports.Page[T]{}, err
+	}
+	where, params, err := sqliteWhere(repo.table, plan.filters)
+	if err != nil {
+		return ports.Page[T]{}, err
+	}
+	order, err := sqliteOrderBy(repo.table, plan.orders)
+	if err != nil {
+		return ports.Page[T]{}, err
+	}
+
+	rows, err := repo.db.Query("SELECT "+repo.table.columnList()+" FROM "+repo.table.name+
+		where+order+sqlitePage(plan), params...)
+	if err != nil {
+		return ports.Page[T]{}, err
+	}
+	objects, err := sqliteScan(repo.table, repo.table.columns, rows)
+	if err != nil {
+		return ports.Page[T]{}, err
+	}
+
+	for _, embed := range selection.embeds {
+		if err := sqliteEmbedRows(repo.db, repo.table, embed, objects); err != nil {
+			return ports.Page[T]{}, err
+		}
+	}
+	narrowObjects(repo.table, selection, objects)
+	models, err := decodeModels[T](objects)
+	if err != nil {
+		return ports.Page[T]{}, err
+	}
+
+	total := -1
+	if plan.count != "" {
+		if err := repo.db.QueryRow("SELECT COUNT(*) FROM "+repo.table.name+where, params...).Scan(&total); err != nil {
+			return ports.Page[T]{}, err
+		}
+	}
+	return queryPage(plan, models, total), nil
+}
+
+// one runs a query that returns a single row of the table
+func (repo *SQLiteRepository[T]) one(query string, args ...any) (*T, error) {
+	rows, err := repo.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	objects, err := sqliteScan(repo.table, repo.table.columns, rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(objects) == 0 {
+		return nil, types.ErrNotFound
+	}
+	models, err := decodeModels[T](objects)
+	if err != nil {
+		return nil, err
+	}
+	return &models[0], nil
+}
+
+// sqliteEmbedRows loads the related rows of embed and adds them to every object under the embed key
+func sqliteEmbedRows(database sqliteExecutor, table *tableSchema, embed selectEmbed, objects []map[string]any) error {
+	relation := embed.relation
+	localName := table.jsonName(relation.localKey)
+
+	var keys []any
+	for _, object := range objects {
+		if key := object[localName]; key != nil {
+			keys = append(keys, key)
+		}
+	}
+
+	related := map[any][]map[string]any{}
+	if len(keys) > 0 {
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(keys)), ", ")
+		rows, err := database.Query(fmt.Sprintf("SELECT %s FROM %s WHERE %s IN (%s) ORDER BY id",
+			relation.table.columnList(), relation.table.name, relation.foreignKey, placeholders), keys...)
+		if err != nil {
+			return err
+		}
+		children, err := sqliteScan(relation.table, relation.table.columns, rows)
+		if err != nil {
+			return err
+		}
+		foreignName := relation.table.jsonName(relation.foreignKey)
+		for _, child := range children {
+			key := child[foreignName]
+			related[key] = append(related[key], child)
+		}
+		for _, nested := range embed.fields.embeds {
+			if err := sqliteEmbedRows(database, relation.table, nested, children); err != nil {
+				return err
+			}
+		}
+		narrowObjects(relation.table, embed.fields, children)
+	}
+
+	for _, object := range objects {
+		matches := related[object[localName]]
+		switch {
+		case relation.many && matches == nil:
+			object[embed.key] = []map[string]any{}
+		case relation.many:
+			object[embed.key] = matches
+		case len(matches) > 0:
+			object[embed.key] = matches[0]
+		default:
+			object[embed.key] = nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package infrastructure
+
+import (
+	"Financial/Core/Models/db"
+	"Financial/Core/ports"
+	"Financial/Core/types"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/supabase-community/supabase-go"
+)
+
+const sessionTable = "sessions"
+
+type SupaBaseSessionRepository struct {
+	client *supabase.Client
+}
+
+func NewSupaBaseSessionRepository(client *supabase.Client) ports.SessionRepository {
+	return &SupaBaseSessionRepository{client: client}
+}
+
+// CreateSession is a helper struct that matches the database schema
+type CreateSession struct {
+	UserID              int        `json:"user_id"`
+	AccessTokenID       string     `json:"access_token_id"`
+	RefreshTokenHash    string     `json:"refresh_token_hash"`
+	PreviousRefreshHash *string    `json:"previous_refresh_hash"`
+	Device              string     `json:"device"`
+	IP                  string     `json:"ip"`
+	CreatedAt           time.Time  `json:"created_at"`
+	LastSeenAt          time.Time  `json:"last_seen_at"`
+	ExpiresAt           time.Time  `json:"expires_at"`
+	RevokedAt           *time.Time `json:"revoked_at"`
+}
+
+// newCreateSession maps the model to the columns, leaving out the ID
+func newCreateSession(model *db.Session) CreateSession {
+	return CreateSession{
+		UserID:              model.UserID,
+		AccessTokenID:       model.AccessTokenID,
+		RefreshTokenHash:    model.RefreshTokenHash,
+		PreviousRefreshHash: model.PreviousRefreshHash,
+		Device:              model.Device,
+		IP:                  model.IP,
+		CreatedAt:           model.CreatedAt,
+		LastSeenAt:          model.LastSeenAt,
+		ExpiresAt:           model.ExpiresAt,
+		RevokedAt:           model.RevokedAt,
+	}
+}
+
+func (repo *SupaBaseSessionRepository) Create(model *db.Session) (*db.Session, error) {
+	newSession := newCreateSession(model)
+
+	var result db.Session
+	_, err := repo.client.From(sessionTable).
+		Insert(newSession, false, "", "representation", "").
+		Single().
+		ExecuteTo(&result)
+
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (repo *SupaBaseSessionRepository) Delete(id int) error {
+	_, _, err := repo.client.From(sessionTable).Delete("", "").
+		Eq("id", strconv.Itoa(id)).Execute()
+	return err
+}
+
+func (repo *SupaBaseSessionRepository) FindByField(field string, value any) (*db.Session, error) {
+	var results []db.Session
+
+	var filterValue string
+	switch v := value.(type) {
+	case string:
+		filterValue = v
+	case int, int32, int64, uint, uint32, uint64:
+		filterValue = fmt.Sprintf("%d", v)
+	case float32, float64:
+		filterValue = fmt.Sprintf("%f", v)
+	case bool:
+		filterValue = strconv.FormatBool(v)
+	default:
+		return nil, fmt.Errorf("unsupported type for field filtering: %T", value)
+	}
+
+	_, err := repo.client.From(sessionTable).
+		Select("*", "exact", false).
+		Filter(field, "eq", filterValue).
+		ExecuteTo(&results)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(results) == 0 {
+		return nil, types.ErrNotFound
+	}
+
+	return &results[0], nil
+}
+
+func (repo *SupaBaseSessionRepository) GetAll() ([]db.Session, error) {
+	var sessions []db.Session
+	_, err := repo.client.From(sessionTable).Select("*", "exact", false).
+		ExecuteTo(&sessions)
+	if err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (repo *SupaBaseSessionRepository) GetByID(id int) (*db.Session, error) {
+	return repo.FindByField("id", id)
+}
+
+func (repo *SupaBaseSessionRepository) Update(model *db.Session) (*db.Session, error) {
+	var result []db.Session
+	_, err := repo.client.From(sessionTable).Update(newCreateSession(model), "representation", "").Eq("id", strconv.Itoa(model.ID)).
+		ExecuteTo(&result)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, types.ErrNotFound
+	}
+	return &result[0], nil
+}
+
+// RotateRefresh updates the session only while its refresh_token_hash is still previousHash
+func (repo *SupaBaseSessionRepository) RotateRefresh(model *db.Session, previousHash string) (*db.Session, error) {
+	var result []db.Session
+	_, err := repo.client.From(sessionTable).Update(newCreateSession(model), "representation", "").
+		Eq("id", strconv.Itoa(model.ID)).Eq("refresh_token_hash", previousHash).
+		ExecuteTo(&result)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, types.ErrNotFound
+	}
+	return &result[0], nil
+}
+
+// Query returns a page of the rows matching args; see ports.QueryOptions for the filters,
+// paging and counts it supports.
+func (repo *SupaBaseSessionRepository) Query(fields string, args ports.QueryOptions) (ports.Page[db.Session], error) {
+	return supabaseQuery[db.Session](repo.client, sessionTable, fields, args)
+}
@@ -0,0 +1,142 @@
+package infrastructure
+
+import (
+	"Financial/Core/Models/db"
+	"Financial/Core/ports"
+	"Financial/Core/types"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/supabase-community/supabase-go"
+)
+
+const transactionTable = "transactions"
+
+type SupaBaseTransactionRepository struct {
+	client *supabase.Client
+}
+
+func NewSupaBaseTransactionRepository(client *supabase.Client) ports.Repository[db.Transaction, int] {
+	return &SupaBaseTransactionRepository{client: client}
+}
+
+// CreateTransaction is a helper struct that matches the database schema
+type CreateTransaction struct {
+	WalletID    int                   `json:"wallet_id"`
+	Type        types.TransactionType `json:"type"`
+	Amount      types.Money           `json:"amount"`
+	Description string                `json:"description"`
+	CategoryID  *int                  `json:"category_id"`
+	CreatedAt   time.Time             `json:"created_at"`
+
+	RecurringID    *int       `json:"recurring_id,omitempty"`
+	OccurrenceDate *time.Time `json:"occurrence_date,omitempty"`
+}
+
+func (repo *SupaBaseTransactionRepository) Create(model *db.Transaction) (*db.Transaction, error) {
+	newTransaction := CreateTransaction{
+		WalletID:    model.WalletID,
+		Type:        model.Type,
+		Amount:      model.Amount,
+		Description: model.Description,
+		CategoryID:  model.CategoryID,
+		CreatedAt:   model.CreatedAt,
+
+		RecurringID:    model.RecurringID,
+		OccurrenceDate: model.OccurrenceDate,
+	}
+
+	var result db.Transaction
+	_, err := repo.client.From(transactionTable).
+		Insert(newTransaction, false, "", "representation", "").
+		Single().
+		ExecuteTo(&result)
+
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (repo *SupaBaseTransactionRepository) Delete(id int) error {
+	_, _, err := repo.client.From(transactionTable).Delete("", "").
+		Eq("id", strconv.Itoa(id)).Execute()
+	return err
+}
+
+func (repo *SupaBaseTransactionRepository) FindByField(field string, value any) (*db.Transaction, error) {
+	var results []db.Transaction
+
+	var filterValue string
+	switch v := value.(type) {
+	case string:
+		filterValue = v
+	case int, int32, int64, uint, uint32, uint64:
+		filterValue = fmt.Sprintf("%d", v)
+	case float32, float64:
+		filterValue = fmt.Sprintf("%f", v)
+	case bool:
+		filterValue = strconv.FormatBool(v)
+	default:
+		return nil, fmt.Errorf("unsupported type for field filtering: %T", value)
+	}
+
+	_, err := repo.client.From(transactionTable).
+		Select("*", "exact", false).
+		Filter(field, "eq", filterValue).
+		ExecuteTo(&results)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(results) == 0 {
+		return nil, types.ErrNotFound
+	}
+
+	return &results[0], nil
+}
+
+func (repo *SupaBaseTransactionRepository) GetAll() ([]db.Transaction, error) {
+	var transactions []db.Transaction
+	_, err := repo.client.From(transactionTable).Select("*", "exact", false).
+		ExecuteTo(&transactions)
+	if err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
+
+func (repo *SupaBaseTransactionRepository) GetByID(id int) (*db.Transaction, error) {
+	return repo.FindByField("id", id)
+}
+
+func (repo *SupaBaseTransactionRepository) Update(model *db.Transaction) (*db.Transaction, error) {
+	var result []db.Transaction
+	_, err := repo.client.From(transactionTable).Update(CreateTransaction{
+		WalletID:    model.WalletID,
+		Type:        model.Type,
+		Amount:      model.Amount,
+		Description: model.Description,
+		CategoryID:  model.CategoryID,
+		CreatedAt:   model.CreatedAt,
+
+		RecurringID:    model.RecurringID,
+		OccurrenceDate: model.OccurrenceDate,
+	}, "representation", "").Eq("id", strconv.Itoa(model.ID)).
+		ExecuteTo(&result)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, types.ErrNotFound
+	}
+	return &result[0], nil
+}
+
+// Query returns a page of the rows matching args; see ports.QueryOptions for the filters,
+// paging and counts it supports.
+func (repo *SupaBaseTransactionRepository) Query(fields string, args ports.QueryOptions) (ports.Page[db.Transaction], error) {
+	return supabaseQuery[db.Transaction](repo.client, transactionTable, fields, args)
+}
@@ -0,0 +1,120 @@
+package infrastructure
+
+import (
+	"errors"
+
+	"Financial/Core/Models/db"
+	"Financial/Core/ports"
+	"Financial/Core/types"
+
+	"github.com/supabase-community/supabase-go"
+)
+
+// transferFunction is the Postgres function that applies a transfer inside a single database transaction
+const transferFunction = "transfer_between_wallets"
+
+type SupaBaseTransferRepository struct {
+	client *supabase.Client
+}
+
+func NewSupaBaseTransferRepository(client *supabase.Client) ports.TransferRepository {
+	return &SupaBaseTransferRepository{client: client}
+}
+
+// transferParams is a helper struct that matches the arguments of transfer_between_wallets
+type transferParams struct {
+	FromWalletID int         `json:"p_from_wallet_id"`
+	ToWalletID   int         `json:"p_to_wallet_id"`
+	Amount       types.Money `json:"p_amount"`
+	Description  string      `json:"p_description"`
+}
+
+func (repo *SupaBaseTransferRepository) Transfer(transfer *db.Transfer) (*db.Transfer, error) {
+	var result db.Transfer
+	err := executeRpc(repo.client, transferFunction, transferParams{
+		FromWalletID: transfer.FromWalletID,
+		ToWalletID:   transfer.ToWalletID,
+		Amount:       transfer.Amount,
+		Description:  transfer.Description,
+	}, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// transferBetweenWallets applies a transfer through the given repositories with the rules of the
+// transfer_between_wallets Postgres function. The caller makes it atomic, running it on
+// repositories bound to a transaction.
+func transferBetweenWallets(wallets ports.Repository[db.Wallet, int], transactions ports.Repository[db.Transaction, int], transfer *db.Transfer) (*db.Transfer, error) {
+	if !transfer.Amount.IsPositive() {
+		return nil, errors.New("transfer amount must be greater than zero")
+	}
+	if transfer.FromWalletID == transfer.ToWalletID {
+		return nil, errors.New("source and destination wallets must be different")
+	}
+
+	from, err := wallets.GetByID(transfer.FromWalletID)
+	if errors.Is(err, types.ErrNotFound) {
+		return nil, errors.New("source wallet not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	to, err := wallets.GetByID(transfer.ToWalletID)
+	if errors.Is(err, types.ErrNotFound) {
+		return nil, errors.New("destination wallet not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if from.UserID != to.UserID {
+		return nil, errors.New("wallets belong to different users")
+	}
+
+	// Like the SQL function, the amount is taken as a plain number in the currency of each wallet
+	debitAmount := transfer.Amount.WithCurrency(from.Currency)
+	creditAmount := transfer.Amount.WithCurrency(to.Currency)
+	if from.Balance, err = from.Balance.WithCurrency(from.Currency).Subtract(debitAmount); err != nil {
+		return nil, err
+	}
+	if from.Type == types.Debit && from.Balance.IsNegative() {
+		return nil, errors.New("insufficient funds in debit wallet")
+	}
+	if to.Balance, err = to.Balance.WithCurrency(to.Currency).Add(creditAmount); err != nil {
+		return nil, err
+	}
+	if _, err := wallets.Update(from); err != nil {
+		return nil, err
+	}
+	if _, err := wallets.Update(to); err != nil {
+		return nil, err
+	}
+
+	debitDescription, creditDescription := transfer.Description, transfer.Description
+	if transfer.Description == "" {
+		debitDescription = "Transfer to " + to.Name
+		creditDescription = "Transfer from " + from.Name
+	}
+	debit, err := transactions.Create(&db.Transaction{
+		WalletID: from.ID, Type: types.Expense, Amount: debitAmount, Description: debitDescription,
+	})
+	if err != nil {
+		return nil, err
+	}
+	credit, err := transactions.Create(&db.Transaction{
+		WalletID: to.ID, Type: types.Income, Amount: creditAmount, Description: creditDescription,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &db.Transfer{
+		FromWalletID: transfer.FromWalletID,
+		ToWalletID:   transfer.ToWalletID,
+		Amount:       transfer.Amount,
+		Description:  transfer.Description,
+		Debit:        debit,
+		Credit:       credit,
+	}, nil
+}
@@ -0,0 +1,136 @@
+package infrastructure
+
+import (
+	"Financial/Core/Models/db"
+	"Financial/Core/ports"
+	"Financial/Core/types"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/supabase-community/supabase-go"
+)
+
+const twoFactorTable = "two_factors"
+
+type SupaBaseTwoFactorRepository struct {
+	client *supabase.Client
+}
+
+func NewSupaBaseTwoFactorRepository(client *supabase.Client) ports.Repository[db.TwoFactor, int] {
+	return &SupaBaseTwoFactorRepository{client: client}
+}
+
+// CreateTwoFactor is a helper struct that matches the database schema
+type CreateTwoFactor struct {
+	UserID        int        `json:"user_id"`
+	Secret        string     `json:"secret"`
+	ConfirmedAt   *time.Time `json:"confirmed_at"`
+	LastUsedStep  int64      `json:"last_used_step"`
+	RecoveryCodes []string   `json:"recovery_codes"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// newCreateTwoFactor maps the model to the columns, leaving out the ID
+func newCreateTwoFactor(model *db.TwoFactor) CreateTwoFactor {
+	codes := model.RecoveryCodes
+	if codes == nil {
+		// NULL would break the NOT NULL column
+		codes = []string{}
+	}
+	return CreateTwoFactor{
+		UserID:        model.UserID,
+		Secret:        model.Secret,
+		ConfirmedAt:   model.ConfirmedAt,
+		LastUsedStep:  model.LastUsedStep,
+		RecoveryCodes: codes,
+		CreatedAt:     model.CreatedAt,
+	}
+}
+
+func (repo *SupaBaseTwoFactorRepository) Create(model *db.TwoFactor) (*db.TwoFactor, error) {
+	newTwoFactor := newCreateTwoFactor(model)
+
+	var result db.TwoFactor
+	_, err := repo.client.From(twoFactorTable).
+		Insert(newTwoFactor, false, "", "representation", "").
+		Single().
+		ExecuteTo(&result)
+
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (repo *SupaBaseTwoFactorRepository) Delete(id int) error {
+	_, _, err := repo.client.From(twoFactorTable).Delete("", "").
+		Eq("id", strconv.Itoa(id)).Execute()
+	return err
+}
+
+func (repo *SupaBaseTwoFactorRepository) FindByField(field string, value any) (*db.TwoFactor, error) {
+	var results []db.TwoFactor
+
+	var filterValue string
+	switch v := value.(type) {
+	case string:
+		filterValue = v
+	case int, int32, int64, uint, uint32, uint64:
+		filterValue = fmt.Sprintf("%d", v)
+	case float32, float64:
+		filterValue = fmt.Sprintf("%f", v)
+	case bool:
+		filterValue = strconv.FormatBool(v)
+	default:
+		return nil, fmt.Errorf("unsupported type for field filtering: %T", value)
+	}
+
+	_, err := repo.client.From(twoFactorTable).
+		Select("*", "exact", false).
+		Filter(field, "eq", filterValue).
+		ExecuteTo(&results)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(results) == 0 {
+		return nil, types.ErrNotFound
+	}
+
+	return &results[0], nil
+}
+
+func (repo *SupaBaseTwoFactorRepository) GetAll() ([]db.TwoFactor, error) {
+	var enrollments []db.TwoFactor
+	_, err := repo.client.From(twoFactorTable).Select("*", "exact", false).
+		ExecuteTo(&enrollments)
+	if err != nil {
+		return nil, err
+	}
+	return enrollments, nil
+}
+
+func (repo *SupaBaseTwoFactorRepository) GetByID(id int) (*db.TwoFactor, error) {
+	return repo.FindByField("id", id)
+}
+
+func (repo *SupaBaseTwoFactorRepository) Update(model *db.TwoFactor) (*db.TwoFactor, error) {
+	var result []db.TwoFactor
+	_, err := repo.client.From(twoFactorTable).Update(newCreateTwoFactor(model), "representation", "").Eq("id", strconv.Itoa(model.ID)).
+		ExecuteTo(&result)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, types.ErrNotFound
+	}
+	return &result[0], nil
+}
+
+// Query returns a page of the rows matching args; see ports.QueryOptions for the filters,
+// paging and counts it supports.
+func (repo *SupaBaseTwoFactorRepository) Query(fields string, args ports.QueryOptions) (ports.Page[db.TwoFactor], error) {
+	return supabaseQuery[db.TwoFactor](repo.client, twoFactorTable, fields, args)
+}
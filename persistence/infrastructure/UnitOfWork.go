@@ -0,0 +1,239 @@
+package infrastructure
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"Financial/Core/Models/db"
+	"Financial/Core/ports"
+
+	"github.com/supabase-community/supabase-go"
+)
+
+// unitOfWorkFunction is the Postgres function that applies the writes of a unit of work inside a single database transaction
+const unitOfWorkFunction = "apply_unit_of_work"
+
+// reserveRowIDFunction is the Postgres function that takes the next id of a table from its sequence
+const reserveRowIDFunction = "reserve_row_id"
+
+// unitOperation is one write of a unit of work, or a check of a row read for an update, as
+// apply_unit_of_work reads it
+type unitOperation struct {
+	Op     string         `json:"op"`
+	Table  string         `json:"table"`
+	ID     int            `json:"id,omitempty"`
+	Values map[string]any `json:"values,omitempty"`
+}
+
+// BatchUnitOfWork runs a unit of work through PostgREST, which can't keep a transaction open
+// across requests: the repositories collect the writes and apply_unit_of_work applies them all
+// at once when fn returns. Created rows get their id from reserve_row_id up front, so later
+// writes can refer to them; reads go to the committed data. Rows can't be locked while fn runs,
+// so GetForUpdate has apply_unit_of_work check them instead: if one changed in between, the
+// whole unit of work fails and nothing is written.
+type BatchUnitOfWork struct {
+	reads   batchReads
+	reserve func(table string) (int, error)
+	apply   func(operations []unitOperation) ([]json.RawMessage, error)
+}
+
+// batchReads are the repositories a BatchUnitOfWork reads the committed data through
+type batchReads struct {
+	users        ports.Repository[db.User, int]
+	wallets      ports.Repository[db.Wallet, int]
+	transactions ports.Repository[db.Transaction, int]
+	categories   ports.Repository[db.Category, int]
+}
+
+// NewSupaBaseUnitOfWork calls reserve_row_id and apply_unit_of_work through PostgREST
+func NewSupaBaseUnitOfWork(client *supabase.Client) ports.UnitOfWork {
+	return &BatchUnitOfWork{
+		reads: batchReads{
+			users:        NewSupaBaseUserRepository(client),
+			wallets:      NewSupaBaseWalletRepository(client),
+			transactions: NewSupaBaseTransactionRepository(client),
+			categories:   NewSupaBaseCategoryRepository(client),
+		},
+		reserve: func(table string) (int, error) {
+			var id int
+			err := executeRpc(client, reserveRowIDFunction, map[string]any{"p_table": table}, &id)
+			return id, err
+		},
+		apply: func(operations []unitOperation) ([]json.RawMessage, error) {
+			var rows []json.RawMessage
+			err := executeRpc(client, unitOfWorkFunction, map[string]any{"p_operations": operations}, &rows)
+			return rows, err
+		},
+	}
+}
+
+func (uow *BatchUnitOfWork) Do(fn func(repos ports.UnitOfWorkRepositories) error) error {
+	batch := &unitOfWorkBatch{reserve: uow.reserve}
+	err := fn(ports.UnitOfWorkRepositories{
+		Users:        &batchRepository[db.User]{Repository: uow.reads.users, batch: batch, table: usersSchema},
+		Wallets:      &batchRepository[db.Wallet]{Repository: uow.reads.wallets, batch: batch, table: walletsSchema},
+		Transactions: &batchRepository[db.Transaction]{Repository: uow.reads.transactions, batch: batch, table: transactionsSchema},
+		Categories:   &batchRepository[db.Category]{Repository: uow.reads.categories, batch: batch, table: categoriesSchema},
+	})
+	if err != nil || len(batch.operations) == 0 {
+		return err
+	}
+
+	rows, err := uow.apply(batch.operations)
+	if err != nil {
+		return err
+	}
+	if len(rows) != len(batch.operations) {
+		return fmt.Errorf("%s returned %d rows for %d operations", unitOfWorkFunction, len(rows), len(batch.operations))
+	}
+	for i, refresh := range batch.refresh {
+		if refresh == nil {
+			continue
+		}
+		if err := refresh(rows[i]); err != nil {
+			return fmt.Errorf("error decoding %s row: %w", batch.operations[i].Table, err)
+		}
+	}
+	return nil
+}
+
+// unitOfWorkBatch holds the writes of a unit of work, and for each one how to update the
+// model it returned once the write is applied
+type unitOfWorkBatch struct {
+	operations []unitOperation
+	refresh    []func(row json.RawMessage) error
+	reserve    func(table string) (int, error)
+}
+
+func (batch *unitOfWorkBatch) add(operation unitOperation, refresh func(row json.RawMessage) error) {
+	batch.operations = append(batch.operations, operation)
+	batch.refresh = append(batch.refresh, refresh)
+}
+
+// batchRepository adds its writes to a batch and reads through the embedded repository
+type batchRepository[T any] struct {
+	ports.Repository[T, int]
+	batch *unitOfWorkBatch
+	table *tableSchema
+}
+
+// Create reserves the id of the row, queues the insert and returns the model with that id
+func (repo *batchRepository[T]) Create(model *T) (*T, error) {
+	values, err := columnValues(repo.table, model)
+	if err != nil {
+		return nil, err
+	}
+	id, err := repo.batch.reserve(repo.table.name)
+	if err != nil {
+		return nil, fmt.Errorf("error reserving %s id: %w", repo.table.name, err)
+	}
+	values["id"] = int64(id)
+
+	inserted := map[string]any{}
+	for _, column := range repo.table.columns {
+		value, ok := values[column.name]
+		if !ok || value == nil || value == storedZeroTime {
+			continue
+		}
+		inserted[column.name] = storedJSON(column, value)
+	}
+
+	created, err := repo.model(values)
+	if err != nil {
+		return nil, err
+	}
+	repo.batch.add(unitOperation{Op: "insert", Table: repo.table.name, Values: inserted}, repo.refresher(created))
+	return created, nil
+}
+
+// GetForUpdate reads the committed row and queues a check of it. apply_unit_of_work runs the
+// check before the writes queued after it: it locks the row and raises if the row no longer
+// has the values read, so a write computed from a stale read is never applied.
+func (repo *batchRepository[T]) GetForUpdate(id int) (*T, error) {
+	model, err := repo.Repository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	values, err := columnValues(repo.table, model)
+	if err != nil {
+		return nil, err
+	}
+
+	read := map[string]any{}
+	for _, column := range repo.table.columns {
+		value, ok := values[column.name]
+		if column.name == "id" || !ok || value == storedZeroTime {
+			continue
+		}
+		read[column.name] = storedJSON(column, value)
+	}
+	repo.batch.add(unitOperation{Op: "check", Table: repo.table.name, ID: id, Values: read}, nil)
+	return model, nil
+}
+
+func (repo *batchRepository[T]) Delete(id int) error {
+	repo.batch.add(unitOperation{Op: "delete", Table: repo.table.name, ID: id}, nil)
+	return nil
+}
+
+// Update queues a write of the writable columns of the model that its JSON carries
+func (repo *batchRepository[T]) Update(model *T) (*T, error) {
+	values, err := columnValues(repo.table, model)
+	if err != nil {
+		return nil, err
+	}
+	id, ok := values["id"].(int64)
+	if !ok {
+		return nil, fmt.Errorf("%s model without id", repo.table.name)
+	}
+
+	writable := repo.table.writable
+	if writable == nil {
+		for _, column := range repo.table.columns {
+			writable = append(writable, column.name)
+		}
+	}
+	changes := map[string]any{}
+	for _, name := range writable {
+		value, ok := values[name]
+		if name == "id" || !ok || value == storedZeroTime {
+			continue
+		}
+		column, _ := repo.table.column(name)
+		changes[name] = storedJSON(column, value)
+	}
+
+	updated, err := repo.model(values)
+	if err != nil {
+		return nil, err
+	}
+	repo.batch.add(unitOperation{Op: "update", Table: repo.table.name, ID: int(id), Values: changes}, repo.refresher(updated))
+	return updated, nil
+}
+
+// model converts column values into a model of the table
+func (repo *batchRepository[T]) model(values map[string]any) (*T, error) {
+	object := make(map[string]any, len(values))
+	for _, column := range repo.table.columns {
+		if value, ok := values[column.name]; ok {
+			object[repo.table.jsonName(column.name)] = storedJSON(column, value)
+		}
+	}
+	models, err := decodeModels[T]([]map[string]any{object})
+	if err != nil {
+		return nil, err
+	}
+	return &models[0], nil
+}
+
+// refresher replaces model with the row apply_unit_of_work wrote for it
+func (repo *batchRepository[T]) refresher(model *T) func(row json.RawMessage) error {
+	return func(row json.RawMessage) error {
+		written, err := rowModel[T](repo.table, row)
+		if err != nil {
+			return err
+		}
+		*model = *written
+		return nil
+	}
+}
@@ -3,12 +3,11 @@ package infrastructure
 import (
 	"Financial/Core/Models/db"
 	contracts "Financial/Core/ports"
-	"errors"
+	"Financial/Core/types"
 	"fmt"
 	"strconv"
 	"time"
 
-	"github.com/supabase-community/postgrest-go"
 	"github.com/supabase-community/supabase-go"
 )
 
@@ -29,6 +28,7 @@ type CreateUser struct {
 	Lastname  string    `json:"last_name"`
 	Email     string    `json:"email"`
 	Status    string    `json:"status"`
+	Role      string    `json:"role"`
 	CreatedAt time.Time `json:"created_at"`
 	Password  string    `json:"password"`
 }
@@ -41,6 +41,7 @@ func (repo *SupaBaseUserRepository) Create(model *db.User) (*db.User, error) {
 		Lastname:  model.Lastname,
 		Email:     model.Email,
 		Status:    string(model.Status),
+		Role:      string(model.EffectiveRole()),
 		CreatedAt: model.CreatedAt,
 		Password:  model.Password,
 	}
@@ -59,13 +60,10 @@ func (repo *SupaBaseUserRepository) Create(model *db.User) (*db.User, error) {
 
 func (repo *SupaBaseUserRepository) Delete(id int) error {
 	_, _, err := repo.client.From(table_string).Delete("", "").
-		Single().Eq("id", strconv.Itoa(id)).Execute()
+		Eq("id", strconv.Itoa(id)).Execute()
 	return err
 }
 
-// ErrNotFound is returned when a record is not found
-var ErrNotFound = errors.New("record not found")
-
 func (repo *SupaBaseUserRepository) FindByField(field string, value any) (*db.User, error) {
 	var results []db.User
 
@@ -95,7 +93,7 @@ func (repo *SupaBaseUserRepository) FindByField(field string, value any) (*db.Us
 
 	// If no results found, return not found error
 	if len(results) == 0 {
-		return nil, ErrNotFound
+		return nil, types.ErrNotFound
 	}
 
 	// Return the first result
@@ -113,78 +111,33 @@ func (repo *SupaBaseUserRepository) GetAll() ([]db.User, error) {
 }
 
 func (repo *SupaBaseUserRepository) GetByID(id int) (*db.User, error) {
-	var todo db.User
+	var todos []db.User
 	_, err := repo.client.From(table_string).Select("*", "exact", false).Eq("id", strconv.Itoa(id)).
-		Single().ExecuteTo(&todo)
+		ExecuteTo(&todos)
 	if err != nil {
 		return nil, err
 	}
-	return &todo, nil
+	if len(todos) == 0 {
+		return nil, types.ErrNotFound
+	}
+	return &todos[0], nil
 }
 
 func (r *SupaBaseUserRepository) Update(todo *db.User) (*db.User, error) {
 	var result []db.User
-	_, err := r.client.From(table_string).Update(todo, "", "").Eq("id", strconv.Itoa(todo.ID)).
+	_, err := r.client.From(table_string).Update(todo, "representation", "").Eq("id", strconv.Itoa(todo.ID)).
 		ExecuteTo(&result)
 	if err != nil {
 		return nil, err
 	}
+	if len(result) == 0 {
+		return nil, types.ErrNotFound
+	}
 	return &result[0], nil
 }
 
-// Query executes a custom query and returns the result as interface{}.
-// This method provides a flexible way to execute custom queries that don't fit the standard CRUD operations.
-func (r *SupaBaseUserRepository) Query(fields string, args contracts.QueryOptions) (interface{}, error) {
-	var user []db.User
-
-	query := r.client.From(table_string)
-	queryUnfilter := query.Select(fields, "", false)
-
-	for _, filter := range args.Filters {
-		if filter.Operator == "eq" {
-			queryUnfilter.Eq(filter.Field, filter.Value.(string))
-		}
-		if filter.Operator == "neq" {
-			queryUnfilter.Neq(filter.Field, filter.Value.(string))
-		}
-		if filter.Operator == "gt" {
-			queryUnfilter.Gt(filter.Field, filter.Value.(string))
-		}
-		if filter.Operator == "gte" {
-			queryUnfilter.Gte(filter.Field, filter.Value.(string))
-		}
-		if filter.Operator == "lt" {
-			queryUnfilter.Lt(filter.Field, filter.Value.(string))
-		}
-		if filter.Operator == "lte" {
-			queryUnfilter.Lte(filter.Field, filter.Value.(string))
-		}
-		if filter.Operator == "like" {
-			queryUnfilter.Like(filter.Field, filter.Value.(string))
-		}
-		if filter.Operator == "ilike" {
-			queryUnfilter.Ilike(filter.Field, filter.Value.(string))
-		}
-		if filter.Operator == "is" {
-			queryUnfilter.Is(filter.Field, filter.Value.(string))
-		}
-		if filter.Operator == "in" {
-			queryUnfilter.In(filter.Field, []string{filter.Value.(string)})
-		}
-	}
-
-	for _, order := range args.OrderBy {
-		queryUnfilter.Order(order.Field, &postgrest.OrderOpts{
-			Ascending:  order.Ascending,
-			NullsFirst: *order.NullsFirst,
-		})
-	}
-
-	_, err := queryUnfilter.ExecuteTo(&user)
-
-	if err != nil {
-		return nil, err
-	}
-
-	return user, nil
+// Query returns a page of the rows matching args; see ports.QueryOptions for the filters,
+// paging and counts it supports.
+func (r *SupaBaseUserRepository) Query(fields string, args contracts.QueryOptions) (contracts.Page[db.User], error) {
+	return supabaseQuery[db.User](r.client, table_string, fields, args)
 }
@@ -0,0 +1,131 @@
+package infrastructure
+
+import (
+	"Financial/Core/Models/db"
+	"Financial/Core/ports"
+	"Financial/Core/types"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/supabase-community/supabase-go"
+)
+
+const userIdentityTable = "user_identities"
+
+type SupaBaseUserIdentityRepository struct {
+	client *supabase.Client
+}
+
+func NewSupaBaseUserIdentityRepository(client *supabase.Client) ports.Repository[db.UserIdentity, int] {
+	return &SupaBaseUserIdentityRepository{client: client}
+}
+
+// CreateUserIdentity is a helper struct that matches the database schema
+type CreateUserIdentity struct {
+	UserID      int        `json:"user_id"`
+	Issuer      string     `json:"issuer"`
+	Subject     string     `json:"subject"`
+	Email       string     `json:"email"`
+	CreatedAt   time.Time  `json:"created_at"`
+	LastLoginAt *time.Time `json:"last_login_at"`
+}
+
+// newCreateUserIdentity maps the model to the columns, leaving out the ID
+func newCreateUserIdentity(model *db.UserIdentity) CreateUserIdentity {
+	return CreateUserIdentity{
+		UserID:      model.UserID,
+		Issuer:      model.Issuer,
+		Subject:     model.Subject,
+		Email:       model.Email,
+		CreatedAt:   model.CreatedAt,
+		LastLoginAt: model.LastLoginAt,
+	}
+}
+
+func (repo *SupaBaseUserIdentityRepository) Create(model *db.UserIdentity) (*db.UserIdentity, error) {
+	newIdentity := newCreateUserIdentity(model)
+
+	var result db.UserIdentity
+	_, err := repo.client.From(userIdentityTable).
+		Insert(newIdentity, false, "", "representation", "").
+		Single().
+		ExecuteTo(&result)
+
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (repo *SupaBaseUserIdentityRepository) Delete(id int) error {
+	_, _, err := repo.client.From(userIdentityTable).Delete("", "").
+		Eq("id", strconv.Itoa(id)).Execute()
+	return err
+}
+
+func (repo *SupaBaseUserIdentityRepository) FindByField(field string, value any) (*db.UserIdentity, error) {
+	var results []db.UserIdentity
+
+	var filterValue string
+	switch v := value.(type) {
+	case string:
+		filterValue = v
+	case int, int32, int64, uint, uint32, uint64:
+		filterValue = fmt.Sprintf("%d", v)
+	case float32, float64:
+		filterValue = fmt.Sprintf("%f", v)
+	case bool:
+		filterValue = strconv.FormatBool(v)
+	default:
+		return nil, fmt.Errorf("unsupported type for field filtering: %T", value)
+	}
+
+	_, err := repo.client.From(userIdentityTable).
+		Select("*", "exact", false).
+		Filter(field, "eq", filterValue).
+		ExecuteTo(&results)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(results) == 0 {
+		return nil, types.ErrNotFound
+	}
+
+	return &results[0], nil
+}
+
+func (repo *SupaBaseUserIdentityRepository) GetAll() ([]db.UserIdentity, error) {
+	var identities []db.UserIdentity
+	_, err := repo.client.From(userIdentityTable).Select("*", "exact", false).
+		ExecuteTo(&identities)
+	if err != nil {
+		return nil, err
+	}
+	return identities, nil
+}
+
+func (repo *SupaBaseUserIdentityRepository) GetByID(id int) (*db.UserIdentity, error) {
+	return repo.FindByField("id", id)
+}
+
+func (repo *SupaBaseUserIdentityRepository) Update(model *db.UserIdentity) (*db.UserIdentity, error) {
+	var result []db.UserIdentity
+	_, err := repo.client.From(userIdentityTable).Update(newCreateUserIdentity(model), "representation", "").Eq("id", strconv.Itoa(model.ID)).
+		ExecuteTo(&result)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, types.ErrNotFound
+	}
+	return &result[0], nil
+}
+
+// Query returns a page of the rows matching args; see ports.QueryOptions for the filters,
+// paging and counts it supports.
+func (repo *SupaBaseUserIdentityRepository) Query(fields string, args ports.QueryOptions) (ports.Page[db.UserIdentity], error) {
+	return supabaseQuery[db.UserIdentity](repo.client, userIdentityTable, fields, args)
+}
@@ -5,10 +5,10 @@ import (
 	"Financial/Core/Models/db"
 	response "Financial/Core/Models/dtos/Response"
 	"Financial/Core/ports"
+	"Financial/Core/types"
 	"fmt"
 	"strconv"
 
-	"github.com/supabase-community/postgrest-go"
 	"github.com/supabase-community/supabase-go"
 )
 
@@ -36,8 +36,8 @@ func (repo *SupaBaseWalletRepository) Create(model *db.Wallet) (*db.Wallet, erro
 }
 
 func (repo *SupaBaseWalletRepository) Delete(id int) error {
-	_, _, err := repo.client.From(table_string).Delete("", "").
-		Single().Eq("id", strconv.Itoa(id)).Execute()
+	_, _, err := repo.client.From(walletTable).Delete("", "").
+		Eq("id", strconv.Itoa(id)).Execute()
 	return err
 }
 
@@ -70,7 +70,7 @@ func (repo *SupaBaseWalletRepository) FindByField(field string, value any) (*db.
 
 	// If no results found, return not found error
 	if len(results) == 0 {
-		return nil, ErrNotFound
+		return nil, types.ErrNotFound
 	}
 
 	// Return the first result
@@ -79,7 +79,7 @@ func (repo *SupaBaseWalletRepository) FindByField(field string, value any) (*db.
 
 func (repo *SupaBaseWalletRepository) GetAll() ([]db.Wallet, error) {
 	var todos []db.Wallet
-	_, err := repo.client.From(table_string).Select("*", "exact", false).
+	_, err := repo.client.From(walletTable).Select("*", "exact", false).
 		ExecuteTo(&todos)
 	if err != nil {
 		return nil, err
@@ -88,22 +88,39 @@ func (repo *SupaBaseWalletRepository) GetAll() ([]db.Wallet, error) {
 }
 
 func (repo *SupaBaseWalletRepository) GetByID(id int) (*db.Wallet, error) {
-	var todo db.Wallet
-	_, err := repo.client.From(table_string).Select("*", "exact", false).Eq("id", strconv.Itoa(id)).
-		Single().ExecuteTo(&todo)
+	var todos []db.Wallet
+	_, err := repo.client.From(walletTable).Select("*", "exact", false).Eq("id", strconv.Itoa(id)).
+		ExecuteTo(&todos)
 	if err != nil {
 		return nil, err
 	}
-	return &todo, nil
+	if len(todos) == 0 {
+		return nil, types.ErrNotFound
+	}
+	return &todos[0], nil
+}
+
+// UpdateWallet is a helper struct with the columns of a wallet that can be updated
+type UpdateWallet struct {
+	Name    string           `json:"name"`
+	Type    types.WalletType `json:"type"`
+	Balance types.Money      `json:"balance"`
 }
 
 func (r *SupaBaseWalletRepository) Update(todo *db.Wallet) (*db.Wallet, error) {
 	var result []db.Wallet
-	_, err := r.client.From(table_string).Update(todo, "", "").Eq("id", strconv.Itoa(todo.ID)).
+	_, err := r.client.From(walletTable).Update(UpdateWallet{
+		Name:    todo.Name,
+		Type:    todo.Type,
+		Balance: todo.Balance,
+	}, "representation", "").Eq("id", strconv.Itoa(todo.ID)).
 		ExecuteTo(&result)
 	if err != nil {
 		return nil, err
 	}
+	if len(result) == 0 {
+		return nil, types.ErrNotFound
+	}
 	return &result[0], nil
 }
 
@@ -121,59 +138,8 @@ func (r *SupaBaseWalletRepository) GetUserWallet(id int, email string) (*respons
 	return &result, nil
 }
 
-// Query executes a custom query and returns the result as interface{}.
-// This method provides a flexible way to execute custom queries that don't fit the standard CRUD operations.
-func (r *SupaBaseWalletRepository) Query(fields string, args ports.QueryOptions) (interface{}, error) {
-	var wallet []db.Wallet
-
-	query := r.client.From(walletTable)
-	queryUnfilter := query.Select(fields, "", false)
-
-	for _, filter := range args.Filters {
-		if filter.Operator == "eq" {
-			queryUnfilter.Eq(filter.Field, filter.Value.(string))
-		}
-		if filter.Operator == "neq" {
-			queryUnfilter.Neq(filter.Field, filter.Value.(string))
-		}
-		if filter.Operator == "gt" {
-			queryUnfilter.Gt(filter.Field, filter.Value.(string))
-		}
-		if filter.Operator == "gte" {
-			queryUnfilter.Gte(filter.Field, filter.Value.(string))
-		}
-		if filter.Operator == "lt" {
-			queryUnfilter.Lt(filter.Field, filter.Value.(string))
-		}
-		if filter.Operator == "lte" {
-			queryUnfilter.Lte(filter.Field, filter.Value.(string))
-		}
-		if filter.Operator == "like" {
-			queryUnfilter.Like(filter.Field, filter.Value.(string))
-		}
-		if filter.Operator == "ilike" {
-			queryUnfilter.Ilike(filter.Field, filter.Value.(string))
-		}
-		if filter.Operator == "is" {
-			queryUnfilter.Is(filter.Field, filter.Value.(string))
-		}
-		if filter.Operator == "in" {
-			queryUnfilter.In(filter.Field, []string{filter.Value.(string)})
-		}
-	}
-
-	for _, order := range args.OrderBy {
-		queryUnfilter.Order(order.Field, &postgrest.OrderOpts{
-			Ascending:  order.Ascending,
-			NullsFirst: *order.NullsFirst,
-		})
-	}
-
-	_, err := queryUnfilter.ExecuteTo(&wallet)
-
-	if err != nil {
-		return nil, err
-	}
-
-	return wallet, nil
+// Query returns a page of the rows matching args; see ports.QueryOptions for the filters,
+// paging and counts it supports.
+func (r *SupaBaseWalletRepository) Query(fields string, args ports.QueryOptions) (ports.Page[db.Wallet], error) {
+	return supabaseQuery[db.Wallet](r.client, walletTable, fields, args)
 }
@@ -0,0 +1,178 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"Financial/Core/ports"
+	"Financial/Core/types"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// NewPostgresPool opens a connection pool to databaseURL and checks that the database answers
+func NewPostgresPool(ctx context.Context, databaseURL string) (*pgxpool.Pool, error) {
+	pool, err := pgxpool.New(ctx, databaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("error creating the postgres pool: %w", err)
+	}
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("error connecting to postgres: %w", err)
+	}
+	return pool, nil
+}
+
+// pgNotFound turns the "no rows" error of pgx into types.ErrNotFound
+func pgNotFound(err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return types.ErrNotFound
+	}
+	return err
+}
+
+// pgColumn is a field a query may filter or order by: the SQL expression it reads and
+// the type the filter values are cast to on the server
+type pgColumn struct {
+	expr    string
+	sqlType string
+}
+
+// pgComparisons maps the comparison operators of QueryOptions to SQL
+var pgComparisons = map[string]string{
+	ports.OpEq:    "=",
+	ports.OpNeq:   "<>",
+	ports.OpGt:    ">",
+	ports.OpGte:   ">=",
+	ports.OpLt:    "<",
+	ports.OpLte:   "<=",
+	ports.OpLike:  "LIKE",
+	ports.OpIlike: "ILIKE",
+}
+
+// pgWhere builds the WHERE clause for filters. Only fields listed in columns are accepted, so
+// field names never reach the SQL; values travel as text parameters and the server casts them
+// to the type of the column, which lets callers pass ints, times or Money as well as strings.
+// The placeholders are numbered after the first args already in use.
+func pgWhere(columns map[string]pgColumn, filters []queryFilter, args []any) (string, []any, error) {
+	return sqlWhere(filters, args, func(filter queryFilter, args []any) (string, []any, error) {
+		column, ok := columns[filter.field]
+		if !ok {
+			return "", nil, fmt.Errorf("unknown filter field %q", filter.field)
+		}
+
+		switch filter.operator {
+		case ports.OpIs:
+			return pgIs(column.expr, filter.values[0]), args, nil
+		case ports.OpIn:
+			args = append(args, filter.values)
+			return fmt.Sprintf("%s = ANY(CAST($%d::text[] AS %s[]))", column.expr, len(args), column.sqlType), args, nil
+		}
+		operator := pgComparisons[filter.operator]
+		args = append(args, filter.values[0])
+		if operator == "LIKE" || operator == "ILIKE" {
+			return fmt.Sprintf("%s::text %s $%d::text", column.expr, operator, len(args)), args, nil
+		}
+		return fmt.Sprintf("%s %s CAST($%d::text AS %s)", column.expr, operator, len(args), column.sqlType), args, nil
+	})
+}
+
+// pgFieldWhere builds the WHERE clause of a lookup by field, as FindByField does
+func pgFieldWhere(columns map[string]pgColumn, field string, value any) (string, []any, error) {
+	plan, err := newQueryPlan(ports.QueryOptions{Filters: []ports.Filter{{Field: field, Operator: ports.OpEq, Value: value}}})
+	if err != nil {
+		return "", nil, err
+	}
+	return pgWhere(columns, plan.filters, nil)
+}
+
+// pgCount counts the rows a query matches when the plan asks for it, and returns -1 otherwise.
+// Postgres counts every kind exactly; planned and estimated only make PostgREST cheaper.
+func pgCount(conn pgExecutor, plan *queryPlan, from string, where string, args []any) (int, error) {
+	if plan.count == "" {
+		return -1, nil
+	}
+	var total int
+	err := conn.QueryRow(context.Background(), "SELECT COUNT(*)"+from+where, args...).Scan(&total)
+	return total, err
+}
+
+// pgIs builds the IS clause of a checked is filter, whose value is null, true or false
+func pgIs(expr string, value string) string {
+	return expr + " IS " + strings.ToUpper(value)
+}
+
+// pgOrderBy builds the ORDER BY clause; a nil NullsFirst keeps the database default
+func pgOrderBy(columns map[string]pgColumn, orders []ports.OrderBy) (string, error) {
+	if len(orders) == 0 {
+		return "", nil
+	}
+
+	terms := make([]string, 0, len(orders))
+	for _, order := range orders {
+		column, ok := columns[order.Field]
+		if !ok {
+			return "", fmt.Errorf("unknown order field %q", order.Field)
+		}
+		term := column.expr + " DESC"
+		if order.Ascending {
+			term = column.expr + " ASC"
+		}
+		if order.NullsFirst != nil {
+			if *order.NullsFirst {
+				term += " NULLS FIRST"
+			} else {
+				term += " NULLS LAST"
+			}
+		}
+		terms = append(terms, term)
+	}
+	return " ORDER BY " + strings.Join(terms, ", "), nil
+}
+
+// pgPage builds the LIMIT and OFFSET clauses of a plan
+func pgPage(plan *queryPlan) string {
+	var clause string
+	if plan.limit != nil {
+		clause += " LIMIT " + strconv.Itoa(*plan.limit)
+	}
+	if plan.offset > 0 {
+		clause += " OFFSET " + strconv.Itoa(plan.offset)
+	}
+	return clause
+}
+
+// pgEmbeds returns the relations embedded in a PostgREST select list, by alias
+// (e.g. "user:users!inner(email),transactions(*)" gives user and transactions)
+func pgEmbeds(fields string) map[string]bool {
+	embeds := map[string]bool{}
+	depth, start := 0, 0
+	for i, r := range fields + "," {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth > 0 {
+				continue
+			}
+			part := strings.TrimSpace(fields[start:i])
+			start = i + 1
+			name, _, embedded := strings.Cut(part, "(")
+			if !embedded {
+				continue
+			}
+			if alias, _, ok := strings.Cut(name, ":"); ok {
+				name = alias
+			}
+			name, _, _ = strings.Cut(name, "!")
+			embeds[strings.TrimSpace(name)] = true
+		}
+	}
+	return embeds
+}
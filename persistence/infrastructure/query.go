@@ -0,0 +1,372 @@
+package infrastructure
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"Financial/Core/ports"
+	"Financial/Core/types"
+
+	"github.com/supabase-community/postgrest-go"
+	"github.com/supabase-community/supabase-go"
+)
+
+// queryPlan is a QueryOptions checked once for every backend: the operators are known, the
+// values are text in the format Postgres parses, and the page and count are resolved. Each
+// backend renders it: PostgREST parameters, a SQL WHERE clause or an in-memory match.
+type queryPlan struct {
+	filters []queryFilter
+	orders  []ports.OrderBy
+	limit   *int
+	offset  int
+	count   string
+}
+
+// queryFilter is a checked Filter: a condition on a field, or a group of filters
+type queryFilter struct {
+	field    string
+	operator string
+	// values holds the value as text; an in filter has one per element, and an is
+	// filter null, true or false
+	values []string
+
+	group []queryFilter
+	// any makes the group match when one of its filters does (Or) instead of all (And)
+	any bool
+}
+
+func (filter queryFilter) isGroup() bool {
+	return filter.group != nil
+}
+
+// queryComparisons are the operators that compare a field with a single value
+var queryComparisons = map[string]bool{
+	ports.OpEq: true, ports.OpNeq: true, ports.OpGt: true, ports.OpGte: true,
+	ports.OpLt: true, ports.OpLte: true, ports.OpLike: true, ports.OpIlike: true,
+}
+
+func newQueryPlan(args ports.QueryOptions) (*queryPlan, error) {
+	filters, err := newQueryFilters(args.Filters)
+	if err != nil {
+		return nil, err
+	}
+	for _, order := range args.OrderBy {
+		if order.Field == "" {
+			return nil, fmt.Errorf("order without a field")
+		}
+	}
+
+	plan := &queryPlan{filters: filters, orders: args.OrderBy}
+	if args.Limit != nil {
+		limit := max(*args.Limit, 0)
+		plan.limit = &limit
+	}
+	if args.Offset != nil {
+		plan.offset = max(*args.Offset, 0)
+	}
+	if args.Count != nil {
+		switch *args.Count {
+		case ports.CountExact, ports.CountPlanned, ports.CountEstimated:
+			plan.count = *args.Count
+		default:
+			return nil, fmt.Errorf("unsupported count %q", *args.Count)
+		}
+	}
+	return plan, nil
+}
+
+func newQueryFilters(filters []ports.Filter) ([]queryFilter, error) {
+	result := make([]queryFilter, 0, len(filters))
+	for _, filter := range filters {
+		checked, err := newQueryFilter(filter)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, checked)
+	}
+	return result, nil
+}
+
+func newQueryFilter(filter ports.Filter) (queryFilter, error) {
+	if len(filter.Or) > 0 || len(filter.And) > 0 {
+		if filter.Field != "" || (len(filter.Or) > 0 && len(filter.And) > 0) {
+			return queryFilter{}, fmt.Errorf("a filter is either a condition or one Or/And group")
+		}
+		members := filter.And
+		if len(filter.Or) > 0 {
+			members = filter.Or
+		}
+		group, err := newQueryFilters(members)
+		if err != nil {
+			return queryFilter{}, err
+		}
+		return queryFilter{group: group, any: len(filter.Or) > 0}, nil
+	}
+
+	if filter.Field == "" {
+		return queryFilter{}, fmt.Errorf("filter without a field")
+	}
+	checked := queryFilter{field: filter.Field, operator: filter.Operator}
+	switch {
+	case filter.Operator == ports.OpIs:
+		text := "null"
+		if filter.Value != nil {
+			var err error
+			if text, err = filterText(filter.Value); err != nil {
+				return queryFilter{}, err
+			}
+		}
+		text = strings.ToLower(text)
+		if text != "null" && text != "true" && text != "false" {
+			return queryFilter{}, fmt.Errorf("unsupported value for the is operator: %q", text)
+		}
+		checked.values = []string{text}
+	case filter.Operator == ports.OpIn:
+		values, err := filterTextList(filter.Value)
+		if err != nil {
+			return queryFilter{}, err
+		}
+		checked.values = values
+	case queryComparisons[filter.Operator]:
+		text, err := filterText(filter.Value)
+		if err != nil {
+			return queryFilter{}, err
+		}
+		checked.values = []string{text}
+	default:
+		return queryFilter{}, fmt.Errorf("unsupported filter operator %q", filter.Operator)
+	}
+	return checked, nil
+}
+
+// queryPage wraps the rows of a query; total is the number of matching rows, or -1 when it wasn't counted
+func queryPage[T any](plan *queryPlan, items []T, total int) ports.Page[T] {
+	if items == nil {
+		items = []T{}
+	}
+	page := ports.Page[T]{Items: items, Offset: plan.offset}
+	if plan.count != "" && total >= 0 {
+		page.Total = &total
+	}
+	return page
+}
+
+// filterText formats a filter value the way Postgres parses it
+func filterText(value any) (string, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v.Format(time.RFC3339Nano), nil
+	case types.Money:
+		return v.Decimal(), nil
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Pointer:
+		if rv.IsNil() {
+			return "", fmt.Errorf("nil filter value; use the is operator")
+		}
+		return filterText(rv.Elem().Interface())
+	case reflect.String:
+		return rv.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'f', -1, 64), nil
+	case reflect.Bool:
+		return strconv.FormatBool(rv.Bool()), nil
+	}
+	return "", fmt.Errorf("unsupported type for field filtering: %T", value)
+}
+
+// filterTextList formats the value of an in filter; a single value is a list of one
+func filterTextList(value any) ([]string, error) {
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		text, err := filterText(value)
+		if err != nil {
+			return nil, err
+		}
+		return []string{text}, nil
+	}
+
+	values := make([]string, rv.Len())
+	for i := range values {
+		text, err := filterText(rv.Index(i).Interface())
+		if err != nil {
+			return nil, err
+		}
+		values[i] = text
+	}
+	return values, nil
+}
+
+// sqlWhere builds the WHERE clause of a SQL backend. condition renders one filter on a field and
+// appends its parameters to args; groups become parenthesized AND/OR expressions.
+func sqlWhere(filters []queryFilter, args []any, condition func(filter queryFilter, args []any) (string, []any, error)) (string, []any, error) {
+	clause, args, err := sqlGroup(filters, false, args, condition)
+	if err != nil || clause == "" {
+		return "", args, err
+	}
+	return " WHERE " + clause, args, nil
+}
+
+func sqlGroup(filters []queryFilter, any bool, args []any, condition func(filter queryFilter, args []any) (string, []any, error)) (string, []any, error) {
+	clauses := make([]string, 0, len(filters))
+	for _, filter := range filters {
+		var clause string
+		var err error
+		if filter.isGroup() {
+			clause, args, err = sqlGroup(filter.group, filter.any, args, condition)
+			clause = "(" + clause + ")"
+		} else {
+			clause, args, err = condition(filter, args)
+		}
+		if err != nil {
+			return "", nil, err
+		}
+		clauses = append(clauses, clause)
+	}
+	if any {
+		return strings.Join(clauses, " OR "), args, nil
+	}
+	return strings.Join(clauses, " AND "), args, nil
+}
+
+// matchFilters evaluates filters in memory; condition evaluates one filter on a field
+func matchFilters(filters []queryFilter, any bool, condition func(filter queryFilter) (bool, error)) (bool, error) {
+	for _, filter := range filters {
+		var matches bool
+		var err error
+		if filter.isGroup() {
+			matches, err = matchFilters(filter.group, filter.any, condition)
+		} else {
+			matches, err = condition(filter)
+		}
+		if err != nil {
+			return false, err
+		}
+		if matches == any {
+			return any, nil
+		}
+	}
+	return !any, nil
+}
+
+// supabaseQuery runs a Query on a table through PostgREST
+func supabaseQuery[T any](client *supabase.Client, table string, fields string, args ports.QueryOptions) (ports.Page[T], error) {
+	plan, err := newQueryPlan(args)
+	if err != nil {
+		return ports.Page[T]{}, err
+	}
+
+	query := client.From(table).Select(fields, plan.count, false)
+	if err := plan.postgrest(query); err != nil {
+		return ports.Page[T]{}, err
+	}
+
+	var rows []T
+	total, err := query.ExecuteTo(&rows)
+	if err != nil {
+		return ports.Page[T]{}, err
+	}
+	return queryPage(plan, rows, int(total)), nil
+}
+
+// postgrest adds the plan to a PostgREST query. A field filtered once becomes its own parameter
+// (field=op.value); groups and fields filtered more than once, e.g. a date range, go into the
+// and=(...) logic tree, since a query has a single parameter per name.
+func (plan *queryPlan) postgrest(query *postgrest.FilterBuilder) error {
+	uses := map[string]int{}
+	for _, filter := range plan.filters {
+		if !filter.isGroup() {
+			uses[filter.field]++
+		}
+	}
+
+	var tree []string
+	for _, filter := range plan.filters {
+		if !filter.isGroup() && uses[filter.field] == 1 {
+			query.Filter(filter.field, filter.operator, postgrestValue(filter, false))
+			continue
+		}
+		condition, err := postgrestCondition(filter)
+		if err != nil {
+			return err
+		}
+		tree = append(tree, condition)
+	}
+	if len(tree) > 0 {
+		query.And(strings.Join(tree, ","), "")
+	}
+
+	for _, order := range plan.orders {
+		// Without NullsFirst, nulls go where Postgres puts them: last when ascending, first when descending
+		nullsFirst := !order.Ascending
+		if order.NullsFirst != nil {
+			nullsFirst = *order.NullsFirst
+		}
+		query.Order(order.Field, &postgrest.OrderOpts{Ascending: order.Ascending, NullsFirst: nullsFirst})
+	}
+
+	switch {
+	case plan.limit != nil:
+		query.Range(plan.offset, plan.offset+*plan.limit-1, "")
+	case plan.offset > 0:
+		query.Range(plan.offset, math.MaxInt32, "")
+	}
+	return nil
+}
+
+// postgrestCondition renders a filter inside a logic tree, e.g. or(name.eq.Cash,balance.gt.10)
+func postgrestCondition(filter queryFilter) (string, error) {
+	if !filter.isGroup() {
+		if strings.Contains(filter.field, ".") {
+			return "", fmt.Errorf("filters on embedded resources can't be grouped or repeated: %q", filter.field)
+		}
+		return filter.field + "." + filter.operator + "." + postgrestValue(filter, true), nil
+	}
+
+	conditions := make([]string, len(filter.group))
+	for i, member := range filter.group {
+		condition, err := postgrestCondition(member)
+		if err != nil {
+			return "", err
+		}
+		conditions[i] = condition
+	}
+	operator := "and"
+	if filter.any {
+		operator = "or"
+	}
+	return operator + "(" + strings.Join(conditions, ",") + ")", nil
+}
+
+// postgrestValue renders the value of a filter; inside a logic tree, and in lists, values with
+// characters PostgREST reserves are quoted
+func postgrestValue(filter queryFilter, inTree bool) string {
+	if filter.operator == ports.OpIn {
+		quoted := make([]string, len(filter.values))
+		for i, value := range filter.values {
+			quoted[i] = postgrestQuote(value)
+		}
+		return "(" + strings.Join(quoted, ",") + ")"
+	}
+	if inTree {
+		return postgrestQuote(filter.values[0])
+	}
+	return filter.values[0]
+}
+
+func postgrestQuote(value string) string {
+	if !strings.ContainsAny(value, ",.:()\" \\") {
+		return value
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
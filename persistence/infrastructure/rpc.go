@@ -0,0 +1,40 @@
+package infrastructure
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/supabase-community/supabase-go"
+)
+
+// rpcError matches the error body PostgREST returns when a Postgres function raises an exception
+type rpcError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Details string `json:"details"`
+	Hint    string `json:"hint"`
+}
+
+// executeRpc calls a Postgres function through PostgREST and decodes its JSON result into to.
+// The supabase client only returns the raw body, so errors raised by the function are
+// detected by their PostgREST error shape.
+func executeRpc(client *supabase.Client, name string, body interface{}, to interface{}) error {
+	raw := client.Rpc(name, "", body)
+	if raw == "" {
+		return fmt.Errorf("rpc %s returned an empty response", name)
+	}
+
+	var rpcErr rpcError
+	if err := json.Unmarshal([]byte(raw), &rpcErr); err == nil && rpcErr.Code != "" && rpcErr.Message != "" {
+		return errors.New(rpcErr.Message)
+	}
+
+	if to == nil {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(raw), to); err != nil {
+		return fmt.Errorf("error decoding rpc %s result: %w", name, err)
+	}
+	return nil
+}
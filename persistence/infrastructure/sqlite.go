@@ -0,0 +1,229 @@
+package infrastructure
+
+import (
+	"database/sql"
+	_ "embed"
+	"errors"
+	"fmt"
+	"strings"
+
+	"Financial/Core/ports"
+	"Financial/Core/types"
+
+	_ "modernc.org/sqlite"
+)
+
+//go:embed sqlite_schema.sql
+var sqliteSchema string
+
+// OpenSQLite opens the database file at path, creating it and its schema when needed.
+// ":memory:" gives a private in-memory database.
+func OpenSQLite(path string) (*sql.DB, error) {
+	dsn := "file:" + path + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"
+	if path != ":memory:" {
+		dsn += "&_pragma=journal_mode(WAL)"
+	}
+	database, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("error opening the sqlite database: %w", err)
+	}
+
+	// One connection: SQLite writes one at a time anyway, and an in-memory database
+	// only exists in the connection that created it
+	database.SetMaxOpenConns(1)
+
+	if _, err := database.Exec(sqliteSchema); err != nil {
+		database.Close()
+		return nil, fmt.Errorf("error creating the sqlite schema: %w", err)
+	}
+	return database, nil
+}
+
+// sqliteExecutor is what the SQLite repositories need from a connection, so they work
+// the same on the database and inside a transaction
+type sqliteExecutor interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// sqliteDecode turns a row read with the columns of table into the JSON of the model
+func sqliteDecode(table *tableSchema, columns []tableColumn, row []any) map[string]any {
+	fields := make(map[string]any, len(columns))
+	for i, column := range columns {
+		fields[table.jsonName(column.name)] = storedJSON(column, row[i])
+	}
+	return fields
+}
+
+// sqliteScan reads the rows of a query into JSON objects
+func sqliteScan(table *tableSchema, columns []tableColumn, rows *sql.Rows) ([]map[string]any, error) {
+	defer rows.Close()
+
+	var result []map[string]any
+	for rows.Next() {
+		row := make([]any, len(columns))
+		dest := make([]any, len(columns))
+		for i := range row {
+			dest[i] = &row[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		result = append(result, sqliteDecode(table, columns, row))
+	}
+	return result, rows.Err()
+}
+
+// sqliteWhere builds the WHERE clause for filters on table. A field "users.email" filters on a
+// column of an embedded relation and keeps the rows that have a matching related row, like an
+// !inner embed does in PostgREST.
+func sqliteWhere(table *tableSchema, filters []queryFilter) (string, []any, error) {
+	return sqlWhere(filters, nil, func(filter queryFilter, args []any) (string, []any, error) {
+		clause, clauseArgs, err := sqliteCondition(table, table.name, filter)
+		return clause, append(args, clauseArgs...), err
+	})
+}
+
+func sqliteCondition(table *tableSchema, alias string, filter queryFilter) (string, []any, error) {
+	if relationName, field, ok := strings.Cut(filter.field, "."); ok {
+		relation, ok := table.relations[relationName]
+		if !ok {
+			return "", nil, fmt.Errorf("unknown filter field %q", filter.field)
+		}
+		inner := filter
+		inner.field = field
+		clause, args, err := sqliteCondition(relation.table, "related", inner)
+		if err != nil {
+			return "", nil, err
+		}
+		return fmt.Sprintf("EXISTS (SELECT 1 FROM %s related WHERE related.%s = %s.%s AND %s)",
+			relation.table.name, relation.foreignKey, alias, relation.localKey, clause), args, nil
+	}
+
+	column, ok := table.column(filter.field)
+	if !ok || column.kind == columnArray {
+		return "", nil, fmt.Errorf("unknown filter field %q", filter.field)
+	}
+	expr := alias + "." + column.name
+	if column.kind == columnDecimal {
+		expr = "CAST(" + expr + " AS REAL)"
+	}
+
+	switch filter.operator {
+	case ports.OpIs:
+		return pgIs(expr, filter.values[0]), nil, nil
+	case ports.OpIn:
+		args := make([]any, len(filter.values))
+		for i, value := range filter.values {
+			var err error
+			if args[i], err = columnArg(column, value); err != nil {
+				return "", nil, err
+			}
+		}
+		if len(args) == 0 {
+			return "FALSE", nil, nil
+		}
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(args)), ", ")
+		return fmt.Sprintf("%s IN (%s)", expr, placeholders), args, nil
+	case ports.OpIlike:
+		return fmt.Sprintf("LOWER(%s) LIKE LOWER(?) ESCAPE '\\'", expr), []any{filter.values[0]}, nil
+	case ports.OpLike:
+		// LIKE ignores case in SQLite; GLOB doesn't, like LIKE in Postgres
+		return expr + " GLOB ?", []any{likeToGlob(filter.values[0])}, nil
+	}
+
+	arg, err := columnArg(column, filter.values[0])
+	if err != nil {
+		return "", nil, err
+	}
+	operator := pgComparisons[filter.operator]
+	if column.kind == columnDecimal {
+		return fmt.Sprintf("%s %s CAST(? AS REAL)", expr, operator), []any{arg}, nil
+	}
+	return fmt.Sprintf("%s %s ?", expr, operator), []any{arg}, nil
+}
+
+// likeToGlob translates a LIKE pattern into a GLOB pattern
+func likeToGlob(pattern string) string {
+	var glob strings.Builder
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			escaped = false
+			glob.WriteString(globLiteral(r))
+		case r == '\\':
+			escaped = true
+		case r == '%':
+			glob.WriteRune('*')
+		case r == '_':
+			glob.WriteRune('?')
+		default:
+			glob.WriteString(globLiteral(r))
+		}
+	}
+	return glob.String()
+}
+
+func globLiteral(r rune) string {
+	if r == '*' || r == '?' || r == '[' {
+		return "[" + string(r) + "]"
+	}
+	return string(r)
+}
+
+// sqliteOrderBy builds the ORDER BY clause. Without NullsFirst, nulls go where Postgres puts
+// them: last when ascending and first when descending.
+func sqliteOrderBy(table *tableSchema, orders []ports.OrderBy) (string, error) {
+	if len(orders) == 0 {
+		return "", nil
+	}
+
+	terms := make([]string, 0, len(orders))
+	for _, order := range orders {
+		column, ok := table.column(order.Field)
+		if !ok {
+			return "", fmt.Errorf("unknown order field %q", order.Field)
+		}
+		expr := table.name + "." + column.name
+		if column.kind == columnDecimal {
+			expr = "CAST(" + expr + " AS REAL)"
+		}
+		nullsFirst := !order.Ascending
+		if order.NullsFirst != nil {
+			nullsFirst = *order.NullsFirst
+		}
+
+		term := expr + " DESC"
+		if order.Ascending {
+			term = expr + " ASC"
+		}
+		if nullsFirst {
+			term += " NULLS FIRST"
+		} else {
+			term += " NULLS LAST"
+		}
+		terms = append(terms, term)
+	}
+	return " ORDER BY " + strings.Join(terms, ", "), nil
+}
+
+// sqlitePage builds the LIMIT and OFFSET clauses of a plan; SQLite needs a LIMIT before an OFFSET
+func sqlitePage(plan *queryPlan) string {
+	switch {
+	case plan.limit != nil:
+		return fmt.Sprintf(" LIMIT %d OFFSET %d", *plan.limit, plan.offset)
+	case plan.offset > 0:
+		return fmt.Sprintf(" LIMIT -1 OFFSET %d", plan.offset)
+	}
+	return ""
+}
+
+// sqliteNotFound turns the "no rows" error into types.ErrNotFound
+func sqliteNotFound(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return types.ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,271 @@
+-- SQLite version of the schema built by supabase/migrations, applied on every start.
+-- Enums become CHECK constraints, arrays are JSON text, DECIMAL columns are text so
+-- amounts stay exact, and timestamps are UTC text with microseconds
+-- (2025-07-14T12:00:00.000000Z) so they sort correctly as text.
+
+CREATE TABLE IF NOT EXISTS users (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    nick_name TEXT NOT NULL,
+    first_name TEXT NOT NULL,
+    last_name TEXT NOT NULL,
+    email TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('active', 'inactive', 'suspended', 'pending')),
+    created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%f000Z', 'now')),
+    password TEXT NOT NULL,
+    updated_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%f000Z', 'now')),
+    role TEXT NOT NULL DEFAULT 'user'
+        CONSTRAINT valid_user_role CHECK (role IN ('user', 'support', 'admin'))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS users_nick_name_key ON users(nick_name);
+CREATE UNIQUE INDEX IF NOT EXISTS users_email_key ON users(email);
+
+CREATE TABLE IF NOT EXISTS wallets (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    type TEXT NOT NULL CHECK (type IN ('Debit', 'Credit')),
+    balance TEXT NOT NULL DEFAULT '0.00',
+    user_id INTEGER NOT NULL,
+    currency TEXT NOT NULL DEFAULT 'USD' CHECK (currency GLOB '[A-Z][A-Z][A-Z]'),
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE,
+    CONSTRAINT unique_user_wallet_name UNIQUE (user_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS exchange_rates (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    base_currency TEXT NOT NULL CHECK (base_currency GLOB '[A-Z][A-Z][A-Z]'),
+    quote_currency TEXT NOT NULL CHECK (quote_currency GLOB '[A-Z][A-Z][A-Z]'),
+    rate TEXT NOT NULL CHECK (CAST(rate AS REAL) > 0),
+    effective_date TEXT NOT NULL,
+    CONSTRAINT different_currencies CHECK (base_currency <> quote_currency),
+    CONSTRAINT unique_rate_per_date UNIQUE (base_currency, quote_currency, effective_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_exchange_rates_pair_date ON exchange_rates(base_currency, quote_currency, effective_date DESC);
+
+CREATE TABLE IF NOT EXISTS categories (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    parent_id INTEGER,
+    name TEXT NOT NULL CHECK (length(name) <= 100),
+    type TEXT NOT NULL CHECK (type IN ('Income', 'Expense')),
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_parent
+        FOREIGN KEY (parent_id)
+        REFERENCES categories(id)
+        ON DELETE NO ACTION,
+    CONSTRAINT parent_is_not_self CHECK (parent_id IS NULL OR parent_id <> id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS unique_category_name_per_parent
+    ON categories(user_id, COALESCE(parent_id, 0), LOWER(name));
+CREATE INDEX IF NOT EXISTS idx_categories_parent_id ON categories(parent_id);
+
+CREATE TABLE IF NOT EXISTS recurring_transactions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    wallet_id INTEGER NOT NULL,
+    type TEXT NOT NULL CHECK (type IN ('Income', 'Expense')),
+    amount TEXT NOT NULL CHECK (CAST(amount AS REAL) > 0),
+    description TEXT NOT NULL DEFAULT '' CHECK (length(description) <= 255),
+    category_id INTEGER,
+    frequency TEXT NOT NULL CHECK (frequency IN ('DAILY', 'WEEKLY', 'MONTHLY', 'YEARLY')),
+    interval INTEGER NOT NULL DEFAULT 1 CHECK (interval > 0),
+    by_month_day TEXT,
+    start_date TEXT NOT NULL,
+    end_date TEXT,
+    status TEXT NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'paused', 'ended')),
+    next_run_at TEXT,
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_wallet
+        FOREIGN KEY (wallet_id)
+        REFERENCES wallets(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_category
+        FOREIGN KEY (category_id)
+        REFERENCES categories(id)
+        ON DELETE SET NULL,
+    CONSTRAINT end_after_start CHECK (end_date IS NULL OR end_date >= start_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_recurring_due ON recurring_transactions(status, next_run_at);
+CREATE INDEX IF NOT EXISTS idx_recurring_user_id ON recurring_transactions(user_id);
+
+CREATE TABLE IF NOT EXISTS transactions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    wallet_id INTEGER NOT NULL,
+    type TEXT NOT NULL CHECK (type IN ('Income', 'Expense')),
+    amount TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%f000Z', 'now')),
+    category_id INTEGER
+        REFERENCES categories(id)
+        ON DELETE NO ACTION,
+    recurring_id INTEGER REFERENCES recurring_transactions(id) ON DELETE SET NULL,
+    occurrence_date TEXT,
+    CONSTRAINT fk_wallet
+        FOREIGN KEY (wallet_id)
+        REFERENCES wallets(id)
+        ON DELETE CASCADE,
+    CONSTRAINT positive_transaction_amount CHECK (CAST(amount AS REAL) > 0),
+    CONSTRAINT unique_recurring_occurrence UNIQUE (recurring_id, occurrence_date)
+);
+
+CREATE INDEX IF NOT EXISTS transactions_wallet_id_created_at_idx ON transactions(wallet_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_transactions_category_id ON transactions(category_id);
+
+CREATE TABLE IF NOT EXISTS budgets (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    category_id INTEGER NOT NULL,
+    period TEXT NOT NULL
+        CHECK (period GLOB '[0-9][0-9][0-9][0-9]-[0-1][0-9]' AND substr(period, 6, 2) BETWEEN '01' AND '12'),
+    limit_amount TEXT NOT NULL CHECK (CAST(limit_amount AS REAL) > 0),
+    currency TEXT NOT NULL DEFAULT 'USD' CHECK (currency GLOB '[A-Z][A-Z][A-Z]'),
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_category
+        FOREIGN KEY (category_id)
+        REFERENCES categories(id)
+        ON DELETE CASCADE,
+    CONSTRAINT unique_category_period UNIQUE (user_id, category_id, period)
+);
+
+CREATE INDEX IF NOT EXISTS idx_budgets_user_period ON budgets(user_id, period);
+
+CREATE TABLE IF NOT EXISTS sessions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    access_token_id TEXT NOT NULL,
+    refresh_token_hash TEXT NOT NULL,
+    previous_refresh_hash TEXT,
+    device TEXT NOT NULL DEFAULT '',
+    ip TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%f000Z', 'now')),
+    last_seen_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%f000Z', 'now')),
+    expires_at TEXT NOT NULL,
+    revoked_at TEXT,
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE,
+    CONSTRAINT unique_access_token_id UNIQUE (access_token_id),
+    CONSTRAINT unique_refresh_token_hash UNIQUE (refresh_token_hash)
+);
+
+CREATE INDEX IF NOT EXISTS idx_sessions_user ON sessions(user_id);
+CREATE INDEX IF NOT EXISTS idx_sessions_previous_refresh_hash ON sessions(previous_refresh_hash);
+
+CREATE TABLE IF NOT EXISTS password_resets (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    token_hash TEXT NOT NULL,
+    created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%f000Z', 'now')),
+    expires_at TEXT NOT NULL,
+    used_at TEXT,
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE,
+    CONSTRAINT unique_token_hash UNIQUE (token_hash)
+);
+
+CREATE INDEX IF NOT EXISTS idx_password_resets_user ON password_resets(user_id);
+
+CREATE TABLE IF NOT EXISTS two_factors (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    secret TEXT NOT NULL,
+    confirmed_at TEXT,
+    last_used_step INTEGER NOT NULL DEFAULT 0,
+    recovery_codes TEXT NOT NULL DEFAULT '[]',
+    created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%f000Z', 'now')),
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE,
+    CONSTRAINT unique_user_two_factor UNIQUE (user_id)
+);
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    prefix TEXT NOT NULL,
+    key_hash TEXT NOT NULL,
+    scopes TEXT NOT NULL DEFAULT '[]',
+    created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%f000Z', 'now')),
+    expires_at TEXT,
+    last_used_at TEXT,
+    revoked_at TEXT,
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE,
+    CONSTRAINT unique_api_key_hash UNIQUE (key_hash)
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_keys_user ON api_keys(user_id);
+
+CREATE TABLE IF NOT EXISTS user_identities (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    issuer TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    email TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%f000Z', 'now')),
+    last_login_at TEXT,
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE,
+    CONSTRAINT unique_issuer_subject UNIQUE (issuer, subject)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_identities_user ON user_identities(user_id);
+
+CREATE TABLE IF NOT EXISTS oidc_logins (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    state_hash TEXT NOT NULL,
+    code_verifier TEXT NOT NULL,
+    nonce TEXT NOT NULL,
+    created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%f000Z', 'now')),
+    expires_at TEXT NOT NULL,
+    CONSTRAINT unique_oidc_state UNIQUE (state_hash)
+);
+
+CREATE TABLE IF NOT EXISTS login_attempts (
+    key TEXT PRIMARY KEY,
+    failures INTEGER NOT NULL DEFAULT 0,
+    last_failure_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%f000Z', 'now')),
+    locked_until TEXT,
+    previous_status TEXT
+);
+
+CREATE TABLE IF NOT EXISTS audit_log (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER,
+    action TEXT NOT NULL,
+    detail TEXT NOT NULL DEFAULT '',
+    ip TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%f000Z', 'now')),
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(id)
+        ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_user ON audit_log(user_id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log(created_at);
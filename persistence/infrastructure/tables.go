@@ -0,0 +1,671 @@
+package infrastructure
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// storedTimeLayout is how timestamps are stored: UTC with a fixed width, so that comparing
+// and ordering them as text gives the same result as comparing the times
+const storedTimeLayout = "2006-01-02T15:04:05.000000Z"
+
+// columnKind is how a column is stored and how it goes back into the JSON of the model
+type columnKind int
+
+const (
+	columnInteger columnKind = iota
+	columnText
+	columnDecimal // exact amounts and rates, stored as text and compared as numbers
+	columnTime    // timestamps in storedTimeLayout
+	columnArray   // Postgres arrays, stored as JSON text
+)
+
+type tableColumn struct {
+	name string
+	kind columnKind
+}
+
+// tableRelation is a table that can be embedded in a select, PostgREST style,
+// joined by localKey of the parent and foreignKey of the related table
+type tableRelation struct {
+	table      *tableSchema
+	localKey   string
+	foreignKey string
+	many       bool
+}
+
+// tableSchema describes how a model is stored. Rows are converted to and from the model through
+// its JSON, whose names are the column names, as with PostgREST. The constraints repeat those of
+// the SQL schema for the stores that have no database to enforce them.
+type tableSchema struct {
+	name    string
+	columns []tableColumn
+
+	// writable are the columns Update changes; nil means every column but id
+	writable []string
+
+	// jsonNames maps the columns whose JSON name in the model is a different one
+	jsonNames map[string]string
+
+	// relations are the tables that can be embedded, by table name and by alias
+	relations map[string]tableRelation
+
+	// notNull are the NOT NULL columns besides id
+	notNull []string
+
+	// defaults are the values of the columns a new row doesn't set; defaultNow is the current time
+	defaults map[string]any
+
+	checks      []tableCheck
+	uniques     []tableUnique
+	foreignKeys []tableForeignKey
+}
+
+// defaultNow is the default of the timestamp columns that take the time of the insert
+var defaultNow = struct{}{}
+
+// tableCheck is a CHECK constraint over the stored values of a row. Like in SQL, a check
+// on a null value passes.
+type tableCheck struct {
+	name  string
+	valid func(row map[string]any) bool
+}
+
+// tableUnique is a UNIQUE constraint. key returns false when the row has a null in the
+// constrained columns, which never conflicts with another row.
+type tableUnique struct {
+	name string
+	key  func(row map[string]any) (string, bool)
+}
+
+// foreignKeyAction is what deleting a referenced row does to the rows that reference it
+type foreignKeyAction int
+
+const (
+	onDeleteNoAction foreignKeyAction = iota // the delete fails while references remain
+	onDeleteCascade
+	onDeleteSetNull
+)
+
+// tableForeignKey is a FOREIGN KEY constraint; the referenced column is always id
+type tableForeignKey struct {
+	name     string
+	column   string
+	table    *tableSchema
+	onDelete foreignKeyAction
+}
+
+func (table *tableSchema) column(name string) (tableColumn, bool) {
+	for _, column := range table.columns {
+		if column.name == name {
+			return column, true
+		}
+	}
+	return tableColumn{}, false
+}
+
+func (table *tableSchema) jsonName(column string) string {
+	if name, ok := table.jsonNames[column]; ok {
+		return name
+	}
+	return column
+}
+
+func (table *tableSchema) columnList() string {
+	names := make([]string, len(table.columns))
+	for i, column := range table.columns {
+		names[i] = column.name
+	}
+	return strings.Join(names, ", ")
+}
+
+// columnValues converts a model into the values of its columns, keyed by column name.
+// Columns missing from the JSON of the model (omitempty) are left out.
+func columnValues(table *tableSchema, model any) (map[string]any, error) {
+	data, err := json.Marshal(model)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var fields map[string]any
+	if err := decoder.Decode(&fields); err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]any, len(table.columns))
+	for _, column := range table.columns {
+		field, ok := fields[table.jsonName(column.name)]
+		if !ok {
+			continue
+		}
+		value, err := columnValue(column, field)
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %w", column.name, err)
+		}
+		values[column.name] = value
+	}
+	return values, nil
+}
+
+// columnValue converts a decoded JSON value into what is stored in column
+func columnValue(column tableColumn, field any) (any, error) {
+	if field == nil {
+		return nil, nil
+	}
+	switch column.kind {
+	case columnInteger:
+		number, ok := field.(json.Number)
+		if !ok {
+			return nil, fmt.Errorf("expected a number, got %T", field)
+		}
+		return number.Int64()
+	case columnDecimal:
+		return fmt.Sprint(field), nil
+	case columnTime:
+		text, ok := field.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected a timestamp, got %T", field)
+		}
+		return storedTimestamp(text)
+	case columnArray:
+		data, err := json.Marshal(field)
+		return string(data), err
+	}
+	return fmt.Sprint(field), nil
+}
+
+// storedTimestamp normalizes a timestamp to storedTimeLayout
+func storedTimestamp(text string) (string, error) {
+	t, err := time.Parse(time.RFC3339Nano, text)
+	if err != nil {
+		// Columns without time zone come as 2006-01-02T15:04:05.999 and are UTC
+		if t, err = time.Parse("2006-01-02T15:04:05.999999999", text); err != nil {
+			if t, err = time.Parse(time.DateOnly, text); err != nil {
+				return "", fmt.Errorf("invalid timestamp %q", text)
+			}
+		}
+	}
+	return t.UTC().Format(storedTimeLayout), nil
+}
+
+// storedZeroTime is the zero time.Time once stored
+var storedZeroTime = time.Time{}.Format(storedTimeLayout)
+
+// storedJSON converts a stored value back into its JSON form
+func storedJSON(column tableColumn, value any) any {
+	if raw, ok := value.([]byte); ok {
+		value = string(raw)
+	}
+	text, isText := value.(string)
+	switch {
+	case value == nil:
+		return nil
+	case column.kind == columnDecimal && isText:
+		return json.RawMessage(text)
+	case column.kind == columnArray && isText:
+		return json.RawMessage(text)
+	}
+	return value
+}
+
+// decodeModels converts JSON objects into models
+func decodeModels[T any](objects []map[string]any) ([]T, error) {
+	models := make([]T, 0, len(objects))
+	for _, object := range objects {
+		data, err := json.Marshal(object)
+		if err != nil {
+			return nil, err
+		}
+		var model T
+		if err := json.Unmarshal(data, &model); err != nil {
+			return nil, fmt.Errorf("error decoding row: %w", err)
+		}
+		models = append(models, model)
+	}
+	return models, nil
+}
+
+// rowModel converts a row encoded as a JSON object keyed by column name, as to_jsonb gives
+// it, into a model of the table
+func rowModel[T any](table *tableSchema, row []byte) (*T, error) {
+	decoder := json.NewDecoder(bytes.NewReader(row))
+	decoder.UseNumber()
+	var columns map[string]any
+	if err := decoder.Decode(&columns); err != nil {
+		return nil, err
+	}
+	object := make(map[string]any, len(columns))
+	for name, value := range columns {
+		object[table.jsonName(name)] = value
+	}
+	models, err := decodeModels[T]([]map[string]any{object})
+	if err != nil {
+		return nil, err
+	}
+	return &models[0], nil
+}
+
+// selectList is a parsed PostgREST select list: the columns of the table and the
+// embedded relations, each with its own select list
+type selectList struct {
+	columns []tableColumn
+	embeds  []selectEmbed
+}
+
+type selectEmbed struct {
+	key      string
+	relation tableRelation
+	fields   selectList
+}
+
+// parseSelectList parses fields such as "id,name,user:users!inner(email),transactions(*)"
+func parseSelectList(table *tableSchema, fields string) (selectList, error) {
+	var selection selectList
+	all := false
+	for _, part := range splitSelect(fields) {
+		name, inner, embedded := strings.Cut(part, "(")
+		if !embedded {
+			if part == "*" || part == "" {
+				all = true
+				continue
+			}
+			column, ok := table.column(part)
+			if !ok {
+				return selectList{}, fmt.Errorf("unknown column %q in %s", part, table.name)
+			}
+			selection.columns = append(selection.columns, column)
+			continue
+		}
+
+		key := name
+		if alias, target, ok := strings.Cut(name, ":"); ok {
+			key, name = alias, target
+		}
+		name, _, _ = strings.Cut(name, "!")
+		relation, ok := table.relations[name]
+		if !ok {
+			return selectList{}, fmt.Errorf("no relation %q in %s", name, table.name)
+		}
+		nested, err := parseSelectList(relation.table, strings.TrimSuffix(inner, ")"))
+		if err != nil {
+			return selectList{}, err
+		}
+		selection.embeds = append(selection.embeds, selectEmbed{key: key, relation: relation, fields: nested})
+	}
+
+	if all || len(selection.columns) == 0 {
+		selection.columns = table.columns
+	}
+	return selection, nil
+}
+
+// splitSelect splits a select list on the commas that are not inside an embed
+func splitSelect(fields string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, r := range fields + "," {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, strings.TrimSpace(fields[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return parts
+}
+
+// columnArg converts a filter value, already formatted as text, to the type of column
+func columnArg(column tableColumn, text string) (any, error) {
+	switch column.kind {
+	case columnInteger:
+		value, err := strconv.ParseInt(text, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %q for %s: %w", text, column.name, err)
+		}
+		return value, nil
+	case columnTime:
+		return storedTimestamp(text)
+	}
+	return text, nil
+}
+
+// The tables of supabase/migrations (sqlite_schema.sql for SQLite)
+var (
+	usersSchema = &tableSchema{
+		name: "users",
+		columns: []tableColumn{
+			{"id", columnInteger}, {"nick_name", columnText}, {"first_name", columnText}, {"last_name", columnText},
+			{"email", columnText}, {"status", columnText}, {"role", columnText}, {"created_at", columnTime},
+			{"password", columnText},
+		},
+		notNull:  []string{"nick_name", "first_name", "last_name", "email", "status", "created_at", "password", "role"},
+		defaults: map[string]any{"status": "pending", "role": "user", "created_at": defaultNow},
+		checks: []tableCheck{
+			checkIn("users_status_check", "status", "active", "inactive", "suspended", "pending"),
+			checkIn("valid_user_role", "role", "user", "support", "admin"),
+		},
+		uniques: []tableUnique{unique("users_nick_name_key", "nick_name"), unique("users_email_key", "email")},
+	}
+	transactionsSchema = &tableSchema{
+		name: "transactions",
+		columns: []tableColumn{
+			{"id", columnInteger}, {"wallet_id", columnInteger}, {"type", columnText}, {"amount", columnDecimal},
+			{"description", columnText}, {"category_id", columnInteger}, {"recurring_id", columnInteger},
+			{"occurrence_date", columnTime}, {"created_at", columnTime},
+		},
+		notNull:  []string{"wallet_id", "type", "amount", "description", "created_at"},
+		defaults: map[string]any{"description": "", "created_at": defaultNow},
+		checks: []tableCheck{
+			checkIn("transactions_type_check", "type", "Income", "Expense"),
+			checkPositive("positive_transaction_amount", "amount"),
+		},
+		uniques: []tableUnique{unique("unique_recurring_occurrence", "recurring_id", "occurrence_date")},
+	}
+	walletsSchema = &tableSchema{
+		name: "wallets",
+		columns: []tableColumn{
+			{"id", columnInteger}, {"name", columnText}, {"type", columnText}, {"balance", columnDecimal},
+			{"currency", columnText}, {"user_id", columnInteger},
+		},
+		writable:  []string{"name", "type", "balance"},
+		jsonNames: map[string]string{"user_id": "userId"},
+		relations: map[string]tableRelation{
+			"users":        {table: usersSchema, localKey: "user_id", foreignKey: "id"},
+			"transactions": {table: transactionsSchema, localKey: "id", foreignKey: "wallet_id", many: true},
+		},
+		notNull:  []string{"name", "type", "balance", "user_id", "currency"},
+		defaults: map[string]any{"balance": "0.00", "currency": "USD"},
+		checks: []tableCheck{
+			checkIn("wallets_type_check", "type", "Debit", "Credit"),
+			checkCurrency("wallets_currency_check", "currency"),
+		},
+		uniques: []tableUnique{unique("unique_user_wallet_name", "user_id", "name")},
+	}
+	exchangeRatesSchema = &tableSchema{
+		name: "exchange_rates",
+		columns: []tableColumn{
+			{"id", columnInteger}, {"base_currency", columnText}, {"quote_currency", columnText},
+			{"rate", columnDecimal}, {"effective_date", columnTime},
+		},
+		notNull: []string{"base_currency", "quote_currency", "rate", "effective_date"},
+		checks: []tableCheck{
+			checkCurrency("exchange_rates_base_currency_check", "base_currency"),
+			checkCurrency("exchange_rates_quote_currency_check", "quote_currency"),
+			checkPositive("exchange_rates_rate_check", "rate"),
+			{name: "different_currencies", valid: func(row map[string]any) bool {
+				return row["base_currency"] == nil || row["base_currency"] != row["quote_currency"]
+			}},
+		},
+		uniques: []tableUnique{unique("unique_rate_per_date", "base_currency", "quote_currency", "effective_date")},
+	}
+	categoriesSchema = &tableSchema{
+		name: "categories",
+		columns: []tableColumn{
+			{"id", columnInteger}, {"user_id", columnInteger}, {"parent_id", columnInteger}, {"name", columnText},
+			{"type", columnText},
+		},
+		notNull: []string{"user_id", "name", "type"},
+		checks: []tableCheck{
+			checkMaxLength("categories_name_check", "name", 100),
+			checkIn("categories_type_check", "type", "Income", "Expense"),
+			{name: "parent_is_not_self", valid: func(row map[string]any) bool {
+				return row["parent_id"] == nil || row["parent_id"] != row["id"]
+			}},
+		},
+		uniques: []tableUnique{{name: "unique_category_name_per_parent", key: func(row map[string]any) (string, bool) {
+			// ON categories(user_id, COALESCE(parent_id, 0), LOWER(name))
+			parent := row["parent_id"]
+			if parent == nil {
+				parent = int64(0)
+			}
+			name, _ := row["name"].(string)
+			return fmt.Sprint(row["user_id"], "\x00", parent, "\x00", strings.ToLower(name)), true
+		}}},
+	}
+	budgetsSchema = &tableSchema{
+		name: "budgets",
+		columns: []tableColumn{
+			{"id", columnInteger}, {"user_id", columnInteger}, {"category_id", columnInteger}, {"period", columnText},
+			{"limit_amount", columnDecimal}, {"currency", columnText},
+		},
+		notNull:  []string{"user_id", "category_id", "period", "limit_amount", "currency"},
+		defaults: map[string]any{"currency": "USD"},
+		checks: []tableCheck{
+			{name: "budgets_period_check", valid: func(row map[string]any) bool {
+				period, ok := row["period"].(string)
+				return !ok || budgetPeriod.MatchString(period)
+			}},
+			checkPositive("budgets_limit_amount_check", "limit_amount"),
+			checkCurrency("budgets_currency_check", "currency"),
+		},
+		uniques: []tableUnique{unique("unique_category_period", "user_id", "category_id", "period")},
+	}
+	recurringTransactionsSchema = &tableSchema{
+		name: "recurring_transactions",
+		columns: []tableColumn{
+			{"id", columnInteger}, {"user_id", columnInteger}, {"wallet_id", columnInteger}, {"type", columnText},
+			{"amount", columnDecimal}, {"description", columnText}, {"category_id", columnInteger},
+			{"frequency", columnText}, {"interval", columnInteger}, {"by_month_day", columnArray},
+			{"start_date", columnTime}, {"end_date", columnTime}, {"status", columnText}, {"next_run_at", columnTime},
+		},
+		notNull:  []string{"user_id", "wallet_id", "type", "amount", "description", "frequency", "interval", "start_date", "status"},
+		defaults: map[string]any{"description": "", "interval": int64(1), "status": "active"},
+		checks: []tableCheck{
+			checkIn("recurring_transactions_type_check", "type", "Income", "Expense"),
+			checkPositive("recurring_transactions_amount_check", "amount"),
+			checkMaxLength("recurring_transactions_description_check", "description", 255),
+			checkIn("recurring_transactions_frequency_check", "frequency", "DAILY", "WEEKLY", "MONTHLY", "YEARLY"),
+			{name: "recurring_transactions_interval_check", valid: func(row map[string]any) bool {
+				interval, ok := row["interval"].(int64)
+				return !ok || interval > 0
+			}},
+			checkIn("recurring_transactions_status_check", "status", "active", "paused", "ended"),
+			{name: "end_after_start", valid: func(row map[string]any) bool {
+				end, ok := row["end_date"].(string)
+				start, _ := row["start_date"].(string)
+				return !ok || end >= start
+			}},
+		},
+	}
+	sessionsSchema = &tableSchema{
+		name: "sessions",
+		columns: []tableColumn{
+			{"id", columnInteger}, {"user_id", columnInteger}, {"access_token_id", columnText},
+			{"refresh_token_hash", columnText}, {"previous_refresh_hash", columnText}, {"device", columnText},
+			{"ip", columnText}, {"created_at", columnTime}, {"last_seen_at", columnTime}, {"expires_at", columnTime},
+			{"revoked_at", columnTime},
+		},
+		notNull:  []string{"user_id", "access_token_id", "refresh_token_hash", "device", "ip", "created_at", "last_seen_at", "expires_at"},
+		defaults: map[string]any{"device": "", "ip": "", "created_at": defaultNow, "last_seen_at": defaultNow},
+		uniques: []tableUnique{
+			unique("unique_access_token_id", "access_token_id"),
+			unique("unique_refresh_token_hash", "refresh_token_hash"),
+		},
+	}
+	passwordResetsSchema = &tableSchema{
+		name: "password_resets",
+		columns: []tableColumn{
+			{"id", columnInteger}, {"user_id", columnInteger}, {"token_hash", columnText}, {"created_at", columnTime},
+			{"expires_at", columnTime}, {"used_at", columnTime},
+		},
+		notNull:  []string{"user_id", "token_hash", "created_at", "expires_at"},
+		defaults: map[string]any{"created_at": defaultNow},
+		uniques:  []tableUnique{unique("unique_token_hash", "token_hash")},
+	}
+	twoFactorsSchema = &tableSchema{
+		name: "two_factors",
+		columns: []tableColumn{
+			{"id", columnInteger}, {"user_id", columnInteger}, {"secret", columnText}, {"confirmed_at", columnTime},
+			{"last_used_step", columnInteger}, {"recovery_codes", columnArray}, {"created_at", columnTime},
+		},
+		notNull:  []string{"user_id", "secret", "last_used_step", "recovery_codes", "created_at"},
+		defaults: map[string]any{"last_used_step": int64(0), "recovery_codes": "[]", "created_at": defaultNow},
+		uniques:  []tableUnique{unique("unique_user_two_factor", "user_id")},
+	}
+	apiKeysSchema = &tableSchema{
+		name: "api_keys",
+		columns: []tableColumn{
+			{"id", columnInteger}, {"user_id", columnInteger}, {"name", columnText}, {"prefix", columnText},
+			{"key_hash", columnText}, {"scopes", columnArray}, {"created_at", columnTime}, {"expires_at", columnTime},
+			{"last_used_at", columnTime}, {"revoked_at", columnTime},
+		},
+		notNull:  []string{"user_id", "name", "prefix", "key_hash", "scopes", "created_at"},
+		defaults: map[string]any{"scopes": "[]", "created_at": defaultNow},
+		uniques:  []tableUnique{unique("unique_api_key_hash", "key_hash")},
+	}
+	userIdentitiesSchema = &tableSchema{
+		name: "user_identities",
+		columns: []tableColumn{
+			{"id", columnInteger}, {"user_id", columnInteger}, {"issuer", columnText}, {"subject", columnText},
+			{"email", columnText}, {"created_at", columnTime}, {"last_login_at", columnTime},
+		},
+		notNull:  []string{"user_id", "issuer", "subject", "email", "created_at"},
+		defaults: map[string]any{"email": "", "created_at": defaultNow},
+		uniques:  []tableUnique{unique("unique_issuer_subject", "issuer", "subject")},
+	}
+	oidcLoginsSchema = &tableSchema{
+		name: "oidc_logins",
+		columns: []tableColumn{
+			{"id", columnInteger}, {"state_hash", columnText}, {"code_verifier", columnText}, {"nonce", columnText},
+			{"created_at", columnTime}, {"expires_at", columnTime},
+		},
+		notNull:  []string{"state_hash", "code_verifier", "nonce", "created_at", "expires_at"},
+		defaults: map[string]any{"created_at": defaultNow},
+		uniques:  []tableUnique{unique("unique_oidc_state", "state_hash")},
+	}
+	auditLogSchema = &tableSchema{
+		name: "audit_log",
+		columns: []tableColumn{
+			{"id", columnInteger}, {"user_id", columnInteger}, {"action", columnText}, {"detail", columnText},
+			{"ip", columnText}, {"created_at", columnTime},
+		},
+		notNull:  []string{"action", "detail", "ip", "created_at"},
+		defaults: map[string]any{"detail": "", "ip": "", "created_at": defaultNow},
+	}
+)
+
+// schemas are all the tables, in the order they are created
+var schemas = []*tableSchema{
+	usersSchema, walletsSchema, exchangeRatesSchema, categoriesSchema, recurringTransactionsSchema,
+	transactionsSchema, budgetsSchema, sessionsSchema, passwordResetsSchema, twoFactorsSchema,
+	apiKeysSchema, userIdentitiesSchema, oidcLoginsSchema, auditLogSchema,
+}
+
+func init() {
+	// The owner of a wallet is usually embedded with the alias user (user:users!inner(email))
+	walletsSchema.relations["user"] = walletsSchema.relations["users"]
+
+	// The foreign keys are set here because several tables reference each other
+	byUser := func(action foreignKeyAction) tableForeignKey {
+		return tableForeignKey{name: "fk_user", column: "user_id", table: usersSchema, onDelete: action}
+	}
+	walletsSchema.foreignKeys = []tableForeignKey{byUser(onDeleteCascade)}
+	categoriesSchema.foreignKeys = []tableForeignKey{
+		byUser(onDeleteCascade),
+		{name: "fk_parent", column: "parent_id", table: categoriesSchema, onDelete: onDeleteNoAction},
+	}
+	recurringTransactionsSchema.foreignKeys = []tableForeignKey{
+		byUser(onDeleteCascade),
+		{name: "fk_wallet", column: "wallet_id", table: walletsSchema, onDelete: onDeleteCascade},
+		{name: "fk_category", column: "category_id", table: categoriesSchema, onDelete: onDeleteSetNull},
+	}
+	transactionsSchema.foreignKeys = []tableForeignKey{
+		{name: "fk_wallet", column: "wallet_id", table: walletsSchema, onDelete: onDeleteCascade},
+		{name: "transactions_category_id_fkey", column: "category_id", table: categoriesSchema, onDelete: onDeleteNoAction},
+		{name: "transactions_recurring_id_fkey", column: "recurring_id", table: recurringTransactionsSchema, onDelete: onDeleteSetNull},
+	}
+	budgetsSchema.foreignKeys = []tableForeignKey{
+		byUser(onDeleteCascade),
+		{name: "fk_category", column: "category_id", table: categoriesSchema, onDelete: onDeleteCascade},
+	}
+	for _, table := range []*tableSchema{sessionsSchema, passwordResetsSchema, twoFactorsSchema, apiKeysSchema, userIdentitiesSchema} {
+		table.foreignKeys = []tableForeignKey{byUser(onDeleteCascade)}
+	}
+	auditLogSchema.foreignKeys = []tableForeignKey{byUser(onDeleteSetNull)}
+}
+
+var (
+	currencyCode = regexp.MustCompile(`^[A-Z]{3}$`)
+	budgetPeriod = regexp.MustCompile(`^[0-9]{4}-(0[1-9]|1[0-2])$`)
+)
+
+func checkIn(name, column string, values ...string) tableCheck {
+	return tableCheck{name: name, valid: func(row map[string]any) bool {
+		value, ok := row[column].(string)
+		return !ok || slices.Contains(values, value)
+	}}
+}
+
+func checkPositive(name, column string) tableCheck {
+	return tableCheck{name: name, valid: func(row map[string]any) bool {
+		value, ok := row[column].(string)
+		if !ok {
+			return true
+		}
+		number, ok := new(big.Rat).SetString(value)
+		return ok && number.Sign() > 0
+	}}
+}
+
+func checkCurrency(name, column string) tableCheck {
+	return tableCheck{name: name, valid: func(row map[string]any) bool {
+		value, ok := row[column].(string)
+		return !ok || currencyCode.MatchString(value)
+	}}
+}
+
+func checkMaxLength(name, column string, length int) tableCheck {
+	return tableCheck{name: name, valid: func(row map[string]any) bool {
+		value, ok := row[column].(string)
+		return !ok || utf8.RuneCountInString(value) <= length
+	}}
+}
+
+// unique is a UNIQUE constraint on plain columns
+func unique(name string, columns ...string) tableUnique {
+	return tableUnique{name: name, key: func(row map[string]any) (string, bool) {
+		var key strings.Builder
+		for _, column := range columns {
+			if row[column] == nil {
+				return "", false
+			}
+			fmt.Fprint(&key, row[column], "\x00")
+		}
+		return key.String(), true
+	}}
+}
+
+// narrowObjects removes from the objects the columns the select list didn't ask for
+func narrowObjects(table *tableSchema, selection selectList, objects []map[string]any) {
+	if len(selection.columns) == len(table.columns) {
+		return
+	}
+	keep := map[string]bool{}
+	for _, column := range selection.columns {
+		keep[table.jsonName(column.name)] = true
+	}
+	for _, embed := range selection.embeds {
+		keep[embed.key] = true
+	}
+	for _, object := range objects {
+		for name := range object {
+			if !keep[name] {
+				delete(object, name)
+			}
+		}
+	}
+}
@@ -0,0 +1,29 @@
+-- CreateEnum
+CREATE TYPE "TransactionType" AS ENUM ('Income', 'Expense');
+
+-- Creating the transactions table to store the ledger of every wallet
+CREATE TABLE transactions (
+    id SERIAL PRIMARY KEY,
+    wallet_id INTEGER NOT NULL,
+    type "TransactionType" NOT NULL,
+    amount DECIMAL(15,2) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_wallet
+        FOREIGN KEY (wallet_id)
+        REFERENCES wallets(id)
+        ON DELETE CASCADE,
+    CONSTRAINT positive_transaction_amount CHECK (amount > 0)
+);
+
+-- CreateIndex
+CREATE INDEX "transactions_wallet_id_created_at_idx" ON transactions(wallet_id, created_at DESC);
+
+-- Adding comments for better documentation
+COMMENT ON TABLE transactions IS 'Stores every movement of money in or out of a wallet';
+COMMENT ON COLUMN transactions.id IS 'Unique identifier for the transaction';
+COMMENT ON COLUMN transactions.wallet_id IS 'Foreign key referencing the wallet the movement belongs to';
+COMMENT ON COLUMN transactions.type IS 'Direction of the movement (Income adds to the balance, Expense subtracts)';
+COMMENT ON COLUMN transactions.amount IS 'Absolute monetary amount of the movement';
+COMMENT ON COLUMN transactions.description IS 'Free text note about the movement';
+COMMENT ON COLUMN transactions.created_at IS 'Timestamp when the movement was recorded';
@@ -0,0 +1,72 @@
+-- Moves money between two wallets of the same user.
+-- Everything runs inside the function's transaction: either both balances and both
+-- ledger entries are written, or an exception is raised and nothing changes.
+CREATE OR REPLACE FUNCTION transfer_between_wallets(
+    p_from_wallet_id INTEGER,
+    p_to_wallet_id INTEGER,
+    p_amount DECIMAL(15,2),
+    p_description TEXT DEFAULT ''
+) RETURNS JSON
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    v_from wallets%ROWTYPE;
+    v_to wallets%ROWTYPE;
+    v_debit transactions%ROWTYPE;
+    v_credit transactions%ROWTYPE;
+BEGIN
+    IF p_amount IS NULL OR p_amount <= 0 THEN
+        RAISE EXCEPTION 'transfer amount must be greater than zero';
+    END IF;
+
+    IF p_from_wallet_id = p_to_wallet_id THEN
+        RAISE EXCEPTION 'source and destination wallets must be different';
+    END IF;
+
+    -- Lock both wallets in a stable order so concurrent transfers cannot deadlock
+    PERFORM 1 FROM wallets
+    WHERE id IN (p_from_wallet_id, p_to_wallet_id)
+    ORDER BY id
+    FOR UPDATE;
+
+    SELECT * INTO v_from FROM wallets WHERE id = p_from_wallet_id;
+    IF NOT FOUND THEN
+        RAISE EXCEPTION 'source wallet not found';
+    END IF;
+
+    SELECT * INTO v_to FROM wallets WHERE id = p_to_wallet_id;
+    IF NOT FOUND THEN
+        RAISE EXCEPTION 'destination wallet not found';
+    END IF;
+
+    IF v_from.user_id <> v_to.user_id THEN
+        RAISE EXCEPTION 'wallets belong to different users';
+    END IF;
+
+    IF v_from.type = 'Debit' AND v_from.balance - p_amount < 0 THEN
+        RAISE EXCEPTION 'insufficient funds in debit wallet';
+    END IF;
+
+    UPDATE wallets SET balance = balance - p_amount WHERE id = p_from_wallet_id;
+    UPDATE wallets SET balance = balance + p_amount WHERE id = p_to_wallet_id;
+
+    INSERT INTO transactions (wallet_id, type, amount, description)
+    VALUES (p_from_wallet_id, 'Expense', p_amount, COALESCE(NULLIF(p_description, ''), 'Transfer to ' || v_to.name))
+    RETURNING * INTO v_debit;
+
+    INSERT INTO transactions (wallet_id, type, amount, description)
+    VALUES (p_to_wallet_id, 'Income', p_amount, COALESCE(NULLIF(p_description, ''), 'Transfer from ' || v_from.name))
+    RETURNING * INTO v_credit;
+
+    RETURN json_build_object(
+        'from_wallet_id', p_from_wallet_id,
+        'to_wallet_id', p_to_wallet_id,
+        'amount', p_amount,
+        'description', COALESCE(p_description, ''),
+        'debit', row_to_json(v_debit),
+        'credit', row_to_json(v_credit)
+    );
+END;
+$$;
+
+COMMENT ON FUNCTION transfer_between_wallets(INTEGER, INTEGER, DECIMAL, TEXT) IS 'Atomically moves an amount between two wallets of the same user and records both ledger entries';
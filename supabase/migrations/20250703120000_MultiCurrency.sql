@@ -0,0 +1,27 @@
+-- Wallets keep their balance in a single currency
+ALTER TABLE wallets
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'USD'
+        CHECK (currency ~ '^[A-Z]{3}$');
+
+COMMENT ON COLUMN wallets.currency IS 'ISO 4217 code of the wallet balance';
+
+-- Creating the exchange_rates table; rates are append-only so past conversions stay reproducible
+CREATE TABLE exchange_rates (
+    id SERIAL PRIMARY KEY,
+    base_currency CHAR(3) NOT NULL CHECK (base_currency ~ '^[A-Z]{3}$'),
+    quote_currency CHAR(3) NOT NULL CHECK (quote_currency ~ '^[A-Z]{3}$'),
+    rate NUMERIC(18,8) NOT NULL CHECK (rate > 0),
+    effective_date TIMESTAMPTZ NOT NULL,
+    CONSTRAINT different_currencies CHECK (base_currency <> quote_currency),
+    CONSTRAINT unique_rate_per_date UNIQUE (base_currency, quote_currency, effective_date)
+);
+
+CREATE INDEX idx_exchange_rates_pair_date ON exchange_rates(base_currency, quote_currency, effective_date DESC);
+
+-- Adding comments for better documentation
+COMMENT ON TABLE exchange_rates IS 'Historical exchange rates between currencies';
+COMMENT ON COLUMN exchange_rates.id IS 'Unique identifier for the rate';
+COMMENT ON COLUMN exchange_rates.base_currency IS 'ISO 4217 code of the currency being converted';
+COMMENT ON COLUMN exchange_rates.quote_currency IS 'ISO 4217 code of the resulting currency';
+COMMENT ON COLUMN exchange_rates.rate IS 'Units of quote_currency worth one unit of base_currency';
+COMMENT ON COLUMN exchange_rates.effective_date IS 'Moment from which the rate applies';
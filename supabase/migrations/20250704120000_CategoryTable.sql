@@ -0,0 +1,41 @@
+-- Creating the categories table; each user has a tree of categories (e.g. Food > Restaurants)
+CREATE TABLE categories (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    parent_id INTEGER,
+    name VARCHAR(100) NOT NULL,
+    type "TransactionType" NOT NULL,
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE,
+    -- NO ACTION (checked at the end of the statement) still lets a user deletion cascade through the tree;
+    -- the application reassigns children and transactions before deleting a single category
+    CONSTRAINT fk_parent
+        FOREIGN KEY (parent_id)
+        REFERENCES categories(id)
+        ON DELETE NO ACTION,
+    CONSTRAINT parent_is_not_self CHECK (parent_id IS NULL OR parent_id <> id)
+);
+
+-- Names are unique among siblings; root categories share the "0" parent
+CREATE UNIQUE INDEX unique_category_name_per_parent
+    ON categories(user_id, COALESCE(parent_id, 0), LOWER(name));
+CREATE INDEX idx_categories_parent_id ON categories(parent_id);
+
+-- Transactions can be tagged with a category
+ALTER TABLE transactions
+    ADD COLUMN category_id INTEGER
+        REFERENCES categories(id)
+        ON DELETE NO ACTION;
+
+CREATE INDEX idx_transactions_category_id ON transactions(category_id);
+
+-- Adding comments for better documentation
+COMMENT ON TABLE categories IS 'User-defined hierarchical categories for transactions';
+COMMENT ON COLUMN categories.id IS 'Unique identifier for the category';
+COMMENT ON COLUMN categories.user_id IS 'Foreign key referencing the user who owns this category';
+COMMENT ON COLUMN categories.parent_id IS 'Parent category; NULL for root categories';
+COMMENT ON COLUMN categories.name IS 'Label of the category, unique among its siblings';
+COMMENT ON COLUMN categories.type IS 'Whether the category groups incomes or expenses';
+COMMENT ON COLUMN transactions.category_id IS 'Category the transaction is tagged with';
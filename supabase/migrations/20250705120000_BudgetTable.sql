@@ -0,0 +1,29 @@
+-- Creating the budgets table to store the monthly spending limit of each category
+CREATE TABLE budgets (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    category_id INTEGER NOT NULL,
+    period CHAR(7) NOT NULL CHECK (period ~ '^[0-9]{4}-(0[1-9]|1[0-2])$'),
+    limit_amount DECIMAL(15,2) NOT NULL CHECK (limit_amount > 0),
+    currency CHAR(3) NOT NULL DEFAULT 'USD' CHECK (currency ~ '^[A-Z]{3}$'),
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_category
+        FOREIGN KEY (category_id)
+        REFERENCES categories(id)
+        ON DELETE CASCADE,
+    CONSTRAINT unique_category_period UNIQUE (user_id, category_id, period)
+);
+
+CREATE INDEX idx_budgets_user_period ON budgets(user_id, period);
+
+-- Adding comments for better documentation
+COMMENT ON TABLE budgets IS 'Monthly spending limits per category';
+COMMENT ON COLUMN budgets.id IS 'Unique identifier for the budget';
+COMMENT ON COLUMN budgets.user_id IS 'Foreign key referencing the user who owns this budget';
+COMMENT ON COLUMN budgets.category_id IS 'Expense category limited by the budget, including its subcategories';
+COMMENT ON COLUMN budgets.period IS 'Month covered by the budget (YYYY-MM)';
+COMMENT ON COLUMN budgets.limit_amount IS 'Maximum amount planned for the period';
+COMMENT ON COLUMN budgets.currency IS 'Currency of the limit; only wallets in this currency count towards it';
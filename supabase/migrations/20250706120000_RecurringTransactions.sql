@@ -0,0 +1,55 @@
+-- Creating the recurring_transactions table to store scheduled incomes and expenses
+CREATE TABLE recurring_transactions (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    wallet_id INTEGER NOT NULL,
+    type VARCHAR(10) NOT NULL CHECK (type IN ('Income', 'Expense')),
+    amount DECIMAL(15,2) NOT NULL CHECK (amount > 0),
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    category_id INTEGER,
+    frequency VARCHAR(10) NOT NULL CHECK (frequency IN ('DAILY', 'WEEKLY', 'MONTHLY', 'YEARLY')),
+    interval INTEGER NOT NULL DEFAULT 1 CHECK (interval > 0),
+    by_month_day INTEGER[],
+    start_date TIMESTAMP WITH TIME ZONE NOT NULL,
+    end_date TIMESTAMP WITH TIME ZONE,
+    status VARCHAR(10) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'paused', 'ended')),
+    next_run_at TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_wallet
+        FOREIGN KEY (wallet_id)
+        REFERENCES wallets(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_category
+        FOREIGN KEY (category_id)
+        REFERENCES categories(id)
+        ON DELETE SET NULL,
+    CONSTRAINT end_after_start CHECK (end_date IS NULL OR end_date >= start_date)
+);
+
+-- The scheduler looks up the active series that are due
+CREATE INDEX idx_recurring_due ON recurring_transactions(status, next_run_at);
+CREATE INDEX idx_recurring_user_id ON recurring_transactions(user_id);
+
+-- Every posting remembers the occurrence it belongs to, so it can never be posted twice
+ALTER TABLE transactions
+    ADD COLUMN recurring_id INTEGER REFERENCES recurring_transactions(id) ON DELETE SET NULL,
+    ADD COLUMN occurrence_date TIMESTAMP WITH TIME ZONE,
+    ADD CONSTRAINT unique_recurring_occurrence UNIQUE (recurring_id, occurrence_date);
+
+-- Adding comments for better documentation
+COMMENT ON TABLE recurring_transactions IS 'Incomes and expenses posted automatically on a schedule';
+COMMENT ON COLUMN recurring_transactions.id IS 'Unique identifier for the series';
+COMMENT ON COLUMN recurring_transactions.user_id IS 'Foreign key referencing the user who owns this series';
+COMMENT ON COLUMN recurring_transactions.wallet_id IS 'Wallet that receives the postings';
+COMMENT ON COLUMN recurring_transactions.frequency IS 'Unit of the schedule, as the FREQ part of an RRULE';
+COMMENT ON COLUMN recurring_transactions.interval IS 'Number of frequency units between periods';
+COMMENT ON COLUMN recurring_transactions.by_month_day IS 'Days of the month to post on (negative values count from the end)';
+COMMENT ON COLUMN recurring_transactions.start_date IS 'First possible occurrence; sets the time of day of every posting';
+COMMENT ON COLUMN recurring_transactions.end_date IS 'Last instant an occurrence may fall on';
+COMMENT ON COLUMN recurring_transactions.status IS 'active, paused or ended';
+COMMENT ON COLUMN recurring_transactions.next_run_at IS 'Next occurrence still to be posted';
+COMMENT ON COLUMN transactions.recurring_id IS 'Recurring series that posted this transaction';
+COMMENT ON COLUMN transactions.occurrence_date IS 'Scheduled occurrence posted by this transaction';
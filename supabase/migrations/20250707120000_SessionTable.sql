@@ -0,0 +1,36 @@
+-- Creating the sessions table that backs refresh tokens and access token revocation
+CREATE TABLE sessions (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    access_token_id VARCHAR(64) NOT NULL,
+    refresh_token_hash CHAR(64) NOT NULL,
+    previous_refresh_hash CHAR(64),
+    device VARCHAR(512) NOT NULL DEFAULT '',
+    ip VARCHAR(45) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_seen_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE,
+    CONSTRAINT unique_access_token_id UNIQUE (access_token_id),
+    CONSTRAINT unique_refresh_token_hash UNIQUE (refresh_token_hash)
+);
+
+CREATE INDEX idx_sessions_user ON sessions(user_id);
+CREATE INDEX idx_sessions_previous_refresh_hash ON sessions(previous_refresh_hash);
+
+-- Adding comments for better documentation
+COMMENT ON TABLE sessions IS 'Logins of users per device, with their current refresh token';
+COMMENT ON COLUMN sessions.id IS 'Unique identifier for the session';
+COMMENT ON COLUMN sessions.user_id IS 'Foreign key referencing the user who logged in';
+COMMENT ON COLUMN sessions.access_token_id IS 'jti of the last access token issued; older access tokens are rejected';
+COMMENT ON COLUMN sessions.refresh_token_hash IS 'SHA-256 of the current refresh token (the token itself is never stored)';
+COMMENT ON COLUMN sessions.previous_refresh_hash IS 'Hash of the refresh token replaced by the last rotation; reusing it revokes the session';
+COMMENT ON COLUMN sessions.device IS 'User agent that opened or last refreshed the session';
+COMMENT ON COLUMN sessions.ip IS 'Client address that opened or last refreshed the session';
+COMMENT ON COLUMN sessions.last_seen_at IS 'Last time the session was refreshed or used';
+COMMENT ON COLUMN sessions.expires_at IS 'When the refresh token stops being accepted';
+COMMENT ON COLUMN sessions.revoked_at IS 'Set when the session is logged out';
@@ -0,0 +1,5 @@
+-- Accounts were created as 'inactive' with no way to activate them, and could log in anyway.
+-- Login now requires a verified email, so the accounts that already exist are treated as verified.
+UPDATE users SET status = 'active' WHERE status IN ('inactive', 'pending');
+
+COMMENT ON COLUMN "users"."status" IS 'Current state of the user''s account; new accounts stay inactive until their email is verified';
@@ -0,0 +1,25 @@
+-- Creating the password_resets table with the single-use tokens of forgotten-password requests
+CREATE TABLE password_resets (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    token_hash CHAR(64) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE,
+    CONSTRAINT unique_token_hash UNIQUE (token_hash)
+);
+
+CREATE INDEX idx_password_resets_user ON password_resets(user_id);
+
+-- Adding comments for better documentation
+COMMENT ON TABLE password_resets IS 'Single-use tokens emailed to users who forgot their password';
+COMMENT ON COLUMN password_resets.id IS 'Unique identifier for the reset';
+COMMENT ON COLUMN password_resets.user_id IS 'Foreign key referencing the account being recovered';
+COMMENT ON COLUMN password_resets.token_hash IS 'SHA-256 of the emailed token (the token itself is never stored)';
+COMMENT ON COLUMN password_resets.created_at IS 'When the reset was requested';
+COMMENT ON COLUMN password_resets.expires_at IS 'When the token stops being accepted';
+COMMENT ON COLUMN password_resets.used_at IS 'Set once the token is used or replaced by a successful reset';
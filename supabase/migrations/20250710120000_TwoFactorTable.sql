@@ -0,0 +1,25 @@
+-- Creating the two_factors table with the TOTP enrollment of users
+CREATE TABLE two_factors (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    secret VARCHAR(64) NOT NULL,
+    confirmed_at TIMESTAMP WITH TIME ZONE,
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    recovery_codes TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE,
+    CONSTRAINT unique_user_two_factor UNIQUE (user_id)
+);
+
+-- Adding comments for better documentation
+COMMENT ON TABLE two_factors IS 'TOTP two-factor authentication of users';
+COMMENT ON COLUMN two_factors.id IS 'Unique identifier for the enrollment';
+COMMENT ON COLUMN two_factors.user_id IS 'Foreign key referencing the enrolled account';
+COMMENT ON COLUMN two_factors.secret IS 'Base32 secret shared with the authenticator app';
+COMMENT ON COLUMN two_factors.confirmed_at IS 'Set once the user entered a first valid code; until then two-factor is not enforced';
+COMMENT ON COLUMN two_factors.last_used_step IS 'Time step of the last accepted code, so a code is never accepted twice';
+COMMENT ON COLUMN two_factors.recovery_codes IS 'SHA-256 of the unused recovery codes';
+COMMENT ON COLUMN two_factors.created_at IS 'When the setup started';
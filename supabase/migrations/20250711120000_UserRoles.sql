@@ -0,0 +1,10 @@
+-- Adding roles to users: plain users only reach their own data, support staff can inspect
+-- accounts and wallets, and admins can also suspend accounts and change roles
+ALTER TABLE users
+    ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'user',
+    ADD CONSTRAINT valid_user_role CHECK (role IN ('user', 'support', 'admin'));
+
+-- Promote the first admin by hand, e.g.:
+-- UPDATE users SET role = 'admin' WHERE email = 'owner@example.com';
+
+COMMENT ON COLUMN "users"."role" IS 'Role of the account: user, support or admin';
@@ -0,0 +1,33 @@
+-- Creating the api_keys table with the personal API keys users create for scripts
+CREATE TABLE api_keys (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    prefix VARCHAR(20) NOT NULL,
+    key_hash CHAR(64) NOT NULL,
+    scopes TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE,
+    CONSTRAINT unique_api_key_hash UNIQUE (key_hash)
+);
+
+CREATE INDEX idx_api_keys_user ON api_keys(user_id);
+
+-- Adding comments for better documentation
+COMMENT ON TABLE api_keys IS 'Personal API keys, sent in the X-API-Key header';
+COMMENT ON COLUMN api_keys.id IS 'Unique identifier for the key';
+COMMENT ON COLUMN api_keys.user_id IS 'Foreign key referencing the owner of the key';
+COMMENT ON COLUMN api_keys.name IS 'Label given by the user';
+COMMENT ON COLUMN api_keys.prefix IS 'Start of the key, shown so users can tell keys apart';
+COMMENT ON COLUMN api_keys.key_hash IS 'SHA-256 of the key (the key itself is never stored)';
+COMMENT ON COLUMN api_keys.scopes IS 'Access granted: wallets:read, wallets:write, account:read, account:write';
+COMMENT ON COLUMN api_keys.created_at IS 'When the key was created';
+COMMENT ON COLUMN api_keys.expires_at IS 'When the key stops working; NULL for keys that do not expire';
+COMMENT ON COLUMN api_keys.last_used_at IS 'When the key last authenticated a request';
+COMMENT ON COLUMN api_keys.revoked_at IS 'Set when the user revokes the key';
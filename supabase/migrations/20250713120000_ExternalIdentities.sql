@@ -0,0 +1,44 @@
+-- Creating the user_identities table that links accounts to OpenID Connect providers
+CREATE TABLE user_identities (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    issuer VARCHAR(255) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_login_at TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE,
+    CONSTRAINT unique_issuer_subject UNIQUE (issuer, subject)
+);
+
+CREATE INDEX idx_user_identities_user ON user_identities(user_id);
+
+-- Creating the oidc_logins table with the logins waiting for the provider callback
+CREATE TABLE oidc_logins (
+    id SERIAL PRIMARY KEY,
+    state_hash CHAR(64) NOT NULL,
+    code_verifier VARCHAR(128) NOT NULL,
+    nonce VARCHAR(128) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    CONSTRAINT unique_oidc_state UNIQUE (state_hash)
+);
+
+-- Adding comments for better documentation
+COMMENT ON TABLE user_identities IS 'Accounts of external identity providers linked to local accounts';
+COMMENT ON COLUMN user_identities.id IS 'Unique identifier for the link';
+COMMENT ON COLUMN user_identities.user_id IS 'Foreign key referencing the linked account';
+COMMENT ON COLUMN user_identities.issuer IS 'Issuer URL of the provider (iss claim)';
+COMMENT ON COLUMN user_identities.subject IS 'ID of the user at the provider (sub claim)';
+COMMENT ON COLUMN user_identities.email IS 'Verified email reported by the provider when the link was made';
+COMMENT ON COLUMN user_identities.created_at IS 'When the link was made';
+COMMENT ON COLUMN user_identities.last_login_at IS 'When the identity was last used to log in';
+
+COMMENT ON TABLE oidc_logins IS 'Logins started against the identity provider and not completed yet';
+COMMENT ON COLUMN oidc_logins.state_hash IS 'SHA-256 of the state sent to the provider';
+COMMENT ON COLUMN oidc_logins.code_verifier IS 'PKCE verifier, sent with the authorization code';
+COMMENT ON COLUMN oidc_logins.nonce IS 'Value the ID token must carry';
+COMMENT ON COLUMN oidc_logins.expires_at IS 'When the callback stops being accepted';
@@ -0,0 +1,68 @@
+-- Creating the login_attempts table with the failed login counters of accounts and client addresses.
+-- Keys look like 'user:42', 'login:someone@example.com' (unknown accounts) or 'ip:203.0.113.7'
+CREATE TABLE login_attempts (
+    key VARCHAR(320) PRIMARY KEY,
+    failures INTEGER NOT NULL DEFAULT 0,
+    last_failure_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    locked_until TIMESTAMP WITH TIME ZONE,
+    previous_status VARCHAR(20)
+);
+
+-- Adding comments for better documentation
+COMMENT ON TABLE login_attempts IS 'Failed logins per account and per client address, for backoff and lockouts';
+COMMENT ON COLUMN login_attempts.key IS 'What failed to log in: user:<id>, login:<identifier> or ip:<address>';
+COMMENT ON COLUMN login_attempts.failures IS 'Failed logins since the count was last reset';
+COMMENT ON COLUMN login_attempts.last_failure_at IS 'When the last failed login happened';
+COMMENT ON COLUMN login_attempts.locked_until IS 'Set while the key is locked out';
+COMMENT ON COLUMN login_attempts.previous_status IS 'Status of the account before the lockout suspended it';
+
+-- Counts a failed login in a single statement, so instances sharing the table never lose one.
+-- A count whose last failure is older than the window starts again from one
+CREATE OR REPLACE FUNCTION record_login_failure(
+    p_key VARCHAR(320),
+    p_at TIMESTAMP WITH TIME ZONE,
+    p_window_seconds INTEGER
+) RETURNS JSON
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    v_attempt login_attempts%ROWTYPE;
+BEGIN
+    INSERT INTO login_attempts AS a (key, failures, last_failure_at)
+    VALUES (p_key, 1, p_at)
+    ON CONFLICT (key) DO UPDATE SET
+        failures = CASE
+            WHEN a.last_failure_at <= p_at - make_interval(secs => p_window_seconds) THEN 1
+            ELSE a.failures + 1
+        END,
+        last_failure_at = p_at
+    RETURNING * INTO v_attempt;
+
+    RETURN row_to_json(v_attempt);
+END;
+$$;
+
+-- Creating the audit_log table with security events such as lockouts
+CREATE TABLE audit_log (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER,
+    action VARCHAR(50) NOT NULL,
+    detail TEXT NOT NULL DEFAULT '',
+    ip VARCHAR(45) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(id)
+        ON DELETE SET NULL
+);
+
+CREATE INDEX idx_audit_log_user ON audit_log(user_id);
+CREATE INDEX idx_audit_log_created_at ON audit_log(created_at);
+
+COMMENT ON TABLE audit_log IS 'Security events, e.g. accounts locked out after failed logins';
+COMMENT ON COLUMN audit_log.id IS 'Unique identifier for the entry';
+COMMENT ON COLUMN audit_log.user_id IS 'Account the event concerns; NULL for unknown accounts';
+COMMENT ON COLUMN audit_log.action IS 'What happened: account_locked, account_unlocked';
+COMMENT ON COLUMN audit_log.detail IS 'Description of the event';
+COMMENT ON COLUMN audit_log.ip IS 'Client address that caused the event';
+COMMENT ON COLUMN audit_log.created_at IS 'When the event happened';
@@ -0,0 +1,77 @@
+-- Takes the next id of a table that can be written by a unit of work. The client inserts rows
+-- with the ids it reserved, so later writes of the same unit of work can refer to them.
+CREATE OR REPLACE FUNCTION reserve_row_id(p_table TEXT) RETURNS BIGINT
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    IF p_table IS NULL OR p_table NOT IN ('users', 'wallets', 'transactions', 'categories') THEN
+        RAISE EXCEPTION 'table % is not allowed in a unit of work', p_table;
+    END IF;
+    RETURN nextval(pg_get_serial_sequence(p_table, 'id'));
+END;
+$$;
+
+COMMENT ON FUNCTION reserve_row_id(TEXT) IS 'Reserves the id of a row a unit of work is going to insert';
+
+-- Applies the writes of a unit of work (e.g. an account and its default categories).
+-- Everything runs inside the function's transaction: either every write is kept, or an
+-- exception is raised and nothing changes.
+--
+-- p_operations is an array of {"op": "insert" | "update" | "delete", "table", "id", "values"},
+-- with values keyed by column name. Inserts carry the id reserved with reserve_row_id.
+-- Returns the row written by every operation, in order (null for deletes).
+CREATE OR REPLACE FUNCTION apply_unit_of_work(p_operations JSONB) RETURNS JSONB
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    v_operation JSONB;
+    v_table TEXT;
+    v_id BIGINT;
+    v_values JSONB;
+    v_columns TEXT;
+    v_row JSONB;
+    v_results JSONB := '[]';
+BEGIN
+    FOR v_operation IN SELECT value FROM jsonb_array_elements(p_operations) LOOP
+        v_table := v_operation->>'table';
+        IF v_table IS NULL OR v_table NOT IN ('users', 'wallets', 'transactions', 'categories') THEN
+            RAISE EXCEPTION 'table % is not allowed in a unit of work', v_table;
+        END IF;
+
+        v_values := COALESCE(v_operation->'values', '{}');
+        v_id := (v_operation->>'id')::BIGINT;
+
+        CASE v_operation->>'op'
+        WHEN 'insert' THEN
+            SELECT string_agg(quote_ident(key), ', ') INTO v_columns FROM jsonb_object_keys(v_values) AS key;
+            IF v_columns IS NULL THEN
+                EXECUTE format('INSERT INTO %I DEFAULT VALUES RETURNING to_jsonb(%I.*)', v_table, v_table)
+                INTO v_row;
+            ELSE
+                EXECUTE format('INSERT INTO %I (%s) SELECT %s FROM jsonb_populate_record(NULL::%I, $1) RETURNING to_jsonb(%I.*)',
+                    v_table, v_columns, v_columns, v_table, v_table)
+                INTO v_row USING v_values;
+            END IF;
+        WHEN 'update' THEN
+            SELECT string_agg(format('%I = r.%I', key, key), ', ') INTO v_columns FROM jsonb_object_keys(v_values) AS key;
+            EXECUTE format('UPDATE %I AS t SET %s FROM jsonb_populate_record(NULL::%I, $1) AS r WHERE t.id = $2 RETURNING to_jsonb(t.*)',
+                v_table, COALESCE(v_columns, 'id = t.id'), v_table)
+            INTO v_row USING v_values, v_id;
+            IF v_row IS NULL THEN
+                RAISE EXCEPTION '% row % not found', v_table, v_id;
+            END IF;
+        WHEN 'delete' THEN
+            EXECUTE format('DELETE FROM %I WHERE id = $1', v_table) USING v_id;
+            v_row := 'null';
+        ELSE
+            RAISE EXCEPTION 'unknown unit of work operation %', v_operation->>'op';
+        END CASE;
+
+        v_results := v_results || jsonb_build_array(v_row);
+    END LOOP;
+
+    RETURN v_results;
+END;
+$$;
+
+COMMENT ON FUNCTION apply_unit_of_work(JSONB) IS 'Atomically applies the inserts, updates and deletes of a unit of work on users, wallets, transactions and categories';
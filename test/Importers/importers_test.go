@@ -0,0 +1,134 @@
+package Importers_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	request "Financial/Core/Models/dtos/Request"
+	"Financial/Core/importers"
+	"Financial/Core/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParsers(t *testing.T) {
+	tests := []struct {
+		name      string
+		format    string
+		mapping   request.ColumnMapping
+		content   string
+		expected  []importers.Line
+		expectErr string
+	}{
+		{
+			name:    "csv with a signed amount column",
+			format:  "csv",
+			mapping: request.ColumnMapping{Date: "Date", Amount: "Amount", Description: "Description"},
+			content: "Date,Description,Amount\n2025-07-01,Salary,\"1,500.00\"\n2025-07-02,Coffee shop,-3.50\n",
+			expected: []importers.Line{
+				{Number: 2, Date: date(2025, time.July, 1), Amount: types.NewMoney(150000, ""), Description: "Salary"},
+				{Number: 3, Date: date(2025, time.July, 2), Amount: types.NewMoney(-350, ""), Description: "Coffee shop"},
+			},
+		},
+		{
+			name:   "csv with debit and credit columns, decimal comma and no header",
+			format: "CSV",
+			mapping: request.ColumnMapping{
+				Date: "0", Description: "1", Debit: "2", Credit: "3",
+				DateFormat: "02/01/2006", Delimiter: ";", DecimalComma: true, NoHeader: true,
+			},
+			content: "05/07/2025;Supermercado;1.234,50;\n06/07/2025;Reembolso;;20,00\n",
+			expected: []importers.Line{
+				{Number: 1, Date: date(2025, time.July, 5), Amount: types.NewMoney(-123450, ""), Description: "Supermercado"},
+				{Number: 2, Date: date(2025, time.July, 6), Amount: types.NewMoney(2000, ""), Description: "Reembolso"},
+			},
+		},
+		{
+			name:      "csv mapping an unknown column",
+			format:    "csv",
+			mapping:   request.ColumnMapping{Date: "Fecha", Amount: "Amount", Description: "Description"},
+			content:   "Date,Description,Amount\n",
+			expectErr: `column "Fecha" not found`,
+		},
+		{
+			name:   "ofx 1.x sgml",
+			format: "ofx",
+			content: `OFXHEADER:100
+DATA:OFXSGML
+<OFX><BANKMSGSRSV1><STMTTRNRS><STMTRS><BANKTRANLIST>
+<STMTTRN><TRNTYPE>DEBIT<DTPOSTED>20250703120000[-4:EDT]<TRNAMT>-42.10<FITID>1<NAME>GROCERY STORE<MEMO>Card 1234</STMTTRN>
+<STMTTRN><TRNTYPE>CREDIT<DTPOSTED>20250715<TRNAMT>2000.00<FITID>2<NAME>PAYROLL</STMTTRN>
+</BANKTRANLIST></STMTRS></STMTTRNRS></BANKMSGSRSV1></OFX>`,
+			expected: []importers.Line{
+				{Number: 1, Date: date(2025, time.July, 3), Amount: types.NewMoney(-4210, ""), Description: "GROCERY STORE Card 1234"},
+				{Number: 2, Date: date(2025, time.July, 15), Amount: types.NewMoney(200000, ""), Description: "PAYROLL"},
+			},
+		},
+		{
+			name:   "ofx 2.x xml",
+			format: "ofx",
+			content: `<?xml version="1.0"?><OFX><BANKTRANLIST>
+<STMTTRN><DTPOSTED>20250801</DTPOSTED><TRNAMT>-9.99</TRNAMT><NAME>Streaming</NAME></STMTTRN>
+</BANKTRANLIST></OFX>`,
+			expected: []importers.Line{
+				{Number: 1, Date: date(2025, time.August, 1), Amount: types.NewMoney(-999, ""), Description: "Streaming"},
+			},
+		},
+		{
+			name:    "qif",
+			format:  "qif",
+			content: "!Type:Bank\nD07/04'25\nT-1,200.00\nPRent\nMJuly\n^\nD7/5/2025\nU15.00\nPInterest\n^\n",
+			expected: []importers.Line{
+				{Number: 2, Date: date(2025, time.July, 4), Amount: types.NewMoney(-120000, ""), Description: "Rent July"},
+				{Number: 7, Date: date(2025, time.July, 5), Amount: types.NewMoney(1500, ""), Description: "Interest"},
+			},
+		},
+		{
+			name:      "qif record without terminator",
+			format:    "qif",
+			content:   "!Type:Bank\nD07/04/2025\nT-10.00\n",
+			expectErr: "record is not terminated with ^",
+		},
+		{
+			name:      "unknown format",
+			format:    "xlsx",
+			expectErr: "unsupported statement format",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser, err := importers.NewParser(tt.format, tt.mapping)
+			if err == nil {
+				var lines []importers.Line
+				lines, err = parser.Parse(strings.NewReader(tt.content))
+				if tt.expectErr == "" {
+					assert.NoError(t, err)
+					assert.Equal(t, tt.expected, lines)
+					return
+				}
+			}
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectErr)
+		})
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	day := date(2025, time.July, 3)
+	afternoon := day.Add(15 * time.Hour)
+
+	assert.Equal(t,
+		importers.Fingerprint(day, types.NewMoney(-4210, "USD"), "Grocery  Store"),
+		importers.Fingerprint(afternoon, types.NewMoney(-4210, "USD"), "grocery store"),
+		"time of day, case and spacing are ignored")
+	assert.NotEqual(t,
+		importers.Fingerprint(day, types.NewMoney(-4210, "USD"), "Grocery Store"),
+		importers.Fingerprint(day, types.NewMoney(4210, "USD"), "Grocery Store"),
+		"the sign of the amount matters")
+}
@@ -0,0 +1,38 @@
+package Infrastructure_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"Financial/Core/ports"
+	"Financial/persistence/infrastructure"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileMailer(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	mailer, err := infrastructure.NewFileMailer(dir)
+	require.NoError(t, err)
+
+	err = mailer.Send(ports.MailMessage{
+		To:      "ana@example.com\r\nBcc: everyone@example.com",
+		Subject: "Confirm your email address",
+		Body:    "Open this link:\nhttp://localhost/verify?token=abc\n",
+	})
+	require.NoError(t, err)
+
+	files, _ := os.ReadDir(dir)
+	require.Len(t, files, 1)
+	assert.True(t, strings.HasSuffix(files[0].Name(), ".eml"))
+	assert.NotContains(t, files[0].Name(), "/")
+
+	content, _ := os.ReadFile(filepath.Join(dir, files[0].Name()))
+	text := string(content)
+	assert.Contains(t, text, "To: ana@example.comBcc: everyone@example.com\r\n", "line breaks can't inject headers")
+	assert.Contains(t, text, "Subject: Confirm your email address\r\n")
+	assert.Contains(t, text, "\r\n\r\nOpen this link:\r\nhttp://localhost/verify?token=abc\r\n")
+}
@@ -0,0 +1,123 @@
+package Infrastructure_test
+
+import (
+	"testing"
+
+	"Financial/Core/Models/db"
+	"Financial/Core/ports"
+	"Financial/Core/types"
+	"Financial/persistence/infrastructure"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryRepositories(t *testing.T) {
+	runRepositoryContract(t, func(t *testing.T) repositories {
+		store := infrastructure.NewMemoryStore()
+		return repositories{
+			users:   infrastructure.NewMemoryUserRepository(store),
+			wallets: infrastructure.NewMemoryWalletRepository(store),
+		}
+	})
+}
+
+func TestMemoryTransfer(t *testing.T) {
+	store := infrastructure.NewMemoryStore()
+	runTransferContract(t, repositories{
+		users:   infrastructure.NewMemoryUserRepository(store),
+		wallets: infrastructure.NewMemoryWalletRepository(store),
+	}, infrastructure.NewMemoryTransferRepository(store))
+}
+
+func TestMemoryUnitOfWork(t *testing.T) {
+	store := infrastructure.NewMemoryStore()
+	runUnitOfWorkContract(t, repositories{
+		users:   infrastructure.NewMemoryUserRepository(store),
+		wallets: infrastructure.NewMemoryWalletRepository(store),
+	}, infrastructure.NewMemoryUnitOfWork(store))
+}
+
+func TestMemorySessionRotation(t *testing.T) {
+	store := infrastructure.NewMemoryStore()
+	runSessionRotationContract(t, repositories{
+		users:   infrastructure.NewMemoryUserRepository(store),
+		wallets: infrastructure.NewMemoryWalletRepository(store),
+	}, infrastructure.NewMemorySessionRepository(store))
+}
+
+func TestMemoryStoreConstraints(t *testing.T) {
+	store := infrastructure.NewMemoryStore()
+	repos := repositories{
+		users:   infrastructure.NewMemoryUserRepository(store),
+		wallets: infrastructure.NewMemoryWalletRepository(store),
+	}
+	transactions := infrastructure.NewMemoryTransactionRepository(store)
+	categories := infrastructure.NewMemoryCategoryRepository(store)
+	audit := infrastructure.NewMemoryAuditRepository(store)
+
+	ana := createUser(t, repos, "ana")
+	wallet := createWallet(t, repos, ana.ID, "Savings")
+
+	t.Run("foreign keys must reference existing rows", func(t *testing.T) {
+		_, err := repos.wallets.Create(&db.Wallet{Name: "Orphan", Type: types.Debit, Currency: "USD", UserID: 404})
+		assert.ErrorContains(t, err, "fk_user")
+	})
+
+	t.Run("checks reject invalid values", func(t *testing.T) {
+		_, err := repos.wallets.Create(&db.Wallet{Name: "Bad", Type: "Savings", Currency: "USD", UserID: ana.ID})
+		assert.ErrorContains(t, err, "wallets_type_check")
+		_, err = repos.wallets.Create(&db.Wallet{Name: "Bad", Type: types.Debit, Currency: "usd", UserID: ana.ID})
+		assert.ErrorContains(t, err, "wallets_currency_check")
+		_, err = transactions.Create(&db.Transaction{WalletID: wallet.ID, Type: types.Income, Amount: types.NewMoney(0, "USD")})
+		assert.ErrorContains(t, err, "positive_transaction_amount")
+	})
+
+	t.Run("category names are unique per parent ignoring case", func(t *testing.T) {
+		_, err := categories.Create(&db.Category{UserID: ana.ID, Name: "Food", Type: types.Expense})
+		require.NoError(t, err)
+		_, err = categories.Create(&db.Category{UserID: ana.ID, Name: "food", Type: types.Expense})
+		assert.ErrorContains(t, err, "unique_category_name_per_parent")
+	})
+
+	t.Run("deleting a user cascades and clears references", func(t *testing.T) {
+		bob := createUser(t, repos, "bob")
+		bobWallet := createWallet(t, repos, bob.ID, "Checking")
+		entry, err := transactions.Create(&db.Transaction{WalletID: bobWallet.ID, Type: types.Income, Amount: types.NewMoney(500, "USD")})
+		require.NoError(t, err)
+		logged, err := audit.Create(&db.AuditEntry{UserID: &bob.ID, Action: types.AuditAccountLocked})
+		require.NoError(t, err)
+
+		require.NoError(t, repos.users.Delete(bob.ID))
+
+		_, err = repos.wallets.GetByID(bobWallet.ID)
+		assert.ErrorIs(t, err, types.ErrNotFound)
+		_, err = transactions.GetByID(entry.ID)
+		assert.ErrorIs(t, err, types.ErrNotFound)
+		kept, err := audit.GetByID(logged.ID)
+		require.NoError(t, err)
+		assert.Nil(t, kept.UserID, "the audit log keeps the entry without the user")
+	})
+
+	t.Run("a category in use can't be deleted", func(t *testing.T) {
+		category, err := categories.Create(&db.Category{UserID: ana.ID, Name: "Salary", Type: types.Income})
+		require.NoError(t, err)
+		_, err = transactions.Create(&db.Transaction{WalletID: wallet.ID, Type: types.Income, Amount: types.NewMoney(100, "USD"), CategoryID: &category.ID})
+		require.NoError(t, err)
+
+		assert.ErrorContains(t, categories.Delete(category.ID), "violates foreign key constraint")
+		_, err = categories.GetByID(category.ID)
+		assert.NoError(t, err, "a failed delete leaves the row")
+	})
+
+	t.Run("wallets embed their transactions", func(t *testing.T) {
+		page, err := repos.wallets.Query("id,transactions(id,amount)", ports.QueryOptions{
+			Filters: []ports.Filter{{Field: "id", Operator: "eq", Value: wallet.ID}},
+		})
+		require.NoError(t, err)
+		wallets := page.Items
+		require.Len(t, wallets, 1)
+		require.Len(t, wallets[0].Transactions, 1)
+		assert.Equal(t, int64(100), wallets[0].Transactions[0].Amount.Minor)
+	})
+}
@@ -0,0 +1,64 @@
+package Infrastructure_test
+
+import (
+	"strings"
+	"testing"
+
+	"Financial/Core/types"
+	"Financial/persistence/infrastructure"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// testParams keeps the tests fast; production uses DefaultArgon2Params
+var testParams = infrastructure.Argon2Params{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+func TestArgon2PasswordHasher(t *testing.T) {
+	hasher := infrastructure.NewArgon2PasswordHasher(testParams)
+
+	hash, err := hasher.Hash("correct horse battery staple")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=64,t=1,p=1$"))
+
+	other, _ := hasher.Hash("correct horse battery staple")
+	assert.NotEqual(t, hash, other, "every hash gets its own salt")
+
+	ok, err := hasher.Verify("correct horse battery staple", hash)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = hasher.Verify("Correct horse battery staple", hash)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.False(t, hasher.NeedsRehash(hash))
+}
+
+func TestArgon2PasswordHasher_LegacyValues(t *testing.T) {
+	hasher := infrastructure.NewArgon2PasswordHasher(testParams)
+
+	t.Run("plain text is not a hash", func(t *testing.T) {
+		_, err := hasher.Verify("secret", "secret")
+		assert.ErrorIs(t, err, types.ErrUnknownHash)
+		assert.True(t, hasher.NeedsRehash("secret"))
+	})
+
+	t.Run("bcrypt hashes are verified and upgraded", func(t *testing.T) {
+		legacy, _ := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+
+		ok, err := hasher.Verify("secret", string(legacy))
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.True(t, hasher.NeedsRehash(string(legacy)))
+	})
+
+	t.Run("weaker argon2 parameters are upgraded", func(t *testing.T) {
+		weak, _ := infrastructure.NewArgon2PasswordHasher(infrastructure.Argon2Params{Memory: 32, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}).Hash("secret")
+
+		ok, err := hasher.Verify("secret", weak)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.True(t, hasher.NeedsRehash(weak))
+	})
+}
@@ -0,0 +1,127 @@
+package Infrastructure_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"Financial/Core/Models/db"
+	"Financial/Core/ports"
+	"Financial/Core/types"
+	"Financial/persistence/infrastructure"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPostgresRepositories runs the contract against the database in TEST_DATABASE_URL.
+// The tables are emptied before every case, so never point it at a database with real data.
+func TestPostgresRepositories(t *testing.T) {
+	databaseURL := os.Getenv("TEST_DATABASE_URL")
+	if databaseURL == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	ctx := context.Background()
+	pool, err := infrastructure.NewPostgresPool(ctx, databaseURL)
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+	applyMigrations(t, pool)
+
+	runRepositoryContract(t, func(t *testing.T) repositories {
+		_, err := pool.Exec(ctx, "TRUNCATE users, wallets, transactions RESTART IDENTITY CASCADE")
+		require.NoError(t, err)
+		return repositories{
+			users:   infrastructure.NewPostgresUserRepository(pool),
+			wallets: infrastructure.NewPostgresWalletRepository(pool),
+		}
+	})
+}
+
+// TestPostgresUnitOfWork runs the unit of work contract inside a database transaction
+func TestPostgresUnitOfWork(t *testing.T) {
+	databaseURL := os.Getenv("TEST_DATABASE_URL")
+	if databaseURL == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	ctx := context.Background()
+	pool, err := infrastructure.NewPostgresPool(ctx, databaseURL)
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+	applyMigrations(t, pool)
+	_, err = pool.Exec(ctx, "TRUNCATE users, wallets, transactions RESTART IDENTITY CASCADE")
+	require.NoError(t, err)
+
+	repos := repositories{
+		users:   infrastructure.NewPostgresUserRepository(pool),
+		wallets: infrastructure.NewPostgresWalletRepository(pool),
+	}
+	uow := infrastructure.NewPostgresUnitOfWork(pool)
+	runUnitOfWorkContract(t, repos, uow)
+
+	t.Run("reads see the earlier writes of the unit of work", func(t *testing.T) {
+		user := createUser(t, repos, "elena")
+		wallet := createWallet(t, repos, user.ID, "Groceries")
+		err := uow.Do(func(scoped ports.UnitOfWorkRepositories) error {
+			category, err := scoped.Categories.Create(&db.Category{Name: "Food", Type: types.Expense, UserID: user.ID})
+			if err != nil {
+				return err
+			}
+			if _, err := scoped.Categories.GetByID(category.ID); err != nil {
+				return err
+			}
+			_, err = scoped.Transactions.Create(&db.Transaction{WalletID: wallet.ID, CategoryID: &category.ID, Type: types.Expense, Amount: types.NewMoney(500, "USD"), Description: "Market"})
+			return err
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("the repositories outside read what the unit of work wrote", func(t *testing.T) {
+		user := createUser(t, repos, "fabio")
+		wallet := createWallet(t, repos, user.ID, "Travel")
+		var transactionID int
+		err := uow.Do(func(scoped ports.UnitOfWorkRepositories) error {
+			category, err := scoped.Categories.Create(&db.Category{Name: "Flights", Type: types.Expense, UserID: user.ID})
+			if err != nil {
+				return err
+			}
+			transaction, err := scoped.Transactions.Create(&db.Transaction{WalletID: wallet.ID, CategoryID: &category.ID, Type: types.Expense, Amount: types.NewMoney(9900, "USD"), Description: "Lisbon"})
+			if err != nil {
+				return err
+			}
+			transactionID = transaction.ID
+			return nil
+		})
+		require.NoError(t, err)
+
+		transaction, err := infrastructure.NewPostgresTransactionRepository(pool).GetByID(transactionID)
+		require.NoError(t, err)
+		require.Equal(t, "Lisbon", transaction.Description)
+		category, err := infrastructure.NewPostgresCategoryRepository(pool).GetByID(*transaction.CategoryID)
+		require.NoError(t, err)
+		require.Equal(t, "Flights", category.Name)
+	})
+}
+
+// applyMigrations creates the schema from supabase/migrations when the database is empty
+func applyMigrations(t *testing.T, pool *pgxpool.Pool) {
+	ctx := context.Background()
+	var exists bool
+	require.NoError(t, pool.QueryRow(ctx, "SELECT to_regclass('users') IS NOT NULL").Scan(&exists))
+	if exists {
+		return
+	}
+
+	files, err := filepath.Glob(filepath.Join("..", "..", "supabase", "migrations", "*.sql"))
+	require.NoError(t, err)
+	sort.Strings(files)
+	for _, file := range files {
+		sql, err := os.ReadFile(file)
+		require.NoError(t, err)
+		_, err = pool.Exec(ctx, string(sql))
+		require.NoError(t, err, file)
+	}
+}
@@ -0,0 +1,499 @@
+package Infrastructure_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"Financial/Core/Models/db"
+	"Financial/Core/ports"
+	"Financial/Core/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// repositories is a storage backend under test; every call of the factory gives
+// repositories over an empty store
+type repositories struct {
+	users   ports.Repository[db.User, int]
+	wallets ports.Repository[db.Wallet, int]
+}
+
+// runRepositoryContract checks the behaviour every backend must share with the Supabase one
+func runRepositoryContract(t *testing.T, newRepositories func(t *testing.T) repositories) {
+	t.Run("user create and read", func(t *testing.T) {
+		repos := newRepositories(t)
+		created := createUser(t, repos, "ana")
+
+		assert.NotZero(t, created.ID)
+		assert.Equal(t, types.RoleUser, created.Role, "an empty role is stored as a plain user")
+		assert.Equal(t, types.Active, created.Status)
+
+		found, err := repos.users.GetByID(created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "ana@example.com", found.Email)
+		assert.Equal(t, "Ana", found.FirstName)
+		assert.Equal(t, "hash", found.Password)
+		assert.WithinDuration(t, created.CreatedAt, found.CreatedAt, time.Millisecond)
+
+		byEmail, err := repos.users.FindByField("email", "ana@example.com")
+		require.NoError(t, err)
+		assert.Equal(t, created.ID, byEmail.ID)
+		byNickname, err := repos.users.FindByField("nick_name", "ana")
+		require.NoError(t, err)
+		assert.Equal(t, created.ID, byNickname.ID)
+		byID, err := repos.users.FindByField("id", created.ID)
+		require.NoError(t, err, "ints are valid filter values")
+		assert.Equal(t, created.ID, byID.ID)
+
+		all, err := repos.users.GetAll()
+		require.NoError(t, err)
+		assert.Len(t, all, 1)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		repos := newRepositories(t)
+
+		_, err := repos.users.GetByID(404)
+		assert.ErrorIs(t, err, types.ErrNotFound)
+		_, err = repos.users.FindByField("email", "nobody@example.com")
+		assert.ErrorIs(t, err, types.ErrNotFound)
+		_, err = repos.users.Update(&db.User{ID: 404, Nickname: "x", Email: "x@example.com", Status: types.Active})
+		assert.ErrorIs(t, err, types.ErrNotFound)
+	})
+
+	t.Run("user update and delete", func(t *testing.T) {
+		repos := newRepositories(t)
+		user := createUser(t, repos, "ana")
+
+		user.FirstName = "Ana María"
+		user.Status = types.Suspend
+		user.Role = types.RoleAdmin
+		updated, err := repos.users.Update(user)
+		require.NoError(t, err)
+		assert.Equal(t, "Ana María", updated.FirstName)
+		assert.Equal(t, types.Suspend, updated.Status)
+		assert.Equal(t, types.RoleAdmin, updated.Role)
+
+		require.NoError(t, repos.users.Delete(user.ID))
+		_, err = repos.users.GetByID(user.ID)
+		assert.ErrorIs(t, err, types.ErrNotFound)
+		assert.NoError(t, repos.users.Delete(user.ID), "deleting twice is not an error")
+	})
+
+	t.Run("user unique email and nickname", func(t *testing.T) {
+		repos := newRepositories(t)
+		createUser(t, repos, "ana")
+
+		_, err := repos.users.Create(&db.User{Nickname: "other", FirstName: "A", Lastname: "B",
+			Email: "ana@example.com", Status: types.Active, Password: "hash"})
+		assert.Error(t, err)
+		_, err = repos.users.Create(&db.User{Nickname: "ana", FirstName: "A", Lastname: "B",
+			Email: "other@example.com", Status: types.Active, Password: "hash"})
+		assert.Error(t, err)
+	})
+
+	t.Run("user query", func(t *testing.T) {
+		repos := newRepositories(t)
+		ana := createUser(t, repos, "ana")
+		bob := createUser(t, repos, "bob")
+		carla := createUser(t, repos, "carla")
+		carla.Status = types.Pending
+		_, err := repos.users.Update(carla)
+		require.NoError(t, err)
+
+		users := queryUsers(t, repos, ports.QueryOptions{
+			Filters: []ports.Filter{{Field: "status", Operator: "eq", Value: types.Active}},
+			OrderBy: []ports.OrderBy{{Field: "id", Ascending: false}},
+		})
+		assert.Equal(t, []int{bob.ID, ana.ID}, userIDs(users))
+
+		users = queryUsers(t, repos, ports.QueryOptions{
+			Filters: []ports.Filter{{Field: "nick_name", Operator: "in", Value: []string{"ana", "carla"}}},
+			OrderBy: []ports.OrderBy{{Field: "id", Ascending: true}},
+		})
+		assert.Equal(t, []int{ana.ID, carla.ID}, userIDs(users))
+
+		users = queryUsers(t, repos, ports.QueryOptions{
+			Filters: []ports.Filter{{Field: "id", Operator: "gt", Value: ana.ID}},
+			OrderBy: []ports.OrderBy{{Field: "id", Ascending: true}},
+		})
+		assert.Equal(t, []int{bob.ID, carla.ID}, userIDs(users))
+
+		users = queryUsers(t, repos, ports.QueryOptions{
+			Filters: []ports.Filter{{Field: "email", Operator: "ilike", Value: "%@EXAMPLE.com"}},
+			OrderBy: []ports.OrderBy{{Field: "id", Ascending: true}},
+			Limit:   intPtr(1),
+			Offset:  intPtr(1),
+		})
+		assert.Equal(t, []int{bob.ID}, userIDs(users))
+	})
+
+	t.Run("user query with groups, pages and counts", func(t *testing.T) {
+		repos := newRepositories(t)
+		ana := createUser(t, repos, "ana")
+		bob := createUser(t, repos, "bob")
+		carla := createUser(t, repos, "carla")
+		carla.Status = types.Pending
+		_, err := repos.users.Update(carla)
+		require.NoError(t, err)
+		byID := []ports.OrderBy{{Field: "id", Ascending: true}}
+
+		users := queryUsers(t, repos, ports.QueryOptions{
+			Filters: []ports.Filter{ports.Or(
+				ports.Filter{Field: "nick_name", Operator: ports.OpEq, Value: "ana"},
+				ports.Filter{Field: "id", Operator: ports.OpEq, Value: carla.ID},
+			)},
+			OrderBy: byID,
+		})
+		assert.Equal(t, []int{ana.ID, carla.ID}, userIDs(users))
+
+		users = queryUsers(t, repos, ports.QueryOptions{
+			Filters: []ports.Filter{{Field: "id", Operator: ports.OpIn, Value: []int{bob.ID, carla.ID}}},
+			OrderBy: byID,
+		})
+		assert.Equal(t, []int{bob.ID, carla.ID}, userIDs(users), "in takes typed values")
+
+		users = queryUsers(t, repos, ports.QueryOptions{
+			Filters: []ports.Filter{
+				{Field: "id", Operator: ports.OpGte, Value: ana.ID},
+				{Field: "id", Operator: ports.OpLt, Value: carla.ID},
+			},
+			OrderBy: byID,
+		})
+		assert.Equal(t, []int{ana.ID, bob.ID}, userIDs(users), "a field may be filtered more than once")
+
+		users = queryUsers(t, repos, ports.QueryOptions{
+			Filters: []ports.Filter{ports.Or(
+				ports.And(
+					ports.Filter{Field: "status", Operator: ports.OpEq, Value: types.Active},
+					ports.Filter{Field: "id", Operator: ports.OpGt, Value: ana.ID},
+				),
+				ports.Filter{Field: "email", Operator: ports.OpLike, Value: "carla@%"},
+			)},
+			OrderBy: byID,
+		})
+		assert.Equal(t, []int{bob.ID, carla.ID}, userIDs(users))
+
+		count := ports.CountExact
+		page, err := repos.users.Query("*", ports.QueryOptions{OrderBy: byID, Limit: intPtr(1), Offset: intPtr(1), Count: &count})
+		require.NoError(t, err)
+		assert.Equal(t, []int{bob.ID}, userIDs(page.Items))
+		assert.Equal(t, 1, page.Offset)
+		require.NotNil(t, page.Total)
+		assert.Equal(t, 3, *page.Total)
+		assert.True(t, page.HasMore())
+
+		page, err = repos.users.Query("*", ports.QueryOptions{OrderBy: byID, Offset: intPtr(2), Count: &count})
+		require.NoError(t, err)
+		assert.Equal(t, []int{carla.ID}, userIDs(page.Items))
+		assert.Equal(t, 3, *page.Total)
+		assert.False(t, page.HasMore())
+
+		page, err = repos.users.Query("*", ports.QueryOptions{OrderBy: []ports.OrderBy{{Field: "id", Ascending: false}}, Limit: intPtr(2)})
+		require.NoError(t, err)
+		assert.Equal(t, []int{carla.ID, bob.ID}, userIDs(page.Items), "NullsFirst is optional")
+		assert.Nil(t, page.Total, "the total is only counted on request")
+
+		_, err = repos.users.Query("*", ports.QueryOptions{Filters: []ports.Filter{{Field: "id", Operator: "between", Value: 1}}})
+		assert.ErrorContains(t, err, "unsupported filter operator")
+		_, err = repos.users.Query("*", ports.QueryOptions{Filters: []ports.Filter{{Field: "id", Operator: ports.OpEq, Value: []int{1}}}})
+		assert.ErrorContains(t, err, "unsupported type")
+	})
+
+	t.Run("wallet create and read", func(t *testing.T) {
+		repos := newRepositories(t)
+		owner := createUser(t, repos, "ana")
+		wallet := createWallet(t, repos, owner.ID, "Savings")
+
+		assert.NotZero(t, wallet.ID)
+		assert.Equal(t, owner.ID, wallet.UserID)
+		assert.Equal(t, "DOP", wallet.Currency)
+		assert.Equal(t, int64(125050), wallet.Balance.Minor)
+
+		found, err := repos.wallets.GetByID(wallet.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Savings", found.Name)
+		assert.Equal(t, types.Debit, found.Type)
+		assert.Equal(t, int64(125050), found.Balance.Minor)
+
+		byName, err := repos.wallets.FindByField("name", "Savings")
+		require.NoError(t, err)
+		assert.Equal(t, wallet.ID, byName.ID)
+
+		_, err = repos.wallets.GetByID(404)
+		assert.ErrorIs(t, err, types.ErrNotFound)
+		_, err = repos.wallets.FindByField("name", "Nope")
+		assert.ErrorIs(t, err, types.ErrNotFound)
+	})
+
+	t.Run("wallet names are unique per user", func(t *testing.T) {
+		repos := newRepositories(t)
+		ana := createUser(t, repos, "ana")
+		bob := createUser(t, repos, "bob")
+		createWallet(t, repos, ana.ID, "Savings")
+
+		_, err := repos.wallets.Create(&db.Wallet{Name: "Savings", Type: types.Credit, Currency: "DOP", UserID: ana.ID})
+		assert.Error(t, err, "unique_user_wallet_name")
+		_, err = repos.wallets.Create(&db.Wallet{Name: "Savings", Type: types.Credit, Currency: "DOP", UserID: bob.ID})
+		assert.NoError(t, err, "another user may use the same name")
+	})
+
+	t.Run("wallet update and delete", func(t *testing.T) {
+		repos := newRepositories(t)
+		owner := createUser(t, repos, "ana")
+		wallet := createWallet(t, repos, owner.ID, "Savings")
+
+		wallet.Name = "Rainy day"
+		wallet.Type = types.Credit
+		wallet.Balance = types.MustParseMoney("10.05", "DOP")
+		wallet.Currency = "USD"
+		updated, err := repos.wallets.Update(wallet)
+		require.NoError(t, err)
+		assert.Equal(t, "Rainy day", updated.Name)
+		assert.Equal(t, types.Credit, updated.Type)
+		assert.Equal(t, int64(1005), updated.Balance.Minor)
+		assert.Equal(t, "DOP", updated.Currency, "the currency cannot change")
+
+		_, err = repos.wallets.Update(&db.Wallet{ID: 404, Name: "x", Type: types.Debit})
+		assert.ErrorIs(t, err, types.ErrNotFound)
+
+		require.NoError(t, repos.wallets.Delete(wallet.ID))
+		_, err = repos.wallets.GetByID(wallet.ID)
+		assert.ErrorIs(t, err, types.ErrNotFound)
+		assert.NoError(t, repos.wallets.Delete(wallet.ID))
+	})
+
+	t.Run("wallet query with the owner embedded", func(t *testing.T) {
+		repos := newRepositories(t)
+		ana := createUser(t, repos, "ana")
+		bob := createUser(t, repos, "bob")
+		savings := createWallet(t, repos, ana.ID, "Savings")
+		checking := createWallet(t, repos, ana.ID, "Checking")
+		createWallet(t, repos, bob.ID, "Savings")
+
+		wallets := queryWallets(t, repos, "id,name,type,balance,currency,user:users!inner(email),transactions(*)", ports.QueryOptions{
+			Filters: []ports.Filter{{Field: "users.email", Operator: "eq", Value: "ana@example.com"}},
+			OrderBy: []ports.OrderBy{{Field: "id", Ascending: true}},
+		})
+		require.Len(t, wallets, 2)
+		assert.Equal(t, savings.ID, wallets[0].ID)
+		assert.Equal(t, checking.ID, wallets[1].ID)
+		require.NotNil(t, wallets[0].User)
+		assert.Equal(t, "ana@example.com", wallets[0].User.Email)
+		assert.Empty(t, wallets[0].Transactions)
+
+		wallets = queryWallets(t, repos, "*", ports.QueryOptions{
+			Filters: []ports.Filter{
+				{Field: "user_id", Operator: "eq", Value: ana.ID},
+				{Field: "name", Operator: "neq", Value: "Savings"},
+			},
+		})
+		require.Len(t, wallets, 1)
+		assert.Equal(t, checking.ID, wallets[0].ID)
+		assert.Equal(t, int64(125050), wallets[0].Balance.Minor)
+
+		wallets = queryWallets(t, repos, "id,name,currency", ports.QueryOptions{
+			Filters: []ports.Filter{{Field: "balance", Operator: "gte", Value: types.MustParseMoney("1250.50", "DOP")}},
+			OrderBy: []ports.OrderBy{{Field: "name", Ascending: true, NullsFirst: boolPtr(false)}, {Field: "id", Ascending: false}},
+			Limit:   intPtr(2),
+		})
+		require.Len(t, wallets, 2)
+		assert.Equal(t, checking.ID, wallets[0].ID)
+		assert.Equal(t, "Savings", wallets[1].Name)
+		assert.NotEqual(t, savings.ID, wallets[1].ID, "ties are ordered by the next term")
+	})
+}
+
+// runTransferContract checks that a transfer repository follows the transfer_between_wallets function
+func runTransferContract(t *testing.T, repos repositories, transfers ports.TransferRepository) {
+	user := createUser(t, repos, "ana")
+	from, err := repos.wallets.Create(&db.Wallet{Name: "Checking", Type: types.Debit, Balance: types.NewMoney(10000, "USD"), Currency: "USD", UserID: user.ID})
+	require.NoError(t, err)
+	to, err := repos.wallets.Create(&db.Wallet{Name: "Card", Type: types.Credit, Balance: types.NewMoney(0, "USD"), Currency: "USD", UserID: user.ID})
+	require.NoError(t, err)
+
+	t.Run("moves the amount and records both entries", func(t *testing.T) {
+		result, err := transfers.Transfer(&db.Transfer{FromWalletID: from.ID, ToWalletID: to.ID, Amount: types.NewMoney(2550, "USD")})
+		require.NoError(t, err)
+		require.NotNil(t, result.Debit)
+		require.NotNil(t, result.Credit)
+		assert.Equal(t, types.Expense, result.Debit.Type)
+		assert.Equal(t, "Transfer to Card", result.Debit.Description)
+		assert.Equal(t, types.Income, result.Credit.Type)
+		assert.Equal(t, "Transfer from Checking", result.Credit.Description)
+
+		source, err := repos.wallets.GetByID(from.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(7450), source.Balance.Minor)
+		destination, err := repos.wallets.GetByID(to.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2550), destination.Balance.Minor)
+	})
+
+	t.Run("rejects overdrawing a debit wallet without changing balances", func(t *testing.T) {
+		_, err := transfers.Transfer(&db.Transfer{FromWalletID: from.ID, ToWalletID: to.ID, Amount: types.NewMoney(100000, "USD")})
+		assert.ErrorContains(t, err, "insufficient funds in debit wallet")
+
+		source, err := repos.wallets.GetByID(from.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(7450), source.Balance.Minor)
+	})
+
+	t.Run("rejects invalid transfers", func(t *testing.T) {
+		_, err := transfers.Transfer(&db.Transfer{FromWalletID: from.ID, ToWalletID: from.ID, Amount: types.NewMoney(100, "USD")})
+		assert.Error(t, err)
+		_, err = transfers.Transfer(&db.Transfer{FromWalletID: from.ID, ToWalletID: 404, Amount: types.NewMoney(100, "USD")})
+		assert.ErrorContains(t, err, "destination wallet not found")
+	})
+}
+
+// runUnitOfWorkContract checks that a unit of work keeps all of its writes or none of them.
+// It only writes users and wallets, the tables every backend under test has.
+func runUnitOfWorkContract(t *testing.T, repos repositories, uow ports.UnitOfWork) {
+	t.Run("commits every write and fills in the created models", func(t *testing.T) {
+		var user *db.User
+		var wallet *db.Wallet
+		err := uow.Do(func(scoped ports.UnitOfWorkRepositories) error {
+			var err error
+			user, err = scoped.Users.Create(&db.User{Nickname: "ana", Email: "ana@example.com", Status: types.Active, Password: "hash"})
+			if err != nil {
+				return err
+			}
+			wallet, err = scoped.Wallets.Create(&db.Wallet{Name: "Checking", Type: types.Debit, Balance: types.NewMoney(1000, "USD"), Currency: "USD", UserID: user.ID})
+			return err
+		})
+		require.NoError(t, err)
+		assert.Positive(t, user.ID)
+		assert.Positive(t, wallet.ID)
+		assert.Equal(t, user.ID, wallet.UserID, "later writes can refer to rows created earlier")
+
+		stored, err := repos.wallets.GetByID(wallet.ID)
+		require.NoError(t, err)
+		assert.Equal(t, user.ID, stored.UserID)
+	})
+
+	t.Run("keeps nothing when the function fails", func(t *testing.T) {
+		err := uow.Do(func(scoped ports.UnitOfWorkRepositories) error {
+			if _, err := scoped.Users.Create(&db.User{Nickname: "bea", Email: "bea@example.com", Status: types.Active, Password: "hash"}); err != nil {
+				return err
+			}
+			return errors.New("seeding failed")
+		})
+		assert.EqualError(t, err, "seeding failed")
+
+		_, err = repos.users.FindByField("email", "bea@example.com")
+		assert.ErrorIs(t, err, types.ErrNotFound)
+	})
+
+	t.Run("keeps nothing when a write is rejected", func(t *testing.T) {
+		user := createUser(t, repos, "carla")
+		err := uow.Do(func(scoped ports.UnitOfWorkRepositories) error {
+			for _, name := range []string{"Savings", "Savings"} {
+				if _, err := scoped.Wallets.Create(&db.Wallet{Name: name, Type: types.Debit, Balance: types.NewMoney(0, "USD"), Currency: "USD", UserID: user.ID}); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		assert.Error(t, err, "wallet names are unique per user")
+
+		wallets := queryWallets(t, repos, "*", ports.QueryOptions{Filters: []ports.Filter{{Field: "user_id", Operator: "eq", Value: user.ID}}})
+		assert.Empty(t, wallets)
+	})
+
+	t.Run("deletes cascade inside the unit of work", func(t *testing.T) {
+		user := createUser(t, repos, "dora")
+		wallet := createWallet(t, repos, user.ID, "Cash")
+		err := uow.Do(func(scoped ports.UnitOfWorkRepositories) error {
+			return scoped.Users.Delete(user.ID)
+		})
+		require.NoError(t, err)
+
+		_, err = repos.wallets.GetByID(wallet.ID)
+		assert.ErrorIs(t, err, types.ErrNotFound)
+	})
+}
+
+// runSessionRotationContract checks that a refresh token rotation only applies while the
+// session still has the refresh token it was read with
+func runSessionRotationContract(t *testing.T, repos repositories, sessions ports.SessionRepository) {
+	user := createUser(t, repos, "flor")
+	now := time.Date(2025, time.July, 7, 12, 0, 0, 0, time.UTC)
+	session, err := sessions.Create(&db.Session{
+		UserID: user.ID, AccessTokenID: "access-1", RefreshTokenHash: "hash-1",
+		CreatedAt: now, LastSeenAt: now, ExpiresAt: now.Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	first := *session
+	first.AccessTokenID, first.RefreshTokenHash = "access-2", "hash-2"
+	rotated, err := sessions.RotateRefresh(&first, "hash-1")
+	require.NoError(t, err)
+	assert.Equal(t, "hash-2", rotated.RefreshTokenHash)
+
+	// A second rotation read before the first one was written
+	second := *session
+	second.AccessTokenID, second.RefreshTokenHash = "access-3", "hash-3"
+	_, err = sessions.RotateRefresh(&second, "hash-1")
+	assert.ErrorIs(t, err, types.ErrNotFound)
+
+	stored, err := sessions.GetByID(session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "hash-2", stored.RefreshTokenHash, "the losing rotation writes nothing")
+	assert.Equal(t, "access-2", stored.AccessTokenID)
+}
+
+func createUser(t *testing.T, repos repositories, nickname string) *db.User {
+	t.Helper()
+	user, err := repos.users.Create(&db.User{
+		Nickname:  nickname,
+		FirstName: "Ana",
+		Lastname:  "Pérez",
+		Email:     nickname + "@example.com",
+		Status:    types.Active,
+		CreatedAt: time.Date(2025, 7, 14, 12, 0, 0, 0, time.UTC),
+		Password:  "hash",
+	})
+	require.NoError(t, err)
+	return user
+}
+
+func createWallet(t *testing.T, repos repositories, userID int, name string) *db.Wallet {
+	t.Helper()
+	wallet, err := repos.wallets.Create(&db.Wallet{
+		Name:     name,
+		Type:     types.Debit,
+		Balance:  types.MustParseMoney("1250.50", "DOP"),
+		Currency: "DOP",
+		UserID:   userID,
+	})
+	require.NoError(t, err)
+	return wallet
+}
+
+func queryUsers(t *testing.T, repos repositories, options ports.QueryOptions) []db.User {
+	t.Helper()
+	page, err := repos.users.Query("*", options)
+	require.NoError(t, err)
+	return page.Items
+}
+
+func queryWallets(t *testing.T, repos repositories, fields string, options ports.QueryOptions) []db.Wallet {
+	t.Helper()
+	page, err := repos.wallets.Query(fields, options)
+	require.NoError(t, err)
+	return page.Items
+}
+
+func userIDs(users []db.User) []int {
+	ids := make([]int, len(users))
+	for i, user := range users {
+		ids[i] = user.ID
+	}
+	return ids
+}
+
+func intPtr(v int) *int    { return &v }
+func boolPtr(v bool) *bool { return &v }
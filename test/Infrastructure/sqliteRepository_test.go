@@ -0,0 +1,96 @@
+package Infrastructure_test
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"Financial/Core/types"
+	"Financial/persistence/infrastructure"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func openSQLite(t *testing.T) *sql.DB {
+	database, err := infrastructure.OpenSQLite(filepath.Join(t.TempDir(), "financial.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { database.Close() })
+	return database
+}
+
+func TestSQLiteRepositories(t *testing.T) {
+	runRepositoryContract(t, func(t *testing.T) repositories {
+		database := openSQLite(t)
+		return repositories{
+			users:   infrastructure.NewSQLiteUserRepository(database),
+			wallets: infrastructure.NewSQLiteWalletRepository(database),
+		}
+	})
+}
+
+func TestSQLiteSchemaIsIdempotent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "financial.db")
+	first, err := infrastructure.OpenSQLite(path)
+	require.NoError(t, err)
+	require.NoError(t, first.Close())
+
+	second, err := infrastructure.OpenSQLite(path)
+	require.NoError(t, err)
+	require.NoError(t, second.Close())
+}
+
+func TestSQLiteTransfer(t *testing.T) {
+	database := openSQLite(t)
+	runTransferContract(t, repositories{
+		users:   infrastructure.NewSQLiteUserRepository(database),
+		wallets: infrastructure.NewSQLiteWalletRepository(database),
+	}, infrastructure.NewSQLiteTransferRepository(database))
+}
+
+func TestSQLiteUnitOfWork(t *testing.T) {
+	database := openSQLite(t)
+	runUnitOfWorkContract(t, repositories{
+		users:   infrastructure.NewSQLiteUserRepository(database),
+		wallets: infrastructure.NewSQLiteWalletRepository(database),
+	}, infrastructure.NewSQLiteUnitOfWork(database))
+}
+
+func TestSQLiteSessionRotation(t *testing.T) {
+	database := openSQLite(t)
+	runSessionRotationContract(t, repositories{
+		users:   infrastructure.NewSQLiteUserRepository(database),
+		wallets: infrastructure.NewSQLiteWalletRepository(database),
+	}, infrastructure.NewSQLiteSessionRepository(database))
+}
+
+func TestSQLiteLoginAttemptStore(t *testing.T) {
+	store := infrastructure.NewSQLiteLoginAttemptStore(openSQLite(t))
+	at := time.Date(2025, 7, 14, 12, 0, 0, 0, time.UTC)
+
+	_, err := store.Get("ana@example.com")
+	assert.ErrorIs(t, err, types.ErrNotFound)
+	assert.ErrorIs(t, store.Lock("ana@example.com", at, ""), types.ErrNotFound)
+
+	attempt, err := store.RecordFailure("ana@example.com", at, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 1, attempt.Failures)
+	attempt, err = store.RecordFailure("ana@example.com", at.Add(30*time.Second), time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 2, attempt.Failures)
+	attempt, err = store.RecordFailure("ana@example.com", at.Add(2*time.Minute), time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 1, attempt.Failures, "failures older than the window are forgotten")
+
+	require.NoError(t, store.Lock("ana@example.com", at.Add(time.Hour), types.Active))
+	attempt, err = store.Get("ana@example.com")
+	require.NoError(t, err)
+	require.NotNil(t, attempt.LockedUntil)
+	assert.True(t, attempt.LockedUntil.Equal(at.Add(time.Hour)))
+	assert.Equal(t, types.Active, attempt.PreviousStatus)
+
+	require.NoError(t, store.Reset("ana@example.com"))
+	_, err = store.Get("ana@example.com")
+	assert.ErrorIs(t, err, types.ErrNotFound)
+}
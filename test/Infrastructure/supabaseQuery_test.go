@@ -0,0 +1,112 @@
+package Infrastructure_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"Financial/Core/ports"
+	"Financial/Core/types"
+	"Financial/persistence/infrastructure"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/supabase-community/supabase-go"
+)
+
+// postgrestServer answers every request with body and contentRange, and keeps the last request
+func postgrestServer(t *testing.T, body string, contentRange string) (*supabase.Client, *http.Request) {
+	t.Helper()
+	last := &http.Request{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*last = *r.Clone(r.Context())
+		if contentRange != "" {
+			w.Header().Set("Content-Range", contentRange)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := supabase.NewClient(server.URL, "test-key", nil)
+	require.NoError(t, err)
+	return client, last
+}
+
+func TestSupaBaseQuery(t *testing.T) {
+	t.Run("renders filters, groups, order and range", func(t *testing.T) {
+		client, request := postgrestServer(t, `[{"id":2,"nick_name":"bob"}]`, "1-1/3")
+		repo := infrastructure.NewSupaBaseUserRepository(client)
+
+		count := ports.CountExact
+		page, err := repo.Query("id,nick_name", ports.QueryOptions{
+			Filters: []ports.Filter{
+				{Field: "status", Operator: ports.OpEq, Value: types.Active},
+				{Field: "nick_name", Operator: ports.OpIn, Value: []string{"ana", "bob smith"}},
+				{Field: "id", Operator: ports.OpGte, Value: 1},
+				{Field: "id", Operator: ports.OpLte, Value: 10},
+				ports.Or(
+					ports.Filter{Field: "email", Operator: ports.OpIlike, Value: "%@example.com"},
+					ports.Filter{Field: "role", Operator: ports.OpIs, Value: nil},
+				),
+			},
+			OrderBy: []ports.OrderBy{{Field: "created_at", Ascending: false}},
+			Limit:   intPtr(1),
+			Offset:  intPtr(1),
+			Count:   &count,
+		})
+		require.NoError(t, err)
+
+		params := request.URL.Query()
+		assert.Equal(t, "id,nick_name", params.Get("select"))
+		assert.Equal(t, "eq.active", params.Get("status"))
+		assert.Equal(t, `in.(ana,"bob smith")`, params.Get("nick_name"))
+		assert.Equal(t, `(id.gte.1,id.lte.10,or(email.ilike."%@example.com",role.is.null))`, params.Get("and"))
+		assert.Equal(t, "created_at.desc.nullsfirst", params.Get("order"))
+		assert.Equal(t, "1", params.Get("offset"))
+		assert.Equal(t, "1", params.Get("limit"))
+		assert.Equal(t, "count=exact", request.Header.Get("Prefer"))
+
+		require.Len(t, page.Items, 1)
+		assert.Equal(t, "bob", page.Items[0].Nickname)
+		assert.Equal(t, 1, page.Offset)
+		require.NotNil(t, page.Total)
+		assert.Equal(t, 3, *page.Total)
+		assert.True(t, page.HasMore())
+	})
+
+	t.Run("without count or paging", func(t *testing.T) {
+		client, request := postgrestServer(t, `[]`, "")
+		repo := infrastructure.NewSupaBaseWalletRepository(client)
+
+		page, err := repo.Query("*", ports.QueryOptions{
+			Filters: []ports.Filter{{Field: "user_id", Operator: ports.OpEq, Value: 7}},
+			OrderBy: []ports.OrderBy{{Field: "name", Ascending: true}},
+		})
+		require.NoError(t, err)
+		assert.Empty(t, page.Items)
+		assert.Nil(t, page.Total)
+
+		params := request.URL.Query()
+		assert.Equal(t, "eq.7", params.Get("user_id"))
+		assert.Equal(t, "name.asc.nullslast", params.Get("order"))
+		assert.False(t, params.Has("limit"))
+		assert.Empty(t, request.Header.Get("Prefer"))
+	})
+
+	t.Run("rejects invalid options before sending", func(t *testing.T) {
+		client, request := postgrestServer(t, `[]`, "")
+		repo := infrastructure.NewSupaBaseUserRepository(client)
+
+		_, err := repo.Query("*", ports.QueryOptions{Filters: []ports.Filter{{Field: "id", Operator: "between", Value: 1}}})
+		assert.ErrorContains(t, err, "unsupported filter operator")
+		_, err = repo.Query("*", ports.QueryOptions{Filters: []ports.Filter{ports.Or()}})
+		assert.ErrorContains(t, err, "filter without a field", "an empty group is not a filter")
+		_, err = repo.Query("*", ports.QueryOptions{Filters: []ports.Filter{{Field: "id", Operator: ports.OpIs, Value: "maybe"}}})
+		assert.ErrorContains(t, err, "unsupported value for the is operator")
+		planned := "guess"
+		_, err = repo.Query("*", ports.QueryOptions{Count: &planned})
+		assert.ErrorContains(t, err, "unsupported count")
+		assert.Nil(t, request.URL, "nothing reaches the server")
+	})
+}
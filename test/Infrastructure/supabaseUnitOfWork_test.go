@@ -0,0 +1,139 @@
+package Infrastructure_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"Financial/Core/Models/db"
+	"Financial/Core/ports"
+	"Financial/Core/types"
+	"Financial/persistence/infrastructure"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/supabase-community/supabase-go"
+)
+
+// unitOfWorkServer answers reserve_row_id with consecutive ids from firstID,
+// apply_unit_of_work with the rows the operations write, and reads of wallets with wallet 7;
+// it keeps the operations it applied
+func unitOfWorkServer(t *testing.T, firstID int) (*supabase.Client, *[]map[string]any) {
+	t.Helper()
+	applied := &[]map[string]any{}
+	next := firstID
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/rpc/reserve_row_id"):
+			require.NoError(t, json.NewEncoder(w).Encode(next))
+			next++
+		case strings.HasSuffix(r.URL.Path, "/rpc/apply_unit_of_work"):
+			var body struct {
+				Operations []map[string]any `json:"p_operations"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			rows := []any{}
+			for _, operation := range body.Operations {
+				*applied = append(*applied, operation)
+				if operation["op"] == "delete" {
+					rows = append(rows, nil)
+					continue
+				}
+				row, _ := operation["values"].(map[string]any)
+				if id, ok := operation["id"]; ok {
+					row["id"] = id
+				}
+				rows = append(rows, row)
+			}
+			require.NoError(t, json.NewEncoder(w).Encode(rows))
+		case strings.HasSuffix(r.URL.Path, "/rest/v1/wallets") && r.Method == http.MethodGet:
+			require.NoError(t, json.NewEncoder(w).Encode([]map[string]any{
+				{"id": 7, "name": "Checking", "type": "debit", "balance": "100.00", "currency": "USD", "user_id": 3},
+			}))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := supabase.NewClient(server.URL, "test-key", nil)
+	require.NoError(t, err)
+	return client, applied
+}
+
+func TestSupaBaseUnitOfWork(t *testing.T) {
+	t.Run("inserts rows with reserved ids that later writes refer to", func(t *testing.T) {
+		client, applied := unitOfWorkServer(t, 41)
+		uow := infrastructure.NewSupaBaseUnitOfWork(client)
+
+		var user *db.User
+		var wallet *db.Wallet
+		err := uow.Do(func(scoped ports.UnitOfWorkRepositories) error {
+			var err error
+			user, err = scoped.Users.Create(&db.User{Nickname: "ana", Email: "ana@example.com", Status: types.Active, Password: "hash"})
+			if err != nil {
+				return err
+			}
+			wallet, err = scoped.Wallets.Create(&db.Wallet{Name: "Checking", Type: types.Debit, Balance: types.NewMoney(1000, "USD"), Currency: "USD", UserID: user.ID})
+			return err
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 41, user.ID)
+		assert.Equal(t, 42, wallet.ID)
+		assert.Equal(t, user.ID, wallet.UserID)
+
+		require.Len(t, *applied, 2)
+		assert.Equal(t, "users", (*applied)[0]["table"])
+		assert.EqualValues(t, 41, (*applied)[0]["values"].(map[string]any)["id"], "the insert carries the reserved id")
+		assert.Equal(t, "wallets", (*applied)[1]["table"])
+		assert.EqualValues(t, 42, (*applied)[1]["values"].(map[string]any)["id"])
+		assert.EqualValues(t, 41, (*applied)[1]["values"].(map[string]any)["user_id"], "no temporary id is sent")
+	})
+
+	t.Run("applies nothing when the function fails", func(t *testing.T) {
+		client, applied := unitOfWorkServer(t, 1)
+		uow := infrastructure.NewSupaBaseUnitOfWork(client)
+
+		err := uow.Do(func(scoped ports.UnitOfWorkRepositories) error {
+			if _, err := scoped.Users.Create(&db.User{Nickname: "bea", Email: "bea@example.com", Status: types.Active, Password: "hash"}); err != nil {
+				return err
+			}
+			return errors.New("seeding failed")
+		})
+		assert.EqualError(t, err, "seeding failed")
+		assert.Empty(t, *applied)
+	})
+
+	t.Run("checks the rows read for an update before writing them", func(t *testing.T) {
+		client, applied := unitOfWorkServer(t, 1)
+		uow := infrastructure.NewSupaBaseUnitOfWork(client)
+
+		err := uow.Do(func(scoped ports.UnitOfWorkRepositories) error {
+			wallet, err := scoped.Wallets.GetForUpdate(7)
+			if err != nil {
+				return err
+			}
+			balance, err := wallet.Balance.Add(types.MustParseMoney("25", "USD"))
+			if err != nil {
+				return err
+			}
+			wallet.Balance = balance
+			_, err = scoped.Wallets.Update(wallet)
+			return err
+		})
+		require.NoError(t, err)
+
+		require.Len(t, *applied, 2)
+		check := (*applied)[0]
+		assert.Equal(t, "check", check["op"])
+		assert.Equal(t, "wallets", check["table"])
+		assert.EqualValues(t, 7, check["id"])
+		assert.EqualValues(t, 100, check["values"].(map[string]any)["balance"], "the check carries the balance that was read")
+		assert.Equal(t, "update", (*applied)[1]["op"])
+		assert.EqualValues(t, 125, (*applied)[1]["values"].(map[string]any)["balance"])
+	})
+}
@@ -0,0 +1,69 @@
+package Middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"Financial/Core/Models/db"
+	response "Financial/Core/Models/dtos/Response"
+	contracts "Financial/Core/ports"
+	"Financial/intefaces/controllers"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// accountUpdater records the updates that reach the use case
+type accountUpdater struct {
+	contracts.UserUseCase
+	updates []db.UpdateAccountRequest
+}
+
+func (u *accountUpdater) UpdateAccount(req db.UpdateAccountRequest) (*response.SuccessResponse[*response.UpdateAccountResponse], *[]response.ErrorResponse) {
+	u.updates = append(u.updates, req)
+	return &response.SuccessResponse[*response.UpdateAccountResponse]{Message: "Updated"}, nil
+}
+
+func TestUpdateUserAccount_APIKeysCantChangeCredentials(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		viaKey   bool
+		wantCode int
+	}{
+		{name: "names with an API key", body: `{"id":1,"first_name":"Alice"}`, viaKey: true, wantCode: http.StatusOK},
+		{name: "password with an API key", body: `{"id":1,"password":"newsecret","current_password":"secret"}`, viaKey: true, wantCode: http.StatusForbidden},
+		{name: "email with an API key", body: `{"id":1,"email":"alice.new@example.com","current_password":"secret"}`, viaKey: true, wantCode: http.StatusForbidden},
+		{name: "password in a session", body: `{"id":1,"password":"newsecret","current_password":"secret"}`, wantCode: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			updater := &accountUpdater{}
+			controller := controllers.NewAccountController(updater, nil, nil)
+
+			router := gin.New()
+			router.PUT("/api/account", func(c *gin.Context) {
+				c.Set("userID", 7)
+				if tt.viaKey {
+					c.Set("apiKeyID", 3)
+				}
+			}, controller.UpdateUserAccount)
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPut, "/api/account", strings.NewReader(tt.body))
+			request.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(recorder, request)
+
+			assert.Equal(t, tt.wantCode, recorder.Code, recorder.Body.String())
+			if tt.wantCode == http.StatusForbidden {
+				assert.Empty(t, updater.updates)
+			} else if assert.Len(t, updater.updates, 1) {
+				assert.Equal(t, 7, updater.updates[0].ID)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package Middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"Financial/Core/signing"
+	"Financial/intefaces/controllers"
+	"Financial/intefaces/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// walletRouter registers the wallet, ledger and import routes under /api the way the server does
+func walletRouter(t *testing.T) (*gin.Engine, *middleware.AuthMiddleware) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	key, err := signing.GenerateKey()
+	require.NoError(t, err)
+	ring, err := signing.NewKeyRing(key)
+	require.NoError(t, err)
+	auth := middleware.NewAuthMiddleware(ring)
+
+	router := gin.New()
+	api := router.Group("/api")
+	api.Use(auth.AuthMiddleware())
+	for _, controller := range []controllers.Controller{
+		controllers.NewWalletController(nil, auth),
+		controllers.NewTransactionController(nil, auth),
+		controllers.NewImportController(nil, auth),
+	} {
+		controller.RegisterRoutes(api)
+	}
+	return router, auth
+}
+
+// samplePath fills the parameters of a route with values of the type they hold
+func samplePath(route string) string {
+	return strings.NewReplacer(":email", "ana@example.com", ":walletId", "1", ":transactionId", "3").Replace(route)
+}
+
+func TestAuthConfig_IsPublicRoute(t *testing.T) {
+	config := middleware.NewAuthConfig()
+
+	assert.True(t, config.IsPublicRoute("POST", "/api/auth/login"))
+	assert.True(t, config.IsPublicRoute("GET", "/swagger/index.html"), "routes ending in / match by prefix")
+	assert.True(t, config.IsPublicRoute("OPTIONS", "/api/wallet"), "CORS preflight is always allowed")
+	assert.False(t, config.IsPublicRoute("GET", "/api/auth/login"), "the method must match")
+
+	// A public route without a trailing slash covers only itself
+	config.AddPublicRoute("GET", "/api/wallet")
+	assert.True(t, config.IsPublicRoute("GET", "/api/wallet"))
+	assert.False(t, config.IsPublicRoute("GET", "/api/wallet/:email"))
+	assert.False(t, config.IsPublicRoute("GET", "/api/wallets/:walletId/transactions"))
+}
+
+func TestAuthMiddleware_WalletRoutesAreNotPublic(t *testing.T) {
+	router, auth := walletRouter(t)
+
+	checked := 0
+	for _, route := range router.Routes() {
+		if !strings.HasPrefix(route.Path, "/api/wallet") {
+			continue
+		}
+		checked++
+
+		t.Run(route.Method+" "+route.Path, func(t *testing.T) {
+			assert.False(t, auth.Config.IsPublicRoute(route.Method, route.Path))
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(route.Method, samplePath(route.Path), nil)
+			router.ServeHTTP(recorder, request)
+			assert.Equal(t, http.StatusUnauthorized, recorder.Code, "a request without a token never reaches the handler")
+		})
+	}
+
+	// /api/wallet, /api/wallet/:email, /api/wallet/:walletId (PUT, DELETE), POST /api/wallet,
+	// the three ledger routes and the two import routes
+	assert.Equal(t, 10, checked)
+}
+
+func TestAuthMiddleware_WalletRouteParameters(t *testing.T) {
+	router, _ := walletRouter(t)
+
+	paths := map[string]bool{}
+	for _, route := range router.Routes() {
+		paths[route.Method+" "+route.Path] = true
+	}
+
+	// Each parameter is named after what it holds
+	for _, expected := range []string{
+		"GET /api/wallet/:email",
+		"PUT /api/wallet/:walletId",
+		"DELETE /api/wallet/:walletId",
+		"GET /api/wallets/:walletId/transactions",
+		"POST /api/wallets/:walletId/transactions",
+		"DELETE /api/wallets/:walletId/transactions/:transactionId",
+		"POST /api/wallets/:walletId/import/preview",
+		"POST /api/wallets/:walletId/import",
+	} {
+		assert.True(t, paths[expected], "missing route %s", expected)
+	}
+	for path := range paths {
+		assert.NotContains(t, path, ":id", "no route carries an ambiguous :id")
+	}
+}
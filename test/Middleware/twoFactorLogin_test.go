@@ -0,0 +1,118 @@
+package Middleware_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	response "Financial/Core/Models/dtos/Response"
+	usecases "Financial/Core/UseCases"
+	contracts "Financial/Core/ports"
+	"Financial/Core/signing"
+	"Financial/Core/types"
+	"Financial/intefaces/controllers"
+	"Financial/intefaces/middleware"
+	"Financial/persistence/infrastructure"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// validCode is the only code codeChecker accepts
+const validCode = "123456"
+
+// codeChecker accepts validCode for every account
+type codeChecker struct {
+	contracts.TwoFactorUseCase
+}
+
+func (codeChecker) Verify(userID int, code string) *response.ErrorResponse {
+	if code != validCode {
+		return &response.ErrorResponse{Error: types.ErrInvalidTwoFactorCode.Error()}
+	}
+	return nil
+}
+
+// sessionStarter opens a session for every login
+type sessionStarter struct {
+	contracts.SessionUseCase
+}
+
+func (sessionStarter) StartSession(userID int, device string, ip string) (*response.SessionGrant, *response.ErrorResponse) {
+	return &response.SessionGrant{SessionID: 1, Subject: "7", TokenID: "session-1", RefreshToken: "refresh"}, nil
+}
+
+// manualClock is a Clock that only moves when told to
+type manualClock struct {
+	now time.Time
+}
+
+func (c *manualClock) Now() time.Time {
+	return c.now
+}
+
+// twoFactorRouter serves the auth routes with a throttle on an in-memory store, and returns a
+// challenge token for the user 7
+func twoFactorRouter(t *testing.T, clock contracts.Clock) (*gin.Engine, string) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	key, err := signing.GenerateKey()
+	require.NoError(t, err)
+	ring, err := signing.NewKeyRing(key)
+	require.NoError(t, err)
+	auth := middleware.NewAuthMiddleware(ring)
+
+	store := infrastructure.NewMemoryStore()
+	throttle := usecases.NewLoginThrottleUseCase(infrastructure.NewMemoryLoginAttemptStore(), infrastructure.NewMemoryUserRepository(store),
+		infrastructure.NewMemoryAuditRepository(store), clock, usecases.DefaultLoginThrottleConfig)
+
+	router := gin.New()
+	controllers.NewAuthController(nil, sessionStarter{}, nil, codeChecker{}, nil, throttle, auth).RegisterRoutes(router.Group("/api"))
+
+	challenge, err := auth.GenerateChallengeToken("7")
+	require.NoError(t, err)
+	return router, challenge
+}
+
+// verify posts a challenge token and a code to /api/auth/2fa/verify
+func verify(router *gin.Engine, challenge string, code string) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(map[string]string{"challenge_token": challenge, "code": code})
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPost, "/api/auth/2fa/verify", strings.NewReader(string(body)))
+	request.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestVerifyTwoFactor_ChallengeCompletesOneLogin(t *testing.T) {
+	router, challenge := twoFactorRouter(t, usecases.SystemClock{})
+
+	first := verify(router, challenge, validCode)
+	require.Equal(t, http.StatusOK, first.Code, first.Body.String())
+
+	replay := verify(router, challenge, validCode)
+	assert.Equal(t, http.StatusUnauthorized, replay.Code, "the token of a completed login is refused")
+	assert.Contains(t, replay.Body.String(), "Invalid or expired challenge token")
+}
+
+func TestVerifyTwoFactor_WrongCodesAreThrottled(t *testing.T) {
+	clock := &manualClock{now: time.Now()}
+	router, challenge := twoFactorRouter(t, clock)
+
+	for i := 0; i < usecases.DefaultLoginThrottleConfig.FreeAttempts+1; i++ {
+		recorder := verify(router, challenge, "000000")
+		require.Equal(t, http.StatusUnauthorized, recorder.Code)
+	}
+
+	throttled := verify(router, challenge, validCode)
+	assert.Equal(t, http.StatusTooManyRequests, throttled.Code, "even the right code waits")
+	assert.Equal(t, "1", throttled.Header().Get("Retry-After"))
+
+	clock.now = clock.now.Add(time.Second)
+	assert.Equal(t, http.StatusOK, verify(router, challenge, validCode).Code)
+}
@@ -0,0 +1,189 @@
+package Oidc_test
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"Financial/Core/oidc"
+	mocks "Financial/Test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const redirectURL = "http://localhost:8080/api/auth/oidc/callback"
+
+func discover(t *testing.T, server *mocks.MockOIDCServer) *oidc.Provider {
+	provider, err := oidc.Discover(oidc.Config{
+		Issuer:       server.Issuer(),
+		ClientID:     server.ClientID,
+		ClientSecret: server.ClientSecret,
+		RedirectURL:  redirectURL,
+	}, nil)
+	require.NoError(t, err)
+	return provider
+}
+
+// login runs the authorization step with a fresh verifier and returns the code and the verifier
+func login(t *testing.T, server *mocks.MockOIDCServer, provider *oidc.Provider, nonce string) (string, string) {
+	verifier, err := oidc.NewCodeVerifier()
+	require.NoError(t, err)
+	_, code, err := server.Authorize(provider.AuthCodeURL("state-1", nonce, oidc.CodeChallenge(verifier)))
+	require.NoError(t, err)
+	return code, verifier
+}
+
+func TestDiscover_RejectsAnotherIssuer(t *testing.T) {
+	server := mocks.NewMockOIDCServer("client", "secret")
+	defer server.Close()
+
+	_, err := oidc.Discover(oidc.Config{
+		Issuer:      server.Issuer() + "/other",
+		ClientID:    "client",
+		RedirectURL: redirectURL,
+	}, nil)
+
+	assert.Error(t, err)
+}
+
+func TestCodeChallenge_RFC7636Vector(t *testing.T) {
+	// Appendix B of RFC 7636
+	assert.Equal(t, "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM", oidc.CodeChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"))
+}
+
+func TestProvider_AuthCodeURL(t *testing.T) {
+	server := mocks.NewMockOIDCServer("client", "secret")
+	defer server.Close()
+	provider := discover(t, server)
+
+	address, err := url.Parse(provider.AuthCodeURL("the-state", "the-nonce", "the-challenge"))
+
+	require.NoError(t, err)
+	query := address.Query()
+	assert.Equal(t, server.Issuer()+"/authorize", address.Scheme+"://"+address.Host+address.Path)
+	assert.Equal(t, "code", query.Get("response_type"))
+	assert.Equal(t, "client", query.Get("client_id"))
+	assert.Equal(t, redirectURL, query.Get("redirect_uri"))
+	assert.Equal(t, "openid email profile", query.Get("scope"))
+	assert.Equal(t, "the-state", query.Get("state"))
+	assert.Equal(t, "the-nonce", query.Get("nonce"))
+	assert.Equal(t, "the-challenge", query.Get("code_challenge"))
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+}
+
+func TestProvider_Identify(t *testing.T) {
+	t.Run("valid login", func(t *testing.T) {
+		server := mocks.NewMockOIDCServer("client", "secret")
+		defer server.Close()
+		server.User = mocks.MockOIDCUser{Subject: "abc", Email: "ana@example.com", EmailVerified: true, GivenName: "Ana"}
+		provider := discover(t, server)
+		code, verifier := login(t, server, provider, "nonce-1")
+
+		identity, err := provider.Identify(code, verifier, "nonce-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, server.Issuer(), identity.Issuer)
+		assert.Equal(t, "abc", identity.Subject)
+		assert.Equal(t, "ana@example.com", identity.Email)
+		assert.True(t, identity.EmailVerified)
+		assert.Equal(t, "Ana", identity.GivenName)
+	})
+
+	t.Run("wrong PKCE verifier", func(t *testing.T) {
+		server := mocks.NewMockOIDCServer("client", "secret")
+		defer server.Close()
+		provider := discover(t, server)
+		code, _ := login(t, server, provider, "nonce-1")
+		other, _ := oidc.NewCodeVerifier()
+
+		_, err := provider.Identify(code, other, "nonce-1")
+
+		assert.Error(t, err)
+	})
+
+	t.Run("code used twice", func(t *testing.T) {
+		server := mocks.NewMockOIDCServer("client", "secret")
+		defer server.Close()
+		provider := discover(t, server)
+		code, verifier := login(t, server, provider, "nonce-1")
+		_, err := provider.Identify(code, verifier, "nonce-1")
+		require.NoError(t, err)
+
+		_, err = provider.Identify(code, verifier, "nonce-1")
+
+		assert.Error(t, err)
+	})
+
+	t.Run("wrong client secret", func(t *testing.T) {
+		server := mocks.NewMockOIDCServer("client", "secret")
+		defer server.Close()
+		provider, err := oidc.Discover(oidc.Config{Issuer: server.Issuer(), ClientID: "client", ClientSecret: "guess", RedirectURL: redirectURL}, nil)
+		require.NoError(t, err)
+		code, verifier := login(t, server, provider, "nonce-1")
+
+		_, err = provider.Identify(code, verifier, "nonce-1")
+
+		assert.Error(t, err)
+	})
+
+	t.Run("rotated signing key is fetched", func(t *testing.T) {
+		server := mocks.NewMockOIDCServer("client", "secret")
+		defer server.Close()
+		provider := discover(t, server)
+		code, verifier := login(t, server, provider, "nonce-1")
+		_, err := provider.Identify(code, verifier, "nonce-1")
+		require.NoError(t, err)
+
+		server.RotateKey()
+		code, verifier = login(t, server, provider, "nonce-2")
+		_, err = provider.Identify(code, verifier, "nonce-2")
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestProvider_Identify_RejectsBadIDTokens(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims func(claims map[string]any)
+	}{
+		{name: "wrong nonce", claims: func(c map[string]any) { c["nonce"] = "other" }},
+		{name: "another audience", claims: func(c map[string]any) { c["aud"] = "someone-else" }},
+		{name: "another issuer", claims: func(c map[string]any) { c["iss"] = "https://evil.example.com" }},
+		{name: "expired", claims: func(c map[string]any) { c["exp"] = time.Now().Add(-time.Hour).Unix() }},
+		{name: "no subject", claims: func(c map[string]any) { c["sub"] = "" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := mocks.NewMockOIDCServer("client", "secret")
+			defer server.Close()
+			server.Claims = tt.claims
+			provider := discover(t, server)
+			code, verifier := login(t, server, provider, "nonce-1")
+
+			_, err := provider.Identify(code, verifier, "nonce-1")
+
+			assert.True(t, errors.Is(err, oidc.ErrInvalidIDToken), "got %v", err)
+		})
+	}
+}
+
+func TestProvider_Identify_AudienceList(t *testing.T) {
+	server := mocks.NewMockOIDCServer("client", "secret")
+	defer server.Close()
+	server.Claims = func(c map[string]any) {
+		c["aud"] = []string{"client", "api"}
+		c["azp"] = "client"
+		c["email_verified"] = "true"
+	}
+	provider := discover(t, server)
+	code, verifier := login(t, server, provider, "nonce-1")
+
+	identity, err := provider.Identify(code, verifier, "nonce-1")
+
+	require.NoError(t, err)
+	assert.True(t, identity.EmailVerified)
+}
@@ -0,0 +1,159 @@
+package Signing_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"Financial/Core/signing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeKey saves a PKCS#8 PEM key in dir and returns it parsed
+func writeKey(t *testing.T, dir, name string, private any) *signing.Key {
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	require.NoError(t, err)
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), data, 0o600))
+
+	key, err := signing.ParsePrivateKey(data)
+	require.NoError(t, err)
+	return key
+}
+
+func TestNewKey_Algorithms(t *testing.T) {
+	_, edPrivate, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	key, err := signing.NewKey(edPrivate)
+	require.NoError(t, err)
+	assert.Equal(t, signing.EdDSA, key.Algorithm)
+
+	rsaPrivate, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	key, err = signing.NewKey(rsaPrivate)
+	require.NoError(t, err)
+	assert.Equal(t, signing.RS256, key.Algorithm)
+
+	weak, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+	_, err = signing.NewKey(weak)
+	assert.Error(t, err, "RSA keys under 2048 bits are rejected")
+}
+
+func TestKeyID_RFC7638Thumbprint(t *testing.T) {
+	// The Ed25519 key of RFC 8037, appendix A, and its thumbprint from A.3
+	seed := []byte{
+		0x9d, 0x61, 0xb1, 0x9d, 0xef, 0xfd, 0x5a, 0x60, 0xba, 0x84, 0x4a, 0xf4, 0x92, 0xec, 0x2c, 0xc4,
+		0x44, 0x49, 0xc5, 0x69, 0x7b, 0x32, 0x69, 0x19, 0x70, 0x3b, 0xac, 0x03, 0x1c, 0xae, 0x7f, 0x60,
+	}
+	key, err := signing.NewKey(ed25519.NewKeyFromSeed(seed))
+	require.NoError(t, err)
+
+	assert.Equal(t, "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k", key.ID)
+}
+
+func TestParsePrivateKey_RejectsGarbage(t *testing.T) {
+	_, err := signing.ParsePrivateKey([]byte("not a key"))
+	assert.Error(t, err)
+
+	_, err = signing.ParsePrivateKey(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte{1}}))
+	assert.Error(t, err)
+}
+
+func TestKeyRing_RotateKeepsOldKeyUntilRetention(t *testing.T) {
+	first, err := signing.GenerateKey()
+	require.NoError(t, err)
+	second, err := signing.GenerateKey()
+	require.NoError(t, err)
+
+	ring, err := signing.NewKeyRing(first)
+	require.NoError(t, err)
+	ring.SetRetention(50 * time.Millisecond)
+
+	ring.Rotate(second)
+	assert.Equal(t, second.ID, ring.Current().ID)
+
+	_, ok := ring.Lookup(first.ID)
+	assert.True(t, ok, "the retired key still verifies during the retention")
+	assert.Len(t, ring.JWKS().Keys, 2)
+
+	time.Sleep(60 * time.Millisecond)
+	_, ok = ring.Lookup(first.ID)
+	assert.False(t, ok, "the retired key stops verifying after the retention")
+	_, ok = ring.Lookup(second.ID)
+	assert.True(t, ok)
+
+	set := ring.JWKS()
+	require.Len(t, set.Keys, 1)
+	assert.Equal(t, second.ID, set.Keys[0].Kid)
+}
+
+func TestKeyRing_JWKSHasOnlyPublicMembers(t *testing.T) {
+	dir := t.TempDir()
+	rsaPrivate, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	_, edPrivate, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	rsaKey := writeKey(t, dir, "2025-01-01.pem", rsaPrivate)
+	edKey := writeKey(t, dir, "2025-07-01.pem", edPrivate)
+
+	ring, err := signing.LoadDir(dir)
+	require.NoError(t, err)
+	assert.Equal(t, edKey.ID, ring.Current().ID, "the last file by name signs")
+
+	byID := map[string]signing.JSONWebKey{}
+	for _, jwk := range ring.JWKS().Keys {
+		byID[jwk.Kid] = jwk
+	}
+	require.Len(t, byID, 2)
+
+	assert.Equal(t, "RSA", byID[rsaKey.ID].Kty)
+	assert.Equal(t, signing.RS256, byID[rsaKey.ID].Alg)
+	assert.Equal(t, "AQAB", byID[rsaKey.ID].E)
+	assert.NotEmpty(t, byID[rsaKey.ID].N)
+
+	assert.Equal(t, "OKP", byID[edKey.ID].Kty)
+	assert.Equal(t, "Ed25519", byID[edKey.ID].Crv)
+	assert.Equal(t, signing.EdDSA, byID[edKey.ID].Alg)
+	assert.NotEmpty(t, byID[edKey.ID].X)
+}
+
+func TestKeyRing_ReloadDir(t *testing.T) {
+	dir := t.TempDir()
+	_, firstPrivate, _ := ed25519.GenerateKey(rand.Reader)
+	first := writeKey(t, dir, "2025-01-01.pem", firstPrivate)
+
+	ring, err := signing.LoadDir(dir)
+	require.NoError(t, err)
+	ring.SetRetention(time.Hour)
+
+	// A newer file takes over signing
+	_, secondPrivate, _ := ed25519.GenerateKey(rand.Reader)
+	second := writeKey(t, dir, "2025-07-01.pem", secondPrivate)
+	require.NoError(t, ring.ReloadDir(dir))
+	assert.Equal(t, second.ID, ring.Current().ID)
+
+	// Removing the old file retires its key but it keeps verifying for the retention
+	require.NoError(t, os.Remove(filepath.Join(dir, "2025-01-01.pem")))
+	require.NoError(t, ring.ReloadDir(dir))
+	_, ok := ring.Lookup(first.ID)
+	assert.True(t, ok)
+
+	// A broken directory leaves the loaded keys in place
+	require.NoError(t, os.Remove(filepath.Join(dir, "2025-07-01.pem")))
+	assert.Error(t, ring.ReloadDir(dir))
+	assert.Equal(t, second.ID, ring.Current().ID)
+}
+
+func TestLoadDir_Empty(t *testing.T) {
+	_, err := signing.LoadDir(t.TempDir())
+	assert.Error(t, err)
+}
@@ -0,0 +1,85 @@
+package Totp_test
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"Financial/Core/totp"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rfcSecret is the SHA-1 key of the RFC 6238 test vectors ("12345678901234567890"), base32 encoded
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCode_RFC6238Vectors(t *testing.T) {
+	// The RFC lists 8-digit codes; the 6-digit code is their last six digits
+	tests := []struct {
+		unix     int64
+		expected string
+	}{
+		{unix: 59, expected: "287082"},
+		{unix: 1111111109, expected: "081804"},
+		{unix: 1111111111, expected: "050471"},
+		{unix: 1234567890, expected: "005924"},
+		{unix: 2000000000, expected: "279037"},
+		{unix: 20000000000, expected: "353130"},
+	}
+
+	for _, tt := range tests {
+		code, err := totp.Code(rfcSecret, totp.Step(time.Unix(tt.unix, 0)))
+		require.NoError(t, err)
+		assert.Equal(t, tt.expected, code, "at %d", tt.unix)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	current, _ := totp.Code(rfcSecret, totp.Step(now))
+	previous, _ := totp.Code(rfcSecret, totp.Step(now)-1)
+	old, _ := totp.Code(rfcSecret, totp.Step(now)-2)
+
+	step, ok := totp.Validate(rfcSecret, current, now)
+	assert.True(t, ok)
+	assert.Equal(t, totp.Step(now), step)
+
+	step, ok = totp.Validate(rfcSecret, previous, now)
+	assert.True(t, ok, "one step of clock drift is allowed")
+	assert.Equal(t, totp.Step(now)-1, step)
+
+	_, ok = totp.Validate(rfcSecret, old, now)
+	assert.False(t, ok)
+
+	_, ok = totp.Validate(rfcSecret, "12345", now)
+	assert.False(t, ok)
+
+	_, ok = totp.Validate("not base32!", current, now)
+	assert.False(t, ok)
+}
+
+func TestGenerateSecret(t *testing.T) {
+	first, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	second, _ := totp.GenerateSecret()
+
+	assert.Len(t, first, 32, "160 bits in base32")
+	assert.NotEqual(t, first, second)
+	_, err = totp.Code(first, 1)
+	assert.NoError(t, err)
+}
+
+func TestURI(t *testing.T) {
+	uri := totp.URI("MyFinance", "ana@example.com", rfcSecret)
+
+	parsed, err := url.Parse(uri)
+	require.NoError(t, err)
+	assert.Equal(t, "otpauth", parsed.Scheme)
+	assert.Equal(t, "totp", parsed.Host)
+	assert.Equal(t, "/MyFinance:ana@example.com", parsed.Path)
+	assert.Equal(t, rfcSecret, parsed.Query().Get("secret"))
+	assert.Equal(t, "MyFinance", parsed.Query().Get("issuer"))
+	assert.Equal(t, "6", parsed.Query().Get("digits"))
+	assert.Equal(t, "30", parsed.Query().Get("period"))
+}
@@ -0,0 +1,29 @@
+package Types_test
+
+import (
+	"testing"
+
+	"Financial/Core/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKeyScope_Grants(t *testing.T) {
+	tests := []struct {
+		name     string
+		scope    types.APIKeyScope
+		required types.APIKeyScope
+		expected bool
+	}{
+		{name: "same scope", scope: types.ScopeWalletsRead, required: types.ScopeWalletsRead, expected: true},
+		{name: "write includes read", scope: types.ScopeWalletsWrite, required: types.ScopeWalletsRead, expected: true},
+		{name: "read excludes write", scope: types.ScopeWalletsRead, required: types.ScopeWalletsWrite, expected: false},
+		{name: "other resource", scope: types.ScopeAccountWrite, required: types.ScopeWalletsRead, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.scope.Grants(tt.required))
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package Types_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"Financial/Core/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		expected  int64
+		expectErr bool
+	}{
+		{name: "whole amount", value: "1250", expected: 125000},
+		{name: "one decimal", value: "-3.5", expected: -350},
+		{name: "two decimals", value: "0.99", expected: 99},
+		{name: "no whole part", value: ".10", expected: 10},
+		{name: "trailing zeros", value: "10.500", expected: 1050},
+		{name: "too many decimals", value: "0.001", expectErr: true},
+		{name: "not a number", value: "12a", expectErr: true},
+		{name: "empty", value: "", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := types.ParseMoney(tt.value, "usd")
+
+			if tt.expectErr {
+				assert.ErrorIs(t, err, types.ErrInvalidMoney)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, m.Minor)
+			assert.Equal(t, "USD", m.Currency)
+		})
+	}
+}
+
+func TestMoney_Arithmetic(t *testing.T) {
+	t.Run("sums without float rounding", func(t *testing.T) {
+		total := types.Money{}
+		for i := 0; i < 10; i++ {
+			var err error
+			total, err = total.Add(types.MustParseMoney("0.10", "USD"))
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, types.MustParseMoney("1.00", "USD"), total)
+	})
+
+	t.Run("different currencies cannot be mixed", func(t *testing.T) {
+		_, err := types.MustParseMoney("1", "USD").Subtract(types.MustParseMoney("1", "DOP"))
+		assert.ErrorIs(t, err, types.ErrCurrencyMismatch)
+	})
+
+	t.Run("unknown currency adopts the other one", func(t *testing.T) {
+		result, err := types.MustParseMoney("5", "").Add(types.MustParseMoney("2.5", "DOP"))
+		assert.NoError(t, err)
+		assert.Equal(t, "7.50 DOP", result.String())
+	})
+}
+
+func TestMoney_JSON(t *testing.T) {
+	var payload struct {
+		Balance types.Money `json:"balance"`
+	}
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"balance": 1000.10}`), &payload))
+	assert.Equal(t, int64(100010), payload.Balance.Minor)
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"balance": "-0.05"}`), &payload))
+	assert.Equal(t, int64(-5), payload.Balance.Minor)
+
+	data, err := json.Marshal(payload)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"balance": -0.05}`, string(data))
+
+	assert.Error(t, json.Unmarshal([]byte(`{"balance": 1e3}`), &payload))
+}
+
+func TestMoney_Convert(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   string
+		rate     string
+		inverse  bool
+		expected string
+	}{
+		{name: "exact conversion", amount: "100", rate: "58.75", expected: "5875.00"},
+		{name: "rounds half away from zero", amount: "0.01", rate: "0.5", expected: "0.01"},
+		{name: "negative amounts round symmetrically", amount: "-25.25", rate: "58.75", expected: "-1483.44"},
+		{name: "inverse rate", amount: "5875", rate: "58.75", inverse: true, expected: "100.00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate := types.MustParseRate(tt.rate)
+			if tt.inverse {
+				rate = rate.Inverse()
+			}
+
+			converted := types.MustParseMoney(tt.amount, "USD").Convert(rate, "DOP")
+
+			assert.Equal(t, tt.expected, converted.Decimal())
+			assert.Equal(t, "DOP", converted.Currency)
+		})
+	}
+
+	t.Run("rates must be positive decimals", func(t *testing.T) {
+		for _, value := range []string{"0", "-1", "1/3", "1e3", "abc"} {
+			_, err := types.ParseRate(value)
+			assert.ErrorIs(t, err, types.ErrInvalidRate, value)
+		}
+	})
+}
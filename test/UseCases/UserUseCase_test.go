@@ -1,15 +1,20 @@
 package UseCases_test
 
 import (
+	"errors"
+	"strings"
 	"testing"
 
 	"Financial/Core/Models/db"
+	request "Financial/Core/Models/dtos/Request"
 	response "Financial/Core/Models/dtos/Response"
 	usecases "Financial/Core/UseCases"
+	contracts "Financial/Core/ports"
 	"Financial/Core/types"
 	mocks "Financial/Test"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAccountUseCase_CreateAccount(t *testing.T) {
@@ -33,6 +38,7 @@ func TestAccountUseCase_CreateAccount(t *testing.T) {
 				mock.SetFindByFieldNotExists(true)
 				mock.SetResponse("FindByField", nil, nil)
 				mock.SetResponse("FindByField", nil, nil)
+				mock.SetResponse("Create", &db.User{ID: 1, Nickname: "alice_serat", Email: "alice@example.com"}, nil)
 			},
 			verify: func(t *testing.T, user *response.CreateAccountResponse, err error) {
 				assert.NoError(t, err)
@@ -188,7 +194,9 @@ func TestAccountUseCase_CreateAccount(t *testing.T) {
 				tt.setupMock(repo)
 			}
 
-			useCase := usecases.NewAccountUseCase(repo)
+			categories := mocks.NewMockRepository[db.Category, int]()
+			categories.SetResponse("Create", &db.Category{ID: 1}, nil)
+			useCase := usecases.NewAccountUseCase(repo, accountUnitOfWork(repo, categories), fakeHasher{}, true)
 			newUser, err := useCase.CreateAccount(tt.nickname, tt.email, tt.password)
 
 			if tt.expectErr {
@@ -212,6 +220,374 @@ func TestAccountUseCase_CreateAccount(t *testing.T) {
 				}
 				tt.verify(t, newUser.Data, nil)
 			}
+			assert.NotEmpty(t, categories.Calls("Create"), "new accounts get the default categories")
+			created := repo.Calls("Create")[0].([]interface{})[0].(*db.User)
+			assert.Equal(t, "hashed:"+tt.password, created.Password, "passwords are stored hashed")
+		})
+	}
+}
+
+func TestAccountUseCase_CreateAccount_IsAtomic(t *testing.T) {
+	store := newMemoryStore()
+	failingCategories := unitOfWorkFunc(func(fn func(repos contracts.UnitOfWorkRepositories) error) error {
+		return store.unitOfWork.Do(func(repos contracts.UnitOfWorkRepositories) error {
+			repos.Categories = failingCreates[db.Category]{repos.Categories}
+			return fn(repos)
+		})
+	})
+	// The uniqueness checks run on the mock, the writes on the store
+	repo := mocks.NewMockRepository[db.User, int]()
+	repo.SetFindByFieldNotExists(true)
+	useCase := usecases.NewAccountUseCase(repo, failingCategories, fakeHasher{}, true)
+
+	_, err := useCase.CreateAccount("alice_serat", "alice@example.com", "securepassword123!")
+	require.NotNil(t, err)
+	assert.Contains(t, (*err)[0].Error, "error creating default category")
+
+	users, errAll := store.users.GetAll()
+	require.NoError(t, errAll)
+	assert.Empty(t, users, "the account is not kept without its categories")
+}
+
+func TestAccountUseCase_DestroyAccount_Cascades(t *testing.T) {
+	store := newMemoryStore()
+	user := store.addUser(t, "alice")
+	wallet := store.addWallet(t, user.ID, "Cash", "10")
+
+	uow := mocks.NewMockUnitOfWork(contracts.UnitOfWorkRepositories{Users: store.users})
+	useCase := usecases.NewAccountUseCase(store.users, uow, fakeHasher{}, true)
+
+	err := useCase.DestroyAccount(user.ID, user.Email)
+	require.Nil(t, err)
+	assert.Equal(t, 0, uow.Runs, "one delete needs no unit of work")
+	_, errGet := store.wallets.GetByID(wallet.ID)
+	assert.ErrorIs(t, errGet, types.ErrNotFound)
+}
+
+// accountUnitOfWork runs the writes of the account use case on users and categories
+func accountUnitOfWork(users contracts.Repository[db.User, int], categories contracts.Repository[db.Category, int]) *mocks.MockUnitOfWork {
+	return mocks.NewMockUnitOfWork(contracts.UnitOfWorkRepositories{
+		Users:        users,
+		Categories:   categories,
+		Transactions: mocks.NewMockRepository[db.Transaction, int](),
+	})
+}
+
+// fakeHasher "hashes" with a prefix; values with an "old:" prefix are outdated hashes
+type fakeHasher struct{}
+
+func (fakeHasher) Hash(password string) (string, error) {
+	return "hashed:" + password, nil
+}
+
+func (fakeHasher) Verify(password string, encoded string) (bool, error) {
+	for _, prefix := range []string{"hashed:", "old:"} {
+		if strings.HasPrefix(encoded, prefix) {
+			return encoded == prefix+password, nil
+		}
+	}
+	return false, types.ErrUnknownHash
+}
+
+func (fakeHasher) NeedsRehash(encoded string) bool {
+	return !strings.HasPrefix(encoded, "hashed:")
+}
+
+func TestAccountUseCase_Login(t *testing.T) {
+	tests := []struct {
+		name           string
+		stored         string
+		password       string
+		expectErr      bool
+		expectedUpdate string
+	}{
+		{
+			name:     "current hash",
+			stored:   "hashed:s3cret!",
+			password: "s3cret!",
+		},
+		{
+			name:      "wrong password",
+			stored:    "hashed:s3cret!",
+			password:  "s3cret",
+			expectErr: true,
+		},
+		{
+			name:           "legacy plain text is upgraded",
+			stored:         "s3cret!",
+			password:       "s3cret!",
+			expectedUpdate: "hashed:s3cret!",
+		},
+		{
+			name:      "wrong password on legacy plain text",
+			stored:    "s3cret!",
+			password:  "other",
+			expectErr: true,
+		},
+		{
+			name:           "outdated hash is upgraded",
+			stored:         "old:s3cret!",
+			password:       "s3cret!",
+			expectedUpdate: "hashed:s3cret!",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewMockRepository[db.User, int]()
+			repo.SetResponse("FindByField", &db.User{ID: 1, Email: "alice@example.com", Password: tt.stored}, nil)
+			repo.SetResponse("Update", &db.User{ID: 1}, nil)
+			useCase := usecases.NewAccountUseCase(repo, accountUnitOfWork(repo, mocks.NewMockRepository[db.Category, int]()), fakeHasher{}, true)
+
+			userID, err := useCase.Login(request.AuthRequest{Email: "alice@example.com", Passwd: tt.password})
+
+			if tt.expectErr {
+				assert.ErrorIs(t, err, types.ErrInvalidCredentials)
+				assert.Empty(t, repo.Calls("Update"))
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, 1, *userID)
+			if tt.expectedUpdate == "" {
+				assert.Empty(t, repo.Calls("Update"))
+				return
+			}
+			updated := repo.Calls("Update")[0].([]interface{})[0].(*db.User)
+			assert.Equal(t, tt.expectedUpdate, updated.Password)
+		})
+	}
+}
+
+func TestAccountUseCase_Login_Identifier(t *testing.T) {
+	tests := []struct {
+		name          string
+		auth          request.AuthRequest
+		expectedField string
+		expectedValue string
+	}{
+		{
+			name:          "by email",
+			auth:          request.AuthRequest{Email: "alice@example.com", Passwd: "s3cret!"},
+			expectedField: "email",
+			expectedValue: "alice@example.com",
+		},
+		{
+			name:          "by nickname",
+			auth:          request.AuthRequest{Nickname: "alice", Passwd: "s3cret!"},
+			expectedField: "nick_name",
+			expectedValue: "alice",
+		},
+		{
+			name:          "email wins over nickname",
+			auth:          request.AuthRequest{Email: "alice@example.com", Nickname: "bob", Passwd: "s3cret!"},
+			expectedField: "email",
+			expectedValue: "alice@example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewMockRepository[db.User, int]()
+			repo.SetResponse("FindByField", &db.User{ID: 42, Nickname: "alice", Email: "alice@example.com", Password: "hashed:s3cret!"}, nil)
+			useCase := usecases.NewAccountUseCase(repo, accountUnitOfWork(repo, mocks.NewMockRepository[db.Category, int]()), fakeHasher{}, true)
+
+			userID, err := useCase.Login(tt.auth)
+
+			assert.NoError(t, err)
+			assert.Equal(t, 42, *userID)
+			call := repo.Calls("FindByField")[0].([]interface{})
+			assert.Equal(t, tt.expectedField, call[0])
+			assert.Equal(t, tt.expectedValue, call[1])
+		})
+	}
+}
+
+func TestAccountUseCase_Login_Errors(t *testing.T) {
+	t.Run("unknown nickname fails like a wrong password", func(t *testing.T) {
+		repo := mocks.NewMockRepository[db.User, int]()
+		repo.SetResponse("FindByField", nil, types.ErrNotFound)
+		useCase := usecases.NewAccountUseCase(repo, accountUnitOfWork(repo, mocks.NewMockRepository[db.Category, int]()), fakeHasher{}, true)
+
+		_, err := useCase.Login(request.AuthRequest{Nickname: "ghost", Passwd: "s3cret!"})
+
+		assert.ErrorIs(t, err, types.ErrInvalidCredentials)
+	})
+
+	t.Run("repository failure is not reported as bad credentials", func(t *testing.T) {
+		repo := mocks.NewMockRepository[db.User, int]()
+		repo.SetResponse("FindByField", nil, errors.New("connection refused"))
+		useCase := usecases.NewAccountUseCase(repo, accountUnitOfWork(repo, mocks.NewMockRepository[db.Category, int]()), fakeHasher{}, true)
+
+		_, err := useCase.Login(request.AuthRequest{Email: "alice@example.com", Passwd: "s3cret!"})
+
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, types.ErrInvalidCredentials)
+	})
+
+	t.Run("missing identifier", func(t *testing.T) {
+		repo := mocks.NewMockRepository[db.User, int]()
+		useCase := usecases.NewAccountUseCase(repo, accountUnitOfWork(repo, mocks.NewMockRepository[db.Category, int]()), fakeHasher{}, true)
+
+		_, err := useCase.Login(request.AuthRequest{Passwd: "s3cret!"})
+
+		assert.Error(t, err)
+		assert.Empty(t, repo.Calls("FindByField"))
+	})
+}
+
+func TestAccountUseCase_Login_Verification(t *testing.T) {
+	tests := []struct {
+		name            string
+		status          types.AccountStatus
+		requireVerified bool
+		expectedErr     error
+	}{
+		{name: "verified account", status: types.Active, requireVerified: true},
+		{name: "unverified account is blocked", status: types.Inactive, requireVerified: true, expectedErr: types.ErrAccountNotVerified},
+		{name: "pending account is blocked", status: types.Pending, requireVerified: true, expectedErr: types.ErrAccountNotVerified},
+		{name: "unverified account when verification is off", status: types.Inactive, requireVerified: false},
+		{name: "suspended account is blocked", status: types.Suspend, requireVerified: false, expectedErr: types.ErrAccountSuspended},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewMockRepository[db.User, int]()
+			repo.SetResponse("FindByField", &db.User{ID: 1, Email: "alice@example.com", Password: "hashed:s3cret!", Status: tt.status}, nil)
+			useCase := usecases.NewAccountUseCase(repo, accountUnitOfWork(repo, mocks.NewMockRepository[db.Category, int]()), fakeHasher{}, tt.requireVerified)
+
+			_, err := useCase.Login(request.AuthRequest{Email: "alice@example.com", Passwd: "s3cret!"})
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+
+	t.Run("wrong password on an unverified account does not reveal it", func(t *testing.T) {
+		repo := mocks.NewMockRepository[db.User, int]()
+		repo.SetResponse("FindByField", &db.User{ID: 1, Email: "alice@example.com", Password: "hashed:s3cret!", Status: types.Inactive}, nil)
+		useCase := usecases.NewAccountUseCase(repo, accountUnitOfWork(repo, mocks.NewMockRepository[db.Category, int]()), fakeHasher{}, true)
+
+		_, err := useCase.Login(request.AuthRequest{Email: "alice@example.com", Passwd: "wrong"})
+
+		assert.ErrorIs(t, err, types.ErrInvalidCredentials)
+	})
+}
+
+func TestAccountUseCase_DestroyAccount_Ownership(t *testing.T) {
+	newUseCase := func(repo *mocks.MockRepository[db.User, int]) contracts.UserUseCase {
+		return usecases.NewAccountUseCase(repo, accountUnitOfWork(repo, mocks.NewMockRepository[db.Category, int]()), fakeHasher{}, true)
+	}
+
+	t.Run("own account", func(t *testing.T) {
+		repo := mocks.NewMockRepository[db.User, int]()
+		repo.SetResponse("FindByField", &db.User{ID: 2, Email: "alice@example.com"}, nil)
+		repo.SetResponse("Delete", nil, nil)
+
+		err := newUseCase(repo).DestroyAccount(2, "alice@example.com")
+
+		assert.Nil(t, err)
+		assert.Len(t, repo.Calls("Delete"), 1)
+	})
+
+	t.Run("someone else's account", func(t *testing.T) {
+		repo := mocks.NewMockRepository[db.User, int]()
+		repo.SetResponse("FindByField", &db.User{ID: 2, Email: "alice@example.com"}, nil)
+
+		err := newUseCase(repo).DestroyAccount(1, "alice@example.com")
+
+		if assert.NotNil(t, err) {
+			assert.Equal(t, "User not found", (*err)[0].Error)
+		}
+		assert.Empty(t, repo.Calls("Delete"))
+	})
+}
+
+func TestAccountUseCase_UpdateAccount_CurrentPassword(t *testing.T) {
+	tests := []struct {
+		name         string
+		request      db.UpdateAccountRequest
+		wantErr      string
+		wantEmail    string
+		wantPassword string
+	}{
+		{
+			name:         "names need no password",
+			request:      db.UpdateAccountRequest{FirstName: "Alice"},
+			wantEmail:    "alice@example.com",
+			wantPassword: "hashed:secret",
+		},
+		{
+			name:         "new password without the current one",
+			request:      db.UpdateAccountRequest{Password: "newsecret"},
+			wantErr:      types.ErrCurrentPasswordRequired.Error(),
+			wantEmail:    "alice@example.com",
+			wantPassword: "hashed:secret",
+		},
+		{
+			name:         "new password with a wrong current one",
+			request:      db.UpdateAccountRequest{Password: "newsecret", CurrentPassword: "guess"},
+			wantErr:      types.ErrCurrentPasswordRequired.Error(),
+			wantEmail:    "alice@example.com",
+			wantPassword: "hashed:secret",
+		},
+		{
+			name:         "new password with the current one",
+			request:      db.UpdateAccountRequest{Password: "newsecret", CurrentPassword: "secret"},
+			wantEmail:    "alice@example.com",
+			wantPassword: "hashed:newsecret",
+		},
+		{
+			name:         "new email without the current password",
+			request:      db.UpdateAccountRequest{Email: "alice.new@example.com"},
+			wantErr:      types.ErrCurrentPasswordRequired.Error(),
+			wantEmail:    "alice@example.com",
+			wantPassword: "hashed:secret",
+		},
+		{
+			name:         "new email with the current password",
+			request:      db.UpdateAccountRequest{Email: "alice.new@example.com", CurrentPassword: "secret"},
+			wantEmail:    "alice.new@example.com",
+			wantPassword: "hashed:secret",
+		},
+		{
+			name:         "own email is not a change",
+			request:      db.UpdateAccountRequest{Email: "alice@example.com", Lastname: "Liddell"},
+			wantEmail:    "alice@example.com",
+			wantPassword: "hashed:secret",
+		},
+		{
+			name:         "email of another account",
+			request:      db.UpdateAccountRequest{Email: "bob@example.com", CurrentPassword: "secret"},
+			wantErr:      "Email already exists.",
+			wantEmail:    "alice@example.com",
+			wantPassword: "hashed:secret",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMemoryStore()
+			user := store.addUser(t, "alice")
+			store.addUser(t, "bob")
+			useCase := usecases.NewAccountUseCase(store.users, store.unitOfWork, fakeHasher{}, true)
+
+			tt.request.ID = user.ID
+			_, err := useCase.UpdateAccount(tt.request)
+
+			if tt.wantErr != "" {
+				if assert.NotNil(t, err) {
+					assert.Equal(t, tt.wantErr, (*err)[0].Error)
+				}
+			} else {
+				assert.Nil(t, err)
+			}
+			stored := store.user(t, user.ID)
+			assert.Equal(t, tt.wantEmail, stored.Email)
+			assert.Equal(t, tt.wantPassword, stored.Password)
 		})
 	}
 }
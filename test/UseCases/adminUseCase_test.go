@@ -0,0 +1,177 @@
+package UseCases_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"Financial/Core/Models/db"
+	usecases "Financial/Core/UseCases"
+	contracts "Financial/Core/ports"
+	"Financial/Core/types"
+	"Financial/persistence/infrastructure"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type adminFixture struct {
+	store    *memoryStore
+	admin    *db.User
+	support  *db.User
+	user     *db.User
+	sessions *sessionRecorder
+	attempts contracts.LoginAttemptStore
+	useCase  contracts.AdminUseCase
+}
+
+func newAdminFixture(t *testing.T) *adminFixture {
+	store := newMemoryStore()
+	f := &adminFixture{
+		store:    store,
+		admin:    store.addUser(t, "admin", func(user *db.User) { user.Role = types.RoleAdmin }),
+		support:  store.addUser(t, "support", func(user *db.User) { user.Role = types.RoleSupport }),
+		user:     store.addUser(t, "ana"),
+		sessions: &sessionRecorder{},
+		attempts: infrastructure.NewMemoryLoginAttemptStore(),
+	}
+	walletUseCase := usecases.NewWalletUseCase(store.wallets, store.unitOfWork, nil)
+	throttle := usecases.NewLoginThrottleUseCase(f.attempts, store.users, store.audit, &fakeClock{now: time.Now()}, usecases.DefaultLoginThrottleConfig)
+	f.useCase = usecases.NewAdminUseCase(store.users, walletUseCase, f.sessions, throttle)
+	return f
+}
+
+func TestAdminUseCase_Authorize(t *testing.T) {
+	fixture := newAdminFixture(t)
+	suspended := fixture.store.addUser(t, "former", func(user *db.User) {
+		user.Role = types.RoleAdmin
+		user.Status = types.Suspend
+	})
+
+	tests := []struct {
+		name       string
+		userID     int
+		permission types.Permission
+		allowed    bool
+	}{
+		{name: "admin manages users", userID: fixture.admin.ID, permission: types.PermUsersManage, allowed: true},
+		{name: "admin manages roles", userID: fixture.admin.ID, permission: types.PermRolesManage, allowed: true},
+		{name: "support reads wallets", userID: fixture.support.ID, permission: types.PermWalletsRead, allowed: true},
+		{name: "support can't manage users", userID: fixture.support.ID, permission: types.PermUsersManage},
+		{name: "user without role can't read users", userID: fixture.user.ID, permission: types.PermUsersRead},
+		{name: "suspended admin", userID: suspended.ID, permission: types.PermUsersRead},
+		{name: "unknown user", userID: 99, permission: types.PermUsersRead},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := fixture.useCase.Authorize(tt.userID, tt.permission)
+
+			if tt.allowed {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, types.ErrForbidden)
+		})
+	}
+}
+
+func TestAdminUseCase_ListUsers(t *testing.T) {
+	fixture := newAdminFixture(t)
+
+	users, err := fixture.useCase.ListUsers()
+
+	require.Nil(t, err)
+	require.Len(t, users, 3)
+	assert.Equal(t, []int{fixture.admin.ID, fixture.support.ID, fixture.user.ID}, []int{users[0].ID, users[1].ID, users[2].ID})
+	assert.Equal(t, types.RoleAdmin, users[0].Role)
+	assert.Equal(t, types.RoleUser, users[2].Role)
+}
+
+func TestAdminUseCase_SetAccountStatus(t *testing.T) {
+	t.Run("suspending logs the user out", func(t *testing.T) {
+		fixture := newAdminFixture(t)
+
+		user, err := fixture.useCase.SetAccountStatus(fixture.admin.ID, fixture.user.ID, types.Suspend)
+
+		require.Nil(t, err)
+		assert.Equal(t, types.Suspend, user.Status)
+		assert.Equal(t, types.Suspend, fixture.store.user(t, fixture.user.ID).Status)
+		assert.Equal(t, []int{fixture.user.ID}, fixture.sessions.loggedOut)
+	})
+
+	t.Run("reactivating keeps sessions alone", func(t *testing.T) {
+		fixture := newAdminFixture(t)
+		fixture.store.setStatus(t, fixture.user.ID, types.Suspend)
+
+		_, err := fixture.useCase.SetAccountStatus(fixture.admin.ID, fixture.user.ID, types.Active)
+
+		assert.Nil(t, err)
+		assert.Equal(t, types.Active, fixture.store.user(t, fixture.user.ID).Status)
+		assert.Empty(t, fixture.sessions.loggedOut)
+	})
+
+	t.Run("a status set by hand ends the lockout", func(t *testing.T) {
+		fixture := newAdminFixture(t)
+		fixture.store.setStatus(t, fixture.user.ID, types.Suspend)
+		key := fmt.Sprintf("user:%d", fixture.user.ID)
+		require.NoError(t, fixture.attempts.Lock(key, time.Now().Add(time.Hour), types.Active))
+
+		_, err := fixture.useCase.SetAccountStatus(fixture.admin.ID, fixture.user.ID, types.Suspend)
+
+		require.Nil(t, err)
+		_, errGet := fixture.attempts.Get(key)
+		assert.ErrorIs(t, errGet, types.ErrNotFound, "the expiring lockout must not reactivate the account")
+	})
+
+	t.Run("unknown status", func(t *testing.T) {
+		fixture := newAdminFixture(t)
+
+		_, err := fixture.useCase.SetAccountStatus(fixture.admin.ID, fixture.user.ID, "banned")
+
+		assert.Equal(t, `unknown account status "banned"`, err.Error)
+	})
+
+	t.Run("own account", func(t *testing.T) {
+		fixture := newAdminFixture(t)
+
+		_, err := fixture.useCase.SetAccountStatus(fixture.admin.ID, fixture.admin.ID, types.Suspend)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, types.Active, fixture.store.user(t, fixture.admin.ID).Status)
+	})
+
+	t.Run("unknown account", func(t *testing.T) {
+		fixture := newAdminFixture(t)
+
+		_, err := fixture.useCase.SetAccountStatus(fixture.admin.ID, 99, types.Suspend)
+
+		assert.Equal(t, "account not found", err.Error)
+	})
+}
+
+func TestAdminUseCase_SetRole(t *testing.T) {
+	fixture := newAdminFixture(t)
+
+	user, err := fixture.useCase.SetRole(fixture.admin.ID, fixture.user.ID, types.RoleSupport)
+	require.Nil(t, err)
+	assert.Equal(t, types.RoleSupport, user.Role)
+	assert.NoError(t, fixture.useCase.Authorize(fixture.user.ID, types.PermWalletsRead))
+
+	_, err = fixture.useCase.SetRole(fixture.admin.ID, fixture.user.ID, "root")
+	assert.Equal(t, `unknown role "root"`, err.Error)
+}
+
+func TestAdminUseCase_GetUserWallets(t *testing.T) {
+	fixture := newAdminFixture(t)
+	fixture.store.addWallet(t, fixture.user.ID, "Savings", "10")
+	fixture.store.addWallet(t, fixture.support.ID, "Someone else's", "20")
+
+	wallets, err := fixture.useCase.GetUserWallets(fixture.user.ID, "", time.Time{})
+
+	require.Nil(t, err)
+	assert.Equal(t, "ana@example.com", wallets.Email)
+	if assert.Len(t, wallets.Wallets, 1) {
+		assert.Equal(t, "Savings", wallets.Wallets[0].Name)
+	}
+}
@@ -0,0 +1,204 @@
+package UseCases_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"Financial/Core/Models/db"
+	request "Financial/Core/Models/dtos/Request"
+	response "Financial/Core/Models/dtos/Response"
+	usecases "Financial/Core/UseCases"
+	contracts "Financial/Core/ports"
+	"Financial/Core/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type apiKeyFixture struct {
+	store   *memoryStore
+	keys    *countingUpdates[db.APIKey]
+	owner   *db.User
+	other   *db.User
+	clock   *fakeClock
+	useCase contracts.APIKeyUseCase
+}
+
+func newAPIKeyFixture(t *testing.T) *apiKeyFixture {
+	store := newMemoryStore()
+	keys := &countingUpdates[db.APIKey]{Repository: store.apiKeys}
+	clock := &fakeClock{now: date(2025, time.July, 12)}
+	return &apiKeyFixture{
+		store:   store,
+		keys:    keys,
+		owner:   store.addUser(t, "ana"),
+		other:   store.addUser(t, "bob"),
+		clock:   clock,
+		useCase: usecases.NewAPIKeyUseCase(keys, store.users, clock),
+	}
+}
+
+func (f *apiKeyFixture) create(t *testing.T, req request.CreateAPIKeyRequest) *response.CreatedAPIKeyResponse {
+	created, err := f.useCase.CreateKey(f.owner.ID, req)
+	require.Nil(t, err)
+	return created
+}
+
+// stored reads a key back from the store
+func (f *apiKeyFixture) stored(t *testing.T, id int) *db.APIKey {
+	key, err := f.store.apiKeys.GetByID(id)
+	require.NoError(t, err)
+	return key
+}
+
+func TestAPIKeyUseCase_CreateKey(t *testing.T) {
+	t.Run("stores only the hash", func(t *testing.T) {
+		f := newAPIKeyFixture(t)
+
+		created, err := f.useCase.CreateKey(f.owner.ID, request.CreateAPIKeyRequest{Name: " backup script ", Scopes: []string{"wallets:read"}})
+
+		require.Nil(t, err)
+		assert.True(t, strings.HasPrefix(created.Key, "mfk_"))
+		assert.True(t, strings.HasPrefix(created.Key, created.Prefix))
+		assert.Equal(t, "backup script", created.Name)
+		assert.Equal(t, []string{"wallets:read"}, created.Scopes)
+		stored := f.stored(t, created.ID)
+		assert.NotContains(t, stored.KeyHash, created.Key)
+		assert.Len(t, stored.KeyHash, 64)
+		assert.Equal(t, f.owner.ID, stored.UserID)
+	})
+
+	t.Run("rejects an unknown scope", func(t *testing.T) {
+		f := newAPIKeyFixture(t)
+
+		_, err := f.useCase.CreateKey(f.owner.ID, request.CreateAPIKeyRequest{Name: "script", Scopes: []string{"admin"}})
+
+		if assert.NotNil(t, err) {
+			assert.Contains(t, err.Error, "unknown scope")
+		}
+		keys, errAll := f.store.apiKeys.GetAll()
+		require.NoError(t, errAll)
+		assert.Empty(t, keys)
+	})
+
+	t.Run("rejects a key without scopes", func(t *testing.T) {
+		f := newAPIKeyFixture(t)
+
+		_, err := f.useCase.CreateKey(f.owner.ID, request.CreateAPIKeyRequest{Name: "script"})
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("rejects an expiry in the past", func(t *testing.T) {
+		f := newAPIKeyFixture(t)
+		past := f.clock.now.Add(-time.Hour)
+
+		_, err := f.useCase.CreateKey(f.owner.ID, request.CreateAPIKeyRequest{Name: "script", Scopes: []string{"wallets:read"}, ExpiresAt: &past})
+
+		assert.NotNil(t, err)
+	})
+}
+
+func TestAPIKeyUseCase_ListKeys(t *testing.T) {
+	f := newAPIKeyFixture(t)
+	f.create(t, request.CreateAPIKeyRequest{Name: "old", Scopes: []string{"wallets:read"}})
+	f.clock.now = f.clock.now.Add(time.Hour)
+	f.create(t, request.CreateAPIKeyRequest{Name: "new", Scopes: []string{"account:read"}})
+	_, errOther := f.useCase.CreateKey(f.other.ID, request.CreateAPIKeyRequest{Name: "someone else", Scopes: []string{"wallets:read"}})
+	require.Nil(t, errOther)
+
+	keys, err := f.useCase.ListKeys(f.owner.ID)
+
+	require.Nil(t, err)
+	if assert.Len(t, keys, 2) {
+		assert.Equal(t, "new", keys[0].Name)
+		assert.Equal(t, "old", keys[1].Name)
+	}
+}
+
+func TestAPIKeyUseCase_RevokeKey(t *testing.T) {
+	t.Run("own key stops working", func(t *testing.T) {
+		f := newAPIKeyFixture(t)
+		created := f.create(t, request.CreateAPIKeyRequest{Name: "script", Scopes: []string{"wallets:read"}})
+
+		err := f.useCase.RevokeKey(f.owner.ID, created.ID)
+
+		assert.Nil(t, err)
+		assert.NotNil(t, f.stored(t, created.ID).RevokedAt)
+		_, errAuth := f.useCase.Authenticate(created.Key)
+		assert.ErrorIs(t, errAuth, types.ErrInvalidAPIKey)
+	})
+
+	t.Run("someone else's key is not found", func(t *testing.T) {
+		f := newAPIKeyFixture(t)
+		created := f.create(t, request.CreateAPIKeyRequest{Name: "script", Scopes: []string{"wallets:read"}})
+
+		err := f.useCase.RevokeKey(f.other.ID, created.ID)
+
+		if assert.NotNil(t, err) {
+			assert.Equal(t, "API key not found", err.Error)
+		}
+		assert.Nil(t, f.stored(t, created.ID).RevokedAt)
+	})
+}
+
+func TestAPIKeyUseCase_Authenticate(t *testing.T) {
+	t.Run("valid key", func(t *testing.T) {
+		f := newAPIKeyFixture(t)
+		created := f.create(t, request.CreateAPIKeyRequest{Name: "script", Scopes: []string{"wallets:write"}})
+
+		principal, err := f.useCase.Authenticate(created.Key)
+
+		require.NoError(t, err)
+		assert.Equal(t, f.owner.ID, principal.UserID)
+		assert.Equal(t, created.ID, principal.KeyID)
+		assert.Equal(t, []string{"wallets:write"}, principal.Scopes)
+		if lastUsed := f.stored(t, created.ID).LastUsedAt; assert.NotNil(t, lastUsed) {
+			assert.True(t, f.clock.now.Equal(*lastUsed))
+		}
+	})
+
+	t.Run("last use is written at most once a minute", func(t *testing.T) {
+		f := newAPIKeyFixture(t)
+		key := f.create(t, request.CreateAPIKeyRequest{Name: "script", Scopes: []string{"wallets:read"}}).Key
+
+		_, _ = f.useCase.Authenticate(key)
+		f.clock.now = f.clock.now.Add(30 * time.Second)
+		_, _ = f.useCase.Authenticate(key)
+		assert.Equal(t, 1, f.keys.updates)
+
+		f.clock.now = f.clock.now.Add(time.Minute)
+		_, _ = f.useCase.Authenticate(key)
+		assert.Equal(t, 2, f.keys.updates)
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		f := newAPIKeyFixture(t)
+
+		_, err := f.useCase.Authenticate("mfk_unknown")
+
+		assert.ErrorIs(t, err, types.ErrInvalidAPIKey)
+	})
+
+	t.Run("expired key", func(t *testing.T) {
+		f := newAPIKeyFixture(t)
+		expiresAt := f.clock.now.Add(time.Hour)
+		key := f.create(t, request.CreateAPIKeyRequest{Name: "script", Scopes: []string{"wallets:read"}, ExpiresAt: &expiresAt}).Key
+		f.clock.now = f.clock.now.Add(2 * time.Hour)
+
+		_, err := f.useCase.Authenticate(key)
+
+		assert.ErrorIs(t, err, types.ErrInvalidAPIKey)
+	})
+
+	t.Run("suspended owner", func(t *testing.T) {
+		f := newAPIKeyFixture(t)
+		key := f.create(t, request.CreateAPIKeyRequest{Name: "script", Scopes: []string{"wallets:read"}}).Key
+		f.store.setStatus(t, f.owner.ID, types.Suspend)
+
+		_, err := f.useCase.Authenticate(key)
+
+		assert.ErrorIs(t, err, types.ErrInvalidAPIKey)
+	})
+}
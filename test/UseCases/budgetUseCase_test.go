@@ -0,0 +1,143 @@
+package UseCases_test
+
+import (
+	"testing"
+	"time"
+
+	"Financial/Core/Models/db"
+	request "Financial/Core/Models/dtos/Request"
+	usecases "Financial/Core/UseCases"
+	contracts "Financial/Core/ports"
+	"Financial/Core/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newBudgetUseCase builds the use case on the categories of newCategorySample
+func newBudgetUseCase(store *memoryStore) contracts.BudgetUseCase {
+	return usecases.NewBudgetUseCase(store.budgets, store.categories, store.wallets, store.transactions)
+}
+
+func TestBudgetUseCase_GetBudgetProgress(t *testing.T) {
+	store := newCategorySample(t)
+	for _, budget := range []db.Budget{
+		{UserID: 1, CategoryID: 1, Period: "2025-07", Limit: money("100"), Currency: "USD"},
+		{UserID: 1, CategoryID: 5, Period: "2025-07", Limit: money("50"), Currency: "USD"},
+		{UserID: 1, CategoryID: 1, Period: "2025-06", Limit: money("10"), Currency: "USD"},
+	} {
+		_, err := store.budgets.Create(&budget)
+		require.NoError(t, err)
+	}
+
+	// The EUR wallet gets the same transactions, but they don't count towards USD budgets
+	dollars := store.addWallet(t, 1, "Checking", "0")
+	euros, errWallet := store.wallets.Create(&db.Wallet{Name: "Euros", Type: types.Debit, Balance: types.MustParseMoney("0", "EUR"), Currency: "EUR", UserID: 1})
+	require.NoError(t, errWallet)
+	july := date(2025, time.July, 10)
+	for _, wallet := range []int{dollars.ID, euros.ID} {
+		for _, transaction := range []db.Transaction{
+			{WalletID: wallet, Type: types.Expense, Amount: money("60"), CategoryID: intPtr(1), CreatedAt: july},
+			{WalletID: wallet, Type: types.Expense, Amount: money("45.50"), CategoryID: intPtr(2), CreatedAt: july},
+			{WalletID: wallet, Type: types.Expense, Amount: money("20"), CategoryID: intPtr(5), CreatedAt: july},
+			{WalletID: wallet, Type: types.Expense, Amount: money("99"), CreatedAt: july},
+			{WalletID: wallet, Type: types.Expense, Amount: money("70"), CategoryID: intPtr(1), CreatedAt: date(2025, time.June, 30)},
+		} {
+			if wallet == euros.ID {
+				transaction.Amount = transaction.Amount.WithCurrency("EUR")
+			}
+			_, err := store.transactions.Create(&transaction)
+			require.NoError(t, err)
+		}
+	}
+
+	progress, err := newBudgetUseCase(store).GetBudgetProgress(1, "2025-07")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "2025-07", progress.Period)
+	require.Len(t, progress.Budgets, 2)
+
+	food := progress.Budgets[0]
+	assert.Equal(t, "Food", food.CategoryName)
+	assert.Equal(t, money("105.50").Minor, food.Spent.Minor, "subcategory expenses count towards the parent budget")
+	assert.Equal(t, money("-5.50").Minor, food.Remaining.Minor)
+	assert.True(t, food.Exceeded)
+
+	leisure := progress.Budgets[1]
+	assert.Equal(t, "Leisure", leisure.CategoryName)
+	assert.Equal(t, money("20").Minor, leisure.Spent.Minor)
+	assert.Equal(t, money("30").Minor, leisure.Remaining.Minor)
+	assert.False(t, leisure.Exceeded)
+}
+
+func TestBudgetUseCase_GetBudgetProgress_InvalidPeriod(t *testing.T) {
+	_, err := newBudgetUseCase(newCategorySample(t)).GetBudgetProgress(1, "2025-13")
+
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error, "period must have the format YYYY-MM")
+}
+
+func TestBudgetUseCase_CreateBudget(t *testing.T) {
+	tests := []struct {
+		name        string
+		req         request.CreateBudgetRequest
+		existing    []db.Budget
+		expectErr   bool
+		expectedErr string
+	}{
+		{
+			name: "expense category",
+			req:  request.CreateBudgetRequest{UserID: 1, CategoryID: 1, Period: "2025-07", Limit: money("300")},
+		},
+		{
+			name:        "income category",
+			req:         request.CreateBudgetRequest{UserID: 1, CategoryID: 3, Period: "2025-07", Limit: money("300")},
+			expectErr:   true,
+			expectedErr: "budgets can only be set on expense categories",
+		},
+		{
+			name:        "category of another user",
+			req:         request.CreateBudgetRequest{UserID: 1, CategoryID: 4, Period: "2025-07", Limit: money("300")},
+			expectErr:   true,
+			expectedErr: "category not found",
+		},
+		{
+			name:        "budget already set for the period",
+			req:         request.CreateBudgetRequest{UserID: 1, CategoryID: 1, Period: "2025-07", Limit: money("300")},
+			existing:    []db.Budget{{UserID: 1, CategoryID: 1, Period: "2025-07", Limit: money("100"), Currency: "USD"}},
+			expectErr:   true,
+			expectedErr: "the category already has a budget for this period",
+		},
+		{
+			name:        "limit not positive",
+			req:         request.CreateBudgetRequest{UserID: 1, CategoryID: 1, Period: "2025-07", Limit: money("0")},
+			expectErr:   true,
+			expectedErr: "Limit must be greater than zero",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newCategorySample(t)
+			for _, budget := range tt.existing {
+				_, errCreate := store.budgets.Create(&budget)
+				require.NoError(t, errCreate)
+			}
+
+			budget, err := newBudgetUseCase(store).CreateBudget(tt.req)
+
+			if tt.expectErr {
+				assert.NotNil(t, err)
+				assert.Contains(t, err.Error, tt.expectedErr)
+				assert.Len(t, store.allBudgets(t), len(tt.existing))
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.NotNil(t, budget)
+			budgets := store.allBudgets(t)
+			require.Len(t, budgets, 1)
+			assert.Equal(t, "USD", budgets[0].Currency, "currency defaults to USD")
+		})
+	}
+}
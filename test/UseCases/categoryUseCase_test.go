@@ -0,0 +1,250 @@
+package UseCases_test
+
+import (
+	"testing"
+
+	"Financial/Core/Models/db"
+	request "Financial/Core/Models/dtos/Request"
+	usecases "Financial/Core/UseCases"
+	"Financial/Core/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+// newCategorySample stores the users 1 and 2, and Food > Restaurants, Salary and Leisure
+// for user 1 and Travel for user 2 as the categories 1 to 5
+func newCategorySample(t *testing.T) *memoryStore {
+	t.Helper()
+	store := newMemoryStore()
+	store.addUser(t, "ana")
+	store.addUser(t, "bob")
+	for _, category := range []db.Category{
+		{ID: 1, UserID: 1, Name: "Food", Type: types.Expense},
+		{ID: 2, UserID: 1, ParentID: intPtr(1), Name: "Restaurants", Type: types.Expense},
+		{ID: 3, UserID: 1, Name: "Salary", Type: types.Income},
+		{ID: 4, UserID: 2, Name: "Travel", Type: types.Expense},
+		{ID: 5, UserID: 1, Name: "Leisure", Type: types.Expense},
+	} {
+		created, err := store.categories.Create(&category)
+		require.NoError(t, err)
+		require.Equal(t, category.ID, created.ID)
+	}
+	return store
+}
+
+func TestCategoryUseCase_CreateCategory(t *testing.T) {
+	tests := []struct {
+		name        string
+		req         request.CreateCategoryRequest
+		expectErr   bool
+		expectedErr string
+	}{
+		{
+			name: "root category",
+			req:  request.CreateCategoryRequest{UserID: 1, Name: "Housing", Type: types.Expense},
+		},
+		{
+			name: "subcategory",
+			req:  request.CreateCategoryRequest{UserID: 1, Name: "Groceries", Type: types.Expense, ParentID: intPtr(1)},
+		},
+		{
+			name:        "blank name",
+			req:         request.CreateCategoryRequest{UserID: 1, Name: "  ", Type: types.Expense},
+			expectErr:   true,
+			expectedErr: "category name must have between 1 and 100 characters",
+		},
+		{
+			name:        "sibling with the same name",
+			req:         request.CreateCategoryRequest{UserID: 1, Name: "restaurants", Type: types.Expense, ParentID: intPtr(1)},
+			expectErr:   true,
+			expectedErr: "a category with this name already exists at this level",
+		},
+		{
+			name:        "parent of another user",
+			req:         request.CreateCategoryRequest{UserID: 1, Name: "Flights", Type: types.Expense, ParentID: intPtr(4)},
+			expectErr:   true,
+			expectedErr: "parent category not found",
+		},
+		{
+			name:        "type differs from the parent",
+			req:         request.CreateCategoryRequest{UserID: 1, Name: "Bonus", Type: types.Income, ParentID: intPtr(1)},
+			expectErr:   true,
+			expectedErr: "subcategory type must match its parent",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newCategorySample(t)
+			useCase := usecases.NewCategoryUseCase(store.categories, store.transactions)
+
+			category, err := useCase.CreateCategory(tt.req)
+
+			if tt.expectErr {
+				assert.NotNil(t, err)
+				assert.Contains(t, err.Error, tt.expectedErr)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.req.Name, category.Name)
+			assert.Equal(t, tt.req.ParentID, category.ParentID)
+			assert.NotNil(t, store.category(t, category.ID))
+		})
+	}
+}
+
+func TestCategoryUseCase_UpdateCategory(t *testing.T) {
+	tests := []struct {
+		name        string
+		req         request.UpdateCategoryRequest
+		expectErr   bool
+		expectedErr string
+		verify      func(t *testing.T, store *memoryStore)
+	}{
+		{
+			name: "move under another parent",
+			req:  request.UpdateCategoryRequest{CategoryID: 2, UserID: 1, ParentID: intPtr(5)},
+			verify: func(t *testing.T, store *memoryStore) {
+				assert.Equal(t, 5, *store.category(t, 2).ParentID)
+			},
+		},
+		{
+			name: "move to root and rename",
+			req:  request.UpdateCategoryRequest{CategoryID: 2, UserID: 1, Name: "Dining out", MoveToRoot: true},
+			verify: func(t *testing.T, store *memoryStore) {
+				moved := store.category(t, 2)
+				assert.Nil(t, moved.ParentID)
+				assert.Equal(t, "Dining out", moved.Name)
+			},
+		},
+		{
+			name:        "move under its own subcategory",
+			req:         request.UpdateCategoryRequest{CategoryID: 1, UserID: 1, ParentID: intPtr(2)},
+			expectErr:   true,
+			expectedErr: "a category can't be moved under itself or one of its subcategories",
+		},
+		{
+			name:        "rename to a sibling name",
+			req:         request.UpdateCategoryRequest{CategoryID: 5, UserID: 1, Name: "Food"},
+			expectErr:   true,
+			expectedErr: "a category with this name already exists at this level",
+		},
+		{
+			name:        "category of another user",
+			req:         request.UpdateCategoryRequest{CategoryID: 4, UserID: 1, Name: "Trips"},
+			expectErr:   true,
+			expectedErr: "category not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newCategorySample(t)
+			useCase := usecases.NewCategoryUseCase(store.categories, store.transactions)
+
+			_, err := useCase.UpdateCategory(tt.req)
+
+			if tt.expectErr {
+				assert.NotNil(t, err)
+				assert.Contains(t, err.Error, tt.expectedErr)
+				return
+			}
+
+			assert.Nil(t, err)
+			if tt.verify != nil {
+				tt.verify(t, store)
+			}
+		})
+	}
+}
+
+func TestCategoryUseCase_DeleteCategory(t *testing.T) {
+	t.Run("leaf without transactions", func(t *testing.T) {
+		store := newCategorySample(t)
+
+		err := usecases.NewCategoryUseCase(store.categories, store.transactions).DeleteCategory(1, 2, nil)
+
+		assert.Nil(t, err)
+		assert.Nil(t, store.category(t, 2))
+	})
+
+	t.Run("rejects when children exist and no reassignment is given", func(t *testing.T) {
+		store := newCategorySample(t)
+
+		err := usecases.NewCategoryUseCase(store.categories, store.transactions).DeleteCategory(1, 1, nil)
+
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error, "choose a category to reassign them to")
+		assert.NotNil(t, store.category(t, 1))
+	})
+
+	t.Run("reassigns children and transactions", func(t *testing.T) {
+		store := newCategorySample(t)
+		wallet := store.addWallet(t, 1, "Checking", "100")
+		spent, errCreate := store.transactions.Create(&db.Transaction{WalletID: wallet.ID, Type: types.Expense, Amount: money("12"), CategoryID: intPtr(1)})
+		require.NoError(t, errCreate)
+
+		err := usecases.NewCategoryUseCase(store.categories, store.transactions).DeleteCategory(1, 1, intPtr(5))
+
+		assert.Nil(t, err)
+		assert.Nil(t, store.category(t, 1))
+		assert.Equal(t, 5, *store.category(t, 2).ParentID)
+		updated, errGet := store.transactions.GetByID(spent.ID)
+		require.NoError(t, errGet)
+		assert.Equal(t, 5, *updated.CategoryID)
+	})
+
+	t.Run("reassignment into its own subcategory", func(t *testing.T) {
+		store := newCategorySample(t)
+
+		err := usecases.NewCategoryUseCase(store.categories, store.transactions).DeleteCategory(1, 1, intPtr(2))
+
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error, "reassignment category can't be the deleted category or one of its subcategories")
+	})
+
+	t.Run("reassignment with a different type", func(t *testing.T) {
+		store := newCategorySample(t)
+
+		err := usecases.NewCategoryUseCase(store.categories, store.transactions).DeleteCategory(1, 1, intPtr(3))
+
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error, "reassignment category must have the same type")
+	})
+}
+
+func TestCategoryUseCase_GetUserCategories(t *testing.T) {
+	store := newCategorySample(t)
+	useCase := usecases.NewCategoryUseCase(store.categories, store.transactions)
+
+	tree, err := useCase.GetUserCategories(1)
+
+	assert.Nil(t, err)
+	assert.Len(t, tree, 3, "only root categories of the user at the top level")
+	assert.Equal(t, "Food", tree[0].Name)
+	assert.Len(t, tree[0].Children, 1)
+	assert.Equal(t, "Restaurants", tree[0].Children[0].Name)
+}
+
+func TestCategoryUseCase_SeedDefaultCategories(t *testing.T) {
+	store := newMemoryStore()
+	user := store.addUser(t, "ana")
+	useCase := usecases.NewCategoryUseCase(store.categories, store.transactions)
+
+	err := useCase.SeedDefaultCategories(user.ID)
+
+	assert.Nil(t, err)
+	tree, _ := useCase.GetUserCategories(user.ID)
+	assert.NotEmpty(t, tree)
+	for _, root := range tree {
+		for _, child := range root.Children {
+			assert.Equal(t, root.Type, child.Type, "subcategories share the type of their parent")
+		}
+	}
+}
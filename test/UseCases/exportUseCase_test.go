@@ -0,0 +1,114 @@
+package UseCases_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"Financial/Core/Models/db"
+	request "Financial/Core/Models/dtos/Request"
+	usecases "Financial/Core/UseCases"
+	"Financial/Core/importers"
+	contracts "Financial/Core/ports"
+	"Financial/Core/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newExportUseCase stores the wallet 1 of the user 1 with a salary and a coffee in July,
+// the transactions 1 and 2, and a June fee as the transaction 3
+func newExportUseCase(t *testing.T) contracts.ExportUseCase {
+	store := newCategorySample(t)
+	store.addWallet(t, 1, "Checking", "96.50")
+	for _, transaction := range []db.Transaction{
+		{WalletID: 1, Type: types.Income, Amount: money("100"), Description: "Salary", CreatedAt: date(2025, time.July, 1)},
+		{WalletID: 1, Type: types.Expense, Amount: money("3.50"), Description: "Coffee & cake", CategoryID: intPtr(2), CreatedAt: date(2025, time.July, 2)},
+		{WalletID: 1, Type: types.Expense, Amount: money("1"), Description: "Fee", CreatedAt: date(2025, time.June, 30)},
+	} {
+		_, err := store.transactions.Create(&transaction)
+		require.NoError(t, err)
+	}
+	return usecases.NewExportUseCase(store.wallets, store.transactions)
+}
+
+func TestExportUseCase_Export(t *testing.T) {
+	t.Run("csv", func(t *testing.T) {
+		useCase := newExportUseCase(t)
+		var out bytes.Buffer
+
+		from := date(2025, time.July, 1)
+
+		err := useCase.Export(request.ExportRequest{UserID: 1, Format: "csv", From: &from}, &out)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "wallet_id,wallet_name,wallet_type,currency,transaction_id,date,type,amount,description,category_id\n"+
+			"1,Checking,Debit,USD,1,2025-07-01T00:00:00Z,Income,100.00,Salary,\n"+
+			"1,Checking,Debit,USD,2,2025-07-02T00:00:00Z,Expense,-3.50,Coffee & cake,2\n", out.String())
+	})
+
+	t.Run("json lines", func(t *testing.T) {
+		useCase := newExportUseCase(t)
+		var out bytes.Buffer
+
+		from := date(2025, time.July, 1)
+
+		err := useCase.Export(request.ExportRequest{UserID: 1, Format: "jsonl", From: &from}, &out)
+
+		assert.Nil(t, err)
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		assert.Len(t, lines, 3)
+		var record struct {
+			Record      string         `json:"record"`
+			Transaction db.Transaction `json:"transaction"`
+		}
+		assert.NoError(t, json.Unmarshal([]byte(lines[2]), &record))
+		assert.Equal(t, "transaction", record.Record)
+		assert.Equal(t, 2, record.Transaction.ID)
+	})
+
+	t.Run("ofx can be imported back", func(t *testing.T) {
+		useCase := newExportUseCase(t)
+		var out bytes.Buffer
+
+		from := date(2025, time.July, 1)
+
+		err := useCase.Export(request.ExportRequest{UserID: 1, Format: "ofx", From: &from}, &out)
+
+		assert.Nil(t, err)
+		assert.Contains(t, out.String(), "<BALAMT>96.50</BALAMT>")
+		parser, _ := importers.NewParser("ofx", request.ColumnMapping{})
+		lines, errParse := parser.Parse(&out)
+		assert.NoError(t, errParse)
+		assert.Len(t, lines, 2)
+		assert.Equal(t, "Coffee & cake", lines[1].Description)
+		assert.Equal(t, int64(-350), lines[1].Amount.Minor)
+	})
+
+	t.Run("date range filters the movements by day", func(t *testing.T) {
+		useCase := newExportUseCase(t)
+		from, to := date(2025, time.June, 30), date(2025, time.July, 1)
+		var out bytes.Buffer
+
+		err := useCase.Export(request.ExportRequest{UserID: 1, Format: "csv", From: &from, To: &to}, &out)
+
+		assert.Nil(t, err)
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		require.Len(t, lines, 3, "the whole last day is included")
+		assert.Contains(t, lines[1], ",Fee,")
+		assert.Contains(t, lines[2], ",Salary,")
+	})
+
+	t.Run("unknown format writes nothing", func(t *testing.T) {
+		useCase := newExportUseCase(t)
+		var out bytes.Buffer
+
+		err := useCase.Export(request.ExportRequest{UserID: 1, Format: "xlsx"}, &out)
+
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error, "unsupported export format")
+		assert.Zero(t, out.Len())
+	})
+}
@@ -0,0 +1,245 @@
+package UseCases_test
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"Financial/Core/Models/db"
+	usecases "Financial/Core/UseCases"
+	"Financial/Core/oidc"
+	contracts "Financial/Core/ports"
+	"Financial/Core/types"
+	mocks "Financial/Test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type externalLoginFixture struct {
+	server  *mocks.MockOIDCServer
+	store   *memoryStore
+	clock   *fakeClock
+	useCase contracts.ExternalLoginUseCase
+}
+
+func newExternalLoginFixture(t *testing.T, autoProvision bool) *externalLoginFixture {
+	server := mocks.NewMockOIDCServer("myfinance", "secret")
+	t.Cleanup(server.Close)
+
+	provider, err := oidc.Discover(oidc.Config{
+		Issuer:       server.Issuer(),
+		ClientID:     "myfinance",
+		ClientSecret: "secret",
+		RedirectURL:  "http://localhost:8080/api/auth/oidc/callback",
+	}, nil)
+	require.NoError(t, err)
+
+	store := newMemoryStore()
+	f := &externalLoginFixture{
+		server: server,
+		store:  store,
+		clock:  &fakeClock{now: time.Now()},
+	}
+	categories := usecases.NewCategoryUseCase(store.categories, store.transactions)
+	f.useCase = usecases.NewExternalLoginUseCase(provider, store.identities, store.oidcLogins, store.users, categories, fakeHasher{}, f.clock, autoProvision)
+	return f
+}
+
+// links reads the stored identity links
+func (f *externalLoginFixture) links(t *testing.T) []db.UserIdentity {
+	t.Helper()
+	links, err := f.store.identities.GetAll()
+	require.NoError(t, err)
+	return links
+}
+
+// pending reads the logins that were started and not completed
+func (f *externalLoginFixture) pending(t *testing.T) []db.OIDCLogin {
+	t.Helper()
+	logins, err := f.store.oidcLogins.GetAll()
+	require.NoError(t, err)
+	return logins
+}
+
+// users reads every stored account
+func (f *externalLoginFixture) users(t *testing.T) []db.User {
+	t.Helper()
+	users, err := f.store.users.GetAll()
+	require.NoError(t, err)
+	return users
+}
+
+// login starts a login and lets the mock provider answer it, returning the callback parameters
+func (f *externalLoginFixture) login(t *testing.T) (string, string) {
+	loginURL, err := f.useCase.StartLogin()
+	require.NoError(t, err)
+	state, code, err := f.server.Authorize(loginURL)
+	require.NoError(t, err)
+	return state, code
+}
+
+func TestExternalLoginUseCase_StartLogin(t *testing.T) {
+	f := newExternalLoginFixture(t, false)
+
+	loginURL, err := f.useCase.StartLogin()
+
+	require.NoError(t, err)
+	address, err := url.Parse(loginURL)
+	require.NoError(t, err)
+	query := address.Query()
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+	logins := f.pending(t)
+	require.Len(t, logins, 1)
+	for _, login := range logins {
+		// Only the hash of the state is kept; the challenge comes from the stored verifier
+		assert.NotEqual(t, query.Get("state"), login.StateHash)
+		assert.Equal(t, oidc.CodeChallenge(login.CodeVerifier), query.Get("code_challenge"))
+		assert.Equal(t, query.Get("nonce"), login.Nonce)
+	}
+}
+
+func TestExternalLoginUseCase_CompleteLogin(t *testing.T) {
+	t.Run("links an existing account by verified email", func(t *testing.T) {
+		f := newExternalLoginFixture(t, false)
+		ana := f.store.addUser(t, "ana")
+		state, code := f.login(t)
+
+		userID, err := f.useCase.CompleteLogin(state, code)
+
+		require.NoError(t, err)
+		assert.Equal(t, ana.ID, *userID)
+		links := f.links(t)
+		require.Len(t, links, 1)
+		assert.Equal(t, f.server.Issuer(), links[0].Issuer)
+		assert.Equal(t, "user-1", links[0].Subject)
+		assert.Equal(t, "hashed:secret", f.store.user(t, ana.ID).Password)
+	})
+
+	t.Run("uses the link on later logins", func(t *testing.T) {
+		f := newExternalLoginFixture(t, false)
+		ana := f.store.addUser(t, "ana")
+		_, err := f.store.identities.Create(&db.UserIdentity{UserID: ana.ID, Issuer: f.server.Issuer(), Subject: "user-1", Email: "ana@example.com"})
+		require.NoError(t, err)
+		// The email at the provider changed; the link still decides the account
+		f.server.User.Email = "ana@other.example.com"
+		state, code := f.login(t)
+
+		userID, err := f.useCase.CompleteLogin(state, code)
+
+		require.NoError(t, err)
+		assert.Equal(t, ana.ID, *userID)
+		links := f.links(t)
+		require.Len(t, links, 1)
+		assert.NotNil(t, links[0].LastLoginAt)
+	})
+
+	t.Run("unverified email is never linked", func(t *testing.T) {
+		f := newExternalLoginFixture(t, true)
+		f.store.addUser(t, "ana")
+		f.server.User.EmailVerified = false
+		state, code := f.login(t)
+
+		_, err := f.useCase.CompleteLogin(state, code)
+
+		assert.ErrorIs(t, err, types.ErrAccountNotLinked)
+		assert.Empty(t, f.links(t))
+		assert.Len(t, f.users(t), 1)
+	})
+
+	t.Run("unknown user without provisioning", func(t *testing.T) {
+		f := newExternalLoginFixture(t, false)
+		state, code := f.login(t)
+
+		_, err := f.useCase.CompleteLogin(state, code)
+
+		assert.ErrorIs(t, err, types.ErrAccountNotLinked)
+		assert.Empty(t, f.users(t))
+	})
+
+	t.Run("provisions an active account", func(t *testing.T) {
+		f := newExternalLoginFixture(t, true)
+		f.store.addUser(t, "ana", func(user *db.User) { user.Email = "someone@example.com" })
+		f.server.User = mocks.MockOIDCUser{Subject: "new", Email: "Ana@Example.com", EmailVerified: true, GivenName: "Ana", FamilyName: "Pérez"}
+		state, code := f.login(t)
+
+		userID, err := f.useCase.CompleteLogin(state, code)
+
+		require.NoError(t, err)
+		created := f.store.user(t, *userID)
+		assert.Equal(t, "ana@example.com", created.Email)
+		assert.Equal(t, "ana2", created.Nickname)
+		assert.Equal(t, "Ana", created.FirstName)
+		assert.Equal(t, "Pérez", created.Lastname)
+		assert.Equal(t, types.Active, created.Status)
+		assert.Equal(t, types.RoleUser, created.Role)
+		assert.Contains(t, created.Password, "hashed:")
+		categories, err := f.store.categories.GetAll()
+		require.NoError(t, err)
+		assert.NotEmpty(t, categories)
+		assert.Len(t, f.links(t), 1)
+	})
+
+	t.Run("unverified account is claimed with a new password", func(t *testing.T) {
+		f := newExternalLoginFixture(t, false)
+		ana := f.store.addUser(t, "ana", func(user *db.User) {
+			user.Status = types.Pending
+			user.Password = "hashed:chosen-by-someone"
+		})
+		state, code := f.login(t)
+
+		userID, err := f.useCase.CompleteLogin(state, code)
+
+		require.NoError(t, err)
+		assert.Equal(t, ana.ID, *userID)
+		claimed := f.store.user(t, ana.ID)
+		assert.Equal(t, types.Active, claimed.Status)
+		assert.NotEqual(t, "hashed:chosen-by-someone", claimed.Password)
+	})
+
+	t.Run("suspended account", func(t *testing.T) {
+		f := newExternalLoginFixture(t, false)
+		f.store.addUser(t, "ana", func(user *db.User) { user.Status = types.Suspend })
+		state, code := f.login(t)
+
+		_, err := f.useCase.CompleteLogin(state, code)
+
+		assert.ErrorIs(t, err, types.ErrAccountSuspended)
+	})
+
+	t.Run("state works once", func(t *testing.T) {
+		f := newExternalLoginFixture(t, false)
+		f.store.addUser(t, "ana")
+		state, code := f.login(t)
+		_, err := f.useCase.CompleteLogin(state, code)
+		require.NoError(t, err)
+
+		_, err = f.useCase.CompleteLogin(state, code)
+
+		assert.ErrorIs(t, err, types.ErrExternalLoginFailed)
+	})
+
+	t.Run("expired state", func(t *testing.T) {
+		f := newExternalLoginFixture(t, false)
+		f.store.addUser(t, "ana")
+		state, code := f.login(t)
+		f.clock.now = f.clock.now.Add(11 * time.Minute)
+
+		_, err := f.useCase.CompleteLogin(state, code)
+
+		assert.ErrorIs(t, err, types.ErrExternalLoginFailed)
+		assert.Empty(t, f.pending(t))
+	})
+
+	t.Run("ID token with another nonce", func(t *testing.T) {
+		f := newExternalLoginFixture(t, false)
+		f.store.addUser(t, "ana")
+		f.server.Claims = func(claims map[string]any) { claims["nonce"] = "replayed" }
+		state, code := f.login(t)
+
+		_, err := f.useCase.CompleteLogin(state, code)
+
+		assert.ErrorIs(t, err, types.ErrExternalLoginFailed)
+		assert.Empty(t, f.links(t))
+	})
+}
@@ -0,0 +1,86 @@
+package UseCases_test
+
+import (
+	"testing"
+	"time"
+
+	"Financial/Core/Models/db"
+	request "Financial/Core/Models/dtos/Request"
+	usecases "Financial/Core/UseCases"
+	"Financial/Core/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleStatement = "Date,Description,Amount\n" +
+	"2025-07-01,Salary,1500.00\n" +
+	"2025-07-02,Coffee,-3.50\n" +
+	"2025-07-02,Coffee,-3.50\n" +
+	"2025-07-03,Fee,0\n"
+
+func statementRequest(userID int) request.ImportStatementRequest {
+	return request.ImportStatementRequest{
+		UserID:   userID,
+		WalletID: 1,
+		Format:   "csv",
+		Content:  []byte(sampleStatement),
+		Mapping:  request.ColumnMapping{Date: "Date", Amount: "Amount", Description: "Description"},
+	}
+}
+
+// newImportFixture stores the user 1 with the wallet 1, the user 2 and the existing movements
+func newImportFixture(t *testing.T, existing ...db.Transaction) (*memoryStore, *usecases.ImportUseCase) {
+	store := newMemoryStore()
+	store.addUser(t, "ana")
+	store.addUser(t, "bob")
+	store.addWallet(t, 1, "Checking", "100")
+	for _, transaction := range existing {
+		_, err := store.transactions.Create(&transaction)
+		require.NoError(t, err)
+	}
+	transactions := usecases.NewTransactionUseCase(store.transactions, store.wallets, store.categories, store.unitOfWork)
+	return store, usecases.NewImportUseCase(store.transactions, store.wallets, transactions).(*usecases.ImportUseCase)
+}
+
+func TestImportUseCase_PreviewImport(t *testing.T) {
+	// One of the two coffees was already typed by hand
+	store, useCase := newImportFixture(t, db.Transaction{WalletID: 1, Type: types.Expense, Amount: money("3.50"), Description: "coffee", CreatedAt: time.Date(2025, time.July, 2, 18, 30, 0, 0, time.UTC)})
+
+	preview, err := useCase.PreviewImport(statementRequest(1))
+
+	assert.Nil(t, err)
+	assert.Len(t, preview.Lines, 3, "lines without amount are left out")
+	assert.Equal(t, 2, preview.New)
+	assert.Equal(t, 1, preview.Duplicates)
+	assert.False(t, preview.Lines[0].Duplicate)
+	assert.Equal(t, types.Income, preview.Lines[0].Type)
+	assert.True(t, preview.Lines[1].Duplicate)
+	assert.False(t, preview.Lines[2].Duplicate, "only as many lines as recorded movements are duplicates")
+	assert.Equal(t, types.Expense, preview.Lines[2].Type)
+	assert.Len(t, store.ledger(t), 1, "the preview records nothing")
+}
+
+func TestImportUseCase_CommitImport(t *testing.T) {
+	store, useCase := newImportFixture(t)
+
+	result, err := useCase.CommitImport(statementRequest(1))
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, result.Imported)
+	assert.Equal(t, 0, result.Duplicates)
+	ledger := store.ledger(t)
+	require.Len(t, ledger, 3)
+	assert.Equal(t, money("1500").Minor, ledger[0].Amount.Minor)
+	assert.Equal(t, money("1593").Minor, store.wallet(t, 1).Balance.Minor)
+}
+
+func TestImportUseCase_RejectsWalletOfAnotherUser(t *testing.T) {
+	store, useCase := newImportFixture(t)
+
+	_, err := useCase.CommitImport(statementRequest(2))
+
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error, "wallet not found")
+	assert.Empty(t, store.ledger(t))
+}
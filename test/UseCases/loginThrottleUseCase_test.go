@@ -0,0 +1,280 @@
+package UseCases_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"Financial/Core/Models/db"
+	dtos "Financial/Core/Models/dtos/Request"
+	usecases "Financial/Core/UseCases"
+	contracts "Financial/Core/ports"
+	"Financial/Core/types"
+	"Financial/persistence/infrastructure"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type throttleFixture struct {
+	store    *memoryStore
+	user     *db.User
+	attempts contracts.LoginAttemptStore
+	clock    *fakeClock
+	throttle contracts.LoginThrottle
+}
+
+func newThrottleFixture(t *testing.T) *throttleFixture {
+	store := newMemoryStore()
+	f := &throttleFixture{
+		store:    store,
+		user:     store.addUser(t, "ana"),
+		attempts: infrastructure.NewMemoryLoginAttemptStore(),
+		clock:    &fakeClock{now: time.Date(2025, 7, 14, 12, 0, 0, 0, time.UTC)},
+	}
+	f.throttle = f.newThrottle(usecases.LoginThrottleConfig{
+		FreeAttempts:     2,
+		IPFreeAttempts:   100,
+		BaseDelay:        time.Second,
+		MaxDelay:         8 * time.Second,
+		LockoutThreshold: 6,
+		LockoutDuration:  30 * time.Minute,
+		Window:           time.Hour,
+	})
+	return f
+}
+
+func (f *throttleFixture) newThrottle(config usecases.LoginThrottleConfig) contracts.LoginThrottle {
+	return usecases.NewLoginThrottleUseCase(f.attempts, f.store.users, f.store.audit, f.clock, config)
+}
+
+// userKey is the attempt key of the fixture's account
+func (f *throttleFixture) userKey() string {
+	return fmt.Sprintf("user:%d", f.user.ID)
+}
+
+// fail records a failed login
+func (f *throttleFixture) fail(t *testing.T, auth dtos.AuthRequest, ip string) {
+	require.NoError(t, f.throttle.LoginFailed(auth, ip))
+}
+
+func TestLoginThrottle_ExponentialBackoff(t *testing.T) {
+	f := newThrottleFixture(t)
+	auth := dtos.AuthRequest{Email: "ana@example.com"}
+
+	f.fail(t, auth, "10.0.0.1")
+	f.fail(t, auth, "10.0.0.1")
+	_, err := f.throttle.Allow(auth, "10.0.0.1")
+	assert.NoError(t, err, "the first failures are free")
+
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second}
+	for _, wait := range expected {
+		f.fail(t, auth, "10.0.0.1")
+
+		got, err := f.throttle.Allow(auth, "10.0.0.1")
+		assert.ErrorIs(t, err, types.ErrTooManyAttempts)
+		assert.Equal(t, wait, got)
+
+		f.clock.now = f.clock.now.Add(wait)
+		_, err = f.throttle.Allow(auth, "10.0.0.1")
+		assert.NoError(t, err, "after %v the account may try again", wait)
+	}
+}
+
+func TestLoginThrottle_BackoffIsCapped(t *testing.T) {
+	f := newThrottleFixture(t)
+	config := usecases.DefaultLoginThrottleConfig
+	config.MaxDelay = 8 * time.Second
+	f.throttle = f.newThrottle(config)
+	auth := dtos.AuthRequest{Email: "ana@example.com"}
+
+	for i := 0; i < 9; i++ {
+		f.fail(t, auth, "10.0.0.1")
+	}
+
+	wait, err := f.throttle.Allow(auth, "10.0.0.1")
+	assert.ErrorIs(t, err, types.ErrTooManyAttempts)
+	assert.Equal(t, 8*time.Second, wait, "six failures beyond the free ones would be 32s")
+}
+
+func TestLoginThrottle_LockoutSuspendsAndRestores(t *testing.T) {
+	f := newThrottleFixture(t)
+
+	// Failures by email and by nickname count for the same account
+	for i := 0; i < 3; i++ {
+		f.fail(t, dtos.AuthRequest{Email: "ana@example.com"}, "10.0.0.1")
+		f.fail(t, dtos.AuthRequest{Nickname: "ana"}, "10.0.0.2")
+	}
+
+	assert.Equal(t, types.Suspend, f.store.user(t, f.user.ID).Status)
+	entries := f.store.auditEntries(t)
+	require.Len(t, entries, 1)
+	assert.Equal(t, types.AuditAccountLocked, entries[0].Action)
+	assert.Equal(t, f.user.ID, *entries[0].UserID)
+	assert.Equal(t, "10.0.0.2", entries[0].IP)
+
+	wait, err := f.throttle.Allow(dtos.AuthRequest{Email: "ana@example.com"}, "10.0.0.3")
+	assert.ErrorIs(t, err, types.ErrTooManyAttempts)
+	assert.Equal(t, 30*time.Minute, wait)
+
+	f.clock.now = f.clock.now.Add(30 * time.Minute)
+	_, err = f.throttle.Allow(dtos.AuthRequest{Email: "ana@example.com"}, "10.0.0.3")
+	assert.NoError(t, err)
+	assert.Equal(t, types.Active, f.store.user(t, f.user.ID).Status, "the account gets its status back")
+	entries = f.store.auditEntries(t)
+	require.Len(t, entries, 2)
+	assert.Equal(t, types.AuditAccountUnlocked, entries[1].Action)
+
+	_, err = f.attempts.Get(f.userKey())
+	assert.ErrorIs(t, err, types.ErrNotFound, "the failures start over")
+}
+
+func TestLoginThrottle_AdminSuspensionSurvivesLockout(t *testing.T) {
+	f := newThrottleFixture(t)
+	f.store.setStatus(t, f.user.ID, types.Suspend)
+	auth := dtos.AuthRequest{Email: "ana@example.com"}
+
+	for i := 0; i < 6; i++ {
+		f.fail(t, auth, "10.0.0.1")
+	}
+	f.clock.now = f.clock.now.Add(time.Hour)
+	_, err := f.throttle.Allow(auth, "10.0.0.1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, types.Suspend, f.store.user(t, f.user.ID).Status)
+}
+
+func TestLoginThrottle_UnknownAccountsAreThrottledAlike(t *testing.T) {
+	f := newThrottleFixture(t)
+	auth := dtos.AuthRequest{Email: "Nobody@Example.com"}
+
+	for i := 0; i < 6; i++ {
+		f.fail(t, auth, "10.0.0.1")
+	}
+
+	wait, err := f.throttle.Allow(dtos.AuthRequest{Email: "nobody@example.com"}, "10.0.0.9")
+	assert.ErrorIs(t, err, types.ErrTooManyAttempts)
+	assert.Equal(t, 30*time.Minute, wait)
+	entries := f.store.auditEntries(t)
+	require.Len(t, entries, 1)
+	assert.Nil(t, entries[0].UserID)
+	assert.Contains(t, entries[0].Detail, "nobody@example.com")
+}
+
+func TestLoginThrottle_PerIP(t *testing.T) {
+	f := newThrottleFixture(t)
+	f.throttle = f.newThrottle(usecases.LoginThrottleConfig{
+		FreeAttempts:     100,
+		IPFreeAttempts:   3,
+		BaseDelay:        time.Second,
+		MaxDelay:         time.Minute,
+		LockoutThreshold: 100,
+		LockoutDuration:  time.Hour,
+		Window:           time.Hour,
+	})
+
+	// A client guessing across many accounts is slowed down by its address
+	for _, email := range []string{"a@example.com", "b@example.com", "c@example.com", "d@example.com"} {
+		f.fail(t, dtos.AuthRequest{Email: email}, "10.0.0.1")
+	}
+
+	_, err := f.throttle.Allow(dtos.AuthRequest{Email: "ana@example.com"}, "10.0.0.1")
+	assert.ErrorIs(t, err, types.ErrTooManyAttempts)
+	_, err = f.throttle.Allow(dtos.AuthRequest{Email: "ana@example.com"}, "10.0.0.2")
+	assert.NoError(t, err, "other addresses are not affected")
+}
+
+func TestLoginThrottle_SuccessAndWindowReset(t *testing.T) {
+	f := newThrottleFixture(t)
+	auth := dtos.AuthRequest{Email: "ana@example.com"}
+
+	for i := 0; i < 4; i++ {
+		f.fail(t, auth, "10.0.0.1")
+	}
+	require.NoError(t, f.throttle.LoginSucceeded(f.user.ID))
+	_, err := f.attempts.Get(f.userKey())
+	assert.ErrorIs(t, err, types.ErrNotFound)
+	_, err = f.attempts.Get("ip:10.0.0.1")
+	assert.NoError(t, err, "a valid login doesn't clear the address")
+
+	for i := 0; i < 5; i++ {
+		f.fail(t, auth, "10.0.0.1")
+	}
+	f.clock.now = f.clock.now.Add(time.Hour)
+	f.fail(t, auth, "10.0.0.1")
+	attempt, err := f.attempts.Get(f.userKey())
+	require.NoError(t, err)
+	assert.Equal(t, 1, attempt.Failures, "failures older than the window are forgotten")
+	assert.Equal(t, types.Active, f.store.user(t, f.user.ID).Status)
+}
+
+func TestLoginThrottle_SecondFactorCountsWithPasswords(t *testing.T) {
+	f := newThrottleFixture(t)
+
+	// A stolen password gets the attacker to the code; guessing it costs the same as guessing passwords
+	f.fail(t, dtos.AuthRequest{Email: "ana@example.com"}, "10.0.0.1")
+	require.NoError(t, f.throttle.SecondFactorFailed(f.user.ID, "10.0.0.1"))
+	_, err := f.throttle.AllowSecondFactor(f.user.ID, "10.0.0.1")
+	assert.NoError(t, err, "the first failures are free")
+
+	require.NoError(t, f.throttle.SecondFactorFailed(f.user.ID, "10.0.0.1"))
+	wait, err := f.throttle.AllowSecondFactor(f.user.ID, "10.0.0.1")
+	assert.ErrorIs(t, err, types.ErrTooManyAttempts)
+	assert.Equal(t, time.Second, wait)
+	_, err = f.throttle.Allow(dtos.AuthRequest{Nickname: "ana"}, "10.0.0.2")
+	assert.ErrorIs(t, err, types.ErrTooManyAttempts, "passwords wait as well")
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, f.throttle.SecondFactorFailed(f.user.ID, "10.0.0.1"))
+	}
+	assert.Equal(t, types.Suspend, f.store.user(t, f.user.ID).Status, "wrong codes lock the account out")
+	wait, err = f.throttle.AllowSecondFactor(f.user.ID, "10.0.0.1")
+	assert.ErrorIs(t, err, types.ErrTooManyAttempts)
+	assert.Equal(t, 30*time.Minute, wait)
+
+	// A login is only successful once the code is accepted
+	require.NoError(t, f.throttle.LoginSucceeded(f.user.ID))
+	_, err = f.attempts.Get(f.userKey())
+	assert.ErrorIs(t, err, types.ErrNotFound)
+}
+
+func TestLoginThrottle_ClaimChallenge(t *testing.T) {
+	f := newThrottleFixture(t)
+
+	first, err := f.throttle.ClaimChallenge("3f2a", 5*time.Minute)
+	require.NoError(t, err)
+	assert.True(t, first)
+
+	again, err := f.throttle.ClaimChallenge("3f2a", 5*time.Minute)
+	require.NoError(t, err)
+	assert.False(t, again, "a challenge completes a single login")
+
+	other, err := f.throttle.ClaimChallenge("9b71", 5*time.Minute)
+	require.NoError(t, err)
+	assert.True(t, other)
+}
+
+func TestLoginThrottle_AllowPasswordReset(t *testing.T) {
+	f := newThrottleFixture(t)
+	config := usecases.DefaultLoginThrottleConfig
+	config.ResetRequests = 2
+	config.IPResetRequests = 3
+	f.throttle = f.newThrottle(config)
+
+	for i := 0; i < 2; i++ {
+		_, err := f.throttle.AllowPasswordReset("ana@example.com", "10.0.0.1")
+		require.NoError(t, err)
+	}
+	wait, err := f.throttle.AllowPasswordReset("ANA@example.com", "10.0.0.2")
+	assert.ErrorIs(t, err, types.ErrTooManyAttempts, "an address gets a few emails, whatever the case")
+	assert.Equal(t, time.Hour, wait)
+
+	_, err = f.throttle.AllowPasswordReset("nobody@example.com", "10.0.0.1")
+	assert.NoError(t, err, "unknown addresses are counted the same")
+	_, err = f.throttle.AllowPasswordReset("someone@example.com", "10.0.0.1")
+	assert.ErrorIs(t, err, types.ErrTooManyAttempts, "a client can't spread its requests over many addresses")
+
+	f.clock.now = f.clock.now.Add(time.Hour)
+	_, err = f.throttle.AllowPasswordReset("ana@example.com", "10.0.0.1")
+	assert.NoError(t, err, "requests older than the window are forgotten")
+}
@@ -0,0 +1,164 @@
+package UseCases_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"Financial/Core/Models/db"
+	contracts "Financial/Core/ports"
+	"Financial/Core/types"
+	"Financial/persistence/infrastructure"
+
+	"github.com/stretchr/testify/require"
+)
+
+// memoryStore is an infrastructure.MemoryStore with the repositories the use cases are built
+// from. The store enforces the keys, checks and uniques of the SQL schema, so tests only pass
+// when the use cases write rows the database would accept.
+type memoryStore struct {
+	*infrastructure.MemoryStore
+	users        contracts.Repository[db.User, int]
+	wallets      contracts.Repository[db.Wallet, int]
+	transactions contracts.Repository[db.Transaction, int]
+	categories   contracts.Repository[db.Category, int]
+	budgets      contracts.Repository[db.Budget, int]
+	recurring    contracts.Repository[db.RecurringTransaction, int]
+	sessions     contracts.SessionRepository
+	resets       contracts.Repository[db.PasswordReset, int]
+	twoFactors   contracts.Repository[db.TwoFactor, int]
+	apiKeys      contracts.Repository[db.APIKey, int]
+	identities   contracts.Repository[db.UserIdentity, int]
+	oidcLogins   contracts.Repository[db.OIDCLogin, int]
+	audit        contracts.Repository[db.AuditEntry, int]
+	transfers    contracts.TransferRepository
+	unitOfWork   contracts.UnitOfWork
+}
+
+func newMemoryStore() *memoryStore {
+	store := infrastructure.NewMemoryStore()
+	return &memoryStore{
+		MemoryStore:  store,
+		users:        infrastructure.NewMemoryUserRepository(store),
+		wallets:      infrastructure.NewMemoryWalletRepository(store),
+		transactions: infrastructure.NewMemoryTransactionRepository(store),
+		categories:   infrastructure.NewMemoryCategoryRepository(store),
+		budgets:      infrastructure.NewMemoryBudgetRepository(store),
+		recurring:    infrastructure.NewMemoryRecurringTransactionRepository(store),
+		sessions:     infrastructure.NewMemorySessionRepository(store),
+		resets:       infrastructure.NewMemoryPasswordResetRepository(store),
+		twoFactors:   infrastructure.NewMemoryTwoFactorRepository(store),
+		apiKeys:      infrastructure.NewMemoryAPIKeyRepository(store),
+		identities:   infrastructure.NewMemoryUserIdentityRepository(store),
+		oidcLogins:   infrastructure.NewMemoryOIDCLoginRepository(store),
+		audit:        infrastructure.NewMemoryAuditRepository(store),
+		transfers:    infrastructure.NewMemoryTransferRepository(store),
+		unitOfWork:   infrastructure.NewMemoryUnitOfWork(store),
+	}
+}
+
+// addUser stores an active account named nickname, with the email nickname@example.com;
+// edit changes it before it is stored
+func (s *memoryStore) addUser(t *testing.T, nickname string, edit ...func(user *db.User)) *db.User {
+	t.Helper()
+	user := db.User{
+		Nickname: nickname,
+		Email:    strings.ToLower(nickname) + "@example.com",
+		Status:   types.Active,
+		Role:     types.RoleUser,
+		Password: "hashed:secret",
+	}
+	for _, change := range edit {
+		change(&user)
+	}
+	created, err := s.users.Create(&user)
+	require.NoError(t, err)
+	return created
+}
+
+// user reads an account back from the store
+func (s *memoryStore) user(t *testing.T, id int) *db.User {
+	t.Helper()
+	user, err := s.users.GetByID(id)
+	require.NoError(t, err)
+	return user
+}
+
+// setStatus changes the status of a stored account
+func (s *memoryStore) setStatus(t *testing.T, id int, status types.AccountStatus) {
+	t.Helper()
+	user := s.user(t, id)
+	user.Status = status
+	_, err := s.users.Update(user)
+	require.NoError(t, err)
+}
+
+// addWallet stores a USD debit wallet of the user with the given balance
+func (s *memoryStore) addWallet(t *testing.T, userID int, name string, balance string) *db.Wallet {
+	t.Helper()
+	wallet, err := s.wallets.Create(&db.Wallet{Name: name, Type: types.Debit, Balance: types.MustParseMoney(balance, "USD"), Currency: "USD", UserID: userID})
+	require.NoError(t, err)
+	return wallet
+}
+
+// wallet reads a wallet back from the store
+func (s *memoryStore) wallet(t *testing.T, id int) *db.Wallet {
+	t.Helper()
+	wallet, err := s.wallets.GetByID(id)
+	require.NoError(t, err)
+	return wallet
+}
+
+// addCategory stores a root category of the user
+func (s *memoryStore) addCategory(t *testing.T, userID int, name string, categoryType types.TransactionType) *db.Category {
+	t.Helper()
+	category, err := s.categories.Create(&db.Category{UserID: userID, Name: name, Type: categoryType})
+	require.NoError(t, err)
+	return category
+}
+
+// category reads a category back from the store, or nil when it was deleted
+func (s *memoryStore) category(t *testing.T, id int) *db.Category {
+	t.Helper()
+	category, err := s.categories.GetByID(id)
+	if errors.Is(err, types.ErrNotFound) {
+		return nil
+	}
+	require.NoError(t, err)
+	return category
+}
+
+// ledger reads every stored transaction
+func (s *memoryStore) ledger(t *testing.T) []db.Transaction {
+	t.Helper()
+	transactions, err := s.transactions.GetAll()
+	require.NoError(t, err)
+	return transactions
+}
+
+// allBudgets reads every stored budget
+func (s *memoryStore) allBudgets(t *testing.T) []db.Budget {
+	t.Helper()
+	budgets, err := s.budgets.GetAll()
+	require.NoError(t, err)
+	return budgets
+}
+
+// countingUpdates counts the updates that reach the repository it wraps
+type countingUpdates[T any] struct {
+	contracts.Repository[T, int]
+	updates int
+}
+
+func (r *countingUpdates[T]) Update(model *T) (*T, error) {
+	r.updates++
+	return r.Repository.Update(model)
+}
+
+// auditEntries reads the audit log in the order it was written
+func (s *memoryStore) auditEntries(t *testing.T) []db.AuditEntry {
+	t.Helper()
+	entries, err := s.audit.GetAll()
+	require.NoError(t, err)
+	return entries
+}
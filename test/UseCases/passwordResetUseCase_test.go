@@ -0,0 +1,187 @@
+package UseCases_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"Financial/Core/Models/db"
+	request "Financial/Core/Models/dtos/Request"
+	response "Financial/Core/Models/dtos/Response"
+	usecases "Financial/Core/UseCases"
+	contracts "Financial/Core/ports"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sessionRecorder records the users whose sessions were all revoked
+type sessionRecorder struct {
+	contracts.SessionUseCase
+	loggedOut []int
+}
+
+func (s *sessionRecorder) LogoutAll(userID int) *response.ErrorResponse {
+	s.loggedOut = append(s.loggedOut, userID)
+	return nil
+}
+
+type resetFixture struct {
+	store    *memoryStore
+	user     *db.User
+	sessions *sessionRecorder
+	mailer   *fakeMailer
+	clock    *fakeClock
+	useCase  contracts.PasswordResetUseCase
+}
+
+func newResetFixture(t *testing.T) *resetFixture {
+	store := newMemoryStore()
+	f := &resetFixture{
+		store:    store,
+		user:     store.addUser(t, "ana", func(user *db.User) { user.Password = "hashed:old-password" }),
+		sessions: &sessionRecorder{},
+		mailer:   &fakeMailer{},
+		clock:    &fakeClock{now: date(2025, time.July, 9)},
+	}
+	f.useCase = usecases.NewPasswordResetUseCase(store.resets, store.users, fakeHasher{}, f.sessions, f.mailer, f.clock, "http://localhost:8080/reset-password", time.Hour)
+	return f
+}
+
+// resets reads the stored reset tokens in the order they were issued
+func (f *resetFixture) resets(t *testing.T) []db.PasswordReset {
+	t.Helper()
+	resets, err := f.store.resets.GetAll()
+	require.NoError(t, err)
+	return resets
+}
+
+// password is the stored password hash of the account
+func (f *resetFixture) password(t *testing.T) string {
+	return f.store.user(t, f.user.ID).Password
+}
+
+func TestPasswordResetUseCase_ResetPassword(t *testing.T) {
+	t.Run("sets the password and logs out every session", func(t *testing.T) {
+		f := newResetFixture(t)
+		f.useCase.RequestReset("ana@example.com")
+		require.Len(t, f.mailer.sent, 1)
+		assert.Equal(t, "ana@example.com", f.mailer.sent[0].To)
+		token := sentToken(t, f.mailer)
+		assert.NotContains(t, f.resets(t)[0].TokenHash, token, "only the hash is stored")
+
+		err := f.useCase.ResetPassword(request.ResetPasswordRequest{Token: token, Password: "n3w-passw0rd"})
+
+		assert.Nil(t, err)
+		assert.Equal(t, "hashed:n3w-passw0rd", f.password(t))
+		assert.Equal(t, []int{f.user.ID}, f.sessions.loggedOut)
+		assert.NotNil(t, f.resets(t)[0].UsedAt)
+	})
+
+	t.Run("a token works once", func(t *testing.T) {
+		f := newResetFixture(t)
+		f.useCase.RequestReset("ana@example.com")
+		token := sentToken(t, f.mailer)
+		f.useCase.ResetPassword(request.ResetPasswordRequest{Token: token, Password: "n3w-passw0rd"})
+
+		err := f.useCase.ResetPassword(request.ResetPasswordRequest{Token: token, Password: "an0ther-one"})
+
+		assert.Equal(t, "invalid or expired reset token", err.Error)
+		assert.Equal(t, "hashed:n3w-passw0rd", f.password(t))
+	})
+
+	t.Run("a reset burns the other outstanding tokens", func(t *testing.T) {
+		f := newResetFixture(t)
+		f.useCase.RequestReset("ana@example.com")
+		first := sentToken(t, f.mailer)
+		f.useCase.RequestReset("ana@example.com")
+		second := sentToken(t, f.mailer)
+
+		assert.Nil(t, f.useCase.ResetPassword(request.ResetPasswordRequest{Token: second, Password: "n3w-passw0rd"}))
+
+		assert.NotNil(t, f.useCase.ResetPassword(request.ResetPasswordRequest{Token: first, Password: "an0ther-one"}))
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		f := newResetFixture(t)
+		f.useCase.RequestReset("ana@example.com")
+		f.clock.now = f.clock.now.Add(61 * time.Minute)
+
+		err := f.useCase.ResetPassword(request.ResetPasswordRequest{Token: sentToken(t, f.mailer), Password: "n3w-passw0rd"})
+
+		assert.Equal(t, "invalid or expired reset token", err.Error)
+		assert.Equal(t, "hashed:old-password", f.password(t))
+		assert.Empty(t, f.sessions.loggedOut)
+	})
+
+	t.Run("password rules of account updates apply", func(t *testing.T) {
+		f := newResetFixture(t)
+		f.useCase.RequestReset("ana@example.com")
+		token := sentToken(t, f.mailer)
+
+		err := f.useCase.ResetPassword(request.ResetPasswordRequest{Token: token, Password: "short"})
+		assert.Contains(t, err.Error, "Password not have length")
+
+		err = f.useCase.ResetPassword(request.ResetPasswordRequest{Token: token, Password: "has spaces inside"})
+		assert.Contains(t, err.Error, "Password contains space")
+
+		assert.Nil(t, f.resets(t)[0].UsedAt, "a rejected password doesn't use the token")
+	})
+
+	t.Run("unknown token", func(t *testing.T) {
+		f := newResetFixture(t)
+
+		err := f.useCase.ResetPassword(request.ResetPasswordRequest{Token: "made-up", Password: "n3w-passw0rd"})
+
+		assert.Equal(t, "invalid or expired reset token", err.Error)
+	})
+}
+
+func TestPasswordResetUseCase_RequestReset_UnknownEmail(t *testing.T) {
+	f := newResetFixture(t)
+
+	f.useCase.RequestReset("nobody@example.com")
+
+	assert.Empty(t, f.mailer.sent)
+	assert.Empty(t, f.resets(t))
+}
+
+// resetRequests passes on the emails whose reset was requested
+type resetRequests struct {
+	contracts.PasswordResetUseCase
+	emails chan string
+}
+
+func (r *resetRequests) RequestReset(email string) {
+	r.emails <- email
+}
+
+func TestPasswordResetQueue(t *testing.T) {
+	requests := &resetRequests{emails: make(chan string, 3)}
+	queue := usecases.NewPasswordResetQueue(requests, 2)
+
+	queue.RequestReset("ana@example.com")
+	queue.RequestReset("bob@example.com")
+	queue.RequestReset("eve@example.com")
+	assert.Empty(t, requests.emails, "nothing is sent before the workers start")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		queue.Start(ctx, 1)
+		close(done)
+	}()
+
+	for _, want := range []string{"ana@example.com", "bob@example.com"} {
+		select {
+		case email := <-requests.emails:
+			assert.Equal(t, want, email)
+		case <-time.After(time.Second):
+			t.Fatalf("reset for %s not sent", want)
+		}
+	}
+
+	cancel()
+	<-done
+	assert.Empty(t, requests.emails, "a request that found the queue full is dropped")
+}
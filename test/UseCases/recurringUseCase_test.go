@@ -0,0 +1,374 @@
+package UseCases_test
+
+import (
+	"testing"
+	"time"
+
+	"Financial/Core/Models/db"
+	request "Financial/Core/Models/dtos/Request"
+	response "Financial/Core/Models/dtos/Response"
+	usecases "Financial/Core/UseCases"
+	contracts "Financial/Core/ports"
+	"Financial/Core/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// failingPostings posts through the transaction use case, except that the next postings fail
+// with the errors in failures, in order: "category not found" as when the category of the
+// series was deleted, or a storage error
+type failingPostings struct {
+	contracts.TransactionUseCase
+	failures []string
+}
+
+func (uc *failingPostings) RecordTransaction(userID int, req request.CreateTransactionRequest) (*db.Transaction, *response.ErrorResponse) {
+	if len(uc.failures) > 0 {
+		failure := uc.failures[0]
+		uc.failures = uc.failures[1:]
+		return nil, &response.ErrorResponse{Error: failure}
+	}
+	return uc.TransactionUseCase.RecordTransaction(userID, req)
+}
+
+type recurringFixture struct {
+	store     *memoryStore
+	postings  *failingPostings
+	clock     *fakeClock
+	scheduler contracts.RecurringScheduler
+}
+
+// newRecurringFixture stores the user 1 with the wallet 1, the user 2 and the given series
+func newRecurringFixture(t *testing.T, now time.Time, series ...db.RecurringTransaction) *recurringFixture {
+	store := newMemoryStore()
+	store.addUser(t, "ana")
+	store.addUser(t, "bob")
+	store.addWallet(t, 1, "Checking", "0")
+	for _, s := range series {
+		_, err := store.recurring.Create(&s)
+		require.NoError(t, err)
+	}
+	f := &recurringFixture{
+		store:    store,
+		postings: &failingPostings{TransactionUseCase: usecases.NewTransactionUseCase(store.transactions, store.wallets, store.categories, store.unitOfWork)},
+		clock:    &fakeClock{now: now},
+	}
+	f.scheduler = usecases.NewRecurringScheduler(store.recurring, store.transactions, f.postings, f.clock)
+	return f
+}
+
+// series reads a series back from the store
+func (f *recurringFixture) series(t *testing.T, id int) *db.RecurringTransaction {
+	t.Helper()
+	series, err := f.store.recurring.GetByID(id)
+	require.NoError(t, err)
+	return series
+}
+
+func salarySeries() db.RecurringTransaction {
+	start := date(2025, time.January, 15)
+	return db.RecurringTransaction{
+		ID:         1,
+		UserID:     1,
+		WalletID:   1,
+		Type:       types.Income,
+		Amount:     money("1500"),
+		Frequency:  types.Monthly,
+		Interval:   1,
+		ByMonthDay: []int{15, 30},
+		StartDate:  start,
+		Status:     types.RecurringActive,
+		NextRunAt:  &start,
+	}
+}
+
+func TestRecurringTransaction_NextOccurrence(t *testing.T) {
+	endDate := date(2025, time.March, 1)
+
+	tests := []struct {
+		name     string
+		series   db.RecurringTransaction
+		after    time.Time
+		expected []time.Time
+	}{
+		{
+			name:   "twice a month, falling on the last day of February",
+			series: db.RecurringTransaction{Frequency: types.Monthly, ByMonthDay: []int{15, 30}, StartDate: date(2025, time.January, 15)},
+			after:  date(2025, time.January, 1),
+			expected: []time.Time{
+				date(2025, time.January, 15), date(2025, time.January, 30),
+				date(2025, time.February, 15), date(2025, time.February, 28),
+				date(2025, time.March, 15),
+			},
+		},
+		{
+			name:     "last day of the month",
+			series:   db.RecurringTransaction{Frequency: types.Monthly, ByMonthDay: []int{-1}, StartDate: date(2024, time.January, 1)},
+			after:    date(2024, time.January, 1),
+			expected: []time.Time{date(2024, time.January, 31), date(2024, time.February, 29), date(2024, time.March, 31)},
+		},
+		{
+			name:     "every two weeks",
+			series:   db.RecurringTransaction{Frequency: types.Weekly, Interval: 2, StartDate: date(2025, time.January, 3)},
+			after:    date(2025, time.February, 1),
+			expected: []time.Time{date(2025, time.February, 14), date(2025, time.February, 28)},
+		},
+		{
+			name:     "yearly subscription",
+			series:   db.RecurringTransaction{Frequency: types.Yearly, StartDate: date(2023, time.June, 10)},
+			after:    date(2025, time.July, 1),
+			expected: []time.Time{date(2026, time.June, 10), date(2027, time.June, 10)},
+		},
+		{
+			name:     "stops at the end date",
+			series:   db.RecurringTransaction{Frequency: types.Monthly, StartDate: date(2025, time.January, 1), EndDate: &endDate},
+			after:    date(2024, time.December, 31),
+			expected: []time.Time{date(2025, time.January, 1), date(2025, time.February, 1), date(2025, time.March, 1)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			after := tt.after
+			for _, expected := range tt.expected {
+				next, ok := tt.series.NextOccurrence(after)
+				assert.True(t, ok)
+				assert.Equal(t, expected, next)
+				after = next
+			}
+			if tt.series.EndDate != nil {
+				_, ok := tt.series.NextOccurrence(after)
+				assert.False(t, ok, "no occurrences after the end date")
+			}
+		})
+	}
+}
+
+func TestRecurringScheduler_RunDue(t *testing.T) {
+	t.Run("posts every due occurrence once", func(t *testing.T) {
+		f := newRecurringFixture(t, date(2025, time.March, 1), salarySeries())
+
+		posted, err := f.scheduler.RunDue()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 4, posted)
+		assert.Equal(t, date(2025, time.March, 15), *f.series(t, 1).NextRunAt)
+		assert.Equal(t, money("6000").Minor, f.store.wallet(t, 1).Balance.Minor, "postings move the balance")
+
+		posted, err = f.scheduler.RunDue()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, posted, "nothing is due until the next occurrence")
+		assert.Len(t, f.store.ledger(t), 4)
+	})
+
+	t.Run("does not double post when the progress was not saved", func(t *testing.T) {
+		f := newRecurringFixture(t, date(2025, time.February, 1), salarySeries())
+
+		_, err := f.scheduler.RunDue()
+		assert.NoError(t, err)
+		assert.Len(t, f.store.ledger(t), 2)
+
+		// Simulates a restart that lost the progress of the series
+		lost := salarySeries()
+		_, errUpdate := f.store.recurring.Update(&lost)
+		require.NoError(t, errUpdate)
+		posted, err := f.scheduler.RunDue()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, posted)
+		assert.Len(t, f.store.ledger(t), 2)
+		assert.Equal(t, date(2025, time.February, 15), *f.series(t, 1).NextRunAt)
+	})
+
+	t.Run("ends the series after its last occurrence", func(t *testing.T) {
+		series := salarySeries()
+		endDate := date(2025, time.January, 31)
+		series.EndDate = &endDate
+		f := newRecurringFixture(t, date(2025, time.June, 1), series)
+
+		posted, err := f.scheduler.RunDue()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, posted)
+		assert.Equal(t, types.RecurringEnded, f.series(t, 1).Status)
+		assert.Nil(t, f.series(t, 1).NextRunAt)
+	})
+
+	t.Run("a rejected posting does not hold back the later occurrences", func(t *testing.T) {
+		f := newRecurringFixture(t, date(2025, time.March, 1), salarySeries())
+		f.postings.failures = []string{"category not found"}
+
+		posted, err := f.scheduler.RunDue()
+
+		assert.ErrorContains(t, err, "recurring transaction 1: occurrence 2025-01-15T00:00:00Z skipped: category not found")
+		assert.Equal(t, 3, posted)
+		ledger := f.store.ledger(t)
+		require.Len(t, ledger, 3)
+		assert.Equal(t, date(2025, time.January, 30), *ledger[0].OccurrenceDate)
+		assert.Equal(t, date(2025, time.March, 15), *f.series(t, 1).NextRunAt, "the failed occurrence is skipped")
+
+		posted, err = f.scheduler.RunDue()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, posted, "the skipped occurrence is not retried")
+	})
+
+	t.Run("a storage failure stops the series until the next run", func(t *testing.T) {
+		f := newRecurringFixture(t, date(2025, time.March, 1), salarySeries())
+		f.postings.failures = []string{"error recording transaction: connection reset"}
+
+		posted, err := f.scheduler.RunDue()
+
+		assert.ErrorContains(t, err, "recurring transaction 1: occurrence 2025-01-15T00:00:00Z not posted, retrying on the next run: error recording transaction: connection reset")
+		assert.Equal(t, 0, posted)
+		assert.Empty(t, f.store.ledger(t))
+		assert.Equal(t, date(2025, time.January, 15), *f.series(t, 1).NextRunAt, "the occurrence is not skipped")
+
+		posted, err = f.scheduler.RunDue()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 4, posted)
+		ledger := f.store.ledger(t)
+		require.Len(t, ledger, 4)
+		assert.Equal(t, date(2025, time.January, 15), *ledger[0].OccurrenceDate)
+		assert.Equal(t, date(2025, time.March, 15), *f.series(t, 1).NextRunAt)
+	})
+
+	t.Run("a storage failure after earlier postings keeps them", func(t *testing.T) {
+		f := newRecurringFixture(t, date(2025, time.March, 1), salarySeries())
+		f.postings.failures = []string{"category not found", "error fetching wallet: timeout"}
+
+		posted, err := f.scheduler.RunDue()
+
+		assert.ErrorContains(t, err, "occurrence 2025-01-15T00:00:00Z skipped: category not found")
+		assert.ErrorContains(t, err, "occurrence 2025-01-30T00:00:00Z not posted, retrying on the next run")
+		assert.Equal(t, 0, posted)
+		assert.Equal(t, date(2025, time.January, 30), *f.series(t, 1).NextRunAt, "the rejected occurrence stays skipped")
+
+		posted, err = f.scheduler.RunDue()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, posted)
+	})
+
+	t.Run("skips paused series", func(t *testing.T) {
+		series := salarySeries()
+		series.Status = types.RecurringPaused
+		f := newRecurringFixture(t, date(2025, time.March, 1), series)
+
+		posted, err := f.scheduler.RunDue()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, posted)
+	})
+}
+
+func TestRecurringTransactionUseCase_CreateRecurring(t *testing.T) {
+	tests := []struct {
+		name        string
+		req         request.CreateRecurringRequest
+		expectErr   bool
+		expectedErr string
+	}{
+		{
+			name: "monthly rent",
+			req:  request.CreateRecurringRequest{UserID: 1, WalletID: 1, Type: types.Expense, Amount: money("800"), Frequency: "monthly", StartDate: date(2025, time.July, 1)},
+		},
+		{
+			name:        "wallet of another user",
+			req:         request.CreateRecurringRequest{UserID: 2, WalletID: 1, Type: types.Expense, Amount: money("800"), Frequency: types.Monthly, StartDate: date(2025, time.July, 1)},
+			expectErr:   true,
+			expectedErr: "wallet not found",
+		},
+		{
+			name:        "days of the month on a weekly schedule",
+			req:         request.CreateRecurringRequest{UserID: 1, WalletID: 1, Type: types.Expense, Amount: money("10"), Frequency: types.Weekly, ByMonthDay: []int{1}, StartDate: date(2025, time.July, 1)},
+			expectErr:   true,
+			expectedErr: "invalid days of the month",
+		},
+		{
+			name:        "unknown frequency",
+			req:         request.CreateRecurringRequest{UserID: 1, WalletID: 1, Type: types.Expense, Amount: money("10"), Frequency: "HOURLY", StartDate: date(2025, time.July, 1)},
+			expectErr:   true,
+			expectedErr: "frequency must be DAILY, WEEKLY, MONTHLY or YEARLY",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newCategorySample(t)
+			store.addWallet(t, 1, "Checking", "0")
+			useCase := usecases.NewRecurringTransactionUseCase(store.recurring, store.wallets, store.categories, &fakeClock{now: date(2025, time.June, 1)})
+
+			series, err := useCase.CreateRecurring(tt.req)
+
+			if tt.expectErr {
+				assert.NotNil(t, err)
+				assert.Contains(t, err.Error, tt.expectedErr)
+				stored, _ := store.recurring.GetAll()
+				assert.Empty(t, stored)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, types.Monthly, series.Frequency)
+			assert.Equal(t, 1, series.Interval)
+			assert.Equal(t, types.RecurringActive, series.Status)
+			assert.Equal(t, tt.req.StartDate, *series.NextRunAt)
+		})
+	}
+}
+
+func TestRecurringTransactionUseCase_UpdateRecurring(t *testing.T) {
+	t.Run("resuming skips the occurrences missed while paused", func(t *testing.T) {
+		series := salarySeries()
+		series.Status = types.RecurringPaused
+		f := newRecurringFixture(t, date(2025, time.April, 20), series)
+		useCase := usecases.NewRecurringTransactionUseCase(f.store.recurring, f.store.wallets, f.store.categories, f.clock)
+
+		updated, err := useCase.UpdateRecurring(request.UpdateRecurringRequest{RecurringID: 1, UserID: 1, Status: types.RecurringActive})
+
+		assert.Nil(t, err)
+		assert.Equal(t, types.RecurringActive, updated.Status)
+		assert.Equal(t, date(2025, time.April, 30), *updated.NextRunAt)
+	})
+
+	t.Run("ending stops the series", func(t *testing.T) {
+		f := newRecurringFixture(t, date(2025, time.January, 20), salarySeries())
+		useCase := usecases.NewRecurringTransactionUseCase(f.store.recurring, f.store.wallets, f.store.categories, f.clock)
+
+		updated, err := useCase.UpdateRecurring(request.UpdateRecurringRequest{RecurringID: 1, UserID: 1, Status: types.RecurringEnded})
+
+		assert.Nil(t, err)
+		assert.Equal(t, types.RecurringEnded, updated.Status)
+		assert.Nil(t, updated.NextRunAt)
+
+		_, err = useCase.UpdateRecurring(request.UpdateRecurringRequest{RecurringID: 1, UserID: 1, Status: types.RecurringActive})
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error, "the recurring transaction has already ended")
+	})
+
+	t.Run("series of another user", func(t *testing.T) {
+		f := newRecurringFixture(t, time.Time{}, salarySeries())
+		useCase := usecases.NewRecurringTransactionUseCase(f.store.recurring, f.store.wallets, f.store.categories, f.clock)
+
+		_, err := useCase.UpdateRecurring(request.UpdateRecurringRequest{RecurringID: 1, UserID: 2, Status: types.RecurringPaused})
+
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error, "recurring transaction not found")
+	})
+}
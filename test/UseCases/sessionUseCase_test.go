@@ -0,0 +1,234 @@
+package UseCases_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"Financial/Core/Models/db"
+	response "Financial/Core/Models/dtos/Response"
+	usecases "Financial/Core/UseCases"
+	contracts "Financial/Core/ports"
+	"Financial/Core/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// racingRotation runs beforeRotate right before the next rotation is written, as a
+// concurrent refresh would
+type racingRotation struct {
+	contracts.SessionRepository
+	beforeRotate func()
+}
+
+func (r *racingRotation) RotateRefresh(session *db.Session, previousHash string) (*db.Session, error) {
+	if r.beforeRotate != nil {
+		run := r.beforeRotate
+		r.beforeRotate = nil
+		run()
+	}
+	return r.SessionRepository.RotateRefresh(session, previousHash)
+}
+
+type sessionFixture struct {
+	store    *memoryStore
+	sessions *racingRotation
+	ana      *db.User
+	bob      *db.User
+	clock    *fakeClock
+	useCase  contracts.SessionUseCase
+}
+
+func newSessionFixture(t *testing.T) *sessionFixture {
+	store := newMemoryStore()
+	f := &sessionFixture{
+		store:    store,
+		sessions: &racingRotation{SessionRepository: store.sessions},
+		ana:      store.addUser(t, "ana"),
+		bob:      store.addUser(t, "bob"),
+		clock:    &fakeClock{now: date(2025, time.July, 7)},
+	}
+	f.useCase = usecases.NewSessionUseCase(f.sessions, store.users, f.clock, 24*time.Hour)
+	return f
+}
+
+// session reads a session back from the store
+func (f *sessionFixture) session(t *testing.T, id int) *db.Session {
+	t.Helper()
+	session, err := f.store.sessions.GetByID(id)
+	require.NoError(t, err)
+	return session
+}
+
+func TestSessionUseCase_StartSession(t *testing.T) {
+	f := newSessionFixture(t)
+
+	grant, err := f.useCase.StartSession(f.ana.ID, "curl/8.0", "10.0.0.1")
+
+	assert.Nil(t, err)
+	assert.Equal(t, strconv.Itoa(f.ana.ID), grant.Subject)
+	assert.NotEmpty(t, grant.TokenID)
+	assert.NotEmpty(t, grant.RefreshToken)
+	assert.Equal(t, f.clock.now.Add(24*time.Hour), grant.RefreshExpiresAt)
+
+	session := f.session(t, grant.SessionID)
+	assert.Equal(t, f.ana.ID, session.UserID)
+	assert.Equal(t, "curl/8.0", session.Device)
+	assert.Equal(t, "10.0.0.1", session.IP)
+	assert.Equal(t, grant.TokenID, session.AccessTokenID)
+	assert.NotEqual(t, grant.RefreshToken, session.RefreshTokenHash, "the refresh token must be stored hashed")
+	assert.NoError(t, f.useCase.ValidateAccess(grant.TokenID))
+}
+
+func TestSessionUseCase_RefreshSession(t *testing.T) {
+	t.Run("rotates both tokens", func(t *testing.T) {
+		f := newSessionFixture(t)
+		first, _ := f.useCase.StartSession(f.ana.ID, "curl/8.0", "10.0.0.1")
+
+		f.clock.now = f.clock.now.Add(time.Hour)
+		second, err := f.useCase.RefreshSession(first.RefreshToken, "firefox", "10.0.0.2")
+
+		assert.Nil(t, err)
+		assert.Equal(t, first.SessionID, second.SessionID)
+		assert.NotEqual(t, first.TokenID, second.TokenID)
+		assert.NotEqual(t, first.RefreshToken, second.RefreshToken)
+		assert.Equal(t, f.clock.now.Add(24*time.Hour), second.RefreshExpiresAt)
+		assert.ErrorIs(t, f.useCase.ValidateAccess(first.TokenID), types.ErrSessionRevoked)
+		assert.NoError(t, f.useCase.ValidateAccess(second.TokenID))
+		assert.Equal(t, "10.0.0.2", f.session(t, second.SessionID).IP)
+	})
+
+	t.Run("reusing a rotated token revokes the session", func(t *testing.T) {
+		f := newSessionFixture(t)
+		first, _ := f.useCase.StartSession(f.ana.ID, "curl/8.0", "10.0.0.1")
+		second, _ := f.useCase.RefreshSession(first.RefreshToken, "curl/8.0", "10.0.0.1")
+
+		_, err := f.useCase.RefreshSession(first.RefreshToken, "curl/8.0", "10.0.0.1")
+
+		assert.NotNil(t, err)
+		assert.NotNil(t, f.session(t, first.SessionID).RevokedAt)
+		assert.ErrorIs(t, f.useCase.ValidateAccess(second.TokenID), types.ErrSessionRevoked)
+		_, err = f.useCase.RefreshSession(second.RefreshToken, "curl/8.0", "10.0.0.1")
+		assert.NotNil(t, err)
+	})
+
+	t.Run("of two refreshes with the same token only one rotates it", func(t *testing.T) {
+		f := newSessionFixture(t)
+		first, _ := f.useCase.StartSession(f.ana.ID, "curl/8.0", "10.0.0.1")
+
+		// The other refresh rotates the token after this one read the session
+		var winner *response.SessionGrant
+		f.sessions.beforeRotate = func() {
+			winner, _ = f.useCase.RefreshSession(first.RefreshToken, "firefox", "10.0.0.2")
+		}
+		loser, err := f.useCase.RefreshSession(first.RefreshToken, "curl/8.0", "10.0.0.1")
+
+		assert.Nil(t, loser)
+		assert.Equal(t, "invalid refresh token", err.Error)
+		require.NotNil(t, winner)
+		assert.NoError(t, f.useCase.ValidateAccess(winner.TokenID))
+		assert.Equal(t, "firefox", f.session(t, first.SessionID).Device, "the losing refresh wrote nothing")
+	})
+
+	t.Run("suspended account", func(t *testing.T) {
+		f := newSessionFixture(t)
+		first, _ := f.useCase.StartSession(f.ana.ID, "curl/8.0", "10.0.0.1")
+
+		f.store.setStatus(t, f.ana.ID, types.Suspend)
+		grant, err := f.useCase.RefreshSession(first.RefreshToken, "curl/8.0", "10.0.0.1")
+
+		assert.Nil(t, grant)
+		assert.Equal(t, types.ErrAccountSuspended.Error(), err.Error)
+		assert.NotNil(t, f.session(t, first.SessionID).RevokedAt, "the session of a suspended account is closed")
+		assert.ErrorIs(t, f.useCase.ValidateAccess(first.TokenID), types.ErrSessionRevoked)
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		f := newSessionFixture(t)
+		first, _ := f.useCase.StartSession(f.ana.ID, "curl/8.0", "10.0.0.1")
+
+		f.clock.now = f.clock.now.Add(25 * time.Hour)
+		_, err := f.useCase.RefreshSession(first.RefreshToken, "curl/8.0", "10.0.0.1")
+
+		assert.Equal(t, "invalid refresh token", err.Error)
+	})
+
+	t.Run("unknown token", func(t *testing.T) {
+		f := newSessionFixture(t)
+
+		_, err := f.useCase.RefreshSession("not-a-token", "curl/8.0", "10.0.0.1")
+
+		assert.Equal(t, "invalid refresh token", err.Error)
+	})
+}
+
+func TestSessionUseCase_Logout(t *testing.T) {
+	f := newSessionFixture(t)
+	laptop, _ := f.useCase.StartSession(f.ana.ID, "firefox", "10.0.0.1")
+	phone, _ := f.useCase.StartSession(f.ana.ID, "android", "10.0.0.2")
+
+	assert.Nil(t, f.useCase.Logout(laptop.TokenID))
+
+	assert.ErrorIs(t, f.useCase.ValidateAccess(laptop.TokenID), types.ErrSessionRevoked)
+	assert.NoError(t, f.useCase.ValidateAccess(phone.TokenID))
+	_, err := f.useCase.RefreshSession(laptop.RefreshToken, "firefox", "10.0.0.1")
+	assert.NotNil(t, err)
+}
+
+func TestSessionUseCase_LogoutAll(t *testing.T) {
+	f := newSessionFixture(t)
+	laptop, _ := f.useCase.StartSession(f.ana.ID, "firefox", "10.0.0.1")
+	phone, _ := f.useCase.StartSession(f.ana.ID, "android", "10.0.0.2")
+
+	assert.Nil(t, f.useCase.LogoutAll(f.ana.ID))
+
+	assert.ErrorIs(t, f.useCase.ValidateAccess(laptop.TokenID), types.ErrSessionRevoked)
+	assert.ErrorIs(t, f.useCase.ValidateAccess(phone.TokenID), types.ErrSessionRevoked)
+}
+
+func TestSessionUseCase_GetSessions(t *testing.T) {
+	f := newSessionFixture(t)
+	laptop, _ := f.useCase.StartSession(f.ana.ID, "firefox", "10.0.0.1")
+	f.clock.now = f.clock.now.Add(time.Hour)
+	phone, _ := f.useCase.StartSession(f.ana.ID, "android", "10.0.0.2")
+	_, errOther := f.useCase.StartSession(f.bob.ID, "someone else", "10.0.0.3")
+	require.Nil(t, errOther)
+
+	sessions, err := f.useCase.GetSessions(f.ana.ID, laptop.TokenID)
+
+	assert.Nil(t, err)
+	assert.Len(t, sessions, 2)
+	assert.Equal(t, phone.SessionID, sessions[0].ID, "most recently used first")
+	assert.False(t, sessions[0].Current)
+	assert.Equal(t, laptop.SessionID, sessions[1].ID)
+	assert.True(t, sessions[1].Current)
+}
+
+func TestSessionUseCase_RevokeSession(t *testing.T) {
+	f := newSessionFixture(t)
+	phone, _ := f.useCase.StartSession(f.ana.ID, "android", "10.0.0.2")
+	other, _ := f.useCase.StartSession(f.bob.ID, "firefox", "10.0.0.3")
+
+	assert.Nil(t, f.useCase.RevokeSession(f.ana.ID, phone.SessionID))
+	assert.ErrorIs(t, f.useCase.ValidateAccess(phone.TokenID), types.ErrSessionRevoked)
+
+	err := f.useCase.RevokeSession(f.ana.ID, other.SessionID)
+	assert.Equal(t, "session not found", err.Error)
+	assert.Nil(t, f.session(t, other.SessionID).RevokedAt)
+}
+
+func TestSessionUseCase_ValidateAccess_TouchesLastSeen(t *testing.T) {
+	f := newSessionFixture(t)
+	grant, _ := f.useCase.StartSession(f.ana.ID, "firefox", "10.0.0.1")
+
+	f.clock.now = f.clock.now.Add(10 * time.Second)
+	assert.NoError(t, f.useCase.ValidateAccess(grant.TokenID))
+	assert.Equal(t, date(2025, time.July, 7), f.session(t, grant.SessionID).LastSeenAt)
+
+	f.clock.now = f.clock.now.Add(5 * time.Minute)
+	assert.NoError(t, f.useCase.ValidateAccess(grant.TokenID))
+	assert.Equal(t, f.clock.now, f.session(t, grant.SessionID).LastSeenAt)
+
+	assert.ErrorIs(t, f.useCase.ValidateAccess(""), types.ErrSessionRevoked)
+}
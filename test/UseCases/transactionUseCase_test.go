@@ -0,0 +1,442 @@
+package UseCases_test
+
+import (
+	"errors"
+	"testing"
+
+	"Financial/Core/Models/db"
+	request "Financial/Core/Models/dtos/Request"
+	usecases "Financial/Core/UseCases"
+	contracts "Financial/Core/ports"
+	"Financial/Core/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// money parses an amount without currency for the test tables
+func money(value string) types.Money {
+	return types.MustParseMoney(value, "")
+}
+
+type ledgerFixture struct {
+	store *memoryStore
+	// savings and card belong to user 1, other to user 2; all of them hold 100
+	savings *db.Wallet
+	card    *db.Wallet
+	other   *db.Wallet
+	// broken, when set, changes the repositories of every unit of work
+	broken  func(repos *contracts.UnitOfWorkRepositories)
+	useCase contracts.TransactionUseCase
+}
+
+func newLedgerFixture(t *testing.T) *ledgerFixture {
+	store := newMemoryStore()
+	ana := store.addUser(t, "ana")
+	bob := store.addUser(t, "bob")
+	f := &ledgerFixture{
+		store:   store,
+		savings: store.addWallet(t, ana.ID, "Savings", "100"),
+		other:   store.addWallet(t, bob.ID, "Other", "100"),
+	}
+	card, err := store.wallets.Create(&db.Wallet{Name: "Card", Type: types.Credit, Balance: money("100"), Currency: "USD", UserID: ana.ID})
+	require.NoError(t, err)
+	f.card = card
+
+	unitOfWork := unitOfWorkFunc(func(fn func(repos contracts.UnitOfWorkRepositories) error) error {
+		return store.unitOfWork.Do(func(repos contracts.UnitOfWorkRepositories) error {
+			if f.broken != nil {
+				f.broken(&repos)
+			}
+			return fn(repos)
+		})
+	})
+	f.useCase = usecases.NewTransactionUseCase(store.transactions, store.wallets, store.categories, unitOfWork)
+	return f
+}
+
+// record stores a transaction of the wallet through the use case
+func (f *ledgerFixture) record(t *testing.T, wallet *db.Wallet, transactionType types.TransactionType, amount string) *db.Transaction {
+	t.Helper()
+	transaction, err := f.useCase.RecordTransaction(wallet.UserID, request.CreateTransactionRequest{WalletID: wallet.ID, Type: transactionType, Amount: money(amount)})
+	require.Nil(t, err)
+	return transaction
+}
+
+// balance is the stored balance of the wallet
+func (f *ledgerFixture) balance(t *testing.T, wallet *db.Wallet) string {
+	return f.store.wallet(t, wallet.ID).Balance.Decimal()
+}
+
+// postedMeanwhile changes the balance of the wallet by amount at the start of the next unit
+// of work, like a posting committed after the use case checked the wallet
+func (f *ledgerFixture) postedMeanwhile(t *testing.T, wallet *db.Wallet, amount string) {
+	f.broken = func(repos *contracts.UnitOfWorkRepositories) {
+		f.broken = nil
+		current, err := repos.Wallets.GetByID(wallet.ID)
+		require.NoError(t, err)
+		balance, err := current.Balance.Add(types.MustParseMoney(amount, current.Currency))
+		require.NoError(t, err)
+		current.Balance = balance
+		_, err = repos.Wallets.Update(current)
+		require.NoError(t, err)
+	}
+}
+
+func TestTransactionUseCase_RecordTransaction(t *testing.T) {
+	tests := []struct {
+		name            string
+		card            bool
+		walletID        int
+		req             request.CreateTransactionRequest
+		broken          func(repos *contracts.UnitOfWorkRepositories)
+		expectErr       bool
+		expectedErr     error
+		expectedBalance string
+	}{
+		{
+			name: "successful income",
+			req: request.CreateTransactionRequest{
+				Type:        types.Income,
+				Amount:      money("250.25"),
+				Description: "Salary",
+			},
+			expectedBalance: "350.25",
+		},
+		{
+			name: "successful expense",
+			req: request.CreateTransactionRequest{
+				Type:   types.Expense,
+				Amount: money("40"),
+			},
+			expectedBalance: "60.00",
+		},
+		{
+			name: "expense can take a credit wallet below zero",
+			card: true,
+			req: request.CreateTransactionRequest{
+				Type:   types.Expense,
+				Amount: money("150"),
+			},
+			expectedBalance: "-50.00",
+		},
+		{
+			name: "expense would overdraw a debit wallet",
+			req: request.CreateTransactionRequest{
+				Type:   types.Expense,
+				Amount: money("150"),
+			},
+			expectErr:   true,
+			expectedErr: errors.New("insufficient funds in debit wallet"),
+		},
+		{
+			name: "amount must be positive",
+			req: request.CreateTransactionRequest{
+				Type:   types.Income,
+				Amount: money("0"),
+			},
+			expectErr:   true,
+			expectedErr: errors.New("Amount must be greater than zero"),
+		},
+		{
+			name: "unknown transaction type",
+			req: request.CreateTransactionRequest{
+				Type:   "Refund",
+				Amount: money("10"),
+			},
+			expectErr:   true,
+			expectedErr: errors.New("Type is not valid"),
+		},
+		{
+			name:     "wallet not found",
+			walletID: 99,
+			req: request.CreateTransactionRequest{
+				Type:   types.Income,
+				Amount: money("10"),
+			},
+			expectErr:   true,
+			expectedErr: errors.New("wallet not found"),
+		},
+		{
+			name: "balance update failure",
+			req: request.CreateTransactionRequest{
+				Type:   types.Income,
+				Amount: money("10"),
+			},
+			broken: func(repos *contracts.UnitOfWorkRepositories) {
+				repos.Wallets = failingUpdates[db.Wallet]{repos.Wallets}
+			},
+			expectErr:   true,
+			expectedErr: errors.New("error updating wallet balance"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newLedgerFixture(t)
+			f.broken = tt.broken
+			wallet := f.savings
+			if tt.card {
+				wallet = f.card
+			}
+			tt.req.WalletID = wallet.ID
+			if tt.walletID != 0 {
+				tt.req.WalletID = tt.walletID
+			}
+
+			transaction, err := f.useCase.RecordTransaction(wallet.UserID, tt.req)
+
+			if tt.expectErr {
+				assert.NotNil(t, err)
+				assert.Contains(t, err.Error, tt.expectedErr.Error())
+				assert.Empty(t, f.store.ledger(t), "the transaction is not kept without its balance")
+				assert.Equal(t, "100.00", f.balance(t, wallet))
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.req.Amount, transaction.Amount)
+			assert.Equal(t, tt.req.Type, transaction.Type)
+			assert.Equal(t, tt.expectedBalance, f.balance(t, wallet))
+		})
+	}
+}
+
+// failingQueries is a repository whose Query always fails
+type failingQueries[T any] struct {
+	contracts.Repository[T, int]
+}
+
+func (failingQueries[T]) Query(string, contracts.QueryOptions) (contracts.Page[T], error) {
+	return contracts.Page[T]{}, assert.AnError
+}
+
+func TestTransactionUseCase_GetWalletTransactions(t *testing.T) {
+	t.Run("returns the wallet ledger", func(t *testing.T) {
+		f := newLedgerFixture(t)
+		f.record(t, f.savings, types.Income, "10")
+		f.record(t, f.savings, types.Expense, "5")
+		f.record(t, f.other, types.Income, "1")
+
+		transactions, err := f.useCase.GetWalletTransactions(f.savings.UserID, f.savings.ID)
+
+		assert.Nil(t, err)
+		assert.Len(t, transactions, 2)
+	})
+
+	t.Run("invalid wallet ID", func(t *testing.T) {
+		f := newLedgerFixture(t)
+
+		_, err := f.useCase.GetWalletTransactions(f.savings.UserID, 0)
+
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error, "invalid wallet ID")
+	})
+
+	t.Run("repository query fails", func(t *testing.T) {
+		f := newLedgerFixture(t)
+		useCase := usecases.NewTransactionUseCase(failingQueries[db.Transaction]{f.store.transactions}, f.store.wallets, f.store.categories, f.store.unitOfWork)
+
+		_, err := useCase.GetWalletTransactions(f.savings.UserID, f.savings.ID)
+
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error, assert.AnError.Error())
+	})
+}
+
+func TestTransactionUseCase_RecordTransaction_ConcurrentPosting(t *testing.T) {
+	f := newLedgerFixture(t)
+	f.postedMeanwhile(t, f.savings, "50")
+
+	f.record(t, f.savings, types.Expense, "30")
+
+	assert.Equal(t, "120.00", f.balance(t, f.savings), "the balance starts from the one the other posting wrote")
+}
+
+func TestTransactionUseCase_DeleteTransaction(t *testing.T) {
+	t.Run("reverts the transaction on the balance", func(t *testing.T) {
+		f := newLedgerFixture(t)
+		spent := f.record(t, f.savings, types.Expense, "25")
+		require.Equal(t, "75.00", f.balance(t, f.savings))
+
+		err := f.useCase.DeleteTransaction(f.savings.UserID, f.savings.ID, spent.ID)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "100.00", f.balance(t, f.savings))
+		assert.Empty(t, f.store.ledger(t))
+	})
+
+	t.Run("reverts on the balance a concurrent posting wrote", func(t *testing.T) {
+		f := newLedgerFixture(t)
+		spent := f.record(t, f.savings, types.Expense, "25")
+		f.postedMeanwhile(t, f.savings, "50")
+
+		err := f.useCase.DeleteTransaction(f.savings.UserID, f.savings.ID, spent.ID)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "150.00", f.balance(t, f.savings))
+	})
+
+	t.Run("transaction from another wallet", func(t *testing.T) {
+		f := newLedgerFixture(t)
+		charge := f.record(t, f.card, types.Income, "25")
+
+		err := f.useCase.DeleteTransaction(f.savings.UserID, f.savings.ID, charge.ID)
+
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error, "transaction not found")
+		assert.Len(t, f.store.ledger(t), 1)
+	})
+
+	t.Run("reverting an income would overdraw a debit wallet", func(t *testing.T) {
+		f := newLedgerFixture(t)
+		earned := f.record(t, f.savings, types.Income, "25")
+		f.record(t, f.savings, types.Expense, "115")
+
+		err := f.useCase.DeleteTransaction(f.savings.UserID, f.savings.ID, earned.ID)
+
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error, "insufficient funds in debit wallet")
+		assert.Len(t, f.store.ledger(t), 2)
+		assert.Equal(t, "10.00", f.balance(t, f.savings))
+	})
+
+	t.Run("keeps the balance when the transaction can't be removed", func(t *testing.T) {
+		f := newLedgerFixture(t)
+		earned := f.record(t, f.savings, types.Income, "10")
+		f.broken = func(repos *contracts.UnitOfWorkRepositories) {
+			repos.Transactions = failingDeletes[db.Transaction]{repos.Transactions}
+		}
+
+		err := f.useCase.DeleteTransaction(f.savings.UserID, f.savings.ID, earned.ID)
+
+		require.NotNil(t, err)
+		assert.Contains(t, err.Error, "error deleting transaction")
+		assert.Equal(t, "110.00", f.balance(t, f.savings), "the balance still counts the transaction")
+	})
+}
+
+func TestTransactionUseCase_RecordTransaction_Category(t *testing.T) {
+	tests := []struct {
+		name        string
+		category    string
+		expectErr   bool
+		expectedErr string
+	}{
+		{name: "tagged with a category of the wallet owner", category: "salary"},
+		{name: "category of another user", category: "foreign", expectErr: true, expectedErr: "category not found"},
+		{name: "income tagged with an expense category", category: "food", expectErr: true, expectedErr: "category type must match the transaction type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newLedgerFixture(t)
+			categories := map[string]*db.Category{
+				"food":    f.store.addCategory(t, f.savings.UserID, "Food", types.Expense),
+				"salary":  f.store.addCategory(t, f.savings.UserID, "Salary", types.Income),
+				"foreign": f.store.addCategory(t, f.other.UserID, "Salary", types.Income),
+			}
+			categoryID := categories[tt.category].ID
+
+			transaction, err := f.useCase.RecordTransaction(f.savings.UserID, request.CreateTransactionRequest{
+				WalletID:   f.savings.ID,
+				Type:       types.Income,
+				Amount:     money("10"),
+				CategoryID: &categoryID,
+			})
+
+			if tt.expectErr {
+				assert.NotNil(t, err)
+				assert.Contains(t, err.Error, tt.expectedErr)
+				assert.Empty(t, f.store.ledger(t))
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, categoryID, *transaction.CategoryID)
+		})
+	}
+}
+
+func TestTransactionUseCase_WalletOfAnotherUser(t *testing.T) {
+	// The savings wallet belongs to user 1; user 2 must not see nor change its ledger
+	newLedger := func(t *testing.T) (*ledgerFixture, *db.Transaction) {
+		f := newLedgerFixture(t)
+		return f, f.record(t, f.savings, types.Income, "25")
+	}
+
+	t.Run("record", func(t *testing.T) {
+		f, _ := newLedger(t)
+		_, err := f.useCase.RecordTransaction(f.other.UserID, request.CreateTransactionRequest{
+			WalletID: f.savings.ID,
+			Type:     types.Income,
+			Amount:   money("10"),
+		})
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "wallet not found", err.Error)
+		assert.Len(t, f.store.ledger(t), 1)
+		assert.Equal(t, "125.00", f.balance(t, f.savings))
+	})
+
+	t.Run("list", func(t *testing.T) {
+		f, _ := newLedger(t)
+		_, err := f.useCase.GetWalletTransactions(f.other.UserID, f.savings.ID)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "wallet not found", err.Error)
+	})
+
+	t.Run("support staff read any ledger", func(t *testing.T) {
+		f, _ := newLedger(t)
+		transactions, err := f.useCase.GetWalletTransactions(0, f.savings.ID)
+
+		assert.Nil(t, err)
+		assert.Len(t, transactions, 1)
+	})
+
+	t.Run("lifting the owner does not allow writes", func(t *testing.T) {
+		f, earned := newLedger(t)
+		_, err := f.useCase.RecordTransaction(0, request.CreateTransactionRequest{
+			WalletID: f.savings.ID,
+			Type:     types.Income,
+			Amount:   money("10"),
+		})
+		assert.NotNil(t, err)
+		assert.Equal(t, "wallet not found", err.Error)
+
+		errDelete := f.useCase.DeleteTransaction(0, f.savings.ID, earned.ID)
+		assert.NotNil(t, errDelete)
+		assert.Equal(t, "wallet not found", errDelete.Error)
+		assert.Len(t, f.store.ledger(t), 1)
+		assert.Equal(t, "125.00", f.balance(t, f.savings))
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		f, earned := newLedger(t)
+		err := f.useCase.DeleteTransaction(f.other.UserID, f.savings.ID, earned.ID)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "wallet not found", err.Error)
+		assert.Len(t, f.store.ledger(t), 1)
+		assert.Equal(t, "125.00", f.balance(t, f.savings))
+	})
+}
+
+// failingUpdates is a repository whose Update always fails
+type failingUpdates[T any] struct {
+	contracts.LockingRepository[T, int]
+}
+
+func (failingUpdates[T]) Update(*T) (*T, error) {
+	return nil, errors.New("storage is down")
+}
+
+// failingDeletes is a repository whose Delete always fails
+type failingDeletes[T any] struct {
+	contracts.Repository[T, int]
+}
+
+func (failingDeletes[T]) Delete(int) error {
+	return errors.New("storage is down")
+}
@@ -0,0 +1,155 @@
+package UseCases_test
+
+import (
+	"errors"
+	"testing"
+
+	"Financial/Core/Models/db"
+	request "Financial/Core/Models/dtos/Request"
+	usecases "Financial/Core/UseCases"
+	contracts "Financial/Core/ports"
+	"Financial/Core/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingTransfers rejects every transfer with err, as the database does when a check fails
+type failingTransfers struct {
+	err error
+}
+
+func (f failingTransfers) Transfer(transfer *db.Transfer) (*db.Transfer, error) {
+	return nil, f.err
+}
+
+// newTransferStore stores the users 1 and 2 and the wallets Savings (100), Checking, Card
+// (credit) and Pesos (DOP) of user 1 and Other of user 2 as the wallets 1 to 5
+func newTransferStore(t *testing.T) *memoryStore {
+	t.Helper()
+	store := newMemoryStore()
+	store.addUser(t, "ana")
+	store.addUser(t, "bob")
+	for _, wallet := range []db.Wallet{
+		{ID: 1, Name: "Savings", Type: types.Debit, Balance: money("100"), Currency: "USD", UserID: 1},
+		{ID: 2, Name: "Checking", Type: types.Debit, Balance: money("0"), Currency: "USD", UserID: 1},
+		{ID: 3, Name: "Card", Type: types.Credit, Balance: money("0"), Currency: "USD", UserID: 1},
+		{ID: 4, Name: "Other", Type: types.Debit, Balance: money("0"), Currency: "USD", UserID: 2},
+		{ID: 5, Name: "Pesos", Type: types.Debit, Balance: types.MustParseMoney("0", "DOP"), Currency: "DOP", UserID: 1},
+	} {
+		created, err := store.wallets.Create(&wallet)
+		require.NoError(t, err)
+		require.Equal(t, wallet.ID, created.ID)
+	}
+	return store
+}
+
+func TestTransferUseCase_Transfer(t *testing.T) {
+	tests := []struct {
+		name        string
+		userID      int
+		req         request.CreateTransferRequest
+		repoErr     error
+		expectErr   bool
+		expectedErr string
+	}{
+		{
+			name: "successful transfer",
+			req:  request.CreateTransferRequest{FromWalletID: 1, ToWalletID: 2, Amount: money("60"), Description: "Rent"},
+		},
+		{
+			name: "credit wallet can go below zero",
+			req:  request.CreateTransferRequest{FromWalletID: 3, ToWalletID: 2, Amount: money("500")},
+		},
+		{
+			name:        "transfer would overdraw a debit wallet",
+			req:         request.CreateTransferRequest{FromWalletID: 1, ToWalletID: 2, Amount: money("100.01")},
+			expectErr:   true,
+			expectedErr: "insufficient funds in debit wallet",
+		},
+		{
+			name:        "destination wallet of another user",
+			req:         request.CreateTransferRequest{FromWalletID: 1, ToWalletID: 4, Amount: money("10")},
+			expectErr:   true,
+			expectedErr: "destination wallet not found",
+		},
+		{
+			name:        "transfer out of the wallets of another user",
+			userID:      2,
+			req:         request.CreateTransferRequest{FromWalletID: 1, ToWalletID: 2, Amount: money("10")},
+			expectErr:   true,
+			expectedErr: "source wallet not found",
+		},
+		{
+			name:        "transfer from another user into own wallet",
+			userID:      2,
+			req:         request.CreateTransferRequest{FromWalletID: 1, ToWalletID: 4, Amount: money("10")},
+			expectErr:   true,
+			expectedErr: "source wallet not found",
+		},
+		{
+			name:        "wallets with different currencies",
+			req:         request.CreateTransferRequest{FromWalletID: 1, ToWalletID: 5, Amount: money("10")},
+			expectErr:   true,
+			expectedErr: "wallets have different currencies",
+		},
+		{
+			name:        "same source and destination",
+			req:         request.CreateTransferRequest{FromWalletID: 1, ToWalletID: 1, Amount: money("10")},
+			expectErr:   true,
+			expectedErr: "Source and destination wallets must be different",
+		},
+		{
+			name:        "amount must be positive",
+			req:         request.CreateTransferRequest{FromWalletID: 1, ToWalletID: 2, Amount: money("-5")},
+			expectErr:   true,
+			expectedErr: "Amount must be greater than zero",
+		},
+		{
+			name:        "destination wallet not found",
+			req:         request.CreateTransferRequest{FromWalletID: 1, ToWalletID: 99, Amount: money("10")},
+			expectErr:   true,
+			expectedErr: "destination wallet not found",
+		},
+		{
+			name:        "repository rejects the transfer",
+			req:         request.CreateTransferRequest{FromWalletID: 1, ToWalletID: 2, Amount: money("10")},
+			repoErr:     errors.New("insufficient funds in debit wallet"),
+			expectErr:   true,
+			expectedErr: "error applying transfer: insufficient funds in debit wallet",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newTransferStore(t)
+			var transfers contracts.TransferRepository = store.transfers
+			if tt.repoErr != nil {
+				transfers = failingTransfers{err: tt.repoErr}
+			}
+
+			userID := tt.userID
+			if userID == 0 {
+				userID = 1
+			}
+
+			useCase := usecases.NewTransferUseCase(transfers, store.wallets)
+			transfer, err := useCase.Transfer(userID, tt.req)
+
+			if tt.expectErr {
+				assert.NotNil(t, err)
+				assert.Contains(t, err.Error, tt.expectedErr)
+				assert.Empty(t, store.ledger(t), "no transfer is written")
+				assert.Equal(t, money("100").Minor, store.wallet(t, 1).Balance.Minor)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Len(t, store.ledger(t), 2, "one movement on each wallet")
+			assert.Equal(t, tt.req.FromWalletID, transfer.Debit.WalletID)
+			assert.Equal(t, tt.req.ToWalletID, transfer.Credit.WalletID)
+			assert.Equal(t, tt.req.Amount, transfer.Amount)
+			assert.Equal(t, tt.req.Amount.Minor, store.wallet(t, tt.req.ToWalletID).Balance.Minor)
+		})
+	}
+}
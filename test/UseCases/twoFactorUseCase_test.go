@@ -0,0 +1,163 @@
+package UseCases_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"Financial/Core/Models/db"
+	usecases "Financial/Core/UseCases"
+	contracts "Financial/Core/ports"
+	"Financial/Core/totp"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type twoFactorFixture struct {
+	store   *memoryStore
+	user    *db.User
+	clock   *fakeClock
+	useCase contracts.TwoFactorUseCase
+}
+
+func newTwoFactorFixture(t *testing.T) *twoFactorFixture {
+	store := newMemoryStore()
+	clock := &fakeClock{now: date(2025, time.July, 10)}
+	return &twoFactorFixture{
+		store:   store,
+		user:    store.addUser(t, "ana"),
+		clock:   clock,
+		useCase: usecases.NewTwoFactorUseCase(store.twoFactors, store.users, clock, "MyFinance"),
+	}
+}
+
+// enrollment reads the stored enrollment of the account
+func (f *twoFactorFixture) enrollment(t *testing.T) *db.TwoFactor {
+	t.Helper()
+	enrollment, err := f.store.twoFactors.FindByField("user_id", f.user.ID)
+	require.NoError(t, err)
+	return enrollment
+}
+
+// code is the app code for the secret of the account at the fixture time
+func (f *twoFactorFixture) code(t *testing.T) string {
+	code, err := totp.Code(f.enrollment(t).Secret, totp.Step(f.clock.now))
+	require.NoError(t, err)
+	return code
+}
+
+// enable runs setup and confirmation and returns the recovery codes
+func (f *twoFactorFixture) enable(t *testing.T) []string {
+	_, err := f.useCase.Setup(f.user.ID)
+	require.Nil(t, err)
+	codes, err := f.useCase.Confirm(f.user.ID, f.code(t))
+	require.Nil(t, err)
+	f.clock.now = f.clock.now.Add(totp.Period)
+	return codes.Codes
+}
+
+func TestTwoFactorUseCase_Setup(t *testing.T) {
+	fixture := newTwoFactorFixture(t)
+
+	setup, err := fixture.useCase.Setup(fixture.user.ID)
+
+	require.Nil(t, err)
+	assert.Equal(t, fixture.enrollment(t).Secret, setup.Secret)
+	assert.True(t, strings.HasPrefix(setup.URI, "otpauth://totp/MyFinance:ana@example.com?"))
+	enabled, _ := fixture.useCase.Enabled(fixture.user.ID)
+	assert.False(t, enabled, "not enforced until confirmed")
+
+	again, _ := fixture.useCase.Setup(fixture.user.ID)
+	assert.NotEqual(t, setup.Secret, again.Secret, "an unconfirmed setup can be restarted")
+	enrollments, _ := fixture.store.twoFactors.GetAll()
+	assert.Len(t, enrollments, 1)
+}
+
+func TestTwoFactorUseCase_Confirm(t *testing.T) {
+	t.Run("enables with a valid code", func(t *testing.T) {
+		fixture := newTwoFactorFixture(t)
+		codes := fixture.enable(t)
+
+		enabled, err := fixture.useCase.Enabled(fixture.user.ID)
+		assert.NoError(t, err)
+		assert.True(t, enabled)
+		assert.Len(t, codes, 10)
+		assert.Len(t, fixture.enrollment(t).RecoveryCodes, 10)
+		assert.NotContains(t, fixture.enrollment(t).RecoveryCodes, codes[0], "recovery codes are stored hashed")
+
+		_, err2 := fixture.useCase.Setup(fixture.user.ID)
+		assert.Equal(t, "two-factor authentication is already enabled", err2.Error)
+	})
+
+	t.Run("wrong code", func(t *testing.T) {
+		fixture := newTwoFactorFixture(t)
+		fixture.useCase.Setup(fixture.user.ID)
+
+		_, err := fixture.useCase.Confirm(fixture.user.ID, "000000")
+
+		assert.Equal(t, "invalid two-factor code", err.Error)
+		assert.Nil(t, fixture.enrollment(t).ConfirmedAt)
+	})
+
+	t.Run("without setup", func(t *testing.T) {
+		fixture := newTwoFactorFixture(t)
+
+		_, err := fixture.useCase.Confirm(fixture.user.ID, "123456")
+
+		assert.Equal(t, "no two-factor setup in progress", err.Error)
+	})
+}
+
+func TestTwoFactorUseCase_Verify(t *testing.T) {
+	t.Run("app code works once", func(t *testing.T) {
+		fixture := newTwoFactorFixture(t)
+		fixture.enable(t)
+		code := fixture.code(t)
+
+		assert.Nil(t, fixture.useCase.Verify(fixture.user.ID, code))
+		err := fixture.useCase.Verify(fixture.user.ID, code)
+		assert.Equal(t, "invalid two-factor code", err.Error)
+	})
+
+	t.Run("the confirmation code can't be replayed", func(t *testing.T) {
+		fixture := newTwoFactorFixture(t)
+		fixture.useCase.Setup(fixture.user.ID)
+		code := fixture.code(t)
+		fixture.useCase.Confirm(fixture.user.ID, code)
+
+		err := fixture.useCase.Verify(fixture.user.ID, code)
+
+		assert.Equal(t, "invalid two-factor code", err.Error)
+	})
+
+	t.Run("recovery code is consumed", func(t *testing.T) {
+		fixture := newTwoFactorFixture(t)
+		codes := fixture.enable(t)
+
+		assert.Nil(t, fixture.useCase.Verify(fixture.user.ID, strings.ToUpper(codes[3])))
+		assert.Len(t, fixture.enrollment(t).RecoveryCodes, 9)
+		err := fixture.useCase.Verify(fixture.user.ID, codes[3])
+		assert.Equal(t, "invalid two-factor code", err.Error)
+	})
+
+	t.Run("not enabled", func(t *testing.T) {
+		fixture := newTwoFactorFixture(t)
+
+		err := fixture.useCase.Verify(fixture.user.ID, "123456")
+
+		assert.Equal(t, "two-factor authentication is not enabled", err.Error)
+	})
+}
+
+func TestTwoFactorUseCase_Disable(t *testing.T) {
+	fixture := newTwoFactorFixture(t)
+	fixture.enable(t)
+
+	err := fixture.useCase.Disable(fixture.user.ID, "000000")
+	assert.Equal(t, "invalid two-factor code", err.Error)
+
+	assert.Nil(t, fixture.useCase.Disable(fixture.user.ID, fixture.code(t)))
+	enabled, _ := fixture.useCase.Enabled(fixture.user.ID)
+	assert.False(t, enabled)
+}
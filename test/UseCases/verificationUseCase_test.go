@@ -0,0 +1,145 @@
+package UseCases_test
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"Financial/Core/Models/db"
+	usecases "Financial/Core/UseCases"
+	contracts "Financial/Core/ports"
+	"Financial/Core/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeMailer keeps the messages it is asked to send
+type fakeMailer struct {
+	sent []contracts.MailMessage
+}
+
+func (m *fakeMailer) Send(message contracts.MailMessage) error {
+	m.sent = append(m.sent, message)
+	return nil
+}
+
+var tokenInLink = regexp.MustCompile(`\?token=(\S+)`)
+
+// sentToken extracts the token from the last verification email
+func sentToken(t *testing.T, mailer *fakeMailer) string {
+	require.NotEmpty(t, mailer.sent)
+	match := tokenInLink.FindStringSubmatch(mailer.sent[len(mailer.sent)-1].Body)
+	require.NotNil(t, match)
+	token, err := url.QueryUnescape(match[1])
+	require.NoError(t, err)
+	return token
+}
+
+type verificationFixture struct {
+	store   *memoryStore
+	user    *db.User
+	mailer  *fakeMailer
+	clock   *fakeClock
+	useCase contracts.VerificationUseCase
+}
+
+// newVerificationFixture stores one account named ana with the given status
+func newVerificationFixture(t *testing.T, status types.AccountStatus) *verificationFixture {
+	store := newMemoryStore()
+	f := &verificationFixture{
+		store:  store,
+		user:   store.addUser(t, "ana", func(user *db.User) { user.Status = status }),
+		mailer: &fakeMailer{},
+		clock:  &fakeClock{now: date(2025, time.July, 8)},
+	}
+	f.useCase = usecases.NewVerificationUseCase(store.users, f.mailer, f.clock, []byte("test-secret"), "http://localhost:8080/api/account/verify", 48*time.Hour)
+	return f
+}
+
+// status is the stored status of the account
+func (f *verificationFixture) status(t *testing.T) types.AccountStatus {
+	return f.store.user(t, f.user.ID).Status
+}
+
+func TestVerificationUseCase_VerifyEmail(t *testing.T) {
+	t.Run("activates the account once", func(t *testing.T) {
+		f := newVerificationFixture(t, types.Inactive)
+
+		require.Nil(t, f.useCase.SendVerification(f.user.ID))
+		assert.Equal(t, "ana@example.com", f.mailer.sent[0].To)
+		assert.Contains(t, f.mailer.sent[0].Body, "http://localhost:8080/api/account/verify?token=")
+		token := sentToken(t, f.mailer)
+
+		assert.Nil(t, f.useCase.VerifyEmail(token))
+		assert.Equal(t, types.Active, f.status(t))
+
+		err := f.useCase.VerifyEmail(token)
+		assert.Equal(t, "invalid or expired verification token", err.Error)
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		f := newVerificationFixture(t, types.Inactive)
+		f.useCase.SendVerification(f.user.ID)
+
+		f.clock.now = f.clock.now.Add(49 * time.Hour)
+
+		assert.NotNil(t, f.useCase.VerifyEmail(sentToken(t, f.mailer)))
+		assert.Equal(t, types.Inactive, f.status(t))
+	})
+
+	t.Run("tampered token", func(t *testing.T) {
+		f := newVerificationFixture(t, types.Inactive)
+		f.useCase.SendVerification(f.user.ID)
+		token := sentToken(t, f.mailer)
+
+		// Pushing the expiry forward breaks the signature
+		parts := strings.Split(token, ".")
+		parts[1] = "9999999999"
+
+		assert.NotNil(t, f.useCase.VerifyEmail(strings.Join(parts, ".")))
+		assert.NotNil(t, f.useCase.VerifyEmail("garbage"))
+		assert.Equal(t, types.Inactive, f.status(t))
+	})
+
+	t.Run("email changed since the link was sent", func(t *testing.T) {
+		f := newVerificationFixture(t, types.Inactive)
+		f.useCase.SendVerification(f.user.ID)
+		user := f.store.user(t, f.user.ID)
+		user.Email = "other@example.com"
+		_, err := f.store.users.Update(user)
+		require.NoError(t, err)
+
+		assert.NotNil(t, f.useCase.VerifyEmail(sentToken(t, f.mailer)))
+	})
+
+	t.Run("suspended accounts are not activated", func(t *testing.T) {
+		f := newVerificationFixture(t, types.Inactive)
+		f.useCase.SendVerification(f.user.ID)
+		f.store.setStatus(t, f.user.ID, types.Suspend)
+
+		assert.NotNil(t, f.useCase.VerifyEmail(sentToken(t, f.mailer)))
+		assert.Equal(t, types.Suspend, f.status(t))
+	})
+}
+
+func TestVerificationUseCase_SendVerification_AlreadyVerified(t *testing.T) {
+	f := newVerificationFixture(t, types.Active)
+
+	err := f.useCase.SendVerification(f.user.ID)
+
+	assert.Equal(t, "account already verified", err.Error)
+	assert.Empty(t, f.mailer.sent)
+}
+
+func TestVerificationUseCase_ResendVerification(t *testing.T) {
+	f := newVerificationFixture(t, types.Inactive)
+
+	f.useCase.ResendVerification("nobody@example.com")
+	assert.Empty(t, f.mailer.sent)
+
+	f.useCase.ResendVerification("ana@example.com")
+	assert.Len(t, f.mailer.sent, 1)
+}
@@ -3,6 +3,7 @@ package UseCases_test
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"Financial/Core/Models/db"
 	request "Financial/Core/Models/dtos/Request"
@@ -14,16 +15,24 @@ import (
 	"Financial/Core/types"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
-// errorProneMockRepository is a custom mock that will return an invalid type for Query
+// errorProneMockRepository is a custom mock whose Query always fails
 type errorProneMockRepository struct {
 	mocks.MockRepository[db.Wallet, int]
 }
 
-func (m *errorProneMockRepository) Query(query string, args contracts.QueryOptions) (interface{}, error) {
-	// Return an invalid type that will cause the type assertion to fail
-	return []struct{}{}, nil
+func (m *errorProneMockRepository) Query(query string, args contracts.QueryOptions) (contracts.Page[db.Wallet], error) {
+	return contracts.Page[db.Wallet]{}, errors.New("query failed")
+}
+
+// walletUnitOfWork runs the writes of the wallet use case on wallets, with a ledger nobody inspects
+func walletUnitOfWork(wallets contracts.LockingRepository[db.Wallet, int]) *mocks.MockUnitOfWork {
+	return mocks.NewMockUnitOfWork(contracts.UnitOfWorkRepositories{
+		Wallets:      wallets,
+		Transactions: mocks.NewMockRepository[db.Transaction, int](),
+	})
 }
 
 func TestWalletUseCase_CreateWallet(t *testing.T) {
@@ -33,7 +42,7 @@ func TestWalletUseCase_CreateWallet(t *testing.T) {
 			Req: request.CreateWalletRequest{
 				Name:       "Savings",
 				WalletType: "savings",
-				Balance:    1000.0,
+				Balance:    money("1000"),
 				UserID:     1,
 			},
 			SetupMock: func(mock *mocks.MockRepository[db.Wallet, int]) {
@@ -42,7 +51,7 @@ func TestWalletUseCase_CreateWallet(t *testing.T) {
 					ID:      1,
 					Name:    "Savings",
 					Type:    types.Debit,
-					Balance: 1000.0,
+					Balance: money("1000"),
 					UserID:  1,
 				}, nil)
 			},
@@ -50,7 +59,7 @@ func TestWalletUseCase_CreateWallet(t *testing.T) {
 				assert.NoError(t, err)
 				assert.Equal(t, "Savings", wallet.Name)
 				assert.Equal(t, types.Debit, wallet.Type)
-				assert.Equal(t, 1000.0, wallet.Balance)
+				assert.Equal(t, money("1000"), wallet.Balance)
 				assert.Equal(t, 1, wallet.UserID)
 			},
 		},
@@ -59,7 +68,7 @@ func TestWalletUseCase_CreateWallet(t *testing.T) {
 			Req: request.CreateWalletRequest{
 				Name:       "",
 				WalletType: "savings",
-				Balance:    1000.0,
+				Balance:    money("1000"),
 				UserID:     1,
 			},
 			ExpectErr:   true,
@@ -70,7 +79,7 @@ func TestWalletUseCase_CreateWallet(t *testing.T) {
 			Req: request.CreateWalletRequest{
 				Name:       "  ",
 				WalletType: "savings",
-				Balance:    1000.0,
+				Balance:    money("1000"),
 				UserID:     1,
 			},
 			ExpectErr:   true,
@@ -81,7 +90,7 @@ func TestWalletUseCase_CreateWallet(t *testing.T) {
 			Req: request.CreateWalletRequest{
 				Name:       "Savings",
 				WalletType: types.Debit,
-				Balance:    -100.0,
+				Balance:    money("-100"),
 				UserID:     1,
 			},
 			ExpectErr:   true,
@@ -92,18 +101,30 @@ func TestWalletUseCase_CreateWallet(t *testing.T) {
 			Req: request.CreateWalletRequest{
 				Name:       "Savings",
 				WalletType: "savings",
-				Balance:    1000.0,
+				Balance:    money("1000"),
 				UserID:     0,
 			},
 			ExpectErr:   true,
 			ExpectedErr: errors.New("invalid user ID"),
 		},
+		{
+			Name: "invalid currency code",
+			Req: request.CreateWalletRequest{
+				Name:       "Savings",
+				WalletType: types.Debit,
+				Balance:    money("1000"),
+				Currency:   "US$",
+				UserID:     1,
+			},
+			ExpectErr:   true,
+			ExpectedErr: errors.New("currency must be a 3-letter ISO 4217 code"),
+		},
 		{
 			Name: "duplicate wallet name for user",
 			Req: request.CreateWalletRequest{
 				Name:       "Savings",
 				WalletType: "savings",
-				Balance:    1000.0,
+				Balance:    money("1000"),
 				UserID:     1,
 			},
 			SetupMock: func(mock *mocks.MockRepository[db.Wallet, int]) {
@@ -111,7 +132,7 @@ func TestWalletUseCase_CreateWallet(t *testing.T) {
 					ID:      1,
 					Name:    "Savings",
 					Type:    "savings",
-					Balance: 1000.0,
+					Balance: money("1000"),
 					UserID:  1,
 				}}, nil)
 			},
@@ -123,7 +144,7 @@ func TestWalletUseCase_CreateWallet(t *testing.T) {
 			Req: request.CreateWalletRequest{
 				Name:       "Savings",
 				WalletType: types.Debit,
-				Balance:    1000.0,
+				Balance:    money("1000"),
 				UserID:     1,
 			},
 			ExpectErr:   true,
@@ -141,11 +162,13 @@ func TestWalletUseCase_CreateWallet(t *testing.T) {
 				tt.SetupMock(repo)
 			}
 
-			useCase := usecases.NewWalletUseCase(repo)
+			useCase := usecases.NewWalletUseCase(repo, walletUnitOfWork(repo), nil)
 			wallet, err := useCase.CreateWallet(tt.Req)
 
 			if tt.ExpectErr {
-				assert.Error(t, errors.New(err.Error))
+				if !assert.NotNil(t, err) {
+					return
+				}
 				if tt.ExpectedErr != nil {
 					assert.Contains(t, err.Error, tt.ExpectedErr.Error())
 				}
@@ -153,7 +176,11 @@ func TestWalletUseCase_CreateWallet(t *testing.T) {
 			}
 
 			if tt.Verify != nil {
-				tt.Verify(t, wallet, errors.New(err.Error))
+				var verifyErr error
+				if err != nil {
+					verifyErr = errors.New(err.Error)
+				}
+				tt.Verify(t, wallet, verifyErr)
 			}
 		})
 	}
@@ -177,22 +204,29 @@ func TestWalletUseCase_UpdateWallet(t *testing.T) {
 				WalletID:   1,
 				Name:       "Updated Savings",
 				WalletType: types.WalletTypePtr(types.Debit),
-				Balance:    float64Ptr(2000.0),
+				Balance:    types.MoneyPtr(money("2000")),
 			},
 			SetupMock: func(mock *mocks.MockRepository[db.Wallet, int]) {
 				mock.SetResponse("FindByField", &db.Wallet{
 					ID:      1,
 					Name:    "Savings",
 					Type:    types.Debit,
-					Balance: 1000.0,
+					Balance: money("1000"),
 					UserID:  1,
 				}, nil)
 				mock.SetResponse("GetAll", []db.Wallet{}, nil)
+				mock.SetResponse("GetForUpdate", &db.Wallet{
+					ID:      1,
+					Name:    "Savings",
+					Type:    types.Debit,
+					Balance: money("1000"),
+					UserID:  1,
+				}, nil)
 				mock.SetResponse("Update", &db.Wallet{
 					ID:      1,
 					Name:    "Updated Savings",
 					Type:    types.Credit,
-					Balance: 2000.0,
+					Balance: money("2000"),
 					UserID:  1,
 				}, nil)
 			},
@@ -200,7 +234,7 @@ func TestWalletUseCase_UpdateWallet(t *testing.T) {
 				assert.NoError(t, err)
 				assert.Equal(t, "Updated Savings", wallet.Name)
 				assert.Equal(t, types.Credit, wallet.Type)
-				assert.Equal(t, 2000.0, wallet.Balance)
+				assert.Equal(t, money("2000"), wallet.Balance)
 			},
 		},
 		{
@@ -215,6 +249,24 @@ func TestWalletUseCase_UpdateWallet(t *testing.T) {
 			ExpectErr:   true,
 			ExpectedErr: errors.New("wallet not found"),
 		},
+		{
+			Name: "wallet of another user",
+			Req: request.UpdateWalletRequest{
+				WalletID: 1,
+				Name:     "Mine now",
+			},
+			SetupMock: func(mock *mocks.MockRepository[db.Wallet, int]) {
+				mock.SetResponse("FindByField", &db.Wallet{
+					ID:      1,
+					Name:    "Savings",
+					Type:    types.Debit,
+					Balance: money("1000"),
+					UserID:  2,
+				}, nil)
+			},
+			ExpectErr:   true,
+			ExpectedErr: errors.New("wallet not found"),
+		},
 		{
 			Name: "duplicate wallet name",
 			Req: request.UpdateWalletRequest{
@@ -226,14 +278,14 @@ func TestWalletUseCase_UpdateWallet(t *testing.T) {
 					ID:      1,
 					Name:    "Savings",
 					Type:    "savings",
-					Balance: 1000.0,
+					Balance: money("1000"),
 					UserID:  1,
 				}, nil)
 				mock.SetResponse("GetAll", []db.Wallet{{
 					ID:      2,
 					Name:    "Existing Wallet",
 					Type:    types.Debit,
-					Balance: 500.0,
+					Balance: money("500"),
 					UserID:  1,
 				}}, nil)
 			},
@@ -244,14 +296,14 @@ func TestWalletUseCase_UpdateWallet(t *testing.T) {
 			Name: "negative balance",
 			Req: request.UpdateWalletRequest{
 				WalletID: 1,
-				Balance:  float64Ptr(-100.0),
+				Balance:  types.MoneyPtr(money("-100")),
 			},
 			SetupMock: func(mock *mocks.MockRepository[db.Wallet, int]) {
 				mock.SetResponse("FindByField", &db.Wallet{
 					ID:      1,
 					Name:    "Savings",
 					Type:    "savings",
-					Balance: 1000.0,
+					Balance: money("1000"),
 					UserID:  1,
 				}, nil)
 			},
@@ -292,7 +344,7 @@ func TestWalletUseCase_UpdateWallet(t *testing.T) {
 					ID:      1,
 					Name:    "Old Name",
 					Type:    "Debit",
-					Balance: 1000.0,
+					Balance: money("1000"),
 					UserID:  1,
 				}, nil)
 				// Mock para GetAll que devuelve un error
@@ -313,9 +365,35 @@ func TestWalletUseCase_UpdateWallet(t *testing.T) {
 					ID:      1,
 					Name:    "Old Name",
 					Type:    "Debit",
-					Balance: 1000.0,
+					Balance: money("1000"),
+					UserID:  1,
+				}, nil)
+				mr.SetResponse("GetForUpdate", &db.Wallet{
+					ID:      1,
+					Name:    "Old Name",
+					Type:    "Debit",
+					Balance: money("1000"),
+					UserID:  1,
+				}, nil)
+			},
+		},
+		{
+			Name: "wallet deleted before it is locked",
+			Req: request.UpdateWalletRequest{
+				WalletID: 1,
+				Balance:  types.MoneyPtr(money("2000")),
+			},
+			ExpectErr:   true,
+			ExpectedErr: errors.New("wallet not found"),
+			SetupMock: func(mr *mocks.MockRepository[db.Wallet, int]) {
+				mr.SetResponse("FindByField", &db.Wallet{
+					ID:      1,
+					Name:    "Savings",
+					Type:    types.Debit,
+					Balance: money("1000"),
 					UserID:  1,
 				}, nil)
+				mr.SetResponse("GetForUpdate", nil, types.ErrNotFound)
 			},
 		},
 	}
@@ -327,11 +405,13 @@ func TestWalletUseCase_UpdateWallet(t *testing.T) {
 				tt.SetupMock(repo)
 			}
 
-			useCase := usecases.NewWalletUseCase(repo)
-			wallet, err := useCase.UpdateWallet(tt.Req)
+			useCase := usecases.NewWalletUseCase(repo, walletUnitOfWork(repo), nil)
+			wallet, err := useCase.UpdateWallet(1, tt.Req)
 
 			if tt.ExpectErr {
-				assert.Error(t, errors.New(err.Error))
+				if !assert.NotNil(t, err) {
+					return
+				}
 				if tt.ExpectedErr != nil {
 					assert.Contains(t, err.Error, tt.ExpectedErr.Error())
 				}
@@ -339,12 +419,126 @@ func TestWalletUseCase_UpdateWallet(t *testing.T) {
 			}
 
 			if tt.Verify != nil {
-				tt.Verify(t, wallet, errors.New(err.Error))
+				var verifyErr error
+				if err != nil {
+					verifyErr = errors.New(err.Error)
+				}
+				tt.Verify(t, wallet, verifyErr)
 			}
 		})
 	}
 }
 
+func TestWalletUseCase_UpdateWallet_AdjustsTheLockedBalance(t *testing.T) {
+	store := newMemoryStore()
+	user := store.addUser(t, "ana")
+	wallet := store.addWallet(t, user.ID, "Savings", "100")
+
+	// A transaction lands between the ownership check and the unit of work
+	unitOfWork := unitOfWorkFunc(func(fn func(repos contracts.UnitOfWorkRepositories) error) error {
+		_, err := store.transactions.Create(&db.Transaction{WalletID: wallet.ID, Amount: money("50"), Type: types.Income, Description: "Salary"})
+		require.NoError(t, err)
+		current := store.wallet(t, wallet.ID)
+		current.Balance = types.MustParseMoney("150", "USD")
+		_, err = store.wallets.Update(current)
+		require.NoError(t, err)
+		return store.unitOfWork.Do(fn)
+	})
+	useCase := usecases.NewWalletUseCase(store.wallets, unitOfWork, nil)
+
+	updated, err := useCase.UpdateWallet(user.ID, request.UpdateWalletRequest{WalletID: wallet.ID, Balance: types.MoneyPtr(types.MustParseMoney("200", "USD"))})
+
+	require.Nil(t, err)
+	assert.Equal(t, "200.00", updated.Balance.Decimal())
+	ledger := store.ledger(t)
+	require.Len(t, ledger, 2)
+	assert.Equal(t, "Balance adjustment", ledger[1].Description)
+	assert.Equal(t, "50.00", ledger[1].Amount.Decimal(), "the adjustment starts from the balance it overwrites")
+}
+
+// countingWalletUpdates counts the wallet updates of a unit of work
+type countingWalletUpdates struct {
+	contracts.LockingRepository[db.Wallet, int]
+	updates int
+}
+
+func (r *countingWalletUpdates) Update(wallet *db.Wallet) (*db.Wallet, error) {
+	r.updates++
+	return r.LockingRepository.Update(wallet)
+}
+
+func TestWalletUseCase_UpdateWallet_ComparesValues(t *testing.T) {
+	tests := []struct {
+		name        string
+		req         request.UpdateWalletRequest
+		wantErr     string
+		wantUpdates int
+		wantBalance string
+		wantLedger  []string
+	}{
+		{
+			name:        "same balance without a currency and same type",
+			req:         request.UpdateWalletRequest{Balance: types.MoneyPtr(money("100")), WalletType: types.WalletTypePtr(types.Debit)},
+			wantBalance: "100.00",
+		},
+		{
+			name:        "new balance without a currency",
+			req:         request.UpdateWalletRequest{Balance: types.MoneyPtr(money("120"))},
+			wantUpdates: 1,
+			wantBalance: "120.00",
+			wantLedger:  []string{"20.00"},
+		},
+		{
+			name:        "balance in another currency",
+			req:         request.UpdateWalletRequest{Balance: types.MoneyPtr(types.MustParseMoney("120", "EUR"))},
+			wantErr:     types.ErrCurrencyMismatch.Error(),
+			wantBalance: "100.00",
+		},
+		{
+			name:        "new type",
+			req:         request.UpdateWalletRequest{WalletType: types.WalletTypePtr(types.Credit)},
+			wantUpdates: 1,
+			wantBalance: "100.00",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMemoryStore()
+			user := store.addUser(t, "ana")
+			wallet := store.addWallet(t, user.ID, "Savings", "100")
+			counter := &countingWalletUpdates{}
+			unitOfWork := unitOfWorkFunc(func(fn func(repos contracts.UnitOfWorkRepositories) error) error {
+				return store.unitOfWork.Do(func(repos contracts.UnitOfWorkRepositories) error {
+					counter.LockingRepository = repos.Wallets
+					repos.Wallets = counter
+					return fn(repos)
+				})
+			})
+			useCase := usecases.NewWalletUseCase(store.wallets, unitOfWork, nil)
+
+			tt.req.WalletID = wallet.ID
+			_, err := useCase.UpdateWallet(user.ID, tt.req)
+
+			if tt.wantErr != "" {
+				if assert.NotNil(t, err) {
+					assert.Contains(t, err.Error, tt.wantErr)
+				}
+			} else {
+				assert.Nil(t, err)
+			}
+			assert.Equal(t, tt.wantUpdates, counter.updates)
+			stored := store.wallet(t, wallet.ID)
+			assert.Equal(t, tt.wantBalance, stored.Balance.Decimal())
+			var ledger []string
+			for _, entry := range store.ledger(t) {
+				ledger = append(ledger, entry.Amount.Decimal())
+			}
+			assert.Equal(t, tt.wantLedger, ledger)
+		})
+	}
+}
+
 func TestWalletUseCase_DeleteWallet(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -361,12 +555,27 @@ func TestWalletUseCase_DeleteWallet(t *testing.T) {
 					ID:      1,
 					Name:    "Savings",
 					Type:    "savings",
-					Balance: 1000.0,
+					Balance: money("1000"),
 					UserID:  1,
 				}, nil)
 				mock.SetResponse("Delete", nil, nil)
 			},
 		},
+		{
+			name:     "wallet of another user",
+			walletID: 1,
+			setupMock: func(mock *mocks.MockRepository[db.Wallet, int]) {
+				mock.SetResponse("GetByID", &db.Wallet{
+					ID:      1,
+					Name:    "Savings",
+					Type:    "savings",
+					Balance: money("1000"),
+					UserID:  2,
+				}, nil)
+			},
+			expectErr:   true,
+			expectedErr: errors.New("wallet not found"),
+		},
 		{
 			name:        "invalid wallet ID",
 			walletID:    0,
@@ -409,8 +618,8 @@ func TestWalletUseCase_DeleteWallet(t *testing.T) {
 				tt.setupMock(repo)
 			}
 
-			useCase := usecases.NewWalletUseCase(repo)
-			err := useCase.DeleteWallet(tt.walletID)
+			useCase := usecases.NewWalletUseCase(repo, walletUnitOfWork(repo), nil)
+			err := useCase.DeleteWallet(1, tt.walletID)
 
 			if tt.expectErr {
 				assert.Error(t, err)
@@ -424,31 +633,26 @@ func TestWalletUseCase_DeleteWallet(t *testing.T) {
 	}
 }
 
-func float64Ptr(f float64) *float64 {
-	return &f
-}
-
 func TestWalletUseCase_GetUserWallet(t *testing.T) {
-	// Test case for type assertion error
-	t.Run("error - unexpected type from repository", func(t *testing.T) {
+	// Test case for a failing query
+	t.Run("error - repository query fails", func(t *testing.T) {
 		// Crear el mock del repositorio
 		mockRepo := &errorProneMockRepository{
 			MockRepository: *mocks.NewMockRepository[db.Wallet, int](),
 		}
 
-		// Configurar el mock para devolver un tipo incorrecto
-		mockRepo.SetResponse("Query", []struct{}{}, assert.AnError)
-
 		// Crear el caso de uso con el mock
-		uc := usecases.NewWalletUseCase(mockRepo)
+		uc := usecases.NewWalletUseCase(mockRepo, walletUnitOfWork(mockRepo), nil)
 
 		// Llamar al método bajo prueba
-		result, err := uc.GetUserWallet(1, "test@example.com")
+		result, err := uc.GetUserWallet(1, "test@example.com", "", time.Time{})
 
 		// Verificar los resultados
-		assert.Error(t, errors.New(err.Error), "Expected an error due to type assertion failure")
+		if !assert.NotNil(t, err, "Expected an error from the failing query") {
+			return
+		}
 		assert.Nil(t, result, "Result should be nil on error")
-		assert.Contains(t, err.Error, "unexpected type returned from repository", "Error message should indicate type assertion failure")
+		assert.Contains(t, err.Error, "query failed", "Error message should carry the query error")
 	})
 
 	// Setup other test cases
@@ -468,19 +672,21 @@ func TestWalletUseCase_GetUserWallet(t *testing.T) {
 				// Mock the Query method to return wallets for the user
 				mockWallets := []db.Wallet{
 					{
-						ID:      1,
-						Name:    "Savings",
-						Type:    types.Credit,
-						Balance: 1000.50,
+						ID:       1,
+						Name:     "Savings",
+						Type:     types.Credit,
+						Balance:  money("1000.50"),
+						Currency: "USD",
 						User: &db.User{
 							Email: "test@example.com",
 						},
 					},
 					{
-						ID:      2,
-						Name:    "Checking",
-						Type:    types.Debit,
-						Balance: 500.75,
+						ID:       2,
+						Name:     "Checking",
+						Type:     types.Debit,
+						Balance:  money("500.75"),
+						Currency: "USD",
 						User: &db.User{
 							Email: "test@example.com",
 						},
@@ -491,13 +697,9 @@ func TestWalletUseCase_GetUserWallet(t *testing.T) {
 			expectError: false,
 			expectWallet: &reponse.UserWalletResponse{
 				Email: "test@example.com",
-				Wallets: []struct {
-					Name    string           `json:"name"`
-					Type    types.WalletType `json:"type"`
-					Balance float64          `json:"balance"`
-				}{
-					{Name: "Savings", Type: types.Credit, Balance: 1000.50},
-					{Name: "Checking", Type: types.Debit, Balance: 500.75},
+				Wallets: []reponse.WalletSummary{
+					{Name: "Savings", Type: types.Credit, Balance: types.MustParseMoney("1000.50", "USD")},
+					{Name: "Checking", Type: types.Debit, Balance: types.MustParseMoney("500.75", "USD")},
 				},
 			},
 		},
@@ -511,12 +713,8 @@ func TestWalletUseCase_GetUserWallet(t *testing.T) {
 			},
 			expectError: false,
 			expectWallet: &reponse.UserWalletResponse{
-				Email: "test@example.com",
-				Wallets: []struct {
-					Name    string           `json:"name"`
-					Type    types.WalletType `json:"type"`
-					Balance float64          `json:"balance"`
-				}{},
+				Email:   "test@example.com",
+				Wallets: []reponse.WalletSummary{},
 			},
 		},
 		{
@@ -542,16 +740,18 @@ func TestWalletUseCase_GetUserWallet(t *testing.T) {
 			}
 
 			// Create the use case with the mock repository
-			uc := usecases.NewWalletUseCase(mockRepo)
+			uc := usecases.NewWalletUseCase(mockRepo, walletUnitOfWork(mockRepo), nil)
 
 			// Call the method being tested
-			result, err := uc.GetUserWallet(tt.userID, tt.email)
+			result, err := uc.GetUserWallet(tt.userID, tt.email, "", time.Time{})
 
 			// Assert the results
 			if tt.expectError {
-				assert.Error(t, errors.New(err.Error), "Expected an error")
+				assert.NotNil(t, err, "Expected an error")
 			} else {
-				assert.NoError(t, errors.New(err.Error), "Unexpected error")
+				if !assert.Nil(t, err, "Unexpected error") {
+					return
+				}
 				assert.Equal(t, tt.expectWallet.Email, result.Email, "Email should match")
 				assert.Len(t, result.Wallets, len(tt.expectWallet.Wallets), "Number of wallets should match")
 
@@ -570,3 +770,164 @@ func TestWalletUseCase_GetUserWallet(t *testing.T) {
 		})
 	}
 }
+
+// fakeRateProvider answers with fixed rates to a reporting currency and records the requested dates
+type fakeRateProvider struct {
+	rates map[string]types.Rate
+	dates []time.Time
+}
+
+func (f *fakeRateProvider) GetRate(from string, to string, at time.Time) (*db.ExchangeRate, error) {
+	f.dates = append(f.dates, at)
+	rate, ok := f.rates[from+"/"+to]
+	if !ok {
+		return nil, types.ErrExchangeRateNotFound
+	}
+	return &db.ExchangeRate{BaseCurrency: from, QuoteCurrency: to, Rate: rate, EffectiveDate: at}, nil
+}
+
+func TestWalletUseCase_GetUserWallet_ReportingCurrency(t *testing.T) {
+	user := &db.User{Email: "test@example.com"}
+	wallets := []db.Wallet{
+		{ID: 1, Name: "Dollars", Type: types.Debit, Balance: money("100"), Currency: "USD", User: user},
+		{ID: 2, Name: "Pesos", Type: types.Debit, Balance: money("1175.50"), Currency: "DOP", User: user},
+		{ID: 3, Name: "Card", Type: types.Credit, Balance: money("-25.25"), Currency: "USD", User: user},
+	}
+	asOf := time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	t.Run("totals per currency without conversion", func(t *testing.T) {
+		repo := mocks.NewMockRepository[db.Wallet, int]()
+		repo.SetResponse("Query", wallets, nil)
+
+		uc := usecases.NewWalletUseCase(repo, walletUnitOfWork(repo), nil)
+		result, err := uc.GetUserWallet(1, "test@example.com", "", time.Time{})
+
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]types.Money{
+			"USD": types.MustParseMoney("74.75", "USD"),
+			"DOP": types.MustParseMoney("1175.50", "DOP"),
+		}, result.Totals)
+		assert.Nil(t, result.Total)
+		assert.Nil(t, result.Wallets[0].ConvertedBalance)
+	})
+
+	t.Run("converts every wallet to the reporting currency", func(t *testing.T) {
+		repo := mocks.NewMockRepository[db.Wallet, int]()
+		repo.SetResponse("Query", wallets, nil)
+		rates := &fakeRateProvider{rates: map[string]types.Rate{
+			"USD/DOP": types.MustParseRate("58.75"),
+			"DOP/DOP": types.IdentityRate(),
+		}}
+
+		uc := usecases.NewWalletUseCase(repo, walletUnitOfWork(repo), rates)
+		result, err := uc.GetUserWallet(1, "test@example.com", "dop", asOf)
+
+		if !assert.Nil(t, err) {
+			return
+		}
+		assert.Equal(t, "DOP", result.ReportingCurrency)
+		assert.Equal(t, types.MustParseMoney("5875", "DOP"), *result.Wallets[0].ConvertedBalance)
+		assert.Equal(t, types.MustParseMoney("1175.50", "DOP"), *result.Wallets[1].ConvertedBalance)
+		assert.Equal(t, types.MustParseMoney("-1483.44", "DOP"), *result.Wallets[2].ConvertedBalance)
+		assert.Equal(t, types.MustParseMoney("5567.06", "DOP"), *result.Total)
+		assert.Equal(t, asOf, *result.RatesAsOf)
+		assert.Len(t, rates.dates, 2, "each currency is looked up once")
+		for _, date := range rates.dates {
+			assert.Equal(t, asOf, date)
+		}
+	})
+
+	t.Run("missing exchange rate", func(t *testing.T) {
+		repo := mocks.NewMockRepository[db.Wallet, int]()
+		repo.SetResponse("Query", wallets, nil)
+
+		uc := usecases.NewWalletUseCase(repo, walletUnitOfWork(repo), &fakeRateProvider{})
+		_, err := uc.GetUserWallet(1, "test@example.com", "EUR", asOf)
+
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error, "error converting USD to EUR")
+	})
+
+	t.Run("conversion without a rate provider", func(t *testing.T) {
+		repo := mocks.NewMockRepository[db.Wallet, int]()
+		uc := usecases.NewWalletUseCase(repo, walletUnitOfWork(repo), nil)
+		_, err := uc.GetUserWallet(1, "test@example.com", "DOP", asOf)
+
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error, "currency conversion is not available")
+	})
+}
+
+// unitOfWorkFunc adapts a function to contracts.UnitOfWork
+type unitOfWorkFunc func(fn func(repos contracts.UnitOfWorkRepositories) error) error
+
+func (f unitOfWorkFunc) Do(fn func(repos contracts.UnitOfWorkRepositories) error) error {
+	return f(fn)
+}
+
+// failingCreates is a repository whose Create always fails
+type failingCreates[T any] struct {
+	contracts.Repository[T, int]
+}
+
+func (failingCreates[T]) Create(*T) (*T, error) {
+	return nil, errors.New("storage is down")
+}
+
+// newWalletStore gives a memory store with one user, and a unit of work on it whose ledger can't be written
+func newWalletStore(t *testing.T) (*memoryStore, *db.User, contracts.UnitOfWork) {
+	store := newMemoryStore()
+	user := store.addUser(t, "ana")
+
+	failingLedger := unitOfWorkFunc(func(fn func(repos contracts.UnitOfWorkRepositories) error) error {
+		return store.unitOfWork.Do(func(repos contracts.UnitOfWorkRepositories) error {
+			repos.Transactions = failingCreates[db.Transaction]{repos.Transactions}
+			return fn(repos)
+		})
+	})
+	return store, user, failingLedger
+}
+
+func TestWalletUseCase_WritesAreAtomic(t *testing.T) {
+	t.Run("create stores the wallet with its opening entry", func(t *testing.T) {
+		store, user, _ := newWalletStore(t)
+		uc := usecases.NewWalletUseCase(store.wallets, store.unitOfWork, nil)
+
+		wallet, err := uc.CreateWallet(request.CreateWalletRequest{Name: "Savings", WalletType: types.Debit, Balance: money("100"), UserID: user.ID})
+		require.Nil(t, err)
+		assert.Positive(t, wallet.ID)
+
+		entries := store.ledger(t)
+		require.Len(t, entries, 1)
+		assert.Equal(t, wallet.ID, entries[0].WalletID)
+		assert.Equal(t, "Opening balance", entries[0].Description)
+	})
+
+	t.Run("create keeps no wallet when the opening entry fails", func(t *testing.T) {
+		store, user, failingLedger := newWalletStore(t)
+		uc := usecases.NewWalletUseCase(store.wallets, failingLedger, nil)
+
+		_, err := uc.CreateWallet(request.CreateWalletRequest{Name: "Savings", WalletType: types.Debit, Balance: money("100"), UserID: user.ID})
+		require.NotNil(t, err)
+		assert.Contains(t, err.Error, "error recording opening balance")
+
+		stored, errAll := store.wallets.GetAll()
+		require.NoError(t, errAll)
+		assert.Empty(t, stored)
+	})
+
+	t.Run("update keeps the old balance when the adjustment fails", func(t *testing.T) {
+		store, user, failingLedger := newWalletStore(t)
+		wallet := store.addWallet(t, user.ID, "Savings", "100")
+		uc := usecases.NewWalletUseCase(store.wallets, failingLedger, nil)
+
+		balance := money("250")
+		_, err := uc.UpdateWallet(user.ID, request.UpdateWalletRequest{WalletID: wallet.ID, Name: "Rainy day", Balance: &balance})
+		require.NotNil(t, err)
+		assert.Contains(t, err.Error, "error recording balance adjustment")
+
+		stored := store.wallet(t, wallet.ID)
+		assert.Equal(t, "Savings", stored.Name)
+		assert.Equal(t, "100.00", stored.Balance.String())
+	})
+}
@@ -0,0 +1,238 @@
+//go:build !coverage
+// +build !coverage
+
+package mocks
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// MockOIDCUser is the user the mock provider logs in on its authorization endpoint
+type MockOIDCUser struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	GivenName     string
+	FamilyName    string
+}
+
+// MockOIDCServer is a local OpenID Connect provider for tests. It publishes discovery
+// metadata and a JWKS, logs in User without asking anything on /authorize, and redeems
+// codes on /token only with the PKCE verifier of the challenge they were issued for.
+type MockOIDCServer struct {
+	*httptest.Server
+
+	ClientID     string
+	ClientSecret string
+	User         MockOIDCUser
+
+	// Claims, when set, changes the claims of the next ID tokens (e.g. to expire them or break the nonce)
+	Claims func(claims map[string]any)
+
+	mu    sync.Mutex
+	keyID string
+	key   *rsa.PrivateKey
+	codes map[string]mockAuthorization
+}
+
+// mockAuthorization is what the mock remembers about an issued code
+type mockAuthorization struct {
+	challenge   string
+	nonce       string
+	redirectURI string
+}
+
+// NewMockOIDCServer starts a mock provider; close it with Close
+func NewMockOIDCServer(clientID, clientSecret string) *MockOIDCServer {
+	server := &MockOIDCServer{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		User:         MockOIDCUser{Subject: "user-1", Email: "ana@example.com", EmailVerified: true},
+		codes:        map[string]mockAuthorization{},
+	}
+	server.RotateKey()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", server.discovery)
+	mux.HandleFunc("/jwks", server.jwks)
+	mux.HandleFunc("/authorize", server.authorize)
+	mux.HandleFunc("/token", server.token)
+	server.Server = httptest.NewServer(mux)
+	return server
+}
+
+// Issuer is the issuer URL of the mock
+func (s *MockOIDCServer) Issuer() string {
+	return s.URL
+}
+
+// RotateKey replaces the signing key with a new one under a new key ID
+func (s *MockOIDCServer) RotateKey() {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.key = key
+	s.keyID = randomID()
+}
+
+// Authorize plays the browser: it follows a login URL to the mock and returns the
+// state and code the provider sends back to the redirect URI
+func (s *MockOIDCServer) Authorize(loginURL string) (state string, code string, err error) {
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	res, err := client.Get(loginURL)
+	if err != nil {
+		return "", "", err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusFound {
+		return "", "", fmt.Errorf("authorize answered %d", res.StatusCode)
+	}
+	location, err := url.Parse(res.Header.Get("Location"))
+	if err != nil {
+		return "", "", err
+	}
+	return location.Query().Get("state"), location.Query().Get("code"), nil
+}
+
+func (s *MockOIDCServer) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"issuer":                 s.URL,
+		"authorization_endpoint": s.URL + "/authorize",
+		"token_endpoint":         s.URL + "/token",
+		"jwks_uri":               s.URL + "/jwks",
+	})
+}
+
+func (s *MockOIDCServer) jwks(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, http.StatusOK, map[string]any{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"use": "sig",
+			"alg": "RS256",
+			"kid": s.keyID,
+			"n":   base64.RawURLEncoding.EncodeToString(s.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(s.key.E)).Bytes()),
+		}},
+	})
+}
+
+func (s *MockOIDCServer) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("client_id") != s.ClientID || query.Get("response_type") != "code" || query.Get("code_challenge_method") != "S256" {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+
+	code := randomID()
+	s.mu.Lock()
+	s.codes[code] = mockAuthorization{
+		challenge:   query.Get("code_challenge"),
+		nonce:       query.Get("nonce"),
+		redirectURI: query.Get("redirect_uri"),
+	}
+	s.mu.Unlock()
+
+	redirect := query.Get("redirect_uri") + "?" + url.Values{"code": {code}, "state": {query.Get("state")}}.Encode()
+	http.Redirect(w, r, redirect, http.StatusFound)
+}
+
+func (s *MockOIDCServer) token(w http.ResponseWriter, r *http.Request) {
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok || clientID != s.ClientID || clientSecret != s.ClientSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	s.mu.Lock()
+	authorization, found := s.codes[r.FormValue("code")]
+	delete(s.codes, r.FormValue("code"))
+	s.mu.Unlock()
+
+	sum := sha256.Sum256([]byte(r.FormValue("code_verifier")))
+	if !found || r.FormValue("grant_type") != "authorization_code" ||
+		r.FormValue("redirect_uri") != authorization.redirectURI ||
+		base64.RawURLEncoding.EncodeToString(sum[:]) != authorization.challenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	now := time.Now()
+	claims := map[string]any{
+		"iss":            s.URL,
+		"sub":            s.User.Subject,
+		"aud":            s.ClientID,
+		"exp":            now.Add(5 * time.Minute).Unix(),
+		"iat":            now.Unix(),
+		"nonce":          authorization.nonce,
+		"email":          s.User.Email,
+		"email_verified": s.User.EmailVerified,
+		"given_name":     s.User.GivenName,
+		"family_name":    s.User.FamilyName,
+	}
+	if s.Claims != nil {
+		s.Claims(claims)
+	}
+
+	idToken, err := s.sign(claims)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": randomID(),
+		"token_type":   "Bearer",
+		"id_token":     idToken,
+	})
+}
+
+// sign builds an RS256 JWT with the current key
+func (s *MockOIDCServer) sign(claims map[string]any) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": s.keyID})
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func randomID() string {
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
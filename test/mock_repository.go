@@ -131,6 +131,26 @@ func (m *MockRepository[T, ID]) GetByID(id ID) (*T, error) {
 	return entity, nil
 }
 
+// GetForUpdate retrieves an entity like GetByID, which also answers it unless a
+// "GetForUpdate" response is set; the mock has nothing to lock
+func (m *MockRepository[T, ID]) GetForUpdate(id ID) (*T, error) {
+	m.mu.RLock()
+	resp, exists := m.responses["GetForUpdate"]
+	m.mu.RUnlock()
+	if !exists {
+		return m.GetByID(id)
+	}
+
+	m.recordCall("GetForUpdate", id)
+	if resp.err != nil {
+		return nil, resp.err
+	}
+	if val, ok := resp.value.(*T); ok {
+		return val, nil
+	}
+	return nil, errors.New("invalid response type for GetForUpdate")
+}
+
 // GetAll retrieves all entities
 func (m *MockRepository[T, ID]) GetAll() ([]T, error) {
 	m.recordCall("GetAll")
@@ -265,9 +285,9 @@ func (m *MockRepository[T, ID]) SetFindByFieldNotExists(notExists bool) {
 	m.ForceFindByFieldNotExists = notExists
 }
 
-// Query executes a custom query and returns the result as interface{}.
-// This method provides a flexible way to execute custom queries that don't fit the standard CRUD operations.
-func (m *MockRepository[T, ID]) Query(query string, args contracts.QueryOptions) (interface{}, error) {
+// Query returns the predefined response, which may be a []T or a contracts.Page[T].
+// Without one it returns an empty page.
+func (m *MockRepository[T, ID]) Query(query string, args contracts.QueryOptions) (contracts.Page[T], error) {
 	m.recordCall("Query", append([]interface{}{query}, args)...)
 
 	// Check for predefined response
@@ -277,11 +297,19 @@ func (m *MockRepository[T, ID]) Query(query string, args contracts.QueryOptions)
 
 	if exists {
 		if resp.err != nil {
-			return nil, resp.err
+			return contracts.Page[T]{}, resp.err
+		}
+		switch val := resp.value.(type) {
+		case contracts.Page[T]:
+			return val, nil
+		case []T:
+			return contracts.Page[T]{Items: val}, nil
+		case nil:
+			return contracts.Page[T]{}, nil
 		}
-		return resp.value, nil
+		return contracts.Page[T]{}, errors.New("invalid response type for Query")
 	}
 
-	// Default behavior: return nil
-	return nil, nil
+	// Default behavior: return an empty page
+	return contracts.Page[T]{}, nil
 }
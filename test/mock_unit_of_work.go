@@ -0,0 +1,28 @@
+//go:build !coverage
+// +build !coverage
+
+package mocks
+
+import (
+	contracts "Financial/Core/ports"
+)
+
+// MockUnitOfWork implements the ports.UnitOfWork interface for testing purposes.
+// It runs the function on the given repositories and keeps whatever they stored,
+// so tests check the writes through the same mocks they already inspect.
+type MockUnitOfWork struct {
+	repos contracts.UnitOfWorkRepositories
+	// Runs counts how many units of work were started
+	Runs int
+}
+
+// NewMockUnitOfWork creates a new instance of MockUnitOfWork over repos
+func NewMockUnitOfWork(repos contracts.UnitOfWorkRepositories) *MockUnitOfWork {
+	return &MockUnitOfWork{repos: repos}
+}
+
+// Do runs fn with the repositories of the mock
+func (m *MockUnitOfWork) Do(fn func(repos contracts.UnitOfWorkRepositories) error) error {
+	m.Runs++
+	return fn(m.repos)
+}